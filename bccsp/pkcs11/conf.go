@@ -28,6 +28,9 @@ type PKCS11Opts struct {
 	Immutable      bool           `json:"immutable,omitempty"`
 	AltID          string         `json:"altid,omitempty"`
 	KeyIDs         []KeyIDMapping `json:"keyids,omitempty" mapstructure:"keyids"`
+	// SymmetricKeys generates the AES-256 keys in the token rather than in the
+	// software key store, so that they can be used but never extracted.
+	SymmetricKeys bool `json:"symmetrickeys,omitempty"`
 
 	sessionCacheSize        int
 	createSessionRetries    int
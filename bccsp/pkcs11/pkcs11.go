@@ -7,8 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package pkcs11
 
 import (
+	"crypto/aes"
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/asn1"
 	"encoding/hex"
@@ -42,6 +44,8 @@ type Provider struct {
 	curve      asn1.ObjectIdentifier
 	softVerify bool
 	immutable  bool
+	// symmetricKeys generates the AES keys in the token
+	symmetricKeys bool
 
 	getKeyIDForSKI          func(ski []byte) []byte
 	createSessionRetries    int
@@ -117,6 +121,7 @@ func New(opts PKCS11Opts, keyStore bccsp.KeyStore, options ...Option) (*Provider
 		keyCache:                map[string]bccsp.Key{},
 		softVerify:              opts.SoftwareVerify,
 		immutable:               opts.Immutable,
+		symmetricKeys:           opts.SymmetricKeys,
 	}
 
 	for _, o := range options {
@@ -199,6 +204,17 @@ func (csp *Provider) KeyGen(opts bccsp.KeyGenOpts) (k bccsp.Key, err error) {
 
 		k = &ecdsaPrivateKey{ski, ecdsaPublicKey{ski, pub}}
 
+	case *bccsp.AES256KeyGenOpts:
+		if !csp.symmetricKeys {
+			return csp.BCCSP.KeyGen(opts)
+		}
+		ski, err := csp.generateAESKey(opts.Ephemeral())
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed generating AES 256 key")
+		}
+
+		k = &aesKey{ski}
+
 	default:
 		return csp.BCCSP.KeyGen(opts)
 	}
@@ -227,6 +243,13 @@ func (csp *Provider) GetKey(ski []byte) (bccsp.Key, error) {
 	}
 
 	pubKey, isPriv, err := csp.getECKey(ski)
+	if err != nil && csp.symmetricKeys {
+		if _, aesErr := csp.getAESKey(ski); aesErr == nil {
+			key := &aesKey{ski}
+			csp.cacheKey(ski, key)
+			return key, nil
+		}
+	}
 	if err != nil {
 		logger.Debugf("Key not found using PKCS11: %v", err)
 		return csp.BCCSP.GetKey(ski)
@@ -323,6 +346,61 @@ func (csp *Provider) verifyECDSA(k ecdsaPublicKey, signature, digest []byte) (bo
 	return csp.verifyP11ECDSA(k.ski, digest, r, s, k.pub.Curve.Params().BitSize/8)
 }
 
+// Encrypt encrypts plaintext using key k.
+// The opts argument should be appropriate for the algorithm used.
+func (csp *Provider) Encrypt(k bccsp.Key, plaintext []byte, opts bccsp.EncrypterOpts) ([]byte, error) {
+	key, ok := k.(*aesKey)
+	if !ok {
+		return csp.BCCSP.Encrypt(k, plaintext, opts)
+	}
+
+	var iv []byte
+	switch o := opts.(type) {
+	case *bccsp.AESCBCPKCS7ModeOpts:
+		iv = o.IV
+	case bccsp.AESCBCPKCS7ModeOpts:
+		iv = o.IV
+	default:
+		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
+	}
+	if iv == nil {
+		iv = make([]byte, aes.BlockSize)
+		if _, err := rand.Read(iv); err != nil {
+			return nil, errors.Wrap(err, "Failed generating IV")
+		}
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, errors.Errorf("Invalid IV. It must have length the block size [%d]", len(iv))
+	}
+
+	ciphertext, err := csp.encryptP11AES(key.ski, iv, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	// the IV is prepended as the software implementation does
+	return append(append([]byte{}, iv...), ciphertext...), nil
+}
+
+// Decrypt decrypts ciphertext using key k.
+// The opts argument should be appropriate for the algorithm used.
+func (csp *Provider) Decrypt(k bccsp.Key, ciphertext []byte, opts bccsp.DecrypterOpts) ([]byte, error) {
+	key, ok := k.(*aesKey)
+	if !ok {
+		return csp.BCCSP.Decrypt(k, ciphertext, opts)
+	}
+
+	switch opts.(type) {
+	case *bccsp.AESCBCPKCS7ModeOpts, bccsp.AESCBCPKCS7ModeOpts:
+	default:
+		return nil, fmt.Errorf("Mode not recognized [%s]", opts)
+	}
+	if len(ciphertext) < 2*aes.BlockSize || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errors.New("Invalid ciphertext. It must be a multiple of the block size")
+	}
+
+	return csp.decryptP11AES(key.ski, ciphertext[:aes.BlockSize], ciphertext[aes.BlockSize:])
+}
+
 func (csp *Provider) getSession() (session pkcs11.SessionHandle, err error) {
 	for {
 		select {
@@ -601,6 +679,115 @@ func (csp *Provider) generateECKey(curve asn1.ObjectIdentifier, ephemeral bool)
 	return ski, pubGoKey, nil
 }
 
+func (csp *Provider) generateAESKey(ephemeral bool) (ski []byte, err error) {
+	session, err := csp.getSession()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { csp.handleSessionReturn(err, session) }()
+
+	// unlike the EC keys, the secret keys have no public part from which
+	// to derive the SKI, which is therefore random
+	ski = make([]byte, sha256.Size)
+	if _, err = rand.Read(ski); err != nil {
+		return nil, fmt.Errorf("Could not generate SKI [%s]", err)
+	}
+
+	keyT := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_CLASS, pkcs11.CKO_SECRET_KEY),
+		pkcs11.NewAttribute(pkcs11.CKA_KEY_TYPE, pkcs11.CKK_AES),
+		pkcs11.NewAttribute(pkcs11.CKA_VALUE_LEN, 32),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, !ephemeral),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_ENCRYPT, true),
+		pkcs11.NewAttribute(pkcs11.CKA_DECRYPT, true),
+
+		pkcs11.NewAttribute(pkcs11.CKA_ID, csp.getKeyIDForSKI(ski)),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, hex.EncodeToString(ski)),
+
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+	}
+	if csp.immutable {
+		keyT = append(keyT, pkcs11.NewAttribute(pkcs11.CKA_MODIFIABLE, false))
+	}
+
+	key, err := csp.ctx.GenerateKey(session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_KEY_GEN, nil)},
+		keyT,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("P11: key generate failed [%s]", err)
+	}
+
+	logger.Infof("Generated new P11 AES key, SKI %x\n", ski)
+	if logger.IsEnabledFor(zapcore.DebugLevel) {
+		listAttrs(csp.ctx, session, key)
+	}
+
+	return ski, nil
+}
+
+func (csp *Provider) getAESKey(ski []byte) (key pkcs11.ObjectHandle, err error) {
+	session, err := csp.getSession()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { csp.handleSessionReturn(err, session) }()
+
+	return csp.findKeyPairFromSKI(session, ski, secretKeyType)
+}
+
+func (csp *Provider) encryptP11AES(ski, iv, plaintext []byte) (ciphertext []byte, err error) {
+	session, err := csp.getSession()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { csp.handleSessionReturn(err, session) }()
+
+	secretKey, err := csp.findKeyPairFromSKI(session, ski, secretKeyType)
+	if err != nil {
+		return nil, fmt.Errorf("Secret key not found [%s]", err)
+	}
+
+	err = csp.ctx.EncryptInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_CBC_PAD, iv)}, secretKey)
+	if err != nil {
+		return nil, fmt.Errorf("Encrypt-initialize failed [%s]", err)
+	}
+
+	ciphertext, err = csp.ctx.Encrypt(session, plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("P11: encrypt failed [%s]", err)
+	}
+
+	return ciphertext, nil
+}
+
+func (csp *Provider) decryptP11AES(ski, iv, ciphertext []byte) (plaintext []byte, err error) {
+	session, err := csp.getSession()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { csp.handleSessionReturn(err, session) }()
+
+	secretKey, err := csp.findKeyPairFromSKI(session, ski, secretKeyType)
+	if err != nil {
+		return nil, fmt.Errorf("Secret key not found [%s]", err)
+	}
+
+	err = csp.ctx.DecryptInit(session, []*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_AES_CBC_PAD, iv)}, secretKey)
+	if err != nil {
+		return nil, fmt.Errorf("Decrypt-initialize failed [%s]", err)
+	}
+
+	plaintext, err = csp.ctx.Decrypt(session, ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("P11: decrypt failed [%s]", err)
+	}
+
+	return plaintext, nil
+}
+
 func (csp *Provider) signP11ECDSA(ski []byte, msg []byte) (R, S *big.Int, err error) {
 	session, err := csp.getSession()
 	if err != nil {
@@ -679,6 +866,7 @@ type keyType int8
 const (
 	publicKeyType keyType = iota
 	privateKeyType
+	secretKeyType
 )
 
 func (csp *Provider) cachedHandle(keyType keyType, ski []byte) (pkcs11.ObjectHandle, bool) {
@@ -712,8 +900,11 @@ func (csp *Provider) findKeyPairFromSKI(session pkcs11.SessionHandle, ski []byte
 	}
 
 	ktype := pkcs11.CKO_PUBLIC_KEY
-	if keyType == privateKeyType {
+	switch keyType {
+	case privateKeyType:
 		ktype = pkcs11.CKO_PRIVATE_KEY
+	case secretKeyType:
+		ktype = pkcs11.CKO_SECRET_KEY
 	}
 
 	template := []*pkcs11.Attribute{
@@ -10,6 +10,7 @@ SPDX-License-Identifier: Apache-2.0
 package pkcs11
 
 import (
+	"crypto/aes"
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/sha256"
@@ -863,6 +864,41 @@ func TestDelegation(t *testing.T) {
 	})
 }
 
+func TestAESSymmetricKeys(t *testing.T) {
+	opts := defaultOptions()
+	opts.SymmetricKeys = true
+	csp, cleanup := newProvider(t, opts)
+	defer cleanup()
+
+	k, err := csp.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: false})
+	require.NoError(t, err)
+	require.IsType(t, &aesKey{}, k)
+	require.True(t, k.Symmetric())
+	require.True(t, k.Private())
+	_, err = k.Bytes()
+	require.EqualError(t, err, "Not supported.")
+
+	csp.clearCaches()
+	k2, err := csp.GetKey(k.SKI())
+	require.NoError(t, err)
+	require.IsType(t, &aesKey{}, k2)
+	require.Equal(t, k.SKI(), k2.SKI())
+
+	msg := []byte("a message longer than a single block")
+	ct, err := csp.Encrypt(k, msg, &bccsp.AESCBCPKCS7ModeOpts{})
+	require.NoError(t, err)
+	require.Len(t, ct, 3*aes.BlockSize)
+
+	pt, err := csp.Decrypt(k2, ct, &bccsp.AESCBCPKCS7ModeOpts{})
+	require.NoError(t, err)
+	require.Equal(t, msg, pt)
+
+	_, err = csp.Encrypt(k, msg, &bccsp.AESCBCPKCS7ModeOpts{IV: []byte{1}})
+	require.EqualError(t, err, "Invalid IV. It must have length the block size [1]")
+	_, err = csp.Decrypt(k, ct[1:], &bccsp.AESCBCPKCS7ModeOpts{})
+	require.EqualError(t, err, "Invalid ciphertext. It must be a multiple of the block size")
+}
+
 func TestHandleSessionReturn(t *testing.T) {
 	opts := defaultOptions()
 	opts.sessionCacheSize = 5
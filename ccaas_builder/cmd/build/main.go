@@ -38,6 +38,24 @@ type connection struct {
 	RootCert    string `json:"root_cert"`
 	ClientKey   string `json:"client_key"`
 	ClientCert  string `json:"client_cert"`
+
+	// Endpoints are additional replicas of the chaincode service, whose
+	// unspecified settings are inherited from the fields above.
+	Endpoints     []endpoint `json:"endpoints,omitempty"`
+	LoadBalancing string     `json:"load_balancing,omitempty"`
+}
+
+// endpoint structure is used to represent a single
+// replica of the chaincode service in the connection.json file.
+type endpoint struct {
+	Address     string `json:"address"`
+	Domain      string `json:"domain,omitempty"`
+	DialTimeout string `json:"dial_timeout,omitempty"`
+	TLS         *bool  `json:"tls_required,omitempty"`
+	ClientAuth  *bool  `json:"client_auth_required,omitempty"`
+	RootCert    string `json:"root_cert,omitempty"`
+	ClientKey   string `json:"client_key,omitempty"`
+	ClientCert  string `json:"client_cert,omitempty"`
 }
 
 type Config struct {
@@ -142,9 +160,10 @@ func run() error {
 
 	// if connection is TLS Enabled, updated with the correct information
 	// no other information is needed for the no-TLS case, so the default can be assumed
-	// to be good
-	if connectionData.TLS {
-		updatedConnection.TLS = true
+	// to be good. The TLS information is also kept when endpoints, which inherit it,
+	// enable TLS
+	if connectionData.TLS || endpointsRequireTLS(connectionData.Endpoints) {
+		updatedConnection.TLS = connectionData.TLS
 		updatedConnection.ClientAuth = connectionData.ClientAuth
 
 		updatedConnection.RootCert, err = execTempl(cfg, connectionData.RootCert)
@@ -161,6 +180,21 @@ func run() error {
 		}
 	}
 
+	switch connectionData.LoadBalancing {
+	case "", "round_robin", "least_loaded":
+		updatedConnection.LoadBalancing = connectionData.LoadBalancing
+	default:
+		return fmt.Errorf("unknown load_balancing policy %s", connectionData.LoadBalancing)
+	}
+
+	for i, e := range connectionData.Endpoints {
+		updatedEndpoint, err := templateEndpoint(cfg, e)
+		if err != nil {
+			return fmt.Errorf("Failed to parse endpoint %d: %s", i, err)
+		}
+		updatedConnection.Endpoints = append(updatedConnection.Endpoints, updatedEndpoint)
+	}
+
 	updatedConnectionBytes, err := json.Marshal(updatedConnection)
 	if err != nil {
 		return fmt.Errorf("failed to marshal updated connection.json file: %s", err)
@@ -175,6 +209,45 @@ func run() error {
 
 }
 
+func endpointsRequireTLS(endpoints []endpoint) bool {
+	for _, e := range endpoints {
+		if e.TLS != nil && *e.TLS {
+			return true
+		}
+	}
+	return false
+}
+
+// templateEndpoint processes each of the string fields of an endpoint as a template
+func templateEndpoint(cfg map[string]interface{}, e endpoint) (endpoint, error) {
+	updated := endpoint{TLS: e.TLS, ClientAuth: e.ClientAuth}
+
+	fields := []struct {
+		name     string
+		src, dst *string
+	}{
+		{"Address", &e.Address, &updated.Address},
+		{"Domain", &e.Domain, &updated.Domain},
+		{"DialTimeout", &e.DialTimeout, &updated.DialTimeout},
+		{"RootCert", &e.RootCert, &updated.RootCert},
+		{"ClientKey", &e.ClientKey, &updated.ClientKey},
+		{"ClientCert", &e.ClientCert, &updated.ClientCert},
+	}
+	for _, f := range fields {
+		v, err := execTempl(cfg, *f.src)
+		if err != nil {
+			return endpoint{}, fmt.Errorf("Failed to parse the %s field template: %s", f.name, err)
+		}
+		*f.dst = v
+	}
+
+	if updated.Address == "" {
+		return endpoint{}, fmt.Errorf("address not provided")
+	}
+
+	return updated, nil
+}
+
 // execTempl is a helper function to process a template against a string, and return a string
 func execTempl(cfg map[string]interface{}, inputStr string) (string, error) {
 
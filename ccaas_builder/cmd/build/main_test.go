@@ -185,6 +185,74 @@ func TestTemplating(t *testing.T) {
 	gt.Expect(string(connectionFileContents)).To(MatchJSON(expectedJson))
 }
 
+func TestTemplatingEndpoints(t *testing.T) {
+	gt := NewWithT(t)
+
+	releaseCmd, err := gexec.Build("github.com/hyperledger/fabric/ccaas_builder/cmd/build")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer gexec.CleanupBuildArtifacts()
+
+	testPath := t.TempDir()
+
+	// create a basic structure of the chaincode to use
+	os.MkdirAll(path.Join(testPath, "in-builder-dir", "META-INF"), 0755)
+	os.MkdirAll(path.Join(testPath, "in-metadata-dir"), 0755)
+	os.MkdirAll(path.Join(testPath, "out-release-dir"), 0755)
+
+	connectionJson := path.Join(testPath, "in-builder-dir", "connection.json")
+	file, err := os.Create(connectionJson)
+	gt.Expect(err).NotTo(HaveOccurred())
+	file.WriteString(`{
+		"address": "{{.address}}-0:9999",
+		"dial_timeout": "10s",
+		"tls_required": false,
+		"root_cert":"{{.root_cert}}",
+		"load_balancing": "least_loaded",
+		"endpoints": [
+			{"address": "{{.address}}-1:9999"},
+			{"address": "{{.address}}-2:9999", "domain": "{{.address}}-2", "tls_required": true, "dial_timeout": "5s"}
+		]
+	  }`)
+
+	metadataJson := path.Join(testPath, "in-metadata-dir", "metadata.json")
+	fileMetadata, err := os.Create(metadataJson)
+	gt.Expect(err).NotTo(HaveOccurred())
+	fileMetadata.WriteString(`{
+		  "type":"ccaas"
+		}`)
+
+	// call 'build' with the environment set for the templating to be tested
+	args := []string{path.Join(testPath, "in-builder-dir"), path.Join(testPath, "in-metadata-dir"), path.Join(testPath, "out-release-dir")}
+	cmd := exec.Command(releaseCmd, args...)
+	env := os.Environ()
+	env = append(env, `CHAINCODE_AS_A_SERVICE_BUILDER_CONFIG={"address":"ccaas","root_cert":"a root cert"}`)
+	cmd.Env = env
+
+	session, err := gexec.Start(cmd, nil, nil)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Eventually(session, 5*time.Second).Should(gexec.Exit(0))
+
+	connectionFileContents, err := ioutil.ReadFile(path.Join(testPath, "out-release-dir", "connection.json"))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	expectedJson := `{
+		"address": "ccaas-0:9999",
+		"dial_timeout": "10s",
+		"tls_required": false,
+		"root_cert":"a root cert",
+		"client_key":"",
+		"client_cert":"",
+		"client_auth_required":false,
+		"load_balancing": "least_loaded",
+		"endpoints": [
+			{"address": "ccaas-1:9999"},
+			{"address": "ccaas-2:9999", "domain": "ccaas-2", "tls_required": true, "dial_timeout": "5s"}
+		]
+	  }`
+
+	gt.Expect(string(connectionFileContents)).To(MatchJSON(expectedJson))
+}
+
 func TestTemplatingFailure(t *testing.T) {
 	gt := NewWithT(t)
 
@@ -0,0 +1,202 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/pkg/errors"
+)
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// printConfigDiff prints the differences between the channel groups of the
+// original and updated configs, one per line, as the jq path of the field
+// in the JSON representation of the config followed by its values. Added
+// fields are prefixed with '+', removed fields with '-' and modified fields
+// with '~'. The versions of the config elements are not compared, as they are
+// assigned when the update is computed.
+func printConfigDiff(w io.Writer, original, updated *cb.Config) error {
+	originalTree, err := configTree(original)
+	if err != nil {
+		return err
+	}
+	updatedTree, err := configTree(updated)
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+	diffTree(".channel_group", originalTree, updatedTree, &lines)
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reuseEquivalentValues replaces the values of updated which are encoded
+// differently from the values of original but hold the same content, as
+// happens to values holding protobuf maps, whose encoding is not
+// deterministic, so that the update does not rewrite them.
+func reuseEquivalentValues(original, updated *cb.Config) error {
+	originalTree, err := configTree(original)
+	if err != nil {
+		return err
+	}
+	updatedTree, err := configTree(updated)
+	if err != nil {
+		return err
+	}
+
+	reuseGroupValues(original.ChannelGroup, updated.ChannelGroup, originalTree, updatedTree)
+	return nil
+}
+
+func reuseGroupValues(original, updated *cb.ConfigGroup, originalTree, updatedTree interface{}) {
+	for key, updatedValue := range updated.Values {
+		originalValue, ok := original.Values[key]
+		if !ok || originalValue.ModPolicy != updatedValue.ModPolicy || bytes.Equal(originalValue.Value, updatedValue.Value) {
+			continue
+		}
+		if compactJSON(subTree(originalTree, "values", key, "value")) == compactJSON(subTree(updatedTree, "values", key, "value")) {
+			updated.Values[key] = &cb.ConfigValue{
+				ModPolicy: updatedValue.ModPolicy,
+				Value:     originalValue.Value,
+			}
+		}
+	}
+
+	for key, updatedGroup := range updated.Groups {
+		if originalGroup, ok := original.Groups[key]; ok {
+			reuseGroupValues(originalGroup, updatedGroup, subTree(originalTree, "groups", key), subTree(updatedTree, "groups", key))
+		}
+	}
+}
+
+func subTree(tree interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		m, ok := tree.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		tree = m[key]
+	}
+	return tree
+}
+
+// configTree returns the JSON representation of the channel group of config
+// as generic maps and slices.
+func configTree(config *cb.Config) (interface{}, error) {
+	// empty values are decoded as their zero message rather than as null,
+	// whether they were unmarshaled from a block or just marshaled
+	channelGroup := proto.Clone(config.ChannelGroup).(*cb.ConfigGroup)
+	normalizeEmptyValues(channelGroup)
+
+	buf := &bytes.Buffer{}
+	if err := protolator.DeepMarshalJSON(buf, &cb.Config{ChannelGroup: channelGroup}); err != nil {
+		return nil, errors.Wrap(err, "malformed config")
+	}
+
+	decoder := json.NewDecoder(buf)
+	decoder.UseNumber()
+	tree := map[string]interface{}{}
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+	return tree["channel_group"], nil
+}
+
+func normalizeEmptyValues(group *cb.ConfigGroup) {
+	for _, value := range group.Values {
+		if len(value.Value) == 0 {
+			value.Value = []byte{}
+		}
+	}
+	for _, subGroup := range group.Groups {
+		normalizeEmptyValues(subGroup)
+	}
+}
+
+func diffTree(path string, original, updated interface{}, lines *[]string) {
+	originalMap, originalIsMap := original.(map[string]interface{})
+	updatedMap, updatedIsMap := updated.(map[string]interface{})
+	if originalIsMap && updatedIsMap {
+		_, isConfigElement := originalMap["mod_policy"]
+		for _, key := range sortedKeys(originalMap, updatedMap) {
+			if isConfigElement && key == "version" {
+				continue
+			}
+			keyPath := path + "." + jqKey(key)
+			originalValue, inOriginal := originalMap[key]
+			updatedValue, inUpdated := updatedMap[key]
+			switch {
+			case !inUpdated:
+				*lines = append(*lines, fmt.Sprintf("- %s: %s", keyPath, compactJSON(originalValue)))
+			case !inOriginal:
+				*lines = append(*lines, fmt.Sprintf("+ %s: %s", keyPath, compactJSON(updatedValue)))
+			default:
+				diffTree(keyPath, originalValue, updatedValue, lines)
+			}
+		}
+		return
+	}
+
+	originalSlice, originalIsSlice := original.([]interface{})
+	updatedSlice, updatedIsSlice := updated.([]interface{})
+	if originalIsSlice && updatedIsSlice && len(originalSlice) == len(updatedSlice) {
+		for i := range originalSlice {
+			diffTree(fmt.Sprintf("%s[%d]", path, i), originalSlice[i], updatedSlice[i], lines)
+		}
+		return
+	}
+
+	originalJSON, updatedJSON := compactJSON(original), compactJSON(updated)
+	if originalJSON != updatedJSON {
+		*lines = append(*lines, fmt.Sprintf("~ %s: %s -> %s", path, originalJSON, updatedJSON))
+	}
+}
+
+func sortedKeys(maps ...map[string]interface{}) []string {
+	keySet := map[string]struct{}{}
+	for _, m := range maps {
+		for key := range m {
+			keySet[key] = struct{}{}
+		}
+	}
+	keys := make([]string, 0, len(keySet))
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func jqKey(key string) string {
+	if identifierRegexp.MatchString(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
+
+func compactJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(b)
+}
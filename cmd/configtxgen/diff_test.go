@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/core/config/configtest"
+	"github.com/hyperledger/fabric/internal/configtxgen/encoder"
+	"github.com/hyperledger/fabric/internal/configtxgen/genesisconfig"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintConfigDiff(t *testing.T) {
+	config := genesisconfig.Load(genesisconfig.SampleAppChannelInsecureSoloProfile, configtest.GetDevConfigDir())
+	config.Application.Organizations = genesisconfig.LoadTopLevel(configtest.GetDevConfigDir()).Organizations
+	channelGroup, err := encoder.NewChannelGroup(config)
+	require.NoError(t, err)
+	original := &cb.Config{ChannelGroup: channelGroup}
+	original.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Version = 3
+
+	config.Orderer.BatchSize.MaxMessageCount = 42
+	config.Capabilities = nil
+	channelGroup, err = encoder.NewChannelGroup(config)
+	require.NoError(t, err)
+	updated := &cb.Config{ChannelGroup: channelGroup}
+	updated.ChannelGroup.Groups[channelconfig.ApplicationGroupKey].Groups["Org.2"] = proto.Clone(updated.ChannelGroup.Groups[channelconfig.ApplicationGroupKey].Groups[genesisconfig.SampleOrgName]).(*cb.ConfigGroup)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, printConfigDiff(buf, original, updated))
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 3)
+	require.Regexp(t, `^\+ \.channel_group\.groups\.Application\.groups\."Org\.2": \{"groups":\{\},"mod_policy":"Admins",`, string(lines[0]))
+	require.Equal(t, `~ .channel_group.groups.Orderer.values.BatchSize.value.max_message_count: 500 -> 42`, string(lines[1]))
+	require.Regexp(t, `^- \.channel_group\.values\.Capabilities: \{"mod_policy":"Admins","value":\{"capabilities":\{"V3_0":\{\}\}\},"version":"0"\}$`, string(lines[2]))
+
+	require.NoError(t, printConfigDiff(buf, original, original))
+}
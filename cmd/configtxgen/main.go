@@ -20,6 +20,7 @@ import (
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/internal/configtxgen/encoder"
 	"github.com/hyperledger/fabric/internal/configtxgen/genesisconfig"
@@ -131,6 +132,134 @@ func doOutputAnchorPeersUpdate(conf *genesisconfig.Profile, channelID string, ou
 	return nil
 }
 
+func doOutputConfigUpdate(conf *genesisconfig.Profile, channelID string, configBlock string, outputConfigUpdate string) error {
+	logger.Info("Generating config update")
+	data, err := ioutil.ReadFile(configBlock)
+	if err != nil {
+		return fmt.Errorf("could not read config block %s", configBlock)
+	}
+
+	block, err := protoutil.UnmarshalBlock(data)
+	if err != nil {
+		return fmt.Errorf("error unmarshalling to block: %s", err)
+	}
+	original, blockChannelID, err := configFromBlock(block)
+	if err != nil {
+		return errors.WithMessage(err, "could not extract config from block")
+	}
+	if channelID == "" {
+		channelID = blockChannelID
+	}
+	if channelID != blockChannelID {
+		return errors.Errorf("config block is for channel '%s', not '%s'", blockChannelID, channelID)
+	}
+
+	channelGroup, err := desiredChannelGroup(conf, original.ChannelGroup)
+	if err != nil {
+		return errors.WithMessage(err, "error parsing profile as channel group")
+	}
+	updated := &cb.Config{ChannelGroup: channelGroup}
+	if err := reuseEquivalentValues(original, updated); err != nil {
+		return errors.WithMessage(err, "could not compare profile with current config")
+	}
+
+	updt, err := update.Compute(original, updated)
+	if err != nil {
+		return errors.WithMessage(err, "could not compute update")
+	}
+	updt.ChannelId = channelID
+
+	logger.Info("Computed config update, differences with the current config:")
+	if err := printConfigDiff(os.Stdout, original, updated); err != nil {
+		return errors.WithMessage(err, "could not print config differences")
+	}
+
+	newConfigUpdateEnv := &cb.ConfigUpdateEnvelope{
+		ConfigUpdate: protoutil.MarshalOrPanic(updt),
+	}
+
+	updateTx, err := protoutil.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, channelID, nil, newConfigUpdateEnv, 0, 0)
+	if err != nil {
+		return errors.WithMessage(err, "could not create envelope")
+	}
+
+	logger.Info("Writing config update")
+	err = writeFile(outputConfigUpdate, protoutil.MarshalOrPanic(updateTx), 0o640)
+	if err != nil {
+		return fmt.Errorf("error writing config update: %s", err)
+	}
+	return nil
+}
+
+// configFromBlock returns the config contained in a config block, along with
+// the ID of its channel.
+func configFromBlock(block *cb.Block) (*cb.Config, string, error) {
+	env, err := protoutil.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, "", err
+	}
+	if payload.Header == nil {
+		return nil, "", errors.New("missing payload header")
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, "", err
+	}
+	if cb.HeaderType(chdr.Type) != cb.HeaderType_CONFIG {
+		return nil, "", errors.Errorf("block %d is not a config block", block.Header.Number)
+	}
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "invalid config envelope")
+	}
+	if configEnv.Config == nil || configEnv.Config.ChannelGroup == nil {
+		return nil, "", errors.New("missing channel group in config envelope")
+	}
+	return configEnv.Config, chdr.ChannelId, nil
+}
+
+// desiredChannelGroup returns the channel group described by conf. The parts
+// of the current channel group which a profile does not define, such as the
+// orderer section missing from a channel creation profile, are retained, so
+// that the update does not remove them from the channel.
+func desiredChannelGroup(conf *genesisconfig.Profile, current *cb.ConfigGroup) (*cb.ConfigGroup, error) {
+	channelGroup, err := encoder.NewChannelGroup(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	retainGroup := func(key string) {
+		if group, ok := current.Groups[key]; ok {
+			channelGroup.Groups[key] = group
+		}
+	}
+	retainValue := func(key string) {
+		if value, ok := current.Values[key]; ok {
+			channelGroup.Values[key] = value
+		}
+	}
+
+	if conf.Orderer == nil {
+		retainGroup(channelconfig.OrdererGroupKey)
+		retainValue(channelconfig.OrdererAddressesKey)
+	}
+	if conf.Application == nil {
+		retainGroup(channelconfig.ApplicationGroupKey)
+	}
+	if conf.Consortiums == nil {
+		retainGroup(channelconfig.ConsortiumsGroupKey)
+	}
+	if conf.Consortium == "" {
+		retainValue(channelconfig.ConsortiumKey)
+	}
+
+	return channelGroup, nil
+}
+
 func doInspectBlock(inspectBlock string) error {
 	logger.Info("Inspecting block")
 	data, err := ioutil.ReadFile(inspectBlock)
@@ -215,7 +344,7 @@ func dirExists(path string) (bool, error) {
 }
 
 func main() {
-	var outputBlock, outputChannelCreateTx, channelCreateTxBaseProfile, profile, configPath, channelID, inspectBlock, inspectChannelCreateTx, outputAnchorPeersUpdate, asOrg, printOrg string
+	var outputBlock, outputChannelCreateTx, channelCreateTxBaseProfile, profile, configPath, channelID, inspectBlock, inspectChannelCreateTx, outputAnchorPeersUpdate, asOrg, printOrg, configBlock, outputConfigUpdate string
 
 	flag.StringVar(&outputBlock, "outputBlock", "", "The path to write the genesis block to (if set)")
 	flag.StringVar(&channelID, "channelID", "", "The channel ID to use in the configtx")
@@ -228,6 +357,8 @@ func main() {
 	flag.StringVar(&outputAnchorPeersUpdate, "outputAnchorPeersUpdate", "", "[DEPRECATED] Creates a config update to update an anchor peer (works only with the default channel creation, and only for the first update)")
 	flag.StringVar(&asOrg, "asOrg", "", "Performs the config generation as a particular organization (by name), only including values in the write set that org (likely) has privilege to set")
 	flag.StringVar(&printOrg, "printOrg", "", "Prints the definition of an organization as JSON. (useful for adding an org to a channel manually)")
+	flag.StringVar(&configBlock, "configBlock", "", "The path of the current config block of the channel. Only valid in conjunction with 'outputConfigUpdate'.")
+	flag.StringVar(&outputConfigUpdate, "outputConfigUpdate", "", "The path to write a config update to (if set), which brings the channel of 'configBlock' to the state described by 'profile' and whose differences are printed")
 
 	version := flag.Bool("version", false, "Show version information")
 
@@ -268,9 +399,9 @@ func main() {
 		logger.Fatalf("Error on initFactories: %s", err)
 	}
 	var profileConfig *genesisconfig.Profile
-	if outputBlock != "" || outputChannelCreateTx != "" || outputAnchorPeersUpdate != "" {
+	if outputBlock != "" || outputChannelCreateTx != "" || outputAnchorPeersUpdate != "" || outputConfigUpdate != "" {
 		if profile == "" {
-			logger.Fatalf("The '-profile' is required when '-outputBlock', '-outputChannelCreateTx', or '-outputAnchorPeersUpdate' is specified")
+			logger.Fatalf("The '-profile' is required when '-outputBlock', '-outputChannelCreateTx', '-outputAnchorPeersUpdate', or '-outputConfigUpdate' is specified")
 		}
 
 		if configPath != "" {
@@ -322,6 +453,19 @@ func main() {
 		}
 	}
 
+	if configBlock != "" && outputConfigUpdate == "" {
+		logger.Warning("Specified 'configBlock', but did not specify 'outputConfigUpdate', 'configBlock' will not affect output.")
+	}
+
+	if outputConfigUpdate != "" {
+		if configBlock == "" {
+			logger.Fatalf("The '-configBlock' is required when '-outputConfigUpdate' is specified")
+		}
+		if err := doOutputConfigUpdate(profileConfig, channelID, configBlock, outputConfigUpdate); err != nil {
+			logger.Fatalf("Error on outputConfigUpdate: %s", err)
+		}
+	}
+
 	if printOrg != "" {
 		var topLevelConfig *genesisconfig.TopLevel
 		if configPath != "" {
@@ -14,9 +14,13 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/core/config/configtest"
 	"github.com/hyperledger/fabric/internal/configtxgen/genesisconfig"
+	"github.com/hyperledger/fabric/protoutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -145,6 +149,67 @@ func TestBadAnchorPeersUpdates(t *testing.T) {
 	require.EqualError(t, doOutputAnchorPeersUpdate(config, "foo", configTxDest, genesisconfig.SampleOrgName), "error parsing profile as channel group: could not create application group: failed to create application org: 1 - Error loading MSP configuration for org FakeOrg: unknown MSP type ''")
 }
 
+func TestOutputConfigUpdate(t *testing.T) {
+	blockDest := filepath.Join(tmpDir, "configUpdateBlock")
+	configUpdateDest := filepath.Join(tmpDir, "configUpdate")
+
+	config := genesisconfig.Load(genesisconfig.SampleAppChannelInsecureSoloProfile, configtest.GetDevConfigDir())
+	config.Application.Organizations = genesisconfig.LoadTopLevel(configtest.GetDevConfigDir()).Organizations
+	require.NoError(t, doOutputBlock(config, "foo", blockDest))
+
+	config.Orderer.BatchSize.MaxMessageCount++
+	require.NoError(t, doOutputConfigUpdate(config, "", blockDest, configUpdateDest))
+
+	data, err := ioutil.ReadFile(configUpdateDest)
+	require.NoError(t, err)
+	env, err := protoutil.UnmarshalEnvelope(data)
+	require.NoError(t, err)
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	require.NoError(t, err)
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	require.NoError(t, err)
+	require.Equal(t, cb.HeaderType_CONFIG_UPDATE, cb.HeaderType(chdr.Type))
+	require.Equal(t, "foo", chdr.ChannelId)
+	configUpdateEnv := &cb.ConfigUpdateEnvelope{}
+	require.NoError(t, proto.Unmarshal(payload.Data, configUpdateEnv))
+	require.Empty(t, configUpdateEnv.Signatures)
+	configUpdate := &cb.ConfigUpdate{}
+	require.NoError(t, proto.Unmarshal(configUpdateEnv.ConfigUpdate, configUpdate))
+	require.Equal(t, "foo", configUpdate.ChannelId)
+
+	ordererGroup := configUpdate.WriteSet.Groups[channelconfig.OrdererGroupKey]
+	require.NotNil(t, ordererGroup)
+	require.Len(t, ordererGroup.Values, 1)
+	require.Equal(t, uint64(1), ordererGroup.Values[channelconfig.BatchSizeKey].Version)
+	require.NotContains(t, configUpdate.WriteSet.Groups, channelconfig.ApplicationGroupKey)
+
+	// sections missing from the profile are left untouched
+	config.Orderer = nil
+	require.EqualError(t, doOutputConfigUpdate(config, "foo", blockDest, configUpdateDest), "could not compute update: no differences detected between original and updated config")
+}
+
+func TestBadOutputConfigUpdate(t *testing.T) {
+	blockDest := filepath.Join(tmpDir, "configUpdateBlock")
+	configUpdateDest := filepath.Join(tmpDir, "configUpdate")
+
+	config := genesisconfig.Load(genesisconfig.SampleAppChannelInsecureSoloProfile, configtest.GetDevConfigDir())
+	config.Application.Organizations = genesisconfig.LoadTopLevel(configtest.GetDevConfigDir()).Organizations
+	require.NoError(t, doOutputBlock(config, "foo", blockDest))
+
+	require.EqualError(t, doOutputConfigUpdate(config, "", "NonSenseBlockFile", configUpdateDest), "could not read config block NonSenseBlockFile")
+	require.EqualError(t, doOutputConfigUpdate(config, "bar", blockDest, configUpdateDest), "config block is for channel 'foo', not 'bar'")
+	require.EqualError(t, doOutputConfigUpdate(config, "foo", blockDest, configUpdateDest), "could not compute update: no differences detected between original and updated config")
+
+	block := protoutil.NewBlock(1, nil)
+	block.Data.Data = [][]byte{protoutil.MarshalOrPanic(&cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: protoutil.MakePayloadHeader(protoutil.MakeChannelHeader(cb.HeaderType_ENDORSER_TRANSACTION, 0, "foo", 0), &cb.SignatureHeader{}),
+		}),
+	})}
+	require.NoError(t, ioutil.WriteFile(blockDest, protoutil.MarshalOrPanic(block), 0o640))
+	require.EqualError(t, doOutputConfigUpdate(config, "foo", blockDest, configUpdateDest), "could not extract config from block: block 1 is not a config block")
+}
+
 func TestConfigTxFlags(t *testing.T) {
 	configTxDest := filepath.Join(tmpDir, "configtx")
 	configTxDestAnchorPeers := filepath.Join(tmpDir, "configtxAnchorPeers")
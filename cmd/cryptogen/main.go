@@ -7,11 +7,14 @@ package main
 
 import (
 	"bytes"
+	"crypto/x509"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"text/template"
 
 	"github.com/hyperledger/fabric/internal/cryptogen/ca"
@@ -66,14 +69,23 @@ type UsersSpec struct {
 	Count int `yaml:"Count"`
 }
 
+type ExternalCASpec struct {
+	Cert  string `yaml:"Cert"`
+	Key   string `yaml:"Key"`
+	Chain string `yaml:"Chain"`
+}
+
 type OrgSpec struct {
-	Name          string       `yaml:"Name"`
-	Domain        string       `yaml:"Domain"`
-	EnableNodeOUs bool         `yaml:"EnableNodeOUs"`
-	CA            NodeSpec     `yaml:"CA"`
-	Template      NodeTemplate `yaml:"Template"`
-	Specs         []NodeSpec   `yaml:"Specs"`
-	Users         UsersSpec    `yaml:"Users"`
+	Name            string          `yaml:"Name"`
+	Domain          string          `yaml:"Domain"`
+	EnableNodeOUs   bool            `yaml:"EnableNodeOUs"`
+	CA              NodeSpec        `yaml:"CA"`
+	IntermediateCAs int             `yaml:"IntermediateCAs"`
+	ExternalCA      *ExternalCASpec `yaml:"ExternalCA"`
+	ExternalTLSCA   *ExternalCASpec `yaml:"ExternalTLSCA"`
+	Template        NodeTemplate    `yaml:"Template"`
+	Specs           []NodeSpec      `yaml:"Specs"`
+	Users           UsersSpec       `yaml:"Users"`
 }
 
 type Config struct {
@@ -125,6 +137,35 @@ PeerOrgs:
     #    StreetAddress: address for org # default nil
     #    PostalCode: postalCode for org # default nil
 
+    # ---------------------------------------------------------------------------
+    # "IntermediateCAs"
+    # ---------------------------------------------------------------------------
+    # Uncomment this entry to generate a hierarchy of intermediate CAs below the
+    # root CA of this organization, for both the signing and the TLS CAs. The
+    # last intermediate CA issues the certificates of the nodes and users, and
+    # the intermediate CAs are added to the intermediatecerts and
+    # tlsintermediatecerts folders of the MSPs.
+    # ---------------------------------------------------------------------------
+    # IntermediateCAs: 1
+
+    # ---------------------------------------------------------------------------
+    # "ExternalCA" and "ExternalTLSCA"
+    # ---------------------------------------------------------------------------
+    # Uncomment these sections to import an existing root or intermediate CA
+    # instead of generating the root CA of this organization. Cert and Key are
+    # the PEM-encoded certificate and private key of the CA. When the CA is an
+    # intermediate CA, Chain holds the certificates of its issuers, up to the
+    # root CA. The intermediate CAs set by IntermediateCAs are generated below
+    # the imported CA.
+    # ---------------------------------------------------------------------------
+    # ExternalCA:
+    #    Cert: /path/to/ca-cert.pem
+    #    Key: /path/to/ca-key.pem
+    #    Chain: /path/to/ca-chain.pem
+    # ExternalTLSCA:
+    #    Cert: /path/to/tlsca-cert.pem
+    #    Key: /path/to/tlsca-key.pem
+
     # ---------------------------------------------------------------------------
     # "Specs"
     # ---------------------------------------------------------------------------
@@ -213,6 +254,11 @@ var (
 	ext           = app.Command("extend", "Extend existing network")
 	inputDir      = ext.Flag("input", "The input directory in which existing network place").Default("crypto-config").String()
 	extConfigFile = ext.Flag("config", "The configuration template to use").File()
+
+	ren            = app.Command("renew", "Renew the certificates of an existing network under its CA keys")
+	renewInputDir  = ren.Flag("input", "The input directory in which existing network place").Default("crypto-config").String()
+	renewOutputDir = ren.Flag("output", "The output directory in which to place the renewed artifacts").Default("crypto-config-renewed").String()
+	renewRotateCA  = ren.Flag("rotate-ca", "Issue the certificates under new CA keys, cross-signed by the existing CAs").Bool()
 )
 
 func main() {
@@ -226,6 +272,9 @@ func main() {
 	case ext.FullCommand():
 		extend()
 
+	case ren.FullCommand():
+		renew()
+
 		// "showtemplate" command
 	case showtemplate.FullCommand():
 		fmt.Print(defaultConfig)
@@ -306,8 +355,16 @@ func extendPeerOrg(orgSpec OrgSpec) {
 	caDir := filepath.Join(orgDir, "ca")
 	tlscaDir := filepath.Join(orgDir, "tlsca")
 
-	signCA := getCA(caDir, orgSpec, orgSpec.CA.CommonName)
-	tlsCA := getCA(tlscaDir, orgSpec, "tls"+orgSpec.CA.CommonName)
+	signCA, err := getCA(caDir, orgSpec, orgSpec.CA.CommonName)
+	if err != nil {
+		fmt.Printf("Error loading signCA for org %s:\n%v\n", orgName, err)
+		os.Exit(1)
+	}
+	tlsCA, err := getCA(tlscaDir, orgSpec, "tls"+orgSpec.CA.CommonName)
+	if err != nil {
+		fmt.Printf("Error loading tlsCA for org %s:\n%v\n", orgName, err)
+		os.Exit(1)
+	}
 
 	generateNodes(peersDir, orgSpec.Specs, signCA, tlsCA, msp.PEER, orgSpec.EnableNodeOUs)
 
@@ -355,8 +412,16 @@ func extendOrdererOrg(orgSpec OrgSpec) {
 		return
 	}
 
-	signCA := getCA(caDir, orgSpec, orgSpec.CA.CommonName)
-	tlsCA := getCA(tlscaDir, orgSpec, "tls"+orgSpec.CA.CommonName)
+	signCA, err := getCA(caDir, orgSpec, orgSpec.CA.CommonName)
+	if err != nil {
+		fmt.Printf("Error loading signCA for org %s:\n%v\n", orgName, err)
+		os.Exit(1)
+	}
+	tlsCA, err := getCA(tlscaDir, orgSpec, "tls"+orgSpec.CA.CommonName)
+	if err != nil {
+		fmt.Printf("Error loading tlsCA for org %s:\n%v\n", orgName, err)
+		os.Exit(1)
+	}
 
 	generateNodes(orderersDir, orgSpec.Specs, signCA, tlsCA, msp.ORDERER, orgSpec.EnableNodeOUs)
 
@@ -512,20 +577,18 @@ func generatePeerOrg(baseDir string, orgSpec OrgSpec) {
 	fmt.Println(orgName)
 	// generate CAs
 	orgDir := filepath.Join(baseDir, "peerOrganizations", orgName)
-	caDir := filepath.Join(orgDir, "ca")
-	tlsCADir := filepath.Join(orgDir, "tlsca")
 	mspDir := filepath.Join(orgDir, "msp")
 	peersDir := filepath.Join(orgDir, "peers")
 	usersDir := filepath.Join(orgDir, "users")
 	adminCertsDir := filepath.Join(mspDir, "admincerts")
 	// generate signing CA
-	signCA, err := ca.NewCA(caDir, orgName, orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode)
+	signCA, err := generateCA(orgDir, "ca", orgSpec.CA.CommonName, orgSpec, orgSpec.ExternalCA)
 	if err != nil {
 		fmt.Printf("Error generating signCA for org %s:\n%v\n", orgName, err)
 		os.Exit(1)
 	}
 	// generate TLS CA
-	tlsCA, err := ca.NewCA(tlsCADir, orgName, "tls"+orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode)
+	tlsCA, err := generateCA(orgDir, "tlsca", "tls"+orgSpec.CA.CommonName, orgSpec, orgSpec.ExternalTLSCA)
 	if err != nil {
 		fmt.Printf("Error generating tlsCA for org %s:\n%v\n", orgName, err)
 		os.Exit(1)
@@ -628,20 +691,18 @@ func generateOrdererOrg(baseDir string, orgSpec OrgSpec) {
 
 	// generate CAs
 	orgDir := filepath.Join(baseDir, "ordererOrganizations", orgName)
-	caDir := filepath.Join(orgDir, "ca")
-	tlsCADir := filepath.Join(orgDir, "tlsca")
 	mspDir := filepath.Join(orgDir, "msp")
 	orderersDir := filepath.Join(orgDir, "orderers")
 	usersDir := filepath.Join(orgDir, "users")
 	adminCertsDir := filepath.Join(mspDir, "admincerts")
 	// generate signing CA
-	signCA, err := ca.NewCA(caDir, orgName, orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode)
+	signCA, err := generateCA(orgDir, "ca", orgSpec.CA.CommonName, orgSpec, orgSpec.ExternalCA)
 	if err != nil {
 		fmt.Printf("Error generating signCA for org %s:\n%v\n", orgName, err)
 		os.Exit(1)
 	}
 	// generate TLS CA
-	tlsCA, err := ca.NewCA(tlsCADir, orgName, "tls"+orgSpec.CA.CommonName, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode)
+	tlsCA, err := generateCA(orgDir, "tlsca", "tls"+orgSpec.CA.CommonName, orgSpec, orgSpec.ExternalTLSCA)
 	if err != nil {
 		fmt.Printf("Error generating tlsCA for org %s:\n%v\n", orgName, err)
 		os.Exit(1)
@@ -691,6 +752,97 @@ func generateOrdererOrg(baseDir string, orgSpec OrgSpec) {
 	}
 }
 
+// generateCA generates, or imports from external, the root CA of the
+// organization, and the intermediate CAs set by IntermediateCAs below it. The
+// CA that issues the certificates of the nodes and users, the last
+// intermediate CA if any, is saved in orgDir/caDirName, and the CAs above it
+// in orgDir/caDirName+"chain"/<n>, n counting the levels above the issuing CA.
+func generateCA(orgDir, caDirName, name string, orgSpec OrgSpec, external *ExternalCASpec) (*ca.CA, error) {
+	levelDir := func(level int) string {
+		if level == 0 {
+			return filepath.Join(orgDir, caDirName)
+		}
+		return filepath.Join(orgDir, caDirName+"chain", strconv.Itoa(level))
+	}
+
+	spec := orgSpec.CA
+	depth := orgSpec.IntermediateCAs
+	if depth < 0 {
+		return nil, fmt.Errorf("invalid number of intermediate CAs: %d", depth)
+	}
+
+	var signCA *ca.CA
+	var err error
+	if external != nil {
+		signCA, err = ca.ImportCA(levelDir(depth), external.Cert, external.Key, external.Chain)
+		if err != nil {
+			return nil, err
+		}
+		// the issuers of an imported intermediate CA are saved without keys
+		for i, cert := range signCA.Chain {
+			err = exportCert(levelDir(depth+1+i), cert)
+			if err != nil {
+				return nil, err
+			}
+		}
+		name = signCA.Name
+	} else {
+		signCA, err = ca.NewCA(levelDir(depth), orgSpec.Domain, name, spec.Country, spec.Province, spec.Locality, spec.OrganizationalUnit, spec.StreetAddress, spec.PostalCode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for level := depth - 1; level >= 0; level-- {
+		intermediateName := fmt.Sprintf("intermediate%d.%s", depth-level, name)
+		signCA, err = signCA.NewIntermediateCA(levelDir(level), orgSpec.Domain, intermediateName, spec.Country, spec.Province, spec.Locality, spec.OrganizationalUnit, spec.StreetAddress, spec.PostalCode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	signCA.Country = spec.Country
+	signCA.Province = spec.Province
+	signCA.Locality = spec.Locality
+	signCA.OrganizationalUnit = spec.OrganizationalUnit
+	signCA.StreetAddress = spec.StreetAddress
+	signCA.PostalCode = spec.PostalCode
+
+	return signCA, nil
+}
+
+// loadChain loads the certificates of the CAs saved by generateCA in chainDir,
+// from the direct issuer of the issuing CA up to the root CA.
+func loadChain(chainDir string) ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+	for level := 1; ; level++ {
+		levelDir := filepath.Join(chainDir, strconv.Itoa(level))
+		if _, err := os.Stat(levelDir); err != nil {
+			return chain, nil
+		}
+		cert, err := ca.LoadCertificateECDSA(levelDir)
+		if err != nil {
+			return nil, err
+		}
+		if cert == nil {
+			return nil, fmt.Errorf("no CA certificate found in %s", levelDir)
+		}
+		chain = append(chain, cert)
+	}
+}
+
+func exportCert(dir string, cert *x509.Certificate) error {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(
+		filepath.Join(dir, cert.Subject.CommonName+"-cert.pem"),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}),
+		0o644,
+	)
+}
+
 func copyFile(src, dst string) error {
 	in, err := os.Open(src)
 	if err != nil {
@@ -714,19 +866,28 @@ func printVersion() {
 	fmt.Println(metadata.GetVersionInfo())
 }
 
-func getCA(caDir string, spec OrgSpec, name string) *ca.CA {
+func getCA(caDir string, spec OrgSpec, name string) (*ca.CA, error) {
 	priv, _ := csp.LoadPrivateKey(caDir)
 	cert, _ := ca.LoadCertificateECDSA(caDir)
+	if cert != nil {
+		// the name of an imported or intermediate CA differs from the spec
+		name = cert.Subject.CommonName
+	}
+	chain, err := loadChain(caDir + "chain")
+	if err != nil {
+		return nil, err
+	}
 
 	return &ca.CA{
 		Name:               name,
 		Signer:             priv,
 		SignCert:           cert,
+		Chain:              chain,
 		Country:            spec.CA.Country,
 		Province:           spec.CA.Province,
 		Locality:           spec.CA.Locality,
 		OrganizationalUnit: spec.CA.OrganizationalUnit,
 		StreetAddress:      spec.CA.StreetAddress,
 		PostalCode:         spec.CA.PostalCode,
-	}
+	}, nil
 }
@@ -0,0 +1,37 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package main
+
+import (
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/internal/cryptogen/ca"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadChain(t *testing.T) {
+	chainDir := filepath.Join(t.TempDir(), "cachain")
+	chain, err := loadChain(chainDir)
+	require.NoError(t, err)
+	require.Empty(t, chain)
+
+	rootCA, err := ca.NewCA(filepath.Join(chainDir, "1"), testOrgDomain, "ca."+testOrgDomain, "", "", "", "", "", "")
+	require.NoError(t, err)
+	chain, err = loadChain(chainDir)
+	require.NoError(t, err)
+	require.Equal(t, []*x509.Certificate{rootCA.SignCert}, chain)
+
+	require.NoError(t, os.MkdirAll(filepath.Join(chainDir, "2"), 0o755))
+	_, err = loadChain(chainDir)
+	require.EqualError(t, err, "no CA certificate found in "+filepath.Join(chainDir, "2"))
+
+	require.NoError(t, os.WriteFile(filepath.Join(chainDir, "2", "ca-cert.pem"), []byte("garbage"), 0o644))
+	_, err = loadChain(chainDir)
+	require.Error(t, err)
+}
@@ -0,0 +1,499 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric/internal/cryptogen/ca"
+	"github.com/hyperledger/fabric/internal/cryptogen/csp"
+	"github.com/hyperledger/fabric/internal/cryptogen/msp"
+	"github.com/pkg/errors"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const renewalReportFile = "renewal-report.yaml"
+
+// RenewalReport lists the certificates written by the renew command.
+type RenewalReport struct {
+	Certificates []RenewedCertificate `yaml:"Certificates"`
+}
+
+// RenewedCertificate describes a certificate written by the renew command.
+// Action is "renewed" for a certificate reissued in place of a previous one,
+// "issued" for the certificate of a new CA, and "updated" for a copy of a
+// renewed certificate.
+type RenewedCertificate struct {
+	Path                 string `yaml:"Path"`
+	Action               string `yaml:"Action"`
+	Subject              string `yaml:"Subject"`
+	Issuer               string `yaml:"Issuer"`
+	SubjectKeyID         string `yaml:"SubjectKeyID,omitempty"`
+	SerialNumber         string `yaml:"SerialNumber"`
+	NotAfter             string `yaml:"NotAfter"`
+	PreviousSerialNumber string `yaml:"PreviousSerialNumber,omitempty"`
+	PreviousNotAfter     string `yaml:"PreviousNotAfter,omitempty"`
+}
+
+func renew() {
+	report, err := renewNetwork(*renewInputDir, *renewOutputDir, *renewRotateCA)
+	if err != nil {
+		fmt.Printf("Error renewing certificates:\n%v\n", err)
+		os.Exit(1)
+	}
+
+	reportBytes, err := yaml.Marshal(report)
+	if err != nil {
+		fmt.Printf("Error marshaling renewal report:\n%v\n", err)
+		os.Exit(1)
+	}
+	reportPath := filepath.Join(*renewOutputDir, renewalReportFile)
+	err = os.WriteFile(reportPath, reportBytes, 0o644)
+	if err != nil {
+		fmt.Printf("Error writing renewal report:\n%v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Renewed %d certificates, see %s\n", len(report.Certificates), reportPath)
+}
+
+// renewNetwork copies the network in inputDir to outputDir and reissues there
+// the certificates of its nodes and users under the keys of their CAs. The CA
+// certificates are reissued under their own keys. When rotateCA is set, new CA
+// keys are generated, cross-signed by the previous CAs, and the certificates
+// of the nodes and users are issued under the new keys.
+func renewNetwork(inputDir, outputDir string, rotateCA bool) (report *RenewalReport, err error) {
+	if _, err := os.Stat(outputDir); !os.IsNotExist(err) {
+		return nil, errors.Errorf("output directory %s already exists", outputDir)
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(outputDir)
+		}
+	}()
+	err = copyDir(inputDir, outputDir)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to copy %s to %s", inputDir, outputDir)
+	}
+
+	report = &RenewalReport{}
+	for _, orgsDir := range []string{"peerOrganizations", "ordererOrganizations"} {
+		orgDirs, err := subDirs(filepath.Join(outputDir, orgsDir))
+		if err != nil {
+			return nil, err
+		}
+		for _, orgDir := range orgDirs {
+			fmt.Println(filepath.Base(orgDir))
+			renewed, err := renewOrg(orgDir, rotateCA)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "failed to renew organization %s", filepath.Base(orgDir))
+			}
+			for _, c := range renewed {
+				c.Path, err = filepath.Rel(outputDir, c.Path)
+				if err != nil {
+					return nil, err
+				}
+				report.Certificates = append(report.Certificates, *c)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func renewOrg(orgDir string, rotateCA bool) ([]*RenewedCertificate, error) {
+	var renewed []*RenewedCertificate
+	var renewals []*msp.Renewal
+
+	issuingCAs := map[string]*ca.CA{}
+	for _, caDirName := range []string{"ca", "tlsca"} {
+		hierarchy, err := loadCAHierarchy(orgDir, caDirName)
+		if err != nil {
+			return nil, err
+		}
+		if rotateCA {
+			// a previous root CA certifies the new CA, while a previous
+			// intermediate CA is kept aside
+			previousDir := filepath.Join(orgDir, caDirName+"chain", "1")
+			if len(hierarchy) > 1 {
+				previousDir = filepath.Join(orgDir, "previous", caDirName, hierarchy[0].ca.SignCert.SerialNumber.Text(16))
+			}
+			err = os.MkdirAll(filepath.Dir(previousDir), 0o755)
+			if err != nil {
+				return nil, err
+			}
+			err = os.Rename(hierarchy[0].dir, previousDir)
+			if err != nil {
+				return nil, err
+			}
+			hierarchy[0].dir = previousDir
+		}
+
+		caRenewals, err := renewCAs(hierarchy)
+		if err != nil {
+			return nil, err
+		}
+		for _, renewal := range caRenewals {
+			renewed = append(renewed, renewedCertificate("renewed", renewal))
+		}
+		renewals = append(renewals, caRenewals...)
+
+		if !rotateCA {
+			issuingCAs[caDirName] = hierarchy[0].ca
+			continue
+		}
+
+		issuingCA, rotated, err := rotate(orgDir, caDirName, hierarchy)
+		if err != nil {
+			return nil, err
+		}
+		issuingCAs[caDirName] = issuingCA
+		renewed = append(renewed, rotated...)
+	}
+	signCA, tlsCA := issuingCAs["ca"], issuingCAs["tlsca"]
+
+	err := msp.AddIntermediateCAs(filepath.Join(orgDir, "msp"), signCA, tlsCA)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, nodesDir := range []string{"peers", "orderers", "users"} {
+		nodeDirs, err := subDirs(filepath.Join(orgDir, nodesDir))
+		if err != nil {
+			return nil, err
+		}
+		for _, nodeDir := range nodeDirs {
+			nodeRenewals, err := msp.RenewLocalMSP(nodeDir, signCA, tlsCA)
+			if err != nil {
+				return nil, errors.WithMessagef(err, "failed to renew local MSP of %s", filepath.Base(nodeDir))
+			}
+			for _, renewal := range nodeRenewals {
+				renewed = append(renewed, renewedCertificate("renewed", renewal))
+			}
+			renewals = append(renewals, nodeRenewals...)
+		}
+	}
+
+	copies, err := updateCopies(orgDir, renewals)
+	if err != nil {
+		return nil, err
+	}
+	for _, renewal := range copies {
+		renewed = append(renewed, renewedCertificate("updated", renewal))
+	}
+
+	return renewed, nil
+}
+
+// caLevel is a CA of the hierarchy of an organization, along with the
+// directory in which it is saved.
+type caLevel struct {
+	dir string
+	ca  *ca.CA
+}
+
+// loadCAHierarchy loads the issuing CA saved in orgDir/caDirName and the CAs
+// above it, from its direct issuer up to the root CA. The keys of the issuers
+// of an imported CA are not available, and their Signer is nil.
+func loadCAHierarchy(orgDir, caDirName string) ([]*caLevel, error) {
+	caDir := filepath.Join(orgDir, caDirName)
+	issuingCA, err := loadCA(caDir)
+	if err != nil {
+		return nil, err
+	}
+	if issuingCA.Signer == nil {
+		return nil, errors.Errorf("no CA key found in %s", caDir)
+	}
+
+	hierarchy := []*caLevel{{dir: caDir, ca: issuingCA}}
+	for level := 1; ; level++ {
+		levelDir := filepath.Join(orgDir, caDirName+"chain", strconv.Itoa(level))
+		if _, err := os.Stat(levelDir); os.IsNotExist(err) {
+			break
+		}
+		issuer, err := loadCA(levelDir)
+		if err != nil {
+			return nil, err
+		}
+		hierarchy = append(hierarchy, &caLevel{dir: levelDir, ca: issuer})
+	}
+	setChains(hierarchy)
+
+	return hierarchy, nil
+}
+
+// renewCAs reissues the certificates of the CAs of the hierarchy from the root
+// CA down, each under the key of its issuer. The certificates whose issuer key
+// is not available are left as they are.
+func renewCAs(hierarchy []*caLevel) ([]*msp.Renewal, error) {
+	var renewals []*msp.Renewal
+	for i := len(hierarchy) - 1; i >= 0; i-- {
+		c := hierarchy[i].ca
+		issuer := c
+		if i < len(hierarchy)-1 {
+			issuer = hierarchy[i+1].ca
+		}
+		if issuer.Signer == nil {
+			continue
+		}
+
+		cert, err := issuer.RenewCertificate(hierarchy[i].dir, c.Name, c.SignCert)
+		if err != nil {
+			return nil, err
+		}
+		renewals = append(renewals, &msp.Renewal{
+			Path:     filepath.Join(hierarchy[i].dir, c.Name+"-cert.pem"),
+			Previous: c.SignCert,
+			Renewed:  cert,
+		})
+		c.SignCert = cert
+	}
+	setChains(hierarchy)
+
+	return renewals, nil
+}
+
+func setChains(hierarchy []*caLevel) {
+	for i, level := range hierarchy {
+		level.ca.Chain = nil
+		for _, issuer := range hierarchy[i+1:] {
+			level.ca.Chain = append(level.ca.Chain, issuer.ca.SignCert)
+		}
+	}
+}
+
+// rotate generates a new issuing CA in orgDir/caDirName, with the same subject
+// as the previous one. An intermediate CA is replaced by a new intermediate CA
+// certified by the same issuer. A root CA is replaced by a new root CA, whose
+// key is certified by the previous root CA with a cross-signed intermediate
+// certificate, which makes the certificates issued under the new key
+// verifiable against the previous root CA. The cross-signed certificate is
+// saved in orgDir/caDirName, and the self-signed certificate of the new root
+// CA in orgDir/selfsigned/caDirName. The returned CA issues certificates under
+// the new key.
+func rotate(orgDir, caDirName string, hierarchy []*caLevel) (*ca.CA, []*RenewedCertificate, error) {
+	caDir := filepath.Join(orgDir, caDirName)
+	previousCA := hierarchy[0].ca
+	subject := previousCA.SignCert.Subject
+
+	if len(hierarchy) > 1 {
+		issuer := hierarchy[1].ca
+		if issuer.Signer == nil {
+			return nil, nil, errors.Errorf("the key of CA %s, which issued CA %s, is not available", issuer.Name, previousCA.Name)
+		}
+		newCA, err := issuer.NewIntermediateCA(
+			caDir,
+			first(subject.Organization),
+			previousCA.Name,
+			first(subject.Country),
+			first(subject.Province),
+			first(subject.Locality),
+			first(subject.OrganizationalUnit),
+			first(subject.StreetAddress),
+			first(subject.PostalCode),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		rotated := []*RenewedCertificate{
+			renewedCertificate("issued", &msp.Renewal{
+				Path:    filepath.Join(caDir, newCA.Name+"-cert.pem"),
+				Renewed: newCA.SignCert,
+			}),
+		}
+		return newCA, rotated, nil
+	}
+
+	newCA, err := ca.NewCA(
+		caDir,
+		first(subject.Organization),
+		previousCA.Name,
+		first(subject.Country),
+		first(subject.Province),
+		first(subject.Locality),
+		first(subject.OrganizationalUnit),
+		first(subject.StreetAddress),
+		first(subject.PostalCode),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	selfSignedDir := filepath.Join(orgDir, "selfsigned", caDirName)
+	err = exportCert(selfSignedDir, newCA.SignCert)
+	if err != nil {
+		return nil, nil, err
+	}
+	crossSigned, err := previousCA.RenewCertificate(caDir, newCA.Name, newCA.SignCert)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rotated := []*RenewedCertificate{
+		renewedCertificate("issued", &msp.Renewal{
+			Path:    filepath.Join(selfSignedDir, newCA.Name+"-cert.pem"),
+			Renewed: newCA.SignCert,
+		}),
+		renewedCertificate("issued", &msp.Renewal{
+			Path:    filepath.Join(caDir, newCA.Name+"-cert.pem"),
+			Renewed: crossSigned,
+		}),
+	}
+	issuingCA := &ca.CA{
+		Name:     newCA.Name,
+		Signer:   newCA.Signer,
+		SignCert: crossSigned,
+		Chain:    []*x509.Certificate{previousCA.SignCert},
+	}
+
+	return issuingCA, rotated, nil
+}
+
+// updateCopies replaces, in the certificate files under dir, the previous
+// certificates of the renewals with the renewed ones.
+func updateCopies(dir string, renewals []*msp.Renewal) ([]*msp.Renewal, error) {
+	renewedCerts := map[string]*msp.Renewal{}
+	for _, renewal := range renewals {
+		renewedCerts[string(renewal.Previous.Raw)] = renewal
+	}
+
+	var copies []*msp.Renewal
+	walkFunc := func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !(strings.HasSuffix(path, ".pem") || strings.HasSuffix(path, ".crt")) {
+			return nil
+		}
+
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var updated bytes.Buffer
+		var replaced []*msp.Renewal
+		for block, rest := pem.Decode(raw); block != nil; block, rest = pem.Decode(rest) {
+			if renewal, ok := renewedCerts[string(block.Bytes)]; ok && block.Type == "CERTIFICATE" {
+				block = &pem.Block{Type: "CERTIFICATE", Bytes: renewal.Renewed.Raw}
+				replaced = append(replaced, &msp.Renewal{
+					Path:     path,
+					Previous: renewal.Previous,
+					Renewed:  renewal.Renewed,
+				})
+			}
+			if err := pem.Encode(&updated, block); err != nil {
+				return err
+			}
+		}
+		if len(replaced) == 0 {
+			return nil
+		}
+
+		copies = append(copies, replaced...)
+		return os.WriteFile(path, updated.Bytes(), info.Mode())
+	}
+
+	err := filepath.Walk(dir, walkFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	return copies, nil
+}
+
+func loadCA(caDir string) (*ca.CA, error) {
+	priv, err := csp.LoadPrivateKey(caDir)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := ca.LoadCertificateECDSA(caDir)
+	if err != nil {
+		return nil, err
+	}
+	if cert == nil {
+		return nil, errors.Errorf("no CA certificate found in %s", caDir)
+	}
+
+	c := &ca.CA{
+		Name:     cert.Subject.CommonName,
+		SignCert: cert,
+	}
+	if priv != nil {
+		c.Signer = &csp.ECDSASigner{PrivateKey: priv}
+	}
+	return c, nil
+}
+
+func renewedCertificate(action string, renewal *msp.Renewal) *RenewedCertificate {
+	c := &RenewedCertificate{
+		Path:         renewal.Path,
+		Action:       action,
+		Subject:      renewal.Renewed.Subject.String(),
+		Issuer:       renewal.Renewed.Issuer.String(),
+		SubjectKeyID: hex.EncodeToString(renewal.Renewed.SubjectKeyId),
+		SerialNumber: renewal.Renewed.SerialNumber.Text(16),
+		NotAfter:     renewal.Renewed.NotAfter.Format(time.RFC3339),
+	}
+	if renewal.Previous != nil {
+		c.PreviousSerialNumber = renewal.Previous.SerialNumber.Text(16)
+		c.PreviousNotAfter = renewal.Previous.NotAfter.Format(time.RFC3339)
+	}
+	return c
+}
+
+func subDirs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return dirs, nil
+}
+
+func copyDir(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if info.IsDir() {
+			return os.MkdirAll(target, info.Mode())
+		}
+		err = copyFile(path, target)
+		if err != nil {
+			return err
+		}
+		return os.Chmod(target, info.Mode())
+	})
+}
+
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
@@ -0,0 +1,148 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/internal/cryptogen/ca"
+	"github.com/stretchr/testify/require"
+)
+
+const testOrgDomain = "org1.example.com"
+
+func generateTestNetwork(t *testing.T, baseDir string) {
+	orgSpec := OrgSpec{
+		Domain:        testOrgDomain,
+		EnableNodeOUs: true,
+		Template:      NodeTemplate{Count: 1},
+		Users:         UsersSpec{Count: 1},
+	}
+	require.NoError(t, renderOrgSpec(&orgSpec, "peer"))
+	generatePeerOrg(baseDir, orgSpec)
+}
+
+func loadTestCert(t *testing.T, dir string) *x509.Certificate {
+	cert, err := ca.LoadCertificateECDSA(dir)
+	require.NoError(t, err)
+	require.NotNil(t, cert)
+	return cert
+}
+
+func verifyTestCert(t *testing.T, cert, root *x509.Certificate, intermediates ...*x509.Certificate) {
+	roots := x509.NewCertPool()
+	roots.AddCert(root)
+	pool := x509.NewCertPool()
+	for _, c := range intermediates {
+		pool.AddCert(c)
+	}
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: pool,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	require.NoError(t, err)
+}
+
+func TestRenewNetwork(t *testing.T) {
+	testDir := t.TempDir()
+	inputDir := filepath.Join(testDir, "crypto-config")
+	outputDir := filepath.Join(testDir, "crypto-config-renewed")
+	generateTestNetwork(t, inputDir)
+
+	orgDir := filepath.Join("peerOrganizations", testOrgDomain)
+	peerMSPDir := filepath.Join(orgDir, "peers", "peer0."+testOrgDomain, "msp")
+	caCert := loadTestCert(t, filepath.Join(inputDir, orgDir, "ca"))
+	signCert := loadTestCert(t, filepath.Join(inputDir, peerMSPDir, "signcerts"))
+
+	report, err := renewNetwork(inputDir, outputDir, false)
+	require.NoError(t, err)
+	require.NotEmpty(t, report.Certificates)
+
+	// the input network is left untouched
+	require.Equal(t, caCert, loadTestCert(t, filepath.Join(inputDir, orgDir, "ca")))
+	require.Equal(t, signCert, loadTestCert(t, filepath.Join(inputDir, peerMSPDir, "signcerts")))
+
+	// the certificates are reissued with the same subject key identifiers,
+	// so that the renewed CA still verifies the certificates issued before
+	renewedCACert := loadTestCert(t, filepath.Join(outputDir, orgDir, "ca"))
+	require.Equal(t, caCert.SubjectKeyId, renewedCACert.SubjectKeyId)
+	require.Equal(t, caCert.Subject.String(), renewedCACert.Subject.String())
+	require.NotEqual(t, caCert.SerialNumber, renewedCACert.SerialNumber)
+	verifyTestCert(t, signCert, renewedCACert)
+
+	renewedSignCert := loadTestCert(t, filepath.Join(outputDir, peerMSPDir, "signcerts"))
+	require.Equal(t, signCert.SubjectKeyId, renewedSignCert.SubjectKeyId)
+	require.Equal(t, signCert.PublicKey, renewedSignCert.PublicKey)
+	require.NotEqual(t, signCert.SerialNumber, renewedSignCert.SerialNumber)
+	verifyTestCert(t, renewedSignCert, caCert)
+	verifyTestCert(t, renewedSignCert, renewedCACert)
+
+	// the copies of the CA certificate in the MSPs are updated
+	require.Equal(t, renewedCACert, loadTestCert(t, filepath.Join(outputDir, peerMSPDir, "cacerts")))
+	require.Equal(t, renewedCACert, loadTestCert(t, filepath.Join(outputDir, orgDir, "msp", "cacerts")))
+
+	_, err = renewNetwork(inputDir, outputDir, false)
+	require.EqualError(t, err, "output directory "+outputDir+" already exists")
+}
+
+func TestRenewNetworkRotateCA(t *testing.T) {
+	testDir := t.TempDir()
+	inputDir := filepath.Join(testDir, "crypto-config")
+	outputDir := filepath.Join(testDir, "crypto-config-renewed")
+	generateTestNetwork(t, inputDir)
+
+	orgDir := filepath.Join("peerOrganizations", testOrgDomain)
+	peerDir := filepath.Join(orgDir, "peers", "peer0."+testOrgDomain)
+	caCert := loadTestCert(t, filepath.Join(inputDir, orgDir, "ca"))
+	tlsCACert := loadTestCert(t, filepath.Join(inputDir, orgDir, "tlsca"))
+	signCert := loadTestCert(t, filepath.Join(inputDir, peerDir, "msp", "signcerts"))
+
+	_, err := renewNetwork(inputDir, outputDir, true)
+	require.NoError(t, err)
+
+	// the certificates are issued under the new CA key
+	crossSigned := loadTestCert(t, filepath.Join(outputDir, orgDir, "ca"))
+	require.NotEqual(t, caCert.SubjectKeyId, crossSigned.SubjectKeyId)
+	require.NoError(t, crossSigned.CheckSignatureFrom(caCert))
+	renewedSignCert := loadTestCert(t, filepath.Join(outputDir, peerDir, "msp", "signcerts"))
+	require.Equal(t, signCert.SubjectKeyId, renewedSignCert.SubjectKeyId)
+	require.NoError(t, renewedSignCert.CheckSignatureFrom(crossSigned))
+
+	// the chain through the cross-signed certificate added to the MSP
+	// verifies against the previous root CA, which remains in cacerts, and
+	// against the self-signed certificate of the new root CA
+	intermediateCert := loadTestCert(t, filepath.Join(outputDir, peerDir, "msp", "intermediatecerts"))
+	require.Equal(t, crossSigned, intermediateCert)
+	rootCert := loadTestCert(t, filepath.Join(outputDir, peerDir, "msp", "cacerts"))
+	require.Equal(t, caCert.SubjectKeyId, rootCert.SubjectKeyId)
+	verifyTestCert(t, renewedSignCert, rootCert, intermediateCert)
+	verifyTestCert(t, renewedSignCert, caCert, intermediateCert)
+	newRootCert := loadTestCert(t, filepath.Join(outputDir, orgDir, "selfsigned", "ca"))
+	require.Equal(t, crossSigned.SubjectKeyId, newRootCert.SubjectKeyId)
+	verifyTestCert(t, renewedSignCert, newRootCert)
+
+	// the TLS certificate is served along with the cross-signed certificate
+	tlsDir := filepath.Join(outputDir, peerDir, "tls")
+	keyPair, err := tls.LoadX509KeyPair(filepath.Join(tlsDir, "server.crt"), filepath.Join(tlsDir, "server.key"))
+	require.NoError(t, err)
+	require.Len(t, keyPair.Certificate, 2)
+	tlsCert, err := x509.ParseCertificate(keyPair.Certificate[0])
+	require.NoError(t, err)
+	tlsCrossSigned, err := x509.ParseCertificate(keyPair.Certificate[1])
+	require.NoError(t, err)
+	verifyTestCert(t, tlsCert, tlsCACert, tlsCrossSigned)
+
+	// the previous CA is kept, with its key, as the issuer of the new CA
+	previousDir := filepath.Join(outputDir, orgDir, "cachain", "1")
+	require.Equal(t, caCert.SubjectKeyId, loadTestCert(t, previousDir).SubjectKeyId)
+	_, err = os.Stat(filepath.Join(outputDir, orgDir, "ca", "priv_sk"))
+	require.NoError(t, err)
+}
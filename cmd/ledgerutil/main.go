@@ -8,10 +8,12 @@ package main
 
 import (
 	"fmt"
+	"math"
 	"os"
 
 	"github.com/hyperledger/fabric/internal/ledgerutil/compare"
 	"github.com/hyperledger/fabric/internal/ledgerutil/identifytxs"
+	"github.com/hyperledger/fabric/internal/ledgerutil/revalidate"
 	"github.com/hyperledger/fabric/internal/ledgerutil/verify"
 	"gopkg.in/alecthomas/kingpin.v2"
 )
@@ -27,10 +29,19 @@ const (
 		"from ledgerutil compare."
 	blockStorePathDesc = "Path to file system of target peer, used to access block store. Defaults to '/var/hyperledger/production'. " +
 		"IMPORTANT: If the configuration for target peer's file system path was changed, the new path MUST be provided."
-	blockStorePathDefault = "/var/hyperledger/production"
-	outputDirIdDesc       = "Location for identified transactions json results output directory. Default is the current directory."
-	verifyErrorMessage    = "Verify Ledger Error:"
-	outputDirVerifyDesc   = "Location for verification result output directory. Default is the current directory."
+	blockStorePathDefault  = "/var/hyperledger/production"
+	outputDirIdDesc        = "Location for identified transactions json results output directory. Default is the current directory."
+	verifyErrorMessage     = "Verify Ledger Error:"
+	outputDirVerifyDesc    = "Location for verification result output directory. Default is the current directory."
+	revalidateErrorMessage = "Ledger Revalidate Error: "
+	configBlockPathDesc    = "Path to a config block file holding the channel config to validate the blocks with. The channel of the " +
+		"config block identifies the channel whose blocks are replayed."
+	firstBlockDesc = "First block whose transactions are revalidated. Earlier blocks are replayed with their recorded validation codes. " +
+		"Defaults to the first block after the genesis block."
+	lastBlockDesc        = "Last block whose transactions are revalidated. Defaults to the last block in the block store."
+	validationPluginDesc = "Validation plugin used by the chaincodes of the channel, as name=library where library is the path " +
+		"to the plugin shared object. May be repeated. The built-in 'vscc' plugin is always available."
+	outputDirRevalidateDesc = "Location for revalidation result output directory. Default is the current directory."
 )
 
 var (
@@ -51,6 +62,14 @@ var (
 	blockStorePathVerify = verifyApp.Arg("blockStorePath", blockStorePathDesc).Default(blockStorePathDefault).String()
 	outputDirVerify      = verifyApp.Flag("outputDir", outputDirVerifyDesc).Short('o').String()
 
+	revalidateApp            = app.Command("revalidate", "Revalidate the transactions of a block store with a given channel config.")
+	configBlockPath          = revalidateApp.Arg("configBlockPath", configBlockPathDesc).Required().String()
+	blockStorePathRevalidate = revalidateApp.Arg("blockStorePath", blockStorePathDesc).Default(blockStorePathDefault).String()
+	firstBlock               = revalidateApp.Flag("firstBlock", firstBlockDesc).Uint64()
+	lastBlock                = revalidateApp.Flag("lastBlock", lastBlockDesc).Uint64()
+	validationPlugins        = revalidateApp.Flag("validationPlugin", validationPluginDesc).Short('p').StringMap()
+	outputDirRevalidate      = revalidateApp.Flag("outputDir", outputDirRevalidateDesc).Short('o').String()
+
 	args = os.Args[1:]
 )
 
@@ -135,5 +154,35 @@ func main() {
 			fmt.Printf("\nSuccessfully executed verify tool. Some error(s) are found.\n")
 			os.Exit(1)
 		}
+
+	case revalidateApp.FullCommand():
+
+		// Determine result json file location
+		if *outputDirRevalidate == "" {
+			*outputDirRevalidate, err = os.Getwd()
+			if err != nil {
+				fmt.Printf("%s%s\n", revalidateErrorMessage, err)
+				os.Exit(1)
+			}
+		}
+
+		// Revalidate up to the last block in the block store unless told otherwise
+		if *lastBlock == 0 {
+			*lastBlock = math.MaxUint64
+		}
+
+		count, outputDirPath, err := revalidate.Revalidate(*configBlockPath, *blockStorePathRevalidate, *outputDirRevalidate, *firstBlock, *lastBlock, *validationPlugins)
+		if err != nil {
+			fmt.Printf("%s%s\n", revalidateErrorMessage, err)
+			os.Exit(1)
+		}
+
+		fmt.Print("\nSuccessfully revalidated transactions. ")
+		if count == 0 {
+			fmt.Printf("All validation codes matched the recorded ones. Results saved to %s.\n", outputDirPath)
+		} else {
+			fmt.Printf("Results saved to %s. Total differences found: %d\n", outputDirPath, count)
+			os.Exit(2)
+		}
 	}
 }
@@ -50,6 +50,7 @@ func executeForArgs(args []string) (output string, exit int, err error) {
 	join := channel.Command("join", "Join an Ordering Service Node (OSN) to a channel. If the channel does not yet exist, it will be created.")
 	joinChannelID := join.Flag("channelID", "Channel ID").Short('c').Required().String()
 	configBlockPath := join.Flag("config-block", "Path to the file containing an up-to-date config block for the channel").Short('b').Required().String()
+	fromCheckpoint := join.Flag("from-checkpoint", "Start the channel ledger at the config block, which is trusted as is and must be vetted beforehand, instead of replicating the channel from its genesis block").Default("false").Bool()
 
 	list := channel.Command("list", "List channel information for an Ordering Service Node (OSN). If the channelID flag is set, more detailed information will be provided for that channel.")
 	listChannelID := list.Flag("channelID", "Channel ID").Short('c').String()
@@ -57,6 +58,19 @@ func executeForArgs(args []string) (output string, exit int, err error) {
 	remove := channel.Command("remove", "Remove an Ordering Service Node (OSN) from a channel.")
 	removeChannelID := remove.Flag("channelID", "Channel ID").Short('c').Required().String()
 
+	consensus := app.Command("consensus", "Consensus actions")
+
+	status := consensus.Command("status", "Show the view, leader, decision sequence and pending request pool size of a channel.")
+	statusChannelID := status.Flag("channelID", "Channel ID").Short('c').Required().String()
+
+	viewChange := consensus.Command("viewchange", "Vote for a view change away from the current leader of a channel.")
+	viewChangeChannelID := viewChange.Flag("channelID", "Channel ID").Short('c').Required().String()
+
+	blacklist := consensus.Command("blacklist", "Vote for a view change whenever the given node is the leader of a channel, for a limited duration.")
+	blacklistChannelID := blacklist.Flag("channelID", "Channel ID").Short('c').Required().String()
+	blacklistID := blacklist.Flag("id", "Consenter ID of the leader to blacklist").Required().Uint64()
+	blacklistDuration := blacklist.Flag("duration", "How long the leader stays blacklisted").Default("10m").Duration()
+
 	command, err := app.Parse(args)
 	if err != nil {
 		return "", 1, err
@@ -111,6 +125,10 @@ func executeForArgs(args []string) (output string, exit int, err error) {
 
 	switch command {
 	case join.FullCommand():
+		if *fromCheckpoint {
+			resp, err = osnadmin.JoinFromCheckpoint(osnURL, marshaledConfigBlock, caCertPool, tlsClientCert)
+			break
+		}
 		resp, err = osnadmin.Join(osnURL, marshaledConfigBlock, caCertPool, tlsClientCert)
 	case list.FullCommand():
 		if *listChannelID != "" {
@@ -120,6 +138,12 @@ func executeForArgs(args []string) (output string, exit int, err error) {
 		resp, err = osnadmin.ListAllChannels(osnURL, caCertPool, tlsClientCert)
 	case remove.FullCommand():
 		resp, err = osnadmin.Remove(osnURL, *removeChannelID, caCertPool, tlsClientCert)
+	case status.FullCommand():
+		resp, err = osnadmin.ConsensusInfo(osnURL, *statusChannelID, caCertPool, tlsClientCert)
+	case viewChange.FullCommand():
+		resp, err = osnadmin.ForceViewChange(osnURL, *viewChangeChannelID, caCertPool, tlsClientCert)
+	case blacklist.FullCommand():
+		resp, err = osnadmin.BlacklistLeader(osnURL, *blacklistChannelID, *blacklistID, *blacklistDuration, caCertPool, tlsClientCert)
 	}
 	if err != nil {
 		return errorOutput(err), 1, nil
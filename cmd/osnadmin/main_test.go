@@ -18,6 +18,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
@@ -335,6 +336,108 @@ var _ = Describe("osnadmin", func() {
 		})
 	})
 
+	Describe("Consensus", func() {
+		BeforeEach(func() {
+			mockChannelManagement.ConsensusInfoReturns(types.ConsensusInfo{
+				Name:             channelID,
+				View:             4,
+				LeaderID:         2,
+				DecisionSequence: 42,
+				DecisionsInView:  3,
+				PendingRequests:  7,
+				Blacklist:        []uint64{1},
+				SuspectedLeaders: []uint64{},
+			}, nil)
+		})
+
+		It("uses the channel participation API to show the consensus status of a channel", func() {
+			args := []string{
+				"consensus",
+				"status",
+				"--orderer-address", ordererURL,
+				"--channelID", channelID,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			expectedOutput := types.ConsensusInfo{
+				Name:             channelID,
+				View:             4,
+				LeaderID:         2,
+				DecisionSequence: 42,
+				DecisionsInView:  3,
+				PendingRequests:  7,
+				Blacklist:        []uint64{1},
+				SuspectedLeaders: []uint64{},
+			}
+			checkStatusOutput(output, exit, err, 200, expectedOutput)
+		})
+
+		It("uses the channel participation API to vote for a view change", func() {
+			args := []string{
+				"consensus",
+				"viewchange",
+				"--orderer-address", ordererURL,
+				"--channelID", channelID,
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal("Status: 204\n"))
+			Expect(mockChannelManagement.ForceViewChangeCallCount()).To(Equal(1))
+		})
+
+		It("uses the channel participation API to blacklist a leader", func() {
+			args := []string{
+				"consensus",
+				"blacklist",
+				"--orderer-address", ordererURL,
+				"--channelID", channelID,
+				"--id", "2",
+				"--duration", "90s",
+				"--ca-file", ordererCACert,
+				"--client-cert", clientCert,
+				"--client-key", clientKey,
+			}
+			output, exit, err := executeForArgs(args)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(exit).To(Equal(0))
+			Expect(output).To(Equal("Status: 204\n"))
+			Expect(mockChannelManagement.BlacklistLeaderCallCount()).To(Equal(1))
+			ch, id, duration := mockChannelManagement.BlacklistLeaderArgsForCall(0)
+			Expect(ch).To(Equal(channelID))
+			Expect(id).To(Equal(uint64(2)))
+			Expect(duration).To(Equal(90 * time.Second))
+		})
+
+		Context("when the consensus type does not support operator controls", func() {
+			BeforeEach(func() {
+				mockChannelManagement.ForceViewChangeReturns(types.ErrConsensusControlNotSupported)
+			})
+
+			It("returns 501 not implemented", func() {
+				args := []string{
+					"consensus",
+					"viewchange",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				expectedOutput := types.ErrorResponse{
+					Error: "consensus type does not support operator controls",
+				}
+				checkStatusOutput(output, exit, err, 501, expectedOutput)
+			})
+		})
+	})
+
 	Describe("Join", func() {
 		var blockPath string
 
@@ -377,6 +480,44 @@ var _ = Describe("osnadmin", func() {
 			checkStatusOutput(output, exit, err, 201, expectedOutput)
 		})
 
+		Context("when joining from a checkpoint", func() {
+			BeforeEach(func() {
+				mockChannelManagement.JoinChannelFromCheckpointReturns(types.ChannelInfo{
+					Name:              "apple",
+					ConsensusRelation: "follower",
+					Status:            "active",
+					Height:            11,
+				}, nil)
+			})
+
+			It("uses the channel participation API to join a channel from the checkpoint", func() {
+				args := []string{
+					"channel",
+					"join",
+					"--orderer-address", ordererURL,
+					"--channelID", channelID,
+					"--config-block", blockPath,
+					"--from-checkpoint",
+					"--ca-file", ordererCACert,
+					"--client-cert", clientCert,
+					"--client-key", clientKey,
+				}
+				output, exit, err := executeForArgs(args)
+				expectedOutput := types.ChannelInfo{
+					Name:              "apple",
+					URL:               "/participation/v1/channels/apple",
+					ConsensusRelation: "follower",
+					Status:            "active",
+					Height:            11,
+				}
+				checkStatusOutput(output, exit, err, 201, expectedOutput)
+				Expect(mockChannelManagement.JoinChannelCallCount()).To(Equal(0))
+				Expect(mockChannelManagement.JoinChannelFromCheckpointCallCount()).To(Equal(1))
+				joinedChannelID, _ := mockChannelManagement.JoinChannelFromCheckpointArgsForCall(0)
+				Expect(joinedChannelID).To(Equal(channelID))
+			})
+		})
+
 		Context("when the block is empty", func() {
 			BeforeEach(func() {
 				blockPath = createBlockFile(tempDir, &cb.Block{})
@@ -3,12 +3,26 @@ package mocks
 
 import (
 	"sync"
+	"time"
 
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/orderer/common/types"
 )
 
 type ChannelManagement struct {
+	BlacklistLeaderStub        func(string, uint64, time.Duration) error
+	blacklistLeaderMutex       sync.RWMutex
+	blacklistLeaderArgsForCall []struct {
+		arg1 string
+		arg2 uint64
+		arg3 time.Duration
+	}
+	blacklistLeaderReturns struct {
+		result1 error
+	}
+	blacklistLeaderReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ChannelInfoStub        func(string) (types.ChannelInfo, error)
 	channelInfoMutex       sync.RWMutex
 	channelInfoArgsForCall []struct {
@@ -32,6 +46,30 @@ type ChannelManagement struct {
 	channelListReturnsOnCall map[int]struct {
 		result1 types.ChannelList
 	}
+	ConsensusInfoStub        func(string) (types.ConsensusInfo, error)
+	consensusInfoMutex       sync.RWMutex
+	consensusInfoArgsForCall []struct {
+		arg1 string
+	}
+	consensusInfoReturns struct {
+		result1 types.ConsensusInfo
+		result2 error
+	}
+	consensusInfoReturnsOnCall map[int]struct {
+		result1 types.ConsensusInfo
+		result2 error
+	}
+	ForceViewChangeStub        func(string) error
+	forceViewChangeMutex       sync.RWMutex
+	forceViewChangeArgsForCall []struct {
+		arg1 string
+	}
+	forceViewChangeReturns struct {
+		result1 error
+	}
+	forceViewChangeReturnsOnCall map[int]struct {
+		result1 error
+	}
 	JoinChannelStub        func(string, *common.Block, bool) (types.ChannelInfo, error)
 	joinChannelMutex       sync.RWMutex
 	joinChannelArgsForCall []struct {
@@ -47,6 +85,20 @@ type ChannelManagement struct {
 		result1 types.ChannelInfo
 		result2 error
 	}
+	JoinChannelFromCheckpointStub        func(string, *common.Block) (types.ChannelInfo, error)
+	joinChannelFromCheckpointMutex       sync.RWMutex
+	joinChannelFromCheckpointArgsForCall []struct {
+		arg1 string
+		arg2 *common.Block
+	}
+	joinChannelFromCheckpointReturns struct {
+		result1 types.ChannelInfo
+		result2 error
+	}
+	joinChannelFromCheckpointReturnsOnCall map[int]struct {
+		result1 types.ChannelInfo
+		result2 error
+	}
 	RemoveChannelStub        func(string) error
 	removeChannelMutex       sync.RWMutex
 	removeChannelArgsForCall []struct {
@@ -62,21 +114,85 @@ type ChannelManagement struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *ChannelManagement) BlacklistLeader(arg1 string, arg2 uint64, arg3 time.Duration) error {
+	fake.blacklistLeaderMutex.Lock()
+	ret, specificReturn := fake.blacklistLeaderReturnsOnCall[len(fake.blacklistLeaderArgsForCall)]
+	fake.blacklistLeaderArgsForCall = append(fake.blacklistLeaderArgsForCall, struct {
+		arg1 string
+		arg2 uint64
+		arg3 time.Duration
+	}{arg1, arg2, arg3})
+	stub := fake.BlacklistLeaderStub
+	fakeReturns := fake.blacklistLeaderReturns
+	fake.recordInvocation("BlacklistLeader", []interface{}{arg1, arg2, arg3})
+	fake.blacklistLeaderMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ChannelManagement) BlacklistLeaderCallCount() int {
+	fake.blacklistLeaderMutex.RLock()
+	defer fake.blacklistLeaderMutex.RUnlock()
+	return len(fake.blacklistLeaderArgsForCall)
+}
+
+func (fake *ChannelManagement) BlacklistLeaderCalls(stub func(string, uint64, time.Duration) error) {
+	fake.blacklistLeaderMutex.Lock()
+	defer fake.blacklistLeaderMutex.Unlock()
+	fake.BlacklistLeaderStub = stub
+}
+
+func (fake *ChannelManagement) BlacklistLeaderArgsForCall(i int) (string, uint64, time.Duration) {
+	fake.blacklistLeaderMutex.RLock()
+	defer fake.blacklistLeaderMutex.RUnlock()
+	argsForCall := fake.blacklistLeaderArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *ChannelManagement) BlacklistLeaderReturns(result1 error) {
+	fake.blacklistLeaderMutex.Lock()
+	defer fake.blacklistLeaderMutex.Unlock()
+	fake.BlacklistLeaderStub = nil
+	fake.blacklistLeaderReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ChannelManagement) BlacklistLeaderReturnsOnCall(i int, result1 error) {
+	fake.blacklistLeaderMutex.Lock()
+	defer fake.blacklistLeaderMutex.Unlock()
+	fake.BlacklistLeaderStub = nil
+	if fake.blacklistLeaderReturnsOnCall == nil {
+		fake.blacklistLeaderReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.blacklistLeaderReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *ChannelManagement) ChannelInfo(arg1 string) (types.ChannelInfo, error) {
 	fake.channelInfoMutex.Lock()
 	ret, specificReturn := fake.channelInfoReturnsOnCall[len(fake.channelInfoArgsForCall)]
 	fake.channelInfoArgsForCall = append(fake.channelInfoArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.ChannelInfoStub
+	fakeReturns := fake.channelInfoReturns
 	fake.recordInvocation("ChannelInfo", []interface{}{arg1})
 	fake.channelInfoMutex.Unlock()
-	if fake.ChannelInfoStub != nil {
-		return fake.ChannelInfoStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.channelInfoReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -130,15 +246,16 @@ func (fake *ChannelManagement) ChannelList() types.ChannelList {
 	ret, specificReturn := fake.channelListReturnsOnCall[len(fake.channelListArgsForCall)]
 	fake.channelListArgsForCall = append(fake.channelListArgsForCall, struct {
 	}{})
+	stub := fake.ChannelListStub
+	fakeReturns := fake.channelListReturns
 	fake.recordInvocation("ChannelList", []interface{}{})
 	fake.channelListMutex.Unlock()
-	if fake.ChannelListStub != nil {
-		return fake.ChannelListStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.channelListReturns
 	return fakeReturns.result1
 }
 
@@ -177,6 +294,131 @@ func (fake *ChannelManagement) ChannelListReturnsOnCall(i int, result1 types.Cha
 	}{result1}
 }
 
+func (fake *ChannelManagement) ConsensusInfo(arg1 string) (types.ConsensusInfo, error) {
+	fake.consensusInfoMutex.Lock()
+	ret, specificReturn := fake.consensusInfoReturnsOnCall[len(fake.consensusInfoArgsForCall)]
+	fake.consensusInfoArgsForCall = append(fake.consensusInfoArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ConsensusInfoStub
+	fakeReturns := fake.consensusInfoReturns
+	fake.recordInvocation("ConsensusInfo", []interface{}{arg1})
+	fake.consensusInfoMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChannelManagement) ConsensusInfoCallCount() int {
+	fake.consensusInfoMutex.RLock()
+	defer fake.consensusInfoMutex.RUnlock()
+	return len(fake.consensusInfoArgsForCall)
+}
+
+func (fake *ChannelManagement) ConsensusInfoCalls(stub func(string) (types.ConsensusInfo, error)) {
+	fake.consensusInfoMutex.Lock()
+	defer fake.consensusInfoMutex.Unlock()
+	fake.ConsensusInfoStub = stub
+}
+
+func (fake *ChannelManagement) ConsensusInfoArgsForCall(i int) string {
+	fake.consensusInfoMutex.RLock()
+	defer fake.consensusInfoMutex.RUnlock()
+	argsForCall := fake.consensusInfoArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ChannelManagement) ConsensusInfoReturns(result1 types.ConsensusInfo, result2 error) {
+	fake.consensusInfoMutex.Lock()
+	defer fake.consensusInfoMutex.Unlock()
+	fake.ConsensusInfoStub = nil
+	fake.consensusInfoReturns = struct {
+		result1 types.ConsensusInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChannelManagement) ConsensusInfoReturnsOnCall(i int, result1 types.ConsensusInfo, result2 error) {
+	fake.consensusInfoMutex.Lock()
+	defer fake.consensusInfoMutex.Unlock()
+	fake.ConsensusInfoStub = nil
+	if fake.consensusInfoReturnsOnCall == nil {
+		fake.consensusInfoReturnsOnCall = make(map[int]struct {
+			result1 types.ConsensusInfo
+			result2 error
+		})
+	}
+	fake.consensusInfoReturnsOnCall[i] = struct {
+		result1 types.ConsensusInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChannelManagement) ForceViewChange(arg1 string) error {
+	fake.forceViewChangeMutex.Lock()
+	ret, specificReturn := fake.forceViewChangeReturnsOnCall[len(fake.forceViewChangeArgsForCall)]
+	fake.forceViewChangeArgsForCall = append(fake.forceViewChangeArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ForceViewChangeStub
+	fakeReturns := fake.forceViewChangeReturns
+	fake.recordInvocation("ForceViewChange", []interface{}{arg1})
+	fake.forceViewChangeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ChannelManagement) ForceViewChangeCallCount() int {
+	fake.forceViewChangeMutex.RLock()
+	defer fake.forceViewChangeMutex.RUnlock()
+	return len(fake.forceViewChangeArgsForCall)
+}
+
+func (fake *ChannelManagement) ForceViewChangeCalls(stub func(string) error) {
+	fake.forceViewChangeMutex.Lock()
+	defer fake.forceViewChangeMutex.Unlock()
+	fake.ForceViewChangeStub = stub
+}
+
+func (fake *ChannelManagement) ForceViewChangeArgsForCall(i int) string {
+	fake.forceViewChangeMutex.RLock()
+	defer fake.forceViewChangeMutex.RUnlock()
+	argsForCall := fake.forceViewChangeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ChannelManagement) ForceViewChangeReturns(result1 error) {
+	fake.forceViewChangeMutex.Lock()
+	defer fake.forceViewChangeMutex.Unlock()
+	fake.ForceViewChangeStub = nil
+	fake.forceViewChangeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *ChannelManagement) ForceViewChangeReturnsOnCall(i int, result1 error) {
+	fake.forceViewChangeMutex.Lock()
+	defer fake.forceViewChangeMutex.Unlock()
+	fake.ForceViewChangeStub = nil
+	if fake.forceViewChangeReturnsOnCall == nil {
+		fake.forceViewChangeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.forceViewChangeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *ChannelManagement) JoinChannel(arg1 string, arg2 *common.Block, arg3 bool) (types.ChannelInfo, error) {
 	fake.joinChannelMutex.Lock()
 	ret, specificReturn := fake.joinChannelReturnsOnCall[len(fake.joinChannelArgsForCall)]
@@ -185,15 +427,16 @@ func (fake *ChannelManagement) JoinChannel(arg1 string, arg2 *common.Block, arg3
 		arg2 *common.Block
 		arg3 bool
 	}{arg1, arg2, arg3})
+	stub := fake.JoinChannelStub
+	fakeReturns := fake.joinChannelReturns
 	fake.recordInvocation("JoinChannel", []interface{}{arg1, arg2, arg3})
 	fake.joinChannelMutex.Unlock()
-	if fake.JoinChannelStub != nil {
-		return fake.JoinChannelStub(arg1, arg2, arg3)
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.joinChannelReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -242,21 +485,87 @@ func (fake *ChannelManagement) JoinChannelReturnsOnCall(i int, result1 types.Cha
 	}{result1, result2}
 }
 
+func (fake *ChannelManagement) JoinChannelFromCheckpoint(arg1 string, arg2 *common.Block) (types.ChannelInfo, error) {
+	fake.joinChannelFromCheckpointMutex.Lock()
+	ret, specificReturn := fake.joinChannelFromCheckpointReturnsOnCall[len(fake.joinChannelFromCheckpointArgsForCall)]
+	fake.joinChannelFromCheckpointArgsForCall = append(fake.joinChannelFromCheckpointArgsForCall, struct {
+		arg1 string
+		arg2 *common.Block
+	}{arg1, arg2})
+	stub := fake.JoinChannelFromCheckpointStub
+	fakeReturns := fake.joinChannelFromCheckpointReturns
+	fake.recordInvocation("JoinChannelFromCheckpoint", []interface{}{arg1, arg2})
+	fake.joinChannelFromCheckpointMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *ChannelManagement) JoinChannelFromCheckpointCallCount() int {
+	fake.joinChannelFromCheckpointMutex.RLock()
+	defer fake.joinChannelFromCheckpointMutex.RUnlock()
+	return len(fake.joinChannelFromCheckpointArgsForCall)
+}
+
+func (fake *ChannelManagement) JoinChannelFromCheckpointCalls(stub func(string, *common.Block) (types.ChannelInfo, error)) {
+	fake.joinChannelFromCheckpointMutex.Lock()
+	defer fake.joinChannelFromCheckpointMutex.Unlock()
+	fake.JoinChannelFromCheckpointStub = stub
+}
+
+func (fake *ChannelManagement) JoinChannelFromCheckpointArgsForCall(i int) (string, *common.Block) {
+	fake.joinChannelFromCheckpointMutex.RLock()
+	defer fake.joinChannelFromCheckpointMutex.RUnlock()
+	argsForCall := fake.joinChannelFromCheckpointArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ChannelManagement) JoinChannelFromCheckpointReturns(result1 types.ChannelInfo, result2 error) {
+	fake.joinChannelFromCheckpointMutex.Lock()
+	defer fake.joinChannelFromCheckpointMutex.Unlock()
+	fake.JoinChannelFromCheckpointStub = nil
+	fake.joinChannelFromCheckpointReturns = struct {
+		result1 types.ChannelInfo
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *ChannelManagement) JoinChannelFromCheckpointReturnsOnCall(i int, result1 types.ChannelInfo, result2 error) {
+	fake.joinChannelFromCheckpointMutex.Lock()
+	defer fake.joinChannelFromCheckpointMutex.Unlock()
+	fake.JoinChannelFromCheckpointStub = nil
+	if fake.joinChannelFromCheckpointReturnsOnCall == nil {
+		fake.joinChannelFromCheckpointReturnsOnCall = make(map[int]struct {
+			result1 types.ChannelInfo
+			result2 error
+		})
+	}
+	fake.joinChannelFromCheckpointReturnsOnCall[i] = struct {
+		result1 types.ChannelInfo
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *ChannelManagement) RemoveChannel(arg1 string) error {
 	fake.removeChannelMutex.Lock()
 	ret, specificReturn := fake.removeChannelReturnsOnCall[len(fake.removeChannelArgsForCall)]
 	fake.removeChannelArgsForCall = append(fake.removeChannelArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.RemoveChannelStub
+	fakeReturns := fake.removeChannelReturns
 	fake.recordInvocation("RemoveChannel", []interface{}{arg1})
 	fake.removeChannelMutex.Unlock()
-	if fake.RemoveChannelStub != nil {
-		return fake.RemoveChannelStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.removeChannelReturns
 	return fakeReturns.result1
 }
 
@@ -305,12 +614,20 @@ func (fake *ChannelManagement) RemoveChannelReturnsOnCall(i int, result1 error)
 func (fake *ChannelManagement) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.blacklistLeaderMutex.RLock()
+	defer fake.blacklistLeaderMutex.RUnlock()
 	fake.channelInfoMutex.RLock()
 	defer fake.channelInfoMutex.RUnlock()
 	fake.channelListMutex.RLock()
 	defer fake.channelListMutex.RUnlock()
+	fake.consensusInfoMutex.RLock()
+	defer fake.consensusInfoMutex.RUnlock()
+	fake.forceViewChangeMutex.RLock()
+	defer fake.forceViewChangeMutex.RUnlock()
 	fake.joinChannelMutex.RLock()
 	defer fake.joinChannelMutex.RUnlock()
+	fake.joinChannelFromCheckpointMutex.RLock()
+	defer fake.joinChannelFromCheckpointMutex.RUnlock()
 	fake.removeChannelMutex.RLock()
 	defer fake.removeChannelMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -8,6 +8,7 @@ package main
 
 import (
 	"testing"
+	"time"
 
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/orderer/common/types"
@@ -21,7 +22,11 @@ type channelManagement interface {
 	ChannelList() types.ChannelList
 	ChannelInfo(channelID string) (types.ChannelInfo, error)
 	JoinChannel(channelID string, configBlock *cb.Block, isAppChannel bool) (types.ChannelInfo, error)
+	JoinChannelFromCheckpoint(channelID string, checkpointBlock *cb.Block) (types.ChannelInfo, error)
 	RemoveChannel(channelID string) error
+	ConsensusInfo(channelID string) (types.ConsensusInfo, error)
+	ForceViewChange(channelID string) error
+	BlacklistLeader(channelID string, id uint64, duration time.Duration) error
 }
 
 func TestOsnadmin(t *testing.T) {
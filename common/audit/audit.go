@@ -0,0 +1,191 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package audit records administrative operations in a tamper-evident log.
+// Every record carries the hash of the record that precedes it, hence
+// removing, reordering or altering records breaks the hash chain.
+// The hashes are not keyed, so whoever can write the log can also recompute
+// the chain after altering it, or drop records from its end; tampering of that
+// kind is only evident against hashes of the log that were kept elsewhere.
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("audit")
+
+const (
+	// OutcomeSuccess is the outcome of an action that succeeded
+	OutcomeSuccess = "success"
+	// OutcomeFailure is the outcome of an action that failed
+	OutcomeFailure = "failure"
+	// OutcomeEndorsed is the outcome of an action that was endorsed as part of
+	// a transaction, and only takes effect if that transaction is committed
+	OutcomeEndorsed = "endorsed"
+)
+
+// Caller identifies the requester of an administrative action.
+type Caller struct {
+	// MSPID is the MSP of the identity of the caller, if it is known
+	MSPID string `json:"mspid,omitempty"`
+	// Subject is the subject of the certificate of the caller
+	Subject string `json:"subject,omitempty"`
+	// Address is the network address the request came from
+	Address string `json:"address,omitempty"`
+}
+
+// Record is an audit record of an administrative action.
+type Record struct {
+	Sequence   uint64            `json:"seq"`
+	Timestamp  time.Time         `json:"timestamp"`
+	Component  string            `json:"component"`
+	Action     string            `json:"action"`
+	Caller     Caller            `json:"caller"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+	Outcome    string            `json:"outcome"`
+	Error      string            `json:"error,omitempty"`
+	PrevHash   string            `json:"prev_hash"`
+	Hash       string            `json:"hash"`
+}
+
+// computeHash returns the hex encoded hash of the record,
+// which covers all its fields but the hash itself.
+func (r Record) computeHash() string {
+	r.Hash = ""
+	// Marshaling cannot fail, as the record has no types that JSON cannot encode,
+	// and map keys are sorted, hence the encoding is deterministic.
+	b, _ := json.Marshal(r)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// Logger writes audit records to a sink, one JSON record per line.
+// A nil Logger discards all records.
+type Logger struct {
+	mutex    sync.Mutex
+	sink     io.Writer
+	sequence uint64
+	lastHash string
+	now      func() time.Time
+}
+
+// NewLogger returns a Logger that writes to the given sink. The records are chained
+// to the given last record, which is nil if the sink has no records yet.
+func NewLogger(sink io.Writer, last *Record) *Logger {
+	l := &Logger{
+		sink: sink,
+		now:  time.Now,
+	}
+	if last != nil {
+		l.sequence = last.Sequence
+		l.lastHash = last.Hash
+	}
+	return l
+}
+
+// Log records the given action of the given component, on behalf of the given caller.
+// The outcome of the action is a failure if err is not nil.
+func (l *Logger) Log(component, action string, caller Caller, parameters map[string]string, err error) {
+	l.log(component, action, caller, parameters, OutcomeSuccess, err)
+}
+
+// LogEndorsed records the given action of the given component, on behalf of the given
+// caller, which was simulated as part of a transaction. The outcome of the action is
+// endorsed, as it only takes effect once the transaction is committed, or a failure if
+// err is not nil.
+func (l *Logger) LogEndorsed(component, action string, caller Caller, parameters map[string]string, err error) {
+	l.log(component, action, caller, parameters, OutcomeEndorsed, err)
+}
+
+func (l *Logger) log(component, action string, caller Caller, parameters map[string]string, outcome string, err error) {
+	if l == nil {
+		return
+	}
+
+	r := Record{
+		Component:  component,
+		Action:     action,
+		Caller:     caller,
+		Parameters: parameters,
+		Outcome:    outcome,
+	}
+	if err != nil {
+		r.Outcome = OutcomeFailure
+		r.Error = err.Error()
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	r.Sequence = l.sequence + 1
+	r.Timestamp = l.now().UTC()
+	r.PrevHash = l.lastHash
+	r.Hash = r.computeHash()
+
+	// A record that the sink reports as entirely written might be persisted
+	// even if the sink fails afterwards, hence the chain advances past it.
+	b, _ := json.Marshal(r)
+	b = append(b, '\n')
+	if n, err := l.sink.Write(b); err != nil {
+		logger.Errorf("Failed writing audit record %d of action %s of %s: %v", r.Sequence, action, component, err)
+		if n < len(b) {
+			return
+		}
+	}
+	l.sequence = r.Sequence
+	l.lastHash = r.Hash
+}
+
+// Close closes the sink of the Logger if it can be closed.
+func (l *Logger) Close() error {
+	if l == nil {
+		return nil
+	}
+	if closer, ok := l.sink.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Verify reads the records of the given reader and verifies that they form a hash chain
+// that starts after the record with the given hash, which is empty for the first record.
+// It returns the last record read, or nil if there were no records.
+func Verify(r io.Reader, prevHash string) (*Record, error) {
+	var last *Record
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		record := &Record{}
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return nil, errors.Wrapf(err, "line %d is not an audit record", line)
+		}
+		if record.PrevHash != prevHash {
+			return nil, errors.Errorf("record %d at line %d is chained to %s instead of %s", record.Sequence, line, record.PrevHash, prevHash)
+		}
+		if last != nil && record.Sequence != last.Sequence+1 {
+			return nil, errors.Errorf("record %d at line %d follows record %d", record.Sequence, line, last.Sequence)
+		}
+		if hash := record.computeHash(); record.Hash != hash {
+			return nil, errors.Errorf("record %d at line %d has hash %s but its content hashes to %s", record.Sequence, line, record.Hash, hash)
+		}
+		prevHash = record.Hash
+		last = record
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed reading audit records")
+	}
+	return last, nil
+}
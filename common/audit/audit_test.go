@@ -0,0 +1,346 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit_test
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/audit"
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+	buff := &bytes.Buffer{}
+	l := audit.NewLogger(buff, nil)
+
+	caller := audit.Caller{MSPID: "Org1MSP", Subject: "CN=admin", Address: "127.0.0.1:1234"}
+	l.Log("cscc", "JoinChain", caller, map[string]string{"channel": "mychannel"}, nil)
+	l.Log("cscc", "JoinChain", caller, map[string]string{"channel": "mychannel"}, errors.New("already exists"))
+
+	lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var first, second audit.Record
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &second))
+
+	require.Equal(t, uint64(1), first.Sequence)
+	require.Equal(t, "cscc", first.Component)
+	require.Equal(t, "JoinChain", first.Action)
+	require.Equal(t, caller, first.Caller)
+	require.Equal(t, map[string]string{"channel": "mychannel"}, first.Parameters)
+	require.Equal(t, audit.OutcomeSuccess, first.Outcome)
+	require.Empty(t, first.Error)
+	require.Empty(t, first.PrevHash)
+	require.NotEmpty(t, first.Hash)
+
+	require.Equal(t, uint64(2), second.Sequence)
+	require.Equal(t, audit.OutcomeFailure, second.Outcome)
+	require.Equal(t, "already exists", second.Error)
+	require.Equal(t, first.Hash, second.PrevHash)
+
+	last, err := audit.Verify(strings.NewReader(buff.String()), "")
+	require.NoError(t, err)
+	require.Equal(t, second.Hash, last.Hash)
+
+	t.Run("continues the chain of the last record", func(t *testing.T) {
+		buff2 := &bytes.Buffer{}
+		l := audit.NewLogger(buff2, last)
+		l.Log("cscc", "JoinChain", caller, nil, nil)
+
+		var third audit.Record
+		require.NoError(t, json.Unmarshal(buff2.Bytes(), &third))
+		require.Equal(t, uint64(3), third.Sequence)
+		require.Equal(t, second.Hash, third.PrevHash)
+
+		_, err := audit.Verify(strings.NewReader(buff.String()+buff2.String()), "")
+		require.NoError(t, err)
+	})
+
+	t.Run("endorsed", func(t *testing.T) {
+		buff := &bytes.Buffer{}
+		l := audit.NewLogger(buff, nil)
+		l.LogEndorsed("lifecycle", "CommitChaincodeDefinition", caller, nil, nil)
+		l.LogEndorsed("lifecycle", "CommitChaincodeDefinition", caller, nil, errors.New("not approved"))
+
+		lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+		require.Len(t, lines, 2)
+		var endorsed, failed audit.Record
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &endorsed))
+		require.NoError(t, json.Unmarshal([]byte(lines[1]), &failed))
+		require.Equal(t, audit.OutcomeEndorsed, endorsed.Outcome)
+		require.Equal(t, audit.OutcomeFailure, failed.Outcome)
+		require.Equal(t, "not approved", failed.Error)
+	})
+
+	t.Run("nil logger", func(t *testing.T) {
+		var l *audit.Logger
+		l.Log("cscc", "JoinChain", caller, nil, nil)
+		l.LogEndorsed("lifecycle", "CommitChaincodeDefinition", caller, nil, nil)
+		require.NoError(t, l.Close())
+	})
+}
+
+func TestVerify(t *testing.T) {
+	buff := &bytes.Buffer{}
+	l := audit.NewLogger(buff, nil)
+	for _, channel := range []string{"a", "b", "c"} {
+		l.Log("channelparticipation", "Join", audit.Caller{}, map[string]string{"channel": channel}, nil)
+	}
+	lines := strings.SplitAfter(buff.String(), "\n")[:3]
+
+	t.Run("empty", func(t *testing.T) {
+		last, err := audit.Verify(strings.NewReader(""), "")
+		require.NoError(t, err)
+		require.Nil(t, last)
+	})
+
+	t.Run("altered record", func(t *testing.T) {
+		altered := strings.Replace(buff.String(), `"channel":"b"`, `"channel":"d"`, 1)
+		_, err := audit.Verify(strings.NewReader(altered), "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "record 2 at line 2 has hash")
+	})
+
+	t.Run("removed record", func(t *testing.T) {
+		_, err := audit.Verify(strings.NewReader(lines[0]+lines[2]), "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "record 3 at line 2 is chained to")
+	})
+
+	t.Run("reordered records", func(t *testing.T) {
+		_, err := audit.Verify(strings.NewReader(lines[1]+lines[0]), "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "record 2 at line 1 is chained to")
+	})
+
+	t.Run("not a record", func(t *testing.T) {
+		_, err := audit.Verify(strings.NewReader(lines[0]+"garbage\n"), "")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "line 2 is not an audit record")
+	})
+}
+
+func TestFileLogger(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		l, err := audit.NewFileLogger(audit.Config{File: filepath.Join(t.TempDir(), "audit.log")})
+		require.NoError(t, err)
+		require.Nil(t, l)
+	})
+
+	t.Run("no file", func(t *testing.T) {
+		_, err := audit.NewFileLogger(audit.Config{Enabled: true})
+		require.EqualError(t, err, "no audit log file specified")
+	})
+
+	t.Run("resumes the chain", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit", "audit.log")
+		conf := audit.Config{Enabled: true, File: path}
+
+		l, err := audit.NewFileLogger(conf)
+		require.NoError(t, err)
+		l.Log("lifecycle", "InstallChaincode", audit.Caller{}, nil, nil)
+		l.Log("lifecycle", "ApproveChaincodeDefinitionForMyOrg", audit.Caller{}, nil, nil)
+		require.NoError(t, l.Close())
+
+		l, err = audit.NewFileLogger(conf)
+		require.NoError(t, err)
+		l.Log("lifecycle", "InstallChaincode", audit.Caller{}, nil, nil)
+		require.NoError(t, l.Close())
+
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+		last, err := audit.Verify(f, "")
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), last.Sequence)
+	})
+
+	t.Run("torn last record", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		conf := audit.Config{Enabled: true, File: path}
+
+		l, err := audit.NewFileLogger(conf)
+		require.NoError(t, err)
+		l.Log("lifecycle", "InstallChaincode", audit.Caller{}, nil, nil)
+		l.Log("lifecycle", "InstallChaincode", audit.Caller{}, nil, nil)
+		require.NoError(t, l.Close())
+
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+		require.NoError(t, err)
+		_, err = f.WriteString(`{"seq":3,"timestamp":"2024-`)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		l, err = audit.NewFileLogger(conf)
+		require.NoError(t, err)
+		l.Log("lifecycle", "ApproveChaincodeDefinitionForMyOrg", audit.Caller{}, nil, nil)
+		require.NoError(t, l.Close())
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.NotContains(t, string(data), `"timestamp":"2024-`)
+		last, err := audit.Verify(bytes.NewReader(data), "")
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), last.Sequence)
+		require.Equal(t, "ApproveChaincodeDefinitionForMyOrg", last.Action)
+	})
+
+	t.Run("torn only record", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		require.NoError(t, os.WriteFile(path, []byte(`{"seq":1,`), 0o600))
+
+		l, err := audit.NewFileLogger(audit.Config{Enabled: true, File: path})
+		require.NoError(t, err)
+		l.Log("lifecycle", "InstallChaincode", audit.Caller{}, nil, nil)
+		require.NoError(t, l.Close())
+
+		f, err := os.Open(path)
+		require.NoError(t, err)
+		defer f.Close()
+		last, err := audit.Verify(f, "")
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), last.Sequence)
+	})
+
+	t.Run("altered complete record", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		conf := audit.Config{Enabled: true, File: path}
+
+		l, err := audit.NewFileLogger(conf)
+		require.NoError(t, err)
+		l.Log("lifecycle", "InstallChaincode", audit.Caller{}, nil, nil)
+		require.NoError(t, l.Close())
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		data = bytes.Replace(data, []byte("InstallChaincode"), []byte("CommitChaincode"), 1)
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+
+		_, err = audit.NewFileLogger(conf)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "audit log "+path+" is corrupted")
+	})
+
+	t.Run("corrupted file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		require.NoError(t, os.WriteFile(path, []byte("garbage\n"), 0o600))
+
+		_, err := audit.NewFileLogger(audit.Config{Enabled: true, File: path})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "audit log "+path+" is corrupted")
+	})
+}
+
+func TestFileSinkRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	sink, err := audit.NewFileSink(path, 1, 2)
+	require.NoError(t, err)
+	l := audit.NewLogger(sink, nil)
+	// Every record makes the file grow beyond the maximum size, hence every record is written to a new file
+	for i := 0; i < 4; i++ {
+		l.Log("snapshotgrpc", "Generate", audit.Caller{}, nil, nil)
+	}
+	require.NoError(t, l.Close())
+
+	require.FileExists(t, path)
+	require.FileExists(t, path+".1")
+	require.FileExists(t, path+".2")
+	require.NoFileExists(t, path+".3")
+
+	// The chain continues across the rotated files
+	var prevHash string
+	for _, p := range []string{path + ".2", path + ".1", path} {
+		data, err := os.ReadFile(p)
+		require.NoError(t, err)
+		var first audit.Record
+		require.NoError(t, json.Unmarshal(data, &first))
+		if prevHash != "" {
+			require.Equal(t, prevHash, first.PrevHash)
+		}
+		last, err := audit.Verify(bytes.NewReader(data), first.PrevHash)
+		require.NoError(t, err)
+		prevHash = last.Hash
+	}
+
+	// A logger for the rotated file continues the chain
+	require.NoError(t, os.Truncate(path, 0))
+	l, err = audit.NewFileLogger(audit.Config{Enabled: true, File: path})
+	require.NoError(t, err)
+	l.Log("snapshotgrpc", "Generate", audit.Caller{}, nil, nil)
+	require.NoError(t, l.Close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var record audit.Record
+	require.NoError(t, json.Unmarshal(data, &record))
+	require.Equal(t, uint64(4), record.Sequence)
+}
+
+func TestCaller(t *testing.T) {
+	ca, err := tlsgen.NewCA()
+	require.NoError(t, err)
+	kp, err := ca.NewClientCertKeyPair()
+	require.NoError(t, err)
+	block, _ := pem.Decode(kp.Cert)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	t.Run("serialized identity", func(t *testing.T) {
+		caller := audit.CallerFromSerializedIdentity(protoutil.MarshalOrPanic(&msp.SerializedIdentity{
+			Mspid:   "Org1MSP",
+			IdBytes: kp.Cert,
+		}))
+		require.Equal(t, audit.Caller{MSPID: "Org1MSP", Subject: cert.Subject.String()}, caller)
+
+		caller = audit.CallerFromSerializedIdentity(protoutil.MarshalOrPanic(&msp.SerializedIdentity{
+			Mspid:   "Org1MSP",
+			IdBytes: []byte("not a certificate"),
+		}))
+		require.Equal(t, audit.Caller{MSPID: "Org1MSP"}, caller)
+
+		require.Equal(t, audit.Caller{}, audit.CallerFromSerializedIdentity([]byte{1, 2, 3}))
+	})
+
+	t.Run("signed proposal", func(t *testing.T) {
+		creator := protoutil.MarshalOrPanic(&msp.SerializedIdentity{
+			Mspid:   "Org1MSP",
+			IdBytes: kp.Cert,
+		})
+		prop, _, err := protoutil.CreateChaincodeProposal(common.HeaderType_ENDORSER_TRANSACTION, "mychannel", &peer.ChaincodeInvocationSpec{ChaincodeSpec: &peer.ChaincodeSpec{ChaincodeId: &peer.ChaincodeID{Name: "cscc"}}}, creator)
+		require.NoError(t, err)
+		sp := &peer.SignedProposal{ProposalBytes: protoutil.MarshalOrPanic(prop)}
+		require.Equal(t, audit.Caller{MSPID: "Org1MSP", Subject: cert.Subject.String()}, audit.CallerFromSignedProposal(sp))
+
+		require.Equal(t, audit.Caller{}, audit.CallerFromSignedProposal(&peer.SignedProposal{ProposalBytes: []byte{1, 2, 3}}))
+	})
+
+	t.Run("HTTP request", func(t *testing.T) {
+		req := httptest.NewRequest("GET", "/logspec", nil)
+		req.RemoteAddr = "10.0.0.1:4321"
+		require.Equal(t, audit.Caller{Address: "10.0.0.1:4321"}, audit.CallerFromHTTPRequest(req))
+
+		req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+		require.Equal(t, audit.Caller{Address: "10.0.0.1:4321", Subject: cert.Subject.String()}, audit.CallerFromHTTPRequest(req))
+	})
+}
@@ -0,0 +1,66 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/protoutil"
+)
+
+// CallerFromSerializedIdentity returns the Caller of the given serialized identity.
+// The subject is left empty if the identity isn't made of a certificate.
+func CallerFromSerializedIdentity(serializedIdentity []byte) Caller {
+	sID := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(serializedIdentity, sID); err != nil {
+		return Caller{}
+	}
+
+	caller := Caller{MSPID: sID.Mspid}
+	block, _ := pem.Decode(sID.IdBytes)
+	if block == nil {
+		return caller
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return caller
+	}
+	caller.Subject = cert.Subject.String()
+	return caller
+}
+
+// CallerFromSignedProposal returns the Caller of the creator of the given signed proposal.
+func CallerFromSignedProposal(sp *peer.SignedProposal) Caller {
+	prop, err := protoutil.UnmarshalProposal(sp.GetProposalBytes())
+	if err != nil {
+		return Caller{}
+	}
+	hdr, err := protoutil.UnmarshalHeader(prop.Header)
+	if err != nil {
+		return Caller{}
+	}
+	shdr, err := protoutil.UnmarshalSignatureHeader(hdr.SignatureHeader)
+	if err != nil {
+		return Caller{}
+	}
+	return CallerFromSerializedIdentity(shdr.Creator)
+}
+
+// CallerFromHTTPRequest returns the Caller of the given HTTP request,
+// made of the TLS client certificate and the remote address of the request.
+func CallerFromHTTPRequest(req *http.Request) Caller {
+	caller := Caller{Address: req.RemoteAddr}
+	if req.TLS != nil && len(req.TLS.PeerCertificates) > 0 {
+		caller.Subject = req.TLS.PeerCertificates[0].Subject.String()
+	}
+	return caller
+}
@@ -0,0 +1,243 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Config is the configuration of an audit log that is written to a file.
+type Config struct {
+	// Enabled enables the audit log
+	Enabled bool
+	// File is the path of the file the records are written to
+	File string
+	// MaxSize is the size in megabytes the file may reach before it is rotated,
+	// or zero if the file is never rotated
+	MaxSize int
+	// MaxBackups is the number of rotated files that are kept,
+	// or zero if all of them are kept
+	MaxBackups int
+}
+
+// NewFileLogger returns a Logger that writes to the file of the given config, and chains
+// its records to the last record in that file or in its latest rotated file.
+// It returns a nil Logger if the audit log is not enabled.
+func NewFileLogger(conf Config) (*Logger, error) {
+	if !conf.Enabled {
+		return nil, nil
+	}
+	last, err := lastRecord(conf.File)
+	if err != nil {
+		return nil, err
+	}
+	sink, err := NewFileSink(conf.File, int64(conf.MaxSize)*1024*1024, conf.MaxBackups)
+	if err != nil {
+		return nil, err
+	}
+	return NewLogger(sink, last), nil
+}
+
+// lastRecord returns the last record in the given file, or in its latest rotated file
+// if it is empty. It returns nil if there are no records. A trailing line that is not
+// terminated by a newline is the remainder of a record whose write was interrupted,
+// hence it is truncated so that the records that follow start on a line of their own.
+func lastRecord(path string) (*Record, error) {
+	for _, p := range []string{path, backupName(path, 1)} {
+		data, err := os.ReadFile(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed reading audit log %s", p)
+		}
+		if i := bytes.LastIndexByte(data, '\n'); i < len(data)-1 {
+			logger.Warningf("Truncating the incomplete last record of audit log %s: %s", p, data[i+1:])
+			if err := os.Truncate(p, int64(i+1)); err != nil {
+				return nil, errors.Wrapf(err, "failed truncating audit log %s", p)
+			}
+			data = data[:i+1]
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		// The records that precede the first record of the file might have been
+		// rotated away, hence the chain is verified from the first record on.
+		var first Record
+		firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+		if err := json.Unmarshal(firstLine, &first); err != nil {
+			return nil, errors.Wrapf(err, "audit log %s is corrupted", p)
+		}
+		last, err := Verify(bytes.NewReader(data), first.PrevHash)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "audit log %s is corrupted", p)
+		}
+		return last, nil
+	}
+	return nil, nil
+}
+
+// auditFile is the part of an *os.File that the FileSink writes to.
+type auditFile interface {
+	Write(p []byte) (int, error)
+	Sync() error
+	Truncate(size int64) error
+	Close() error
+}
+
+// FileSink writes to a file, and rotates it once it grows beyond a maximum size.
+// The rotated files are named after the file with a suffix of .1, .2 and so on,
+// where .1 is the latest.
+type FileSink struct {
+	mutex      sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	file       auditFile
+	size       int64
+	// err is set once a failed write cannot be undone. The file then no longer
+	// ends with the record the Logger chains to, hence all further writes are
+	// refused until the sink is reopened, which resyncs the Logger with the file.
+	err error
+}
+
+// NewFileSink opens the given file for appending, and creates its directory if needed.
+// The file is rotated once it grows beyond maxSize bytes, unless maxSize is zero, and at most
+// maxBackups rotated files are kept, unless maxBackups is zero.
+func NewFileSink(path string, maxSize int64, maxBackups int) (*FileSink, error) {
+	if path == "" {
+		return nil, errors.New("no audit log file specified")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return nil, errors.Wrapf(err, "failed creating directory of audit log %s", path)
+	}
+	fs := &FileSink{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+	if err := fs.open(); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+func (fs *FileSink) open() error {
+	f, err := os.OpenFile(fs.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return errors.Wrapf(err, "failed opening audit log %s", fs.path)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return errors.Wrapf(err, "failed inspecting audit log %s", fs.path)
+	}
+	fs.file = f
+	fs.size = info.Size()
+	return nil
+}
+
+// Write writes the given bytes to the file, after rotating it if they would
+// make it grow beyond the maximum size. The bytes are synced to the disk. If
+// the write or the sync fails, the file is truncated back to its previous size
+// and no bytes are reported as written.
+func (fs *FileSink) Write(p []byte) (int, error) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	if fs.file == nil {
+		return 0, errors.Errorf("audit log %s is closed", fs.path)
+	}
+	if fs.err != nil {
+		return 0, fs.err
+	}
+
+	if fs.maxSize > 0 && fs.size > 0 && fs.size+int64(len(p)) > fs.maxSize {
+		if err := fs.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := fs.file.Write(p)
+	if err != nil {
+		err = errors.Wrapf(err, "failed writing to audit log %s", fs.path)
+	} else if err = fs.file.Sync(); err != nil {
+		err = errors.Wrapf(err, "failed syncing audit log %s", fs.path)
+	}
+	if err != nil {
+		if terr := fs.file.Truncate(fs.size); terr != nil {
+			fs.size += int64(n)
+			fs.err = errors.Wrapf(terr, "audit log %s could not be truncated after a failed write, refusing further writes until it is reopened", fs.path)
+			logger.Errorf("%s", fs.err)
+			return n, err
+		}
+		return 0, err
+	}
+	fs.size += int64(n)
+	return n, nil
+}
+
+// rotate renames the file and its rotated files one suffix up,
+// removes the rotated files beyond the maximum, and opens a new file.
+func (fs *FileSink) rotate() error {
+	if err := fs.file.Close(); err != nil {
+		return errors.Wrapf(err, "failed closing audit log %s", fs.path)
+	}
+	fs.file = nil
+
+	// Find the oldest rotated file
+	oldest := 0
+	for {
+		if _, err := os.Stat(backupName(fs.path, oldest+1)); err != nil {
+			break
+		}
+		oldest++
+	}
+
+	for i := oldest; i >= 1; i-- {
+		if fs.maxBackups > 0 && i >= fs.maxBackups {
+			if err := os.Remove(backupName(fs.path, i)); err != nil {
+				return errors.Wrapf(err, "failed removing rotated audit log %s", backupName(fs.path, i))
+			}
+			continue
+		}
+		if err := os.Rename(backupName(fs.path, i), backupName(fs.path, i+1)); err != nil {
+			return errors.Wrapf(err, "failed rotating audit log %s", backupName(fs.path, i))
+		}
+	}
+
+	if err := os.Rename(fs.path, backupName(fs.path, 1)); err != nil {
+		return errors.Wrapf(err, "failed rotating audit log %s", fs.path)
+	}
+
+	return fs.open()
+}
+
+// Close closes the file.
+func (fs *FileSink) Close() error {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	if fs.file == nil {
+		return nil
+	}
+	err := fs.file.Close()
+	fs.file = nil
+	return err
+}
+
+func backupName(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package audit
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+// failingFile fails the next sync, and the truncation that follows it if
+// failTruncate is set.
+type failingFile struct {
+	*os.File
+	failSync     bool
+	failTruncate bool
+}
+
+func (f *failingFile) Sync() error {
+	if f.failSync {
+		f.failSync = false
+		return errors.New("disk on fire")
+	}
+	return f.File.Sync()
+}
+
+func (f *failingFile) Truncate(size int64) error {
+	if f.failTruncate {
+		return errors.New("disk still on fire")
+	}
+	return f.File.Truncate(size)
+}
+
+func TestFileSinkFailedWrite(t *testing.T) {
+	t.Run("undone", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		sink, err := NewFileSink(path, 0, 0)
+		require.NoError(t, err)
+		file := &failingFile{File: sink.file.(*os.File)}
+		sink.file = file
+		l := NewLogger(sink, nil)
+
+		l.Log("lifecycle", "InstallChaincode", Caller{}, nil, nil)
+		file.failSync = true
+		l.Log("lifecycle", "ApproveChaincodeDefinitionForMyOrg", Caller{}, nil, nil)
+		l.Log("lifecycle", "CommitChaincodeDefinition", Caller{}, nil, nil)
+		require.NoError(t, l.Close())
+
+		// the record whose sync failed is gone, and the chain skips it
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.NotContains(t, string(data), "ApproveChaincodeDefinitionForMyOrg")
+		last, err := Verify(bytes.NewReader(data), "")
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), last.Sequence)
+		require.Equal(t, "CommitChaincodeDefinition", last.Action)
+	})
+
+	t.Run("not undone", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		sink, err := NewFileSink(path, 0, 0)
+		require.NoError(t, err)
+		file := &failingFile{File: sink.file.(*os.File), failSync: true, failTruncate: true}
+		sink.file = file
+		l := NewLogger(sink, nil)
+
+		// the record stays in the file, hence the chain advances past it
+		l.Log("lifecycle", "InstallChaincode", Caller{}, nil, nil)
+		require.Equal(t, uint64(1), l.sequence)
+
+		// but further writes are refused
+		n, err := sink.Write([]byte("record\n"))
+		require.Equal(t, 0, n)
+		require.EqualError(t, err, "audit log "+path+" could not be truncated after a failed write, refusing further writes until it is reopened: disk still on fire")
+		require.NoError(t, l.Close())
+
+		// until the log is reopened, which resumes the chain from the file
+		l, err = NewFileLogger(Config{Enabled: true, File: path})
+		require.NoError(t, err)
+		l.Log("lifecycle", "ApproveChaincodeDefinitionForMyOrg", Caller{}, nil, nil)
+		require.NoError(t, l.Close())
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		last, err := Verify(bytes.NewReader(data), "")
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), last.Sequence)
+	})
+}
@@ -36,6 +36,10 @@ const (
 
 	// ApplicationResourcesTreeExperimental is the capabilities string for private data using the experimental feature of collections/sideDB.
 	ApplicationResourcesTreeExperimental = "V1_1_RESOURCETREE_EXPERIMENTAL"
+
+	// ApplicationCrossChannelReads is the capabilities string for recording the reads of chaincode-to-chaincode
+	// calls to other channels in the read set of the transaction, and validating them on commit.
+	ApplicationCrossChannelReads = "V3_0_CROSS_CHANNEL_READS"
 )
 
 // ApplicationProvider provides capabilities information for application level config.
@@ -48,6 +52,7 @@ type ApplicationProvider struct {
 	v20                    bool
 	v25                    bool
 	v11PvtDataExperimental bool
+	crossChannelReads      bool
 }
 
 // NewApplicationProvider creates a application capabilities provider.
@@ -61,6 +66,7 @@ func NewApplicationProvider(capabilities map[string]*cb.Capability) *Application
 	_, ap.v20 = capabilities[ApplicationV2_0]
 	_, ap.v25 = capabilities[ApplicationV2_5]
 	_, ap.v11PvtDataExperimental = capabilities[ApplicationPvtDataExperimental]
+	_, ap.crossChannelReads = capabilities[ApplicationCrossChannelReads]
 	return ap
 }
 
@@ -149,6 +155,12 @@ func (ap *ApplicationProvider) PurgePvtData() bool {
 	return ap.v25
 }
 
+// CrossChannelReads returns true if the reads of chaincode-to-chaincode calls to other
+// channels are recorded in the read set of the transaction and validated on commit.
+func (ap *ApplicationProvider) CrossChannelReads() bool {
+	return ap.crossChannelReads
+}
+
 // HasCapability returns true if the capability is supported by this binary.
 func (ap *ApplicationProvider) HasCapability(capability string) bool {
 	switch capability {
@@ -169,6 +181,8 @@ func (ap *ApplicationProvider) HasCapability(capability string) bool {
 		return true
 	case ApplicationResourcesTreeExperimental:
 		return true
+	case ApplicationCrossChannelReads:
+		return true
 	default:
 		return false
 	}
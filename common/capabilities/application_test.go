@@ -115,6 +115,20 @@ func TestApplicationPvtDataExperimental(t *testing.T) {
 	require.True(t, ap.PrivateChannelData())
 }
 
+func TestApplicationCrossChannelReads(t *testing.T) {
+	ap := NewApplicationProvider(map[string]*cb.Capability{
+		ApplicationV2_5: {},
+	})
+	require.False(t, ap.CrossChannelReads())
+
+	ap = NewApplicationProvider(map[string]*cb.Capability{
+		ApplicationV2_5:              {},
+		ApplicationCrossChannelReads: {},
+	})
+	require.NoError(t, ap.Supported())
+	require.True(t, ap.CrossChannelReads())
+}
+
 func TestHasCapability(t *testing.T) {
 	ap := NewApplicationProvider(map[string]*cb.Capability{})
 	require.True(t, ap.HasCapability(ApplicationV1_1))
@@ -124,5 +138,6 @@ func TestHasCapability(t *testing.T) {
 	require.True(t, ap.HasCapability(ApplicationV2_5))
 	require.True(t, ap.HasCapability(ApplicationPvtDataExperimental))
 	require.True(t, ap.HasCapability(ApplicationResourcesTreeExperimental))
+	require.True(t, ap.HasCapability(ApplicationCrossChannelReads))
 	require.False(t, ap.HasCapability("default"))
 }
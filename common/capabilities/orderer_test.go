@@ -58,6 +58,30 @@ func TestOrdererV20(t *testing.T) {
 	require.True(t, op.Resubmission())
 	require.True(t, op.ExpirationCheck())
 	require.True(t, op.ConsensusTypeMigration())
+	require.False(t, op.TxReordering())
+}
+
+func TestOrdererV30(t *testing.T) {
+	op := NewOrdererProvider(map[string]*cb.Capability{
+		OrdererV3_0: {},
+	})
+	require.NoError(t, op.Supported())
+	require.True(t, op.PredictableChannelTemplate())
+	require.True(t, op.UseChannelCreationPolicyAsAdmins())
+	require.True(t, op.Resubmission())
+	require.True(t, op.ExpirationCheck())
+	require.True(t, op.ConsensusTypeMigration())
+	require.False(t, op.TxReordering())
+}
+
+func TestOrdererTxReordering(t *testing.T) {
+	op := NewOrdererProvider(map[string]*cb.Capability{
+		OrdererV2_0:         {},
+		OrdererTxReordering: {},
+	})
+	require.NoError(t, op.Supported())
+	require.True(t, op.UseChannelCreationPolicyAsAdmins())
+	require.True(t, op.TxReordering())
 }
 
 func TestNotSupported(t *testing.T) {
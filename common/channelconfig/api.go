@@ -198,6 +198,10 @@ type ApplicationCapabilities interface {
 	// PurgePvtData returns true if this channel supports purging of private
 	// data entries
 	PurgePvtData() bool
+
+	// CrossChannelReads returns true if the reads of chaincode-to-chaincode calls to
+	// other channels are recorded in the read set of the transaction and validated on commit
+	CrossChannelReads() bool
 }
 
 // OrdererCapabilities defines the capabilities for the orderer portion of a channel
@@ -224,6 +228,10 @@ type OrdererCapabilities interface {
 	// channel creation logic using channel creation policy as the Admins policy if
 	// the creation transaction appears to support it.
 	UseChannelCreationPolicyAsAdmins() bool
+
+	// TxReordering specifies whether the orderer reorders the transactions of a block, based on
+	// their read-write sets, so as to reduce the intra-block MVCC conflicts.
+	TxReordering() bool
 }
 
 // PolicyMapper is an interface for
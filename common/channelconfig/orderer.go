@@ -191,6 +191,7 @@ func (oc *OrdererConfig) Validate() error {
 	for _, validator := range []func() error{
 		oc.validateBatchSize,
 		oc.validateBatchTimeout,
+		oc.validateTxReordering,
 	} {
 		if err := validator(); err != nil {
 			return err
@@ -228,6 +229,13 @@ func (oc *OrdererConfig) validateBatchTimeout() error {
 	return nil
 }
 
+func (oc *OrdererConfig) validateTxReordering() error {
+	if oc.protos.ConsensusType.GetType() == "BFT" && oc.Capabilities().TxReordering() {
+		return fmt.Errorf("Attempted to enable the %s capability with the BFT consensus type, which does not support transaction reordering", capabilities.OrdererTxReordering)
+	}
+	return nil
+}
+
 // This does just a barebones sanity check.
 func brokerEntrySeemsValid(broker string) bool {
 	if !strings.Contains(broker, ":") {
@@ -9,7 +9,9 @@ package channelconfig
 import (
 	"testing"
 
+	cb "github.com/hyperledger/fabric-protos-go/common"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	"github.com/hyperledger/fabric/common/capabilities"
 	"github.com/stretchr/testify/require"
 )
 
@@ -41,3 +43,16 @@ func TestBatchTimeout(t *testing.T) {
 	oc = &OrdererConfig{protos: &OrdererProtos{BatchTimeout: &ab.BatchTimeout{Timeout: "0s"}}}
 	require.Error(t, oc.validateBatchTimeout(), "Zero batch timeout")
 }
+
+func TestTxReordering(t *testing.T) {
+	reordering := &cb.Capabilities{Capabilities: map[string]*cb.Capability{capabilities.OrdererTxReordering: {}}}
+
+	oc := &OrdererConfig{protos: &OrdererProtos{ConsensusType: &ab.ConsensusType{Type: "etcdraft"}, Capabilities: reordering}}
+	require.NoError(t, oc.validateTxReordering(), "Reordering with etcdraft")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{ConsensusType: &ab.ConsensusType{Type: "BFT"}, Capabilities: &cb.Capabilities{}}}
+	require.NoError(t, oc.validateTxReordering(), "BFT without reordering")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{ConsensusType: &ab.ConsensusType{Type: "BFT"}, Capabilities: reordering}}
+	require.EqualError(t, oc.validateTxReordering(), "Attempted to enable the V3_0_TX_REORDERING capability with the BFT consensus type, which does not support transaction reordering")
+}
@@ -44,6 +44,11 @@ type EncodingSelector interface {
 // implementations. The core also references the logging configuration to
 // determine the proper encoding to use, the writer to delegate to, and the
 // enabled levels.
+//
+// Log records are also routed to the additional sinks of the logging
+// configuration. As sinks encode log records with encoders of their own, the
+// fields added to the core are retained to be added to the encoders of the
+// sinks when records are written.
 type Core struct {
 	zapcore.LevelEnabler
 	Levels   *LoggerLevels
@@ -51,6 +56,15 @@ type Core struct {
 	Selector EncodingSelector
 	Output   zapcore.WriteSyncer
 	Observer Observer
+	Sinks    SinkChecker
+
+	context []zapcore.Field
+}
+
+// SinkChecker adds the sinks a log entry is routed to to the checked entry.
+// The context holds the fields that have been added to the logger.
+type SinkChecker interface {
+	CheckSinks(e zapcore.Entry, ce *zapcore.CheckedEntry, context []zapcore.Field) *zapcore.CheckedEntry
 }
 
 //go:generate counterfeiter -o mock/observer.go -fake-name Observer . Observer
@@ -68,6 +82,13 @@ func (c *Core) With(fields []zapcore.Field) zapcore.Core {
 		clones[name] = clone
 	}
 
+	var context []zapcore.Field
+	if c.Sinks != nil {
+		context = make([]zapcore.Field, 0, len(c.context)+len(fields))
+		context = append(context, c.context...)
+		context = append(context, fields...)
+	}
+
 	return &Core{
 		LevelEnabler: c.LevelEnabler,
 		Levels:       c.Levels,
@@ -75,6 +96,8 @@ func (c *Core) With(fields []zapcore.Field) zapcore.Core {
 		Selector:     c.Selector,
 		Output:       c.Output,
 		Observer:     c.Observer,
+		Sinks:        c.Sinks,
+		context:      context,
 	}
 }
 
@@ -84,7 +107,10 @@ func (c *Core) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.Checked
 	}
 
 	if c.Enabled(e.Level) && c.Levels.Level(e.LoggerName).Enabled(e.Level) {
-		return ce.AddCore(e, c)
+		ce = ce.AddCore(e, c)
+	}
+	if c.Sinks != nil {
+		ce = c.Sinks.CheckSinks(e, ce, c.context)
 	}
 	return ce
 }
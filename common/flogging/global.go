@@ -82,3 +82,8 @@ func SetWriter(w io.Writer) io.Writer {
 func SetObserver(observer Observer) Observer {
 	return Global.SetObserver(observer)
 }
+
+// SetSinks replaces the sinks of the logging system.
+func SetSinks(configs []SinkConfig) error {
+	return Global.SetSinks(configs)
+}
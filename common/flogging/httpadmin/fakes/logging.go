@@ -4,10 +4,22 @@ package fakes
 import (
 	"sync"
 
+	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/flogging/httpadmin"
 )
 
 type Logging struct {
+	ActivateSinkSpecsStub        func(map[string]flogging.SinkSpec) error
+	activateSinkSpecsMutex       sync.RWMutex
+	activateSinkSpecsArgsForCall []struct {
+		arg1 map[string]flogging.SinkSpec
+	}
+	activateSinkSpecsReturns struct {
+		result1 error
+	}
+	activateSinkSpecsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ActivateSpecStub        func(string) error
 	activateSpecMutex       sync.RWMutex
 	activateSpecArgsForCall []struct {
@@ -19,6 +31,16 @@ type Logging struct {
 	activateSpecReturnsOnCall map[int]struct {
 		result1 error
 	}
+	SinkSpecsStub        func() map[string]flogging.SinkSpec
+	sinkSpecsMutex       sync.RWMutex
+	sinkSpecsArgsForCall []struct {
+	}
+	sinkSpecsReturns struct {
+		result1 map[string]flogging.SinkSpec
+	}
+	sinkSpecsReturnsOnCall map[int]struct {
+		result1 map[string]flogging.SinkSpec
+	}
 	SpecStub        func() string
 	specMutex       sync.RWMutex
 	specArgsForCall []struct {
@@ -33,21 +55,83 @@ type Logging struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *Logging) ActivateSinkSpecs(arg1 map[string]flogging.SinkSpec) error {
+	fake.activateSinkSpecsMutex.Lock()
+	ret, specificReturn := fake.activateSinkSpecsReturnsOnCall[len(fake.activateSinkSpecsArgsForCall)]
+	fake.activateSinkSpecsArgsForCall = append(fake.activateSinkSpecsArgsForCall, struct {
+		arg1 map[string]flogging.SinkSpec
+	}{arg1})
+	stub := fake.ActivateSinkSpecsStub
+	fakeReturns := fake.activateSinkSpecsReturns
+	fake.recordInvocation("ActivateSinkSpecs", []interface{}{arg1})
+	fake.activateSinkSpecsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Logging) ActivateSinkSpecsCallCount() int {
+	fake.activateSinkSpecsMutex.RLock()
+	defer fake.activateSinkSpecsMutex.RUnlock()
+	return len(fake.activateSinkSpecsArgsForCall)
+}
+
+func (fake *Logging) ActivateSinkSpecsCalls(stub func(map[string]flogging.SinkSpec) error) {
+	fake.activateSinkSpecsMutex.Lock()
+	defer fake.activateSinkSpecsMutex.Unlock()
+	fake.ActivateSinkSpecsStub = stub
+}
+
+func (fake *Logging) ActivateSinkSpecsArgsForCall(i int) map[string]flogging.SinkSpec {
+	fake.activateSinkSpecsMutex.RLock()
+	defer fake.activateSinkSpecsMutex.RUnlock()
+	argsForCall := fake.activateSinkSpecsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Logging) ActivateSinkSpecsReturns(result1 error) {
+	fake.activateSinkSpecsMutex.Lock()
+	defer fake.activateSinkSpecsMutex.Unlock()
+	fake.ActivateSinkSpecsStub = nil
+	fake.activateSinkSpecsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Logging) ActivateSinkSpecsReturnsOnCall(i int, result1 error) {
+	fake.activateSinkSpecsMutex.Lock()
+	defer fake.activateSinkSpecsMutex.Unlock()
+	fake.ActivateSinkSpecsStub = nil
+	if fake.activateSinkSpecsReturnsOnCall == nil {
+		fake.activateSinkSpecsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.activateSinkSpecsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Logging) ActivateSpec(arg1 string) error {
 	fake.activateSpecMutex.Lock()
 	ret, specificReturn := fake.activateSpecReturnsOnCall[len(fake.activateSpecArgsForCall)]
 	fake.activateSpecArgsForCall = append(fake.activateSpecArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.ActivateSpecStub
+	fakeReturns := fake.activateSpecReturns
 	fake.recordInvocation("ActivateSpec", []interface{}{arg1})
 	fake.activateSpecMutex.Unlock()
-	if fake.ActivateSpecStub != nil {
-		return fake.ActivateSpecStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.activateSpecReturns
 	return fakeReturns.result1
 }
 
@@ -93,20 +177,74 @@ func (fake *Logging) ActivateSpecReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *Logging) SinkSpecs() map[string]flogging.SinkSpec {
+	fake.sinkSpecsMutex.Lock()
+	ret, specificReturn := fake.sinkSpecsReturnsOnCall[len(fake.sinkSpecsArgsForCall)]
+	fake.sinkSpecsArgsForCall = append(fake.sinkSpecsArgsForCall, struct {
+	}{})
+	stub := fake.SinkSpecsStub
+	fakeReturns := fake.sinkSpecsReturns
+	fake.recordInvocation("SinkSpecs", []interface{}{})
+	fake.sinkSpecsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Logging) SinkSpecsCallCount() int {
+	fake.sinkSpecsMutex.RLock()
+	defer fake.sinkSpecsMutex.RUnlock()
+	return len(fake.sinkSpecsArgsForCall)
+}
+
+func (fake *Logging) SinkSpecsCalls(stub func() map[string]flogging.SinkSpec) {
+	fake.sinkSpecsMutex.Lock()
+	defer fake.sinkSpecsMutex.Unlock()
+	fake.SinkSpecsStub = stub
+}
+
+func (fake *Logging) SinkSpecsReturns(result1 map[string]flogging.SinkSpec) {
+	fake.sinkSpecsMutex.Lock()
+	defer fake.sinkSpecsMutex.Unlock()
+	fake.SinkSpecsStub = nil
+	fake.sinkSpecsReturns = struct {
+		result1 map[string]flogging.SinkSpec
+	}{result1}
+}
+
+func (fake *Logging) SinkSpecsReturnsOnCall(i int, result1 map[string]flogging.SinkSpec) {
+	fake.sinkSpecsMutex.Lock()
+	defer fake.sinkSpecsMutex.Unlock()
+	fake.SinkSpecsStub = nil
+	if fake.sinkSpecsReturnsOnCall == nil {
+		fake.sinkSpecsReturnsOnCall = make(map[int]struct {
+			result1 map[string]flogging.SinkSpec
+		})
+	}
+	fake.sinkSpecsReturnsOnCall[i] = struct {
+		result1 map[string]flogging.SinkSpec
+	}{result1}
+}
+
 func (fake *Logging) Spec() string {
 	fake.specMutex.Lock()
 	ret, specificReturn := fake.specReturnsOnCall[len(fake.specArgsForCall)]
 	fake.specArgsForCall = append(fake.specArgsForCall, struct {
 	}{})
+	stub := fake.SpecStub
+	fakeReturns := fake.specReturns
 	fake.recordInvocation("Spec", []interface{}{})
 	fake.specMutex.Unlock()
-	if fake.SpecStub != nil {
-		return fake.SpecStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.specReturns
 	return fakeReturns.result1
 }
 
@@ -148,8 +286,12 @@ func (fake *Logging) SpecReturnsOnCall(i int, result1 string) {
 func (fake *Logging) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.activateSinkSpecsMutex.RLock()
+	defer fake.activateSinkSpecsMutex.RUnlock()
 	fake.activateSpecMutex.RLock()
 	defer fake.activateSpecMutex.RUnlock()
+	fake.sinkSpecsMutex.RLock()
+	defer fake.sinkSpecsMutex.RUnlock()
 	fake.specMutex.RLock()
 	defer fake.specMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
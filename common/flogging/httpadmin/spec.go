@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/hyperledger/fabric/common/audit"
 	"github.com/hyperledger/fabric/common/flogging"
 )
 
@@ -19,11 +20,14 @@ import (
 type Logging interface {
 	ActivateSpec(spec string) error
 	Spec() string
+	ActivateSinkSpecs(specs map[string]flogging.SinkSpec) error
+	SinkSpecs() map[string]flogging.SinkSpec
 }
 
 // swagger:model spec
 type LogSpec struct {
-	Spec string `json:"spec,omitempty"`
+	Spec  string                       `json:"spec,omitempty"`
+	Sinks map[string]flogging.SinkSpec `json:"sinks,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -38,8 +42,9 @@ func NewSpecHandler() *SpecHandler {
 }
 
 type SpecHandler struct {
-	Logging Logging
-	Logger  *flogging.FabricLogger
+	Logging     Logging
+	Logger      *flogging.FabricLogger
+	AuditLogger *audit.Logger
 }
 
 func (h *SpecHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
@@ -53,14 +58,24 @@ func (h *SpecHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 		}
 		req.Body.Close()
 
-		if err := h.Logging.ActivateSpec(logSpec.Spec); err != nil {
+		var err error
+		// The logging spec is left unchanged when only the specs of sinks are updated.
+		if logSpec.Spec != "" || len(logSpec.Sinks) == 0 {
+			err = h.Logging.ActivateSpec(logSpec.Spec)
+			h.AuditLogger.Log("operations", "SetLogSpec", audit.CallerFromHTTPRequest(req), map[string]string{"spec": logSpec.Spec}, err)
+		}
+		if err == nil && len(logSpec.Sinks) != 0 {
+			err = h.Logging.ActivateSinkSpecs(logSpec.Sinks)
+			h.AuditLogger.Log("operations", "SetLogSinkSpecs", audit.CallerFromHTTPRequest(req), sinkParameters(logSpec.Sinks), err)
+		}
+		if err != nil {
 			h.sendResponse(resp, http.StatusBadRequest, err)
 			return
 		}
 		resp.WriteHeader(http.StatusNoContent)
 
 	case http.MethodGet:
-		h.sendResponse(resp, http.StatusOK, &LogSpec{Spec: h.Logging.Spec()})
+		h.sendResponse(resp, http.StatusOK, &LogSpec{Spec: h.Logging.Spec(), Sinks: h.Logging.SinkSpecs()})
 
 	default:
 		err := fmt.Errorf("invalid request method: %s", req.Method)
@@ -68,6 +83,15 @@ func (h *SpecHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	}
 }
 
+func sinkParameters(specs map[string]flogging.SinkSpec) map[string]string {
+	params := map[string]string{}
+	for name, spec := range specs {
+		params[name+".spec"] = spec.Spec
+		params[name+".format"] = spec.Format
+	}
+	return params
+}
+
 func (h *SpecHandler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
 	encoder := json.NewEncoder(resp)
 	if err, ok := payload.(error); ok {
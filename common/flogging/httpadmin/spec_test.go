@@ -7,11 +7,13 @@ SPDX-License-Identifier: Apache-2.0
 package httpadmin_test
 
 import (
+	"bytes"
 	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 
+	"github.com/hyperledger/fabric/common/audit"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/flogging/httpadmin"
 	"github.com/hyperledger/fabric/common/flogging/httpadmin/fakes"
@@ -54,6 +56,52 @@ var _ = Describe("SpecHandler", func() {
 		Expect(fakeLogging.ActivateSpecArgsForCall(0)).To(Equal("updated-spec"))
 	})
 
+	It("responds with the current specs of the sinks", func() {
+		fakeLogging.SinkSpecsReturns(map[string]flogging.SinkSpec{"gossip": {Spec: "debug", Format: "json"}})
+		req := httptest.NewRequest("GET", "/ignored", nil)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Result().StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Body).To(MatchJSON(`{"spec": "the-returned-specification", "sinks": {"gossip": {"spec": "debug", "format": "json"}}}`))
+	})
+
+	It("sets the specs of the sinks", func() {
+		req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`{"sinks": {"gossip": {"spec": "debug"}}}`))
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Result().StatusCode).To(Equal(http.StatusNoContent))
+		Expect(fakeLogging.ActivateSpecCallCount()).To(Equal(0))
+		Expect(fakeLogging.ActivateSinkSpecsCallCount()).To(Equal(1))
+		Expect(fakeLogging.ActivateSinkSpecsArgsForCall(0)).To(Equal(map[string]flogging.SinkSpec{"gossip": {Spec: "debug"}}))
+	})
+
+	It("sets the logging spec along with the specs of the sinks", func() {
+		req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`{"spec": "updated-spec", "sinks": {"gossip": {"spec": "debug"}}}`))
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		Expect(resp.Result().StatusCode).To(Equal(http.StatusNoContent))
+		Expect(fakeLogging.ActivateSpecArgsForCall(0)).To(Equal("updated-spec"))
+		Expect(fakeLogging.ActivateSinkSpecsCallCount()).To(Equal(1))
+	})
+
+	Context("when activating the specs of the sinks fails", func() {
+		BeforeEach(func() {
+			fakeLogging.ActivateSinkSpecsReturns(errors.New("log sink gossip does not exist"))
+		})
+
+		It("responds with an error payload", func() {
+			req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`{"sinks": {"gossip": {"spec": "debug"}}}`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(resp.Result().StatusCode).To(Equal(http.StatusBadRequest))
+			Expect(resp.Body).To(MatchJSON(`{"error": "log sink gossip does not exist"}`))
+		})
+	})
+
 	Context("when the update spec payload cannot be decoded", func() {
 		It("responds with an error payload", func() {
 			req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`goo`))
@@ -81,6 +129,51 @@ var _ = Describe("SpecHandler", func() {
 		})
 	})
 
+	Context("when an audit logger is set", func() {
+		var buff *bytes.Buffer
+
+		BeforeEach(func() {
+			buff = &bytes.Buffer{}
+			handler.AuditLogger = audit.NewLogger(buff, nil)
+		})
+
+		It("records the update of the spec", func() {
+			req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`{"spec": "updated-spec"}`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			Expect(resp.Result().StatusCode).To(Equal(http.StatusNoContent))
+
+			record, err := audit.Verify(buff, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(record.Component).To(Equal("operations"))
+			Expect(record.Action).To(Equal("SetLogSpec"))
+			Expect(record.Parameters).To(Equal(map[string]string{"spec": "updated-spec"}))
+			Expect(record.Outcome).To(Equal(audit.OutcomeSuccess))
+			Expect(record.Caller.Address).To(Equal(req.RemoteAddr))
+		})
+
+		It("records the update of the specs of the sinks", func() {
+			req := httptest.NewRequest("PUT", "/ignored", strings.NewReader(`{"sinks": {"gossip": {"spec": "debug", "format": "json"}}}`))
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+			Expect(resp.Result().StatusCode).To(Equal(http.StatusNoContent))
+
+			record, err := audit.Verify(buff, "")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(record.Sequence).To(Equal(uint64(1)))
+			Expect(record.Action).To(Equal("SetLogSinkSpecs"))
+			Expect(record.Parameters).To(Equal(map[string]string{"gossip.spec": "debug", "gossip.format": "json"}))
+		})
+
+		It("doesn't record reading the spec", func() {
+			req := httptest.NewRequest("GET", "/ignored", nil)
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, req)
+
+			Expect(buff.Len()).To(Equal(0))
+		})
+	})
+
 	Context("when an unsupported method is used", func() {
 		It("responds with an error", func() {
 			req := httptest.NewRequest("POST", "/ignored", strings.NewReader(`{}`))
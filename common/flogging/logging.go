@@ -13,6 +13,7 @@ import (
 	"sync"
 
 	"github.com/hyperledger/fabric/common/flogging/fabenc"
+	"github.com/pkg/errors"
 	zaplogfmt "github.com/sykesm/zap-logfmt"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -39,6 +40,10 @@ type Config struct {
 	//
 	// If a Writer is not provided, os.Stderr will be used as the log sink.
 	Writer io.Writer
+
+	// Sinks are the destinations of log records in addition to the Writer,
+	// each with its own logging specification, format and loggers.
+	Sinks []SinkConfig
 }
 
 // Logging maintains the state associated with the fabric logging system. It is
@@ -53,6 +58,7 @@ type Logging struct {
 	multiFormatter *fabenc.MultiFormatter
 	writer         zapcore.WriteSyncer
 	observer       Observer
+	sinks          []*sink
 }
 
 // New creates a new logging system and initializes it with the provided
@@ -100,7 +106,7 @@ func (l *Logging) Apply(c Config) error {
 	}
 	l.SetWriter(c.Writer)
 
-	return nil
+	return l.SetSinks(c.Sinks)
 }
 
 // SetFormat updates how log records are formatted and encoded. Log entries
@@ -156,6 +162,121 @@ func (l *Logging) SetWriter(w io.Writer) io.Writer {
 	return ow
 }
 
+// SetSinks replaces the sinks of the logging system with the configured
+// sinks. The previous sinks are closed once they have been replaced. If any
+// of the sinks cannot be created, an error is returned and the sinks are left
+// unchanged.
+func (l *Logging) SetSinks(configs []SinkConfig) error {
+	var sinks []*sink
+	closeAll := func(sinks []*sink) {
+		for _, s := range sinks {
+			s.writer.Close()
+		}
+	}
+
+	names := map[string]struct{}{}
+	for _, c := range configs {
+		if _, ok := names[c.Name]; ok {
+			closeAll(sinks)
+			return errors.Errorf("duplicate log sink name '%s'", c.Name)
+		}
+		names[c.Name] = struct{}{}
+
+		s, err := newSink(c, l.encoderConfig)
+		if err != nil {
+			closeAll(sinks)
+			return err
+		}
+		sinks = append(sinks, s)
+	}
+
+	l.mutex.Lock()
+	old := l.sinks
+	l.sinks = sinks
+	l.mutex.Unlock()
+
+	closeAll(old)
+	return nil
+}
+
+// SinkSpecs returns the specs of the sinks of the logging system by name.
+func (l *Logging) SinkSpecs() map[string]SinkSpec {
+	l.mutex.RLock()
+	sinks := l.sinks
+	l.mutex.RUnlock()
+
+	specs := map[string]SinkSpec{}
+	for _, s := range sinks {
+		specs[s.name] = s.currentSpec()
+	}
+	return specs
+}
+
+// ActivateSinkSpecs changes the logging specification and the format of the
+// named sinks. An empty logging specification makes a sink follow the logging
+// specification of the logging system, and an empty format selects the
+// default format. The specs are validated before any of them is activated.
+func (l *Logging) ActivateSinkSpecs(specs map[string]SinkSpec) error {
+	l.mutex.RLock()
+	sinks := map[string]*sink{}
+	for _, s := range l.sinks {
+		sinks[s.name] = s
+	}
+	l.mutex.RUnlock()
+
+	for name, spec := range specs {
+		s, ok := sinks[name]
+		if !ok {
+			return errors.Errorf("log sink %s does not exist", name)
+		}
+		// validate the spec against a scratch sink
+		if err := (&sink{name: s.name}).activate(spec, l.encoderConfig); err != nil {
+			return err
+		}
+	}
+
+	for name, spec := range specs {
+		if err := sinks[name].activate(spec, l.encoderConfig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckSinks satisfies the SinkChecker interface. It adds the sinks the log
+// entry is routed to and enabled for to the checked entry.
+func (l *Logging) CheckSinks(e zapcore.Entry, ce *zapcore.CheckedEntry, context []zapcore.Field) *zapcore.CheckedEntry {
+	l.mutex.RLock()
+	sinks := l.sinks
+	l.mutex.RUnlock()
+
+	for _, s := range sinks {
+		if s.enabled(e, l.LoggerLevels) {
+			ce = ce.AddCore(e, &sinkCore{sink: s, context: context})
+		}
+	}
+	return ce
+}
+
+// Enabled is an enabled check that evaluates the minimum active logging level
+// of the logging system and of its sinks.
+func (l *Logging) Enabled(lvl zapcore.Level) bool {
+	if l.LoggerLevels.Enabled(lvl) {
+		return true
+	}
+
+	l.mutex.RLock()
+	sinks := l.sinks
+	l.mutex.RUnlock()
+
+	for _, s := range sinks {
+		if s.levelEnabled(lvl) {
+			return true
+		}
+	}
+	return false
+}
+
 // SetObserver is used to provide a log observer that will be called as log
 // levels are checked or written.. Only a single observer is supported.
 func (l *Logging) SetObserver(observer Observer) Observer {
@@ -206,7 +327,7 @@ func (l *Logging) ZapLogger(name string) *zap.Logger {
 
 	l.mutex.RLock()
 	core := &Core{
-		LevelEnabler: l.LoggerLevels,
+		LevelEnabler: l,
 		Levels:       l.LoggerLevels,
 		Encoders: map[Encoding]zapcore.Encoder{
 			JSON:    zapcore.NewJSONEncoder(l.encoderConfig),
@@ -216,6 +337,7 @@ func (l *Logging) ZapLogger(name string) *zap.Logger {
 		Selector: l,
 		Output:   l,
 		Observer: l,
+		Sinks:    l,
 	}
 	l.mutex.RUnlock()
 
@@ -0,0 +1,292 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging/fabenc"
+	"github.com/pkg/errors"
+	zaplogfmt "github.com/sykesm/zap-logfmt"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// defaultSinkFormat is the default format without colors, as the records
+	// of sinks are not intended for terminals.
+	defaultSinkFormat = "%{time:2006-01-02 15:04:05.000 MST} %{id:04x} %{level:.4s} [%{module}] %{shortfunc} -> %{message}"
+
+	FileSink   = "file"
+	SyslogSink = "syslog"
+	RemoteSink = "remote"
+)
+
+// SinkConfig configures a sink, which is a destination of log records in
+// addition to the writer of the logging system.
+type SinkConfig struct {
+	// Name identifies the sink, for instance when its spec is changed at runtime.
+	Name string
+
+	// Type is the type of the sink. A "file" sink writes log records to a
+	// rotated file, a "syslog" sink sends them to a syslog server in the
+	// RFC 5424 format, and a "remote" sink sends them as they are encoded to a
+	// remote collector, one record per line.
+	Type string
+
+	// Spec is the logging specification of the sink, which determines the log
+	// levels that are enabled for the sink independently of the logging
+	// system. The spec must be in a format that can be processed by
+	// ActivateSpec.
+	//
+	// If Spec is not provided, the sink follows the logging specification of
+	// the logging system.
+	Spec string
+
+	// Format is the log record format specifier of the sink. Please see the
+	// Format of Config for details.
+	//
+	// If Format is not provided, the default format without colors is used.
+	Format string
+
+	// Loggers restricts the sink to the log records of the named loggers. As
+	// with logging specifications, a logger name also designates its
+	// descendants unless it ends with a period.
+	//
+	// If Loggers is not provided, the log records of all loggers are routed to
+	// the sink.
+	Loggers []string
+
+	// File is the path of the log file of a file sink.
+	File string
+
+	// MaxSize is the size in megabytes a log file may reach before it is
+	// rotated. Log files are not rotated based on their size if MaxSize is 0.
+	MaxSize int
+
+	// RotationInterval is the time after which a log file is rotated. Log
+	// files are not rotated based on time if RotationInterval is 0.
+	RotationInterval time.Duration
+
+	// MaxBackups is the number of rotated log files to keep. All of them are
+	// kept if MaxBackups is 0.
+	MaxBackups int
+
+	// Network is the network of the server of a syslog or remote sink, either
+	// "udp" or "tcp".
+	Network string
+
+	// Address is the address of the server of a syslog or remote sink.
+	Address string
+
+	// Facility is the syslog facility of the log records of a syslog sink,
+	// such as "daemon" or "local0". If Facility is not provided, "user" is used.
+	Facility string
+
+	// AppName is the syslog APP-NAME of the log records of a syslog sink. If
+	// AppName is not provided, the name of the executable is used.
+	AppName string
+}
+
+// SinkSpec holds the properties of a sink that can be changed at runtime.
+type SinkSpec struct {
+	Spec   string `json:"spec,omitempty"`
+	Format string `json:"format,omitempty"`
+}
+
+// A sinkWriter writes encoded log records to the destination of a sink.
+type sinkWriter interface {
+	WriteEntry(e zapcore.Entry, b []byte) error
+	Sync() error
+	Close() error
+}
+
+type sink struct {
+	name    string
+	loggers []string
+	writer  sinkWriter
+
+	mutex   sync.RWMutex
+	spec    SinkSpec
+	levels  *LoggerLevels // nil when the sink follows the logging system
+	encoder zapcore.Encoder
+}
+
+func newSink(c SinkConfig, encoderConfig zapcore.EncoderConfig) (*sink, error) {
+	if !isValidLoggerName(c.Name) {
+		return nil, errors.Errorf("invalid log sink name '%s'", c.Name)
+	}
+	for _, logger := range c.Loggers {
+		if !isValidLoggerName(strings.TrimSuffix(logger, ".")) {
+			return nil, errors.Errorf("invalid logger name '%s' for log sink %s", logger, c.Name)
+		}
+	}
+
+	s := &sink{
+		name:    c.Name,
+		loggers: c.Loggers,
+	}
+	if err := s.activate(SinkSpec{Spec: c.Spec, Format: c.Format}, encoderConfig); err != nil {
+		return nil, err
+	}
+
+	var err error
+	switch c.Type {
+	case FileSink:
+		s.writer, err = newRotatingFile(c.File, c.MaxSize, c.RotationInterval, c.MaxBackups)
+	case SyslogSink:
+		s.writer, err = newSyslogWriter(c.Network, c.Address, c.Facility, c.AppName)
+	case RemoteSink:
+		s.writer, err = newRemoteWriter(c.Network, c.Address)
+	default:
+		err = errors.Errorf("unknown type '%s'", c.Type)
+	}
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to create log sink %s", c.Name)
+	}
+
+	return s, nil
+}
+
+// activate changes the spec and the format of the sink.
+func (s *sink) activate(spec SinkSpec, encoderConfig zapcore.EncoderConfig) error {
+	var levels *LoggerLevels
+	if spec.Spec != "" {
+		levels = &LoggerLevels{}
+		if err := levels.ActivateSpec(spec.Spec); err != nil {
+			return errors.WithMessagef(err, "log sink %s", s.name)
+		}
+	}
+
+	encoder, err := newSinkEncoder(spec.Format, encoderConfig)
+	if err != nil {
+		return errors.WithMessagef(err, "invalid format for log sink %s", s.name)
+	}
+
+	s.mutex.Lock()
+	s.spec = spec
+	s.levels = levels
+	s.encoder = encoder
+	s.mutex.Unlock()
+
+	return nil
+}
+
+func newSinkEncoder(format string, encoderConfig zapcore.EncoderConfig) (zapcore.Encoder, error) {
+	switch format {
+	case "":
+		format = defaultSinkFormat
+	case "json":
+		return zapcore.NewJSONEncoder(encoderConfig), nil
+	case "logfmt":
+		return zaplogfmt.NewEncoder(encoderConfig), nil
+	}
+
+	formatters, err := fabenc.ParseFormat(format)
+	if err != nil {
+		return nil, err
+	}
+	return fabenc.NewFormatEncoder(formatters...), nil
+}
+
+// routes determines whether the log records of the logger are routed to the sink.
+func (s *sink) routes(loggerName string) bool {
+	if len(s.loggers) == 0 {
+		return true
+	}
+	for _, logger := range s.loggers {
+		if strings.HasSuffix(logger, ".") {
+			if loggerName+"." == logger {
+				return true
+			}
+			continue
+		}
+		if loggerName == logger || strings.HasPrefix(loggerName, logger+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// enabled determines whether the log entry is written to the sink, where
+// defaults are the levels of the logging system.
+func (s *sink) enabled(e zapcore.Entry, defaults *LoggerLevels) bool {
+	s.mutex.RLock()
+	levels := s.levels
+	s.mutex.RUnlock()
+
+	if levels == nil {
+		levels = defaults
+	}
+	return levels.Level(e.LoggerName).Enabled(e.Level) && s.routes(e.LoggerName)
+}
+
+// levelEnabled determines whether the level is enabled for any logger of a sink
+// that does not follow the logging system.
+func (s *sink) levelEnabled(lvl zapcore.Level) bool {
+	s.mutex.RLock()
+	levels := s.levels
+	s.mutex.RUnlock()
+
+	return levels != nil && levels.Enabled(lvl)
+}
+
+func (s *sink) currentSpec() SinkSpec {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.spec
+}
+
+func (s *sink) write(e zapcore.Entry, context, fields []zapcore.Field) error {
+	s.mutex.RLock()
+	enc := s.encoder.Clone()
+	s.mutex.RUnlock()
+
+	addFields(enc, context)
+	buf, err := enc.EncodeEntry(e, fields)
+	if err != nil {
+		return err
+	}
+	err = s.writer.WriteEntry(e, buf.Bytes())
+	buf.Free()
+	if err != nil {
+		return errors.WithMessagef(err, "failed to write to log sink %s", s.name)
+	}
+
+	if e.Level >= zapcore.PanicLevel {
+		s.writer.Sync()
+	}
+	return nil
+}
+
+// sinkCore is the zapcore.Core of a log entry that is routed to a sink.
+type sinkCore struct {
+	sink    *sink
+	context []zapcore.Field
+}
+
+func (c *sinkCore) Enabled(zapcore.Level) bool { return true }
+
+func (c *sinkCore) With(fields []zapcore.Field) zapcore.Core {
+	context := make([]zapcore.Field, 0, len(c.context)+len(fields))
+	context = append(context, c.context...)
+	context = append(context, fields...)
+	return &sinkCore{sink: c.sink, context: context}
+}
+
+func (c *sinkCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(e, c)
+}
+
+func (c *sinkCore) Write(e zapcore.Entry, fields []zapcore.Field) error {
+	return c.sink.write(e, c.context, fields)
+}
+
+func (c *sinkCore) Sync() error {
+	return c.sink.writer.Sync()
+}
@@ -0,0 +1,171 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+)
+
+// backupTimeFormat is the format of the time a log file was rotated at, which
+// is the suffix of the name of the rotated file. Rotated files sort by name in
+// the order they were rotated.
+const backupTimeFormat = "2006-01-02T15-04-05.000000"
+
+// rotatingFile writes log records to a file, which is rotated when it grows
+// beyond its maximum size or when its rotation interval has elapsed.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	interval   time.Duration
+	maxBackups int
+	now        func() time.Time
+
+	mutex    sync.Mutex
+	file     *os.File
+	size     int64
+	rotateAt time.Time
+}
+
+func newRotatingFile(path string, maxSizeMB int, interval time.Duration, maxBackups int) (*rotatingFile, error) {
+	if path == "" {
+		return nil, errors.New("no log file specified")
+	}
+	if maxSizeMB < 0 || interval < 0 || maxBackups < 0 {
+		return nil, errors.New("the maximum size, rotation interval and maximum backups of log files must not be negative")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create directory of log file %s", path)
+	}
+
+	r := &rotatingFile{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		interval:   interval,
+		maxBackups: maxBackups,
+		now:        time.Now,
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open log file %s", r.path)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return errors.Wrapf(err, "failed to stat log file %s", r.path)
+	}
+
+	r.file = f
+	r.size = info.Size()
+	if r.interval > 0 {
+		r.rotateAt = r.now().Add(r.interval)
+	}
+	return nil
+}
+
+func (r *rotatingFile) WriteEntry(_ zapcore.Entry, b []byte) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.file == nil {
+		return errors.Errorf("log file %s is closed", r.path)
+	}
+	if r.size > 0 && r.dueForRotation(int64(len(b))) {
+		if err := r.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := r.file.Write(b)
+	r.size += int64(n)
+	return err
+}
+
+func (r *rotatingFile) dueForRotation(n int64) bool {
+	if r.maxSize > 0 && r.size+n > r.maxSize {
+		return true
+	}
+	return r.interval > 0 && !r.now().Before(r.rotateAt)
+}
+
+func (r *rotatingFile) rotate() error {
+	r.file.Close()
+	r.file = nil
+
+	backup := r.path + "." + r.now().UTC().Format(backupTimeFormat)
+	renameErr := os.Rename(r.path, backup)
+	if err := r.open(); err != nil {
+		return err
+	}
+	if renameErr != nil {
+		// keep on writing to the file that could not be rotated
+		return errors.Wrapf(renameErr, "failed to rotate log file %s", r.path)
+	}
+
+	r.removeBackups()
+	return nil
+}
+
+// removeBackups removes the oldest rotated files beyond the maximum number of backups.
+func (r *rotatingFile) removeBackups() {
+	if r.maxBackups == 0 {
+		return
+	}
+
+	matches, err := filepath.Glob(r.path + ".*")
+	if err != nil {
+		return
+	}
+	var backups []string
+	for _, m := range matches {
+		if _, err := time.Parse(backupTimeFormat, strings.TrimPrefix(m, r.path+".")); err == nil {
+			backups = append(backups, m)
+		}
+	}
+	sort.Strings(backups)
+
+	for len(backups) > r.maxBackups {
+		os.Remove(backups[0])
+		backups = backups[1:]
+	}
+}
+
+func (r *rotatingFile) Sync() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Sync()
+}
+
+func (r *rotatingFile) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
@@ -0,0 +1,327 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	// dialTimeout and writeTimeout bound the time the records of a sink may
+	// wait for its server.
+	dialTimeout  = 2 * time.Second
+	writeTimeout = time.Second
+
+	// netQueueSize is the number of log records that may wait to be sent to
+	// the server of a sink, beyond which log records are dropped.
+	netQueueSize = 1024
+
+	// minReconnectBackoff and maxReconnectBackoff bound the time to wait
+	// before connecting again to the server of a sink after a failure.
+	minReconnectBackoff = 100 * time.Millisecond
+	maxReconnectBackoff = 30 * time.Second
+
+	// syslogTimeFormat is the RFC 5424 timestamp format, at microsecond precision.
+	syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+)
+
+var syslogFacilities = map[string]int{
+	"kern":     0,
+	"user":     1,
+	"mail":     2,
+	"daemon":   3,
+	"auth":     4,
+	"syslog":   5,
+	"lpr":      6,
+	"news":     7,
+	"uucp":     8,
+	"cron":     9,
+	"authpriv": 10,
+	"ftp":      11,
+	"local0":   16,
+	"local1":   17,
+	"local2":   18,
+	"local3":   19,
+	"local4":   20,
+	"local5":   21,
+	"local6":   22,
+	"local7":   23,
+}
+
+// netWriter sends log records to a server over UDP or TCP. Log records are
+// queued and sent in the background, so that logging does not wait for the
+// server, and they are dropped when the queue is full. The connection is
+// established when the first record is sent, and is established again when a
+// record cannot be sent, after a backoff that grows with consecutive failures.
+type netWriter struct {
+	network string
+	address string
+	frame   func(e zapcore.Entry, b []byte) []byte
+
+	queue     chan netRecord
+	done      chan struct{}
+	stopped   chan struct{}
+	closeOnce sync.Once
+	dropped   uint64 // records dropped as the queue was full or they could not be sent, accessed atomically
+
+	// conn and backoff are only accessed by the goroutine that sends the records
+	conn    net.Conn
+	backoff time.Duration
+}
+
+// netRecord is a framed log record, or a request to be notified once the
+// records that precede it are sent when synced is not nil.
+type netRecord struct {
+	msg    []byte
+	synced chan struct{}
+}
+
+func newNetWriter(network, address string, frame func(e zapcore.Entry, b []byte) []byte) (*netWriter, error) {
+	if network != "udp" && network != "tcp" {
+		return nil, errors.Errorf("unsupported network '%s', must be udp or tcp", network)
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return nil, errors.Wrapf(err, "invalid address '%s'", address)
+	}
+
+	n := &netWriter{
+		network: network,
+		address: address,
+		frame:   frame,
+		queue:   make(chan netRecord, netQueueSize),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go n.run()
+	return n, nil
+}
+
+// newRemoteWriter creates a writer that sends log records as they are encoded,
+// one record per line over TCP and one record per datagram over UDP.
+func newRemoteWriter(network, address string) (*netWriter, error) {
+	return newNetWriter(network, address, func(_ zapcore.Entry, b []byte) []byte {
+		// the record is sent after the buffer of the encoder is reused, hence it is copied
+		msg := make([]byte, len(b), len(b)+1)
+		copy(msg, b)
+		if !bytes.HasSuffix(msg, []byte("\n")) {
+			msg = append(msg, '\n')
+		}
+		return msg
+	})
+}
+
+// newSyslogWriter creates a writer that sends log records to a syslog server
+// in the RFC 5424 format. Over TCP, messages are framed by octet counting as
+// specified by RFC 6587.
+func newSyslogWriter(network, address, facility, appName string) (*netWriter, error) {
+	if facility == "" {
+		facility = "user"
+	}
+	code, ok := syslogFacilities[facility]
+	if !ok {
+		return nil, errors.Errorf("unknown syslog facility '%s'", facility)
+	}
+	if appName == "" {
+		appName = filepath.Base(os.Args[0])
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	header := fmt.Sprintf("%s %s %d", syslogHeaderField(hostname, 255), syslogHeaderField(appName, 48), os.Getpid())
+
+	return newNetWriter(network, address, func(e zapcore.Entry, b []byte) []byte {
+		msg := fmt.Sprintf("<%d>1 %s %s %s - %s",
+			code*8+syslogSeverity(e.Level),
+			e.Time.Format(syslogTimeFormat),
+			header,
+			syslogHeaderField(e.LoggerName, 32),
+			bytes.TrimRight(b, "\n"),
+		)
+		if network == "tcp" {
+			msg = fmt.Sprintf("%d %s", len(msg), msg)
+		}
+		return []byte(msg)
+	})
+}
+
+// syslogHeaderField makes the value suitable for a header field of at most
+// maxLen printable ASCII characters, where the nil value is a dash.
+func syslogHeaderField(value string, maxLen int) string {
+	field := make([]byte, 0, len(value))
+	for i := 0; i < len(value) && len(field) < maxLen; i++ {
+		if value[i] > 32 && value[i] < 127 {
+			field = append(field, value[i])
+		}
+	}
+	if len(field) == 0 {
+		return "-"
+	}
+	return string(field)
+}
+
+func syslogSeverity(level zapcore.Level) int {
+	switch {
+	case level >= zapcore.FatalLevel:
+		return 0 // emergency
+	case level >= zapcore.PanicLevel:
+		return 1 // alert
+	case level >= zapcore.DPanicLevel:
+		return 2 // critical
+	case level >= zapcore.ErrorLevel:
+		return 3 // error
+	case level >= zapcore.WarnLevel:
+		return 4 // warning
+	case level >= zapcore.InfoLevel:
+		return 6 // informational
+	default:
+		return 7 // debug
+	}
+}
+
+// WriteEntry queues the log record to be sent. The record is dropped if the
+// queue is full, in which case an error is returned for the first dropped
+// record and then for each power of two of dropped records, so that an
+// unavailable server does not flood the error output of the logging system.
+func (n *netWriter) WriteEntry(e zapcore.Entry, b []byte) error {
+	select {
+	case <-n.done:
+		return errors.Errorf("connection to %s is closed", n.address)
+	default:
+	}
+
+	select {
+	case n.queue <- netRecord{msg: n.frame(e, b)}:
+		return nil
+	default:
+		dropped := atomic.AddUint64(&n.dropped, 1)
+		if dropped&(dropped-1) == 0 {
+			return errors.Errorf("dropped log record, as the queue of records to %s is full (%d records dropped so far)", n.address, dropped)
+		}
+		return nil
+	}
+}
+
+// run sends the queued records until the writer is closed.
+func (n *netWriter) run() {
+	defer close(n.stopped)
+	defer func() {
+		if n.conn != nil {
+			n.conn.Close()
+		}
+	}()
+
+	for {
+		select {
+		case r := <-n.queue:
+			if r.synced != nil {
+				close(r.synced)
+				continue
+			}
+			n.send(r.msg)
+		case <-n.done:
+			return
+		}
+	}
+}
+
+// send sends the record, and tries again once on a new connection if the
+// connection was established already, as the server may have closed it. If the
+// record cannot be sent, it is dropped and the writer backs off before it
+// connects again.
+func (n *netWriter) send(msg []byte) {
+	err := n.write(msg)
+	if err != nil && n.conn != nil {
+		n.conn.Close()
+		n.conn = nil
+		err = n.write(msg)
+	}
+	if err == nil {
+		n.backoff = 0
+		return
+	}
+
+	if n.conn != nil {
+		n.conn.Close()
+		n.conn = nil
+	}
+	atomic.AddUint64(&n.dropped, 1)
+	n.backoff = nextReconnectBackoff(n.backoff)
+
+	timer := time.NewTimer(n.backoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-n.done:
+	}
+}
+
+func nextReconnectBackoff(backoff time.Duration) time.Duration {
+	backoff *= 2
+	if backoff < minReconnectBackoff {
+		return minReconnectBackoff
+	}
+	if backoff > maxReconnectBackoff {
+		return maxReconnectBackoff
+	}
+	return backoff
+}
+
+func (n *netWriter) write(msg []byte) error {
+	if n.conn == nil {
+		conn, err := net.DialTimeout(n.network, n.address, dialTimeout)
+		if err != nil {
+			return errors.Wrapf(err, "failed to connect to %s", n.address)
+		}
+		n.conn = conn
+	}
+
+	n.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err := n.conn.Write(msg)
+	return err
+}
+
+// Sync waits until the records queued so far are sent, for as long as it may
+// take to send a record.
+func (n *netWriter) Sync() error {
+	timeout := time.NewTimer(dialTimeout + writeTimeout)
+	defer timeout.Stop()
+
+	synced := make(chan struct{})
+	select {
+	case n.queue <- netRecord{synced: synced}:
+	case <-n.done:
+		return nil
+	case <-timeout.C:
+		return errors.Errorf("timed out syncing log records to %s", n.address)
+	}
+	select {
+	case <-synced:
+		return nil
+	case <-n.stopped:
+		return nil
+	case <-timeout.C:
+		return errors.Errorf("timed out syncing log records to %s", n.address)
+	}
+}
+
+// Close stops sending records, and drops the records that are still queued.
+func (n *netWriter) Close() error {
+	n.closeOnce.Do(func() { close(n.done) })
+	<-n.stopped
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNetWriterDropsRecords(t *testing.T) {
+	// nothing listens on the address once the listener is closed, hence records cannot be sent
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := lis.Addr().String()
+	lis.Close()
+
+	w, err := newRemoteWriter("tcp", address)
+	require.NoError(t, err)
+	defer w.Close()
+
+	var errs int
+	for i := 0; i < 2*netQueueSize; i++ {
+		if err := w.WriteEntry(zapcore.Entry{}, []byte("record")); err != nil {
+			require.Contains(t, err.Error(), "dropped log record, as the queue of records to "+address+" is full")
+			errs++
+		}
+	}
+
+	dropped := atomic.LoadUint64(&w.dropped)
+	require.GreaterOrEqual(t, dropped, uint64(netQueueSize-1))
+	// an error is reported for the first dropped record and every power of two of dropped records
+	require.Less(t, errs, 12)
+	require.Greater(t, errs, 0)
+
+	require.NoError(t, w.Close())
+	err = w.WriteEntry(zapcore.Entry{}, []byte("record"))
+	require.EqualError(t, err, "connection to "+address+" is closed")
+}
+
+func TestNetWriterSync(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+
+	w, err := newRemoteWriter("udp", conn.LocalAddr().String())
+	require.NoError(t, err)
+	defer w.Close()
+
+	require.NoError(t, w.WriteEntry(zapcore.Entry{}, []byte("record")))
+	require.NoError(t, w.Sync())
+	require.Empty(t, w.queue)
+
+	buf := make([]byte, 1024)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	require.Equal(t, "record\n", string(buf[:n]))
+}
+
+func TestNextReconnectBackoff(t *testing.T) {
+	require.Equal(t, minReconnectBackoff, nextReconnectBackoff(0))
+	require.Equal(t, 2*minReconnectBackoff, nextReconnectBackoff(minReconnectBackoff))
+	require.Equal(t, maxReconnectBackoff, nextReconnectBackoff(maxReconnectBackoff))
+	require.Equal(t, maxReconnectBackoff, nextReconnectBackoff(3*maxReconnectBackoff/4))
+}
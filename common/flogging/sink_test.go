@@ -0,0 +1,314 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package flogging_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/stretchr/testify/require"
+)
+
+func readFile(t *testing.T, path string) string {
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestSinks(t *testing.T) {
+	dir := t.TempDir()
+	buf := &bytes.Buffer{}
+	logging, err := flogging.New(flogging.Config{
+		Format:  "%{module} %{level} %{message}",
+		LogSpec: "info",
+		Writer:  buf,
+		Sinks: []flogging.SinkConfig{
+			{
+				Name:    "gossip",
+				Type:    flogging.FileSink,
+				Spec:    "debug",
+				Format:  "%{module} %{level} %{message}",
+				Loggers: []string{"gossip"},
+				File:    filepath.Join(dir, "gossip", "gossip.log"),
+			},
+			{
+				Name:   "all",
+				Type:   flogging.FileSink,
+				Format: "%{module} %{level} %{message}",
+				File:   filepath.Join(dir, "all.log"),
+			},
+		},
+	})
+	require.NoError(t, err)
+	defer logging.SetSinks(nil)
+
+	gossipLogger := logging.Logger("gossip.comm")
+	gossipLogger.Debug("debug message")
+	gossipLogger.With("key", "value").Info("info message")
+	logging.Logger("gossipy").Info("not gossip")
+	logging.Logger("peer").Debug("not enabled")
+
+	require.Equal(t, "gossip.comm INFO info message key=value\ngossipy INFO not gossip\n", buf.String())
+	require.Equal(t, "gossip.comm DEBUG debug message\ngossip.comm INFO info message key=value\n", readFile(t, filepath.Join(dir, "gossip", "gossip.log")))
+	require.Equal(t, buf.String(), readFile(t, filepath.Join(dir, "all.log")))
+
+	t.Run("sink levels", func(t *testing.T) {
+		require.True(t, logging.Enabled(flogging.NameToLevel("debug")))
+		require.True(t, logging.Logger("peer").IsEnabledFor(flogging.NameToLevel("debug")))
+		require.NoError(t, logging.ActivateSinkSpecs(map[string]flogging.SinkSpec{"gossip": {Spec: "info"}}))
+		require.False(t, logging.Enabled(flogging.NameToLevel("debug")))
+	})
+
+	t.Run("activate sink specs", func(t *testing.T) {
+		require.Equal(t, map[string]flogging.SinkSpec{
+			"gossip": {Spec: "info"},
+			"all":    {Format: "%{module} %{level} %{message}"},
+		}, logging.SinkSpecs())
+
+		err := logging.ActivateSinkSpecs(map[string]flogging.SinkSpec{"all": {Spec: "peer=debug:warn", Format: "json"}})
+		require.NoError(t, err)
+		require.Equal(t, flogging.SinkSpec{Spec: "peer=debug:warn", Format: "json"}, logging.SinkSpecs()["all"])
+
+		logging.Logger("peer").Debug("debug message")
+		lines := strings.Split(strings.TrimSpace(readFile(t, filepath.Join(dir, "all.log"))), "\n")
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(lines[len(lines)-1]), &record))
+		require.Equal(t, "debug message", record["msg"])
+		require.Equal(t, "peer", record["name"])
+	})
+
+	t.Run("invalid sink specs", func(t *testing.T) {
+		err := logging.ActivateSinkSpecs(map[string]flogging.SinkSpec{"missing": {Spec: "debug"}})
+		require.EqualError(t, err, "log sink missing does not exist")
+
+		err = logging.ActivateSinkSpecs(map[string]flogging.SinkSpec{
+			"gossip": {Spec: "debug"},
+			"all":    {Spec: "bogus"},
+		})
+		require.EqualError(t, err, "log sink all: invalid logging specification 'bogus': bad segment 'bogus'")
+
+		err = logging.ActivateSinkSpecs(map[string]flogging.SinkSpec{"all": {Format: "%{color:bad}"}})
+		require.EqualError(t, err, "invalid format for log sink all: invalid color option: bad")
+
+		// none of the specs have been activated
+		require.Equal(t, flogging.SinkSpec{Spec: "info"}, logging.SinkSpecs()["gossip"])
+		require.Equal(t, flogging.SinkSpec{Spec: "peer=debug:warn", Format: "json"}, logging.SinkSpecs()["all"])
+	})
+}
+
+func TestSetSinksErrors(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "peer.log")
+	tests := []struct {
+		name   string
+		config flogging.SinkConfig
+		err    string
+	}{
+		{name: "invalid name", config: flogging.SinkConfig{Name: "a b", Type: flogging.FileSink, File: file}, err: "invalid log sink name 'a b'"},
+		{name: "invalid logger", config: flogging.SinkConfig{Name: "a", Type: flogging.FileSink, File: file, Loggers: []string{".gossip"}}, err: "invalid logger name '.gossip' for log sink a"},
+		{name: "invalid spec", config: flogging.SinkConfig{Name: "a", Type: flogging.FileSink, File: file, Spec: "gossip=bogus"}, err: "log sink a: invalid logging specification 'gossip=bogus': bad segment 'gossip=bogus'"},
+		{name: "unknown type", config: flogging.SinkConfig{Name: "a", Type: "kafka"}, err: "failed to create log sink a: unknown type 'kafka'"},
+		{name: "no file", config: flogging.SinkConfig{Name: "a", Type: flogging.FileSink}, err: "failed to create log sink a: no log file specified"},
+		{name: "unknown network", config: flogging.SinkConfig{Name: "a", Type: flogging.RemoteSink, Network: "unix", Address: "127.0.0.1:514"}, err: "failed to create log sink a: unsupported network 'unix', must be udp or tcp"},
+		{name: "unknown facility", config: flogging.SinkConfig{Name: "a", Type: flogging.SyslogSink, Network: "udp", Address: "127.0.0.1:514", Facility: "local9"}, err: "failed to create log sink a: unknown syslog facility 'local9'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logging, err := flogging.New(flogging.Config{})
+			require.NoError(t, err)
+			err = logging.SetSinks([]flogging.SinkConfig{tt.config})
+			require.EqualError(t, err, tt.err)
+			require.Empty(t, logging.SinkSpecs())
+		})
+	}
+
+	t.Run("duplicate name", func(t *testing.T) {
+		logging, err := flogging.New(flogging.Config{})
+		require.NoError(t, err)
+		sink := flogging.SinkConfig{Name: "a", Type: flogging.FileSink, File: file}
+		err = logging.SetSinks([]flogging.SinkConfig{sink, sink})
+		require.EqualError(t, err, "duplicate log sink name 'a'")
+	})
+}
+
+func TestFileSinkRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "peer.log")
+
+	t.Run("size", func(t *testing.T) {
+		logging, err := flogging.New(flogging.Config{
+			Writer: &bytes.Buffer{},
+			Sinks:  []flogging.SinkConfig{{Name: "file", Type: flogging.FileSink, Format: "%{message}", File: path, MaxSize: 1, MaxBackups: 2}},
+		})
+		require.NoError(t, err)
+		defer logging.SetSinks(nil)
+
+		// every record makes the file grow beyond the maximum size, hence every record is written to a new file
+		message := strings.Repeat("x", 600*1024)
+		logger := logging.Logger("test")
+		for i := 0; i < 4; i++ {
+			logger.Info(message)
+		}
+
+		require.Equal(t, message+"\n", readFile(t, path))
+		backups, err := filepath.Glob(path + ".*")
+		require.NoError(t, err)
+		require.Len(t, backups, 2)
+		for _, b := range backups {
+			require.Equal(t, message+"\n", readFile(t, b))
+		}
+	})
+
+	t.Run("time", func(t *testing.T) {
+		path := filepath.Join(dir, "orderer.log")
+		logging, err := flogging.New(flogging.Config{
+			Writer: &bytes.Buffer{},
+			Sinks:  []flogging.SinkConfig{{Name: "file", Type: flogging.FileSink, Format: "%{message}", File: path, RotationInterval: 200 * time.Millisecond}},
+		})
+		require.NoError(t, err)
+		defer logging.SetSinks(nil)
+
+		logger := logging.Logger("test")
+		logger.Info("first")
+		logger.Info("second")
+		time.Sleep(300 * time.Millisecond)
+		logger.Info("third")
+
+		require.Equal(t, "third\n", readFile(t, path))
+		backups, err := filepath.Glob(path + ".*")
+		require.NoError(t, err)
+		require.Len(t, backups, 1)
+		require.Equal(t, "first\nsecond\n", readFile(t, backups[0]))
+	})
+}
+
+func TestSyslogSink(t *testing.T) {
+	t.Run("udp", func(t *testing.T) {
+		conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer conn.Close()
+
+		logging, err := flogging.New(flogging.Config{
+			Writer: &bytes.Buffer{},
+			Sinks: []flogging.SinkConfig{{
+				Name:     "syslog",
+				Type:     flogging.SyslogSink,
+				Format:   "%{message}",
+				Network:  "udp",
+				Address:  conn.LocalAddr().String(),
+				Facility: "local0",
+				AppName:  "peer",
+			}},
+		})
+		require.NoError(t, err)
+		defer logging.SetSinks(nil)
+
+		logging.Logger("gossip.comm").Warn("warning message")
+
+		buf := make([]byte, 1024)
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+
+		// <local0*8+warning>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+		fields := strings.SplitN(string(buf[:n]), " ", 8)
+		require.Len(t, fields, 8)
+		require.Equal(t, "<132>1", fields[0])
+		_, err = time.Parse(time.RFC3339Nano, fields[1])
+		require.NoError(t, err)
+		require.Equal(t, "peer", fields[3])
+		require.Equal(t, strconv.Itoa(os.Getpid()), fields[4])
+		require.Equal(t, "gossip.comm", fields[5])
+		require.Equal(t, "-", fields[6])
+		require.Equal(t, "warning message", fields[7])
+	})
+
+	t.Run("tcp", func(t *testing.T) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer lis.Close()
+
+		logging, err := flogging.New(flogging.Config{
+			Writer: &bytes.Buffer{},
+			Sinks: []flogging.SinkConfig{{
+				Name:    "syslog",
+				Type:    flogging.SyslogSink,
+				Format:  "%{message}",
+				Network: "tcp",
+				Address: lis.Addr().String(),
+			}},
+		})
+		require.NoError(t, err)
+		defer logging.SetSinks(nil)
+
+		logger := logging.Logger("orderer")
+		logger.Error("first")
+		logger.Info("second")
+
+		conn, err := lis.Accept()
+		require.NoError(t, err)
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		r := bufio.NewReader(conn)
+
+		// messages are framed by octet counting
+		for _, expected := range []struct{ pri, msg string }{{"<11>1", "first"}, {"<14>1", "second"}} {
+			length, err := r.ReadString(' ')
+			require.NoError(t, err)
+			n, err := strconv.Atoi(strings.TrimSpace(length))
+			require.NoError(t, err)
+			msg := make([]byte, n)
+			_, err = io.ReadFull(r, msg)
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(string(msg), expected.pri+" "), "message %q", msg)
+			require.True(t, strings.HasSuffix(string(msg), " orderer - "+expected.msg), "message %q", msg)
+		}
+	})
+}
+
+func TestRemoteSink(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	logging, err := flogging.New(flogging.Config{
+		Writer: &bytes.Buffer{},
+		Sinks: []flogging.SinkConfig{{
+			Name:    "collector",
+			Type:    flogging.RemoteSink,
+			Format:  "json",
+			Network: "tcp",
+			Address: lis.Addr().String(),
+		}},
+	})
+	require.NoError(t, err)
+	defer logging.SetSinks(nil)
+
+	logging.Logger("peer").Infow("info message", "channel", "mychannel")
+
+	conn, err := lis.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	require.NoError(t, err)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(line, &record))
+	require.Equal(t, "info message", record["msg"])
+	require.Equal(t, "mychannel", record["channel"])
+	require.Equal(t, "peer", record["name"])
+}
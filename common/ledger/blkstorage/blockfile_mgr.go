@@ -173,7 +173,30 @@ func bootstrapFromSnapshottedTxIDs(
 	conf *Conf,
 	indexStore *leveldbhelper.DBHandle,
 ) error {
-	rootDir := conf.getLedgerBlockDir(ledgerID)
+	if err := writeBootstrappingSnapshotInfo(conf.getLedgerBlockDir(ledgerID), snapshotInfo); err != nil {
+		return err
+	}
+	if err := importTxIDsFromSnapshot(snapshotDir, snapshotInfo.LastBlockNum, indexStore); err != nil {
+		return err
+	}
+	return nil
+}
+
+func bootstrapFromCheckpoint(
+	ledgerID string,
+	checkpointInfo *SnapshotInfo,
+	conf *Conf,
+	indexStore *leveldbhelper.DBHandle,
+) error {
+	if err := writeBootstrappingSnapshotInfo(conf.getLedgerBlockDir(ledgerID), checkpointInfo); err != nil {
+		return err
+	}
+	batch := indexStore.NewUpdateBatch()
+	batch.Put(indexSavePointKey, encodeBlockNum(checkpointInfo.LastBlockNum))
+	return indexStore.WriteBatch(batch, true)
+}
+
+func writeBootstrappingSnapshotInfo(rootDir string, snapshotInfo *SnapshotInfo) error {
 	isEmpty, err := fileutil.CreateDirIfMissing(rootDir)
 	if err != nil {
 		return err
@@ -202,13 +225,7 @@ func bootstrapFromSnapshottedTxIDs(
 	); err != nil {
 		return err
 	}
-	if err := fileutil.SyncDir(rootDir); err != nil {
-		return err
-	}
-	if err := importTxIDsFromSnapshot(snapshotDir, snapshotInfo.LastBlockNum, indexStore); err != nil {
-		return err
-	}
-	return nil
+	return fileutil.SyncDir(rootDir)
 }
 
 func syncBlockfilesInfoFromFS(rootDir string, blkfilesInfo *blockfilesInfo) {
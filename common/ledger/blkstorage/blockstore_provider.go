@@ -112,6 +112,20 @@ func (p *BlockStoreProvider) ImportFromSnapshot(
 	return nil
 }
 
+// ImportFromCheckpoint initializes a blockstore whose first block is the block
+// following the one described by checkpointInfo, without importing the
+// transaction IDs of the preceding blocks. It allows the orderer, which does
+// not index transaction IDs, to start the ledger of a channel from a trusted
+// block rather than from the genesis block. As with ImportFromSnapshot, the
+// consumer is expected to cleanup the data after a failure.
+func (p *BlockStoreProvider) ImportFromCheckpoint(
+	ledgerID string,
+	checkpointInfo *SnapshotInfo,
+) error {
+	indexStoreHandle := p.leveldbProvider.GetDBHandle(ledgerID)
+	return bootstrapFromCheckpoint(ledgerID, checkpointInfo, p.conf, indexStoreHandle)
+}
+
 // Exists tells whether the BlockStore with given id exists
 func (p *BlockStoreProvider) Exists(ledgerid string) (bool, error) {
 	exists, err := fileutil.DirExists(p.conf.getLedgerBlockDir(ledgerid))
@@ -325,6 +325,60 @@ func TestImportFromSnapshot(t *testing.T) {
 	})
 }
 
+func TestImportFromCheckpoint(t *testing.T) {
+	testDir := t.TempDir()
+	env := newTestEnv(t, NewConf(testDir, 0))
+	defer env.Cleanup()
+
+	blocks := testutil.ConstructTestBlocks(t, 5)
+	checkpoint := blocks[3]
+	checkpointInfo := &SnapshotInfo{
+		LastBlockNum:  checkpoint.Header.Number - 1,
+		LastBlockHash: checkpoint.Header.PreviousHash,
+	}
+
+	require.NoError(t, env.provider.ImportFromCheckpoint("checkpointLedger", checkpointInfo))
+	err := env.provider.ImportFromCheckpoint("checkpointLedger", checkpointInfo)
+	require.EqualError(t, err, fmt.Sprintf("dir %s not empty", filepath.Join(testDir, "chains", "checkpointLedger")))
+
+	blockStore, err := env.provider.Open("checkpointLedger")
+	require.NoError(t, err)
+	bcInfo, err := blockStore.GetBlockchainInfo()
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), bcInfo.Height)
+	require.Equal(t, uint64(2), bcInfo.BootstrappingSnapshotInfo.LastBlockInSnapshot)
+
+	require.EqualError(t, blockStore.AddBlock(blocks[4]), "block number should have been 3 but was 4")
+	require.NoError(t, blockStore.AddBlock(checkpoint))
+	require.NoError(t, blockStore.AddBlock(blocks[4]))
+	bcInfo, err = blockStore.GetBlockchainInfo()
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), bcInfo.Height)
+	require.Equal(t, protoutil.BlockHeaderHash(blocks[4].Header), bcInfo.CurrentBlockHash)
+
+	block, err := blockStore.RetrieveBlockByNumber(3)
+	require.NoError(t, err)
+	require.Equal(t, checkpoint, block)
+	_, err = blockStore.RetrieveBlockByNumber(2)
+	require.EqualError(t, err, "cannot serve block [2]. The ledger is bootstrapped from a snapshot. First available block = [3]")
+	_, err = blockStore.RetrieveBlocks(0)
+	require.EqualError(t, err, "cannot serve block [0]. The ledger is bootstrapped from a snapshot. First available block = [3]")
+	itr, err := blockStore.RetrieveBlocks(3)
+	require.NoError(t, err)
+	defer itr.Close()
+	result, err := itr.Next()
+	require.NoError(t, err)
+	require.Equal(t, checkpoint, result)
+
+	// the index of the blockstore is in sync after a restart
+	blockStore.Shutdown()
+	blockStore, err = env.provider.Open("checkpointLedger")
+	require.NoError(t, err)
+	block, err = blockStore.RetrieveBlockByNumber(4)
+	require.NoError(t, err)
+	require.Equal(t, blocks[4], block)
+}
+
 func TestBootstrapFromSnapshotErrorPaths(t *testing.T) {
 	testPath := t.TempDir()
 	env := newTestEnv(t, NewConf(testPath, 0))
@@ -11,15 +11,18 @@ import (
 	"path/filepath"
 	"sync"
 
+	cb "github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/orderer/common/filerepo"
+	"github.com/pkg/errors"
 )
 
 //go:generate counterfeiter -o mock/block_store_provider.go --fake-name BlockStoreProvider . blockStoreProvider
 type blockStoreProvider interface {
 	Open(ledgerid string) (*blkstorage.BlockStore, error)
+	ImportFromCheckpoint(ledgerID string, checkpointInfo *blkstorage.SnapshotInfo) error
 	Drop(ledgerid string) error
 	List() ([]string, error)
 	Close()
@@ -53,6 +56,40 @@ func (f *fileLedgerFactory) GetOrCreate(channelID string) (blockledger.ReadWrite
 	return ledger, nil
 }
 
+// CreateFromCheckpoint creates a ledger whose first block is the given
+// checkpoint block. The blocks preceding the checkpoint are never available
+// from the ledger. It fails if the ledger already exists.
+func (f *fileLedgerFactory) CreateFromCheckpoint(channelID string, checkpoint *cb.Block) (blockledger.ReadWriter, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if _, ok := f.ledgers[channelID]; ok {
+		return nil, errors.Errorf("ledger of channel %s already exists", channelID)
+	}
+	if checkpoint.GetHeader().GetNumber() == 0 {
+		return nil, errors.New("the checkpoint block cannot be the genesis block")
+	}
+
+	err := f.blkstorageProvider.ImportFromCheckpoint(channelID, &blkstorage.SnapshotInfo{
+		LastBlockNum:  checkpoint.Header.Number - 1,
+		LastBlockHash: checkpoint.Header.PreviousHash,
+	})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to bootstrap ledger of channel %s from checkpoint", channelID)
+	}
+	blockStore, err := f.blkstorageProvider.Open(channelID)
+	if err != nil {
+		return nil, err
+	}
+	ledger := NewFileLedger(blockStore)
+	if err := ledger.Append(checkpoint); err != nil {
+		blockStore.Shutdown()
+		return nil, errors.WithMessage(err, "failed to append checkpoint block")
+	}
+	f.ledgers[channelID] = ledger
+	return ledger, nil
+}
+
 // Remove removes an existing ledger and its indexes. This operation
 // is blocking.
 func (f *fileLedgerFactory) Remove(channelID string) error {
@@ -69,7 +69,14 @@ func (fl *FileLedger) Iterator(startPosition *ab.SeekPosition) (blockledger.Iter
 	var startingBlockNumber uint64
 	switch start := startPosition.Type.(type) {
 	case *ab.SeekPosition_Oldest:
-		startingBlockNumber = 0
+		info, err := fl.blockStore.GetBlockchainInfo()
+		if err != nil {
+			logger.Panic(err)
+		}
+		// a ledger bootstrapped from a checkpoint starts after the snapshot
+		if info.BootstrappingSnapshotInfo != nil {
+			startingBlockNumber = info.BootstrappingSnapshotInfo.LastBlockInSnapshot + 1
+		}
 	case *ab.SeekPosition_Newest:
 		info, err := fl.blockStore.GetBlockchainInfo()
 		if err != nil {
@@ -23,6 +23,18 @@ type BlockStoreProvider struct {
 	dropReturnsOnCall map[int]struct {
 		result1 error
 	}
+	ImportFromCheckpointStub        func(string, *blkstorage.SnapshotInfo) error
+	importFromCheckpointMutex       sync.RWMutex
+	importFromCheckpointArgsForCall []struct {
+		arg1 string
+		arg2 *blkstorage.SnapshotInfo
+	}
+	importFromCheckpointReturns struct {
+		result1 error
+	}
+	importFromCheckpointReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ListStub        func() ([]string, error)
 	listMutex       sync.RWMutex
 	listArgsForCall []struct {
@@ -56,9 +68,10 @@ func (fake *BlockStoreProvider) Close() {
 	fake.closeMutex.Lock()
 	fake.closeArgsForCall = append(fake.closeArgsForCall, struct {
 	}{})
+	stub := fake.CloseStub
 	fake.recordInvocation("Close", []interface{}{})
 	fake.closeMutex.Unlock()
-	if fake.CloseStub != nil {
+	if stub != nil {
 		fake.CloseStub()
 	}
 }
@@ -81,15 +94,16 @@ func (fake *BlockStoreProvider) Drop(arg1 string) error {
 	fake.dropArgsForCall = append(fake.dropArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.DropStub
+	fakeReturns := fake.dropReturns
 	fake.recordInvocation("Drop", []interface{}{arg1})
 	fake.dropMutex.Unlock()
-	if fake.DropStub != nil {
-		return fake.DropStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.dropReturns
 	return fakeReturns.result1
 }
 
@@ -135,20 +149,83 @@ func (fake *BlockStoreProvider) DropReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *BlockStoreProvider) ImportFromCheckpoint(arg1 string, arg2 *blkstorage.SnapshotInfo) error {
+	fake.importFromCheckpointMutex.Lock()
+	ret, specificReturn := fake.importFromCheckpointReturnsOnCall[len(fake.importFromCheckpointArgsForCall)]
+	fake.importFromCheckpointArgsForCall = append(fake.importFromCheckpointArgsForCall, struct {
+		arg1 string
+		arg2 *blkstorage.SnapshotInfo
+	}{arg1, arg2})
+	stub := fake.ImportFromCheckpointStub
+	fakeReturns := fake.importFromCheckpointReturns
+	fake.recordInvocation("ImportFromCheckpoint", []interface{}{arg1, arg2})
+	fake.importFromCheckpointMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *BlockStoreProvider) ImportFromCheckpointCallCount() int {
+	fake.importFromCheckpointMutex.RLock()
+	defer fake.importFromCheckpointMutex.RUnlock()
+	return len(fake.importFromCheckpointArgsForCall)
+}
+
+func (fake *BlockStoreProvider) ImportFromCheckpointCalls(stub func(string, *blkstorage.SnapshotInfo) error) {
+	fake.importFromCheckpointMutex.Lock()
+	defer fake.importFromCheckpointMutex.Unlock()
+	fake.ImportFromCheckpointStub = stub
+}
+
+func (fake *BlockStoreProvider) ImportFromCheckpointArgsForCall(i int) (string, *blkstorage.SnapshotInfo) {
+	fake.importFromCheckpointMutex.RLock()
+	defer fake.importFromCheckpointMutex.RUnlock()
+	argsForCall := fake.importFromCheckpointArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *BlockStoreProvider) ImportFromCheckpointReturns(result1 error) {
+	fake.importFromCheckpointMutex.Lock()
+	defer fake.importFromCheckpointMutex.Unlock()
+	fake.ImportFromCheckpointStub = nil
+	fake.importFromCheckpointReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *BlockStoreProvider) ImportFromCheckpointReturnsOnCall(i int, result1 error) {
+	fake.importFromCheckpointMutex.Lock()
+	defer fake.importFromCheckpointMutex.Unlock()
+	fake.ImportFromCheckpointStub = nil
+	if fake.importFromCheckpointReturnsOnCall == nil {
+		fake.importFromCheckpointReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.importFromCheckpointReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *BlockStoreProvider) List() ([]string, error) {
 	fake.listMutex.Lock()
 	ret, specificReturn := fake.listReturnsOnCall[len(fake.listArgsForCall)]
 	fake.listArgsForCall = append(fake.listArgsForCall, struct {
 	}{})
+	stub := fake.ListStub
+	fakeReturns := fake.listReturns
 	fake.recordInvocation("List", []interface{}{})
 	fake.listMutex.Unlock()
-	if fake.ListStub != nil {
-		return fake.ListStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.listReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -196,15 +273,16 @@ func (fake *BlockStoreProvider) Open(arg1 string) (*blkstorage.BlockStore, error
 	fake.openArgsForCall = append(fake.openArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.OpenStub
+	fakeReturns := fake.openReturns
 	fake.recordInvocation("Open", []interface{}{arg1})
 	fake.openMutex.Unlock()
-	if fake.OpenStub != nil {
-		return fake.OpenStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.openReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -260,6 +338,8 @@ func (fake *BlockStoreProvider) Invocations() map[string][][]interface{} {
 	defer fake.closeMutex.RUnlock()
 	fake.dropMutex.RLock()
 	defer fake.dropMutex.RUnlock()
+	fake.importFromCheckpointMutex.RLock()
+	defer fake.importFromCheckpointMutex.RUnlock()
 	fake.listMutex.RLock()
 	defer fake.listMutex.RUnlock()
 	fake.openMutex.RLock()
@@ -17,6 +17,10 @@ type Factory interface {
 	// or creates it if it does not
 	GetOrCreate(channelID string) (ReadWriter, error)
 
+	// CreateFromCheckpoint creates a ledger whose first block is the given
+	// checkpoint block rather than the genesis block
+	CreateFromCheckpoint(channelID string, checkpoint *cb.Block) (ReadWriter, error)
+
 	// Remove removes an existing ledger
 	Remove(channelID string) error
 
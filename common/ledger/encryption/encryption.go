@@ -0,0 +1,225 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package encryption implements the envelope encryption of the values kept at
+// rest by the ledger stores. Each value is encrypted with AES-GCM under a data
+// key of its own, which is in turn encrypted (wrapped) with a key encryption key
+// generated and kept by a BCCSP. The envelope records the subject key identifier
+// of the key encryption key, so that the key encryption key can be rotated while
+// the values encrypted before the rotation remain readable for as long as the
+// BCCSP retains their key. The Keyring instead shares a data key among the
+// values of a scope, for stores that encrypt too many values to involve the
+// BCCSP in each of them.
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/pkg/errors"
+)
+
+const (
+	envelopeVersion = byte(1)
+	dataKeySize     = 32
+)
+
+// MetadataStore persists the subject key identifier of the current key
+// encryption key. It is typically the database handle of the store whose
+// values are encrypted.
+type MetadataStore interface {
+	Get(key []byte) ([]byte, error)
+	Put(key []byte, value []byte, sync bool) error
+}
+
+// Encryptor encrypts and decrypts values with the current key encryption key.
+type Encryptor struct {
+	csp         bccsp.BCCSP
+	store       MetadataStore
+	metadataKey []byte
+
+	mutex   sync.RWMutex
+	current bccsp.Key
+	keys    map[string]bccsp.Key
+}
+
+// NewEncryptor returns an Encryptor whose current key encryption key is the one
+// recorded in the store under the metadata key. A key is generated by the BCCSP
+// and recorded if there is none.
+func NewEncryptor(csp bccsp.BCCSP, store MetadataStore, metadataKey []byte) (*Encryptor, error) {
+	e := &Encryptor{
+		csp:         csp,
+		store:       store,
+		metadataKey: metadataKey,
+		keys:        map[string]bccsp.Key{},
+	}
+	ski, err := store.Get(metadataKey)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error while reading the current key encryption key")
+	}
+	if ski == nil {
+		if _, err := e.RotateKey(); err != nil {
+			return nil, err
+		}
+		return e, nil
+	}
+	if e.current, err = e.key(ski); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// RotateKey generates a new key encryption key, with which the values are
+// encrypted from then on, and returns its subject key identifier.
+func (e *Encryptor) RotateKey() ([]byte, error) {
+	k, err := e.csp.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: false})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error while generating a key encryption key")
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if err := e.store.Put(e.metadataKey, k.SKI(), true); err != nil {
+		return nil, errors.WithMessage(err, "error while recording the current key encryption key")
+	}
+	e.current = k
+	e.keys[hex.EncodeToString(k.SKI())] = k
+	return k.SKI(), nil
+}
+
+// CurrentKeySKI returns the subject key identifier of the current key
+// encryption key.
+func (e *Encryptor) CurrentKeySKI() []byte {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	return e.current.SKI()
+}
+
+// Encrypt returns the envelope of the plaintext. The additional data, such as
+// the database key of the value, is authenticated but not encrypted, and must
+// be supplied again to decrypt the envelope.
+func (e *Encryptor) Encrypt(plaintext, additionalData []byte) ([]byte, error) {
+	e.mutex.RLock()
+	kek := e.current
+	e.mutex.RUnlock()
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, errors.Wrap(err, "error while generating a data key")
+	}
+	wrappedKey, err := e.csp.Encrypt(kek, dataKey, &bccsp.AESCBCPKCS7ModeOpts{})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error while wrapping the data key")
+	}
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "error while generating a nonce")
+	}
+
+	ski := kek.SKI()
+	envelope := make([]byte, 0, 4+len(ski)+len(wrappedKey)+len(nonce)+len(plaintext)+gcm.Overhead())
+	envelope = append(envelope, envelopeVersion, byte(len(ski)))
+	envelope = append(envelope, ski...)
+	envelope = binary.BigEndian.AppendUint16(envelope, uint16(len(wrappedKey)))
+	envelope = append(envelope, wrappedKey...)
+	envelope = append(envelope, nonce...)
+	return gcm.Seal(envelope, nonce, plaintext, additionalData), nil
+}
+
+// Decrypt returns the plaintext of the envelope, which must have been
+// encrypted with the same additional data.
+func (e *Encryptor) Decrypt(envelope, additionalData []byte) ([]byte, error) {
+	ski, wrappedKey, sealed, err := splitEnvelope(envelope)
+	if err != nil {
+		return nil, err
+	}
+	kek, err := e.key(ski)
+	if err != nil {
+		return nil, err
+	}
+	// the BCCSP may decrypt in place, so the envelope is left untouched
+	wrappedKey = append([]byte(nil), wrappedKey...)
+	dataKey, err := e.csp.Decrypt(kek, wrappedKey, &bccsp.AESCBCPKCS7ModeOpts{})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error while unwrapping the data key")
+	}
+	gcm, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("envelope is truncated")
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while decrypting the envelope")
+	}
+	return plaintext, nil
+}
+
+// KeySKI returns the subject key identifier of the key encryption key that
+// wrapped the data key of the envelope.
+func KeySKI(envelope []byte) ([]byte, error) {
+	ski, _, _, err := splitEnvelope(envelope)
+	return ski, err
+}
+
+func (e *Encryptor) key(ski []byte) (bccsp.Key, error) {
+	id := hex.EncodeToString(ski)
+	e.mutex.RLock()
+	k, ok := e.keys[id]
+	e.mutex.RUnlock()
+	if ok {
+		return k, nil
+	}
+	k, err := e.csp.GetKey(ski)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "key encryption key [%s] is not available", id)
+	}
+	if !k.Symmetric() {
+		return nil, errors.Errorf("key [%s] is not a key encryption key", id)
+	}
+	e.mutex.Lock()
+	e.keys[id] = k
+	e.mutex.Unlock()
+	return k, nil
+}
+
+func splitEnvelope(envelope []byte) (ski, wrappedKey, sealed []byte, err error) {
+	if len(envelope) < 2 || envelope[0] != envelopeVersion {
+		return nil, nil, nil, errors.New("value is not an envelope")
+	}
+	skiEnd := 2 + int(envelope[1])
+	if len(envelope) < skiEnd+2 {
+		return nil, nil, nil, errors.New("envelope is truncated")
+	}
+	wrappedKeyEnd := skiEnd + 2 + int(binary.BigEndian.Uint16(envelope[skiEnd:]))
+	if len(envelope) < wrappedKeyEnd {
+		return nil, nil, nil, errors.New("envelope is truncated")
+	}
+	return envelope[2:skiEnd], envelope[skiEnd+2 : wrappedKeyEnd], envelope[wrappedKeyEnd:], nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while creating the data cipher")
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while creating the data cipher")
+	}
+	return gcm, nil
+}
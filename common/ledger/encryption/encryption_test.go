@@ -0,0 +1,150 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package encryption
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type metadataStore map[string][]byte
+
+func (s metadataStore) Get(key []byte) ([]byte, error) {
+	return s[string(key)], nil
+}
+
+func (s metadataStore) Put(key []byte, value []byte, sync bool) error {
+	s[string(key)] = value
+	return nil
+}
+
+func newTestCSP(t *testing.T) bccsp.BCCSP {
+	csp, err := sw.NewDefaultSecurityLevel(t.TempDir())
+	require.NoError(t, err)
+	return csp
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	store := metadataStore{}
+	e, err := NewEncryptor(newTestCSP(t), store, []byte("kek"))
+	require.NoError(t, err)
+	require.Equal(t, e.CurrentKeySKI(), store["kek"])
+
+	envelope, err := e.Encrypt([]byte("plaintext"), []byte("key1"))
+	require.NoError(t, err)
+	require.NotContains(t, string(envelope), "plaintext")
+	ski, err := KeySKI(envelope)
+	require.NoError(t, err)
+	require.Equal(t, e.CurrentKeySKI(), ski)
+
+	plaintext, err := e.Decrypt(envelope, []byte("key1"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("plaintext"), plaintext)
+
+	other, err := e.Encrypt([]byte("plaintext"), []byte("key1"))
+	require.NoError(t, err)
+	require.NotEqual(t, envelope, other)
+
+	t.Run("empty plaintext", func(t *testing.T) {
+		envelope, err := e.Encrypt(nil, nil)
+		require.NoError(t, err)
+		plaintext, err := e.Decrypt(envelope, nil)
+		require.NoError(t, err)
+		require.Empty(t, plaintext)
+	})
+
+	t.Run("wrong additional data", func(t *testing.T) {
+		_, err := e.Decrypt(envelope, []byte("key2"))
+		require.EqualError(t, err, "error while decrypting the envelope: cipher: message authentication failed")
+	})
+
+	t.Run("tampered envelope", func(t *testing.T) {
+		tampered := append([]byte(nil), envelope...)
+		tampered[len(tampered)-1] ^= 1
+		_, err := e.Decrypt(tampered, []byte("key1"))
+		require.EqualError(t, err, "error while decrypting the envelope: cipher: message authentication failed")
+	})
+
+	t.Run("malformed envelope", func(t *testing.T) {
+		_, err := e.Decrypt([]byte("plaintext"), nil)
+		require.EqualError(t, err, "value is not an envelope")
+		_, err = e.Decrypt(envelope[:10], nil)
+		require.EqualError(t, err, "envelope is truncated")
+		_, err = KeySKI(nil)
+		require.EqualError(t, err, "value is not an envelope")
+	})
+}
+
+func TestRotateKey(t *testing.T) {
+	csp := newTestCSP(t)
+	store := metadataStore{}
+	e, err := NewEncryptor(csp, store, []byte("kek"))
+	require.NoError(t, err)
+	firstSKI := e.CurrentKeySKI()
+	envelope, err := e.Encrypt([]byte("before-rotation"), nil)
+	require.NoError(t, err)
+
+	secondSKI, err := e.RotateKey()
+	require.NoError(t, err)
+	require.NotEqual(t, firstSKI, secondSKI)
+	require.Equal(t, secondSKI, e.CurrentKeySKI())
+	require.Equal(t, secondSKI, store["kek"])
+
+	rotatedEnvelope, err := e.Encrypt([]byte("after-rotation"), nil)
+	require.NoError(t, err)
+	ski, err := KeySKI(rotatedEnvelope)
+	require.NoError(t, err)
+	require.Equal(t, secondSKI, ski)
+
+	// a new encryptor uses the recorded key and loads the previous keys from the BCCSP
+	e, err = NewEncryptor(csp, store, []byte("kek"))
+	require.NoError(t, err)
+	require.Equal(t, secondSKI, e.CurrentKeySKI())
+	plaintext, err := e.Decrypt(envelope, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("before-rotation"), plaintext)
+	plaintext, err = e.Decrypt(rotatedEnvelope, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("after-rotation"), plaintext)
+
+	t.Run("key not available", func(t *testing.T) {
+		e, err := NewEncryptor(newTestCSP(t), metadataStore{}, []byte("kek"))
+		require.NoError(t, err)
+		_, err = e.Decrypt(envelope, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not available")
+
+		_, err = NewEncryptor(newTestCSP(t), store, []byte("kek"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not available")
+	})
+}
+
+type failingStore struct{}
+
+func (failingStore) Get(key []byte) ([]byte, error) {
+	return nil, errors.New("get-error")
+}
+
+func (failingStore) Put(key []byte, value []byte, sync bool) error {
+	return errors.New("put-error")
+}
+
+func TestMetadataStoreErrors(t *testing.T) {
+	_, err := NewEncryptor(newTestCSP(t), failingStore{}, []byte("kek"))
+	require.EqualError(t, err, "error while reading the current key encryption key: get-error")
+
+	e, err := NewEncryptor(newTestCSP(t), metadataStore{}, []byte("kek"))
+	require.NoError(t, err)
+	e.store = failingStore{}
+	_, err = e.RotateKey()
+	require.EqualError(t, err, "error while recording the current key encryption key: put-error")
+}
@@ -0,0 +1,455 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package encryption
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/pkg/errors"
+)
+
+const (
+	keyringEnvelopeVersion = byte(2)
+	keyIDSize              = 16
+)
+
+var (
+	keyEncryptionKeyRecord = []byte("kek")
+	dataKeyRecordPrefix    = []byte("key/")
+	currentKeyRecordPrefix = []byte("current/")
+	scopeRecordPrefix      = []byte("scope/")
+	usesRecordPrefix       = []byte("uses/")
+)
+
+var (
+	// maxDataKeyUses is the number of values encrypted with a data key before
+	// the scope is given a new one. AES-GCM with random 96 bit nonces must
+	// not encrypt more than 2^32 values under a key.
+	maxDataKeyUses = uint64(1) << 32
+	// dataKeyUsesReservation is the number of uses of a data key recorded in
+	// the store at a time. The uses reserved but not consumed before a restart
+	// are counted as consumed.
+	dataKeyUsesReservation = uint64(1) << 16
+)
+
+// Keyring encrypts the values of several scopes, such as the private data
+// collections of a channel, each with a data key of its own. Unlike the
+// Encryptor, which wraps a new data key for every value, the Keyring wraps one
+// data key per scope and key encryption key and keeps the unwrapped data keys
+// in memory, so that a BCCSP backed by a hardware security module is not
+// involved in the encryption of every value. The envelope of a value records
+// the ID of its data key, under which the data key is persisted in the store,
+// wrapped with the key encryption key. Since the nonces of the values are
+// random, a scope is given a new data key once its data key has encrypted
+// maxDataKeyUses values.
+type Keyring struct {
+	csp    bccsp.BCCSP
+	store  MetadataStore
+	prefix []byte
+
+	mutex   sync.RWMutex
+	kek     bccsp.Key
+	keks    map[string]bccsp.Key
+	current map[string]*dataKey
+	keys    map[string]*dataKey
+}
+
+type dataKey struct {
+	id     []byte
+	kekSKI []byte
+	aead   cipher.AEAD
+
+	// uses is the number of values encrypted with the data key, which may
+	// not exceed the reserved number of uses recorded in the store.
+	uses     uint64
+	reserved uint64
+}
+
+// use counts a use of the data key, unless all its reserved uses were
+// consumed, and returns whether it was counted.
+func (dk *dataKey) use() bool {
+	if atomic.AddUint64(&dk.uses, 1) <= atomic.LoadUint64(&dk.reserved) {
+		return true
+	}
+	atomic.AddUint64(&dk.uses, ^uint64(0))
+	return false
+}
+
+// KeyringExists returns whether a key encryption key was ever recorded for a
+// keyring in the store under the prefix.
+func KeyringExists(store MetadataStore, prefix []byte) (bool, error) {
+	ski, err := store.Get(append(append([]byte{}, prefix...), keyEncryptionKeyRecord...))
+	if err != nil {
+		return false, errors.WithMessage(err, "error while reading the current key encryption key")
+	}
+	return ski != nil, nil
+}
+
+// NewKeyring returns a Keyring whose records are kept in the store under the
+// prefix. A key encryption key is generated by the BCCSP and recorded if there
+// is none.
+func NewKeyring(csp bccsp.BCCSP, store MetadataStore, prefix []byte) (*Keyring, error) {
+	k := &Keyring{
+		csp:     csp,
+		store:   store,
+		prefix:  prefix,
+		keks:    map[string]bccsp.Key{},
+		current: map[string]*dataKey{},
+		keys:    map[string]*dataKey{},
+	}
+	ski, err := store.Get(k.recordKey(keyEncryptionKeyRecord))
+	if err != nil {
+		return nil, errors.WithMessage(err, "error while reading the current key encryption key")
+	}
+	if ski == nil {
+		if _, err := k.RotateKey(); err != nil {
+			return nil, err
+		}
+		return k, nil
+	}
+	if k.kek, err = k.keyEncryptionKey(ski); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+// RotateKey generates a new key encryption key and returns its subject key
+// identifier. The scopes are given new data keys, wrapped with the new key
+// encryption key, the next time they encrypt a value. The values encrypted
+// before remain readable for as long as the BCCSP retains the former key
+// encryption key, and are expected to be re-encrypted in the meantime.
+func (k *Keyring) RotateKey() ([]byte, error) {
+	kek, err := k.csp.KeyGen(&bccsp.AES256KeyGenOpts{Temporary: false})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error while generating a key encryption key")
+	}
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	if err := k.store.Put(k.recordKey(keyEncryptionKeyRecord), kek.SKI(), true); err != nil {
+		return nil, errors.WithMessage(err, "error while recording the current key encryption key")
+	}
+	k.kek = kek
+	k.keks[hex.EncodeToString(kek.SKI())] = kek
+	k.current = map[string]*dataKey{}
+	return kek.SKI(), nil
+}
+
+// CurrentKeySKI returns the subject key identifier of the current key
+// encryption key.
+func (k *Keyring) CurrentKeySKI() []byte {
+	k.mutex.RLock()
+	defer k.mutex.RUnlock()
+	return k.kek.SKI()
+}
+
+// Encrypt returns the envelope of the plaintext, encrypted with the current
+// data key of the scope. The additional data, such as the database key of the
+// value, is authenticated but not encrypted, and must be supplied again to
+// decrypt the envelope.
+func (k *Keyring) Encrypt(scope string, plaintext, additionalData []byte) ([]byte, error) {
+	dk, err := k.encryptionKey(scope)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, dk.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "error while generating a nonce")
+	}
+	envelope := make([]byte, 0, 2+len(dk.id)+len(nonce)+len(plaintext)+dk.aead.Overhead())
+	envelope = append(envelope, keyringEnvelopeVersion, byte(len(dk.id)))
+	envelope = append(envelope, dk.id...)
+	envelope = append(envelope, nonce...)
+	return dk.aead.Seal(envelope, nonce, plaintext, additionalData), nil
+}
+
+// Decrypt returns the plaintext of the envelope, which must have been
+// encrypted with the same additional data.
+func (k *Keyring) Decrypt(envelope, additionalData []byte) ([]byte, error) {
+	id, sealed, err := splitKeyringEnvelope(envelope)
+	if err != nil {
+		return nil, err
+	}
+	dk, err := k.dataKey(id)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < dk.aead.NonceSize() {
+		return nil, errors.New("envelope is truncated")
+	}
+	nonce, ciphertext := sealed[:dk.aead.NonceSize()], sealed[dk.aead.NonceSize():]
+	plaintext, err := dk.aead.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, errors.Wrap(err, "error while decrypting the envelope")
+	}
+	return plaintext, nil
+}
+
+// IsCurrent returns whether the envelope was encrypted with a data key of the
+// scope wrapped with the current key encryption key, which is to say that it
+// needs no re-encryption.
+func (k *Keyring) IsCurrent(scope string, envelope []byte) (bool, error) {
+	id, _, err := splitKeyringEnvelope(envelope)
+	if err != nil {
+		return false, err
+	}
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	current, err := k.currentKey(scope)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(current.id, id) {
+		return true, nil
+	}
+	// the data keys of the scope which encrypted maxDataKeyUses values are
+	// retired without being re-encrypted
+	dk, err := k.loadDataKey(id)
+	if err != nil || !bytes.Equal(dk.kekSKI, k.kek.SKI()) {
+		return false, nil
+	}
+	dkScope, err := k.store.Get(k.scopeRecordKey(id))
+	if err != nil {
+		return false, errors.WithMessagef(err, "error while reading the scope of data key [%x]", id)
+	}
+	return dkScope != nil && string(dkScope) == scope, nil
+}
+
+// encryptionKey returns the current data key of the scope and counts its use.
+func (k *Keyring) encryptionKey(scope string) (*dataKey, error) {
+	k.mutex.RLock()
+	dk, ok := k.current[scope]
+	k.mutex.RUnlock()
+	if ok && dk.use() {
+		return dk, nil
+	}
+
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	for {
+		dk, err := k.currentKey(scope)
+		if err != nil {
+			return nil, err
+		}
+		if dk.use() {
+			return dk, nil
+		}
+		if err := k.reserveUses(scope, dk); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// reserveUses records more uses of the current data key of the scope, or
+// retires it if it reached maxDataKeyUses. It must be called with the mutex
+// held.
+func (k *Keyring) reserveUses(scope string, dk *dataKey) error {
+	reserved := atomic.LoadUint64(&dk.reserved)
+	if reserved >= maxDataKeyUses {
+		delete(k.current, scope)
+		return nil
+	}
+	reserved += dataKeyUsesReservation
+	if reserved > maxDataKeyUses {
+		reserved = maxDataKeyUses
+	}
+	if err := k.store.Put(k.usesRecordKey(dk.id), encodeUses(reserved), true); err != nil {
+		return errors.WithMessagef(err, "error while recording the uses of the data key of scope [%s]", scope)
+	}
+	atomic.StoreUint64(&dk.reserved, reserved)
+	return nil
+}
+
+// currentKey returns the current data key of the scope, which is generated if
+// there is none. It must be called with the mutex held.
+func (k *Keyring) currentKey(scope string) (*dataKey, error) {
+	if dk, ok := k.current[scope]; ok {
+		return dk, nil
+	}
+	currentRecordKey := k.recordKey(append(append([]byte{}, currentKeyRecordPrefix...), scope...))
+	id, err := k.store.Get(currentRecordKey)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error while reading the current data key of scope [%s]", scope)
+	}
+	if id != nil {
+		dk, err := k.loadDataKey(id)
+		if err != nil {
+			return nil, err
+		}
+		uses, err := k.store.Get(k.usesRecordKey(id))
+		if err != nil {
+			return nil, errors.WithMessagef(err, "error while reading the uses of data key [%x]", id)
+		}
+		// the data keys wrapped with a former key encryption key, and those
+		// recorded before their uses were, are retired
+		if bytes.Equal(dk.kekSKI, k.kek.SKI()) && uses != nil && decodeUses(uses) < maxDataKeyUses {
+			// the uses reserved before a restart are counted as consumed
+			atomic.StoreUint64(&dk.uses, decodeUses(uses))
+			atomic.StoreUint64(&dk.reserved, decodeUses(uses))
+			k.current[scope] = dk
+			return dk, nil
+		}
+		if bytes.Equal(dk.kekSKI, k.kek.SKI()) && uses == nil {
+			// the scope of the data key is recorded so that its values are
+			// not re-encrypted
+			if err := k.store.Put(k.scopeRecordKey(id), []byte(scope), true); err != nil {
+				return nil, errors.WithMessagef(err, "error while recording the scope of data key [%x]", id)
+			}
+		}
+	}
+
+	rawKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(rawKey); err != nil {
+		return nil, errors.Wrap(err, "error while generating a data key")
+	}
+	id = make([]byte, keyIDSize)
+	if _, err := rand.Read(id); err != nil {
+		return nil, errors.Wrap(err, "error while generating a data key ID")
+	}
+	wrappedKey, err := k.csp.Encrypt(k.kek, rawKey, &bccsp.AESCBCPKCS7ModeOpts{})
+	if err != nil {
+		return nil, errors.WithMessage(err, "error while wrapping the data key")
+	}
+	aead, err := newGCM(rawKey)
+	if err != nil {
+		return nil, err
+	}
+
+	kekSKI := k.kek.SKI()
+	record := make([]byte, 0, 1+len(kekSKI)+len(wrappedKey))
+	record = append(record, byte(len(kekSKI)))
+	record = append(record, kekSKI...)
+	record = append(record, wrappedKey...)
+	// the data key is recorded before it becomes current, so that no value can
+	// be encrypted with a key that is not persisted
+	if err := k.store.Put(k.dataKeyRecordKey(id), record, true); err != nil {
+		return nil, errors.WithMessagef(err, "error while recording the data key of scope [%s]", scope)
+	}
+	if err := k.store.Put(k.scopeRecordKey(id), []byte(scope), true); err != nil {
+		return nil, errors.WithMessagef(err, "error while recording the scope of data key [%x]", id)
+	}
+	if err := k.store.Put(k.usesRecordKey(id), encodeUses(0), true); err != nil {
+		return nil, errors.WithMessagef(err, "error while recording the uses of the data key of scope [%s]", scope)
+	}
+	if err := k.store.Put(currentRecordKey, id, true); err != nil {
+		return nil, errors.WithMessagef(err, "error while recording the current data key of scope [%s]", scope)
+	}
+
+	dk := &dataKey{id: id, kekSKI: kekSKI, aead: aead}
+	k.keys[string(id)] = dk
+	k.current[scope] = dk
+	return dk, nil
+}
+
+func (k *Keyring) dataKey(id []byte) (*dataKey, error) {
+	k.mutex.RLock()
+	dk, ok := k.keys[string(id)]
+	k.mutex.RUnlock()
+	if ok {
+		return dk, nil
+	}
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	return k.loadDataKey(id)
+}
+
+// loadDataKey must be called with the mutex held.
+func (k *Keyring) loadDataKey(id []byte) (*dataKey, error) {
+	if dk, ok := k.keys[string(id)]; ok {
+		return dk, nil
+	}
+	record, err := k.store.Get(k.dataKeyRecordKey(id))
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error while reading data key [%x]", id)
+	}
+	if record == nil {
+		return nil, errors.Errorf("data key [%x] does not exist", id)
+	}
+	if len(record) < 1 || len(record) < 1+int(record[0]) {
+		return nil, errors.Errorf("record of data key [%x] is truncated", id)
+	}
+	kekSKI, wrappedKey := record[1:1+int(record[0])], record[1+int(record[0]):]
+	kek, err := k.keyEncryptionKey(kekSKI)
+	if err != nil {
+		return nil, err
+	}
+	// the BCCSP may decrypt in place, so the record is left untouched
+	wrappedKey = append([]byte(nil), wrappedKey...)
+	rawKey, err := k.csp.Decrypt(kek, wrappedKey, &bccsp.AESCBCPKCS7ModeOpts{})
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error while unwrapping data key [%x]", id)
+	}
+	aead, err := newGCM(rawKey)
+	if err != nil {
+		return nil, err
+	}
+	dk := &dataKey{id: id, kekSKI: kekSKI, aead: aead}
+	k.keys[string(id)] = dk
+	return dk, nil
+}
+
+func (k *Keyring) keyEncryptionKey(ski []byte) (bccsp.Key, error) {
+	id := hex.EncodeToString(ski)
+	if kek, ok := k.keks[id]; ok {
+		return kek, nil
+	}
+	kek, err := k.csp.GetKey(ski)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "key encryption key [%s] is not available", id)
+	}
+	if !kek.Symmetric() {
+		return nil, errors.Errorf("key [%s] is not a key encryption key", id)
+	}
+	k.keks[id] = kek
+	return kek, nil
+}
+
+func (k *Keyring) recordKey(key []byte) []byte {
+	return append(append([]byte{}, k.prefix...), key...)
+}
+
+func (k *Keyring) dataKeyRecordKey(id []byte) []byte {
+	return k.recordKey(append(append([]byte{}, dataKeyRecordPrefix...), id...))
+}
+
+func (k *Keyring) scopeRecordKey(id []byte) []byte {
+	return k.recordKey(append(append([]byte{}, scopeRecordPrefix...), id...))
+}
+
+func (k *Keyring) usesRecordKey(id []byte) []byte {
+	return k.recordKey(append(append([]byte{}, usesRecordPrefix...), id...))
+}
+
+func encodeUses(uses uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uses)
+	return b
+}
+
+func decodeUses(b []byte) uint64 {
+	if len(b) != 8 {
+		return maxDataKeyUses
+	}
+	return binary.BigEndian.Uint64(b)
+}
+
+func splitKeyringEnvelope(envelope []byte) (id, sealed []byte, err error) {
+	if len(envelope) < 2 || envelope[0] != keyringEnvelopeVersion {
+		return nil, nil, errors.New("value is not a keyring envelope")
+	}
+	idEnd := 2 + int(envelope[1])
+	if len(envelope) < idEnd {
+		return nil, nil, errors.New("envelope is truncated")
+	}
+	return envelope[2:idEnd], envelope[idEnd:], nil
+}
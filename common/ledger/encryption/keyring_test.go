@@ -0,0 +1,189 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package encryption
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyringEncryptDecrypt(t *testing.T) {
+	store := metadataStore{}
+	exists, err := KeyringExists(store, []byte("ring/"))
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	k, err := NewKeyring(newTestCSP(t), store, []byte("ring/"))
+	require.NoError(t, err)
+	require.Equal(t, k.CurrentKeySKI(), store["ring/kek"])
+	exists, err = KeyringExists(store, []byte("ring/"))
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	envelope, err := k.Encrypt("ns/coll1", []byte("plaintext"), []byte("key1"))
+	require.NoError(t, err)
+	require.NotContains(t, string(envelope), "plaintext")
+	plaintext, err := k.Decrypt(envelope, []byte("key1"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("plaintext"), plaintext)
+
+	// the values of a scope share a data key, distinct from those of the other scopes
+	sameScope, err := k.Encrypt("ns/coll1", []byte("plaintext"), []byte("key1"))
+	require.NoError(t, err)
+	require.NotEqual(t, envelope, sameScope)
+	otherScope, err := k.Encrypt("ns/coll2", []byte("plaintext"), []byte("key1"))
+	require.NoError(t, err)
+	id, _, err := splitKeyringEnvelope(envelope)
+	require.NoError(t, err)
+	sameScopeID, _, err := splitKeyringEnvelope(sameScope)
+	require.NoError(t, err)
+	otherScopeID, _, err := splitKeyringEnvelope(otherScope)
+	require.NoError(t, err)
+	require.Equal(t, id, sameScopeID)
+	require.NotEqual(t, id, otherScopeID)
+	require.Equal(t, id, store["ring/current/ns/coll1"])
+	require.NotNil(t, store["ring/key/"+string(id)])
+
+	current, err := k.IsCurrent("ns/coll1", envelope)
+	require.NoError(t, err)
+	require.True(t, current)
+	current, err = k.IsCurrent("ns/coll2", envelope)
+	require.NoError(t, err)
+	require.False(t, current)
+
+	_, err = k.Decrypt(envelope, []byte("key2"))
+	require.EqualError(t, err, "error while decrypting the envelope: cipher: message authentication failed")
+	_, err = k.Decrypt([]byte("plaintext"), nil)
+	require.EqualError(t, err, "value is not a keyring envelope")
+	_, err = k.Decrypt(envelope[:10], []byte("key1"))
+	require.EqualError(t, err, "envelope is truncated")
+	_, err = k.Decrypt([]byte{keyringEnvelopeVersion, 1, 0}, nil)
+	require.EqualError(t, err, "data key [00] does not exist")
+}
+
+func TestKeyringRotateKey(t *testing.T) {
+	csp := newTestCSP(t)
+	store := metadataStore{}
+	k, err := NewKeyring(csp, store, nil)
+	require.NoError(t, err)
+	firstSKI := k.CurrentKeySKI()
+	envelope, err := k.Encrypt("ns/coll", []byte("before-rotation"), nil)
+	require.NoError(t, err)
+
+	secondSKI, err := k.RotateKey()
+	require.NoError(t, err)
+	require.NotEqual(t, firstSKI, secondSKI)
+	require.Equal(t, secondSKI, k.CurrentKeySKI())
+	current, err := k.IsCurrent("ns/coll", envelope)
+	require.NoError(t, err)
+	require.False(t, current)
+
+	rotatedEnvelope, err := k.Encrypt("ns/coll", []byte("after-rotation"), nil)
+	require.NoError(t, err)
+	current, err = k.IsCurrent("ns/coll", rotatedEnvelope)
+	require.NoError(t, err)
+	require.True(t, current)
+
+	// a new keyring uses the recorded keys and unwraps the data keys with the BCCSP
+	k, err = NewKeyring(csp, store, nil)
+	require.NoError(t, err)
+	require.Equal(t, secondSKI, k.CurrentKeySKI())
+	plaintext, err := k.Decrypt(envelope, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("before-rotation"), plaintext)
+	plaintext, err = k.Decrypt(rotatedEnvelope, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("after-rotation"), plaintext)
+	current, err = k.IsCurrent("ns/coll", rotatedEnvelope)
+	require.NoError(t, err)
+	require.True(t, current)
+
+	t.Run("key not available", func(t *testing.T) {
+		k, err := NewKeyring(newTestCSP(t), metadataStore{}, nil)
+		require.NoError(t, err)
+		k.store = store
+		_, err = k.Decrypt(envelope, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not available")
+
+		_, err = NewKeyring(newTestCSP(t), store, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not available")
+	})
+}
+
+func TestKeyringDataKeyUses(t *testing.T) {
+	defer func(maxUses, reservation uint64) {
+		maxDataKeyUses, dataKeyUsesReservation = maxUses, reservation
+	}(maxDataKeyUses, dataKeyUsesReservation)
+	maxDataKeyUses, dataKeyUsesReservation = 5, 2
+
+	csp := newTestCSP(t)
+	store := metadataStore{}
+	k, err := NewKeyring(csp, store, nil)
+	require.NoError(t, err)
+	encrypt := func() []byte {
+		envelope, err := k.Encrypt("ns/coll", []byte("plaintext"), nil)
+		require.NoError(t, err)
+		id, _, err := splitKeyringEnvelope(envelope)
+		require.NoError(t, err)
+		return id
+	}
+
+	firstEnvelope, err := k.Encrypt("ns/coll", []byte("plaintext"), nil)
+	require.NoError(t, err)
+	firstID, _, err := splitKeyringEnvelope(firstEnvelope)
+	require.NoError(t, err)
+	require.Equal(t, encodeUses(2), store["uses/"+string(firstID)])
+
+	// the uses reserved before a restart are counted as consumed
+	k, err = NewKeyring(csp, store, nil)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		require.Equal(t, firstID, encrypt())
+	}
+	require.Equal(t, encodeUses(5), store["uses/"+string(firstID)])
+	secondID := encrypt()
+	require.NotEqual(t, firstID, secondID)
+	require.Equal(t, secondID, store["current/ns/coll"])
+
+	// the values of a retired data key of the scope need no re-encryption
+	current, err := k.IsCurrent("ns/coll", firstEnvelope)
+	require.NoError(t, err)
+	require.True(t, current)
+	current, err = k.IsCurrent("ns/other", firstEnvelope)
+	require.NoError(t, err)
+	require.False(t, current)
+	plaintext, err := k.Decrypt(firstEnvelope, nil)
+	require.NoError(t, err)
+	require.Equal(t, []byte("plaintext"), plaintext)
+
+	t.Run("data key without recorded uses", func(t *testing.T) {
+		delete(store, "uses/"+string(secondID))
+		delete(store, "scope/"+string(secondID))
+		k, err = NewKeyring(csp, store, nil)
+		require.NoError(t, err)
+		require.NotEqual(t, secondID, encrypt())
+		require.Equal(t, []byte("ns/coll"), store["scope/"+string(secondID)])
+	})
+}
+
+func TestKeyringStoreErrors(t *testing.T) {
+	_, err := NewKeyring(newTestCSP(t), failingStore{}, nil)
+	require.EqualError(t, err, "error while reading the current key encryption key: get-error")
+	_, err = KeyringExists(failingStore{}, nil)
+	require.EqualError(t, err, "error while reading the current key encryption key: get-error")
+
+	k, err := NewKeyring(newTestCSP(t), metadataStore{}, nil)
+	require.NoError(t, err)
+	k.store = failingStore{}
+	_, err = k.RotateKey()
+	require.EqualError(t, err, "error while recording the current key encryption key: put-error")
+	_, err = k.Encrypt("ns/coll", nil, nil)
+	require.EqualError(t, err, "error while reading the current data key of scope [ns/coll]: get-error")
+}
@@ -22,3 +22,4 @@ func TestMetrics(t *testing.T) {
 //go:generate counterfeiter -o metricsfakes/counter.go -fake-name Counter . Counter
 //go:generate counterfeiter -o metricsfakes/gauge.go -fake-name Gauge . Gauge
 //go:generate counterfeiter -o metricsfakes/histogram.go -fake-name Histogram . Histogram
+//go:generate counterfeiter -o metricsfakes/exemplar_histogram.go -fake-name ExemplarHistogram . ExemplarHistogram
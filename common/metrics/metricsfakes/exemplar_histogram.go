@@ -0,0 +1,191 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package metricsfakes
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/common/metrics"
+)
+
+type ExemplarHistogram struct {
+	ObserveStub        func(float64)
+	observeMutex       sync.RWMutex
+	observeArgsForCall []struct {
+		arg1 float64
+	}
+	ObserveWithExemplarStub        func(float64, map[string]string)
+	observeWithExemplarMutex       sync.RWMutex
+	observeWithExemplarArgsForCall []struct {
+		arg1 float64
+		arg2 map[string]string
+	}
+	WithStub        func(...string) metrics.Histogram
+	withMutex       sync.RWMutex
+	withArgsForCall []struct {
+		arg1 []string
+	}
+	withReturns struct {
+		result1 metrics.Histogram
+	}
+	withReturnsOnCall map[int]struct {
+		result1 metrics.Histogram
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *ExemplarHistogram) Observe(arg1 float64) {
+	fake.observeMutex.Lock()
+	fake.observeArgsForCall = append(fake.observeArgsForCall, struct {
+		arg1 float64
+	}{arg1})
+	stub := fake.ObserveStub
+	fake.recordInvocation("Observe", []interface{}{arg1})
+	fake.observeMutex.Unlock()
+	if stub != nil {
+		fake.ObserveStub(arg1)
+	}
+}
+
+func (fake *ExemplarHistogram) ObserveCallCount() int {
+	fake.observeMutex.RLock()
+	defer fake.observeMutex.RUnlock()
+	return len(fake.observeArgsForCall)
+}
+
+func (fake *ExemplarHistogram) ObserveCalls(stub func(float64)) {
+	fake.observeMutex.Lock()
+	defer fake.observeMutex.Unlock()
+	fake.ObserveStub = stub
+}
+
+func (fake *ExemplarHistogram) ObserveArgsForCall(i int) float64 {
+	fake.observeMutex.RLock()
+	defer fake.observeMutex.RUnlock()
+	argsForCall := fake.observeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ExemplarHistogram) ObserveWithExemplar(arg1 float64, arg2 map[string]string) {
+	fake.observeWithExemplarMutex.Lock()
+	fake.observeWithExemplarArgsForCall = append(fake.observeWithExemplarArgsForCall, struct {
+		arg1 float64
+		arg2 map[string]string
+	}{arg1, arg2})
+	stub := fake.ObserveWithExemplarStub
+	fake.recordInvocation("ObserveWithExemplar", []interface{}{arg1, arg2})
+	fake.observeWithExemplarMutex.Unlock()
+	if stub != nil {
+		fake.ObserveWithExemplarStub(arg1, arg2)
+	}
+}
+
+func (fake *ExemplarHistogram) ObserveWithExemplarCallCount() int {
+	fake.observeWithExemplarMutex.RLock()
+	defer fake.observeWithExemplarMutex.RUnlock()
+	return len(fake.observeWithExemplarArgsForCall)
+}
+
+func (fake *ExemplarHistogram) ObserveWithExemplarCalls(stub func(float64, map[string]string)) {
+	fake.observeWithExemplarMutex.Lock()
+	defer fake.observeWithExemplarMutex.Unlock()
+	fake.ObserveWithExemplarStub = stub
+}
+
+func (fake *ExemplarHistogram) ObserveWithExemplarArgsForCall(i int) (float64, map[string]string) {
+	fake.observeWithExemplarMutex.RLock()
+	defer fake.observeWithExemplarMutex.RUnlock()
+	argsForCall := fake.observeWithExemplarArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *ExemplarHistogram) With(arg1 ...string) metrics.Histogram {
+	fake.withMutex.Lock()
+	ret, specificReturn := fake.withReturnsOnCall[len(fake.withArgsForCall)]
+	fake.withArgsForCall = append(fake.withArgsForCall, struct {
+		arg1 []string
+	}{arg1})
+	stub := fake.WithStub
+	fakeReturns := fake.withReturns
+	fake.recordInvocation("With", []interface{}{arg1})
+	fake.withMutex.Unlock()
+	if stub != nil {
+		return stub(arg1...)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ExemplarHistogram) WithCallCount() int {
+	fake.withMutex.RLock()
+	defer fake.withMutex.RUnlock()
+	return len(fake.withArgsForCall)
+}
+
+func (fake *ExemplarHistogram) WithCalls(stub func(...string) metrics.Histogram) {
+	fake.withMutex.Lock()
+	defer fake.withMutex.Unlock()
+	fake.WithStub = stub
+}
+
+func (fake *ExemplarHistogram) WithArgsForCall(i int) []string {
+	fake.withMutex.RLock()
+	defer fake.withMutex.RUnlock()
+	argsForCall := fake.withArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *ExemplarHistogram) WithReturns(result1 metrics.Histogram) {
+	fake.withMutex.Lock()
+	defer fake.withMutex.Unlock()
+	fake.WithStub = nil
+	fake.withReturns = struct {
+		result1 metrics.Histogram
+	}{result1}
+}
+
+func (fake *ExemplarHistogram) WithReturnsOnCall(i int, result1 metrics.Histogram) {
+	fake.withMutex.Lock()
+	defer fake.withMutex.Unlock()
+	fake.WithStub = nil
+	if fake.withReturnsOnCall == nil {
+		fake.withReturnsOnCall = make(map[int]struct {
+			result1 metrics.Histogram
+		})
+	}
+	fake.withReturnsOnCall[i] = struct {
+		result1 metrics.Histogram
+	}{result1}
+}
+
+func (fake *ExemplarHistogram) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.observeMutex.RLock()
+	defer fake.observeMutex.RUnlock()
+	fake.observeWithExemplarMutex.RLock()
+	defer fake.observeWithExemplarMutex.RUnlock()
+	fake.withMutex.RLock()
+	defer fake.withMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *ExemplarHistogram) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ metrics.ExemplarHistogram = new(ExemplarHistogram)
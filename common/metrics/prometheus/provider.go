@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package prometheus
 
 import (
+	"sort"
+	"unicode/utf8"
+
 	kitmetrics "github.com/go-kit/kit/metrics"
 	"github.com/go-kit/kit/metrics/prometheus"
 	"github.com/hyperledger/fabric/common/metrics"
@@ -44,17 +47,20 @@ func (p *Provider) NewGauge(o metrics.GaugeOpts) metrics.Gauge {
 }
 
 func (p *Provider) NewHistogram(o metrics.HistogramOpts) metrics.Histogram {
+	hv := prom.NewHistogramVec(
+		prom.HistogramOpts{
+			Namespace: o.Namespace,
+			Subsystem: o.Subsystem,
+			Name:      o.Name,
+			Help:      o.Help,
+			Buckets:   o.Buckets,
+		},
+		o.LabelNames,
+	)
+	prom.MustRegister(hv)
 	return &Histogram{
-		Histogram: prometheus.NewHistogramFrom(
-			prom.HistogramOpts{
-				Namespace: o.Namespace,
-				Subsystem: o.Subsystem,
-				Name:      o.Name,
-				Help:      o.Help,
-				Buckets:   o.Buckets,
-			},
-			o.LabelNames,
-		),
+		Histogram: prometheus.NewHistogram(hv),
+		hv:        hv,
 	}
 }
 
@@ -70,8 +76,59 @@ func (g *Gauge) With(labelValues ...string) metrics.Gauge {
 	return &Gauge{Gauge: g.Gauge.With(labelValues...)}
 }
 
-type Histogram struct{ kitmetrics.Histogram }
+type Histogram struct {
+	kitmetrics.Histogram
+
+	hv          *prom.HistogramVec
+	labelValues []string
+}
 
 func (h *Histogram) With(labelValues ...string) metrics.Histogram {
-	return &Histogram{Histogram: h.Histogram.With(labelValues...)}
+	lvs := append(append([]string{}, h.labelValues...), labelValues...)
+	if len(lvs)%2 != 0 {
+		// matches the behavior of go-kit for a label without a value
+		lvs = append(lvs, "unknown")
+	}
+	return &Histogram{
+		Histogram:   h.Histogram.With(labelValues...),
+		hv:          h.hv,
+		labelValues: lvs,
+	}
+}
+
+// ObserveWithExemplar records the observation along with an exemplar made of
+// the given labels. Prometheus limits the total length of the exemplar labels,
+// hence label values that exceed the limit are truncated.
+func (h *Histogram) ObserveWithExemplar(value float64, exemplar map[string]string) {
+	labels := prom.Labels{}
+	for i := 0; i < len(h.labelValues); i += 2 {
+		labels[h.labelValues[i]] = h.labelValues[i+1]
+	}
+	h.hv.With(labels).(prom.ExemplarObserver).ObserveWithExemplar(value, exemplarLabels(exemplar))
+}
+
+// exemplarLabels returns the exemplar labels, with their values truncated
+// to fit the maximum length of exemplar labels.
+func exemplarLabels(exemplar map[string]string) prom.Labels {
+	names := make([]string, 0, len(exemplar))
+	available := prom.ExemplarMaxRunes
+	for name := range exemplar {
+		names = append(names, name)
+		available -= utf8.RuneCountInString(name)
+	}
+	sort.Strings(names)
+
+	labels := prom.Labels{}
+	for _, name := range names {
+		value := []rune(exemplar[name])
+		if available < 0 {
+			available = 0
+		}
+		if len(value) > available {
+			value = value[:available]
+		}
+		available -= len(value)
+		labels[name] = string(value)
+	}
+	return labels
 }
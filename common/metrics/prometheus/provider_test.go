@@ -11,6 +11,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 
 	commonmetrics "github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/metrics/prometheus"
@@ -35,7 +36,7 @@ var _ = Describe("Provider", func() {
 		prom.DefaultRegisterer = registry
 		prom.DefaultGatherer = registry
 
-		server = httptest.NewServer(promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+		server = httptest.NewServer(promhttp.HandlerFor(registry, promhttp.HandlerOpts{EnableOpenMetrics: true}))
 		client = server.Client()
 
 		p = &prometheus.Provider{}
@@ -198,6 +199,29 @@ var _ = Describe("Provider", func() {
 			Expect(string(bytes)).To(ContainSubstring(`peer_playground_histogram_name_sum{alpha="a",beta="b"} 5`))
 			Expect(string(bytes)).To(ContainSubstring(`peer_playground_histogram_name_count{alpha="a",beta="b"} 2`))
 		})
+
+		It("creates histogram that support exemplars", func() {
+			histogramOpts.Buckets = []float64{1, 5}
+			histogram := p.NewHistogram(histogramOpts)
+			Expect(histogram).To(BeAssignableToTypeOf(&prometheus.Histogram{}))
+
+			commonmetrics.ObserveWithExemplar(histogram.With("alpha", "a", "beta", "b"), 0.5, map[string]string{"tx_id": "tx1"})
+			commonmetrics.ObserveWithExemplar(histogram.With("alpha", "a").With("beta", "b"), 4.5, map[string]string{"tx_id": strings.Repeat("f", 64)})
+
+			req, err := http.NewRequest("GET", fmt.Sprintf("http://%s/metrics", server.Listener.Addr().String()), nil)
+			Expect(err).NotTo(HaveOccurred())
+			req.Header.Set("Accept", "application/openmetrics-text; version=0.0.1")
+			resp, err := client.Do(req)
+			Expect(err).NotTo(HaveOccurred())
+			defer resp.Body.Close()
+
+			bytes, err := ioutil.ReadAll(resp.Body)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(bytes)).To(ContainSubstring(`peer_playground_histogram_name_bucket{alpha="a",beta="b",le="1.0"} 1 # {tx_id="tx1"} 0.5`))
+			// the exemplar is truncated to the maximum length of exemplar labels
+			Expect(string(bytes)).To(ContainSubstring(`peer_playground_histogram_name_bucket{alpha="a",beta="b",le="5.0"} 2 # {tx_id="` + strings.Repeat("f", 59) + `"} 4.5`))
+			Expect(string(bytes)).To(ContainSubstring(`peer_playground_histogram_name_count{alpha="a",beta="b"} 2`))
+		})
 	})
 
 	// This helps ensure the label cardinality behavior matches what was implemented
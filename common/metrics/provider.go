@@ -126,6 +126,28 @@ type Histogram interface {
 	Observe(value float64)
 }
 
+// An ExemplarHistogram is a Histogram that can attach an exemplar, such as the
+// ID of a transaction, to an observation. Exemplars are only supported by
+// providers whose histograms implement this interface.
+type ExemplarHistogram interface {
+	Histogram
+
+	// ObserveWithExemplar records an observation along with an exemplar made
+	// of the given labels.
+	ObserveWithExemplar(value float64, exemplar map[string]string)
+}
+
+// ObserveWithExemplar records an observation on the histogram along with an
+// exemplar made of the given labels. The exemplar is discarded if the
+// histogram does not support exemplars.
+func ObserveWithExemplar(h Histogram, value float64, exemplar map[string]string) {
+	if eh, ok := h.(ExemplarHistogram); ok {
+		eh.ObserveWithExemplar(value, exemplar)
+		return
+	}
+	h.Observe(value)
+}
+
 // HistogramOpts is used to provide basic information about a histogram to the
 // metrics subsystem.
 type HistogramOpts struct {
@@ -0,0 +1,35 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package metrics_test
+
+import (
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ObserveWithExemplar", func() {
+	It("records the exemplar when the histogram supports exemplars", func() {
+		histogram := &metricsfakes.ExemplarHistogram{}
+		metrics.ObserveWithExemplar(histogram, 1.5, map[string]string{"tx_id": "txid"})
+
+		Expect(histogram.ObserveCallCount()).To(Equal(0))
+		Expect(histogram.ObserveWithExemplarCallCount()).To(Equal(1))
+		value, exemplar := histogram.ObserveWithExemplarArgsForCall(0)
+		Expect(value).To(Equal(1.5))
+		Expect(exemplar).To(Equal(map[string]string{"tx_id": "txid"}))
+	})
+
+	It("discards the exemplar when the histogram does not support exemplars", func() {
+		histogram := &metricsfakes.Histogram{}
+		metrics.ObserveWithExemplar(histogram, 1.5, map[string]string{"tx_id": "txid"})
+
+		Expect(histogram.ObserveCallCount()).To(Equal(1))
+		Expect(histogram.ObserveArgsForCall(0)).To(Equal(1.5))
+	})
+})
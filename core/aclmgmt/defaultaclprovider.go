@@ -87,6 +87,7 @@ func newDefaultACLProvider(policyChecker policy.PolicyChecker) defaultACLProvide
 	d.cResourcePolicyMap[resources.Qscc_GetBlockByHash] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Qscc_GetTransactionByID] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.Qscc_GetBlockByTxID] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Qscc_GetStateProof] = CHANNELREADERS
 
 	//--------------- CSCC resources -----------
 	//p resources (implemented by the chaincode currently)
@@ -115,6 +116,17 @@ func newDefaultACLProvider(policyChecker policy.PolicyChecker) defaultACLProvide
 	return d
 }
 
+// isOptInResource returns true for the resources which have no default
+// policy and must be granted explicitly in the channel config.
+func isOptInResource(resName string) bool {
+	switch resName {
+	case resources.Qscc_RedactedBlocks, resources.Event_RedactedBlock:
+		return true
+	default:
+		return resources.IsNamespaceRead(resName)
+	}
+}
+
 func (d *defaultACLProviderImpl) IsPtypePolicy(resName string) bool {
 	_, ok := d.pResourcePolicyMap[resName]
 	return ok
@@ -129,7 +141,12 @@ func (d *defaultACLProviderImpl) CheckACL(resName string, channelID string, idin
 	} else {
 		policy = d.cResourcePolicyMap[resName]
 		if policy == "" {
-			aclLogger.Errorf("Unmapped policy for %s", resName)
+			if isOptInResource(resName) {
+				// opt-in resources are only granted by ACLs in the channel config
+				aclLogger.Debugf("Unmapped policy for opt-in resource %s", resName)
+			} else {
+				aclLogger.Errorf("Unmapped policy for %s", resName)
+			}
 			return fmt.Errorf("Unmapped policy for %s", resName)
 		}
 	}
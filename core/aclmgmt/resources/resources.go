@@ -10,6 +10,8 @@ SPDX-License-Identifier: Apache-2.0
 // covered by resource or default ACLProviders
 package resources
 
+import "strings"
+
 const (
 	// _lifecycle resources
 	Lifecycle_InstallChaincode                   = "_lifecycle/InstallChaincode"
@@ -45,6 +47,8 @@ const (
 	Qscc_GetBlockByHash     = "qscc/GetBlockByHash"
 	Qscc_GetTransactionByID = "qscc/GetTransactionByID"
 	Qscc_GetBlockByTxID     = "qscc/GetBlockByTxID"
+	Qscc_GetStateProof      = "qscc/GetStateProof"
+	Qscc_RedactedBlocks     = "qscc/RedactedBlocks"
 
 	// Cscc resources
 	Cscc_JoinChain            = "cscc/JoinChain"
@@ -61,8 +65,25 @@ const (
 	// Events
 	Event_Block         = "event/Block"
 	Event_FilteredBlock = "event/FilteredBlock"
+	Event_RedactedBlock = "event/RedactedBlock"
 
 	// Gateway resources
 	Gateway_CommitStatus    = "gateway/CommitStatus"
 	Gateway_ChaincodeEvents = "gateway/ChaincodeEvents"
+
+	// Namespace resources
+	NamespaceReadPrefix = "namespace/"
+	NamespaceReadSuffix = "/Read"
 )
+
+// NamespaceRead returns the name of the resource controlling the disclosure
+// of the chaincode data of a namespace in redacted blocks, e.g. "namespace/mycc/Read".
+func NamespaceRead(namespace string) string {
+	return NamespaceReadPrefix + namespace + NamespaceReadSuffix
+}
+
+// IsNamespaceRead returns true if resName controls the disclosure of the
+// chaincode data of a namespace.
+func IsNamespaceRead(resName string) bool {
+	return strings.HasPrefix(resName, NamespaceReadPrefix) && strings.HasSuffix(resName, NamespaceReadSuffix)
+}
@@ -8,6 +8,7 @@ package chaincode
 
 import (
 	"bytes"
+	"sync/atomic"
 	"time"
 	"unicode/utf8"
 
@@ -73,6 +74,23 @@ type ChaincodeSupport struct {
 	Runtime                Runtime
 	TotalQueryLimit        int
 	UserRunsCC             bool
+
+	// executeTimeoutOverride is the execute timeout set with SetExecuteTimeout
+	// in nanoseconds, accessed atomically. It is 0 when it has not been set.
+	executeTimeoutOverride int64
+}
+
+// SetExecuteTimeout changes the execute timeout of the invocations that start
+// from now on.
+func (cs *ChaincodeSupport) SetExecuteTimeout(timeout time.Duration) {
+	atomic.StoreInt64(&cs.executeTimeoutOverride, int64(timeout))
+}
+
+func (cs *ChaincodeSupport) currentExecuteTimeout() time.Duration {
+	if timeout := atomic.LoadInt64(&cs.executeTimeoutOverride); timeout != 0 {
+		return time.Duration(timeout)
+	}
+	return cs.ExecuteTimeout
 }
 
 // Launch starts executing chaincode if it is not already running. This method
@@ -285,13 +303,14 @@ func (cs *ChaincodeSupport) execute(cctyp pb.ChaincodeMessage_Type, txParams *cc
 
 func (cs *ChaincodeSupport) executeTimeout(namespace string, input *pb.ChaincodeInput) time.Duration {
 	operation := chaincodeOperation(input.Args)
+	executeTimeout := cs.currentExecuteTimeout()
 	switch {
 	case namespace == "lscc" && operation == "install":
-		return maxDuration(cs.InstallTimeout, cs.ExecuteTimeout)
+		return maxDuration(cs.InstallTimeout, executeTimeout)
 	case namespace == lifecycle.LifecycleNamespace && operation == lifecycle.InstallChaincodeFuncName:
-		return maxDuration(cs.InstallTimeout, cs.ExecuteTimeout)
+		return maxDuration(cs.InstallTimeout, executeTimeout)
 	default:
-		return cs.ExecuteTimeout
+		return executeTimeout
 	}
 }
 
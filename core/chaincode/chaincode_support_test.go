@@ -1286,6 +1286,16 @@ func TestExecuteTimeout(t *testing.T) {
 			require.Equalf(t, tt.expectedTimeout, result, "want %s, got %s", tt.expectedTimeout, result)
 		})
 	}
+
+	t.Run("set at runtime", func(t *testing.T) {
+		cs.ExecuteTimeout = time.Second
+		cs.InstallTimeout = time.Minute
+		cs.SetExecuteTimeout(2 * time.Minute)
+		defer cs.SetExecuteTimeout(0)
+
+		require.Equal(t, 2*time.Minute, cs.executeTimeout("anything", &pb.ChaincodeInput{}))
+		require.Equal(t, 2*time.Minute, cs.executeTimeout("lscc", &pb.ChaincodeInput{Args: util.ToChaincodeArgs("install")}))
+	})
 }
 
 func TestMaxDuration(t *testing.T) {
@@ -8,6 +8,9 @@ package extcc
 
 import (
 	"context"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/container/ccintf"
@@ -25,28 +28,147 @@ type StreamHandler interface {
 	HandleChaincodeStream(stream ccintf.ChaincodeStream) error
 }
 
-type ExternalChaincodeRuntime struct{}
+// DefaultUnhealthyBackoff is how long the peer waits before reconnecting to
+// an endpoint which could not be reached or whose stream terminated.
+const DefaultUnhealthyBackoff = 30 * time.Second
+
+// ExternalChaincodeRuntime connects to chaincode servers. When a chaincode
+// server has several endpoints, a stream is kept to each endpoint which can be
+// reached, and the peer balances the invocations of the chaincode across them
+// according to the load balancing policy of the server. Health checking is
+// passive: endpoints are not probed, and an endpoint is only found unhealthy
+// when a connection to it cannot be established or terminates. An unhealthy
+// endpoint is reconnected to after a backoff, as long as the stream to
+// another endpoint is still active.
+type ExternalChaincodeRuntime struct {
+	// Metrics are optional.
+	Metrics *Metrics
+	// UnhealthyBackoff defaults to DefaultUnhealthyBackoff.
+	UnhealthyBackoff time.Duration
+}
 
 // createConnection - standard grpc client creating using ClientConfig info (surprised there isn't
 // a helper method for this)
-func (i *ExternalChaincodeRuntime) createConnection(ccid string, ccinfo *ccintf.ChaincodeServerInfo) (*grpc.ClientConn, error) {
-	conn, err := ccinfo.ClientConfig.Dial(ccinfo.Address)
+func (i *ExternalChaincodeRuntime) createConnection(ccid string, endpoint ccintf.ChaincodeServerEndpoint) (*grpc.ClientConn, error) {
+	conn, err := endpoint.ClientConfig.Dial(endpoint.Address)
 	if err != nil {
-		return nil, errors.WithMessagef(err, "error creating grpc connection to %s", ccinfo.Address)
+		return nil, errors.WithMessagef(err, "error creating grpc connection to %s", endpoint.Address)
 	}
 
-	extccLogger.Debugf("Created external chaincode connection: %s", ccid)
+	extccLogger.Debugf("Created external chaincode connection: %s (%s)", ccid, endpoint.Address)
 
 	return conn, nil
 }
 
+// Stream streams with every endpoint of the chaincode server, and returns
+// once no stream is active anymore. An error is returned when no endpoint
+// could be connected to.
 func (i *ExternalChaincodeRuntime) Stream(ccid string, ccinfo *ccintf.ChaincodeServerInfo, sHandler StreamHandler) error {
 	extccLogger.Debugf("Starting external chaincode connection: %s", ccid)
-	conn, err := i.createConnection(ccid, ccinfo)
+
+	endpoints := ccinfo.Endpoints()
+	s := &streams{
+		connecting: len(endpoints),
+		stop:       make(chan struct{}),
+	}
+
+	var wg sync.WaitGroup
+	for _, endpoint := range endpoints {
+		wg.Add(1)
+		go func(endpoint ccintf.ChaincodeServerEndpoint) {
+			defer wg.Done()
+			i.serve(ccid, endpoint, sHandler, s)
+		}(endpoint)
+	}
+	wg.Wait()
+
+	extccLogger.Debugf("External chaincode %s client exited", ccid)
+
+	if !s.connected {
+		return errors.WithMessagef(s.err, "error cannot create connection for %s", ccid)
+	}
+	return nil
+}
+
+// streams tracks the streams to the endpoints of a chaincode server.
+type streams struct {
+	mutex      sync.Mutex
+	connecting int           // number of endpoints being connected to
+	live       int           // number of active streams
+	connected  bool          // whether a stream was ever established
+	err        error         // last connection error
+	stop       chan struct{} // closed once no stream is active or being established
+}
+
+// ended records that an attempt to connect to an endpoint, and the stream
+// which followed if it succeeded, ended. It returns true if no stream is left.
+func (s *streams) ended(err error) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.connecting--
 	if err != nil {
-		return errors.WithMessagef(err, "error cannot create connection for %s", ccid)
+		s.err = err
+	}
+	if s.connecting == 0 && s.live == 0 {
+		close(s.stop)
+		return true
+	}
+	return false
+}
+
+func (s *streams) retry() {
+	s.mutex.Lock()
+	s.connecting++
+	s.mutex.Unlock()
+}
+
+func (s *streams) started() {
+	s.mutex.Lock()
+	s.live++
+	s.connected = true
+	s.mutex.Unlock()
+}
+
+func (s *streams) finished() {
+	s.mutex.Lock()
+	s.live--
+	s.mutex.Unlock()
+}
+
+// serve streams with an endpoint, and reconnects to it after a backoff when
+// the connection fails or terminates, as long as a stream to another endpoint
+// is still active.
+func (i *ExternalChaincodeRuntime) serve(ccid string, endpoint ccintf.ChaincodeServerEndpoint, sHandler StreamHandler, s *streams) {
+	for {
+		err := i.stream(ccid, endpoint, sHandler, s)
+		if err != nil {
+			extccLogger.Warningf("External chaincode %s endpoint %s is unreachable: %s", ccid, endpoint.Address, err)
+		}
+		if s.ended(err) {
+			return
+		}
+		if err != nil && i.Metrics != nil {
+			i.Metrics.EndpointFailovers.With("chaincode", ccid).Add(1)
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-time.After(i.backoff()):
+		}
+		s.retry()
 	}
+}
 
+// stream connects to an endpoint and handles the stream until it terminates.
+// An error is returned when the connection could not be established.
+func (i *ExternalChaincodeRuntime) stream(ccid string, endpoint ccintf.ChaincodeServerEndpoint, sHandler StreamHandler, s *streams) error {
+	conn, err := i.createConnection(ccid, endpoint)
+	i.recordAttempt(ccid, endpoint.Address, err == nil)
+	if err != nil {
+		return err
+	}
 	defer conn.Close()
 
 	// create the client and start streaming
@@ -57,10 +179,40 @@ func (i *ExternalChaincodeRuntime) Stream(ccid string, ccinfo *ccintf.ChaincodeS
 		return errors.WithMessagef(err, "error creating grpc client connection to %s", ccid)
 	}
 
-	// peer as client has to initiate the stream. Rest of the process is unchanged
-	sHandler.HandleChaincodeStream(stream)
+	s.started()
+	defer s.finished()
+	i.streamStarted(ccid, endpoint.Address)
+	defer i.streamEnded(ccid, endpoint.Address)
 
-	extccLogger.Debugf("External chaincode %s client exited", ccid)
+	// peer as client has to initiate the stream. Rest of the process is unchanged
+	if err := sHandler.HandleChaincodeStream(stream); err != nil {
+		extccLogger.Warningf("External chaincode %s stream to %s terminated: %s", ccid, endpoint.Address, err)
+	}
 
 	return nil
 }
+
+func (i *ExternalChaincodeRuntime) backoff() time.Duration {
+	if i.UnhealthyBackoff == 0 {
+		return DefaultUnhealthyBackoff
+	}
+	return i.UnhealthyBackoff
+}
+
+func (i *ExternalChaincodeRuntime) streamStarted(ccid, address string) {
+	if i.Metrics != nil {
+		i.Metrics.ActiveStreams.With("chaincode", ccid, "endpoint", address).Add(1)
+	}
+}
+
+func (i *ExternalChaincodeRuntime) streamEnded(ccid, address string) {
+	if i.Metrics != nil {
+		i.Metrics.ActiveStreams.With("chaincode", ccid, "endpoint", address).Add(-1)
+	}
+}
+
+func (i *ExternalChaincodeRuntime) recordAttempt(ccid, address string, success bool) {
+	if i.Metrics != nil {
+		i.Metrics.ConnectionAttempts.With("chaincode", ccid, "endpoint", address, "success", strconv.FormatBool(success)).Add(1)
+	}
+}
@@ -8,9 +8,12 @@ package extcc_test
 
 import (
 	"net"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
 	"github.com/hyperledger/fabric/core/chaincode/extcc"
 	"github.com/hyperledger/fabric/core/chaincode/extcc/mock"
 	"github.com/hyperledger/fabric/core/container/ccintf"
@@ -73,6 +76,152 @@ var _ = Describe("Extcc", func() {
 				Expect(streamArg).To(Not(BeNil()))
 			})
 		})
+		When("chaincode has several endpoints", func() {
+			var (
+				listeners         []net.Listener
+				servers           []*grpc.Server
+				ccinfo            *ccintf.ChaincodeServerInfo
+				fakeAttempts      *metricsfakes.Counter
+				fakeActiveStreams *metricsfakes.Gauge
+				fakeFailovers     *metricsfakes.Counter
+			)
+
+			clientConfig := comm.ClientConfig{
+				KaOpts:      comm.DefaultKeepaliveOptions,
+				DialTimeout: 2 * time.Second,
+			}
+
+			BeforeEach(func() {
+				listeners, servers = nil, nil
+				for n := 0; n < 2; n++ {
+					l, err := net.Listen("tcp", "127.0.0.1:0")
+					Expect(err).NotTo(HaveOccurred())
+					s := grpc.NewServer()
+					go s.Serve(l)
+					listeners = append(listeners, l)
+					servers = append(servers, s)
+				}
+
+				ccinfo = &ccintf.ChaincodeServerInfo{
+					Address:      listeners[0].Addr().String(),
+					ClientConfig: clientConfig,
+					Replicas: []ccintf.ChaincodeServerEndpoint{
+						{Address: listeners[1].Addr().String(), ClientConfig: clientConfig},
+					},
+				}
+
+				fakeAttempts = &metricsfakes.Counter{}
+				fakeAttempts.WithReturns(fakeAttempts)
+				fakeActiveStreams = &metricsfakes.Gauge{}
+				fakeActiveStreams.WithReturns(fakeActiveStreams)
+				fakeFailovers = &metricsfakes.Counter{}
+				fakeFailovers.WithReturns(fakeFailovers)
+				i.Metrics = &extcc.Metrics{
+					ConnectionAttempts: fakeAttempts,
+					ActiveStreams:      fakeActiveStreams,
+					EndpointFailovers:  fakeFailovers,
+				}
+			})
+
+			AfterEach(func() {
+				for n := range servers {
+					servers[n].Stop()
+					listeners[n].Close()
+				}
+			})
+
+			attemptedEndpoints := func() []string {
+				var endpoints []string
+				for n := 0; n < fakeAttempts.WithCallCount(); n++ {
+					labels := fakeAttempts.WithArgsForCall(n)
+					Expect(labels[:4]).To(Equal([]string{"chaincode", "ccid", "endpoint", labels[3]}))
+					endpoints = append(endpoints, labels[3])
+				}
+				return endpoints
+			}
+
+			It("streams with every endpoint", func() {
+				release := make(chan struct{})
+				shandler.HandleChaincodeStreamStub = func(ccintf.ChaincodeStream) error {
+					<-release
+					return nil
+				}
+				done := make(chan error, 1)
+				go func() { done <- i.Stream("ccid", ccinfo, shandler) }()
+
+				Eventually(shandler.HandleChaincodeStreamCallCount).Should(Equal(2))
+				Expect(attemptedEndpoints()).To(ConsistOf(listeners[0].Addr().String(), listeners[1].Addr().String()))
+				Consistently(done).ShouldNot(Receive())
+
+				close(release)
+				Eventually(done).Should(Receive(BeNil()))
+				Expect(fakeActiveStreams.AddCallCount()).To(Equal(4))
+			})
+
+			It("reconnects to an endpoint whose stream terminated while another one is active", func() {
+				i.UnhealthyBackoff = 10 * time.Millisecond
+
+				// the first stream terminates right away
+				var streams int32
+				release := make(chan struct{})
+				shandler.HandleChaincodeStreamStub = func(ccintf.ChaincodeStream) error {
+					if atomic.AddInt32(&streams, 1) > 1 {
+						<-release
+					}
+					return nil
+				}
+				done := make(chan error, 1)
+				go func() { done <- i.Stream("ccid", ccinfo, shandler) }()
+
+				Eventually(shandler.HandleChaincodeStreamCallCount).Should(Equal(3))
+				Expect(fakeAttempts.WithCallCount()).To(Equal(3))
+
+				close(release)
+				Eventually(done).Should(Receive(BeNil()))
+			})
+
+			It("keeps streaming with the reachable endpoints", func() {
+				servers[0].Stop()
+				listeners[0].Close()
+				ccinfo.ClientConfig.DialTimeout = 200 * time.Millisecond
+				i.UnhealthyBackoff = time.Minute
+
+				release := make(chan struct{})
+				shandler.HandleChaincodeStreamStub = func(ccintf.ChaincodeStream) error {
+					<-release
+					return nil
+				}
+				done := make(chan error, 1)
+				go func() { done <- i.Stream("ccid", ccinfo, shandler) }()
+
+				Eventually(fakeFailovers.AddCallCount).Should(Equal(1))
+				Expect(shandler.HandleChaincodeStreamCallCount()).To(Equal(1))
+				Expect(fakeAttempts.WithCallCount()).To(Equal(2))
+				for n := 0; n < 2; n++ {
+					labels := fakeAttempts.WithArgsForCall(n)
+					Expect(labels[5]).To(Equal(strconv.FormatBool(labels[3] == listeners[1].Addr().String())))
+				}
+
+				// the unreachable endpoint is not retried once no stream is left
+				close(release)
+				Eventually(done).Should(Receive(BeNil()))
+				Expect(fakeAttempts.WithCallCount()).To(Equal(2))
+			})
+
+			It("returns an error when no endpoint is reachable", func() {
+				for n := range servers {
+					servers[n].Stop()
+					listeners[n].Close()
+				}
+				ccinfo.ClientConfig.DialTimeout = 200 * time.Millisecond
+				ccinfo.Replicas[0].ClientConfig.DialTimeout = 200 * time.Millisecond
+
+				err := i.Stream("ccid", ccinfo, shandler)
+				Expect(err).To(MatchError(ContainSubstring("error cannot create connection for ccid")))
+				Expect(shandler.HandleChaincodeStreamCallCount()).To(Equal(0))
+				Expect(fakeAttempts.WithCallCount()).To(Equal(2))
+			})
+		})
 		Context("chaincode info incorrect", func() {
 			var ccinfo *ccintf.ChaincodeServerInfo
 			BeforeEach(func() {
@@ -0,0 +1,50 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package extcc
+
+import "github.com/hyperledger/fabric/common/metrics"
+
+var (
+	connectionAttempts = metrics.CounterOpts{
+		Namespace:    "chaincode",
+		Subsystem:    "external",
+		Name:         "connection_attempts",
+		Help:         "The number of connection attempts to external chaincode endpoints.",
+		LabelNames:   []string{"chaincode", "endpoint", "success"},
+		StatsdFormat: "%{#fqname}.%{chaincode}.%{endpoint}.%{success}",
+	}
+	activeStreams = metrics.GaugeOpts{
+		Namespace:    "chaincode",
+		Subsystem:    "external",
+		Name:         "active_streams",
+		Help:         "The number of active streams to external chaincode endpoints.",
+		LabelNames:   []string{"chaincode", "endpoint"},
+		StatsdFormat: "%{#fqname}.%{chaincode}.%{endpoint}",
+	}
+	endpointFailovers = metrics.CounterOpts{
+		Namespace:    "chaincode",
+		Subsystem:    "external",
+		Name:         "endpoint_failovers",
+		Help:         "The number of times an unreachable external chaincode endpoint was skipped in favor of another one.",
+		LabelNames:   []string{"chaincode"},
+		StatsdFormat: "%{#fqname}.%{chaincode}",
+	}
+)
+
+type Metrics struct {
+	ConnectionAttempts metrics.Counter
+	ActiveStreams      metrics.Gauge
+	EndpointFailovers  metrics.Counter
+}
+
+func NewMetrics(p metrics.Provider) *Metrics {
+	return &Metrics{
+		ConnectionAttempts: p.NewCounter(connectionAttempts),
+		ActiveStreams:      p.NewGauge(activeStreams),
+		EndpointFailovers:  p.NewCounter(endpointFailovers),
+	}
+}
@@ -8,15 +8,15 @@ import (
 )
 
 type Registry struct {
-	DeregisterStub        func(string) error
-	deregisterMutex       sync.RWMutex
-	deregisterArgsForCall []struct {
-		arg1 string
+	DeregisterHandlerStub        func(*chaincode.Handler) error
+	deregisterHandlerMutex       sync.RWMutex
+	deregisterHandlerArgsForCall []struct {
+		arg1 *chaincode.Handler
 	}
-	deregisterReturns struct {
+	deregisterHandlerReturns struct {
 		result1 error
 	}
-	deregisterReturnsOnCall map[int]struct {
+	deregisterHandlerReturnsOnCall map[int]struct {
 		result1 error
 	}
 	FailedStub        func(string, error)
@@ -45,62 +45,63 @@ type Registry struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *Registry) Deregister(arg1 string) error {
-	fake.deregisterMutex.Lock()
-	ret, specificReturn := fake.deregisterReturnsOnCall[len(fake.deregisterArgsForCall)]
-	fake.deregisterArgsForCall = append(fake.deregisterArgsForCall, struct {
-		arg1 string
+func (fake *Registry) DeregisterHandler(arg1 *chaincode.Handler) error {
+	fake.deregisterHandlerMutex.Lock()
+	ret, specificReturn := fake.deregisterHandlerReturnsOnCall[len(fake.deregisterHandlerArgsForCall)]
+	fake.deregisterHandlerArgsForCall = append(fake.deregisterHandlerArgsForCall, struct {
+		arg1 *chaincode.Handler
 	}{arg1})
-	fake.recordInvocation("Deregister", []interface{}{arg1})
-	fake.deregisterMutex.Unlock()
-	if fake.DeregisterStub != nil {
-		return fake.DeregisterStub(arg1)
+	stub := fake.DeregisterHandlerStub
+	fakeReturns := fake.deregisterHandlerReturns
+	fake.recordInvocation("DeregisterHandler", []interface{}{arg1})
+	fake.deregisterHandlerMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.deregisterReturns
 	return fakeReturns.result1
 }
 
-func (fake *Registry) DeregisterCallCount() int {
-	fake.deregisterMutex.RLock()
-	defer fake.deregisterMutex.RUnlock()
-	return len(fake.deregisterArgsForCall)
+func (fake *Registry) DeregisterHandlerCallCount() int {
+	fake.deregisterHandlerMutex.RLock()
+	defer fake.deregisterHandlerMutex.RUnlock()
+	return len(fake.deregisterHandlerArgsForCall)
 }
 
-func (fake *Registry) DeregisterCalls(stub func(string) error) {
-	fake.deregisterMutex.Lock()
-	defer fake.deregisterMutex.Unlock()
-	fake.DeregisterStub = stub
+func (fake *Registry) DeregisterHandlerCalls(stub func(*chaincode.Handler) error) {
+	fake.deregisterHandlerMutex.Lock()
+	defer fake.deregisterHandlerMutex.Unlock()
+	fake.DeregisterHandlerStub = stub
 }
 
-func (fake *Registry) DeregisterArgsForCall(i int) string {
-	fake.deregisterMutex.RLock()
-	defer fake.deregisterMutex.RUnlock()
-	argsForCall := fake.deregisterArgsForCall[i]
+func (fake *Registry) DeregisterHandlerArgsForCall(i int) *chaincode.Handler {
+	fake.deregisterHandlerMutex.RLock()
+	defer fake.deregisterHandlerMutex.RUnlock()
+	argsForCall := fake.deregisterHandlerArgsForCall[i]
 	return argsForCall.arg1
 }
 
-func (fake *Registry) DeregisterReturns(result1 error) {
-	fake.deregisterMutex.Lock()
-	defer fake.deregisterMutex.Unlock()
-	fake.DeregisterStub = nil
-	fake.deregisterReturns = struct {
+func (fake *Registry) DeregisterHandlerReturns(result1 error) {
+	fake.deregisterHandlerMutex.Lock()
+	defer fake.deregisterHandlerMutex.Unlock()
+	fake.DeregisterHandlerStub = nil
+	fake.deregisterHandlerReturns = struct {
 		result1 error
 	}{result1}
 }
 
-func (fake *Registry) DeregisterReturnsOnCall(i int, result1 error) {
-	fake.deregisterMutex.Lock()
-	defer fake.deregisterMutex.Unlock()
-	fake.DeregisterStub = nil
-	if fake.deregisterReturnsOnCall == nil {
-		fake.deregisterReturnsOnCall = make(map[int]struct {
+func (fake *Registry) DeregisterHandlerReturnsOnCall(i int, result1 error) {
+	fake.deregisterHandlerMutex.Lock()
+	defer fake.deregisterHandlerMutex.Unlock()
+	fake.DeregisterHandlerStub = nil
+	if fake.deregisterHandlerReturnsOnCall == nil {
+		fake.deregisterHandlerReturnsOnCall = make(map[int]struct {
 			result1 error
 		})
 	}
-	fake.deregisterReturnsOnCall[i] = struct {
+	fake.deregisterHandlerReturnsOnCall[i] = struct {
 		result1 error
 	}{result1}
 }
@@ -111,9 +112,10 @@ func (fake *Registry) Failed(arg1 string, arg2 error) {
 		arg1 string
 		arg2 error
 	}{arg1, arg2})
+	stub := fake.FailedStub
 	fake.recordInvocation("Failed", []interface{}{arg1, arg2})
 	fake.failedMutex.Unlock()
-	if fake.FailedStub != nil {
+	if stub != nil {
 		fake.FailedStub(arg1, arg2)
 	}
 }
@@ -142,9 +144,10 @@ func (fake *Registry) Ready(arg1 string) {
 	fake.readyArgsForCall = append(fake.readyArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.ReadyStub
 	fake.recordInvocation("Ready", []interface{}{arg1})
 	fake.readyMutex.Unlock()
-	if fake.ReadyStub != nil {
+	if stub != nil {
 		fake.ReadyStub(arg1)
 	}
 }
@@ -174,15 +177,16 @@ func (fake *Registry) Register(arg1 *chaincode.Handler) error {
 	fake.registerArgsForCall = append(fake.registerArgsForCall, struct {
 		arg1 *chaincode.Handler
 	}{arg1})
+	stub := fake.RegisterStub
+	fakeReturns := fake.registerReturns
 	fake.recordInvocation("Register", []interface{}{arg1})
 	fake.registerMutex.Unlock()
-	if fake.RegisterStub != nil {
-		return fake.RegisterStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.registerReturns
 	return fakeReturns.result1
 }
 
@@ -231,8 +235,8 @@ func (fake *Registry) RegisterReturnsOnCall(i int, result1 error) {
 func (fake *Registry) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.deregisterMutex.RLock()
-	defer fake.deregisterMutex.RUnlock()
+	fake.deregisterHandlerMutex.RLock()
+	defer fake.deregisterHandlerMutex.RUnlock()
 	fake.failedMutex.RLock()
 	defer fake.failedMutex.RUnlock()
 	fake.readyMutex.RLock()
@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -42,7 +43,7 @@ type Registry interface {
 	Register(*Handler) error
 	Ready(string)
 	Failed(string, error)
-	Deregister(string) error
+	DeregisterHandler(*Handler) error
 }
 
 // An Invoker invokes chaincode.
@@ -148,6 +149,8 @@ type Handler struct {
 	mutex sync.Mutex
 	// streamDoneChan is closed when the chaincode stream terminates.
 	streamDoneChan chan struct{}
+	// inFlight holds the number of transactions being executed.
+	inFlight atomic.Int32
 }
 
 // handleMessage is called by ProcessStream to dispatch messages.
@@ -352,7 +355,7 @@ func (h *Handler) checkACL(signedProp *pb.SignedProposal, proposal *pb.Proposal,
 }
 
 func (h *Handler) deregister() {
-	h.Registry.Deregister(h.chaincodeID)
+	h.Registry.DeregisterHandler(h)
 }
 
 func (h *Handler) streamDone() <-chan struct{} {
@@ -565,6 +568,14 @@ func (h *Handler) checkPurgePrivateDataCap(msg *pb.ChaincodeMessage) error {
 	return nil
 }
 
+// crossChannelReadsEnabled returns true if the application capabilities of the
+// channel require the reads of chaincode-to-chaincode calls to other channels to
+// be recorded in the read set of its transactions.
+func (h *Handler) crossChannelReadsEnabled(channelID string) bool {
+	ac, exists := h.AppConfig.GetApplicationConfig(channelID)
+	return exists && ac.Capabilities().CrossChannelReads()
+}
+
 func errorIfCreatorHasNoReadPermission(chaincodeName, collection string, txContext *TransactionContext) error {
 	rwPermission, err := getReadWritePermission(chaincodeName, collection, txContext)
 	if err != nil {
@@ -1166,9 +1177,21 @@ func (h *Handler) HandleInvokeChaincode(msg *pb.ChaincodeMessage, txContext *Tra
 		Proposal:             txContext.Proposal,
 		TXSimulator:          txContext.TXSimulator,
 		HistoryQueryExecutor: txContext.HistoryQueryExecutor,
+		CrossChannelInvoked:  txContext.CrossChannelInvoked,
 	}
 
+	// When the channel of the caller records cross-channel reads, the reads
+	// performed on the called channel are added to the read set of the
+	// transaction, along with the proofs of the values read, so that they are
+	// validated on commit; otherwise the call is a query whose reads are
+	// discarded.
+	var crossChannelSim ledger.TxSimulator
+	var crossChannelLedger ledger.PeerLedger
 	if targetInstance.ChannelID != txContext.ChannelID {
+		if txContext.CrossChannelInvoked != nil {
+			txContext.CrossChannelInvoked.Store(true)
+		}
+
 		lgr := h.LedgerGetter.GetLedger(targetInstance.ChannelID)
 		if lgr == nil {
 			return nil, errors.Errorf("failed to find ledger for channel: %s", targetInstance.ChannelID)
@@ -1187,6 +1210,10 @@ func (h *Handler) HandleInvokeChaincode(msg *pb.ChaincodeMessage, txContext *Tra
 
 		txParams.TXSimulator = sim
 		txParams.HistoryQueryExecutor = hqe
+		if txContext.TXSimulator != nil && h.crossChannelReadsEnabled(txContext.ChannelID) {
+			crossChannelSim = sim
+			crossChannelLedger = lgr
+		}
 	}
 
 	// Execute the chaincode... this CANNOT be an init at least for now
@@ -1195,6 +1222,12 @@ func (h *Handler) HandleInvokeChaincode(msg *pb.ChaincodeMessage, txContext *Tra
 		return nil, errors.Wrap(err, "execute failed")
 	}
 
+	if crossChannelSim != nil {
+		if err := txContext.TXSimulator.AddCrossChannelReads(targetInstance.ChannelID, crossChannelSim, crossChannelLedger); err != nil {
+			return nil, errors.WithMessagef(err, "failed to record reads of channel %s", targetInstance.ChannelID)
+		}
+	}
+
 	// payload is marshalled and sent to the calling chaincode's shim which unmarshals and
 	// sends it to chaincode
 	res, err := proto.Marshal(responseMessage)
@@ -1219,6 +1252,9 @@ func (h *Handler) Execute(txParams *ccprovider.TransactionParams, namespace stri
 	}
 	defer h.TXContexts.Delete(msg.ChannelId, msg.Txid)
 
+	h.inFlight.Add(1)
+	defer h.inFlight.Add(-1)
+
 	if err := h.setChaincodeProposal(txParams.SignedProp, txParams.Proposal, msg); err != nil {
 		return nil, err
 	}
@@ -27,3 +27,7 @@ func StreamDone(h *Handler) <-chan struct{} {
 func SetStreamDoneChan(h *Handler, ch chan struct{}) {
 	h.streamDoneChan = ch
 }
+
+func SetHandlerInFlight(h *Handler, inFlight int32) {
+	h.inFlight.Store(inFlight)
+}
@@ -9,6 +9,7 @@ package chaincode
 import (
 	"sync"
 
+	"github.com/hyperledger/fabric/core/container/ccintf"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/pkg/errors"
 )
@@ -17,8 +18,10 @@ import (
 type HandlerRegistry struct {
 	allowUnsolicitedRegistration bool // from cs.userRunsCC
 
-	mutex     sync.Mutex              // lock covering handlers and launching
-	handlers  map[string]*Handler     // chaincode cname to associated handler
+	mutex     sync.Mutex              // lock covering handlers, balancing, next and launching
+	handlers  map[string][]*Handler   // chaincode cname to associated handlers
+	balancing map[string]string       // replicated chaincodes to load balancing policy
+	next      map[string]int          // replicated chaincodes to round robin cursor
 	launching map[string]*LaunchState // launching chaincodes to LaunchState
 }
 
@@ -59,7 +62,9 @@ func (l *LaunchState) Notify(err error) {
 // NewHandlerRegistry constructs a HandlerRegistry.
 func NewHandlerRegistry(allowUnsolicitedRegistration bool) *HandlerRegistry {
 	return &HandlerRegistry{
-		handlers:                     map[string]*Handler{},
+		handlers:                     map[string][]*Handler{},
+		balancing:                    map[string]string{},
+		next:                         map[string]int{},
 		launching:                    map[string]*LaunchState{},
 		allowUnsolicitedRegistration: allowUnsolicitedRegistration,
 	}
@@ -79,7 +84,7 @@ func (r *HandlerRegistry) Launching(ccid string) (*LaunchState, bool) {
 	}
 
 	// handler registered without going through launch
-	if _, ok := r.handlers[ccid]; ok {
+	if len(r.handlers[ccid]) > 0 {
 		launchState := NewLaunchState()
 		launchState.Notify(nil)
 		return launchState, true
@@ -114,23 +119,59 @@ func (r *HandlerRegistry) Failed(ccid string, err error) {
 	}
 }
 
-// Handler retrieves the handler for a chaincode instance.
+// Replicated indicates that the launched chaincode is served by several
+// replicas, each of which registers a handler. Invocations are spread across
+// the handlers according to the load balancing policy.
+func (r *HandlerRegistry) Replicated(ccid, loadBalancing string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if loadBalancing == "" {
+		loadBalancing = ccintf.RoundRobin
+	}
+	r.balancing[ccid] = loadBalancing
+}
+
+// Handler retrieves the handler for a chaincode instance. When the chaincode
+// is replicated, the handler is selected according to the load balancing
+// policy: round robin, or the handler executing the fewest transactions.
 func (r *HandlerRegistry) Handler(ccid string) *Handler {
 	r.mutex.Lock()
-	h := r.handlers[ccid]
-	r.mutex.Unlock()
-	return h
+	defer r.mutex.Unlock()
+
+	handlers := r.handlers[ccid]
+	switch len(handlers) {
+	case 0:
+		return nil
+	case 1:
+		return handlers[0]
+	}
+
+	if r.balancing[ccid] == ccintf.LeastLoaded {
+		selected := handlers[0]
+		for _, h := range handlers[1:] {
+			if h.inFlight.Load() < selected.inFlight.Load() {
+				selected = h
+			}
+		}
+		return selected
+	}
+
+	n := r.next[ccid] % len(handlers)
+	r.next[ccid] = n + 1
+	return handlers[n]
 }
 
 // Register adds a chaincode handler to the registry.
 // An error will be returned if a handler is already registered for the
-// chaincode. An error will also be returned if the chaincode has not already
-// been "launched", and unsolicited registration is not allowed.
+// chaincode, unless the chaincode is replicated. An error will also be
+// returned if the chaincode has not already been "launched", and unsolicited
+// registration is not allowed.
 func (r *HandlerRegistry) Register(h *Handler) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
-	if r.handlers[h.chaincodeID] != nil {
+	if len(r.handlers[h.chaincodeID]) > 0 && r.balancing[h.chaincodeID] == "" {
 		chaincodeLogger.Debugf("duplicate registered handler(key:%s) return error", h.chaincodeID)
 		return errors.Errorf("duplicate chaincodeID: %s", h.chaincodeID)
 	}
@@ -141,47 +182,95 @@ func (r *HandlerRegistry) Register(h *Handler) error {
 		return errors.Errorf("peer will not accept external chaincode connection %s (except in dev mode)", h.chaincodeID)
 	}
 
-	r.handlers[h.chaincodeID] = h
+	r.handlers[h.chaincodeID] = append(r.handlers[h.chaincodeID], h)
 
 	chaincodeLogger.Debugf("registered handler complete for chaincode %s", h.chaincodeID)
 	return nil
 }
 
 // Deregister clears references to state associated specified chaincode.
-// As part of the cleanup, it closes the handler so it can cleanup any state.
-// If the registry does not contain the provided handler, an error is returned.
+// As part of the cleanup, it closes the handlers so they can cleanup any state.
+// If the registry does not contain any handler for the chaincode, an error is
+// returned.
 func (r *HandlerRegistry) Deregister(ccid string) error {
 	chaincodeLogger.Debugf("deregister handler: %s", ccid)
 
 	r.mutex.Lock()
-	handler := r.handlers[ccid]
-	delete(r.handlers, ccid)
-	delete(r.launching, ccid)
+	handlers := r.handlers[ccid]
+	r.forget(ccid)
 	r.mutex.Unlock()
 
-	if handler == nil {
+	if len(handlers) == 0 {
 		return errors.Errorf("could not find handler: %s", ccid)
 	}
 
-	handler.Close()
+	for _, h := range handlers {
+		h.Close()
+	}
 
 	chaincodeLogger.Debugf("deregistered handler with key: %s", ccid)
 	return nil
 }
 
+// DeregisterHandler clears references to the provided handler and closes it.
+// State associated with the chaincode is only cleared along with its last
+// handler, so that the handlers of the other replicas of a replicated
+// chaincode keep serving it. If the registry does not contain the provided
+// handler, an error is returned.
+func (r *HandlerRegistry) DeregisterHandler(h *Handler) error {
+	chaincodeLogger.Debugf("deregister handler: %s", h.chaincodeID)
+
+	r.mutex.Lock()
+	handlers := r.handlers[h.chaincodeID]
+	found := false
+	for n := range handlers {
+		if handlers[n] == h {
+			handlers = append(handlers[:n:n], handlers[n+1:]...)
+			found = true
+			break
+		}
+	}
+	if len(handlers) == 0 {
+		r.forget(h.chaincodeID)
+	} else {
+		r.handlers[h.chaincodeID] = handlers
+	}
+	r.mutex.Unlock()
+
+	if !found {
+		return errors.Errorf("could not find handler: %s", h.chaincodeID)
+	}
+
+	h.Close()
+
+	chaincodeLogger.Debugf("deregistered handler with key: %s", h.chaincodeID)
+	return nil
+}
+
+// forget clears the state associated with the chaincode. The caller must hold
+// the lock.
+func (r *HandlerRegistry) forget(ccid string) {
+	delete(r.handlers, ccid)
+	delete(r.balancing, ccid)
+	delete(r.next, ccid)
+	delete(r.launching, ccid)
+}
+
 type TxQueryExecutorGetter struct {
 	HandlerRegistry *HandlerRegistry
 	CCID            string
 }
 
 func (g *TxQueryExecutorGetter) TxQueryExecutor(chainID, txID string) ledger.SimpleQueryExecutor {
-	handler := g.HandlerRegistry.Handler(g.CCID)
-	if handler == nil {
-		return nil
-	}
-	txContext := handler.TXContexts.Get(chainID, txID)
-	if txContext == nil {
-		return nil
+	g.HandlerRegistry.mutex.Lock()
+	handlers := g.HandlerRegistry.handlers[g.CCID]
+	g.HandlerRegistry.mutex.Unlock()
+
+	// the transaction is executed by one of the handlers of the chaincode
+	for _, handler := range handlers {
+		if txContext := handler.TXContexts.Get(chainID, txID); txContext != nil {
+			return txContext.TXSimulator
+		}
 	}
-	return txContext.TXSimulator
+	return nil
 }
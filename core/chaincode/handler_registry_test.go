@@ -146,6 +146,84 @@ var _ = Describe("HandlerRegistry", func() {
 		})
 	})
 
+	Describe("Replicated", func() {
+		var handlers []*chaincode.Handler
+
+		BeforeEach(func() {
+			hr = chaincode.NewHandlerRegistry(false)
+			_, started := hr.Launching("chaincode-id")
+			Expect(started).To(BeFalse())
+			hr.Replicated("chaincode-id", "")
+
+			handlers = nil
+			for n := 0; n < 3; n++ {
+				h := &chaincode.Handler{TXContexts: chaincode.NewTransactionContexts()}
+				chaincode.SetHandlerChaincodeID(h, "chaincode-id")
+				Expect(hr.Register(h)).To(Succeed())
+				handlers = append(handlers, h)
+			}
+		})
+
+		It("spreads invocations across the handlers in round robin", func() {
+			selected := map[*chaincode.Handler]int{}
+			for n := 0; n < 6; n++ {
+				selected[hr.Handler("chaincode-id")]++
+			}
+			Expect(selected).To(Equal(map[*chaincode.Handler]int{
+				handlers[0]: 2,
+				handlers[1]: 2,
+				handlers[2]: 2,
+			}))
+		})
+
+		It("selects the least loaded handler", func() {
+			hr.Replicated("chaincode-id", "least_loaded")
+			chaincode.SetHandlerInFlight(handlers[0], 2)
+			chaincode.SetHandlerInFlight(handlers[1], 1)
+			chaincode.SetHandlerInFlight(handlers[2], 3)
+			Expect(hr.Handler("chaincode-id")).To(BeIdenticalTo(handlers[1]))
+
+			chaincode.SetHandlerInFlight(handlers[1], 4)
+			Expect(hr.Handler("chaincode-id")).To(BeIdenticalTo(handlers[0]))
+		})
+
+		It("keeps serving the chaincode until its last handler is deregistered", func() {
+			Expect(hr.DeregisterHandler(handlers[0])).To(Succeed())
+			Expect(hr.DeregisterHandler(handlers[0])).To(MatchError("could not find handler: chaincode-id"))
+			for n := 0; n < 4; n++ {
+				Expect(hr.Handler("chaincode-id")).To(BeElementOf(handlers[1], handlers[2]))
+			}
+
+			Expect(hr.DeregisterHandler(handlers[1])).To(Succeed())
+			Expect(hr.Handler("chaincode-id")).To(BeIdenticalTo(handlers[2]))
+			_, started := hr.Launching("chaincode-id")
+			Expect(started).To(BeTrue())
+
+			Expect(hr.DeregisterHandler(handlers[2])).To(Succeed())
+			Expect(hr.Handler("chaincode-id")).To(BeNil())
+			_, started = hr.Launching("chaincode-id")
+			Expect(started).To(BeFalse())
+
+			// the chaincode is no longer replicated once relaunched
+			Expect(hr.Register(handlers[0])).To(Succeed())
+			Expect(hr.Register(handlers[1])).To(MatchError("duplicate chaincodeID: chaincode-id"))
+		})
+
+		It("finds the transaction simulator of any handler", func() {
+			txSimulator := &mock.TxSimulator{}
+			_, err := handlers[1].TXContexts.Create(&ccprovider.TransactionParams{
+				ChannelID:   "channel-id",
+				TxID:        "tx-id",
+				TXSimulator: txSimulator,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			getter := &chaincode.TxQueryExecutorGetter{HandlerRegistry: hr, CCID: "chaincode-id"}
+			Expect(getter.TxQueryExecutor("channel-id", "tx-id")).To(BeIdenticalTo(txSimulator))
+			Expect(getter.TxQueryExecutor("channel-id", "other-tx-id")).To(BeNil())
+		})
+	})
+
 	Describe("Register", func() {
 		Context("when unsolicited registration is disallowed", func() {
 			BeforeEach(func() {
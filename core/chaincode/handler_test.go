@@ -8,6 +8,7 @@ package chaincode_test
 
 import (
 	"io"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -20,6 +21,7 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/mock"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/scc"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -2394,6 +2396,56 @@ var _ = Describe("Handler", func() {
 				Expect(newTxSimulator.DoneCallCount()).To(Equal(1))
 			})
 
+			It("records that the transaction invoked another channel", func() {
+				txContext.CrossChannelInvoked = &atomic.Bool{}
+				_, err := handler.HandleInvokeChaincode(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(txContext.CrossChannelInvoked.Load()).To(BeTrue())
+				txParams, _, _ := fakeInvoker.InvokeArgsForCall(0)
+				Expect(txParams.CrossChannelInvoked).To(BeIdenticalTo(txContext.CrossChannelInvoked))
+			})
+
+			It("does not record the reads of the target channel", func() {
+				_, err := handler.HandleInvokeChaincode(incomingMessage, txContext)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeTxSimulator.AddCrossChannelReadsCallCount()).To(Equal(0))
+			})
+
+			Context("when cross-channel reads are enabled on the channel of the caller", func() {
+				BeforeEach(func() {
+					fakeCapabilites.CrossChannelReadsReturns(true)
+				})
+
+				It("records the reads of the target channel with the proofs of its ledger", func() {
+					fakeTxSimulator.AddCrossChannelReadsStub = func(string, ledger.TxSimulator, ledger.StateProver) error {
+						Expect(newTxSimulator.DoneCallCount()).To(Equal(0))
+						return nil
+					}
+					_, err := handler.HandleInvokeChaincode(incomingMessage, txContext)
+					Expect(err).NotTo(HaveOccurred())
+
+					Expect(fakeApplicationConfigRetriever.GetApplicationConfigArgsForCall(0)).To(Equal("channel-id"))
+					Expect(fakeTxSimulator.AddCrossChannelReadsCallCount()).To(Equal(1))
+					channelID, sim, prover := fakeTxSimulator.AddCrossChannelReadsArgsForCall(0)
+					Expect(channelID).To(Equal("target-channel-id"))
+					Expect(sim).To(BeIdenticalTo(newTxSimulator))
+					Expect(prover).To(BeIdenticalTo(fakePeerLedger))
+				})
+
+				Context("when recording the reads fails", func() {
+					BeforeEach(func() {
+						fakeTxSimulator.AddCrossChannelReadsReturns(errors.New("writes are not supported"))
+					})
+
+					It("returns an error", func() {
+						_, err := handler.HandleInvokeChaincode(incomingMessage, txContext)
+						Expect(err).To(MatchError("failed to record reads of channel target-channel-id: writes are not supported"))
+					})
+				})
+			})
+
 			Context("when getting the ledger for the target channel fails", func() {
 				BeforeEach(func() {
 					fakeLedgerGetter.GetLedgerReturns(nil)
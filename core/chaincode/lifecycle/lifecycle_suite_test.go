@@ -75,6 +75,11 @@ type readWritableState interface {
 	lifecycle.RangeableState
 }
 
+//go:generate counterfeiter -o mock/identity.go --fake-name Identity . identity
+type identity interface {
+	msp.Identity
+}
+
 //go:generate counterfeiter -o mock/query_executor.go --fake-name SimpleQueryExecutor . simpleQueryExecutor
 type simpleQueryExecutor interface {
 	ledger.SimpleQueryExecutor
@@ -26,6 +26,16 @@ type ApplicationCapabilities struct {
 	collectionUpgradeReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	CrossChannelReadsStub        func() bool
+	crossChannelReadsMutex       sync.RWMutex
+	crossChannelReadsArgsForCall []struct {
+	}
+	crossChannelReadsReturns struct {
+		result1 bool
+	}
+	crossChannelReadsReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	ForbidDuplicateTXIdInBlockStub        func() bool
 	forbidDuplicateTXIdInBlockMutex       sync.RWMutex
 	forbidDuplicateTXIdInBlockArgsForCall []struct {
@@ -256,6 +266,59 @@ func (fake *ApplicationCapabilities) CollectionUpgradeReturnsOnCall(i int, resul
 	}{result1}
 }
 
+func (fake *ApplicationCapabilities) CrossChannelReads() bool {
+	fake.crossChannelReadsMutex.Lock()
+	ret, specificReturn := fake.crossChannelReadsReturnsOnCall[len(fake.crossChannelReadsArgsForCall)]
+	fake.crossChannelReadsArgsForCall = append(fake.crossChannelReadsArgsForCall, struct {
+	}{})
+	stub := fake.CrossChannelReadsStub
+	fakeReturns := fake.crossChannelReadsReturns
+	fake.recordInvocation("CrossChannelReads", []interface{}{})
+	fake.crossChannelReadsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *ApplicationCapabilities) CrossChannelReadsCallCount() int {
+	fake.crossChannelReadsMutex.RLock()
+	defer fake.crossChannelReadsMutex.RUnlock()
+	return len(fake.crossChannelReadsArgsForCall)
+}
+
+func (fake *ApplicationCapabilities) CrossChannelReadsCalls(stub func() bool) {
+	fake.crossChannelReadsMutex.Lock()
+	defer fake.crossChannelReadsMutex.Unlock()
+	fake.CrossChannelReadsStub = stub
+}
+
+func (fake *ApplicationCapabilities) CrossChannelReadsReturns(result1 bool) {
+	fake.crossChannelReadsMutex.Lock()
+	defer fake.crossChannelReadsMutex.Unlock()
+	fake.CrossChannelReadsStub = nil
+	fake.crossChannelReadsReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *ApplicationCapabilities) CrossChannelReadsReturnsOnCall(i int, result1 bool) {
+	fake.crossChannelReadsMutex.Lock()
+	defer fake.crossChannelReadsMutex.Unlock()
+	fake.CrossChannelReadsStub = nil
+	if fake.crossChannelReadsReturnsOnCall == nil {
+		fake.crossChannelReadsReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.crossChannelReadsReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *ApplicationCapabilities) ForbidDuplicateTXIdInBlock() bool {
 	fake.forbidDuplicateTXIdInBlockMutex.Lock()
 	ret, specificReturn := fake.forbidDuplicateTXIdInBlockReturnsOnCall[len(fake.forbidDuplicateTXIdInBlockArgsForCall)]
@@ -899,6 +962,8 @@ func (fake *ApplicationCapabilities) Invocations() map[string][][]interface{} {
 	defer fake.aCLsMutex.RUnlock()
 	fake.collectionUpgradeMutex.RLock()
 	defer fake.collectionUpgradeMutex.RUnlock()
+	fake.crossChannelReadsMutex.RLock()
+	defer fake.crossChannelReadsMutex.RUnlock()
 	fake.forbidDuplicateTXIdInBlockMutex.RLock()
 	defer fake.forbidDuplicateTXIdInBlockMutex.RUnlock()
 	fake.keyLevelEndorsementMutex.RLock()
@@ -0,0 +1,122 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mock
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/msp"
+)
+
+type PackageSignerDeserializer struct {
+	DeserializeIdentityStub        func([]byte) (msp.Identity, error)
+	deserializeIdentityMutex       sync.RWMutex
+	deserializeIdentityArgsForCall []struct {
+		arg1 []byte
+	}
+	deserializeIdentityReturns struct {
+		result1 msp.Identity
+		result2 error
+	}
+	deserializeIdentityReturnsOnCall map[int]struct {
+		result1 msp.Identity
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *PackageSignerDeserializer) DeserializeIdentity(arg1 []byte) (msp.Identity, error) {
+	var arg1Copy []byte
+	if arg1 != nil {
+		arg1Copy = make([]byte, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.deserializeIdentityMutex.Lock()
+	ret, specificReturn := fake.deserializeIdentityReturnsOnCall[len(fake.deserializeIdentityArgsForCall)]
+	fake.deserializeIdentityArgsForCall = append(fake.deserializeIdentityArgsForCall, struct {
+		arg1 []byte
+	}{arg1Copy})
+	stub := fake.DeserializeIdentityStub
+	fakeReturns := fake.deserializeIdentityReturns
+	fake.recordInvocation("DeserializeIdentity", []interface{}{arg1Copy})
+	fake.deserializeIdentityMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PackageSignerDeserializer) DeserializeIdentityCallCount() int {
+	fake.deserializeIdentityMutex.RLock()
+	defer fake.deserializeIdentityMutex.RUnlock()
+	return len(fake.deserializeIdentityArgsForCall)
+}
+
+func (fake *PackageSignerDeserializer) DeserializeIdentityCalls(stub func([]byte) (msp.Identity, error)) {
+	fake.deserializeIdentityMutex.Lock()
+	defer fake.deserializeIdentityMutex.Unlock()
+	fake.DeserializeIdentityStub = stub
+}
+
+func (fake *PackageSignerDeserializer) DeserializeIdentityArgsForCall(i int) []byte {
+	fake.deserializeIdentityMutex.RLock()
+	defer fake.deserializeIdentityMutex.RUnlock()
+	argsForCall := fake.deserializeIdentityArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *PackageSignerDeserializer) DeserializeIdentityReturns(result1 msp.Identity, result2 error) {
+	fake.deserializeIdentityMutex.Lock()
+	defer fake.deserializeIdentityMutex.Unlock()
+	fake.DeserializeIdentityStub = nil
+	fake.deserializeIdentityReturns = struct {
+		result1 msp.Identity
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PackageSignerDeserializer) DeserializeIdentityReturnsOnCall(i int, result1 msp.Identity, result2 error) {
+	fake.deserializeIdentityMutex.Lock()
+	defer fake.deserializeIdentityMutex.Unlock()
+	fake.DeserializeIdentityStub = nil
+	if fake.deserializeIdentityReturnsOnCall == nil {
+		fake.deserializeIdentityReturnsOnCall = make(map[int]struct {
+			result1 msp.Identity
+			result2 error
+		})
+	}
+	fake.deserializeIdentityReturnsOnCall[i] = struct {
+		result1 msp.Identity
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PackageSignerDeserializer) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.deserializeIdentityMutex.RLock()
+	defer fake.deserializeIdentityMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *PackageSignerDeserializer) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ lifecycle.PackageSignerDeserializer = new(PackageSignerDeserializer)
@@ -0,0 +1,131 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	mspprotos "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/pkg/errors"
+)
+
+//go:generate counterfeiter -o mock/package_signer_deserializer.go --fake-name PackageSignerDeserializer . PackageSignerDeserializer
+
+// PackageSignerDeserializer deserializes the identity of a package signer
+// which claims to be a member of a trusted MSP.
+type PackageSignerDeserializer interface {
+	DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error)
+}
+
+// PackageSignatureVerifier implements the peer local trust policy for chaincode
+// install packages: a package may only be installed if it comes with a valid
+// detached signature from a trusted signer.
+type PackageSignatureVerifier struct {
+	// TrustedMSPs lists the MSPs whose valid members are trusted signers.
+	TrustedMSPs []string
+	// TrustedRoots are the CA certificates of trusted signers. A signer is
+	// trusted if its certificate is valid and chains to one of them.
+	TrustedRoots *x509.CertPool
+	// Deserializer deserializes the identities of members of the TrustedMSPs.
+	Deserializer PackageSignerDeserializer
+	// Now returns the time certificates are validated at, and defaults to
+	// time.Now.
+	Now func() time.Time
+}
+
+// VerifyPackage returns an error unless at least one of the signatures is a
+// valid signature of the package from a trusted signer.
+func (v *PackageSignatureVerifier) VerifyPackage(chaincodeInstallPackage []byte, signatures []*persistence.PackageSignature) error {
+	if len(signatures) == 0 {
+		return errors.New("chaincode package is not signed")
+	}
+
+	var failures []string
+	for i, signature := range signatures {
+		err := v.verifySignature(chaincodeInstallPackage, signature)
+		if err == nil {
+			return nil
+		}
+		failures = append(failures, fmt.Sprintf("signature %d: %s", i, err))
+	}
+
+	return errors.Errorf("no valid signature from a trusted signer: %s", strings.Join(failures, "; "))
+}
+
+func (v *PackageSignatureVerifier) verifySignature(signed []byte, signature *persistence.PackageSignature) error {
+	sid := &mspprotos.SerializedIdentity{}
+	if err := proto.Unmarshal(signature.Signer, sid); err != nil {
+		return errors.Wrap(err, "could not unmarshal signer identity")
+	}
+
+	for _, mspID := range v.TrustedMSPs {
+		if sid.Mspid != mspID || v.Deserializer == nil {
+			continue
+		}
+		identity, err := v.Deserializer.DeserializeIdentity(signature.Signer)
+		if err != nil {
+			return errors.WithMessagef(err, "could not deserialize signer identity of MSP %s", sid.Mspid)
+		}
+		if err := identity.Validate(); err != nil {
+			return errors.WithMessagef(err, "signer identity of MSP %s is not valid", sid.Mspid)
+		}
+		return errors.WithMessage(identity.Verify(signed, signature.Signature), "signature verification failed")
+	}
+
+	if v.TrustedRoots == nil {
+		return errors.Errorf("signer from MSP %s is not trusted", sid.Mspid)
+	}
+	block, _ := pem.Decode(sid.IdBytes)
+	if block == nil {
+		return errors.Errorf("signer from MSP %s has no PEM encoded certificate", sid.Mspid)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return errors.Wrapf(err, "could not parse certificate of signer from MSP %s", sid.Mspid)
+	}
+	now := time.Now
+	if v.Now != nil {
+		now = v.Now
+	}
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:       v.TrustedRoots,
+		CurrentTime: now(),
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return errors.Wrapf(err, "certificate of signer from MSP %s is not trusted", sid.Mspid)
+	}
+
+	return verifyWithCert(cert, signed, signature.Signature)
+}
+
+func verifyWithCert(cert *x509.Certificate, signed, signature []byte) error {
+	switch pub := cert.PublicKey.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(signed)
+		if !ecdsa.VerifyASN1(pub, digest[:], signature) {
+			return errors.New("signature verification failed")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(pub, signed, signature) {
+			return errors.New("signature verification failed")
+		}
+	default:
+		return errors.Errorf("unsupported signer public key type %T", pub)
+	}
+	return nil
+}
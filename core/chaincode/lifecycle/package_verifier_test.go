@@ -0,0 +1,169 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	mspprotos "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/common/crypto/tlsgen"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle/mock"
+	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/hyperledger/fabric/protoutil"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PackageSignatureVerifier", func() {
+	var (
+		verifier         *lifecycle.PackageSignatureVerifier
+		fakeDeserializer *mock.PackageSignerDeserializer
+		fakeIdentity     *mock.Identity
+		pkgBytes         []byte
+		ca               tlsgen.CA
+		signerCertPEM    []byte
+		signerKey        *ecdsa.PrivateKey
+	)
+
+	sign := func(pkg []byte, mspID string) *persistence.PackageSignature {
+		digest := sha256.Sum256(pkg)
+		signature, err := ecdsa.SignASN1(rand.Reader, signerKey, digest[:])
+		Expect(err).NotTo(HaveOccurred())
+
+		return &persistence.PackageSignature{
+			Signer: protoutil.MarshalOrPanic(&mspprotos.SerializedIdentity{
+				Mspid:   mspID,
+				IdBytes: signerCertPEM,
+			}),
+			Signature: signature,
+		}
+	}
+
+	rootsOf := func(ca tlsgen.CA) *x509.CertPool {
+		pool := x509.NewCertPool()
+		Expect(pool.AppendCertsFromPEM(ca.CertBytes())).To(BeTrue())
+		return pool
+	}
+
+	BeforeEach(func() {
+		var err error
+		pkgBytes, err = ioutil.ReadFile("../persistence/testdata/good-package.tar.gz")
+		Expect(err).NotTo(HaveOccurred())
+
+		ca, err = tlsgen.NewCA()
+		Expect(err).NotTo(HaveOccurred())
+		kp, err := ca.NewClientCertKeyPair()
+		Expect(err).NotTo(HaveOccurred())
+
+		signerCertPEM = kp.Cert
+		block, _ := pem.Decode(kp.Key)
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+		signerKey = key.(*ecdsa.PrivateKey)
+
+		fakeIdentity = &mock.Identity{}
+		fakeDeserializer = &mock.PackageSignerDeserializer{}
+		fakeDeserializer.DeserializeIdentityReturns(fakeIdentity, nil)
+
+		verifier = &lifecycle.PackageSignatureVerifier{
+			Deserializer: fakeDeserializer,
+		}
+	})
+
+	It("rejects packages which are not signed", func() {
+		err := verifier.VerifyPackage(pkgBytes, nil)
+		Expect(err).To(MatchError("chaincode package is not signed"))
+	})
+
+	It("rejects signatures from untrusted signers", func() {
+		err := verifier.VerifyPackage(pkgBytes, []*persistence.PackageSignature{sign(pkgBytes, "Org1MSP")})
+		Expect(err).To(MatchError("no valid signature from a trusted signer: signature 0: signer from MSP Org1MSP is not trusted"))
+		Expect(fakeDeserializer.DeserializeIdentityCallCount()).To(Equal(0))
+	})
+
+	When("the CA of the signer certificate is trusted", func() {
+		BeforeEach(func() {
+			verifier.TrustedRoots = rootsOf(ca)
+		})
+
+		It("accepts valid signatures", func() {
+			Expect(verifier.VerifyPackage(pkgBytes, []*persistence.PackageSignature{sign(pkgBytes, "Org1MSP")})).To(Succeed())
+		})
+
+		It("rejects signatures of other contents", func() {
+			signature := sign([]byte("other package"), "Org1MSP")
+			err := verifier.VerifyPackage(pkgBytes, []*persistence.PackageSignature{signature})
+			Expect(err).To(MatchError("no valid signature from a trusted signer: signature 0: signature verification failed"))
+		})
+
+		It("rejects signer certificates which are not valid at the time of verification", func() {
+			verifier.Now = func() time.Time { return time.Now().Add(48 * time.Hour) }
+			err := verifier.VerifyPackage(pkgBytes, []*persistence.PackageSignature{sign(pkgBytes, "Org1MSP")})
+			Expect(err).To(MatchError(ContainSubstring("certificate of signer from MSP Org1MSP is not trusted: x509: certificate has expired or is not yet valid")))
+		})
+	})
+
+	When("the CA of the signer certificate is not trusted", func() {
+		BeforeEach(func() {
+			otherCA, err := tlsgen.NewCA()
+			Expect(err).NotTo(HaveOccurred())
+			verifier.TrustedRoots = rootsOf(otherCA)
+		})
+
+		It("rejects the signature", func() {
+			err := verifier.VerifyPackage(pkgBytes, []*persistence.PackageSignature{sign(pkgBytes, "Org1MSP")})
+			Expect(err).To(MatchError(ContainSubstring("certificate of signer from MSP Org1MSP is not trusted: x509: certificate signed by unknown authority")))
+		})
+	})
+
+	When("the signer MSP is trusted", func() {
+		BeforeEach(func() {
+			verifier.TrustedMSPs = []string{"Org1MSP"}
+		})
+
+		It("verifies the signature of the package bytes with the signer identity", func() {
+			Expect(verifier.VerifyPackage(pkgBytes, []*persistence.PackageSignature{sign(pkgBytes, "Org1MSP")})).To(Succeed())
+
+			Expect(fakeIdentity.ValidateCallCount()).To(Equal(1))
+			Expect(fakeIdentity.VerifyCallCount()).To(Equal(1))
+			msg, _ := fakeIdentity.VerifyArgsForCall(0)
+			Expect(msg).To(Equal(pkgBytes))
+		})
+
+		It("rejects signers which cannot be deserialized", func() {
+			fakeDeserializer.DeserializeIdentityReturns(nil, fmt.Errorf("unknown MSP"))
+			err := verifier.VerifyPackage(pkgBytes, []*persistence.PackageSignature{sign(pkgBytes, "Org1MSP")})
+			Expect(err).To(MatchError("no valid signature from a trusted signer: signature 0: could not deserialize signer identity of MSP Org1MSP: unknown MSP"))
+		})
+
+		It("rejects signers which are not valid", func() {
+			fakeIdentity.ValidateReturns(fmt.Errorf("certificate expired"))
+			err := verifier.VerifyPackage(pkgBytes, []*persistence.PackageSignature{sign(pkgBytes, "Org1MSP")})
+			Expect(err).To(MatchError("no valid signature from a trusted signer: signature 0: signer identity of MSP Org1MSP is not valid: certificate expired"))
+		})
+
+		It("rejects invalid signatures", func() {
+			fakeIdentity.VerifyReturns(fmt.Errorf("bad signature"))
+			err := verifier.VerifyPackage(pkgBytes, []*persistence.PackageSignature{sign(pkgBytes, "Org1MSP")})
+			Expect(err).To(MatchError("no valid signature from a trusted signer: signature 0: signature verification failed: bad signature"))
+		})
+
+		It("accepts the package if any signature is valid", func() {
+			signatures := []*persistence.PackageSignature{sign(pkgBytes, "Org2MSP"), sign(pkgBytes, "Org1MSP")}
+			Expect(verifier.VerifyPackage(pkgBytes, signatures)).To(Succeed())
+		})
+	})
+})
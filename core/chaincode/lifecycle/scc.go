@@ -9,12 +9,14 @@ package lifecycle
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 
 	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/hyperledger/fabric-protos-go/common"
 	mspprotos "github.com/hyperledger/fabric-protos-go/msp"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric/common/audit"
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/common/chaincode"
 	"github.com/hyperledger/fabric/common/channelconfig"
@@ -136,6 +138,14 @@ type QueryExecutorProvider interface {
 	TxQueryExecutor(channelID, txID string) ledger.SimpleQueryExecutor
 }
 
+//go:generate counterfeiter -o mock/package_verifier.go --fake-name PackageVerifier . PackageVerifier
+
+// PackageVerifier verifies that a chaincode install package may be installed,
+// given the detached signatures which come with it.
+type PackageVerifier interface {
+	VerifyPackage(chaincodeInstallPackage []byte, signatures []*persistence.PackageSignature) error
+}
+
 // SCC implements the required methods to satisfy the chaincode interface.
 // It routes the invocation calls to the backing implementations.
 type SCC struct {
@@ -154,6 +164,14 @@ type SCC struct {
 	// Dispatcher handles the rote protobuf boilerplate for unmarshalling/marshaling
 	// the inputs and outputs of the SCC functions.
 	Dispatcher *dispatcher.Dispatcher
+
+	// AuditLogger records the invocations of the functions which install,
+	// approve or commit chaincodes.
+	AuditLogger *audit.Logger
+
+	// PackageVerifier, when set, verifies chaincode install packages and
+	// their signatures before they are installed.
+	PackageVerifier PackageVerifier
 }
 
 // Name returns "_lifecycle"
@@ -184,6 +202,68 @@ func (scc *SCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 		return shim.Error(fmt.Sprintf("lifecycle scc operations require exactly two arguments but received %d", len(args)))
 	}
 
+	res := scc.invoke(stub, args)
+	scc.audit(stub, args, res)
+	return res
+}
+
+// audit records the invocation of a function which installs, approves or commits
+// a chaincode along with its outcome. Invocations of other functions are not recorded.
+// Approvals and commits only take effect once their transaction is committed, hence
+// they are recorded as endorsed along with the ID of the transaction.
+func (scc *SCC) audit(stub shim.ChaincodeStubInterface, args [][]byte, res pb.Response) {
+	if scc.AuditLogger == nil {
+		return
+	}
+
+	params := map[string]string{}
+	if channelID := stub.GetChannelID(); channelID != "" {
+		params["channel"] = channelID
+	}
+
+	log := scc.AuditLogger.LogEndorsed
+	switch string(args[0]) {
+	case InstallChaincodeFuncName:
+		log = scc.AuditLogger.Log
+		result := &lb.InstallChaincodeResult{}
+		if res.Status == shim.OK && proto.Unmarshal(res.Payload, result) == nil {
+			params["packageID"] = result.PackageId
+		}
+	case ApproveChaincodeDefinitionForMyOrgFuncName:
+		input := &lb.ApproveChaincodeDefinitionForMyOrgArgs{}
+		if proto.Unmarshal(args[1], input) == nil {
+			params["name"] = input.Name
+			params["version"] = input.Version
+			params["sequence"] = strconv.FormatInt(input.Sequence, 10)
+			params["packageID"] = input.GetSource().GetLocalPackage().GetPackageId()
+		}
+		params["txID"] = stub.GetTxID()
+	case CommitChaincodeDefinitionFuncName:
+		input := &lb.CommitChaincodeDefinitionArgs{}
+		if proto.Unmarshal(args[1], input) == nil {
+			params["name"] = input.Name
+			params["version"] = input.Version
+			params["sequence"] = strconv.FormatInt(input.Sequence, 10)
+		}
+		params["txID"] = stub.GetTxID()
+	default:
+		return
+	}
+
+	var err error
+	if res.Status != shim.OK {
+		err = errors.New(res.Message)
+	}
+
+	var caller audit.Caller
+	if sp, spErr := stub.GetSignedProposal(); spErr == nil {
+		caller = audit.CallerFromSignedProposal(sp)
+	}
+
+	log("lifecycle", string(args[0]), caller, params, err)
+}
+
+func (scc *SCC) invoke(stub shim.ChaincodeStubInterface, args [][]byte) pb.Response {
 	var ac channelconfig.Application
 	var channelID string
 	if channelID = stub.GetChannelID(); channelID != "" {
@@ -263,6 +343,12 @@ func (i *Invocation) InstallChaincode(input *lb.InstallChaincodeArgs) (proto.Mes
 		)
 	}
 
+	if i.SCC.PackageVerifier != nil {
+		if err := VerifyInstallPackage(i.SCC.PackageVerifier, i.Stub, input.ChaincodeInstallPackage); err != nil {
+			return nil, errors.WithMessage(err, "chaincode install package rejected")
+		}
+	}
+
 	installedCC, err := i.SCC.Functions.InstallChaincode(input.ChaincodeInstallPackage)
 	if err != nil {
 		return nil, err
@@ -274,6 +360,22 @@ func (i *Invocation) InstallChaincode(input *lb.InstallChaincodeArgs) (proto.Mes
 	}, nil
 }
 
+// VerifyInstallPackage verifies the chaincode install package against the
+// detached signatures carried by the transient data of the proposal.
+func VerifyInstallPackage(verifier PackageVerifier, stub shim.ChaincodeStubInterface, chaincodeInstallPackage []byte) error {
+	transient, err := stub.GetTransient()
+	if err != nil {
+		return errors.WithMessage(err, "could not retrieve transient data")
+	}
+	signatures := &persistence.PackageSignatures{}
+	if signaturesBytes, ok := transient[persistence.SignaturesTransientKey]; ok {
+		if signatures, err = persistence.ParsePackageSignatures(signaturesBytes); err != nil {
+			return err
+		}
+	}
+	return verifier.VerifyPackage(chaincodeInstallPackage, signatures.Signatures)
+}
+
 // QueryInstalledChaincode is a SCC function that may be dispatched to which
 // routes to the underlying lifecycle implementation.
 func (i *Invocation) QueryInstalledChaincode(input *lb.QueryInstalledChaincodeArgs) (proto.Message, error) {
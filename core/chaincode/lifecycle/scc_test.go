@@ -9,6 +9,7 @@ package lifecycle_test
 import (
 	"bytes"
 	"encoding/gob"
+	"encoding/json"
 	"fmt"
 
 	"github.com/hyperledger/fabric/core/ledger"
@@ -18,6 +19,7 @@ import (
 	mspprotos "github.com/hyperledger/fabric-protos-go/msp"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	lb "github.com/hyperledger/fabric-protos-go/peer/lifecycle"
+	"github.com/hyperledger/fabric/common/audit"
 	"github.com/hyperledger/fabric/common/chaincode"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/policydsl"
@@ -26,6 +28,7 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/persistence"
 	"github.com/hyperledger/fabric/core/dispatcher"
 	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -197,6 +200,123 @@ var _ = Describe("SCC", func() {
 					Expect(res.Message).To(Equal("failed to invoke backing implementation of 'InstallChaincode': underlying-error"))
 				})
 			})
+
+			Context("when a package verifier is set", func() {
+				var fakePackageVerifier *mock.PackageVerifier
+
+				BeforeEach(func() {
+					fakePackageVerifier = &mock.PackageVerifier{}
+					scc.PackageVerifier = fakePackageVerifier
+
+					signatures := &persistence.PackageSignatures{
+						Signatures: []*persistence.PackageSignature{{Signer: []byte("signer"), Signature: []byte("signature")}},
+					}
+					signaturesBytes, err := signatures.Bytes()
+					Expect(err).NotTo(HaveOccurred())
+					fakeStub.GetTransientReturns(map[string][]byte{"package_signatures": signaturesBytes}, nil)
+				})
+
+				It("verifies the package against the signatures from the transient data before installing it", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(200)))
+
+					Expect(fakePackageVerifier.VerifyPackageCallCount()).To(Equal(1))
+					pkg, signatures := fakePackageVerifier.VerifyPackageArgsForCall(0)
+					Expect(pkg).To(Equal([]byte("chaincode-package")))
+					Expect(signatures).To(Equal([]*persistence.PackageSignature{{Signer: []byte("signer"), Signature: []byte("signature")}}))
+					Expect(fakeSCCFuncs.InstallChaincodeCallCount()).To(Equal(1))
+				})
+
+				Context("when the proposal carries no signatures", func() {
+					BeforeEach(func() {
+						fakeStub.GetTransientReturns(nil, nil)
+					})
+
+					It("verifies the package without signatures", func() {
+						scc.Invoke(fakeStub)
+						Expect(fakePackageVerifier.VerifyPackageCallCount()).To(Equal(1))
+						_, signatures := fakePackageVerifier.VerifyPackageArgsForCall(0)
+						Expect(signatures).To(BeEmpty())
+					})
+				})
+
+				Context("when the signatures cannot be parsed", func() {
+					BeforeEach(func() {
+						fakeStub.GetTransientReturns(map[string][]byte{"package_signatures": []byte("garbage")}, nil)
+					})
+
+					It("rejects the package", func() {
+						res := scc.Invoke(fakeStub)
+						Expect(res.Status).To(Equal(int32(500)))
+						Expect(res.Message).To(HavePrefix("failed to invoke backing implementation of 'InstallChaincode': chaincode install package rejected: could not unmarshal package signatures as json"))
+						Expect(fakeSCCFuncs.InstallChaincodeCallCount()).To(Equal(0))
+					})
+				})
+
+				Context("when the package is rejected", func() {
+					BeforeEach(func() {
+						fakePackageVerifier.VerifyPackageReturns(fmt.Errorf("chaincode package is not signed"))
+					})
+
+					It("does not install the chaincode", func() {
+						res := scc.Invoke(fakeStub)
+						Expect(res.Status).To(Equal(int32(500)))
+						Expect(res.Message).To(Equal("failed to invoke backing implementation of 'InstallChaincode': chaincode install package rejected: chaincode package is not signed"))
+						Expect(fakeSCCFuncs.InstallChaincodeCallCount()).To(Equal(0))
+					})
+				})
+			})
+
+			Context("when an audit logger is set", func() {
+				var auditBuff *bytes.Buffer
+
+				BeforeEach(func() {
+					auditBuff = &bytes.Buffer{}
+					scc.AuditLogger = audit.NewLogger(auditBuff, nil)
+				})
+
+				It("records the installation", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(200)))
+
+					record := audit.Record{}
+					Expect(json.Unmarshal(auditBuff.Bytes(), &record)).To(Succeed())
+					Expect(record.Component).To(Equal("lifecycle"))
+					Expect(record.Action).To(Equal("InstallChaincode"))
+					Expect(record.Outcome).To(Equal(audit.OutcomeSuccess))
+					Expect(record.Parameters).To(Equal(map[string]string{
+						"channel":   "test-channel",
+						"packageID": "package-id",
+					}))
+				})
+
+				Context("when the underlying function implementation fails", func() {
+					BeforeEach(func() {
+						fakeSCCFuncs.InstallChaincodeReturns(nil, fmt.Errorf("underlying-error"))
+					})
+
+					It("records the failure", func() {
+						scc.Invoke(fakeStub)
+
+						record := audit.Record{}
+						Expect(json.Unmarshal(auditBuff.Bytes(), &record)).To(Succeed())
+						Expect(record.Outcome).To(Equal(audit.OutcomeFailure))
+						Expect(record.Error).To(Equal("failed to invoke backing implementation of 'InstallChaincode': underlying-error"))
+					})
+				})
+
+				Context("when a function that does not change chaincodes is invoked", func() {
+					BeforeEach(func() {
+						fakeStub.GetArgsReturns([][]byte{[]byte("QueryInstalledChaincodes"), protoutil.MarshalOrPanic(&lb.QueryInstalledChaincodesArgs{})})
+					})
+
+					It("records nothing", func() {
+						res := scc.Invoke(fakeStub)
+						Expect(res.Status).To(Equal(int32(200)))
+						Expect(auditBuff.Len()).To(BeZero())
+					})
+				})
+			})
 		})
 
 		Describe("QueryInstalledChaincode", func() {
@@ -510,6 +630,34 @@ var _ = Describe("SCC", func() {
 				Expect(privState.(*lifecycle.ChaincodePrivateLedgerShim).Collection).To(Equal("_implicit_org_fake-mspid"))
 			})
 
+			Context("when an audit logger is set", func() {
+				var auditBuff *bytes.Buffer
+
+				BeforeEach(func() {
+					auditBuff = &bytes.Buffer{}
+					scc.AuditLogger = audit.NewLogger(auditBuff, nil)
+					fakeStub.GetTxIDReturns("tx-id")
+				})
+
+				It("records the approval as endorsed", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(200)))
+
+					record := audit.Record{}
+					Expect(json.Unmarshal(auditBuff.Bytes(), &record)).To(Succeed())
+					Expect(record.Action).To(Equal("ApproveChaincodeDefinitionForMyOrg"))
+					Expect(record.Outcome).To(Equal(audit.OutcomeEndorsed))
+					Expect(record.Parameters).To(Equal(map[string]string{
+						"channel":   "test-channel",
+						"name":      "cc_name",
+						"version":   "version_1.0",
+						"sequence":  "7",
+						"packageID": "hash",
+						"txID":      "tx-id",
+					}))
+				})
+			})
+
 			Context("when the chaincode name contains invalid characters", func() {
 				BeforeEach(func() {
 					arg.Name = "!nvalid"
@@ -1013,6 +1161,47 @@ var _ = Describe("SCC", func() {
 				Expect([]string{collection0, collection1}).To(ConsistOf("_implicit_org_fake-mspid", "_implicit_org_other-mspid"))
 			})
 
+			Context("when an audit logger is set", func() {
+				var auditBuff *bytes.Buffer
+
+				BeforeEach(func() {
+					auditBuff = &bytes.Buffer{}
+					scc.AuditLogger = audit.NewLogger(auditBuff, nil)
+					fakeStub.GetTxIDReturns("tx-id")
+				})
+
+				It("records the commit as endorsed", func() {
+					res := scc.Invoke(fakeStub)
+					Expect(res.Status).To(Equal(int32(200)))
+
+					record := audit.Record{}
+					Expect(json.Unmarshal(auditBuff.Bytes(), &record)).To(Succeed())
+					Expect(record.Action).To(Equal("CommitChaincodeDefinition"))
+					Expect(record.Outcome).To(Equal(audit.OutcomeEndorsed))
+					Expect(record.Parameters).To(Equal(map[string]string{
+						"channel":  "test-channel",
+						"name":     "cc-name2",
+						"version":  "version-2+2",
+						"sequence": "7",
+						"txID":     "tx-id",
+					}))
+				})
+
+				Context("when the underlying function implementation fails", func() {
+					BeforeEach(func() {
+						fakeSCCFuncs.CommitChaincodeDefinitionReturns(nil, fmt.Errorf("underlying-error"))
+					})
+
+					It("records the failure", func() {
+						scc.Invoke(fakeStub)
+
+						record := audit.Record{}
+						Expect(json.Unmarshal(auditBuff.Bytes(), &record)).To(Succeed())
+						Expect(record.Outcome).To(Equal(audit.OutcomeFailure))
+					})
+				})
+			})
+
 			Context("when the chaincode name begins with an invalid character", func() {
 				BeforeEach(func() {
 					arg.Name = "_invalid"
@@ -0,0 +1,56 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package persistence
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// SignaturesFileSuffix is appended to the name of a chaincode install package
+// file to name the file holding the detached signatures of the package.
+const SignaturesFileSuffix = ".signatures.json"
+
+// SignaturesTransientKey is the key of the transient data which carries the
+// detached signatures of the package in a chaincode install proposal.
+const SignaturesTransientKey = "package_signatures"
+
+// PackageSignature is a signature over the bytes of a chaincode install
+// package, as they are installed.
+type PackageSignature struct {
+	// Signer is the serialized identity of the signer
+	Signer []byte `json:"signer"`
+	// Signature is the signature over the bytes of the package
+	Signature []byte `json:"signature"`
+}
+
+// PackageSignatures are the detached signatures of a chaincode install
+// package. They are kept apart from the package, so that signing a package
+// does not alter it nor its package ID.
+type PackageSignatures struct {
+	Signatures []*PackageSignature `json:"signatures"`
+}
+
+// ParsePackageSignatures parses a set of bytes as the detached signatures of
+// a chaincode install package.
+func ParsePackageSignatures(source []byte) (*PackageSignatures, error) {
+	signatures := &PackageSignatures{}
+	if err := json.Unmarshal(source, signatures); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal package signatures as json")
+	}
+	return signatures, nil
+}
+
+// Bytes returns the json encoding of the signatures.
+func (p *PackageSignatures) Bytes() ([]byte, error) {
+	signaturesBytes, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal package signatures")
+	}
+	return signaturesBytes, nil
+}
@@ -0,0 +1,37 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package persistence_test
+
+import (
+	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PackageSignatures", func() {
+	It("round trips through json", func() {
+		signatures := &persistence.PackageSignatures{
+			Signatures: []*persistence.PackageSignature{
+				{Signer: []byte("signer1"), Signature: []byte("signature1")},
+				{Signer: []byte("signer2"), Signature: []byte("signature2")},
+			},
+		}
+		signaturesBytes, err := signatures.Bytes()
+		Expect(err).NotTo(HaveOccurred())
+
+		parsed, err := persistence.ParsePackageSignatures(signaturesBytes)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(parsed).To(Equal(signatures))
+	})
+
+	Context("when the signatures are not json", func() {
+		It("returns an error", func() {
+			_, err := persistence.ParsePackageSignatures([]byte("garbage"))
+			Expect(err).To(MatchError(ContainSubstring("could not unmarshal package signatures as json")))
+		})
+	})
+})
@@ -19,6 +19,7 @@ import (
 // LaunchRegistry tracks launching chaincode instances.
 type LaunchRegistry interface {
 	Launching(ccid string) (launchState *LaunchState, started bool)
+	Replicated(ccid, loadBalancing string)
 	Deregister(ccid string) error
 }
 
@@ -87,6 +88,10 @@ func (r *RuntimeLauncher) Launch(ccid string, streamHandler extcc.StreamHandler)
 
 			// chaincode server model indicated... proceed to connect to CC
 			if ccservinfo != nil {
+				// each replica of the chaincode server registers a handler
+				if len(ccservinfo.Endpoints()) > 1 {
+					r.Registry.Replicated(ccid, ccservinfo.LoadBalancing)
+				}
 				if err = r.ConnectionHandler.Stream(ccid, ccservinfo, streamHandler); err != nil {
 					startFailCh <- errors.WithMessagef(err, "connection to %s failed", ccid)
 					return
@@ -233,6 +233,33 @@ var _ = Describe("RuntimeLauncher", func() {
 			Expect(ccshandler).To(Equal(fakeStreamHandler))
 		})
 
+		It("does not mark the chaincode as replicated", func() {
+			err := runtimeLauncher.Launch("chaincode-name:chaincode-version", fakeStreamHandler)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeRegistry.ReplicatedCallCount()).To(Equal(0))
+		})
+
+		Context("when the chaincode server has several endpoints", func() {
+			BeforeEach(func() {
+				fakeRuntime.BuildReturns(&ccintf.ChaincodeServerInfo{
+					Address:       "peer-address",
+					Replicas:      []ccintf.ChaincodeServerEndpoint{{Address: "other-peer-address"}},
+					LoadBalancing: ccintf.LeastLoaded,
+				}, nil)
+			})
+
+			It("marks the chaincode as replicated", func() {
+				err := runtimeLauncher.Launch("chaincode-name:chaincode-version", fakeStreamHandler)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeRegistry.ReplicatedCallCount()).To(Equal(1))
+				ccid, loadBalancing := fakeRegistry.ReplicatedArgsForCall(0)
+				Expect(ccid).To(Equal("chaincode-name:chaincode-version"))
+				Expect(loadBalancing).To(Equal(ccintf.LeastLoaded))
+			})
+		})
+
 		It("does not deregister the chaincode", func() {
 			err := runtimeLauncher.Launch("chaincode-name:chaincode-version", fakeStreamHandler)
 			Expect(err).NotTo(HaveOccurred())
@@ -8,6 +8,7 @@ package chaincode
 
 import (
 	"sync"
+	"sync/atomic"
 
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
@@ -25,6 +26,7 @@ type TransactionContext struct {
 	HistoryQueryExecutor ledger.HistoryQueryExecutor
 	CollectionStore      privdata.CollectionStore
 	IsInitTransaction    bool
+	CrossChannelInvoked  *atomic.Bool
 
 	// tracks open iterators used for range queries
 	queryMutex          sync.Mutex
@@ -40,6 +40,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt/ledgermgmttest"
+	"github.com/hyperledger/fabric/core/ledger/stateproof"
 	mocktxvalidator "github.com/hyperledger/fabric/core/mocks/txvalidator"
 	mocks2 "github.com/hyperledger/fabric/discovery/support/mocks"
 	"github.com/hyperledger/fabric/internal/pkg/txflags"
@@ -1547,6 +1548,10 @@ func (m *mockLedger) CommitNotificationsChannel(done <-chan struct{}) (<-chan *l
 	return nil, nil
 }
 
+func (m *mockLedger) GetStateProof(namespace, key string, blockNum uint64) (*stateproof.Proof, error) {
+	return nil, nil
+}
+
 // mockQueryExecutor mock of the query executor,
 // needed to simulate inability to access state db, e.g.
 // the case where due to db failure it's not possible to
@@ -7,17 +7,21 @@ SPDX-License-Identifier: Apache-2.0
 package plugindispatcher
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/channelconfig"
 	commonerrors "github.com/hyperledger/fabric/common/errors"
 	"github.com/hyperledger/fabric/common/flogging"
 	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
 	s "github.com/hyperledger/fabric/core/handlers/validation/api/state"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/stateproof"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 )
@@ -28,6 +32,9 @@ type ChannelResources interface {
 	// GetMSPIDs returns the IDs for the application MSPs
 	// that have been defined in the channel
 	GetMSPIDs() []string
+
+	// Capabilities defines the capabilities for the application portion of this channel
+	Capabilities() channelconfig.ApplicationCapabilities
 }
 
 // LedgerResources provides access to ledger artefacts or
@@ -169,6 +176,7 @@ func (v *dispatcherImpl) Dispatch(seq int, payload *common.Payload, envBytes []b
 	}
 
 	namespaces := make(map[string]struct{})
+	crossChannelStates := make(map[string]*crossChannelState)
 	for _, ns := range txRWSet.NsRwSets {
 		// check to make sure there is no duplicate namespace in txRWSet
 		if _, ok := namespaces[ns.NameSpace]; ok {
@@ -178,6 +186,14 @@ func (v *dispatcherImpl) Dispatch(seq int, payload *common.Payload, envBytes []b
 		}
 		namespaces[ns.NameSpace] = struct{}{}
 
+		if rwsetutil.IsCrossChannelNamespace(ns.NameSpace) {
+			if err := v.verifyCrossChannelReads(chdr.ChannelId, ns, crossChannelStates); err != nil {
+				logger.Errorf("invalid cross-channel reads for txId = %s: %s", chdr.TxId, err)
+				return peer.TxValidationCode_BAD_RWSET, err
+			}
+			continue
+		}
+
 		if v.txWritesToNamespace(ns) {
 			wrNamespace[ns.NameSpace] = true
 		}
@@ -220,6 +236,98 @@ func (v *dispatcherImpl) Dispatch(seq int, payload *common.Payload, envBytes []b
 	return peer.TxValidationCode_VALID, nil
 }
 
+// crossChannelState is the state of another channel at which the reads of a
+// transaction were performed
+type crossChannelState struct {
+	height uint64
+	root   []byte
+}
+
+// verifyCrossChannelReads checks the reads recorded in the cross-channel
+// namespace of a transaction of the supplied channel against their state
+// proofs, without access to the ledger of the other channel. The proofs
+// establish the values of the keys at the recorded height of the other
+// channel from the root of its state commitment, which is vouched for by the
+// endorsements of the transaction as part of its read-write set. All the reads
+// of a channel must be performed at the same height and prove against the same
+// root, which are tracked in states.
+func (v *dispatcherImpl) verifyCrossChannelReads(channelID string, ns *rwsetutil.NsRwSet, states map[string]*crossChannelState) error {
+	if !v.cr.Capabilities().CrossChannelReads() {
+		return errors.Errorf("namespace '%s' records reads of another channel, which are not enabled on channel %s", ns.NameSpace, channelID)
+	}
+
+	otherChannelID, namespace, _ := rwsetutil.ParseCrossChannelNamespace(ns.NameSpace)
+	if otherChannelID == channelID {
+		return errors.Errorf("namespace '%s' records reads of the channel of the transaction", ns.NameSpace)
+	}
+	if len(ns.KvRwSet.GetWrites()) > 0 || len(ns.KvRwSet.GetRangeQueriesInfo()) > 0 || len(ns.CollHashedRwSets) > 0 {
+		return errors.Errorf("namespace '%s' records operations other than reads of public state", ns.NameSpace)
+	}
+
+	proofs := map[string][]byte{}
+	for _, metadataWrite := range ns.KvRwSet.GetMetadataWrites() {
+		entries := metadataWrite.GetEntries()
+		if len(entries) != 1 || entries[0].GetName() != rwsetutil.CrossChannelProofEntry {
+			return errors.Errorf("namespace '%s' records metadata of key %s other than its state proof", ns.NameSpace, metadataWrite.Key)
+		}
+		proofs[metadataWrite.Key] = entries[0].GetValue()
+	}
+
+	var height uint64
+	for _, read := range ns.KvRwSet.GetReads() {
+		if read.Key == rwsetutil.CrossChannelHeightKey {
+			height = read.GetVersion().GetBlockNum()
+		}
+	}
+	if height == 0 {
+		return errors.Errorf("namespace '%s' does not record the height of the ledger of channel %s", ns.NameSpace, otherChannelID)
+	}
+	state, ok := states[otherChannelID]
+	if !ok {
+		state = &crossChannelState{height: height}
+		states[otherChannelID] = state
+	}
+	if state.height != height {
+		return errors.Errorf("reads of channel %s were performed at heights %d and %d", otherChannelID, state.height, height)
+	}
+
+	for _, read := range ns.KvRwSet.GetReads() {
+		if read.Key == rwsetutil.CrossChannelHeightKey {
+			continue
+		}
+		proofBytes, ok := proofs[read.Key]
+		if !ok {
+			return errors.Errorf("read of key %s of namespace '%s' has no state proof", read.Key, ns.NameSpace)
+		}
+		delete(proofs, read.Key)
+
+		proof := &stateproof.Proof{}
+		if err := json.Unmarshal(proofBytes, proof); err != nil {
+			return errors.Wrapf(err, "invalid state proof of key %s of namespace '%s'", read.Key, ns.NameSpace)
+		}
+		if proof.Namespace != namespace || proof.Key != read.Key || proof.BlockNum != height-1 {
+			return errors.Errorf("state proof of key %s of namespace '%s' proves key %s of namespace %s at block %d",
+				read.Key, ns.NameSpace, proof.Key, proof.Namespace, proof.BlockNum)
+		}
+		if err := proof.Verify(); err != nil {
+			return errors.WithMessagef(err, "invalid state proof of key %s of namespace '%s'", read.Key, ns.NameSpace)
+		}
+		if (read.Version == nil) != (len(proof.Value) == 0) {
+			return errors.Errorf("state proof of key %s of namespace '%s' does not match its read", read.Key, ns.NameSpace)
+		}
+		if state.root == nil {
+			state.root = proof.Root
+		}
+		if !bytes.Equal(state.root, proof.Root) {
+			return errors.Errorf("state proofs of channel %s have different roots at height %d", otherChannelID, height)
+		}
+	}
+	if len(proofs) != 0 {
+		return errors.Errorf("namespace '%s' records state proofs of keys which were not read", ns.NameSpace)
+	}
+	return nil
+}
+
 func (v *dispatcherImpl) invokeValidationPlugin(ctx *Context) error {
 	logger.Debug("Validating", ctx, "with plugin")
 	err := v.pluginValidator.ValidateWithPlugin(ctx)
@@ -111,6 +111,9 @@ type TxValidator struct {
 	LedgerResources  LedgerResources
 	Dispatcher       Dispatcher
 	CryptoProvider   bccsp.BCCSP
+	// IdentityDeserializer, when set, verifies the creators of the transactions
+	// instead of the MSP manager registered for the channel in msp/mgmt
+	IdentityDeserializer msp.IdentityDeserializer
 }
 
 var logger = flogging.MustGetLogger("committer.txvalidator")
@@ -326,7 +329,12 @@ func (v *TxValidator) validateTx(req *blockValidationRequest, results chan<- *bl
 		var err error
 		var txResult peer.TxValidationCode
 
-		if payload, txResult = validation.ValidateTransaction(env, v.CryptoProvider); txResult != peer.TxValidationCode_VALID {
+		if v.IdentityDeserializer != nil {
+			payload, txResult = validation.ValidateTransactionWithDeserializer(env, v.IdentityDeserializer)
+		} else {
+			payload, txResult = validation.ValidateTransaction(env, v.CryptoProvider)
+		}
+		if txResult != peer.TxValidationCode_VALID {
 			logger.Errorf("Invalid transaction with index %d", tIdx)
 			results <- &blockValidationResult{
 				tIdx:           tIdx,
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package txvalidator_test
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -33,6 +34,7 @@ import (
 	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
 	"github.com/hyperledger/fabric/core/handlers/validation/builtin"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/stateproof"
 	mocktxvalidator "github.com/hyperledger/fabric/core/mocks/txvalidator"
 	"github.com/hyperledger/fabric/core/scc/lscc"
 	supportmocks "github.com/hyperledger/fabric/discovery/support/mocks"
@@ -160,6 +162,11 @@ func setupValidator() (*txvalidatorv20.TxValidator, *txvalidatormocks.QueryExecu
 }
 
 func setupValidatorWithMspMgr(mspmgr msp.MSPManager, mockID *supportmocks.Identity) (*txvalidatorv20.TxValidator, *txvalidatormocks.QueryExecutor, *supportmocks.Identity, *txvalidatormocks.CollectionResources) {
+	v, mockQE, mockCR := setupValidatorWithCapabilities(mspmgr, v20Capabilities())
+	return v, mockQE, mockID, mockCR
+}
+
+func setupValidatorWithCapabilities(mspmgr msp.MSPManager, ac *tmocks.ApplicationCapabilities) (*txvalidatorv20.TxValidator, *txvalidatormocks.QueryExecutor, *txvalidatormocks.CollectionResources) {
 	pm := &plugindispatchermocks.Mapper{}
 	factory := &plugindispatchermocks.PluginFactory{}
 	pm.On("FactoryByName", txvalidatorplugin.Name("vscc")).Return(factory)
@@ -183,7 +190,7 @@ func setupValidatorWithMspMgr(mspmgr msp.MSPManager, mockID *supportmocks.Identi
 	v := txvalidatorv20.NewTxValidator(
 		"",
 		semaphore.New(10),
-		&mocktxvalidator.Support{ACVal: v20Capabilities(), MSPManagerVal: mspmgr},
+		&mocktxvalidator.Support{ACVal: ac, MSPManagerVal: mspmgr},
 		mockLedger,
 		&lscc.SCC{BCCSP: cryptoProvider},
 		mockCR,
@@ -192,7 +199,7 @@ func setupValidatorWithMspMgr(mspmgr msp.MSPManager, mockID *supportmocks.Identi
 		cryptoProvider,
 	)
 
-	return v, mockQE, mockID, mockCR
+	return v, mockQE, mockCR
 }
 
 func TestInvokeBadRWSet(t *testing.T) {
@@ -249,6 +256,152 @@ func TestInvokeOK(t *testing.T) {
 	assertValid(b, t)
 }
 
+func TestInvokeCrossChannelReads(t *testing.T) {
+	ccID := "mycc"
+	otherNs := rwsetutil.CrossChannelNamespace("otherchannel", "othercc")
+
+	crossChannelRWSet := func(t *testing.T, addReads func(*rwsetutil.RWSetBuilder)) []byte {
+		rwsetBuilder := rwsetutil.NewRWSetBuilder()
+		rwsetBuilder.AddToWriteSet(ccID, "key", []byte("value"))
+		addReads(rwsetBuilder)
+		rwset, err := rwsetBuilder.GetTxSimulationResults()
+		require.NoError(t, err)
+		rwsetBytes, err := rwset.GetPubSimulationBytes()
+		require.NoError(t, err)
+		return rwsetBytes
+	}
+	// proofOf returns the proof of the value of the key in a state which only
+	// holds that key, or an empty state if value is nil
+	proofOf := func(key string, value []byte) *stateproof.Proof {
+		proof := &stateproof.Proof{BlockNum: 2, Namespace: "othercc", Key: key, Value: value, Root: stateproof.EmptyHash()}
+		if value != nil {
+			proof.Leaf = &stateproof.Leaf{KeyHash: stateproof.KeyHash("othercc", key), ValueHash: stateproof.ValueHash(value)}
+			proof.Root = stateproof.LeafHash(proof.Leaf.KeyHash, proof.Leaf.ValueHash)
+		}
+		return proof
+	}
+	addProof := func(b *rwsetutil.RWSetBuilder, proof *stateproof.Proof) {
+		proofBytes, err := json.Marshal(proof)
+		require.NoError(t, err)
+		b.AddToMetadataWriteSet(otherNs, proof.Key, map[string][]byte{rwsetutil.CrossChannelProofEntry: proofBytes})
+	}
+	addHeight := func(b *rwsetutil.RWSetBuilder) {
+		b.AddToReadSet(otherNs, rwsetutil.CrossChannelHeightKey, rwsetutil.NewVersion(&kvrwset.Version{BlockNum: 3}))
+	}
+	validReads := func(b *rwsetutil.RWSetBuilder) {
+		b.AddToReadSet(otherNs, "key1", rwsetutil.NewVersion(&kvrwset.Version{BlockNum: 1}))
+		addProof(b, proofOf("key1", []byte("value1")))
+		addHeight(b)
+	}
+
+	setup := func(crossChannelReads bool) *txvalidatorv20.TxValidator {
+		mspmgr := &supportmocks.MSPManager{}
+		mockID := &supportmocks.Identity{}
+		mockID.SatisfiesPrincipalReturns(nil)
+		mockID.GetIdentifierReturns(&msp.IdentityIdentifier{})
+		mspmgr.DeserializeIdentityReturns(mockID, nil)
+
+		ac := v20Capabilities()
+		ac.On("CrossChannelReads").Return(crossChannelReads)
+		v, mockQE, _ := setupValidatorWithCapabilities(mspmgr, ac)
+		mockQE.On("GetState", "lscc", ccID).Return(protoutil.MarshalOrPanic(&ccp.ChaincodeData{
+			Name:    ccID,
+			Version: ccVersion,
+			Vscc:    "vscc",
+			Policy:  signedByAnyMember([]string{"SampleOrg"}),
+		}), nil)
+		mockQE.On("GetStateMetadata", ccID, "key").Return(nil, nil)
+		return v
+	}
+
+	validate := func(t *testing.T, v *txvalidatorv20.TxValidator, rwset []byte) (*common.Block, error) {
+		tx := getEnv(ccID, nil, rwset, t)
+		b := &common.Block{Data: &common.BlockData{Data: [][]byte{protoutil.MarshalOrPanic(tx)}}, Header: &common.BlockHeader{Number: 2}}
+		return b, v.Validate(b)
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		b, err := validate(t, setup(true), crossChannelRWSet(t, validReads))
+		require.NoError(t, err)
+		assertValid(b, t)
+	})
+
+	t.Run("valid read of a missing key", func(t *testing.T) {
+		b, err := validate(t, setup(true), crossChannelRWSet(t, func(b *rwsetutil.RWSetBuilder) {
+			b.AddToReadSet(otherNs, "missing", nil)
+			addProof(b, proofOf("missing", nil))
+			addHeight(b)
+		}))
+		require.NoError(t, err)
+		assertValid(b, t)
+	})
+
+	invalidReads := map[string]func(b *rwsetutil.RWSetBuilder){
+		"missing proof": func(b *rwsetutil.RWSetBuilder) {
+			b.AddToReadSet(otherNs, "key1", rwsetutil.NewVersion(&kvrwset.Version{BlockNum: 1}))
+			addHeight(b)
+		},
+		"proof of a key which was not read": func(b *rwsetutil.RWSetBuilder) {
+			validReads(b)
+			addProof(b, proofOf("key2", []byte("value2")))
+		},
+		"tampered proof": func(b *rwsetutil.RWSetBuilder) {
+			b.AddToReadSet(otherNs, "key1", rwsetutil.NewVersion(&kvrwset.Version{BlockNum: 1}))
+			proof := proofOf("key1", []byte("value1"))
+			proof.Value = []byte("value2")
+			proof.Leaf.ValueHash = stateproof.ValueHash(proof.Value)
+			proof.Root = proofOf("key1", []byte("value1")).Root
+			addProof(b, proof)
+			addHeight(b)
+		},
+		"proof at another height": func(b *rwsetutil.RWSetBuilder) {
+			b.AddToReadSet(otherNs, "key1", rwsetutil.NewVersion(&kvrwset.Version{BlockNum: 1}))
+			proof := proofOf("key1", []byte("value1"))
+			proof.BlockNum = 1
+			addProof(b, proof)
+			addHeight(b)
+		},
+		"proof of absence of a key which was read": func(b *rwsetutil.RWSetBuilder) {
+			b.AddToReadSet(otherNs, "key1", rwsetutil.NewVersion(&kvrwset.Version{BlockNum: 1}))
+			addProof(b, proofOf("key1", nil))
+			addHeight(b)
+		},
+		"proofs against different roots": func(b *rwsetutil.RWSetBuilder) {
+			validReads(b)
+			b.AddToReadSet(otherNs, "key2", rwsetutil.NewVersion(&kvrwset.Version{BlockNum: 1}))
+			addProof(b, proofOf("key2", []byte("value2")))
+		},
+		"other metadata": func(b *rwsetutil.RWSetBuilder) {
+			validReads(b)
+			b.AddToMetadataWriteSet(otherNs, "key1", map[string][]byte{"VALIDATION_PARAMETER": []byte("policy")})
+		},
+		"missing height": func(b *rwsetutil.RWSetBuilder) {
+			b.AddToReadSet(otherNs, "key1", rwsetutil.NewVersion(&kvrwset.Version{BlockNum: 1}))
+			addProof(b, proofOf("key1", []byte("value1")))
+		},
+		"writes to other channel": func(b *rwsetutil.RWSetBuilder) {
+			validReads(b)
+			b.AddToWriteSet(otherNs, "key1", []byte("value"))
+		},
+		"reads of own channel": func(b *rwsetutil.RWSetBuilder) {
+			b.AddToReadSet(rwsetutil.CrossChannelNamespace("testchannelid", "othercc"), rwsetutil.CrossChannelHeightKey, rwsetutil.NewVersion(&kvrwset.Version{BlockNum: 3}))
+		},
+	}
+	for name, addReads := range invalidReads {
+		t.Run(name, func(t *testing.T) {
+			b, err := validate(t, setup(true), crossChannelRWSet(t, addReads))
+			require.NoError(t, err)
+			assertInvalid(b, t, peer.TxValidationCode_BAD_RWSET)
+		})
+	}
+
+	t.Run("capability disabled", func(t *testing.T) {
+		b, err := validate(t, setup(false), crossChannelRWSet(t, validReads))
+		require.NoError(t, err)
+		assertInvalid(b, t, peer.TxValidationCode_BAD_RWSET)
+	})
+}
+
 func TestInvokeNOKDuplicateNs(t *testing.T) {
 	ccID := "mycc"
 
@@ -12,6 +12,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"unicode"
 
 	"github.com/golang/protobuf/proto"
@@ -417,4 +418,8 @@ type TransactionParams struct {
 
 	// this is additional data passed to the chaincode
 	ProposalDecorations map[string][]byte
+
+	// CrossChannelInvoked, when not nil, is set once the transaction invokes
+	// a chaincode on a channel other than its own.
+	CrossChannelInvoked *atomic.Bool
 }
@@ -0,0 +1,188 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package redaction removes the chaincode data of the namespaces a caller is
+// not authorized to read from blocks and transactions.
+package redaction
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("redaction")
+
+// NamespaceFilter reports whether the chaincode data of a namespace may be disclosed.
+type NamespaceFilter func(namespace string) bool
+
+// ACLProvider checks access control for a resource.
+type ACLProvider interface {
+	CheckACL(resName string, channelID string, idinfo interface{}) error
+}
+
+// ACLFilter returns a NamespaceFilter which discloses the namespaces whose
+// read ACL is satisfied by idinfo. The result of the check is remembered
+// for each namespace.
+func ACLFilter(aclProvider ACLProvider, channelID string, idinfo interface{}) NamespaceFilter {
+	allowed := map[string]bool{}
+	return func(namespace string) bool {
+		if a, ok := allowed[namespace]; ok {
+			return a
+		}
+		err := aclProvider.CheckACL(resources.NamespaceRead(namespace), channelID, idinfo)
+		if err != nil {
+			logger.Debugf("Redacting namespace %s on channel %s: %s", namespace, channelID, err)
+		}
+		allowed[namespace] = err == nil
+		return err == nil
+	}
+}
+
+// Block returns a copy of the block in which the chaincode data of the
+// namespaces which are not allowed by the filter is removed from every
+// endorser transaction. The block header and metadata are left untouched,
+// hence the data hash and the signatures of redacted transactions no longer
+// match their contents.
+func Block(block *common.Block, allowed NamespaceFilter) *common.Block {
+	redacted := &common.Block{
+		Header:   block.Header,
+		Metadata: block.Metadata,
+	}
+	if block.Data == nil {
+		return redacted
+	}
+
+	redacted.Data = &common.BlockData{Data: make([][]byte, len(block.Data.Data))}
+	for i, envBytes := range block.Data.Data {
+		redacted.Data.Data[i] = redactEnvelopeBytes(envBytes, allowed)
+	}
+	return redacted
+}
+
+// Envelope returns a copy of the envelope in which the chaincode data of the
+// namespaces which are not allowed by the filter is removed.
+func Envelope(env *common.Envelope, allowed NamespaceFilter) (*common.Envelope, error) {
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("missing payload header")
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	if common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
+		return env, nil
+	}
+
+	tx, err := protoutil.UnmarshalTransaction(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, action := range tx.Actions {
+		if tx.Actions[i].Payload, err = redactActionPayload(action.Payload, allowed); err != nil {
+			return nil, errors.WithMessagef(err, "could not redact action %d", i)
+		}
+	}
+
+	if payload.Data, err = proto.Marshal(tx); err != nil {
+		return nil, errors.Wrap(err, "could not marshal transaction")
+	}
+	payloadBytes, err := proto.Marshal(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not marshal payload")
+	}
+
+	return &common.Envelope{Payload: payloadBytes, Signature: env.Signature}, nil
+}
+
+func redactEnvelopeBytes(envBytes []byte, allowed NamespaceFilter) []byte {
+	env, err := protoutil.UnmarshalEnvelope(envBytes)
+	if err == nil {
+		env, err = Envelope(env, allowed)
+	}
+	if err != nil {
+		// nothing is disclosed about transactions which cannot be parsed
+		logger.Debugf("Redacting malformed transaction: %s", err)
+		return protoutil.MarshalOrPanic(&common.Envelope{})
+	}
+	envBytes, err = proto.Marshal(env)
+	if err != nil {
+		return protoutil.MarshalOrPanic(&common.Envelope{})
+	}
+	return envBytes
+}
+
+func redactActionPayload(actionPayload []byte, allowed NamespaceFilter) ([]byte, error) {
+	ccActionPayload, err := protoutil.UnmarshalChaincodeActionPayload(actionPayload)
+	if err != nil {
+		return nil, err
+	}
+	if ccActionPayload.Action == nil {
+		return nil, errors.New("missing endorsed action")
+	}
+
+	prp, err := protoutil.UnmarshalProposalResponsePayload(ccActionPayload.Action.ProposalResponsePayload)
+	if err != nil {
+		return nil, err
+	}
+	ccAction, err := protoutil.UnmarshalChaincodeAction(prp.Extension)
+	if err != nil {
+		return nil, err
+	}
+
+	if ccAction.ChaincodeId == nil || !allowed(ccAction.ChaincodeId.Name) {
+		// the input and the response belong to the invoked chaincode
+		ccActionPayload.ChaincodeProposalPayload = nil
+		if ccAction.Response != nil {
+			ccAction.Response = &peer.Response{Status: ccAction.Response.Status}
+		}
+	}
+
+	if len(ccAction.Events) > 0 {
+		event, err := protoutil.UnmarshalChaincodeEvents(ccAction.Events)
+		if err != nil || !allowed(event.ChaincodeId) {
+			ccAction.Events = nil
+		}
+	}
+
+	if len(ccAction.Results) > 0 {
+		txRWSet := &rwset.TxReadWriteSet{}
+		if err := proto.Unmarshal(ccAction.Results, txRWSet); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal read-write set")
+		}
+		for i, nsRWSet := range txRWSet.NsRwset {
+			if !allowed(nsRWSet.Namespace) {
+				txRWSet.NsRwset[i] = &rwset.NsReadWriteSet{Namespace: nsRWSet.Namespace}
+			}
+		}
+		if ccAction.Results, err = proto.Marshal(txRWSet); err != nil {
+			return nil, errors.Wrap(err, "could not marshal read-write set")
+		}
+	}
+
+	if prp.Extension, err = proto.Marshal(ccAction); err != nil {
+		return nil, errors.Wrap(err, "could not marshal chaincode action")
+	}
+	endorsedAction := &peer.ChaincodeEndorsedAction{
+		Endorsements: ccActionPayload.Action.Endorsements,
+	}
+	if endorsedAction.ProposalResponsePayload, err = proto.Marshal(prp); err != nil {
+		return nil, errors.Wrap(err, "could not marshal proposal response payload")
+	}
+	ccActionPayload.Action = endorsedAction
+
+	return proto.Marshal(ccActionPayload)
+}
@@ -0,0 +1,182 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package redaction
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeACLProvider struct {
+	allowed map[string]bool
+	checks  []string
+}
+
+func (f *fakeACLProvider) CheckACL(resName string, channelID string, idinfo interface{}) error {
+	f.checks = append(f.checks, resName)
+	if !f.allowed[resName] {
+		return errors.Errorf("access denied for %s", resName)
+	}
+	return nil
+}
+
+func constructEnvelope(t *testing.T, chaincodeName string) *common.Envelope {
+	txRWSet := &rwset.TxReadWriteSet{
+		DataModel: rwset.TxReadWriteSet_KV,
+		NsRwset: []*rwset.NsReadWriteSet{
+			{Namespace: "cc1", Rwset: []byte("cc1-rwset")},
+			{Namespace: "cc2", Rwset: []byte("cc2-rwset")},
+		},
+	}
+	events := protoutil.MarshalOrPanic(&peer.ChaincodeEvent{
+		ChaincodeId: chaincodeName,
+		EventName:   "event",
+		Payload:     []byte("event-payload"),
+	})
+
+	env, _, err := testutil.ConstructUnsignedTxEnv(
+		"testchannelid",
+		&peer.ChaincodeID{Name: chaincodeName, Version: "v1"},
+		&peer.Response{Status: 200, Message: "ok", Payload: []byte("response-payload")},
+		protoutil.MarshalOrPanic(txRWSet),
+		"txid",
+		events,
+		nil,
+		common.HeaderType_ENDORSER_TRANSACTION,
+	)
+	require.NoError(t, err)
+	return env
+}
+
+func chaincodeAction(t *testing.T, env *common.Envelope) (*peer.ChaincodeActionPayload, *peer.ChaincodeAction, *rwset.TxReadWriteSet) {
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	require.NoError(t, err)
+	tx, err := protoutil.UnmarshalTransaction(payload.Data)
+	require.NoError(t, err)
+	require.Len(t, tx.Actions, 1)
+	ccActionPayload, err := protoutil.UnmarshalChaincodeActionPayload(tx.Actions[0].Payload)
+	require.NoError(t, err)
+	prp, err := protoutil.UnmarshalProposalResponsePayload(ccActionPayload.Action.ProposalResponsePayload)
+	require.NoError(t, err)
+	ccAction, err := protoutil.UnmarshalChaincodeAction(prp.Extension)
+	require.NoError(t, err)
+	txRWSet := &rwset.TxReadWriteSet{}
+	require.NoError(t, proto.Unmarshal(ccAction.Results, txRWSet))
+	return ccActionPayload, ccAction, txRWSet
+}
+
+func TestACLFilter(t *testing.T) {
+	aclProvider := &fakeACLProvider{allowed: map[string]bool{resources.NamespaceRead("cc1"): true}}
+	allowed := ACLFilter(aclProvider, "testchannelid", nil)
+
+	require.True(t, allowed("cc1"))
+	require.False(t, allowed("cc2"))
+	require.True(t, allowed("cc1"))
+	require.False(t, allowed("cc2"))
+	require.Equal(t, []string{"namespace/cc1/Read", "namespace/cc2/Read"}, aclProvider.checks)
+}
+
+func TestEnvelope(t *testing.T) {
+	env := constructEnvelope(t, "cc1")
+
+	t.Run("AllAllowed", func(t *testing.T) {
+		redacted, err := Envelope(env, func(string) bool { return true })
+		require.NoError(t, err)
+
+		_, ccAction, txRWSet := chaincodeAction(t, redacted)
+		require.Equal(t, []byte("response-payload"), ccAction.Response.Payload)
+		require.NotEmpty(t, ccAction.Events)
+		require.Equal(t, []byte("cc1-rwset"), txRWSet.NsRwset[0].Rwset)
+		require.Equal(t, []byte("cc2-rwset"), txRWSet.NsRwset[1].Rwset)
+	})
+
+	t.Run("InvokedChaincodeAllowed", func(t *testing.T) {
+		redacted, err := Envelope(env, func(ns string) bool { return ns == "cc1" })
+		require.NoError(t, err)
+		require.Equal(t, env.Signature, redacted.Signature)
+
+		ccActionPayload, ccAction, txRWSet := chaincodeAction(t, redacted)
+		require.NotNil(t, ccActionPayload.ChaincodeProposalPayload)
+		require.NotEmpty(t, ccActionPayload.Action.Endorsements)
+		require.Equal(t, []byte("response-payload"), ccAction.Response.Payload)
+		require.NotEmpty(t, ccAction.Events)
+		require.Equal(t, []byte("cc1-rwset"), txRWSet.NsRwset[0].Rwset)
+		require.True(t, proto.Equal(&rwset.NsReadWriteSet{Namespace: "cc2"}, txRWSet.NsRwset[1]))
+	})
+
+	t.Run("InvokedChaincodeNotAllowed", func(t *testing.T) {
+		redacted, err := Envelope(env, func(ns string) bool { return ns == "cc2" })
+		require.NoError(t, err)
+
+		ccActionPayload, ccAction, txRWSet := chaincodeAction(t, redacted)
+		require.Nil(t, ccActionPayload.ChaincodeProposalPayload)
+		require.NotEmpty(t, ccActionPayload.Action.Endorsements)
+		require.True(t, proto.Equal(&peer.Response{Status: 200}, ccAction.Response))
+		require.Empty(t, ccAction.Events)
+		require.Equal(t, "cc1", ccAction.ChaincodeId.Name)
+		require.True(t, proto.Equal(&rwset.NsReadWriteSet{Namespace: "cc1"}, txRWSet.NsRwset[0]))
+		require.Equal(t, []byte("cc2-rwset"), txRWSet.NsRwset[1].Rwset)
+	})
+
+	t.Run("NotEndorserTransaction", func(t *testing.T) {
+		configEnv, _, err := testutil.ConstructUnsignedTxEnv(
+			"testchannelid",
+			&peer.ChaincodeID{Name: "cc1"},
+			nil,
+			nil,
+			"txid",
+			nil,
+			nil,
+			common.HeaderType_CONFIG,
+		)
+		require.NoError(t, err)
+
+		redacted, err := Envelope(configEnv, func(string) bool { return false })
+		require.NoError(t, err)
+		require.True(t, proto.Equal(configEnv, redacted))
+	})
+
+	t.Run("BadPayload", func(t *testing.T) {
+		_, err := Envelope(&common.Envelope{Payload: []byte("garbage")}, func(string) bool { return false })
+		require.Error(t, err)
+	})
+}
+
+func TestBlock(t *testing.T) {
+	block := testutil.NewBlock([]*common.Envelope{constructEnvelope(t, "cc1"), constructEnvelope(t, "cc2")}, 1, []byte("previous-hash"))
+	block.Data.Data = append(block.Data.Data, []byte("garbage"))
+
+	redacted := Block(block, func(ns string) bool { return ns == "cc1" })
+	require.True(t, proto.Equal(block.Header, redacted.Header))
+	require.True(t, proto.Equal(block.Metadata, redacted.Metadata))
+	require.Len(t, redacted.Data.Data, 3)
+
+	env, err := protoutil.GetEnvelopeFromBlock(redacted.Data.Data[0])
+	require.NoError(t, err)
+	_, ccAction, _ := chaincodeAction(t, env)
+	require.Equal(t, []byte("response-payload"), ccAction.Response.Payload)
+
+	env, err = protoutil.GetEnvelopeFromBlock(redacted.Data.Data[1])
+	require.NoError(t, err)
+	_, ccAction, txRWSet := chaincodeAction(t, env)
+	require.Empty(t, ccAction.Response.Payload)
+	require.Equal(t, []byte("cc1-rwset"), txRWSet.NsRwset[0].Rwset)
+	require.Empty(t, txRWSet.NsRwset[1].Rwset)
+
+	require.Empty(t, redacted.Data.Data[2])
+
+	require.Nil(t, Block(&common.Block{Header: block.Header}, nil).Data)
+}
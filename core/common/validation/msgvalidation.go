@@ -13,6 +13,7 @@ import (
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/msp"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
@@ -36,6 +37,12 @@ func checkSignatureFromCreator(creatorBytes, sig, msg []byte, ChannelID string,
 		return errors.Errorf("could not get msp for channel [%s]", ChannelID)
 	}
 
+	return checkSignature(creatorBytes, sig, msg, mspObj)
+}
+
+// checkSignature returns nil if the creator deserialized by mspObj
+// is a valid cert and the signature is valid
+func checkSignature(creatorBytes, sig, msg []byte, mspObj msp.IdentityDeserializer) error {
 	// get the identity of the creator
 	creator, err := mspObj.DeserializeIdentity(creatorBytes)
 	if err != nil {
@@ -246,6 +253,24 @@ func validateEndorserTransaction(data []byte, hdr *common.Header) error {
 
 // ValidateTransaction checks that the transaction envelope is properly formed
 func ValidateTransaction(e *common.Envelope, cryptoProvider bccsp.BCCSP) (*common.Payload, pb.TxValidationCode) {
+	return validateTransaction(e, func(creatorBytes, sig, msg []byte, channelID string) error {
+		return checkSignatureFromCreator(creatorBytes, sig, msg, channelID, cryptoProvider)
+	})
+}
+
+// ValidateTransactionWithDeserializer checks that the transaction envelope is properly
+// formed, verifying its creator with the given identity deserializer rather than with
+// the MSP manager registered for the channel of the transaction
+func ValidateTransactionWithDeserializer(e *common.Envelope, deserializer msp.IdentityDeserializer) (*common.Payload, pb.TxValidationCode) {
+	return validateTransaction(e, func(creatorBytes, sig, msg []byte, channelID string) error {
+		if creatorBytes == nil || sig == nil || msg == nil {
+			return errors.New("nil arguments")
+		}
+		return checkSignature(creatorBytes, sig, msg, deserializer)
+	})
+}
+
+func validateTransaction(e *common.Envelope, checkCreator func(creatorBytes, sig, msg []byte, channelID string) error) (*common.Payload, pb.TxValidationCode) {
 	putilsLogger.Debugf("ValidateTransactionEnvelope starts for envelope %p", e)
 
 	// check for nil argument
@@ -271,7 +296,7 @@ func ValidateTransaction(e *common.Envelope, cryptoProvider bccsp.BCCSP) (*commo
 	}
 
 	// validate the signature in the envelope
-	err = checkSignatureFromCreator(shdr.Creator, e.Signature, e.Payload, chdr.ChannelId, cryptoProvider)
+	err = checkCreator(shdr.Creator, e.Signature, e.Payload, chdr.ChannelId)
 	if err != nil {
 		putilsLogger.Errorf("checkSignatureFromCreator returns err %s", err)
 		return nil, pb.TxValidationCode_BAD_CREATOR_SIGNATURE
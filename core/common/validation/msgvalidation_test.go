@@ -13,6 +13,7 @@ import (
 	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp/sw"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/stretchr/testify/require"
 )
@@ -81,3 +82,21 @@ func TestCheckSignatureFromCreator(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "MSP error: channel doesn't exist")
 }
+
+func TestValidateTransactionWithDeserializer(t *testing.T) {
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	deserializer := mspmgmt.GetIdentityDeserializer("testchannelid", cryptoProvider)
+
+	// the MSP manager registered for the channel is not consulted
+	env, err := createTestTransactionEnvelope("junkchannel", &peer.Response{Status: 200}, []byte("simulation_result"))
+	require.NoError(t, err)
+	_, txResult := ValidateTransaction(env, cryptoProvider)
+	require.Equal(t, peer.TxValidationCode_BAD_CREATOR_SIGNATURE, txResult)
+	_, txResult = ValidateTransactionWithDeserializer(env, deserializer)
+	require.Equal(t, peer.TxValidationCode_VALID, txResult)
+
+	env.Signature = []byte("junk")
+	_, txResult = ValidateTransactionWithDeserializer(env, deserializer)
+	require.Equal(t, peer.TxValidationCode_BAD_CREATOR_SIGNATURE, txResult)
+}
@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hyperledger/fabric/common/audit"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/config/dynamic"
+)
+
+// URLBase is the path under which the handler is registered.
+const URLBase = "/config"
+
+// Registry is the part of the configuration registry used by the handler.
+type Registry interface {
+	Entries() []dynamic.Entry
+	Update(values map[string]string) error
+}
+
+// ConfigResponse reports the effective configuration of the node.
+type ConfigResponse struct {
+	Settings []dynamic.Entry `json:"settings"`
+}
+
+// UpdateRequest holds the new values of the settings to change, by key.
+type UpdateRequest struct {
+	Settings map[string]string `json:"settings"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func NewHandler(registry Registry) *Handler {
+	return &Handler{
+		Registry: registry,
+		Logger:   flogging.MustGetLogger("config.dynamic.httpadmin"),
+	}
+}
+
+// Handler reports the effective configuration of the node, and changes the
+// settings that can be changed at runtime.
+type Handler struct {
+	Registry    Registry
+	Logger      *flogging.FabricLogger
+	AuditLogger *audit.Logger
+}
+
+func (h *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	if req.URL.Path != URLBase {
+		h.sendResponse(resp, http.StatusNotFound, fmt.Errorf("invalid path: %s", req.URL.Path))
+		return
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		h.sendResponse(resp, http.StatusOK, &ConfigResponse{Settings: h.Registry.Entries()})
+
+	case http.MethodPut:
+		var update UpdateRequest
+		decoder := json.NewDecoder(req.Body)
+		if err := decoder.Decode(&update); err != nil {
+			h.sendResponse(resp, http.StatusBadRequest, err)
+			return
+		}
+		req.Body.Close()
+
+		err := h.Registry.Update(update.Settings)
+		h.AuditLogger.Log("operations", "UpdateConfig", audit.CallerFromHTTPRequest(req), update.Settings, err)
+		if err != nil {
+			h.sendResponse(resp, http.StatusBadRequest, err)
+			return
+		}
+		h.sendResponse(resp, http.StatusOK, &ConfigResponse{Settings: h.Registry.Entries()})
+
+	default:
+		h.sendResponse(resp, http.StatusMethodNotAllowed, fmt.Errorf("invalid request method: %s", req.Method))
+	}
+}
+
+func (h *Handler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	encoder := json.NewEncoder(resp)
+	if err, ok := payload.(error); ok {
+		payload = &ErrorResponse{Error: err.Error()}
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+
+	if err := encoder.Encode(payload); err != nil {
+		h.Logger.Errorw("failed to encode payload", "error", err)
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/audit"
+	"github.com/hyperledger/fabric/core/config/dynamic"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRegistry(t *testing.T) (*dynamic.Registry, *time.Duration) {
+	v := viper.New()
+	v.Set("peer.id", "peer0")
+	registry, err := dynamic.NewRegistry(v, "CORE",
+		dynamic.DurationSetting("peer.gateway.endorsementTimeout", 30*time.Second, time.Second),
+	)
+	require.NoError(t, err)
+
+	endorsementTimeout := 30 * time.Second
+	require.NoError(t, registry.SubscribeDuration("peer.gateway.endorsementTimeout", func(d time.Duration) {
+		endorsementTimeout = d
+	}))
+	return registry, &endorsementTimeout
+}
+
+func serve(t *testing.T, h *Handler, method, path string, body io.Reader, response interface{}) int {
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(method, path, body))
+	require.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), response))
+	return resp.Code
+}
+
+func TestHandlerGetConfig(t *testing.T) {
+	registry, _ := newTestRegistry(t)
+	h := NewHandler(registry)
+
+	config := &ConfigResponse{}
+	require.Equal(t, http.StatusOK, serve(t, h, http.MethodGet, URLBase, nil, config))
+	require.Equal(t, []dynamic.Entry{
+		{Key: "peer.gateway.endorsementTimeout", Value: "30s", Source: dynamic.SourceDefault, Dynamic: true},
+		{Key: "peer.id", Value: "peer0", Source: dynamic.SourceDefault},
+	}, config.Settings)
+
+	errResp := &ErrorResponse{}
+	require.Equal(t, http.StatusMethodNotAllowed, serve(t, h, http.MethodPost, URLBase, nil, errResp))
+	require.Equal(t, "invalid request method: POST", errResp.Error)
+	require.Equal(t, http.StatusNotFound, serve(t, h, http.MethodGet, URLBase+"/unknown", nil, errResp))
+	require.Equal(t, "invalid path: /config/unknown", errResp.Error)
+}
+
+func TestHandlerUpdateConfig(t *testing.T) {
+	registry, endorsementTimeout := newTestRegistry(t)
+	buff := &bytes.Buffer{}
+	h := NewHandler(registry)
+	h.AuditLogger = audit.NewLogger(buff, nil)
+
+	config := &ConfigResponse{}
+	body := strings.NewReader(`{"settings": {"peer.gateway.endorsementTimeout": "5s"}}`)
+	require.Equal(t, http.StatusOK, serve(t, h, http.MethodPut, URLBase, body, config))
+	require.Equal(t, dynamic.Entry{Key: "peer.gateway.endorsementTimeout", Value: "5s", Source: dynamic.SourceRuntime, Dynamic: true}, config.Settings[0])
+	require.Equal(t, 5*time.Second, *endorsementTimeout)
+
+	record, err := audit.Verify(bytes.NewReader(buff.Bytes()), "")
+	require.NoError(t, err)
+	require.Equal(t, "operations", record.Component)
+	require.Equal(t, "UpdateConfig", record.Action)
+	require.Equal(t, map[string]string{"peer.gateway.endorsementTimeout": "5s"}, record.Parameters)
+	require.Equal(t, audit.OutcomeSuccess, record.Outcome)
+
+	errResp := &ErrorResponse{}
+	body = strings.NewReader(`{"settings": {"peer.id": "peer1"}}`)
+	require.Equal(t, http.StatusBadRequest, serve(t, h, http.MethodPut, URLBase, body, errResp))
+	require.Equal(t, "setting peer.id cannot be changed at runtime", errResp.Error)
+
+	record, err = audit.Verify(bytes.NewReader(buff.Bytes()), "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), record.Sequence)
+	require.Equal(t, audit.OutcomeFailure, record.Outcome)
+	require.Equal(t, "setting peer.id cannot be changed at runtime", record.Error)
+
+	require.Equal(t, http.StatusBadRequest, serve(t, h, http.MethodPut, URLBase, strings.NewReader("{"), errResp))
+	require.Equal(t, "unexpected EOF", errResp.Error)
+}
@@ -0,0 +1,325 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package dynamic keeps track of the effective configuration of a node, and
+// changes the settings that are safe to change while the node is running.
+package dynamic
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+var logger = flogging.MustGetLogger("config.dynamic")
+
+// The sources a setting can get its value from, from the lowest to the
+// highest precedence.
+const (
+	SourceDefault     = "default"
+	SourceFile        = "file"
+	SourceEnvironment = "environment"
+	SourceRuntime     = "runtime"
+)
+
+// redacted replaces the values of settings that hold secrets.
+const redacted = "[REDACTED]"
+
+// Setting is a setting that can be changed at runtime.
+type Setting struct {
+	// Key is the key of the setting in the configuration, such as
+	// peer.gateway.endorsementTimeout.
+	Key string
+	// Value is the value in effect when the node starts, after defaults
+	// have been applied.
+	Value interface{}
+	// Parse validates a new value of the setting and converts it to the type
+	// its subscribers are notified with.
+	Parse func(value string) (interface{}, error)
+}
+
+// DurationSetting creates a setting holding a duration of at least min.
+func DurationSetting(key string, value, min time.Duration) Setting {
+	return Setting{
+		Key:   key,
+		Value: value,
+		Parse: func(s string) (interface{}, error) {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return nil, err
+			}
+			if d < min {
+				return nil, errors.Errorf("must be at least %s", min)
+			}
+			return d, nil
+		},
+	}
+}
+
+// IntSetting creates a setting holding an integer of at least min.
+func IntSetting(key string, value, min int) Setting {
+	return Setting{
+		Key:   key,
+		Value: value,
+		Parse: func(s string) (interface{}, error) {
+			i, err := strconv.Atoi(s)
+			if err != nil {
+				return nil, errors.Errorf("'%s' is not an integer", s)
+			}
+			if i < min {
+				return nil, errors.Errorf("must be at least %d", min)
+			}
+			return i, nil
+		},
+	}
+}
+
+// Entry reports the effective value of a setting and where it comes from.
+type Entry struct {
+	Key     string      `json:"key"`
+	Value   interface{} `json:"value"`
+	Source  string      `json:"source"`
+	Dynamic bool        `json:"dynamic"`
+}
+
+type entry struct {
+	setting *Setting // nil when the setting cannot be changed at runtime
+	value   interface{}
+	source  string
+}
+
+// Registry holds the effective configuration of a node. The settings that are
+// registered as dynamic can be changed at runtime, and the components that use
+// them are notified of their changes.
+type Registry struct {
+	updateLock sync.Mutex // serializes updates and the notification of subscribers
+
+	mutex       sync.RWMutex
+	entries     map[string]*entry // by lower case key
+	subscribers map[string][]func(interface{})
+}
+
+// NewRegistry takes a snapshot of the configuration of v, and registers the
+// settings that can be changed at runtime. Settings that are set in the
+// environment are recognized by the environment prefix of v.
+func NewRegistry(v *viper.Viper, envPrefix string, settings ...Setting) (*Registry, error) {
+	fileConfig := viper.New()
+	if v.ConfigFileUsed() != "" {
+		fileConfig.SetConfigFile(v.ConfigFileUsed())
+		if err := fileConfig.ReadInConfig(); err != nil {
+			return nil, errors.Wrapf(err, "failed to read config file %s", v.ConfigFileUsed())
+		}
+	}
+	source := func(key string) string {
+		envVar := strings.ToUpper(envPrefix + "_" + strings.ReplaceAll(key, ".", "_"))
+		if _, ok := os.LookupEnv(envVar); ok {
+			return SourceEnvironment
+		}
+		if fileConfig.IsSet(key) {
+			return SourceFile
+		}
+		return SourceDefault
+	}
+
+	r := &Registry{
+		entries:     map[string]*entry{},
+		subscribers: map[string][]func(interface{}){},
+	}
+	for _, key := range v.AllKeys() {
+		value := jsonValue(v.Get(key))
+		if isSecret(key) {
+			value = redacted
+		}
+		r.entries[key] = &entry{value: value, source: source(key)}
+	}
+	for i := range settings {
+		s := &settings[i]
+		key := strings.ToLower(s.Key)
+		if s.Parse == nil {
+			return nil, errors.Errorf("setting %s has no parser", s.Key)
+		}
+		if _, ok := r.entries[key]; ok && r.entries[key].setting != nil {
+			return nil, errors.Errorf("setting %s is registered more than once", s.Key)
+		}
+		r.entries[key] = &entry{setting: s, value: s.Value, source: source(key)}
+	}
+
+	return r, nil
+}
+
+// Subscribe registers a function that is called with the parsed value of the
+// setting whenever the setting is changed.
+func (r *Registry) Subscribe(key string, fn func(value interface{})) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key = strings.ToLower(key)
+	if e, ok := r.entries[key]; !ok || e.setting == nil {
+		return errors.Errorf("setting %s cannot be changed at runtime", key)
+	}
+	r.subscribers[key] = append(r.subscribers[key], fn)
+	return nil
+}
+
+// SubscribeDuration registers a function that is called whenever the duration
+// setting is changed.
+func (r *Registry) SubscribeDuration(key string, fn func(time.Duration)) error {
+	return r.Subscribe(key, func(value interface{}) { fn(value.(time.Duration)) })
+}
+
+// SubscribeInt registers a function that is called whenever the integer
+// setting is changed.
+func (r *Registry) SubscribeInt(key string, fn func(int)) error {
+	return r.Subscribe(key, func(value interface{}) { fn(value.(int)) })
+}
+
+// Value returns the effective value of the setting, which is the value of the
+// setting as it was registered until the setting is changed at runtime.
+func (r *Registry) Value(key string) interface{} {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if e, ok := r.entries[strings.ToLower(key)]; ok {
+		return e.value
+	}
+	return nil
+}
+
+// Entries returns the effective configuration, sorted by key.
+func (r *Registry) Entries() []Entry {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	entries := make([]Entry, 0, len(r.entries))
+	for key, e := range r.entries {
+		ent := Entry{Key: key, Value: e.value, Source: e.source}
+		if e.setting != nil {
+			ent.Key = e.setting.Key
+			ent.Value = displayValue(e.value)
+			ent.Dynamic = true
+		}
+		entries = append(entries, ent)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return strings.ToLower(entries[i].Key) < strings.ToLower(entries[j].Key)
+	})
+	return entries
+}
+
+// Update changes the settings to the values, where keys are case insensitive.
+// Either all settings are changed, or none of them when a setting cannot be
+// changed at runtime or a value is invalid. The subscribers of the settings are
+// notified before Update returns.
+func (r *Registry) Update(values map[string]string) error {
+	if len(values) == 0 {
+		return errors.New("no settings to update")
+	}
+
+	r.updateLock.Lock()
+	defer r.updateLock.Unlock()
+
+	type change struct {
+		key   string
+		value interface{}
+	}
+	var changes []change
+
+	r.mutex.Lock()
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		e, ok := r.entries[strings.ToLower(key)]
+		switch {
+		case !ok:
+			r.mutex.Unlock()
+			return errors.Errorf("unknown setting %s", key)
+		case e.setting == nil:
+			r.mutex.Unlock()
+			return errors.Errorf("setting %s cannot be changed at runtime", key)
+		case len(r.subscribers[strings.ToLower(key)]) == 0:
+			r.mutex.Unlock()
+			return errors.Errorf("setting %s is not used by this node", key)
+		}
+		value, err := e.setting.Parse(values[key])
+		if err != nil {
+			r.mutex.Unlock()
+			return errors.WithMessagef(err, "invalid value for setting %s", key)
+		}
+		changes = append(changes, change{key: strings.ToLower(key), value: value})
+	}
+
+	var notifications []func()
+	for _, c := range changes {
+		e := r.entries[c.key]
+		logger.Infof("Setting %s changed from %v to %v", e.setting.Key, displayValue(e.value), displayValue(c.value))
+		e.value = c.value
+		e.source = SourceRuntime
+		for _, fn := range r.subscribers[c.key] {
+			fn, value := fn, c.value
+			notifications = append(notifications, func() { fn(value) })
+		}
+	}
+	r.mutex.Unlock()
+
+	// subscribers may read the values of other settings
+	for _, notify := range notifications {
+		notify()
+	}
+	return nil
+}
+
+// displayValue renders durations as they are written in the configuration.
+func displayValue(value interface{}) interface{} {
+	if d, ok := value.(time.Duration); ok {
+		return d.String()
+	}
+	return value
+}
+
+// jsonValue converts the maps decoded from YAML, which cannot be encoded as
+// JSON, to maps with string keys.
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[fmt.Sprint(key)] = jsonValue(val)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			m[key] = jsonValue(val)
+		}
+		return m
+	case []interface{}:
+		s := make([]interface{}, len(v))
+		for i, val := range v {
+			s[i] = jsonValue(val)
+		}
+		return s
+	default:
+		return value
+	}
+}
+
+// isSecret determines whether the setting holds a secret, such as the PIN of an
+// HSM or the password of a database.
+func isSecret(key string) bool {
+	name := key[strings.LastIndex(key, ".")+1:]
+	return name == "pin" || strings.Contains(name, "password") || strings.Contains(name, "secret")
+}
@@ -0,0 +1,196 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package dynamic
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `
+peer:
+  id: peer0
+  gateway:
+    endorsementTimeout: 30s
+  limits:
+    concurrency:
+      endorserService: 2500
+  bccsp:
+    pkcs11:
+      pin: "1234"
+ledger:
+  state:
+    couchDBConfig:
+      password: secret
+chaincode:
+  system:
+    _lifecycle: enable
+`
+
+func newTestViper(t *testing.T) *viper.Viper {
+	configFile := filepath.Join(t.TempDir(), "core.yaml")
+	require.NoError(t, os.WriteFile(configFile, []byte(testConfig), 0o644))
+
+	v := viper.New()
+	v.SetEnvPrefix("TESTCORE")
+	v.AutomaticEnv()
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.SetConfigFile(configFile)
+	require.NoError(t, v.ReadInConfig())
+	return v
+}
+
+func testSettings() []Setting {
+	return []Setting{
+		DurationSetting("peer.gateway.endorsementTimeout", 30*time.Second, time.Second),
+		DurationSetting("peer.gateway.broadcastTimeout", 30*time.Second, time.Second),
+		IntSetting("peer.limits.concurrency.endorserService", 2500, 0),
+	}
+}
+
+func findEntry(t *testing.T, entries []Entry, key string) Entry {
+	for _, e := range entries {
+		if e.Key == key {
+			return e
+		}
+	}
+	require.FailNowf(t, "entry not found", "no entry for %s", key)
+	return Entry{}
+}
+
+func TestRegistryEntries(t *testing.T) {
+	t.Setenv("TESTCORE_PEER_ID", "peer1")
+	v := newTestViper(t)
+	v.SetDefault("peer.networkid", "dev")
+
+	r, err := NewRegistry(v, "TESTCORE", testSettings()...)
+	require.NoError(t, err)
+
+	entries := r.Entries()
+	for i := 1; i < len(entries); i++ {
+		require.True(t, strings.ToLower(entries[i-1].Key) < strings.ToLower(entries[i].Key))
+	}
+
+	require.Equal(t, Entry{Key: "peer.id", Value: "peer1", Source: SourceEnvironment}, findEntry(t, entries, "peer.id"))
+	require.Equal(t, Entry{Key: "peer.networkid", Value: "dev", Source: SourceDefault}, findEntry(t, entries, "peer.networkid"))
+	require.Equal(t, Entry{Key: "chaincode.system._lifecycle", Value: "enable", Source: SourceFile}, findEntry(t, entries, "chaincode.system._lifecycle"))
+	require.Equal(t, Entry{Key: "peer.gateway.endorsementTimeout", Value: "30s", Source: SourceFile, Dynamic: true}, findEntry(t, entries, "peer.gateway.endorsementTimeout"))
+	require.Equal(t, Entry{Key: "peer.gateway.broadcastTimeout", Value: "30s", Source: SourceDefault, Dynamic: true}, findEntry(t, entries, "peer.gateway.broadcastTimeout"))
+	require.Equal(t, Entry{Key: "peer.limits.concurrency.endorserService", Value: 2500, Source: SourceFile, Dynamic: true}, findEntry(t, entries, "peer.limits.concurrency.endorserService"))
+
+	// secrets are not disclosed
+	require.Equal(t, redacted, findEntry(t, entries, "peer.bccsp.pkcs11.pin").Value)
+	require.Equal(t, redacted, findEntry(t, entries, "ledger.state.couchdbconfig.password").Value)
+}
+
+func TestRegistryUpdate(t *testing.T) {
+	r, err := NewRegistry(newTestViper(t), "TESTCORE", testSettings()...)
+	require.NoError(t, err)
+
+	var endorsementTimeouts []time.Duration
+	var limits []int
+	require.NoError(t, r.SubscribeDuration("peer.gateway.endorsementTimeout", func(d time.Duration) {
+		endorsementTimeouts = append(endorsementTimeouts, d)
+	}))
+	require.NoError(t, r.SubscribeInt("PEER.LIMITS.CONCURRENCY.ENDORSERSERVICE", func(i int) {
+		// other settings can be read while subscribers are notified
+		require.Equal(t, 5*time.Second, r.Value("peer.gateway.endorsementTimeout"))
+		limits = append(limits, i)
+	}))
+
+	err = r.Update(map[string]string{
+		"peer.gateway.endorsementtimeout":         "5s",
+		"peer.limits.concurrency.endorserService": "100",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []time.Duration{5 * time.Second}, endorsementTimeouts)
+	require.Equal(t, []int{100}, limits)
+
+	entries := r.Entries()
+	require.Equal(t, Entry{Key: "peer.gateway.endorsementTimeout", Value: "5s", Source: SourceRuntime, Dynamic: true}, findEntry(t, entries, "peer.gateway.endorsementTimeout"))
+	require.Equal(t, Entry{Key: "peer.limits.concurrency.endorserService", Value: 100, Source: SourceRuntime, Dynamic: true}, findEntry(t, entries, "peer.limits.concurrency.endorserService"))
+
+	tests := []struct {
+		name   string
+		values map[string]string
+		err    string
+	}{
+		{
+			name: "no settings",
+			err:  "no settings to update",
+		},
+		{
+			name:   "unknown setting",
+			values: map[string]string{"peer.unknown": "1"},
+			err:    "unknown setting peer.unknown",
+		},
+		{
+			name:   "static setting",
+			values: map[string]string{"peer.id": "peer2"},
+			err:    "setting peer.id cannot be changed at runtime",
+		},
+		{
+			name:   "setting without subscribers",
+			values: map[string]string{"peer.gateway.broadcastTimeout": "10s"},
+			err:    "setting peer.gateway.broadcastTimeout is not used by this node",
+		},
+		{
+			name:   "invalid duration",
+			values: map[string]string{"peer.gateway.endorsementTimeout": "soon"},
+			err:    `invalid value for setting peer.gateway.endorsementTimeout: time: invalid duration "soon"`,
+		},
+		{
+			name:   "duration below minimum",
+			values: map[string]string{"peer.gateway.endorsementTimeout": "10ms"},
+			err:    "invalid value for setting peer.gateway.endorsementTimeout: must be at least 1s",
+		},
+		{
+			name:   "invalid integer",
+			values: map[string]string{"peer.limits.concurrency.endorserService": "many"},
+			err:    "invalid value for setting peer.limits.concurrency.endorserService: 'many' is not an integer",
+		},
+		{
+			name: "one invalid value",
+			values: map[string]string{
+				"peer.gateway.endorsementTimeout":         "10s",
+				"peer.limits.concurrency.endorserService": "-1",
+			},
+			err: "invalid value for setting peer.limits.concurrency.endorserService: must be at least 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := r.Update(tt.values)
+			require.EqualError(t, err, tt.err)
+
+			// nothing is changed when an update is rejected
+			require.Equal(t, []time.Duration{5 * time.Second}, endorsementTimeouts)
+			require.Equal(t, []int{100}, limits)
+			require.Equal(t, 5*time.Second, r.Value("peer.gateway.endorsementTimeout"))
+		})
+	}
+}
+
+func TestRegistrySubscribe(t *testing.T) {
+	r, err := NewRegistry(viper.New(), "TESTCORE", testSettings()...)
+	require.NoError(t, err)
+
+	err = r.Subscribe("peer.id", func(interface{}) {})
+	require.EqualError(t, err, "setting peer.id cannot be changed at runtime")
+
+	_, err = NewRegistry(viper.New(), "TESTCORE", testSettings()[0], testSettings()[0])
+	require.EqualError(t, err, "setting peer.gateway.endorsementTimeout is registered more than once")
+
+	_, err = NewRegistry(viper.New(), "TESTCORE", Setting{Key: "peer.id"})
+	require.EqualError(t, err, "setting peer.id has no parser")
+}
@@ -33,8 +33,31 @@ type TLSConfig struct {
 	RootCert   []byte
 }
 
+// Load balancing policies used to select among the endpoints of a chaincode server.
+const (
+	RoundRobin  = "round_robin"
+	LeastLoaded = "least_loaded"
+)
+
 // ChaincodeServerInfo provides chaincode connection information
 type ChaincodeServerInfo struct {
 	Address      string
 	ClientConfig comm.ClientConfig
+	// Replicas are additional endpoints serving the same chaincode.
+	Replicas []ChaincodeServerEndpoint
+	// LoadBalancing is the policy used to select an endpoint, RoundRobin if empty.
+	LoadBalancing string
+}
+
+// ChaincodeServerEndpoint provides the connection information of a single
+// replica of a chaincode server.
+type ChaincodeServerEndpoint struct {
+	Address      string
+	ClientConfig comm.ClientConfig
+}
+
+// Endpoints returns all the endpoints of the chaincode server, starting with Address.
+func (c *ChaincodeServerInfo) Endpoints() []ChaincodeServerEndpoint {
+	endpoints := []ChaincodeServerEndpoint{{Address: c.Address, ClientConfig: c.ClientConfig}}
+	return append(endpoints, c.Replicas...)
 }
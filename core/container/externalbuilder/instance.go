@@ -74,47 +74,139 @@ type ChaincodeServerUserData struct {
 	ClientCert         string   `json:"client_cert"` // PEM encoded client certificate
 	RootCert           string   `json:"root_cert"`   // PEM encoded peer chaincode certificate
 
+	// Endpoints are additional replicas of the chaincode server. Settings
+	// which are not specified by an endpoint are inherited from the above.
+	Endpoints []ChaincodeServerEndpointUserData `json:"endpoints,omitempty"`
+	// LoadBalancing selects the policy used to spread connections over the
+	// endpoints: "round_robin" (default) or "least_loaded".
+	LoadBalancing string `json:"load_balancing,omitempty"`
+}
+
+// ChaincodeServerEndpointUserData holds the "connection.json" information of
+// a single chaincode server endpoint
+type ChaincodeServerEndpointUserData struct {
+	Address            string    `json:"address"`
+	Domain             string    `json:"domain,omitempty"`
+	DialTimeout        *Duration `json:"dial_timeout,omitempty"`
+	TLSRequired        *bool     `json:"tls_required,omitempty"`
+	ClientAuthRequired *bool     `json:"client_auth_required,omitempty"`
+	ClientKey          string    `json:"client_key,omitempty"`  // PEM encoded client key
+	ClientCert         string    `json:"client_cert,omitempty"` // PEM encoded client certificate
+	RootCert           string    `json:"root_cert,omitempty"`   // PEM encoded peer chaincode certificate
 }
 
 func (c *ChaincodeServerUserData) ChaincodeServerInfo(cryptoDir string) (*ccintf.ChaincodeServerInfo, error) {
+	switch c.LoadBalancing {
+	case "", ccintf.RoundRobin, ccintf.LeastLoaded:
+	default:
+		return nil, errors.Errorf("unknown load balancing policy '%s'", c.LoadBalancing)
+	}
+
+	var endpoints []ccintf.ChaincodeServerEndpoint
+	if c.Address != "" || len(c.Endpoints) == 0 {
+		clientConfig, err := c.clientConfig()
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, ccintf.ChaincodeServerEndpoint{Address: c.Address, ClientConfig: clientConfig})
+	}
+
+	for i, e := range c.Endpoints {
+		endpoint := c.endpoint(e)
+		clientConfig, err := endpoint.clientConfig()
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid endpoint %d", i)
+		}
+		endpoints = append(endpoints, ccintf.ChaincodeServerEndpoint{Address: endpoint.Address, ClientConfig: clientConfig})
+	}
+
+	connInfo := &ccintf.ChaincodeServerInfo{
+		Address:       endpoints[0].Address,
+		ClientConfig:  endpoints[0].ClientConfig,
+		LoadBalancing: c.LoadBalancing,
+	}
+	if len(endpoints) > 1 {
+		connInfo.Replicas = endpoints[1:]
+	}
+
+	return connInfo, nil
+}
+
+// endpoint returns the connection information of the given endpoint, with the
+// settings it does not specify inherited from c.
+func (c *ChaincodeServerUserData) endpoint(e ChaincodeServerEndpointUserData) *ChaincodeServerUserData {
+	endpoint := &ChaincodeServerUserData{
+		Address:            e.Address,
+		Domain:             c.Domain,
+		DialTimeout:        c.DialTimeout,
+		TLSRequired:        c.TLSRequired,
+		ClientAuthRequired: c.ClientAuthRequired,
+		ClientKey:          c.ClientKey,
+		ClientCert:         c.ClientCert,
+		RootCert:           c.RootCert,
+	}
+	if e.Domain != "" {
+		endpoint.Domain = e.Domain
+	}
+	if e.DialTimeout != nil {
+		endpoint.DialTimeout = *e.DialTimeout
+	}
+	if e.TLSRequired != nil {
+		endpoint.TLSRequired = *e.TLSRequired
+	}
+	if e.ClientAuthRequired != nil {
+		endpoint.ClientAuthRequired = *e.ClientAuthRequired
+	}
+	if e.ClientKey != "" {
+		endpoint.ClientKey = e.ClientKey
+	}
+	if e.ClientCert != "" {
+		endpoint.ClientCert = e.ClientCert
+	}
+	if e.RootCert != "" {
+		endpoint.RootCert = e.RootCert
+	}
+	return endpoint
+}
+
+func (c *ChaincodeServerUserData) clientConfig() (comm.ClientConfig, error) {
 	if c.Address == "" {
-		return nil, errors.New("chaincode address not provided")
+		return comm.ClientConfig{}, errors.New("chaincode address not provided")
 	}
-	connInfo := &ccintf.ChaincodeServerInfo{Address: c.Address}
 
-	connInfo.ClientConfig.DialTimeout = time.Duration(c.DialTimeout)
-	if connInfo.ClientConfig.DialTimeout == 0 {
-		connInfo.ClientConfig.DialTimeout = DialTimeout
+	clientConfig := comm.ClientConfig{DialTimeout: time.Duration(c.DialTimeout)}
+	if clientConfig.DialTimeout == 0 {
+		clientConfig.DialTimeout = DialTimeout
 	}
 
 	// we can expose this if necessary
-	connInfo.ClientConfig.KaOpts = comm.DefaultKeepaliveOptions
+	clientConfig.KaOpts = comm.DefaultKeepaliveOptions
 
 	if !c.TLSRequired {
-		return connInfo, nil
+		return clientConfig, nil
 	}
 	if c.ClientAuthRequired && c.ClientKey == "" {
-		return nil, errors.New("chaincode tls key not provided")
+		return comm.ClientConfig{}, errors.New("chaincode tls key not provided")
 	}
 	if c.ClientAuthRequired && c.ClientCert == "" {
-		return nil, errors.New("chaincode tls cert not provided")
+		return comm.ClientConfig{}, errors.New("chaincode tls cert not provided")
 	}
 	if c.RootCert == "" {
-		return nil, errors.New("chaincode tls root cert not provided")
+		return comm.ClientConfig{}, errors.New("chaincode tls root cert not provided")
 	}
 
-	connInfo.ClientConfig.SecOpts.UseTLS = true
+	clientConfig.SecOpts.UseTLS = true
 
 	if c.ClientAuthRequired {
-		connInfo.ClientConfig.SecOpts.RequireClientCert = true
-		connInfo.ClientConfig.SecOpts.Certificate = []byte(c.ClientCert)
-		connInfo.ClientConfig.SecOpts.Key = []byte(c.ClientKey)
-		connInfo.ClientConfig.SecOpts.ServerNameOverride = c.Domain
+		clientConfig.SecOpts.RequireClientCert = true
+		clientConfig.SecOpts.Certificate = []byte(c.ClientCert)
+		clientConfig.SecOpts.Key = []byte(c.ClientKey)
+		clientConfig.SecOpts.ServerNameOverride = c.Domain
 	}
 
-	connInfo.ClientConfig.SecOpts.ServerRootCAs = [][]byte{[]byte(c.RootCert)}
+	clientConfig.SecOpts.ServerRootCAs = [][]byte{[]byte(c.RootCert)}
 
-	return connInfo, nil
+	return clientConfig, nil
 }
 
 func (i *Instance) ChaincodeServerReleaseDir() string {
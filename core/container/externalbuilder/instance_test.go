@@ -262,6 +262,82 @@ var _ = Describe("Instance", func() {
 					Expect(err).To(MatchError("chaincode tls root cert not provided"))
 				})
 			})
+
+			Context("endpoints are provided", func() {
+				BeforeEach(func() {
+					tlsRequired := false
+					ccuserdata.LoadBalancing = "least_loaded"
+					ccuserdata.Endpoints = []externalbuilder.ChaincodeServerEndpointUserData{
+						{Address: "replica1:12345", Domain: "replica1"},
+						{Address: "replica2:12345", TLSRequired: &tlsRequired},
+					}
+				})
+
+				It("returns the endpoints as replicas inheriting unspecified settings", func() {
+					ccinfo, err := ccuserdata.ChaincodeServerInfo(releaseDir)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(ccinfo.Address).To(Equal("ccaddress:12345"))
+					Expect(ccinfo.LoadBalancing).To(Equal("least_loaded"))
+					Expect(ccinfo.Replicas).To(Equal([]ccintf.ChaincodeServerEndpoint{
+						{
+							Address: "replica1:12345",
+							ClientConfig: comm.ClientConfig{
+								SecOpts: comm.SecureOptions{
+									UseTLS:             true,
+									RequireClientCert:  true,
+									Certificate:        []byte("fake-cert"),
+									Key:                []byte("fake-key"),
+									ServerRootCAs:      [][]byte{[]byte("fake-root-cert")},
+									ServerNameOverride: "replica1",
+								},
+								KaOpts:      comm.DefaultKeepaliveOptions,
+								DialTimeout: 10 * time.Second,
+							},
+						},
+						{
+							Address: "replica2:12345",
+							ClientConfig: comm.ClientConfig{
+								KaOpts:      comm.DefaultKeepaliveOptions,
+								DialTimeout: 10 * time.Second,
+							},
+						},
+					}))
+				})
+
+				It("uses the first endpoint when the address is not provided", func() {
+					ccuserdata.Address = ""
+
+					ccinfo, err := ccuserdata.ChaincodeServerInfo(releaseDir)
+					Expect(err).NotTo(HaveOccurred())
+					Expect(ccinfo.Address).To(Equal("replica1:12345"))
+					Expect(ccinfo.Replicas).To(HaveLen(1))
+					Expect(ccinfo.Replicas[0].Address).To(Equal("replica2:12345"))
+				})
+
+				It("returns an error when an endpoint address is not provided", func() {
+					ccuserdata.Endpoints[1].Address = ""
+
+					_, err := ccuserdata.ChaincodeServerInfo(releaseDir)
+					Expect(err).To(MatchError("invalid endpoint 1: chaincode address not provided"))
+				})
+
+				It("returns an error when an endpoint tls setting is incomplete", func() {
+					ccuserdata.TLSRequired = false
+					tlsRequired := true
+					ccuserdata.Endpoints[0].TLSRequired = &tlsRequired
+					ccuserdata.RootCert = ""
+
+					_, err := ccuserdata.ChaincodeServerInfo(releaseDir)
+					Expect(err).To(MatchError("invalid endpoint 0: chaincode tls root cert not provided"))
+				})
+
+				It("returns an error for an unknown load balancing policy", func() {
+					ccuserdata.LoadBalancing = "random"
+
+					_, err := ccuserdata.ChaincodeServerInfo(releaseDir)
+					Expect(err).To(MatchError("unknown load balancing policy 'random'"))
+				})
+			})
 		})
 	})
 
@@ -37,6 +37,10 @@ type DeliverService interface {
 	// to channel peers.
 	StopDeliverForChannel(chainID string) error
 
+	// SetRetryThresholds changes the maximum delay between attempts to reconnect
+	// to the ordering service, and the total time the attempts may take
+	SetRetryThresholds(reConnectBackoffThreshold, reconnectTotalTimeThreshold time.Duration)
+
 	// Stop terminates delivery service and closes the connection
 	Stop()
 }
@@ -49,6 +53,9 @@ type deliverServiceImpl struct {
 	blockProviders map[string]*blocksprovider.Deliverer
 	lock           sync.RWMutex
 	stopping       bool
+
+	reConnectBackoffThreshold   time.Duration
+	reconnectTotalTimeThreshold time.Duration
 }
 
 // Config dictates the DeliveryService's properties,
@@ -80,6 +87,10 @@ func NewDeliverService(conf *Config) DeliverService {
 		conf:           conf,
 		blockProviders: make(map[string]*blocksprovider.Deliverer),
 	}
+	if conf.DeliverServiceConfig != nil {
+		ds.reConnectBackoffThreshold = conf.DeliverServiceConfig.ReConnectBackoffThreshold
+		ds.reconnectTotalTimeThreshold = conf.DeliverServiceConfig.ReconnectTotalTimeThreshold
+	}
 	return ds
 }
 
@@ -134,8 +145,8 @@ func (d *deliverServiceImpl) StartDeliverForChannel(chainID string, ledgerInfo b
 		Signer:              d.conf.Signer,
 		DeliverStreamer:     DeliverAdapter{},
 		Logger:              flogging.MustGetLogger("peer.blocksprovider").With("channel", chainID),
-		MaxRetryDelay:       d.conf.DeliverServiceConfig.ReConnectBackoffThreshold,
-		MaxRetryDuration:    d.conf.DeliverServiceConfig.ReconnectTotalTimeThreshold,
+		MaxRetryDelay:       d.reConnectBackoffThreshold,
+		MaxRetryDuration:    d.reconnectTotalTimeThreshold,
 		BlockGossipDisabled: !d.conf.DeliverServiceConfig.BlockGossipEnabled,
 		InitialRetryDelay:   100 * time.Millisecond,
 		YieldLeadership:     !d.conf.IsStaticLeader,
@@ -184,6 +195,19 @@ func (d *deliverServiceImpl) StopDeliverForChannel(chainID string) error {
 	return nil
 }
 
+// SetRetryThresholds changes the retry thresholds of the running block
+// providers, and of the block providers started later on
+func (d *deliverServiceImpl) SetRetryThresholds(reConnectBackoffThreshold, reconnectTotalTimeThreshold time.Duration) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.reConnectBackoffThreshold = reConnectBackoffThreshold
+	d.reconnectTotalTimeThreshold = reconnectTotalTimeThreshold
+
+	for _, client := range d.blockProviders {
+		client.SetRetryThresholds(reConnectBackoffThreshold, reconnectTotalTimeThreshold)
+	}
+}
+
 // Stop all service and release resources
 func (d *deliverServiceImpl) Stop() {
 	d.lock.Lock()
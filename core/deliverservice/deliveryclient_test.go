@@ -213,3 +213,29 @@ func TestStop(t *testing.T) {
 		}
 	}
 }
+
+func TestSetRetryThresholds(t *testing.T) {
+	fakeLedgerInfo := &fake.LedgerInfo{}
+	fakeLedgerInfo.LedgerHeightReturns(0, fmt.Errorf("fake-ledger-error"))
+
+	ds := NewDeliverService(&Config{
+		DeliverServiceConfig: &DeliverServiceConfig{
+			ReConnectBackoffThreshold:   time.Hour,
+			ReconnectTotalTimeThreshold: 2 * time.Hour,
+		},
+	}).(*deliverServiceImpl)
+	ds.blockProviders = map[string]*blocksprovider.Deliverer{
+		"a": {
+			DoneC: make(chan struct{}),
+		},
+	}
+
+	ds.SetRetryThresholds(time.Second, time.Minute)
+	require.Equal(t, time.Second, ds.blockProviders["a"].MaxRetryDelay)
+	require.Equal(t, time.Minute, ds.blockProviders["a"].MaxRetryDuration)
+
+	err := ds.StartDeliverForChannel("b", fakeLedgerInfo, func() {})
+	require.NoError(t, err)
+	require.Equal(t, time.Second, ds.blockProviders["b"].MaxRetryDelay)
+	require.Equal(t, time.Minute, ds.blockProviders["b"].MaxRetryDuration)
+}
@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -22,6 +23,7 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/internal/pkg/identity"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/protoutil"
@@ -101,6 +103,8 @@ type Endorser struct {
 	Support                Support
 	PvtRWSetAssembler      PvtRWSetAssembler
 	Metrics                *Metrics
+	// ResultCache caches the results of read-only proposals; nil disables caching.
+	ResultCache *ResultCache
 }
 
 // call specified chaincode (system or user)
@@ -376,6 +380,23 @@ func (e *Endorser) ProcessProposalSuccessfullyOrError(up *UnpackedProposal) (*pb
 
 	logger := decorateLogger(endorserLogger, txParams)
 
+	cacheKey, cacheable := e.resultCacheKey(up)
+	if cacheable {
+		// The cache is only evicted by the blocks of the channel of the
+		// proposal, so it must not hold results read from another channel.
+		txParams.CrossChannelInvoked = &atomic.Bool{}
+		meterLabels := []string{
+			"channel", up.ChannelID(),
+			"chaincode", up.ChaincodeName,
+		}
+		if result, ok := e.ResultCache.get(cacheKey, up.TxID()); ok {
+			e.Metrics.ProposalCacheHits.With(meterLabels...).Add(1)
+			logger.Debugf("endorsing cached simulation results for chaincode %s", up.ChaincodeName)
+			return e.endorseSimulationResult(up, logger, result)
+		}
+		e.Metrics.ProposalCacheMisses.With(meterLabels...).Add(1)
+	}
+
 	if acquireTxSimulator(up.ChannelHeader.ChannelId, up.ChaincodeName) {
 		txSim, err := e.Support.GetTxSimulator(up.ChannelID(), up.TxID())
 		if err != nil {
@@ -406,19 +427,56 @@ func (e *Endorser) ProcessProposalSuccessfullyOrError(up *UnpackedProposal) (*pb
 	}
 
 	// 1 -- simulate
-	res, simulationResult, ccevent, ccInterest, err := e.simulateProposal(txParams, up.ChaincodeName, up.Input)
+	res, pubSimResults, ccevent, ccInterest, err := e.simulateProposal(txParams, up.ChaincodeName, up.Input)
 	if err != nil {
 		return nil, errors.WithMessage(err, "error in simulation")
 	}
 
-	cceventBytes, err := CreateCCEventBytes(ccevent)
+	result := &simulationResult{
+		response:        res,
+		pubSimResults:   pubSimResults,
+		ccEvent:         ccevent,
+		ccInterest:      ccInterest,
+		endorsementInfo: cdLedger,
+	}
+	if cacheable && !txParams.CrossChannelInvoked.Load() && result.readOnly() {
+		e.ResultCache.put(cacheKey, result)
+	}
+
+	return e.endorseSimulationResult(up, logger, result)
+}
+
+// resultCacheKey returns the key under which the results of the proposal are
+// cached, or false if they may not be cached.
+func (e *Endorser) resultCacheKey(up *UnpackedProposal) (resultCacheKey, bool) {
+	if e.ResultCache == nil || up.ChannelID() == "" || e.Support.IsSysCC(up.ChaincodeName) {
+		return resultCacheKey{}, false
+	}
+
+	// The height is read before simulating, so the cached results reflect
+	// a state at least as recent as the height they are cached for.
+	height, err := e.Support.GetLedgerHeight(up.ChannelID())
+	if err != nil {
+		endorserLogger.Warnw("Failed to obtain ledger height, not caching proposal results", "channel", up.ChannelID(), "error", err)
+		return resultCacheKey{}, false
+	}
+
+	return e.ResultCache.key(up, height)
+}
+
+// endorseSimulationResult builds the proposal response from the simulation
+// results and endorses it.
+func (e *Endorser) endorseSimulationResult(up *UnpackedProposal, logger *flogging.FabricLogger, result *simulationResult) (*pb.ProposalResponse, error) {
+	res := result.response
+
+	cceventBytes, err := CreateCCEventBytes(result.ccEvent)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal chaincode event")
 	}
 
-	prpBytes, err := protoutil.GetBytesProposalResponsePayload(up.ProposalHash, res, simulationResult, cceventBytes, &pb.ChaincodeID{
+	prpBytes, err := protoutil.GetBytesProposalResponsePayload(up.ProposalHash, res, result.pubSimResults, cceventBytes, &pb.ChaincodeID{
 		Name:    up.ChaincodeName,
-		Version: cdLedger.Version,
+		Version: result.endorsementInfo.Version,
 	})
 	if err != nil {
 		logger.Warning("Failed marshaling the proposal response payload to bytes", err)
@@ -436,7 +494,7 @@ func (e *Endorser) ProcessProposalSuccessfullyOrError(up *UnpackedProposal) (*pb
 		return &pb.ProposalResponse{
 			Response: res,
 			Payload:  prpBytes,
-			Interest: ccInterest,
+			Interest: result.ccInterest,
 		}, nil
 	case up.ChannelID() == "":
 		// Chaincode invocations without a channel ID is a broken concept
@@ -454,7 +512,7 @@ func (e *Endorser) ProcessProposalSuccessfullyOrError(up *UnpackedProposal) (*pb
 		}, nil
 	}
 
-	escc := cdLedger.EndorsementPlugin
+	escc := result.endorsementInfo.EndorsementPlugin
 
 	logger.Debugf("escc for chaincode %s is %s", up.ChaincodeName, escc)
 
@@ -471,7 +529,7 @@ func (e *Endorser) ProcessProposalSuccessfullyOrError(up *UnpackedProposal) (*pb
 		Endorsement: endorsement,
 		Payload:     mPrpBytes,
 		Response:    res,
-		Interest:    ccInterest,
+		Interest:    result.ccInterest,
 	}, nil
 }
 
@@ -491,6 +549,10 @@ func (e *Endorser) buildChaincodeInterest(simResult *ledger.TxSimulationResults)
 			// skip system chaincodes
 			continue
 		}
+		if rwsetutil.IsCrossChannelNamespace(nsrws.Namespace) {
+			// skip the reads of other channels, which are not endorsed on this channel
+			continue
+		}
 		if _, ok := policies.policyRequired[nsrws.Namespace]; !ok {
 			// There's a public RWset for this namespace, but no public or private writes, so chaincode policy is required.
 			policies.add(nsrws.Namespace, "", true)
@@ -19,9 +19,11 @@ import (
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/endorser"
 	"github.com/hyperledger/fabric/core/endorser/fake"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	ledgermock "github.com/hyperledger/fabric/core/ledger/mock"
 	"github.com/hyperledger/fabric/protoutil"
 	. "github.com/onsi/ginkgo/v2"
@@ -1004,6 +1006,268 @@ var _ = Describe("Endorser", func() {
 		})
 	})
 
+	Context("when the result cache is enabled", func() {
+		var (
+			fakeBlockNotifier  *fake.BlockNotifier
+			fakeProposalHits   *metricsfakes.Counter
+			fakeProposalMisses *metricsfakes.Counter
+			blocks             chan *ledger.CommitNotification
+			cachedChaincodes   []string
+		)
+
+		BeforeEach(func() {
+			blocks = make(chan *ledger.CommitNotification)
+			fakeBlockNotifier = &fake.BlockNotifier{}
+			fakeBlockNotifier.NotifyBlocksReturns(blocks, nil)
+
+			fakeProposalHits = &metricsfakes.Counter{}
+			fakeProposalHits.WithReturns(fakeProposalHits)
+			fakeProposalMisses = &metricsfakes.Counter{}
+			fakeProposalMisses.WithReturns(fakeProposalMisses)
+			e.Metrics.ProposalCacheHits = fakeProposalHits
+			e.Metrics.ProposalCacheMisses = fakeProposalMisses
+
+			cachedChaincodes = nil
+
+			// the chaincode sets the transaction ID of the proposal on its events
+			chaincodeEvent.TxId = "6f142589e4ef6a1e62c9c816e2074f70baa9f7cf67c2f0c287d4ef907d6d2015"
+		})
+
+		JustBeforeEach(func() {
+			e.ResultCache = endorser.NewResultCache(fakeBlockNotifier, 10, cachedChaincodes)
+		})
+
+		AfterEach(func() {
+			e.ResultCache.Close()
+		})
+
+		It("endorses repeated proposals from the cached simulation results", func() {
+			first, err := e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			second, err := e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeSupport.GetTxSimulatorCallCount()).To(Equal(1))
+			Expect(fakeSupport.ExecuteCallCount()).To(Equal(1))
+			Expect(fakeSupport.EndorseWithPluginCallCount()).To(Equal(2))
+			Expect(proto.Equal(first, second)).To(BeTrue())
+
+			_, _, firstPrpBytes, _ := fakeSupport.EndorseWithPluginArgsForCall(0)
+			_, _, secondPrpBytes, _ := fakeSupport.EndorseWithPluginArgsForCall(1)
+			Expect(secondPrpBytes).To(Equal(firstPrpBytes))
+
+			Expect(fakeProposalMisses.AddCallCount()).To(Equal(1))
+			Expect(fakeProposalHits.AddCallCount()).To(Equal(1))
+			Expect(fakeProposalHits.WithArgsForCall(0)).To(Equal([]string{
+				"channel", "channel-id",
+				"chaincode", "chaincode-name",
+			}))
+
+			Expect(fakeBlockNotifier.NotifyBlocksCallCount()).To(Equal(1))
+			_, channel := fakeBlockNotifier.NotifyBlocksArgsForCall(0)
+			Expect(channel).To(Equal("channel-id"))
+		})
+
+		It("does not share responses between proposals", func() {
+			first, err := e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			first.Response.Payload = []byte("modified")
+
+			second, err := e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second.Response.Payload).To(Equal([]byte("response-payload")))
+		})
+
+		It("simulates the proposal again once the ledger height changes", func() {
+			_, err := e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			fakeSupport.GetLedgerHeightReturns(8, nil)
+			_, err = e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+			Expect(fakeProposalMisses.AddCallCount()).To(Equal(2))
+		})
+
+		It("simulates the proposal again after a block is committed", func() {
+			_, err := e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			blocks <- &ledger.CommitNotification{BlockNumber: 7}
+			// the unbuffered send completes once the previous block has been processed
+			blocks <- &ledger.CommitNotification{BlockNumber: 8}
+			_, err = e.ProcessProposal(context.Background(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+		})
+
+		Context("when the simulation writes state", func() {
+			BeforeEach(func() {
+				fakeTxSimulator.GetTxSimulationResultsReturns(
+					&ledger.TxSimulationResults{
+						PubSimulationResults: &rwset.TxReadWriteSet{
+							NsRwset: []*rwset.NsReadWriteSet{{
+								Namespace: "chaincode-name",
+								Rwset: protoutil.MarshalOrPanic(&kvrwset.KVRWSet{
+									Writes: []*kvrwset.KVWrite{{Key: "key", Value: []byte("value")}},
+								}),
+							}},
+						},
+					},
+					nil,
+				)
+			})
+
+			It("does not cache the results", func() {
+				_, err := e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+				Expect(fakeBlockNotifier.NotifyBlocksCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the simulation reads private data", func() {
+			BeforeEach(func() {
+				fakeTxSimulator.GetTxSimulationResultsReturns(
+					&ledger.TxSimulationResults{
+						PubSimulationResults: &rwset.TxReadWriteSet{
+							NsRwset: []*rwset.NsReadWriteSet{{
+								Namespace: "chaincode-name",
+								CollectionHashedRwset: []*rwset.CollectionHashedReadWriteSet{{
+									CollectionName: "collection",
+									HashedRwset: protoutil.MarshalOrPanic(&kvrwset.HashedRWSet{
+										HashedReads: []*kvrwset.KVReadHash{{KeyHash: []byte("key-hash")}},
+									}),
+								}},
+							}},
+						},
+					},
+					nil,
+				)
+			})
+
+			It("does not cache the results", func() {
+				_, err := e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when the simulation records reads of another channel", func() {
+			BeforeEach(func() {
+				fakeTxSimulator.GetTxSimulationResultsReturns(
+					&ledger.TxSimulationResults{
+						PubSimulationResults: &rwset.TxReadWriteSet{
+							NsRwset: []*rwset.NsReadWriteSet{{
+								Namespace: rwsetutil.CrossChannelNamespace("other-channel", "other-chaincode"),
+								Rwset: protoutil.MarshalOrPanic(&kvrwset.KVRWSet{
+									Reads: []*kvrwset.KVRead{{Key: "key"}},
+								}),
+							}},
+						},
+					},
+					nil,
+				)
+			})
+
+			It("does not cache the results", func() {
+				_, err := e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when the chaincode invokes a chaincode on another channel", func() {
+			BeforeEach(func() {
+				fakeSupport.ExecuteStub = func(txParams *ccprovider.TransactionParams, name string, input *pb.ChaincodeInput) (*pb.Response, *pb.ChaincodeEvent, error) {
+					txParams.CrossChannelInvoked.Store(true)
+					return chaincodeResponse, chaincodeEvent, nil
+				}
+			})
+
+			It("does not cache the results", func() {
+				_, err := e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+				Expect(fakeBlockNotifier.NotifyBlocksCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the chaincode response is an error", func() {
+			BeforeEach(func() {
+				chaincodeResponse.Status = 500
+			})
+
+			It("does not cache the results", func() {
+				_, err := e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when the chaincode is not one of the cached chaincodes", func() {
+			BeforeEach(func() {
+				cachedChaincodes = []string{"other-chaincode"}
+			})
+
+			It("does not cache the results", func() {
+				_, err := e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+				Expect(fakeProposalMisses.AddCallCount()).To(Equal(0))
+				Expect(fakeProposalHits.AddCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the chaincode input is an init", func() {
+			BeforeEach(func() {
+				chaincodeInput.IsInit = true
+			})
+
+			It("does not cache the results", func() {
+				_, err := e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+			})
+		})
+
+		Context("when the ledger height cannot be determined", func() {
+			BeforeEach(func() {
+				fakeSupport.GetLedgerHeightReturns(0, fmt.Errorf("fake-height-error"))
+			})
+
+			It("simulates every proposal", func() {
+				_, err := e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+				_, err = e.ProcessProposal(context.Background(), signedProposal)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(fakeSupport.ExecuteCallCount()).To(Equal(2))
+			})
+		})
+	})
+
 	Context("when retrieving simulation results", func() {
 		BeforeEach(func() {
 			mockDeployedCCInfoProvider := &ledgermock.DeployedChaincodeInfoProvider{}
@@ -1219,6 +1483,37 @@ var _ = Describe("Endorser", func() {
 			}))
 		})
 
+		It("skips reads of other channels", func() {
+			pubSimResults = &rwset.TxReadWriteSet{
+				DataModel: rwset.TxReadWriteSet_KV,
+				NsRwset: []*rwset.NsReadWriteSet{
+					{
+						Namespace: "myCC",
+						Rwset:     readSet,
+					},
+					{
+						Namespace: "otherchannel/othercc",
+						Rwset:     readSet,
+					},
+				},
+			}
+
+			fakeTxSimulator.GetTxSimulationResultsReturns(
+				&ledger.TxSimulationResults{
+					PubSimulationResults: pubSimResults,
+				},
+				nil,
+			)
+
+			proposalResponse, err := e.ProcessProposal(context.TODO(), signedProposal)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proposalResponse.Interest).To(Equal(&pb.ChaincodeInterest{
+				Chaincodes: []*pb.ChaincodeCall{{
+					Name: "myCC",
+				}},
+			}))
+		})
+
 		It("add private collection and SBE", func() {
 			privateReads := ledger.PrivateReads{}
 
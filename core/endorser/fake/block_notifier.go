@@ -0,0 +1,119 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package fake
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/core/endorser"
+	"github.com/hyperledger/fabric/core/ledger"
+)
+
+type BlockNotifier struct {
+	NotifyBlocksStub        func(<-chan struct{}, string) (<-chan *ledger.CommitNotification, error)
+	notifyBlocksMutex       sync.RWMutex
+	notifyBlocksArgsForCall []struct {
+		arg1 <-chan struct{}
+		arg2 string
+	}
+	notifyBlocksReturns struct {
+		result1 <-chan *ledger.CommitNotification
+		result2 error
+	}
+	notifyBlocksReturnsOnCall map[int]struct {
+		result1 <-chan *ledger.CommitNotification
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *BlockNotifier) NotifyBlocks(arg1 <-chan struct{}, arg2 string) (<-chan *ledger.CommitNotification, error) {
+	fake.notifyBlocksMutex.Lock()
+	ret, specificReturn := fake.notifyBlocksReturnsOnCall[len(fake.notifyBlocksArgsForCall)]
+	fake.notifyBlocksArgsForCall = append(fake.notifyBlocksArgsForCall, struct {
+		arg1 <-chan struct{}
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.NotifyBlocksStub
+	fakeReturns := fake.notifyBlocksReturns
+	fake.recordInvocation("NotifyBlocks", []interface{}{arg1, arg2})
+	fake.notifyBlocksMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *BlockNotifier) NotifyBlocksCallCount() int {
+	fake.notifyBlocksMutex.RLock()
+	defer fake.notifyBlocksMutex.RUnlock()
+	return len(fake.notifyBlocksArgsForCall)
+}
+
+func (fake *BlockNotifier) NotifyBlocksCalls(stub func(<-chan struct{}, string) (<-chan *ledger.CommitNotification, error)) {
+	fake.notifyBlocksMutex.Lock()
+	defer fake.notifyBlocksMutex.Unlock()
+	fake.NotifyBlocksStub = stub
+}
+
+func (fake *BlockNotifier) NotifyBlocksArgsForCall(i int) (<-chan struct{}, string) {
+	fake.notifyBlocksMutex.RLock()
+	defer fake.notifyBlocksMutex.RUnlock()
+	argsForCall := fake.notifyBlocksArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *BlockNotifier) NotifyBlocksReturns(result1 <-chan *ledger.CommitNotification, result2 error) {
+	fake.notifyBlocksMutex.Lock()
+	defer fake.notifyBlocksMutex.Unlock()
+	fake.NotifyBlocksStub = nil
+	fake.notifyBlocksReturns = struct {
+		result1 <-chan *ledger.CommitNotification
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *BlockNotifier) NotifyBlocksReturnsOnCall(i int, result1 <-chan *ledger.CommitNotification, result2 error) {
+	fake.notifyBlocksMutex.Lock()
+	defer fake.notifyBlocksMutex.Unlock()
+	fake.NotifyBlocksStub = nil
+	if fake.notifyBlocksReturnsOnCall == nil {
+		fake.notifyBlocksReturnsOnCall = make(map[int]struct {
+			result1 <-chan *ledger.CommitNotification
+			result2 error
+		})
+	}
+	fake.notifyBlocksReturnsOnCall[i] = struct {
+		result1 <-chan *ledger.CommitNotification
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *BlockNotifier) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.notifyBlocksMutex.RLock()
+	defer fake.notifyBlocksMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *BlockNotifier) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ endorser.BlockNotifier = new(BlockNotifier)
@@ -74,6 +74,22 @@ var (
 		LabelNames:   []string{"channel", "chaincode"},
 		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
 	}
+
+	proposalCacheHitsCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "proposal_cache_hits",
+		Help:         "The number of proposals endorsed from cached simulation results.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
+
+	proposalCacheMissesCounterOpts = metrics.CounterOpts{
+		Namespace:    "endorser",
+		Name:         "proposal_cache_misses",
+		Help:         "The number of cacheable proposals that had to be simulated.",
+		LabelNames:   []string{"channel", "chaincode"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}",
+	}
 )
 
 type Metrics struct {
@@ -86,6 +102,8 @@ type Metrics struct {
 	EndorsementsFailed       metrics.Counter
 	DuplicateTxsFailure      metrics.Counter
 	SimulationFailure        metrics.Counter
+	ProposalCacheHits        metrics.Counter
+	ProposalCacheMisses      metrics.Counter
 }
 
 func NewMetrics(p metrics.Provider) *Metrics {
@@ -99,5 +117,7 @@ func NewMetrics(p metrics.Provider) *Metrics {
 		EndorsementsFailed:       p.NewCounter(endorsementFailureCounterOpts),
 		DuplicateTxsFailure:      p.NewCounter(duplicateTxsFailureCounterOpts),
 		SimulationFailure:        p.NewCounter(simulationFailureCounterOpts),
+		ProposalCacheHits:        p.NewCounter(proposalCacheHitsCounterOpts),
+		ProposalCacheMisses:      p.NewCounter(proposalCacheMissesCounterOpts),
 	}
 }
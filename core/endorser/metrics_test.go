@@ -31,6 +31,8 @@ func TestNewMetrics(t *testing.T) {
 		EndorsementsFailed:       &metricsfakes.Counter{},
 		DuplicateTxsFailure:      &metricsfakes.Counter{},
 		SimulationFailure:        &metricsfakes.Counter{},
+		ProposalCacheHits:        &metricsfakes.Counter{},
+		ProposalCacheMisses:      &metricsfakes.Counter{},
 	}))
 
 	gt.Expect(provider.NewHistogramCallCount()).To(Equal(1))
@@ -38,7 +40,7 @@ func TestNewMetrics(t *testing.T) {
 		{proposalDurationHistogramOpts},
 	}))
 
-	gt.Expect(provider.NewCounterCallCount()).To(Equal(8))
+	gt.Expect(provider.NewCounterCallCount()).To(Equal(10))
 	gt.Expect(provider.Invocations()["NewCounter"]).To(ConsistOf([][]interface{}{
 		{receivedProposalsCounterOpts},
 		{successfulProposalsCounterOpts},
@@ -48,5 +50,7 @@ func TestNewMetrics(t *testing.T) {
 		{endorsementFailureCounterOpts},
 		{duplicateTxsFailureCounterOpts},
 		{simulationFailureCounterOpts},
+		{proposalCacheHitsCounterOpts},
+		{proposalCacheMissesCounterOpts},
 	}))
 }
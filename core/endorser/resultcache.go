@@ -0,0 +1,278 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protoutil"
+)
+
+//go:generate counterfeiter -o fake/block_notifier.go --fake-name BlockNotifier . BlockNotifier
+
+// BlockNotifier notifies of the blocks committed to the ledger of a channel.
+type BlockNotifier interface {
+	NotifyBlocks(done <-chan struct{}, channelName string) (<-chan *ledger.CommitNotification, error)
+}
+
+// simulationResult holds the outcome of simulating a proposal, from which the
+// proposal response is built and endorsed.
+type simulationResult struct {
+	response        *pb.Response
+	pubSimResults   []byte
+	ccEvent         *pb.ChaincodeEvent
+	ccInterest      *pb.ChaincodeInterest
+	endorsementInfo *lifecycle.ChaincodeEndorsementInfo
+}
+
+// readOnly returns true if the simulation succeeded without writing any state,
+// without reading or writing any private data and without recording reads of
+// another channel.
+func (sr *simulationResult) readOnly() bool {
+	if sr.response.Status >= shim.ERRORTHRESHOLD {
+		return false
+	}
+
+	txRWSet, err := protoutil.UnmarshalTxReadWriteSet(sr.pubSimResults)
+	if err != nil {
+		return false
+	}
+	for _, nsRWSet := range txRWSet.NsRwset {
+		if rwsetutil.IsCrossChannelNamespace(nsRWSet.Namespace) {
+			return false
+		}
+		if len(nsRWSet.CollectionHashedRwset) != 0 {
+			return false
+		}
+		kvRWSet, err := protoutil.UnmarshalKVRWSet(nsRWSet.Rwset)
+		if err != nil {
+			return false
+		}
+		if len(kvRWSet.Writes) != 0 || len(kvRWSet.MetadataWrites) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// clone returns a copy of the result which does not share any mutable state
+// with the original, adjusted for the transaction with the given ID.
+func (sr *simulationResult) clone(txID string) *simulationResult {
+	result := &simulationResult{
+		response:        proto.Clone(sr.response).(*pb.Response),
+		pubSimResults:   sr.pubSimResults,
+		endorsementInfo: sr.endorsementInfo,
+	}
+	if sr.ccEvent != nil {
+		result.ccEvent = proto.Clone(sr.ccEvent).(*pb.ChaincodeEvent)
+		result.ccEvent.TxId = txID
+	}
+	if sr.ccInterest != nil {
+		result.ccInterest = proto.Clone(sr.ccInterest).(*pb.ChaincodeInterest)
+	}
+	return result
+}
+
+type resultCacheKey struct {
+	channelID     string
+	chaincodeName string
+	mspID         string
+	height        uint64
+	inputHash     [sha256.Size]byte
+}
+
+type resultCacheEntry struct {
+	key    resultCacheKey
+	result *simulationResult
+}
+
+// ResultCache caches the simulation results of read-only proposals, keyed by
+// the channel, chaincode, chaincode input and creator MSP of the proposal and
+// the ledger height at which it was simulated. The entries of a channel are
+// evicted as blocks are committed to it, and the least recently used entries
+// are evicted once the cache is full.
+type ResultCache struct {
+	notifier   BlockNotifier
+	maxEntries int
+	chaincodes map[string]struct{}
+
+	lock     sync.Mutex
+	entries  map[resultCacheKey]*list.Element
+	lru      *list.List
+	watching map[string]struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// NewResultCache creates a cache holding at most maxEntries results of the
+// given chaincodes, or of every application chaincode if none are given.
+func NewResultCache(notifier BlockNotifier, maxEntries int, chaincodes []string) *ResultCache {
+	cache := &ResultCache{
+		notifier:   notifier,
+		maxEntries: maxEntries,
+		chaincodes: map[string]struct{}{},
+		entries:    map[resultCacheKey]*list.Element{},
+		lru:        list.New(),
+		watching:   map[string]struct{}{},
+		done:       make(chan struct{}),
+	}
+	for _, chaincode := range chaincodes {
+		cache.chaincodes[chaincode] = struct{}{}
+	}
+	return cache
+}
+
+// Close stops watching the ledgers for committed blocks.
+func (c *ResultCache) Close() {
+	c.once.Do(func() {
+		close(c.done)
+	})
+}
+
+// key returns the cache key of the proposal simulated at the given height, or
+// false if the results of the proposal may not be cached.
+func (c *ResultCache) key(up *UnpackedProposal, height uint64) (resultCacheKey, bool) {
+	if len(c.chaincodes) != 0 {
+		if _, ok := c.chaincodes[up.ChaincodeName]; !ok {
+			return resultCacheKey{}, false
+		}
+	}
+
+	if up.Input.IsInit || len(up.Input.Decorations) != 0 {
+		return resultCacheKey{}, false
+	}
+
+	cpp, err := protoutil.UnmarshalChaincodeProposalPayload(up.Proposal.Payload)
+	if err != nil || len(cpp.TransientMap) != 0 {
+		return resultCacheKey{}, false
+	}
+
+	creator, err := protoutil.UnmarshalSerializedIdentity(up.SignatureHeader.Creator)
+	if err != nil {
+		return resultCacheKey{}, false
+	}
+
+	h := sha256.New()
+	for _, arg := range up.Input.Args {
+		var length [8]byte
+		binary.BigEndian.PutUint64(length[:], uint64(len(arg)))
+		h.Write(length[:])
+		h.Write(arg)
+	}
+
+	key := resultCacheKey{
+		channelID:     up.ChannelID(),
+		chaincodeName: up.ChaincodeName,
+		mspID:         creator.Mspid,
+		height:        height,
+	}
+	copy(key.inputHash[:], h.Sum(nil))
+	return key, true
+}
+
+// get returns a copy of the cached result for the key, adjusted for the
+// transaction with the given ID.
+func (c *ResultCache) get(key resultCacheKey, txID string) (*simulationResult, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(element)
+	return element.Value.(*resultCacheEntry).result.clone(txID), true
+}
+
+// put caches a copy of the result for the key, unless the cache is unable to
+// watch the channel for committed blocks.
+func (c *ResultCache) put(key resultCacheKey, result *simulationResult) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.watching[key.channelID]; !ok {
+		blocks, err := c.notifier.NotifyBlocks(c.done, key.channelID)
+		if err != nil {
+			endorserLogger.Warnw("Failed to watch for committed blocks, not caching proposal results", "channel", key.channelID, "error", err)
+			return
+		}
+		c.watching[key.channelID] = struct{}{}
+		go c.watch(key.channelID, blocks)
+	}
+
+	if element, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&resultCacheEntry{
+		key:    key,
+		result: result.clone(result.ccEvent.GetTxId()),
+	})
+	for c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+// watch evicts the entries of the channel simulated at or below the height of
+// each committed block. Once the notifications stop, all the entries of the
+// channel are evicted and it is watched again on the next put.
+func (c *ResultCache) watch(channelID string, blocks <-chan *ledger.CommitNotification) {
+	for block := range blocks {
+		c.evict(channelID, block.BlockNumber)
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	delete(c.watching, channelID)
+	c.removeIf(func(key resultCacheKey) bool {
+		return key.channelID == channelID
+	})
+}
+
+func (c *ResultCache) evict(channelID string, blockNumber uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.removeIf(func(key resultCacheKey) bool {
+		return key.channelID == channelID && key.height <= blockNumber
+	})
+}
+
+func (c *ResultCache) removeIf(predicate func(resultCacheKey) bool) {
+	for element := c.lru.Front(); element != nil; {
+		next := element.Next()
+		if predicate(element.Value.(*resultCacheEntry).key) {
+			c.remove(element)
+		}
+		element = next
+	}
+}
+
+func (c *ResultCache) remove(element *list.Element) {
+	c.lru.Remove(element)
+	delete(c.entries, element.Value.(*resultCacheEntry).key)
+}
+
+// size returns the number of cached results.
+func (c *ResultCache) size() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.lru.Len()
+}
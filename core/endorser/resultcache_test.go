@@ -0,0 +1,208 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/common"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protoutil"
+	. "github.com/onsi/gomega"
+)
+
+type blockNotifierFunc func(done <-chan struct{}, channelName string) (<-chan *ledger.CommitNotification, error)
+
+func (f blockNotifierFunc) NotifyBlocks(done <-chan struct{}, channelName string) (<-chan *ledger.CommitNotification, error) {
+	return f(done, channelName)
+}
+
+func cacheableProposal(channelID, mspID string, args ...string) *UnpackedProposal {
+	input := &pb.ChaincodeInput{}
+	for _, arg := range args {
+		input.Args = append(input.Args, []byte(arg))
+	}
+	return &UnpackedProposal{
+		ChaincodeName: "chaincode-name",
+		ChannelHeader: &common.ChannelHeader{ChannelId: channelID},
+		Input:         input,
+		Proposal: &pb.Proposal{
+			Payload: protoutil.MarshalOrPanic(&pb.ChaincodeProposalPayload{}),
+		},
+		SignatureHeader: &common.SignatureHeader{
+			Creator: protoutil.MarshalOrPanic(&mspproto.SerializedIdentity{Mspid: mspID}),
+		},
+	}
+}
+
+func cachedResult() *simulationResult {
+	return &simulationResult{
+		response:        &pb.Response{Status: 200, Payload: []byte("payload")},
+		endorsementInfo: &lifecycle.ChaincodeEndorsementInfo{Version: "version"},
+	}
+}
+
+func TestResultCacheKey(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	cache := NewResultCache(nil, 10, nil)
+
+	key, ok := cache.key(cacheableProposal("channel", "msp", "a", "bc"), 5)
+	gt.Expect(ok).To(BeTrue())
+	gt.Expect(key.channelID).To(Equal("channel"))
+	gt.Expect(key.chaincodeName).To(Equal("chaincode-name"))
+	gt.Expect(key.mspID).To(Equal("msp"))
+	gt.Expect(key.height).To(Equal(uint64(5)))
+
+	other, _ := cache.key(cacheableProposal("channel", "msp", "ab", "c"), 5)
+	gt.Expect(other).NotTo(Equal(key))
+	other, _ = cache.key(cacheableProposal("channel", "other-msp", "a", "bc"), 5)
+	gt.Expect(other).NotTo(Equal(key))
+	other, _ = cache.key(cacheableProposal("channel", "msp", "a", "bc"), 5)
+	gt.Expect(other).To(Equal(key))
+
+	up := cacheableProposal("channel", "msp", "a")
+	up.Proposal.Payload = protoutil.MarshalOrPanic(&pb.ChaincodeProposalPayload{
+		TransientMap: map[string][]byte{"key": []byte("value")},
+	})
+	_, ok = cache.key(up, 5)
+	gt.Expect(ok).To(BeFalse())
+
+	up = cacheableProposal("channel", "msp", "a")
+	up.Input.Decorations = map[string][]byte{"key": []byte("value")}
+	_, ok = cache.key(up, 5)
+	gt.Expect(ok).To(BeFalse())
+
+	up = cacheableProposal("channel", "msp", "a")
+	up.SignatureHeader.Creator = []byte("garbage")
+	_, ok = cache.key(up, 5)
+	gt.Expect(ok).To(BeFalse())
+}
+
+func TestResultCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	notifier := blockNotifierFunc(func(<-chan struct{}, string) (<-chan *ledger.CommitNotification, error) {
+		return make(chan *ledger.CommitNotification), nil
+	})
+	cache := NewResultCache(notifier, 2, nil)
+	defer cache.Close()
+
+	keys := make([]resultCacheKey, 3)
+	for i := range keys {
+		keys[i], _ = cache.key(cacheableProposal("channel", "msp", fmt.Sprintf("arg%d", i)), 5)
+	}
+
+	cache.put(keys[0], cachedResult())
+	cache.put(keys[1], cachedResult())
+	_, ok := cache.get(keys[0], "txid")
+	gt.Expect(ok).To(BeTrue())
+	cache.put(keys[2], cachedResult())
+
+	gt.Expect(cache.size()).To(Equal(2))
+	_, ok = cache.get(keys[1], "txid")
+	gt.Expect(ok).To(BeFalse())
+	_, ok = cache.get(keys[0], "txid")
+	gt.Expect(ok).To(BeTrue())
+	_, ok = cache.get(keys[2], "txid")
+	gt.Expect(ok).To(BeTrue())
+}
+
+func TestResultCacheEvictsOnCommittedBlocks(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	blocksByChannel := map[string]chan *ledger.CommitNotification{
+		"channel1": make(chan *ledger.CommitNotification),
+		"channel2": make(chan *ledger.CommitNotification),
+	}
+	notifier := blockNotifierFunc(func(_ <-chan struct{}, channelName string) (<-chan *ledger.CommitNotification, error) {
+		return blocksByChannel[channelName], nil
+	})
+	cache := NewResultCache(notifier, 10, nil)
+	defer cache.Close()
+
+	key1AtHeight5, _ := cache.key(cacheableProposal("channel1", "msp", "arg"), 5)
+	key1AtHeight6, _ := cache.key(cacheableProposal("channel1", "msp", "arg"), 6)
+	key2AtHeight5, _ := cache.key(cacheableProposal("channel2", "msp", "arg"), 5)
+	cache.put(key1AtHeight5, cachedResult())
+	cache.put(key1AtHeight6, cachedResult())
+	cache.put(key2AtHeight5, cachedResult())
+
+	blocksByChannel["channel1"] <- &ledger.CommitNotification{BlockNumber: 5}
+	gt.Eventually(cache.size).Should(Equal(2))
+	_, ok := cache.get(key1AtHeight6, "txid")
+	gt.Expect(ok).To(BeTrue())
+	_, ok = cache.get(key2AtHeight5, "txid")
+	gt.Expect(ok).To(BeTrue())
+
+	close(blocksByChannel["channel1"])
+	gt.Eventually(cache.size).Should(Equal(1))
+	_, ok = cache.get(key2AtHeight5, "txid")
+	gt.Expect(ok).To(BeTrue())
+}
+
+func TestResultCacheResubscribes(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	var subscriptions []chan *ledger.CommitNotification
+	notifier := blockNotifierFunc(func(<-chan struct{}, string) (<-chan *ledger.CommitNotification, error) {
+		blocks := make(chan *ledger.CommitNotification)
+		subscriptions = append(subscriptions, blocks)
+		return blocks, nil
+	})
+	cache := NewResultCache(notifier, 10, nil)
+	defer cache.Close()
+
+	key, _ := cache.key(cacheableProposal("channel", "msp", "arg"), 5)
+	cache.put(key, cachedResult())
+	close(subscriptions[0])
+	gt.Eventually(cache.size).Should(Equal(0))
+
+	gt.Eventually(func() int {
+		cache.put(key, cachedResult())
+		return len(subscriptions)
+	}).Should(Equal(2))
+	gt.Expect(cache.size()).To(Equal(1))
+}
+
+func TestResultCacheDoesNotStoreUnwatchedChannels(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	notifier := blockNotifierFunc(func(<-chan struct{}, string) (<-chan *ledger.CommitNotification, error) {
+		return nil, fmt.Errorf("channel not found")
+	})
+	cache := NewResultCache(notifier, 10, nil)
+	defer cache.Close()
+
+	key, _ := cache.key(cacheableProposal("channel", "msp", "arg"), 5)
+	cache.put(key, cachedResult())
+	gt.Expect(cache.size()).To(Equal(0))
+}
+
+func TestResultCacheCopiesResults(t *testing.T) {
+	gt := NewGomegaWithT(t)
+	notifier := blockNotifierFunc(func(<-chan struct{}, string) (<-chan *ledger.CommitNotification, error) {
+		return make(chan *ledger.CommitNotification), nil
+	})
+	cache := NewResultCache(notifier, 10, nil)
+	defer cache.Close()
+
+	key, _ := cache.key(cacheableProposal("channel", "msp", "arg"), 5)
+	result := cachedResult()
+	result.ccEvent = &pb.ChaincodeEvent{TxId: "txid1", EventName: "event"}
+	cache.put(key, result)
+	result.response.Payload = []byte("modified")
+
+	cached, ok := cache.get(key, "txid2")
+	gt.Expect(ok).To(BeTrue())
+	gt.Expect(cached.response.Payload).To(Equal([]byte("payload")))
+	gt.Expect(cached.ccEvent.TxId).To(Equal("txid2"))
+	gt.Expect(cached.ccEvent.EventName).To(Equal("event"))
+	cached.response.Payload = []byte("modified")
+
+	cached, _ = cache.get(key, "txid3")
+	gt.Expect(cached.response.Payload).To(Equal([]byte("payload")))
+}
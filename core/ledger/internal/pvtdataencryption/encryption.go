@@ -0,0 +1,130 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package pvtdataencryption decides which private data collections of a channel
+// have their values encrypted at rest, and encrypts them with the keyring of the
+// channel. It is shared by the private data store and the state database.
+package pvtdataencryption
+
+import (
+	"crypto/sha256"
+	"sort"
+
+	"github.com/hyperledger/fabric/common/ledger/encryption"
+	"github.com/pkg/errors"
+)
+
+// Encryption encrypts the values of the collections configured for encryption.
+// A nil Encryption encrypts no value and fails to decrypt any.
+type Encryption struct {
+	keyring     *encryption.Keyring
+	enabled     bool
+	collections map[string]struct{}
+}
+
+// New returns an Encryption that, if enabled, encrypts the values of the listed
+// collections, given as "<namespace>/<collection>", or of all the collections
+// if none is listed. A disabled Encryption encrypts no value but still decrypts
+// the values encrypted while it was enabled.
+func New(keyring *encryption.Keyring, enabled bool, collections []string) *Encryption {
+	e := &Encryption{
+		keyring:     keyring,
+		enabled:     enabled,
+		collections: map[string]struct{}{},
+	}
+	for _, c := range collections {
+		e.collections[c] = struct{}{}
+	}
+	return e
+}
+
+// Scope returns the name under which the keyring holds the data key of a collection.
+func Scope(ns, coll string) string {
+	return ns + "/" + coll
+}
+
+// Enabled returns whether the values of the collection are to be encrypted.
+func (e *Encryption) Enabled(ns, coll string) bool {
+	if e == nil || !e.enabled {
+		return false
+	}
+	if len(e.collections) == 0 {
+		return true
+	}
+	_, ok := e.collections[Scope(ns, coll)]
+	return ok
+}
+
+// Keyring returns the keyring with which the values are encrypted.
+func (e *Encryption) Keyring() *encryption.Keyring {
+	if e == nil {
+		return nil
+	}
+	return e.keyring
+}
+
+// Encrypt encrypts the value of the collection with the current data key of
+// the collection. The additional data binds the envelope to the database key of
+// the value.
+func (e *Encryption) Encrypt(ns, coll string, value, additionalData []byte) ([]byte, error) {
+	envelope, err := e.keyring.Encrypt(Scope(ns, coll), value, additionalData)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error while encrypting the private data of collection [%s] of namespace [%s]", coll, ns)
+	}
+	return envelope, nil
+}
+
+// Decrypt decrypts the envelope of a value of the collection.
+func (e *Encryption) Decrypt(ns, coll string, envelope, additionalData []byte) ([]byte, error) {
+	if e == nil {
+		return nil, errors.Errorf("private data of collection [%s] of namespace [%s] is encrypted but the private data encryption is not configured", coll, ns)
+	}
+	value, err := e.keyring.Decrypt(envelope, additionalData)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error while decrypting the private data of collection [%s] of namespace [%s]", coll, ns)
+	}
+	return value, nil
+}
+
+// NeedsUpdate returns whether a stored value of the collection, encrypted or
+// not, has to be encrypted, re-encrypted with the current data key, or
+// decrypted to comply with the configuration.
+func (e *Encryption) NeedsUpdate(ns, coll string, envelope []byte, encrypted bool) (bool, error) {
+	if !e.Enabled(ns, coll) {
+		return encrypted, nil
+	}
+	if !encrypted {
+		return true, nil
+	}
+	current, err := e.keyring.IsCurrent(Scope(ns, coll), envelope)
+	if err != nil {
+		return false, errors.WithMessagef(err, "error while checking the data key of collection [%s] of namespace [%s]", coll, ns)
+	}
+	return !current, nil
+}
+
+// Fingerprint identifies the key encryption key and the collections to
+// encrypt, so that a change to either is noticed across restarts.
+func (e *Encryption) Fingerprint() []byte {
+	if e == nil {
+		return nil
+	}
+	h := sha256.New()
+	h.Write(e.keyring.CurrentKeySKI())
+	if e.enabled {
+		h.Write([]byte{1})
+		collections := make([]string, 0, len(e.collections))
+		for c := range e.collections {
+			collections = append(collections, c)
+		}
+		sort.Strings(collections)
+		for _, c := range collections {
+			h.Write([]byte(c))
+			h.Write([]byte{0})
+		}
+	}
+	return h.Sum(nil)
+}
@@ -17,10 +17,14 @@ func dropDBs(rootFSPath string) error {
 	// bookkeeper. Suppose if the config or bookkeeper is dropped first and the peer reset/rollback
 	// command fails before dropping the stateDB, peer cannot start with consistent data (if the
 	// user decides to start the peer without retrying the reset/rollback) as the stateDB would
-	// not be rebuilt.
+	// not be rebuilt. The state commitment records the blocks committed to the stateDB and is
+	// dropped along with it, so that it is rebuilt from the blocks retained by the rollback.
 	if err := dropStateLevelDB(rootFSPath); err != nil {
 		return err
 	}
+	if err := dropStateCommitmentDB(rootFSPath); err != nil {
+		return err
+	}
 	if err := dropConfigHistoryDB(rootFSPath); err != nil {
 		return err
 	}
@@ -36,6 +40,12 @@ func dropStateLevelDB(rootFSPath string) error {
 	return fileutil.RemoveContents(stateLeveldbPath)
 }
 
+func dropStateCommitmentDB(rootFSPath string) error {
+	stateCommitmentDBPath := StateCommitmentDBPath(rootFSPath)
+	logger.Infof("Dropping all contents in StateCommitmentDB at location [%s] ...if present", stateCommitmentDBPath)
+	return fileutil.RemoveContents(stateCommitmentDBPath)
+}
+
 func dropConfigHistoryDB(rootFSPath string) error {
 	configHistoryDBPath := ConfigHistoryDBPath(rootFSPath)
 	logger.Infof("Dropping all contents in ConfigHistoryDB at location [%s] ...if present", configHistoryDBPath)
@@ -25,9 +25,11 @@ import (
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
 	"github.com/hyperledger/fabric/core/ledger/confighistory"
+	"github.com/hyperledger/fabric/core/ledger/internal/pvtdataencryption"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/statecommitment"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validation"
@@ -52,11 +54,13 @@ type kvLedger struct {
 	bootSnapshotMetadata *SnapshotMetadata
 	blockStore           *blkstorage.BlockStore
 
-	pvtdataStoreLock sync.Mutex
-	pvtdataStore     *pvtdatastorage.Store
+	pvtdataStoreLock   sync.Mutex
+	pvtdataStore       *pvtdatastorage.Store
+	pvtdataReencryptor *pvtdataReencryptor
 
 	txmgr                  *txmgr.LockBasedTxMgr
 	historyDB              *history.DB
+	stateCommitment        *statecommitment.DB
 	configHistoryRetriever *collectionConfigHistoryRetriever
 	snapshotMgr            *snapshotMgr
 	blockAPIsRWLock        *sync.RWMutex
@@ -80,8 +84,10 @@ type lgrInitializer struct {
 	bootSnapshotMetadata     *SnapshotMetadata
 	blockStore               *blkstorage.BlockStore
 	pvtdataStore             *pvtdatastorage.Store
+	pvtdataEncryption        *pvtdataencryption.Encryption
 	stateDB                  *privacyenabledstate.DB
 	historyDB                *history.DB
+	stateCommitment          *statecommitment.DB
 	configHistoryMgr         *confighistory.Mgr
 	stateListeners           []ledger.StateListener
 	bookkeeperProvider       *bookkeeping.Provider
@@ -102,6 +108,7 @@ func newKVLedger(initializer *lgrInitializer) (*kvLedger, error) {
 		blockStore:           initializer.blockStore,
 		pvtdataStore:         initializer.pvtdataStore,
 		historyDB:            initializer.historyDB,
+		stateCommitment:      initializer.stateCommitment,
 		hashProvider:         initializer.hashProvider,
 		config:               initializer.config,
 		blockAPIsRWLock:      &sync.RWMutex{},
@@ -129,6 +136,10 @@ func newKVLedger(initializer *lgrInitializer) (*kvLedger, error) {
 		CCInfoProvider:      initializer.ccInfoProvider,
 		CustomTxProcessors:  initializer.customTxProcessors,
 		HashFunc:            rwsetHashFunc,
+		StateCommitment:     initializer.stateCommitment,
+	}
+	if initializer.config != nil && initializer.config.StateDBConfig != nil {
+		txmgrInitializer.ParallelValidationWorkers = initializer.config.StateDBConfig.ParallelValidationWorkers
 	}
 	if err := l.initTxMgr(txmgrInitializer); err != nil {
 		return nil, err
@@ -165,6 +176,10 @@ func newKVLedger(initializer *lgrInitializer) (*kvLedger, error) {
 		}
 	}
 
+	if err := l.syncStateCommitmentWithStateDB(initializer.stateDB); err != nil {
+		return nil, err
+	}
+
 	// Recover both state DB and history DB if they are out of sync with block storage
 	if err := l.recoverDBs(); err != nil {
 		return nil, err
@@ -179,6 +194,10 @@ func newKVLedger(initializer *lgrInitializer) (*kvLedger, error) {
 		return nil, err
 	}
 
+	if err := l.initPvtdataReencryptor(initializer); err != nil {
+		return nil, err
+	}
+
 	l.stats = initializer.stats
 	return l, nil
 }
@@ -276,6 +295,25 @@ func (l *kvLedger) initTxMgr(initializer *txmgr.Initializer) error {
 	return err
 }
 
+func (l *kvLedger) initPvtdataReencryptor(initializer *lgrInitializer) error {
+	if initializer.pvtdataEncryption == nil {
+		return nil
+	}
+	reencryptor, err := newPvtdataReencryptor(
+		l.ledgerID,
+		pvtdataEncryptionConfig(initializer.config),
+		initializer.pvtdataEncryption,
+		l.pvtdataStore,
+		l.txmgr,
+	)
+	if err != nil {
+		return err
+	}
+	l.pvtdataReencryptor = reencryptor
+	reencryptor.start()
+	return nil
+}
+
 func (l *kvLedger) initSnapshotMgr(initializer *lgrInitializer) error {
 	dbHandle := initializer.bookkeeperProvider.GetDBHandle(l.ledgerID, bookkeeping.SnapshotRequest)
 	bookkeeper, err := newSnapshotRequestBookkeeper(l.ledgerID, dbHandle)
@@ -1009,6 +1047,9 @@ func (l *kvLedger) sendCommitNotification(blockNum uint64, txStatsInfo []*valida
 // or snapshot generation before calling this function. Otherwise, the ledger may have unknown behavior
 // and cause panic.
 func (l *kvLedger) Close() {
+	if l.pvtdataReencryptor != nil {
+		l.pvtdataReencryptor.shutdown()
+	}
 	l.blockStore.Shutdown()
 	l.txmgr.Shutdown()
 	l.snapshotMgr.shutdown()
@@ -22,6 +22,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/msgs"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/statecommitment"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/pvtdatastorage"
 	"github.com/hyperledger/fabric/internal/fileutil"
@@ -55,18 +56,19 @@ const maxBlockFileSize = 64 * 1024 * 1024
 
 // Provider implements interface ledger.PeerLedgerProvider
 type Provider struct {
-	idStore              *idStore
-	blkStoreProvider     *blkstorage.BlockStoreProvider
-	pvtdataStoreProvider *pvtdatastorage.Provider
-	dbProvider           *privacyenabledstate.DBProvider
-	historydbProvider    *history.DBProvider
-	configHistoryMgr     *confighistory.Mgr
-	stateListeners       []ledger.StateListener
-	bookkeepingProvider  *bookkeeping.Provider
-	initializer          *ledger.Initializer
-	collElgNotifier      *collElgNotifier
-	stats                *stats
-	fileLock             *leveldbhelper.FileLock
+	idStore                 *idStore
+	blkStoreProvider        *blkstorage.BlockStoreProvider
+	pvtdataStoreProvider    *pvtdatastorage.Provider
+	dbProvider              *privacyenabledstate.DBProvider
+	historydbProvider       *history.DBProvider
+	stateCommitmentProvider *statecommitment.DBProvider
+	configHistoryMgr        *confighistory.Mgr
+	stateListeners          []ledger.StateListener
+	bookkeepingProvider     *bookkeeping.Provider
+	initializer             *ledger.Initializer
+	collElgNotifier         *collElgNotifier
+	stats                   *stats
+	fileLock                *leveldbhelper.FileLock
 }
 
 // NewProvider instantiates a new Provider.
@@ -110,6 +112,9 @@ func NewProvider(initializer *ledger.Initializer) (pr *Provider, e error) {
 	if err := p.initHistoryDBProvider(); err != nil {
 		return nil, err
 	}
+	if err := p.initStateCommitmentDBProvider(); err != nil {
+		return nil, err
+	}
 	if err := p.initConfigHistoryManager(); err != nil {
 		return nil, err
 	}
@@ -189,6 +194,21 @@ func (p *Provider) initHistoryDBProvider() error {
 	return nil
 }
 
+func (p *Provider) initStateCommitmentDBProvider() error {
+	if p.initializer.Config.StateCommitmentConfig == nil || !p.initializer.Config.StateCommitmentConfig.Enabled {
+		return nil
+	}
+	stateCommitmentProvider, err := statecommitment.NewDBProvider(
+		StateCommitmentDBPath(p.initializer.Config.RootFSPath),
+		p.initializer.Config.StateCommitmentConfig.RetainBlocks,
+	)
+	if err != nil {
+		return err
+	}
+	p.stateCommitmentProvider = stateCommitmentProvider
+	return nil
+}
+
 func (p *Provider) initConfigHistoryManager() error {
 	var err error
 	configHistoryMgr, err := confighistory.NewMgr(
@@ -350,12 +370,20 @@ func (p *Provider) open(ledgerID string, bootSnapshotMetadata *SnapshotMetadata,
 
 	p.collElgNotifier.registerListener(ledgerID, pvtdataStore)
 
+	// Get the encryption of the private data, which is shared by the pvtdata store and the state database
+	pvtdataEncryption, err := p.openPvtdataEncryption(ledgerID, pvtdataStore)
+	if err != nil {
+		return nil, err
+	}
+	pvtdataStore.SetEncryption(pvtdataEncryption)
+
 	// Get the versioned database (state database) for a chain/ledger
 	channelInfoProvider := &channelInfoProvider{ledgerID, blockStore, p.collElgNotifier.deployedChaincodeInfoProvider}
 	db, err := p.dbProvider.GetDBHandle(ledgerID, channelInfoProvider)
 	if err != nil {
 		return nil, err
 	}
+	db.SetEncryption(pvtdataEncryption)
 
 	// Get the history database (index for history of values by key) for a chain/ledger
 	var historyDB *history.DB
@@ -363,12 +391,20 @@ func (p *Provider) open(ledgerID string, bootSnapshotMetadata *SnapshotMetadata,
 		historyDB = p.historydbProvider.GetDBHandle(ledgerID)
 	}
 
+	// Get the state commitment (Merkle tree over the public state) for a chain/ledger
+	var stateCommitment *statecommitment.DB
+	if p.stateCommitmentProvider != nil {
+		stateCommitment = p.stateCommitmentProvider.GetDBHandle(ledgerID)
+	}
+
 	initializer := &lgrInitializer{
 		ledgerID:                 ledgerID,
 		blockStore:               blockStore,
 		pvtdataStore:             pvtdataStore,
+		pvtdataEncryption:        pvtdataEncryption,
 		stateDB:                  db,
 		historyDB:                historyDB,
+		stateCommitment:          stateCommitment,
 		configHistoryMgr:         p.configHistoryMgr,
 		stateListeners:           p.stateListeners,
 		bookkeeperProvider:       p.bookkeepingProvider,
@@ -422,6 +458,9 @@ func (p *Provider) Close() {
 	if p.historydbProvider != nil {
 		p.historydbProvider.Close()
 	}
+	if p.stateCommitmentProvider != nil {
+		p.stateCommitmentProvider.Close()
+	}
 	if p.fileLock != nil {
 		p.fileLock.Unlock()
 	}
@@ -474,12 +513,13 @@ func (p *Provider) deletePartialLedgers() error {
 // may have got created during in-complete ledger creation
 func (p *Provider) runCleanup(ledgerID string) error {
 	ledgerDataRemover := &ledgerDataRemover{
-		blkStoreProvider:     p.blkStoreProvider,
-		statedbProvider:      p.dbProvider,
-		bookkeepingProvider:  p.bookkeepingProvider,
-		configHistoryMgr:     p.configHistoryMgr,
-		historydbProvider:    p.historydbProvider,
-		pvtdataStoreProvider: p.pvtdataStoreProvider,
+		blkStoreProvider:        p.blkStoreProvider,
+		statedbProvider:         p.dbProvider,
+		bookkeepingProvider:     p.bookkeepingProvider,
+		configHistoryMgr:        p.configHistoryMgr,
+		historydbProvider:       p.historydbProvider,
+		stateCommitmentProvider: p.stateCommitmentProvider,
+		pvtdataStoreProvider:    p.pvtdataStoreProvider,
 	}
 	if err := ledgerDataRemover.Drop(ledgerID); err != nil {
 		return errors.WithMessagef(err, "error while deleting data from ledger [%s]", ledgerID)
@@ -11,21 +11,23 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/confighistory"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/statecommitment"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/pvtdatastorage"
 )
 
 type ledgerDataRemover struct {
-	blkStoreProvider     *blkstorage.BlockStoreProvider
-	statedbProvider      *privacyenabledstate.DBProvider
-	configHistoryMgr     *confighistory.Mgr
-	bookkeepingProvider  *bookkeeping.Provider
-	historydbProvider    *history.DBProvider
-	pvtdataStoreProvider *pvtdatastorage.Provider
+	blkStoreProvider        *blkstorage.BlockStoreProvider
+	statedbProvider         *privacyenabledstate.DBProvider
+	configHistoryMgr        *confighistory.Mgr
+	bookkeepingProvider     *bookkeeping.Provider
+	historydbProvider       *history.DBProvider
+	stateCommitmentProvider *statecommitment.DBProvider
+	pvtdataStoreProvider    *pvtdatastorage.Provider
 }
 
 // Drop drops channel-specific data from all the ledger DBs, which includes
-// stateDB, configHistoryDB, bookkeeperDB, historyDB, pvtdataStore, block index and blocks directory.
+// stateDB, configHistoryDB, bookkeeperDB, historyDB, stateCommitmentDB, pvtdataStore, block index and blocks directory.
 // This function can be called multiple times for the same ledgerID. It is not an error if the ledger
 // does not exist. The data consistency and concurrency control will be handled outside of this function.
 func (r *ledgerDataRemover) Drop(ledgerID string) error {
@@ -53,6 +55,13 @@ func (r *ledgerDataRemover) Drop(ledgerID string) error {
 		}
 	}
 
+	if r.stateCommitmentProvider != nil {
+		if err = r.stateCommitmentProvider.Drop(ledgerID); err != nil {
+			logger.Errorw("failed to drop ledger data from stateCommitmentDB", "channel", ledgerID, "error", err)
+			return err
+		}
+	}
+
 	if err = r.pvtdataStoreProvider.Drop(ledgerID); err != nil {
 		logger.Errorw("failed to drop ledger data from pvtdataStore", "channel", ledgerID, "error", err)
 		return err
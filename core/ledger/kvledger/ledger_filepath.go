@@ -40,6 +40,11 @@ func HistoryDBPath(rootFSPath string) string {
 	return filepath.Join(rootFSPath, "historyLeveldb")
 }
 
+// StateCommitmentDBPath returns the absolute path of state commitment DB
+func StateCommitmentDBPath(rootFSPath string) string {
+	return filepath.Join(rootFSPath, "stateCommitmentLeveldb")
+}
+
 // ConfigHistoryDBPath returns the absolute path of configHistory DB
 func ConfigHistoryDBPath(rootFSPath string) string {
 	return filepath.Join(rootFSPath, "configHistory")
@@ -0,0 +1,352 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/encryption"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/internal/pvtdataencryption"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
+	"github.com/hyperledger/fabric/core/ledger/pvtdatastorage"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultReencryptionBatchSize = 1000
+	reencryptionRetryInterval    = time.Minute
+)
+
+var (
+	pvtdataKeyringPrefix    = []byte("keyring/")
+	reencryptionProgressKey = []byte("reencryption")
+)
+
+type reencryptionPhase uint64
+
+const (
+	reencryptingPvtdataStore reencryptionPhase = iota
+	reencryptingStateDB
+	reencryptionCompleted
+)
+
+func (p reencryptionPhase) String() string {
+	switch p {
+	case reencryptingPvtdataStore:
+		return "pvtdata_store"
+	case reencryptingStateDB:
+		return "state_database"
+	case reencryptionCompleted:
+		return "completed"
+	default:
+		return "unknown"
+	}
+}
+
+// PvtdataEncryptionStatus reports the encryption at rest of the private data of a ledger.
+type PvtdataEncryptionStatus struct {
+	LedgerID string `json:"channel"`
+	// Enabled is whether the private data of the configured collections are encrypted.
+	// The private data encrypted before the encryption was disabled are decrypted by the
+	// re-encryption.
+	Enabled bool `json:"enabled"`
+	// EncryptionKeySKI is the hex encoded subject key identifier of the current key
+	// encryption key.
+	EncryptionKeySKI string `json:"encryption_key_ski"`
+	// Reencryption is the phase of the re-encryption that brings the stored private
+	// data in line with the configuration and with the current key: "pvtdata_store",
+	// "state_database" or "completed".
+	Reencryption string `json:"reencryption"`
+}
+
+// openPvtdataEncryption returns the encryption of the private data of the ledger, whose
+// keyring is kept in the private data store. It returns nil if the encryption is disabled
+// and was never enabled, so that there is nothing to decrypt.
+func (p *Provider) openPvtdataEncryption(ledgerID string, pvtdataStore *pvtdatastorage.Store) (*pvtdataencryption.Encryption, error) {
+	conf := pvtdataEncryptionConfig(p.initializer.Config)
+	if !conf.Enabled {
+		exists, err := encryption.KeyringExists(pvtdataStore.EncryptionMetadata(), pvtdataKeyringPrefix)
+		if err != nil || !exists {
+			return nil, err
+		}
+	}
+	if p.initializer.CryptoProvider == nil {
+		return nil, errors.Errorf("a crypto provider is required to open the private data encryption keyring of ledger [%s]", ledgerID)
+	}
+	keyring, err := encryption.NewKeyring(p.initializer.CryptoProvider, pvtdataStore.EncryptionMetadata(), pvtdataKeyringPrefix)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "error while opening the private data encryption keyring of ledger [%s]", ledgerID)
+	}
+	return pvtdataencryption.New(keyring, conf.Enabled, conf.Collections), nil
+}
+
+func pvtdataEncryptionConfig(config *ledger.Config) *ledger.PrivateDataEncryptionConfig {
+	if config == nil || config.PrivateDataConfig == nil || config.PrivateDataConfig.Encryption == nil {
+		return &ledger.PrivateDataEncryptionConfig{}
+	}
+	return config.PrivateDataConfig.Encryption
+}
+
+// pvtdataReencryptor brings, in the background, the private data in the private data
+// store and in the state database in line with the encryption of their collection and
+// with the current key. It examines the private data in batches and records its progress
+// in the private data store, so that it resumes where it stopped after a restart. It starts
+// over whenever the key or the configuration of the encryption changes.
+type pvtdataReencryptor struct {
+	ledgerID        string
+	enabled         bool
+	encryption      *pvtdataencryption.Encryption
+	pvtdataStore    *pvtdatastorage.Store
+	txmgr           *txmgr.LockBasedTxMgr
+	batchSize       int
+	batchesInterval time.Duration
+
+	mutex    sync.Mutex
+	progress *reencryptionProgress
+
+	trigger  chan struct{}
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newPvtdataReencryptor(
+	ledgerID string,
+	conf *ledger.PrivateDataEncryptionConfig,
+	e *pvtdataencryption.Encryption,
+	pvtdataStore *pvtdatastorage.Store,
+	txmgr *txmgr.LockBasedTxMgr,
+) (*pvtdataReencryptor, error) {
+	r := &pvtdataReencryptor{
+		ledgerID:        ledgerID,
+		enabled:         conf.Enabled,
+		encryption:      e,
+		pvtdataStore:    pvtdataStore,
+		txmgr:           txmgr,
+		batchSize:       conf.ReencryptionBatchSize,
+		batchesInterval: conf.ReencryptionBatchesInterval,
+		trigger:         make(chan struct{}, 1),
+		stop:            make(chan struct{}),
+		done:            make(chan struct{}),
+	}
+	if r.batchSize <= 0 {
+		r.batchSize = defaultReencryptionBatchSize
+	}
+	progressBytes, err := pvtdataStore.EncryptionMetadata().Get(reencryptionProgressKey)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error while reading the progress of the private data re-encryption")
+	}
+	if r.progress, err = unmarshalReencryptionProgress(progressBytes); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *pvtdataReencryptor) start() {
+	go r.run()
+}
+
+func (r *pvtdataReencryptor) run() {
+	defer close(r.done)
+	for {
+		completed, err := r.reencryptBatch()
+		var wait <-chan time.Time
+		switch {
+		case err != nil:
+			logger.Errorw("Failed to re-encrypt the private data", "channelID", r.ledgerID, "error", err)
+			wait = time.After(reencryptionRetryInterval)
+		case !completed:
+			wait = time.After(r.batchesInterval)
+		}
+		select {
+		case <-r.stop:
+			return
+		case <-r.trigger:
+		case <-wait:
+		}
+	}
+}
+
+// reencryptBatch re-encrypts the next batch of private data and returns whether the
+// re-encryption is completed.
+func (r *pvtdataReencryptor) reencryptBatch() (bool, error) {
+	r.mutex.Lock()
+	progress := r.progress
+	r.mutex.Unlock()
+
+	fingerprint := r.encryption.Fingerprint()
+	if !bytes.Equal(progress.fingerprint, fingerprint) {
+		logger.Infow("Starting the re-encryption of the private data", "channelID", r.ledgerID)
+		progress = &reencryptionProgress{fingerprint: fingerprint, phase: reencryptingPvtdataStore}
+	}
+
+	next := &reencryptionProgress{fingerprint: fingerprint, phase: progress.phase}
+	switch progress.phase {
+	case reencryptingPvtdataStore:
+		nextKey, updated, err := r.pvtdataStore.ReencryptData(progress.pvtdataStoreKey, r.batchSize)
+		if err != nil {
+			return false, err
+		}
+		logger.Debugw("Re-encrypted the private data in the private data store", "channelID", r.ledgerID, "updated", updated)
+		next.pvtdataStoreKey = nextKey
+		if nextKey == nil {
+			next.phase = reencryptingStateDB
+		}
+
+	case reencryptingStateDB:
+		nextKey, updated, err := r.txmgr.ReencryptPvtData(progress.stateDBKey, r.batchSize)
+		if err != nil {
+			return false, err
+		}
+		logger.Debugw("Re-encrypted the private data in the state database", "channelID", r.ledgerID, "updated", updated)
+		next.stateDBKey = nextKey
+		if nextKey == nil {
+			next.phase = reencryptionCompleted
+			logger.Infow("Completed the re-encryption of the private data", "channelID", r.ledgerID)
+		}
+
+	default:
+		return true, nil
+	}
+
+	if err := r.pvtdataStore.EncryptionMetadata().Put(reencryptionProgressKey, next.marshal(), true); err != nil {
+		return false, errors.WithMessage(err, "error while recording the progress of the private data re-encryption")
+	}
+	r.mutex.Lock()
+	r.progress = next
+	r.mutex.Unlock()
+	return next.phase == reencryptionCompleted, nil
+}
+
+// rotateKey generates a new key encryption key and wakes the re-encryption up, so that
+// the private data encrypted with the former keys are re-encrypted.
+func (r *pvtdataReencryptor) rotateKey() ([]byte, error) {
+	if !r.enabled {
+		return nil, errors.Errorf("private data encryption is not enabled for ledger [%s]", r.ledgerID)
+	}
+	ski, err := r.encryption.Keyring().RotateKey()
+	if err != nil {
+		return nil, err
+	}
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+	}
+	return ski, nil
+}
+
+func (r *pvtdataReencryptor) status() *PvtdataEncryptionStatus {
+	r.mutex.Lock()
+	progress := r.progress
+	r.mutex.Unlock()
+
+	phase := progress.phase
+	if !bytes.Equal(progress.fingerprint, r.encryption.Fingerprint()) {
+		phase = reencryptingPvtdataStore
+	}
+	return &PvtdataEncryptionStatus{
+		LedgerID:         r.ledgerID,
+		Enabled:          r.enabled,
+		EncryptionKeySKI: hex.EncodeToString(r.encryption.Keyring().CurrentKeySKI()),
+		Reencryption:     phase.String(),
+	}
+}
+
+func (r *pvtdataReencryptor) shutdown() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+		<-r.done
+	})
+}
+
+// reencryptionProgress records the position of the re-encryption along with the
+// fingerprint of the encryption it complies with.
+type reencryptionProgress struct {
+	fingerprint     []byte
+	phase           reencryptionPhase
+	pvtdataStoreKey []byte
+	stateDBKey      *privacyenabledstate.PvtdataCompositeKey
+}
+
+func (p *reencryptionProgress) marshal() []byte {
+	buf := proto.NewBuffer(nil)
+	// the encoding to a buffer does not fail
+	_ = buf.EncodeRawBytes(p.fingerprint)
+	_ = buf.EncodeVarint(uint64(p.phase))
+	_ = buf.EncodeRawBytes(p.pvtdataStoreKey)
+	if p.stateDBKey == nil {
+		_ = buf.EncodeVarint(0)
+		return buf.Bytes()
+	}
+	_ = buf.EncodeVarint(1)
+	_ = buf.EncodeStringBytes(p.stateDBKey.Namespace)
+	_ = buf.EncodeStringBytes(p.stateDBKey.CollectionName)
+	_ = buf.EncodeStringBytes(p.stateDBKey.Key)
+	return buf.Bytes()
+}
+
+func unmarshalReencryptionProgress(b []byte) (*reencryptionProgress, error) {
+	if b == nil {
+		return &reencryptionProgress{}, nil
+	}
+	p := &reencryptionProgress{}
+	buf := proto.NewBuffer(b)
+	var err error
+	if p.fingerprint, err = buf.DecodeRawBytes(true); err != nil {
+		return nil, errors.Wrap(err, "error while decoding the progress of the private data re-encryption")
+	}
+	phase, err := buf.DecodeVarint()
+	if err != nil {
+		return nil, errors.Wrap(err, "error while decoding the progress of the private data re-encryption")
+	}
+	p.phase = reencryptionPhase(phase)
+	if p.pvtdataStoreKey, err = buf.DecodeRawBytes(true); err != nil {
+		return nil, errors.Wrap(err, "error while decoding the progress of the private data re-encryption")
+	}
+	if len(p.pvtdataStoreKey) == 0 {
+		p.pvtdataStoreKey = nil
+	}
+	hasStateDBKey, err := buf.DecodeVarint()
+	if err != nil {
+		return nil, errors.Wrap(err, "error while decoding the progress of the private data re-encryption")
+	}
+	if hasStateDBKey == 0 {
+		return p, nil
+	}
+	p.stateDBKey = &privacyenabledstate.PvtdataCompositeKey{}
+	for _, s := range []*string{&p.stateDBKey.Namespace, &p.stateDBKey.CollectionName, &p.stateDBKey.Key} {
+		if *s, err = buf.DecodeStringBytes(); err != nil {
+			return nil, errors.Wrap(err, "error while decoding the progress of the private data re-encryption")
+		}
+	}
+	return p, nil
+}
+
+// PvtdataEncryptionStatus returns the status of the encryption at rest of the private data
+// of the ledger, or nil if its private data have never been encrypted.
+func (l *kvLedger) PvtdataEncryptionStatus() *PvtdataEncryptionStatus {
+	if l.pvtdataReencryptor == nil {
+		return nil
+	}
+	return l.pvtdataReencryptor.status()
+}
+
+// RotatePvtdataEncryptionKey generates a new key encryption key for the private data of the
+// ledger and returns its subject key identifier. The private data are then re-encrypted in
+// the background with data keys wrapped with the new key.
+func (l *kvLedger) RotatePvtdataEncryptionKey() ([]byte, error) {
+	if l.pvtdataReencryptor == nil {
+		return nil, errors.Errorf("private data encryption is not enabled for ledger [%s]", l.ledgerID)
+	}
+	return l.pvtdataReencryptor.rotateKey()
+}
@@ -0,0 +1,188 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPvtdataEncryption(t *testing.T) {
+	csp, err := sw.NewDefaultSecurityLevel(t.TempDir())
+	require.NoError(t, err)
+	conf := testConfig(t)
+	conf.PrivateDataConfig.Encryption = &ledger.PrivateDataEncryptionConfig{
+		Enabled:               true,
+		Collections:           []string{"ns1/coll1"},
+		ReencryptionBatchSize: 1,
+	}
+	newProvider := func() *Provider {
+		provider := testutilNewProviderWithCollectionConfig(
+			t,
+			[]*nsCollBtlConfig{
+				{namespace: "ns1", btlConfig: map[string]uint64{"coll1": 0, "coll2": 0}},
+			},
+			conf,
+		)
+		provider.initializer.CryptoProvider = csp
+		ccInfoProvider := provider.initializer.DeployedChaincodeInfoProvider.(*mock.DeployedChaincodeInfoProvider)
+		ccInfoProvider.AllChaincodesInfoReturns(
+			map[string]*ledger.DeployedChaincodeInfo{
+				"ns1": {
+					Name: "ns1",
+					ExplicitCollectionConfigPkg: &peer.CollectionConfigPackage{
+						Config: []*peer.CollectionConfig{
+							{Payload: &peer.CollectionConfig_StaticCollectionConfig{StaticCollectionConfig: &peer.StaticCollectionConfig{Name: "coll1"}}},
+							{Payload: &peer.CollectionConfig_StaticCollectionConfig{StaticCollectionConfig: &peer.StaticCollectionConfig{Name: "coll2"}}},
+						},
+					},
+				},
+			},
+			nil,
+		)
+		ccInfoProvider.GenerateImplicitCollectionForOrgStub = func(mspID string) *peer.StaticCollectionConfig {
+			return &peer.StaticCollectionConfig{Name: "_implicit_org_" + mspID}
+		}
+		return provider
+	}
+
+	provider := newProvider()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	lgr, err := provider.CreateFromGenesisBlock(gb)
+	require.NoError(t, err)
+
+	for i, value := range []string{"value1", "value2"} {
+		txid := util.GenerateUUID()
+		simulator, err := lgr.NewTxSimulator(txid)
+		require.NoError(t, err)
+		require.NoError(t, simulator.SetPrivateData("ns1", "coll1", "key1", []byte(value+"-coll1")))
+		require.NoError(t, simulator.SetPrivateData("ns1", "coll2", "key1", []byte(value+"-coll2")))
+		simulator.Done()
+		simRes, err := simulator.GetTxSimulationResults()
+		require.NoError(t, err)
+		pubSimBytes, err := simRes.GetPubSimulationBytes()
+		require.NoError(t, err)
+		block := bg.NextBlockWithTxid([][]byte{pubSimBytes}, []string{txid})
+		require.NoError(t, lgr.CommitLegacy(&ledger.BlockAndPvtData{
+			Block:   block,
+			PvtData: ledger.TxPvtDataMap{0: {SeqInBlock: 0, WriteSet: simRes.PvtSimulationResults}},
+		}, &ledger.CommitOptions{}))
+		testVerifyPvtData(t, lgr, uint64(i+1), ledger.TxPvtDataMap{0: {SeqInBlock: 0, WriteSet: simRes.PvtSimulationResults}})
+	}
+
+	kvlgr := lgr.(*kvLedger)
+	requirePvtdata := func() {
+		qe, err := lgr.NewQueryExecutor()
+		require.NoError(t, err)
+		defer qe.Done()
+		value, err := qe.GetPrivateData("ns1", "coll1", "key1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value2-coll1"), value)
+		value, err = qe.GetPrivateData("ns1", "coll2", "key1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value2-coll2"), value)
+
+		pvtdata, err := lgr.GetPvtDataByNum(1, nil)
+		require.NoError(t, err)
+		require.Len(t, pvtdata, 1)
+		require.True(t, pvtdata[0].Has("ns1", "coll1"))
+		require.True(t, pvtdata[0].Has("ns1", "coll2"))
+	}
+	requireCompleted := func(lgr *kvLedger) *PvtdataEncryptionStatus {
+		var status *PvtdataEncryptionStatus
+		require.Eventually(t, func() bool {
+			status = lgr.PvtdataEncryptionStatus()
+			return status.Reencryption == "completed"
+		}, 10*time.Second, 10*time.Millisecond)
+		return status
+	}
+	requirePvtdata()
+	status := requireCompleted(kvlgr)
+	require.True(t, status.Enabled)
+	require.Equal(t, "testLedger", status.LedgerID)
+	initialSKI := status.EncryptionKeySKI
+
+	// rotating the key re-encrypts the private data with the new key
+	ski, err := kvlgr.RotatePvtdataEncryptionKey()
+	require.NoError(t, err)
+	status = requireCompleted(kvlgr)
+	require.NotEqual(t, initialSKI, status.EncryptionKeySKI)
+	require.Equal(t, hex.EncodeToString(ski), status.EncryptionKeySKI)
+	requirePvtdata()
+
+	// the private data remain readable once the encryption is disabled, and the
+	// re-encryption decrypts them
+	lgr.Close()
+	provider.Close()
+	conf.PrivateDataConfig.Encryption.Enabled = false
+	provider = newProvider()
+	defer provider.Close()
+	lgr, err = provider.Open("testLedger")
+	require.NoError(t, err)
+	defer lgr.Close()
+	kvlgr = lgr.(*kvLedger)
+	status = requireCompleted(kvlgr)
+	require.False(t, status.Enabled)
+	require.Equal(t, hex.EncodeToString(ski), status.EncryptionKeySKI)
+	requirePvtdata()
+	_, err = kvlgr.RotatePvtdataEncryptionKey()
+	require.EqualError(t, err, "private data encryption is not enabled for ledger [testLedger]")
+
+	t.Run("encryption never enabled", func(t *testing.T) {
+		conf := testConfig(t)
+		provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+		defer provider.Close()
+		_, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+		lgr, err := provider.CreateFromGenesisBlock(gb)
+		require.NoError(t, err)
+		defer lgr.Close()
+		require.Nil(t, lgr.(*kvLedger).PvtdataEncryptionStatus())
+		_, err = lgr.(*kvLedger).RotatePvtdataEncryptionKey()
+		require.EqualError(t, err, "private data encryption is not enabled for ledger [testLedger]")
+	})
+
+	t.Run("crypto provider missing", func(t *testing.T) {
+		conf := testConfig(t)
+		conf.PrivateDataConfig.Encryption = &ledger.PrivateDataEncryptionConfig{Enabled: true}
+		provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+		defer provider.Close()
+		_, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+		_, err := provider.CreateFromGenesisBlock(gb)
+		require.EqualError(t, err, "a crypto provider is required to open the private data encryption keyring of ledger [testLedger]")
+	})
+}
+
+func TestReencryptionProgressEncoding(t *testing.T) {
+	for _, p := range []*reencryptionProgress{
+		{},
+		{fingerprint: []byte("fingerprint"), phase: reencryptingPvtdataStore, pvtdataStoreKey: []byte("key")},
+		{
+			fingerprint: []byte("fingerprint"),
+			phase:       reencryptingStateDB,
+			stateDBKey:  &privacyenabledstate.PvtdataCompositeKey{Namespace: "ns", CollectionName: "coll", Key: "key"},
+		},
+		{fingerprint: []byte("fingerprint"), phase: reencryptionCompleted},
+	} {
+		decoded, err := unmarshalReencryptionProgress(p.marshal())
+		require.NoError(t, err)
+		require.Equal(t, p, decoded)
+	}
+
+	_, err := unmarshalReencryptionProgress([]byte{0xff})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "error while decoding the progress of the private data re-encryption")
+}
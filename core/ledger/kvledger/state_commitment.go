@@ -0,0 +1,125 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"bytes"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/stateproof"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// syncStateCommitmentWithStateDB builds the state commitment from the state database if it is behind the
+// state database, which is the case when the state commitment is enabled for an existing ledger, when the
+// ledger is created from a snapshot or when the state commitment database is dropped. The state commitment
+// is otherwise kept in sync by the transaction manager, including when the state database recovers the
+// blocks missed due to a crash, and so it must be called before recovering the state database.
+func (l *kvLedger) syncStateCommitmentWithStateDB(stateDB *privacyenabledstate.DB) error {
+	if l.stateCommitment == nil {
+		return nil
+	}
+	stateSavepoint, err := l.txmgr.GetLastSavepoint()
+	if err != nil || stateSavepoint == nil {
+		return err
+	}
+	commitmentSavepoint, exists, err := l.stateCommitment.LastCommittedBlockNum()
+	if err != nil {
+		return err
+	}
+	if exists && commitmentSavepoint >= stateSavepoint.BlockNum {
+		return nil
+	}
+
+	logger.Infof("Building the state commitment of ledger [%s] from the state database at block [%d]", l.ledgerID, stateSavepoint.BlockNum)
+	itr, err := stateDB.GetPubStateFullScanIterator()
+	if err != nil {
+		return err
+	}
+	defer itr.Close()
+	return errors.WithMessagef(
+		l.stateCommitment.ImportState(stateSavepoint.BlockNum, itr),
+		"error while building the state commitment of ledger [%s]", l.ledgerID,
+	)
+}
+
+// GetStateProof implements method in interface `ledger.PeerLedger`
+func (l *kvLedger) GetStateProof(namespace, key string, blockNum uint64) (*stateproof.Proof, error) {
+	if l.stateCommitment == nil {
+		return nil, errors.New("state commitment is not enabled")
+	}
+
+	// the lock is held by the commit of a block to the block store and to the
+	// state, so that they are consistent with each other while it is held
+	l.blockAPIsRWLock.RLock()
+	defer l.blockAPIsRWLock.RUnlock()
+
+	info, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return nil, err
+	}
+	if blockNum >= info.Height {
+		return nil, errors.Errorf("block [%d] is not committed, ledger [%s] is at height [%d]", blockNum, l.ledgerID, info.Height)
+	}
+
+	proof, ver, err := l.stateCommitment.GetProof(blockNum, namespace, key)
+	if err != nil || ver == nil {
+		return proof, err
+	}
+	if proof.Value, err = l.valueOfLeaf(namespace, key, ver, proof.Leaf.ValueHash); err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// valueOfLeaf returns the value of the key whose hash is recorded in the leaf of the state commitment,
+// from the state database if the key was not updated since, or from the transaction that wrote it otherwise.
+func (l *kvLedger) valueOfLeaf(namespace, key string, ver *version.Height, valueHash []byte) ([]byte, error) {
+	qe, err := l.txmgr.NewQueryExecutorNoCollChecks()
+	if err != nil {
+		return nil, err
+	}
+	value, err := qe.GetState(namespace, key)
+	qe.Done()
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(stateproof.ValueHash(value), valueHash) {
+		return value, nil
+	}
+
+	notAvailable := func(cause error) error {
+		return errors.WithMessagef(cause, "value of key [%s] of namespace [%s] written by transaction [%d] of block [%d] is not available",
+			key, namespace, ver.TxNum, ver.BlockNum)
+	}
+	env, err := l.blockStore.RetrieveTxByBlockNumTranNum(ver.BlockNum, ver.TxNum)
+	if err != nil {
+		return nil, notAvailable(err)
+	}
+	action, err := protoutil.GetActionFromEnvelopeMsg(env)
+	if err != nil {
+		return nil, notAvailable(err)
+	}
+	txRWSet := &rwsetutil.TxRwSet{}
+	if err := txRWSet.FromProtoBytes(action.Results); err != nil {
+		return nil, notAvailable(err)
+	}
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		if nsRWSet.NameSpace != namespace {
+			continue
+		}
+		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+			if kvWrite.Key == key && bytes.Equal(stateproof.ValueHash(kvWrite.Value), valueHash) {
+				return kvWrite.Value, nil
+			}
+		}
+	}
+	return nil, notAvailable(errors.New("write not found in transaction"))
+}
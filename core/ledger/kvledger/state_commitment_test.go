@@ -0,0 +1,122 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func commitStateUpdatesForTest(t *testing.T, lgr ledger.PeerLedger, bg *testutil.BlockGenerator, updates map[string]string) {
+	simulator, err := lgr.NewTxSimulator(util.GenerateUUID())
+	require.NoError(t, err)
+	for key, value := range updates {
+		if value == "" {
+			require.NoError(t, simulator.DeleteState("ns1", key))
+			continue
+		}
+		require.NoError(t, simulator.SetState("ns1", key, []byte(value)))
+	}
+	simulator.Done()
+	simRes, err := simulator.GetTxSimulationResults()
+	require.NoError(t, err)
+	pubSimBytes, err := simRes.GetPubSimulationBytes()
+	require.NoError(t, err)
+	block := bg.NextBlock([][]byte{pubSimBytes})
+	require.NoError(t, lgr.CommitLegacy(&ledger.BlockAndPvtData{Block: block}, &ledger.CommitOptions{}))
+}
+
+func requireStateProof(t *testing.T, lgr ledger.PeerLedger, key string, blockNum uint64, expectedValue string) []byte {
+	proof, err := lgr.GetStateProof("ns1", key, blockNum)
+	require.NoError(t, err)
+	require.NoError(t, proof.Verify())
+	require.Equal(t, blockNum, proof.BlockNum)
+	if expectedValue == "" {
+		require.Nil(t, proof.Value)
+	} else {
+		require.Equal(t, []byte(expectedValue), proof.Value)
+	}
+	return proof.Root
+}
+
+func TestGetStateProof(t *testing.T) {
+	conf := testConfig(t)
+	conf.StateCommitmentConfig = &ledger.StateCommitmentConfig{Enabled: true}
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	lgr, err := provider.CreateFromGenesisBlock(gb)
+	require.NoError(t, err)
+	defer lgr.Close()
+
+	commitStateUpdatesForTest(t, lgr, bg, map[string]string{"key1": "value1", "key2": "value2"})
+	commitStateUpdatesForTest(t, lgr, bg, map[string]string{"key1": "value1-updated", "key2": "", "key3": "value3"})
+
+	// the values at block 1 are read from the block store as they were updated since
+	root := requireStateProof(t, lgr, "key1", 1, "value1")
+	require.Equal(t, root, requireStateProof(t, lgr, "key2", 1, "value2"))
+	require.Equal(t, root, requireStateProof(t, lgr, "key3", 1, ""))
+
+	root = requireStateProof(t, lgr, "key1", 2, "value1-updated")
+	require.Equal(t, root, requireStateProof(t, lgr, "key2", 2, ""))
+	require.Equal(t, root, requireStateProof(t, lgr, "key3", 2, "value3"))
+
+	requireStateProof(t, lgr, "key1", 0, "")
+
+	_, err = lgr.GetStateProof("ns1", "key1", 3)
+	require.EqualError(t, err, "block [3] is not committed, ledger [testLedger] is at height [3]")
+
+	t.Run("state commitment not enabled", func(t *testing.T) {
+		conf := testConfig(t)
+		provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+		defer provider.Close()
+
+		_, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+		lgr, err := provider.CreateFromGenesisBlock(gb)
+		require.NoError(t, err)
+		defer lgr.Close()
+
+		_, err = lgr.GetStateProof("ns1", "key1", 0)
+		require.EqualError(t, err, "state commitment is not enabled")
+	})
+}
+
+func TestStateCommitmentEnabledForExistingLedger(t *testing.T) {
+	conf := testConfig(t)
+	provider := testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	lgr, err := provider.CreateFromGenesisBlock(gb)
+	require.NoError(t, err)
+	commitStateUpdatesForTest(t, lgr, bg, map[string]string{"key1": "value1", "key2": "value2"})
+	commitStateUpdatesForTest(t, lgr, bg, map[string]string{"key2": "value2-updated"})
+	provider.Close()
+
+	conf.StateCommitmentConfig = &ledger.StateCommitmentConfig{Enabled: true}
+	provider = testutilNewProvider(conf, t, &mock.DeployedChaincodeInfoProvider{})
+	defer provider.Close()
+	lgr, err = provider.Open("testLedger")
+	require.NoError(t, err)
+	defer lgr.Close()
+
+	// the state commitment is built at the last block committed to the state database
+	root := requireStateProof(t, lgr, "key1", 2, "value1")
+	require.Equal(t, root, requireStateProof(t, lgr, "key2", 2, "value2-updated"))
+	_, err = lgr.GetStateProof("ns1", "key1", 1)
+	require.EqualError(t, err, "state commitment of channel [testLedger] is not available at block [1]")
+
+	commitStateUpdatesForTest(t, lgr, bg, map[string]string{"key1": "", "key3": "value3"})
+	root = requireStateProof(t, lgr, "key1", 3, "")
+	require.Equal(t, root, requireStateProof(t, lgr, "key2", 3, "value2-updated"))
+	require.Equal(t, root, requireStateProof(t, lgr, "key3", 3, "value3"))
+	requireStateProof(t, lgr, "key1", 2, "value1")
+}
@@ -0,0 +1,331 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package statecommitment maintains, for each channel, a Merkle tree over the
+// public state whose root commits to the state at every block, and generates
+// the proofs of the values of keys defined in package stateproof.
+package statecommitment
+
+import (
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/dataformat"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/stateproof"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("statecommitment")
+
+// importBatchSize is the number of keys of the state added to the tree at a
+// time when the tree is built from the state database.
+var importBatchSize = 10000
+
+// DBProvider provides handle to the state commitment of a given channel
+type DBProvider struct {
+	leveldbProvider *leveldbhelper.Provider
+	retainBlocks    uint64
+}
+
+// NewDBProvider instantiates DBProvider. If retainBlocks is not zero, the
+// state commitments only generate proofs as of the last retainBlocks blocks,
+// and prune the versions of the nodes and the roots of the older blocks.
+func NewDBProvider(path string, retainBlocks uint64) (*DBProvider, error) {
+	logger.Debugf("constructing StateCommitmentDBProvider dbPath=%s", path)
+	levelDBProvider, err := leveldbhelper.NewProvider(
+		&leveldbhelper.Conf{
+			DBPath:         path,
+			ExpectedFormat: dataformat.CurrentFormat,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &DBProvider{
+		leveldbProvider: levelDBProvider,
+		retainBlocks:    retainBlocks,
+	}, nil
+}
+
+// GetDBHandle gets the handle to a named database
+func (p *DBProvider) GetDBHandle(name string) *DB {
+	return &DB{
+		levelDB:      p.leveldbProvider.GetDBHandle(name),
+		name:         name,
+		retainBlocks: p.retainBlocks,
+	}
+}
+
+// Close closes the underlying db
+func (p *DBProvider) Close() {
+	p.leveldbProvider.Close()
+}
+
+// Drop drops channel-specific data from the state commitment db
+func (p *DBProvider) Drop(channelName string) error {
+	return p.leveldbProvider.Drop(channelName)
+}
+
+// DB maintains the state commitment of a particular channel. The nodes of the
+// tree are versioned by the block that wrote them, so that proofs can be
+// generated as of any block committed after the state commitment was enabled,
+// or as of the last retainBlocks blocks if retainBlocks is not zero.
+type DB struct {
+	levelDB      *leveldbhelper.DBHandle
+	name         string
+	retainBlocks uint64
+}
+
+// Commit updates the tree with the public updates of the block and records
+// its root for the block. The block is skipped if it was already committed,
+// which happens when the block is recommitted to the state database after a
+// crash that occurred after its commit to the state commitment.
+func (d *DB) Commit(blockNum uint64, updates *statedb.UpdateBatch) error {
+	savepoint, exists, err := d.LastCommittedBlockNum()
+	if err != nil {
+		return err
+	}
+	if exists && blockNum <= savepoint {
+		logger.Debugf("Channel [%s]: Skipping block [%d] already committed to the state commitment", d.name, blockNum)
+		return nil
+	}
+	if exists && blockNum != savepoint+1 {
+		return errors.Errorf("state commitment of channel [%s] cannot be updated with block [%d] after block [%d]", d.name, blockNum, savepoint)
+	}
+	if !exists && blockNum != 0 {
+		return errors.Errorf("state commitment of channel [%s] is empty and cannot be updated with block [%d]", d.name, blockNum)
+	}
+
+	root := stateproof.EmptyHash()
+	if exists {
+		if root, err = d.root(savepoint); err != nil {
+			return err
+		}
+	}
+
+	var leaves []*leaf
+	for _, ns := range updates.GetUpdatedNamespaces() {
+		for key, vv := range updates.GetUpdates(ns) {
+			l := &leaf{keyHash: stateproof.KeyHash(ns, key), version: vv.Version}
+			if len(vv.Value) != 0 {
+				l.valueHash = stateproof.ValueHash(vv.Value)
+			}
+			leaves = append(leaves, l)
+		}
+	}
+	sortByKeyHash(leaves)
+
+	batch := d.levelDB.NewUpdateBatch()
+	if root, err = d.apply(blockNum, root, leaves, batch); err != nil {
+		return err
+	}
+	batch.Put(encodeRootKey(blockNum), root)
+	batch.Put(savePointKey, encodeBlockNum(blockNum))
+	if err := d.prune(blockNum, batch); err != nil {
+		return err
+	}
+	if err := d.levelDB.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	logger.Debugf("Channel [%s]: State commitment at block [%d] has root [%x]", d.name, blockNum, root)
+	return nil
+}
+
+// ImportState builds the tree of the public state at the block, returned by
+// the iterator, and records its root for the block. The tree does not depend
+// on the existing versions of the nodes, so the state commitment can be built
+// again at a later block, but it cannot generate proofs as of the blocks
+// between its previous savepoint and that block.
+func (d *DB) ImportState(blockNum uint64, itr statedb.FullScanIterator) error {
+	root := stateproof.EmptyHash()
+	batch := d.levelDB.NewUpdateBatch()
+	var numKeys int
+	for {
+		var leaves []*leaf
+		for len(leaves) < importBatchSize {
+			kv, err := itr.Next()
+			if err != nil {
+				return err
+			}
+			if kv == nil {
+				break
+			}
+			if len(kv.Value) == 0 {
+				continue
+			}
+			leaves = append(leaves, &leaf{
+				keyHash:   stateproof.KeyHash(kv.Namespace, kv.Key),
+				valueHash: stateproof.ValueHash(kv.Value),
+				version:   kv.Version,
+			})
+		}
+		if len(leaves) == 0 {
+			break
+		}
+		sortByKeyHash(leaves)
+
+		var err error
+		if root, err = d.apply(blockNum, root, leaves, batch); err != nil {
+			return err
+		}
+		if err := d.levelDB.WriteBatch(batch, false); err != nil {
+			return err
+		}
+		batch.Reset()
+		numKeys += len(leaves)
+	}
+
+	batch.Put(encodeRootKey(blockNum), root)
+	batch.Put(savePointKey, encodeBlockNum(blockNum))
+	if err := d.levelDB.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	logger.Infof("Channel [%s]: Built state commitment of %d keys at block [%d] with root [%x]", d.name, numKeys, blockNum, root)
+	return nil
+}
+
+// apply adds to the batch the nodes written by the updates, sorted by key
+// hash, to the tree with the root, and returns the resulting root.
+func (d *DB) apply(blockNum uint64, root []byte, updates []*leaf, batch *leveldbhelper.UpdateBatch) ([]byte, error) {
+	t := newTree(d, blockNum)
+	rootNode, err := t.load(rootPosition(), root)
+	if err != nil {
+		return nil, err
+	}
+	if rootNode, err = t.update(rootPosition(), rootNode, updates); err != nil {
+		return nil, err
+	}
+	if err := t.addWritesTo(batch); err != nil {
+		return nil, err
+	}
+	return rootNode.hash(), nil
+}
+
+// prune adds to the batch the deletion of the roots of the blocks that are no
+// longer retained once the block is committed, and of the versions of the
+// nodes superseded by the oldest retained block or before it, which no root of
+// a retained block can reach.
+func (d *DB) prune(blockNum uint64, batch *leveldbhelper.UpdateBatch) error {
+	if d.retainBlocks == 0 || blockNum < d.retainBlocks {
+		return nil
+	}
+	oldestRetained := blockNum - d.retainBlocks + 1
+
+	rootItr, err := d.levelDB.GetIterator(encodeRootKey(0), encodeRootKey(oldestRetained))
+	if err != nil {
+		return err
+	}
+	defer rootItr.Release()
+	for rootItr.Next() {
+		batch.Delete(append([]byte(nil), rootItr.Key()...))
+	}
+	if err := rootItr.Error(); err != nil {
+		return errors.Wrapf(err, "internal leveldb error while pruning the state commitment")
+	}
+
+	pruneItr, err := d.levelDB.GetIterator(encodePruneKey(0, nil), encodePruneKey(oldestRetained+1, nil))
+	if err != nil {
+		return err
+	}
+	defer pruneItr.Release()
+	var numNodes int
+	for pruneItr.Next() {
+		batch.Delete(append([]byte(nil), pruneItr.Key()...))
+		batch.Delete(decodePruneKey(pruneItr.Key()))
+		numNodes++
+	}
+	if err := pruneItr.Error(); err != nil {
+		return errors.Wrapf(err, "internal leveldb error while pruning the state commitment")
+	}
+	logger.Debugf("Channel [%s]: Pruned %d node versions of the state commitment before block [%d]", d.name, numNodes, oldestRetained)
+	return nil
+}
+
+// GetProof returns the proof of the key of the namespace as of the block,
+// without the value of the key, and the version of the key if it exists.
+func (d *DB) GetProof(blockNum uint64, namespace, key string) (*stateproof.Proof, *version.Height, error) {
+	savepoint, exists, err := d.LastCommittedBlockNum()
+	if err != nil {
+		return nil, nil, err
+	}
+	if exists && d.retainBlocks > 0 && blockNum <= savepoint && savepoint-blockNum >= d.retainBlocks {
+		return nil, nil, errors.Errorf("state commitment of channel [%s] is not available at block [%d], only the last %d blocks are retained",
+			d.name, blockNum, d.retainBlocks)
+	}
+	root, err := d.root(blockNum)
+	if err != nil {
+		return nil, nil, err
+	}
+	proof, l, err := newTree(d, blockNum).proof(root, stateproof.KeyHash(namespace, key))
+	if err != nil {
+		return nil, nil, err
+	}
+	proof.Namespace = namespace
+	proof.Key = key
+	if l == nil {
+		return proof, nil, nil
+	}
+	return proof, l.version, nil
+}
+
+// LastCommittedBlockNum returns the number of the last block committed to the
+// state commitment, and false if no block was committed.
+func (d *DB) LastCommittedBlockNum() (uint64, bool, error) {
+	b, err := d.levelDB.Get(savePointKey)
+	if err != nil || b == nil {
+		return 0, false, err
+	}
+	blockNum, err := decodeBlockNum(b)
+	if err != nil {
+		return 0, false, err
+	}
+	return blockNum, true, nil
+}
+
+func (d *DB) root(blockNum uint64) ([]byte, error) {
+	root, err := d.levelDB.Get(encodeRootKey(blockNum))
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		return nil, errors.Errorf("state commitment of channel [%s] is not available at block [%d]", d.name, blockNum)
+	}
+	return root, nil
+}
+
+// getNode returns the most recent version of the node at the position written
+// by the block or before it.
+func (d *DB) getNode(p position, blockNum uint64) (*node, error) {
+	startKey, endKey := nodeKeyRange(p, blockNum)
+	itr, err := d.levelDB.GetIterator(startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Release()
+	if !itr.Next() {
+		return nil, errors.Wrapf(itr.Error(), "internal leveldb error while reading the state commitment")
+	}
+	return nodeFromBytes(append([]byte(nil), itr.Value()...))
+}
+
+// getNodeKeyBefore returns the key of the most recent version of the node at
+// the position written before the block, or nil if there is none.
+func (d *DB) getNodeKeyBefore(p position, blockNum uint64) ([]byte, error) {
+	if blockNum == 0 {
+		return nil, nil
+	}
+	startKey, endKey := nodeKeyRange(p, blockNum-1)
+	itr, err := d.levelDB.GetIterator(startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+	defer itr.Release()
+	if !itr.Next() {
+		return nil, errors.Wrapf(itr.Error(), "internal leveldb error while reading the state commitment")
+	}
+	return append([]byte(nil), itr.Key()...), nil
+}
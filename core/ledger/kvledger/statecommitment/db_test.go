@@ -0,0 +1,312 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecommitment
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/stateproof"
+	"github.com/stretchr/testify/require"
+)
+
+type testState map[statedb.CompositeKey][]byte
+
+func (s testState) clone() testState {
+	c := testState{}
+	for k, v := range s {
+		c[k] = v
+	}
+	return c
+}
+
+// fullScanIterator returns the keys of the state in the lexical order of
+// <namespace, key>, as the state database does.
+type fullScanIterator struct {
+	kvs []*statedb.VersionedKV
+}
+
+func newFullScanIterator(s testState) *fullScanIterator {
+	itr := &fullScanIterator{}
+	for k, v := range s {
+		k := k
+		itr.kvs = append(itr.kvs, &statedb.VersionedKV{
+			CompositeKey:   &k,
+			VersionedValue: &statedb.VersionedValue{Value: v, Version: version.NewHeight(1, 0)},
+		})
+	}
+	sort.Slice(itr.kvs, func(i, j int) bool {
+		if itr.kvs[i].Namespace != itr.kvs[j].Namespace {
+			return itr.kvs[i].Namespace < itr.kvs[j].Namespace
+		}
+		return itr.kvs[i].Key < itr.kvs[j].Key
+	})
+	return itr
+}
+
+func (itr *fullScanIterator) Next() (*statedb.VersionedKV, error) {
+	if len(itr.kvs) == 0 {
+		return nil, nil
+	}
+	kv := itr.kvs[0]
+	itr.kvs = itr.kvs[1:]
+	return kv, nil
+}
+
+func (itr *fullScanIterator) Close() {}
+
+func newTestDB(t *testing.T, name string) *DB {
+	provider, err := NewDBProvider(t.TempDir(), 0)
+	require.NoError(t, err)
+	t.Cleanup(provider.Close)
+	return provider.GetDBHandle(name)
+}
+
+func requireValidProof(t *testing.T, db *DB, blockNum uint64, k statedb.CompositeKey, value []byte) {
+	proof, ver, err := db.GetProof(blockNum, k.Namespace, k.Key)
+	require.NoError(t, err)
+	require.Equal(t, blockNum, proof.BlockNum)
+	require.Equal(t, value != nil, ver != nil)
+	proof.Value = value
+	require.NoError(t, proof.Verify(), "proof of %s at block %d", k, blockNum)
+
+	if value != nil {
+		proof.Value = append([]byte("tampered-"), value...)
+	} else {
+		proof.Value = []byte("tampered")
+	}
+	require.Error(t, proof.Verify())
+}
+
+// commitRandomBlocks commits blocks of random updates of the keys, and returns
+// the state at each block.
+func commitRandomBlocks(t *testing.T, db *DB, keys []statedb.CompositeKey, numBlocks uint64) []testState {
+	r := rand.New(rand.NewSource(0))
+	states := []testState{}
+	state := testState{}
+	for blockNum := uint64(0); blockNum < numBlocks; blockNum++ {
+		batch := statedb.NewUpdateBatch()
+		for txNum := uint64(0); txNum < uint64(r.Intn(15)); txNum++ {
+			k := keys[r.Intn(len(keys))]
+			ver := version.NewHeight(blockNum, txNum)
+			if r.Intn(3) == 0 {
+				batch.Delete(k.Namespace, k.Key, ver)
+				delete(state, k)
+				continue
+			}
+			value := []byte(fmt.Sprintf("value-%d-%d", blockNum, txNum))
+			batch.Put(k.Namespace, k.Key, value, ver)
+			state[k] = value
+		}
+		require.NoError(t, db.Commit(blockNum, batch))
+		states = append(states, state.clone())
+
+		savepoint, exists, err := db.LastCommittedBlockNum()
+		require.NoError(t, err)
+		require.True(t, exists)
+		require.Equal(t, blockNum, savepoint)
+	}
+	return states
+}
+
+func testKeys() []statedb.CompositeKey {
+	var keys []statedb.CompositeKey
+	for i := 0; i < 60; i++ {
+		keys = append(keys, statedb.CompositeKey{Namespace: fmt.Sprintf("ns%d", i%3), Key: fmt.Sprintf("key%d", i)})
+	}
+	return keys
+}
+
+func TestCommitAndGetProof(t *testing.T) {
+	db := newTestDB(t, "testchannel")
+	keys := testKeys()
+	states := commitRandomBlocks(t, db, keys, 25)
+
+	for blockNum, state := range states {
+		for _, k := range keys {
+			requireValidProof(t, db, uint64(blockNum), k, state[k])
+		}
+	}
+
+	t.Run("root depends only on the state", func(t *testing.T) {
+		importedDB := newTestDB(t, "testchannel")
+		require.NoError(t, importedDB.ImportState(24, newFullScanIterator(states[24])))
+		root, err := db.root(24)
+		require.NoError(t, err)
+		importedRoot, err := importedDB.root(24)
+		require.NoError(t, err)
+		require.Equal(t, root, importedRoot)
+	})
+
+	t.Run("block already committed", func(t *testing.T) {
+		batch := statedb.NewUpdateBatch()
+		batch.Put("ns0", "key0", []byte("other-value"), version.NewHeight(24, 0))
+		require.NoError(t, db.Commit(24, batch))
+		requireValidProof(t, db, 24, keys[0], states[24][keys[0]])
+	})
+
+	t.Run("block missing", func(t *testing.T) {
+		err := db.Commit(26, statedb.NewUpdateBatch())
+		require.EqualError(t, err, "state commitment of channel [testchannel] cannot be updated with block [26] after block [24]")
+	})
+
+	t.Run("proof of block not committed", func(t *testing.T) {
+		_, _, err := db.GetProof(25, "ns0", "key0")
+		require.EqualError(t, err, "state commitment of channel [testchannel] is not available at block [25]")
+	})
+}
+
+func TestPrune(t *testing.T) {
+	countKeys := func(db *DB, prefix []byte) int {
+		itr, err := db.levelDB.GetIterator(prefix, []byte{prefix[0] + 1})
+		require.NoError(t, err)
+		defer itr.Release()
+		var count int
+		for itr.Next() {
+			count++
+		}
+		return count
+	}
+
+	keys := testKeys()
+	db := newTestDB(t, "testchannel")
+	commitRandomBlocks(t, db, keys, 25)
+
+	provider, err := NewDBProvider(t.TempDir(), 5)
+	require.NoError(t, err)
+	t.Cleanup(provider.Close)
+	prunedDB := provider.GetDBHandle("testchannel")
+	states := commitRandomBlocks(t, prunedDB, keys, 25)
+
+	for blockNum := uint64(20); blockNum < 25; blockNum++ {
+		for _, k := range keys {
+			requireValidProof(t, prunedDB, blockNum, k, states[blockNum][k])
+		}
+	}
+	_, _, err = prunedDB.GetProof(19, "ns0", "key0")
+	require.EqualError(t, err, "state commitment of channel [testchannel] is not available at block [19], only the last 5 blocks are retained")
+
+	require.Equal(t, 5, countKeys(prunedDB, rootKeyPrefix))
+	require.Less(t, countKeys(prunedDB, nodeKeyPrefix), countKeys(db, nodeKeyPrefix))
+	require.Equal(t, 0, countKeys(db, pruneKeyPrefix))
+
+	itr, err := prunedDB.levelDB.GetIterator(pruneKeyPrefix, []byte{pruneKeyPrefix[0] + 1})
+	require.NoError(t, err)
+	defer itr.Release()
+	for itr.Next() {
+		supersededBy, err := decodeBlockNum(itr.Key()[1:9])
+		require.NoError(t, err)
+		require.Greater(t, supersededBy, uint64(20))
+	}
+}
+
+func TestCommitToEmptyDB(t *testing.T) {
+	db := newTestDB(t, "testchannel")
+	_, exists, err := db.LastCommittedBlockNum()
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	err = db.Commit(1, statedb.NewUpdateBatch())
+	require.EqualError(t, err, "state commitment of channel [testchannel] is empty and cannot be updated with block [1]")
+
+	require.NoError(t, db.Commit(0, statedb.NewUpdateBatch()))
+	root, err := db.root(0)
+	require.NoError(t, err)
+	require.Equal(t, stateproof.EmptyHash(), root)
+	requireValidProof(t, db, 0, statedb.CompositeKey{Namespace: "ns", Key: "key"}, nil)
+}
+
+func TestImportState(t *testing.T) {
+	defer func(size int) { importBatchSize = size }(importBatchSize)
+	importBatchSize = 7
+
+	state := testState{}
+	for i := 0; i < 50; i++ {
+		state[statedb.CompositeKey{Namespace: "ns", Key: fmt.Sprintf("key%d", i)}] = []byte(fmt.Sprintf("value%d", i))
+	}
+
+	db := newTestDB(t, "testchannel")
+	require.NoError(t, db.ImportState(10, newFullScanIterator(state)))
+	savepoint, exists, err := db.LastCommittedBlockNum()
+	require.NoError(t, err)
+	require.True(t, exists)
+	require.Equal(t, uint64(10), savepoint)
+	for k, v := range state {
+		requireValidProof(t, db, 10, k, v)
+	}
+	requireValidProof(t, db, 10, statedb.CompositeKey{Namespace: "ns", Key: "key50"}, nil)
+
+	_, _, err = db.GetProof(9, "ns", "key0")
+	require.EqualError(t, err, "state commitment of channel [testchannel] is not available at block [9]")
+
+	// the blocks following the import update the imported tree
+	batch := statedb.NewUpdateBatch()
+	batch.Delete("ns", "key0", version.NewHeight(11, 0))
+	batch.Put("ns", "key1", []byte("new-value"), version.NewHeight(11, 1))
+	require.NoError(t, db.Commit(11, batch))
+	requireValidProof(t, db, 11, statedb.CompositeKey{Namespace: "ns", Key: "key0"}, nil)
+	requireValidProof(t, db, 11, statedb.CompositeKey{Namespace: "ns", Key: "key1"}, []byte("new-value"))
+	requireValidProof(t, db, 10, statedb.CompositeKey{Namespace: "ns", Key: "key0"}, []byte("value0"))
+
+	// the state commitment can be built again at a later block
+	delete(state, statedb.CompositeKey{Namespace: "ns", Key: "key2"})
+	require.NoError(t, db.ImportState(20, newFullScanIterator(state)))
+	requireValidProof(t, db, 20, statedb.CompositeKey{Namespace: "ns", Key: "key2"}, nil)
+	requireValidProof(t, db, 20, statedb.CompositeKey{Namespace: "ns", Key: "key3"}, []byte("value3"))
+	requireValidProof(t, db, 11, statedb.CompositeKey{Namespace: "ns", Key: "key2"}, []byte("value2"))
+}
+
+func TestGetProofVersion(t *testing.T) {
+	db := newTestDB(t, "testchannel")
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns", "key1", []byte("value1"), version.NewHeight(0, 3))
+	batch.Put("ns", "key2", []byte("value2"), version.NewHeight(0, 4))
+	require.NoError(t, db.Commit(0, batch))
+
+	_, ver, err := db.GetProof(0, "ns", "key1")
+	require.NoError(t, err)
+	require.Equal(t, version.NewHeight(0, 3), ver)
+
+	proof, ver, err := db.GetProof(0, "ns", "key3")
+	require.NoError(t, err)
+	require.Nil(t, ver)
+	require.NoError(t, proof.Verify())
+}
+
+func TestCorruptedNode(t *testing.T) {
+	db := newTestDB(t, "testchannel")
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns", "key1", []byte("value1"), version.NewHeight(0, 0))
+	batch.Put("ns", "key2", []byte("value2"), version.NewHeight(0, 1))
+	require.NoError(t, db.Commit(0, batch))
+
+	n := &node{left: stateproof.EmptyHash(), right: stateproof.EmptyHash()}
+	require.NoError(t, db.levelDB.Put(encodeNodeKey(rootPosition(), 0), n.toBytes(), true))
+	_, _, err := db.GetProof(0, "ns", "key1")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "node at depth [0] of the state commitment of channel [testchannel] does not match the hash")
+}
+
+func TestDrop(t *testing.T) {
+	provider, err := NewDBProvider(t.TempDir(), 0)
+	require.NoError(t, err)
+	defer provider.Close()
+
+	db := provider.GetDBHandle("testchannel")
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns", "key1", []byte("value1"), version.NewHeight(0, 0))
+	require.NoError(t, db.Commit(0, batch))
+
+	require.NoError(t, provider.Drop("testchannel"))
+	_, exists, err := provider.GetDBHandle("testchannel").LastCommittedBlockNum()
+	require.NoError(t, err)
+	require.False(t, exists)
+}
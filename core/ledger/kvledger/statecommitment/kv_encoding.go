@@ -0,0 +1,162 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecommitment
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/stateproof"
+	"github.com/pkg/errors"
+)
+
+var (
+	nodeKeyPrefix  = []byte{'n'} // prefix of the keys of the nodes of the tree
+	rootKeyPrefix  = []byte{'r'} // prefix of the keys of the roots of the tree by block
+	savePointKey   = []byte{'s'} // a single key in db for persisting savepoint
+	pruneKeyPrefix = []byte{'p'} // prefix of the keys of the versions of the nodes by the block that superseded them
+)
+
+const (
+	internalNodeType = byte(0x00)
+	leafNodeType     = byte(0x01)
+)
+
+// position identifies a node of the tree by its depth and the leading depth
+// bits of the key hashes of its subtree.
+type position struct {
+	depth int
+	path  []byte
+}
+
+func rootPosition() position {
+	return position{path: make([]byte, stateproof.HashSize)}
+}
+
+// child returns the position of the left child if the bit is 0, or of the
+// right child if it is 1.
+func (p position) child(bit byte) position {
+	path := append([]byte(nil), p.path...)
+	if bit == 1 {
+		path[p.depth/8] |= 1 << (7 - uint(p.depth%8))
+	}
+	return position{depth: p.depth + 1, path: path}
+}
+
+// id identifies the position among the nodes written by a block.
+func (p position) id() string {
+	return string(encodeNodeKeyPrefix(p))
+}
+
+// encodeNodeKey returns the key of the version of a node written by a block
+// as n~depth~path~(^blockNum) so that, for a given position, the versions of
+// the node sort from the most recent block.
+func encodeNodeKey(p position, blockNum uint64) []byte {
+	k := encodeNodeKeyPrefix(p)
+	return append(k, encodeBlockNum(^blockNum)...)
+}
+
+func encodeNodeKeyPrefix(p position) []byte {
+	k := append([]byte(nil), nodeKeyPrefix...)
+	k = append(k, byte(p.depth>>8), byte(p.depth))
+	return append(k, p.path...)
+}
+
+// nodeKeyRange returns the range of the keys of the versions of the node at
+// the position written by the block or before it.
+func nodeKeyRange(p position, blockNum uint64) (startKey, endKey []byte) {
+	prefix := encodeNodeKeyPrefix(p)
+	return encodeNodeKey(p, blockNum), append(prefix, bytes.Repeat([]byte{0xff}, 9)...)
+}
+
+// encodePruneKey returns the key recording that the version of a node, whose
+// key is the node key, was superseded by the block, as p~blockNum~nodeKey so
+// that the versions superseded by the blocks up to a given block are pruned
+// with a single range scan.
+func encodePruneKey(supersededBy uint64, nodeKey []byte) []byte {
+	k := append(append([]byte(nil), pruneKeyPrefix...), encodeBlockNum(supersededBy)...)
+	return append(k, nodeKey...)
+}
+
+func decodePruneKey(k []byte) (nodeKey []byte) {
+	return append([]byte(nil), k[len(pruneKeyPrefix)+8:]...)
+}
+
+func encodeRootKey(blockNum uint64) []byte {
+	return append(append([]byte(nil), rootKeyPrefix...), encodeBlockNum(blockNum)...)
+}
+
+func encodeBlockNum(blockNum uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, blockNum)
+	return b
+}
+
+func decodeBlockNum(b []byte) (uint64, error) {
+	if len(b) != 8 {
+		return 0, errors.Errorf("block number of %d bytes instead of 8", len(b))
+	}
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// node is a node of the tree, either an internal node holding the hashes of
+// its children or a leaf holding a key and value of the public state.
+type node struct {
+	left, right []byte
+	leaf        *leaf
+}
+
+// leaf holds the hashes of a key and its value, and the version of the key,
+// which locates the value in the block store. A nil value hash denotes the
+// deletion of the key in updates.
+type leaf struct {
+	keyHash   []byte
+	valueHash []byte
+	version   *version.Height
+}
+
+func (n *node) hash() []byte {
+	if n == nil {
+		return stateproof.EmptyHash()
+	}
+	if n.leaf != nil {
+		return stateproof.LeafHash(n.leaf.keyHash, n.leaf.valueHash)
+	}
+	return stateproof.InternalHash(n.left, n.right)
+}
+
+func (n *node) toBytes() []byte {
+	if n.leaf != nil {
+		b := append([]byte{leafNodeType}, n.leaf.keyHash...)
+		b = append(b, n.leaf.valueHash...)
+		return append(b, n.leaf.version.ToBytes()...)
+	}
+	b := append([]byte{internalNodeType}, n.left...)
+	return append(b, n.right...)
+}
+
+func nodeFromBytes(b []byte) (*node, error) {
+	const hashesSize = 2 * stateproof.HashSize
+	if len(b) < 1+hashesSize {
+		return nil, errors.Errorf("node of the state commitment has %d bytes", len(b))
+	}
+	hashes := b[1 : 1+hashesSize]
+	left, right := hashes[:stateproof.HashSize], hashes[stateproof.HashSize:]
+	switch b[0] {
+	case internalNodeType:
+		return &node{left: left, right: right}, nil
+	case leafNodeType:
+		ver, _, err := version.NewHeightFromBytes(b[1+hashesSize:])
+		if err != nil {
+			return nil, errors.WithMessage(err, "error while decoding the version of a leaf of the state commitment")
+		}
+		return &node{leaf: &leaf{keyHash: left, valueHash: right, version: ver}}, nil
+	default:
+		return nil, errors.Errorf("node of the state commitment has unknown type %d", b[0])
+	}
+}
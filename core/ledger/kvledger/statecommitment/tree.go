@@ -0,0 +1,253 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecommitment
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/stateproof"
+	"github.com/pkg/errors"
+)
+
+// tree reads the nodes of the tree as of a block, and collects the nodes that
+// the updates of the block write so that they are committed in a single batch.
+//
+// A node is only ever written along with its ancestors up to the root, so the
+// most recent version of a node reachable from the root of a block is the one
+// that the root refers to. The nodes which become unreachable, for instance
+// when a leaf moves up after the deletion of its sibling, are left in place
+// and only recorded as removed so that they can be pruned.
+type tree struct {
+	db       *DB
+	blockNum uint64
+	writes   map[string]*nodeWrite
+}
+
+// nodeWrite is a node written at a position, or the removal of the node at
+// the position if the node is nil.
+type nodeWrite struct {
+	position position
+	node     *node
+}
+
+func newTree(db *DB, blockNum uint64) *tree {
+	return &tree{
+		db:       db,
+		blockNum: blockNum,
+		writes:   map[string]*nodeWrite{},
+	}
+}
+
+// load returns the node at the position whose hash its parent records, or nil
+// if the hash is the one of an empty subtree.
+func (t *tree) load(p position, hash []byte) (*node, error) {
+	if bytes.Equal(hash, stateproof.EmptyHash()) {
+		return nil, nil
+	}
+	var n *node
+	if w, ok := t.writes[p.id()]; ok {
+		n = w.node
+	} else {
+		var err error
+		if n, err = t.db.getNode(p, t.blockNum); err != nil {
+			return nil, err
+		}
+	}
+	if n == nil || !bytes.Equal(n.hash(), hash) {
+		return nil, errors.Errorf("node at depth [%d] of the state commitment of channel [%s] does not match the hash [%x] recorded by its parent at block [%d]",
+			p.depth, t.db.name, hash, t.blockNum)
+	}
+	return n, nil
+}
+
+func (t *tree) write(p position, n *node) {
+	t.writes[p.id()] = &nodeWrite{position: p, node: n}
+}
+
+func (t *tree) remove(p position) {
+	t.writes[p.id()] = &nodeWrite{position: p}
+}
+
+// addWritesTo adds the written nodes to the batch and, if the versions of the
+// nodes are pruned, records the previous versions of the nodes written or
+// removed as superseded by the block.
+func (t *tree) addWritesTo(batch *leveldbhelper.UpdateBatch) error {
+	for _, w := range t.writes {
+		if t.db.retainBlocks > 0 {
+			prevKey, err := t.db.getNodeKeyBefore(w.position, t.blockNum)
+			if err != nil {
+				return err
+			}
+			if prevKey != nil {
+				batch.Put(encodePruneKey(t.blockNum, prevKey), []byte{})
+			}
+		}
+		if w.node != nil {
+			batch.Put(encodeNodeKey(w.position, t.blockNum), w.node.toBytes())
+		}
+	}
+	t.writes = map[string]*nodeWrite{}
+	return nil
+}
+
+// update applies the updates, sorted by key hash, to the subtree of the node
+// at the position, which is nil if the subtree is empty, and returns the
+// resulting node.
+func (t *tree) update(p position, n *node, updates []*leaf) (*node, error) {
+	if len(updates) == 0 {
+		return n, nil
+	}
+	if n == nil || n.leaf != nil {
+		built := t.build(p, merge(n, updates))
+		if built == nil && n != nil {
+			t.remove(p)
+		}
+		return built, nil
+	}
+
+	split := splitIndex(updates, p.depth)
+	children := [2]*node{}
+	hashes := [2][]byte{n.left, n.right}
+	for bit, childUpdates := range [2][]*leaf{updates[:split], updates[split:]} {
+		if len(childUpdates) == 0 {
+			continue
+		}
+		childPosition := p.child(byte(bit))
+		child, err := t.load(childPosition, hashes[bit])
+		if err != nil {
+			return nil, err
+		}
+		if child, err = t.update(childPosition, child, childUpdates); err != nil {
+			return nil, err
+		}
+		children[bit], hashes[bit] = child, child.hash()
+	}
+
+	// a subtree holding a single leaf is replaced by the leaf
+	for bit := range hashes {
+		if !bytes.Equal(hashes[bit], stateproof.EmptyHash()) {
+			continue
+		}
+		other := 1 - bit
+		if bytes.Equal(hashes[other], stateproof.EmptyHash()) {
+			t.remove(p)
+			return nil, nil
+		}
+		if children[other] == nil {
+			var err error
+			if children[other], err = t.load(p.child(byte(other)), hashes[other]); err != nil {
+				return nil, err
+			}
+		}
+		if children[other].leaf != nil {
+			t.remove(p.child(byte(other)))
+			t.write(p, children[other])
+			return children[other], nil
+		}
+	}
+
+	updated := &node{left: hashes[0], right: hashes[1]}
+	t.write(p, updated)
+	return updated, nil
+}
+
+// build writes the subtree at the position holding the leaves, sorted by key
+// hash, and returns its node.
+func (t *tree) build(p position, leaves []*leaf) *node {
+	switch len(leaves) {
+	case 0:
+		return nil
+	case 1:
+		n := &node{leaf: leaves[0]}
+		t.write(p, n)
+		return n
+	}
+	split := splitIndex(leaves, p.depth)
+	left := t.build(p.child(0), leaves[:split])
+	right := t.build(p.child(1), leaves[split:])
+	n := &node{left: left.hash(), right: right.hash()}
+	t.write(p, n)
+	return n
+}
+
+// proof returns the proof of the key hash from the root, and the leaf of the
+// key hash if it exists.
+func (t *tree) proof(root, keyHash []byte) (*stateproof.Proof, *leaf, error) {
+	proof := &stateproof.Proof{
+		BlockNum: t.blockNum,
+		Root:     root,
+		Siblings: [][]byte{},
+	}
+	p, hash := rootPosition(), root
+	for {
+		n, err := t.load(p, hash)
+		if err != nil {
+			return nil, nil, err
+		}
+		if n == nil {
+			return proof, nil, nil
+		}
+		if n.leaf != nil {
+			proof.Leaf = &stateproof.Leaf{KeyHash: n.leaf.keyHash, ValueHash: n.leaf.valueHash}
+			if !bytes.Equal(n.leaf.keyHash, keyHash) {
+				return proof, nil, nil
+			}
+			return proof, n.leaf, nil
+		}
+		bit := stateproof.KeyBit(keyHash, p.depth)
+		if bit == 0 {
+			proof.Siblings = append(proof.Siblings, n.right)
+			hash = n.left
+		} else {
+			proof.Siblings = append(proof.Siblings, n.left)
+			hash = n.right
+		}
+		p = p.child(bit)
+	}
+}
+
+// merge returns the leaves resulting from the updates, sorted by key hash, to
+// the subtree holding at most the leaf of the node.
+func merge(n *node, updates []*leaf) []*leaf {
+	var leaves []*leaf
+	var existing *leaf
+	if n != nil {
+		existing = n.leaf
+	}
+	for _, u := range updates {
+		if existing != nil && bytes.Compare(existing.keyHash, u.keyHash) < 0 {
+			leaves = append(leaves, existing)
+			existing = nil
+		}
+		if existing != nil && bytes.Equal(existing.keyHash, u.keyHash) {
+			existing = nil
+		}
+		if u.valueHash != nil {
+			leaves = append(leaves, u)
+		}
+	}
+	if existing != nil {
+		leaves = append(leaves, existing)
+	}
+	return leaves
+}
+
+// splitIndex returns the index of the first of the leaves, sorted by key hash,
+// which belongs to the right subtree of a node at the depth.
+func splitIndex(leaves []*leaf, depth int) int {
+	return sort.Search(len(leaves), func(i int) bool {
+		return stateproof.KeyBit(leaves[i].keyHash, depth) == 1
+	})
+}
+
+func sortByKeyHash(leaves []*leaf) {
+	sort.Slice(leaves, func(i, j int) bool {
+		return bytes.Compare(leaves[i].keyHash, leaves[j].keyHash) < 0
+	})
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
+	"github.com/hyperledger/fabric/core/ledger/internal/pvtdataencryption"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
@@ -107,7 +108,12 @@ func (p *DBProvider) GetDBHandle(id string, chInfoProvider channelInfoProvider)
 	if err != nil {
 		return nil, err
 	}
-	return NewDB(vdb, id, metadataHint)
+	db, err := NewDB(vdb, id, metadataHint)
+	if err != nil {
+		return nil, err
+	}
+	db.chInfoProvider = chInfoProvider
+	return db, nil
 }
 
 // Close closes all the VersionedDB instances and releases any resources held by VersionedDBProvider
@@ -123,13 +129,15 @@ func (p *DBProvider) Drop(ledgerid string) error {
 // DB uses a single database to maintain both the public and private data
 type DB struct {
 	statedb.VersionedDB
-	metadataHint *metadataHint
+	metadataHint   *metadataHint
+	chInfoProvider channelInfoProvider
+	encryption     *pvtdataencryption.Encryption
 }
 
 // NewDB wraps a VersionedDB instance. The public data is managed directly by the wrapped versionedDB.
 // For managing the hashed data and private data, this implementation creates separate namespaces in the wrapped db
 func NewDB(vdb statedb.VersionedDB, ledgerid string, metadataHint *metadataHint) (*DB, error) {
-	return &DB{vdb, metadataHint}, nil
+	return &DB{VersionedDB: vdb, metadataHint: metadataHint}, nil
 }
 
 // IsBulkOptimizable checks whether the underlying statedb implements statedb.BulkOptimizable
@@ -189,7 +197,11 @@ func (s *DB) GetChaincodeEventListener() cceventmgmt.ChaincodeLifecycleEventList
 
 // GetPrivateData gets the value of a private data item identified by a tuple <namespace, collection, key>
 func (s *DB) GetPrivateData(namespace, collection, key string) (*statedb.VersionedValue, error) {
-	return s.GetState(derivePvtDataNs(namespace, collection), key)
+	vv, err := s.GetState(derivePvtDataNs(namespace, collection), key)
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptPvtData(namespace, collection, key, vv)
 }
 
 // GetPrivateDataHash gets the hash of the value of a private data item identified by a tuple <namespace, collection, key>
@@ -231,18 +243,39 @@ func (s *DB) GetCachedKeyHashVersion(namespace, collection string, keyHash []byt
 
 // GetPrivateDataMultipleKeys gets the values for the multiple private data items in a single call
 func (s *DB) GetPrivateDataMultipleKeys(namespace, collection string, keys []string) ([]*statedb.VersionedValue, error) {
-	return s.GetStateMultipleKeys(derivePvtDataNs(namespace, collection), keys)
+	vvs, err := s.GetStateMultipleKeys(derivePvtDataNs(namespace, collection), keys)
+	if err != nil {
+		return nil, err
+	}
+	for i, vv := range vvs {
+		if vvs[i], err = s.decryptPvtData(namespace, collection, keys[i], vv); err != nil {
+			return nil, err
+		}
+	}
+	return vvs, nil
 }
 
 // GetPrivateDataRangeScanIterator returns an iterator that contains all the key-values between given key ranges.
 // startKey is included in the results and endKey is excluded.
 func (s *DB) GetPrivateDataRangeScanIterator(namespace, collection, startKey, endKey string) (statedb.ResultsIterator, error) {
-	return s.GetStateRangeScanIterator(derivePvtDataNs(namespace, collection), startKey, endKey)
+	itr, err := s.GetStateRangeScanIterator(derivePvtDataNs(namespace, collection), startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptingItr(namespace, collection, itr), nil
 }
 
 // ExecuteQueryOnPrivateData executes the given query and returns an iterator that contains results of type specific to the underlying data store.
+// The queries are not supported on the collections whose private data is encrypted, as the underlying data store cannot evaluate them.
 func (s DB) ExecuteQueryOnPrivateData(namespace, collection, query string) (statedb.ResultsIterator, error) {
-	return s.ExecuteQuery(derivePvtDataNs(namespace, collection), query)
+	if s.encryption.Enabled(namespace, collection) {
+		return nil, errors.Errorf("queries are not supported on collection [%s] of namespace [%s], whose private data is encrypted", collection, namespace)
+	}
+	itr, err := s.ExecuteQuery(derivePvtDataNs(namespace, collection), query)
+	if err != nil {
+		return nil, err
+	}
+	return s.decryptingItr(namespace, collection, itr), nil
 }
 
 // ApplyUpdates overrides the function in statedb.VersionedDB and throws appropriate error message
@@ -255,7 +288,9 @@ func (s *DB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) er
 func (s *DB) ApplyPrivacyAwareUpdates(updates *UpdateBatch, height *version.Height) error {
 	// combinedUpdates includes both updates to public db and private db, which are partitioned by a separate namespace
 	combinedUpdates := updates.PubUpdates
-	addPvtUpdates(combinedUpdates, updates.PvtUpdates)
+	if err := s.addPvtUpdates(combinedUpdates, updates.PvtUpdates); err != nil {
+		return err
+	}
 	addHashedUpdates(combinedUpdates, updates.HashUpdates, !s.BytesKeySupported())
 	if err := s.metadataHint.setMetadataUsedFlag(updates); err != nil {
 		return err
@@ -263,6 +298,13 @@ func (s *DB) ApplyPrivacyAwareUpdates(updates *UpdateBatch, height *version.Heig
 	return s.VersionedDB.ApplyUpdates(combinedUpdates.UpdateBatch, height)
 }
 
+// GetPubStateFullScanIterator returns an iterator over the entire public state, in the lexical order of <Namespace, key>
+func (s *DB) GetPubStateFullScanIterator() (statedb.FullScanIterator, error) {
+	return s.GetFullScanIterator(func(ns string) bool {
+		return isPvtdataNs(ns) || isHashedDataNs(ns)
+	})
+}
+
 // GetStateMetadata implements corresponding function in interface DB. This implementation provides
 // an optimization such that it keeps track if a namespaces has never stored metadata for any of
 // its items, the value 'nil' is returned without going to the db. This is intended to be invoked
@@ -372,14 +414,19 @@ func isHashedDataNs(namespace string) bool {
 	return strings.Contains(namespace, nsJoiner+hashDataPrefix)
 }
 
-func addPvtUpdates(pubUpdateBatch *PubUpdateBatch, pvtUpdateBatch *PvtUpdateBatch) {
+func (s *DB) addPvtUpdates(pubUpdateBatch *PubUpdateBatch, pvtUpdateBatch *PvtUpdateBatch) error {
 	for ns, nsBatch := range pvtUpdateBatch.UpdateMap {
 		for _, coll := range nsBatch.GetCollectionNames() {
 			for key, vv := range nsBatch.GetUpdates(coll) {
+				vv, err := s.encryptPvtData(ns, coll, key, vv)
+				if err != nil {
+					return err
+				}
 				pubUpdateBatch.Update(derivePvtDataNs(ns, coll), key, vv)
 			}
 		}
 	}
+	return nil
 }
 
 func addHashedUpdates(pubUpdateBatch *PubUpdateBatch, hashedUpdateBatch *HashedUpdateBatch, base64Key bool) {
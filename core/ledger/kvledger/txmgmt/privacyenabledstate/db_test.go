@@ -155,6 +155,36 @@ func testDB(t *testing.T, env TestEnv) {
 	require.Nil(t, vv)
 }
 
+func TestGetPubStateFullScanIterator(t *testing.T) {
+	for _, env := range testEnvs {
+		t.Run(env.GetName(), func(t *testing.T) {
+			env.Init(t)
+			defer env.Cleanup()
+			db := env.GetDBHandle(generateLedgerID(t))
+
+			updates := NewUpdateBatch()
+			updates.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+			updates.PubUpdates.Put("ns2", "key2", []byte("value2"), version.NewHeight(1, 2))
+			putPvtUpdates(t, updates, "ns1", "coll1", "key3", []byte("pvt_value3"), version.NewHeight(1, 3))
+			require.NoError(t, db.ApplyPrivacyAwareUpdates(updates, version.NewHeight(1, 3)))
+
+			itr, err := db.GetPubStateFullScanIterator()
+			require.NoError(t, err)
+			defer itr.Close()
+			var keys []string
+			for {
+				kv, err := itr.Next()
+				require.NoError(t, err)
+				if kv == nil {
+					break
+				}
+				keys = append(keys, kv.Namespace+"/"+kv.Key)
+			}
+			require.Equal(t, []string{"ns1/key1", "ns2/key2"}, keys)
+		})
+	}
+}
+
 func TestGetStateMultipleKeys(t *testing.T) {
 	for _, env := range testEnvs {
 		t.Run(env.GetName(), func(t *testing.T) {
@@ -0,0 +1,213 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privacyenabledstate
+
+import (
+	"sort"
+
+	"github.com/hyperledger/fabric/core/ledger/internal/pvtdataencryption"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/pkg/errors"
+)
+
+// encryptedValueMarker prefixes the metadata of an encrypted private data
+// value, whose value is then the envelope. The metadata is otherwise either
+// empty or a marshaled proto message, which cannot start with this byte, as it
+// is not a valid protobuf field tag. Marking the metadata rather than the value
+// keeps the values, which are arbitrary bytes, unambiguous.
+const encryptedValueMarker = byte(0x01)
+
+// SetEncryption sets the encryption of the private data of the collections. It
+// is expected to be invoked, if at all, before using the DB.
+func (s *DB) SetEncryption(e *pvtdataencryption.Encryption) {
+	s.encryption = e
+}
+
+func pvtDataAdditionalData(ns, coll, key string) []byte {
+	return []byte(derivePvtDataNs(ns, coll) + "\x00" + key)
+}
+
+func isEncryptedPvtData(vv *statedb.VersionedValue) bool {
+	return len(vv.Metadata) > 0 && vv.Metadata[0] == encryptedValueMarker
+}
+
+// encryptPvtData returns the value to store for the private data, which is
+// encrypted if the encryption of its collection is enabled.
+func (s *DB) encryptPvtData(ns, coll, key string, vv *statedb.VersionedValue) (*statedb.VersionedValue, error) {
+	if vv.IsDelete() || !s.encryption.Enabled(ns, coll) {
+		return vv, nil
+	}
+	envelope, err := s.encryption.Encrypt(ns, coll, vv.Value, pvtDataAdditionalData(ns, coll, key))
+	if err != nil {
+		return nil, err
+	}
+	return &statedb.VersionedValue{
+		Value:    envelope,
+		Metadata: append([]byte{encryptedValueMarker}, vv.Metadata...),
+		Version:  vv.Version,
+	}, nil
+}
+
+// decryptPvtData returns the private data as it was before being stored.
+func (s *DB) decryptPvtData(ns, coll, key string, vv *statedb.VersionedValue) (*statedb.VersionedValue, error) {
+	if vv == nil || !isEncryptedPvtData(vv) {
+		return vv, nil
+	}
+	value, err := s.encryption.Decrypt(ns, coll, vv.Value, pvtDataAdditionalData(ns, coll, key))
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		// a nil value denotes a delete
+		value = []byte{}
+	}
+	var metadata []byte
+	if len(vv.Metadata) > 1 {
+		metadata = vv.Metadata[1:]
+	}
+	return &statedb.VersionedValue{Value: value, Metadata: metadata, Version: vv.Version}, nil
+}
+
+// pvtdataDecryptingItr decrypts the private data returned by the iterator
+type pvtdataDecryptingItr struct {
+	statedb.ResultsIterator
+	db       *DB
+	ns, coll string
+}
+
+func (itr *pvtdataDecryptingItr) Next() (*statedb.VersionedKV, error) {
+	kv, err := itr.ResultsIterator.Next()
+	if err != nil || kv == nil {
+		return kv, err
+	}
+	vv, err := itr.db.decryptPvtData(itr.ns, itr.coll, kv.Key, kv.VersionedValue)
+	if err != nil {
+		return nil, err
+	}
+	return &statedb.VersionedKV{CompositeKey: kv.CompositeKey, VersionedValue: vv}, nil
+}
+
+// pvtdataDecryptingQueryItr retains the bookmark of a query results iterator
+type pvtdataDecryptingQueryItr struct {
+	*pvtdataDecryptingItr
+	queryItr statedb.QueryResultsIterator
+}
+
+func (itr *pvtdataDecryptingQueryItr) GetBookmarkAndClose() string {
+	return itr.queryItr.GetBookmarkAndClose()
+}
+
+func (s *DB) decryptingItr(ns, coll string, itr statedb.ResultsIterator) statedb.ResultsIterator {
+	decryptingItr := &pvtdataDecryptingItr{itr, s, ns, coll}
+	if queryItr, ok := itr.(statedb.QueryResultsIterator); ok {
+		return &pvtdataDecryptingQueryItr{decryptingItr, queryItr}
+	}
+	return decryptingItr
+}
+
+// ReencryptPvtData brings the private data in line with the encryption of their
+// collection, starting at the start key, or at the first private data if it is
+// nil. It encrypts the private data of the collections whose encryption is
+// enabled, re-encrypts those encrypted with a former data key, and decrypts the
+// others. At most maxEntries values are examined, and the key at which to
+// resume is returned, which is nil once all the values have been examined. The
+// caller is expected to prevent the concurrent commit of the same keys.
+func (s *DB) ReencryptPvtData(start *PvtdataCompositeKey, maxEntries int) (next *PvtdataCompositeKey, updated int, err error) {
+	if s.chInfoProvider == nil {
+		return nil, 0, errors.New("the collections of the channel are not known to the state database")
+	}
+	nsColls, err := s.chInfoProvider.NamespacesAndCollections(s.VersionedDB)
+	if err != nil {
+		return nil, 0, err
+	}
+	namespaces := make([]string, 0, len(nsColls))
+	for ns := range nsColls {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	batch := statedb.NewUpdateBatch()
+	examined := 0
+	defer func() {
+		if err == nil {
+			err = s.applyReencryptedPvtData(batch)
+		}
+	}()
+	for _, ns := range namespaces {
+		collections := append([]string{}, nsColls[ns]...)
+		sort.Strings(collections)
+		for _, coll := range collections {
+			startKey := ""
+			if start != nil {
+				if ns < start.Namespace || (ns == start.Namespace && coll < start.CollectionName) {
+					continue
+				}
+				if ns == start.Namespace && coll == start.CollectionName {
+					startKey = start.Key
+				}
+			}
+			n, u, nextKey, err := s.reencryptCollection(batch, ns, coll, startKey, maxEntries-examined)
+			if err != nil {
+				return nil, 0, err
+			}
+			examined += n
+			updated += u
+			if nextKey != nil {
+				return &PvtdataCompositeKey{Namespace: ns, CollectionName: coll, Key: *nextKey}, updated, nil
+			}
+		}
+	}
+	return nil, updated, nil
+}
+
+func (s *DB) reencryptCollection(batch *statedb.UpdateBatch, ns, coll, startKey string, maxEntries int) (examined, updated int, nextKey *string, err error) {
+	itr, err := s.VersionedDB.GetStateRangeScanIterator(derivePvtDataNs(ns, coll), startKey, "")
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	defer itr.Close()
+
+	for {
+		kv, err := itr.Next()
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		if kv == nil {
+			return examined, updated, nil, nil
+		}
+		if examined == maxEntries {
+			return examined, updated, &kv.Key, nil
+		}
+		examined++
+
+		encrypted := isEncryptedPvtData(kv.VersionedValue)
+		needsUpdate, err := s.encryption.NeedsUpdate(ns, coll, kv.Value, encrypted)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		if !needsUpdate {
+			continue
+		}
+		vv, err := s.decryptPvtData(ns, coll, kv.Key, kv.VersionedValue)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		if vv, err = s.encryptPvtData(ns, coll, kv.Key, vv); err != nil {
+			return 0, 0, nil, err
+		}
+		batch.PutValAndMetadata(derivePvtDataNs(ns, coll), kv.Key, vv.Value, vv.Metadata, vv.Version)
+		updated++
+	}
+}
+
+func (s *DB) applyReencryptedPvtData(batch *statedb.UpdateBatch) error {
+	if len(batch.GetUpdatedNamespaces()) == 0 {
+		return nil
+	}
+	// the savepoint is left untouched, as the private data are only re-encoded
+	return s.VersionedDB.ApplyUpdates(batch, nil)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privacyenabledstate
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/ledger/encryption"
+	"github.com/hyperledger/fabric/core/ledger/internal/pvtdataencryption"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	testmock "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate/mock"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/stretchr/testify/require"
+)
+
+type keyringStore map[string][]byte
+
+func (s keyringStore) Get(key []byte) ([]byte, error) {
+	return s[string(key)], nil
+}
+
+func (s keyringStore) Put(key []byte, value []byte, sync bool) error {
+	s[string(key)] = value
+	return nil
+}
+
+func TestPvtDataEncryption(t *testing.T) {
+	env := &LevelDBTestEnv{}
+	env.Init(t)
+	defer env.Cleanup()
+
+	chInfoProvider := &testmock.ChannelInfoProvider{}
+	chInfoProvider.NamespacesAndCollectionsReturns(map[string][]string{
+		"ns1": {"coll1", "coll2"},
+	}, nil)
+	db, err := env.GetProvider().GetDBHandle(generateLedgerID(t), chInfoProvider)
+	require.NoError(t, err)
+
+	csp, err := sw.NewDefaultSecurityLevel(t.TempDir())
+	require.NoError(t, err)
+	keyring, err := encryption.NewKeyring(csp, keyringStore{}, nil)
+	require.NoError(t, err)
+	db.SetEncryption(pvtdataencryption.New(keyring, true, []string{"ns1/coll1"}))
+
+	updates := NewUpdateBatch()
+	putPvtUpdates(t, updates, "ns1", "coll1", "key1", []byte("pvt_value1"), version.NewHeight(1, 1))
+	putPvtUpdates(t, updates, "ns1", "coll1", "key2", []byte("pvt_value2"), version.NewHeight(1, 2))
+	putPvtUpdates(t, updates, "ns1", "coll2", "key1", []byte("pvt_value3"), version.NewHeight(1, 3))
+	require.NoError(t, db.ApplyPrivacyAwareUpdates(updates, version.NewHeight(1, 3)))
+	// the updates of the caller are not encrypted in place
+	require.Equal(t, []byte("pvt_value1"), updates.PvtUpdates.Get("ns1", "coll1", "key1").Value)
+
+	requireEncrypted := func(coll, key string, expected bool) {
+		vv, err := db.VersionedDB.GetState(derivePvtDataNs("ns1", coll), key)
+		require.NoError(t, err)
+		require.Equal(t, expected, isEncryptedPvtData(vv))
+		require.Equal(t, !expected, strings.HasPrefix(string(vv.Value), "pvt_value"))
+	}
+	requireRetrievable := func() {
+		vv, err := db.GetPrivateData("ns1", "coll1", "key1")
+		require.NoError(t, err)
+		require.Equal(t, &statedb.VersionedValue{Value: []byte("pvt_value1"), Version: version.NewHeight(1, 1)}, vv)
+
+		vvs, err := db.GetPrivateDataMultipleKeys("ns1", "coll1", []string{"key1", "key2", "key3"})
+		require.NoError(t, err)
+		require.Equal(t, []*statedb.VersionedValue{
+			{Value: []byte("pvt_value1"), Version: version.NewHeight(1, 1)},
+			{Value: []byte("pvt_value2"), Version: version.NewHeight(1, 2)},
+			nil,
+		}, vvs)
+
+		itr, err := db.GetPrivateDataRangeScanIterator("ns1", "coll1", "", "")
+		require.NoError(t, err)
+		defer itr.Close()
+		var values []string
+		for {
+			kv, err := itr.Next()
+			require.NoError(t, err)
+			if kv == nil {
+				break
+			}
+			values = append(values, string(kv.Value))
+		}
+		require.Equal(t, []string{"pvt_value1", "pvt_value2"}, values)
+
+		vv, err = db.GetPrivateData("ns1", "coll2", "key1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("pvt_value3"), vv.Value)
+	}
+	requireEncrypted("coll1", "key1", true)
+	requireEncrypted("coll2", "key1", false)
+	requireRetrievable()
+
+	_, err = db.ExecuteQueryOnPrivateData("ns1", "coll1", `{"selector":{}}`)
+	require.EqualError(t, err, "queries are not supported on collection [coll1] of namespace [ns1], whose private data is encrypted")
+
+	reencryptAll := func() int {
+		var next *PvtdataCompositeKey
+		total := 0
+		for {
+			var updated int
+			next, updated, err = db.ReencryptPvtData(next, 1)
+			require.NoError(t, err)
+			total += updated
+			if next == nil {
+				return total
+			}
+		}
+	}
+	require.Equal(t, 0, reencryptAll())
+
+	// rotating the key and enabling the encryption of all the collections
+	// updates all the values
+	_, err = keyring.RotateKey()
+	require.NoError(t, err)
+	db.SetEncryption(pvtdataencryption.New(keyring, true, nil))
+	require.Equal(t, 3, reencryptAll())
+	requireEncrypted("coll1", "key1", true)
+	requireEncrypted("coll2", "key1", true)
+	requireRetrievable()
+	require.Equal(t, 0, reencryptAll())
+
+	// disabling the encryption decrypts the values, without moving the savepoint
+	db.SetEncryption(pvtdataencryption.New(keyring, false, nil))
+	require.Equal(t, 3, reencryptAll())
+	requireEncrypted("coll1", "key2", false)
+	requireEncrypted("coll2", "key1", false)
+	db.SetEncryption(nil)
+	requireRetrievable()
+	savepoint, err := db.GetLatestSavePoint()
+	require.NoError(t, err)
+	require.Equal(t, version.NewHeight(1, 3), savepoint)
+
+	t.Run("encryption not configured", func(t *testing.T) {
+		db.SetEncryption(pvtdataencryption.New(keyring, true, nil))
+		updates := NewUpdateBatch()
+		putPvtUpdates(t, updates, "ns1", "coll1", "key1", []byte("pvt_value1"), version.NewHeight(2, 1))
+		require.NoError(t, db.ApplyPrivacyAwareUpdates(updates, version.NewHeight(2, 1)))
+		db.SetEncryption(nil)
+		_, err := db.GetPrivateData("ns1", "coll1", "key1")
+		require.EqualError(t, err, "private data of collection [coll1] of namespace [ns1] is encrypted but the private data encryption is not configured")
+	})
+
+	t.Run("channel info not known", func(t *testing.T) {
+		db := env.GetDBHandle(generateLedgerID(t))
+		_, _, err := db.ReencryptPvtData(nil, 1)
+		require.EqualError(t, err, "the collections of the channel are not known to the state database")
+	})
+}
@@ -0,0 +1,52 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rwsetutil
+
+import "strings"
+
+// CrossChannelHeightKey is the key, in a cross-channel namespace, whose read
+// records the height of the ledger of the other channel at which the reads of
+// the namespace were performed. The height is carried in the block number of
+// the version of the read. Chaincodes cannot use the empty key, so it never
+// collides with a key of the chaincode.
+const CrossChannelHeightKey = ""
+
+// CrossChannelProofEntry is the name of the metadata entry which carries the
+// JSON encoded state proof of a key read on another channel. The entry is
+// recorded as a metadata write of the key in its cross-channel namespace, and
+// is never applied to the state.
+const CrossChannelProofEntry = "stateproof"
+
+// crossChannelSeparator separates the channel from the chaincode in a
+// cross-channel namespace. Neither a channel name nor a chaincode name can
+// contain it.
+const crossChannelSeparator = "/"
+
+// CrossChannelNamespace returns the namespace under which the reads of
+// chaincode namespace ns on the channel channelID are recorded in the
+// read-write set of a transaction of another channel.
+func CrossChannelNamespace(channelID, ns string) string {
+	return channelID + crossChannelSeparator + ns
+}
+
+// ParseCrossChannelNamespace splits a namespace returned by
+// CrossChannelNamespace into its channel and chaincode namespace. It returns
+// false if ns is not a cross-channel namespace.
+func ParseCrossChannelNamespace(ns string) (channelID, namespace string, ok bool) {
+	i := strings.Index(ns, crossChannelSeparator)
+	if i <= 0 || i == len(ns)-1 {
+		return "", "", false
+	}
+	return ns[:i], ns[i+1:], true
+}
+
+// IsCrossChannelNamespace returns true if ns records reads performed on
+// another channel.
+func IsCrossChannelNamespace(ns string) bool {
+	_, _, ok := ParseCrossChannelNamespace(ns)
+	return ok
+}
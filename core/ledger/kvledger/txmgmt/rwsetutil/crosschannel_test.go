@@ -0,0 +1,30 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rwsetutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrossChannelNamespace(t *testing.T) {
+	ns := CrossChannelNamespace("otherchannel", "mycc")
+	require.Equal(t, "otherchannel/mycc", ns)
+	require.True(t, IsCrossChannelNamespace(ns))
+
+	channelID, namespace, ok := ParseCrossChannelNamespace(ns)
+	require.True(t, ok)
+	require.Equal(t, "otherchannel", channelID)
+	require.Equal(t, "mycc", namespace)
+
+	for _, ns := range []string{"mycc", "/mycc", "otherchannel/", ""} {
+		_, _, ok := ParseCrossChannelNamespace(ns)
+		require.False(t, ok, ns)
+		require.False(t, IsCrossChannelNamespace(ns), ns)
+	}
+}
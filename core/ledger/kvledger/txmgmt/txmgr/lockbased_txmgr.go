@@ -19,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/statecommitment"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/pvtstatepurgemgmt"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/queryutil"
@@ -44,6 +45,7 @@ type LockBasedTxMgr struct {
 	oldBlockCommit      sync.Mutex
 	currentUpdates      *currentUpdates
 	hashFunc            rwsetutil.HashFunc
+	stateCommitment     *statecommitment.DB
 }
 
 // pvtdataPurgeMgr wraps the actual purge manager and an additional flag 'usedOnce'
@@ -89,6 +91,12 @@ type Initializer struct {
 	CCInfoProvider      ledger.DeployedChaincodeInfoProvider
 	CustomTxProcessors  map[common.HeaderType]ledger.CustomTxProcessor
 	HashFunc            rwsetutil.HashFunc
+	// ParallelValidationWorkers is the number of goroutines used to validate
+	// the transactions of a block, which are validated sequentially if it is below 2
+	ParallelValidationWorkers int
+	// StateCommitment, if not nil, is updated with the public updates of each
+	// block before they are applied to the state database
+	StateCommitment *statecommitment.DB
 }
 
 // NewLockBasedTxMgr constructs a new instance of NewLockBasedTxMgr
@@ -101,11 +109,12 @@ func NewLockBasedTxMgr(initializer *Initializer) (*LockBasedTxMgr, error) {
 		return nil, err
 	}
 	txmgr := &LockBasedTxMgr{
-		ledgerid:       initializer.LedgerID,
-		db:             initializer.DB,
-		stateListeners: initializer.StateListeners,
-		ccInfoProvider: initializer.CCInfoProvider,
-		hashFunc:       initializer.HashFunc,
+		ledgerid:        initializer.LedgerID,
+		db:              initializer.DB,
+		stateListeners:  initializer.StateListeners,
+		ccInfoProvider:  initializer.CCInfoProvider,
+		hashFunc:        initializer.HashFunc,
+		stateCommitment: initializer.StateCommitment,
 	}
 	pvtstatePurgeMgr, err := pvtstatepurgemgmt.InstantiatePurgeMgr(
 		initializer.LedgerID,
@@ -120,7 +129,8 @@ func NewLockBasedTxMgr(initializer *Initializer) (*LockBasedTxMgr, error) {
 		txmgr,
 		initializer.DB,
 		initializer.CustomTxProcessors,
-		initializer.HashFunc)
+		initializer.HashFunc,
+		initializer.ParallelValidationWorkers)
 	return txmgr, nil
 }
 
@@ -201,6 +211,17 @@ func (txmgr *LockBasedTxMgr) ValidateAndPrepare(blockAndPvtdata *ledger.BlockAnd
 	return appPurgeUpdates, txstatsInfo, updateBytes, err
 }
 
+// ReencryptPvtData brings at most maxEntries private data values of the state database,
+// starting at the given key, in line with the encryption of their collection, and returns
+// the key at which to resume. The lock on oldBlockCommit is held so that the values read
+// are not updated by a commit before they are written back.
+func (txmgr *LockBasedTxMgr) ReencryptPvtData(start *privacyenabledstate.PvtdataCompositeKey, maxEntries int) (*privacyenabledstate.PvtdataCompositeKey, int, error) {
+	txmgr.oldBlockCommit.Lock()
+	defer txmgr.oldBlockCommit.Unlock()
+	logger.Debug("lock acquired on oldBlockCommit for re-encrypting the private data in the state database")
+	return txmgr.db.ReencryptPvtData(start, maxEntries)
+}
+
 // RemoveStaleAndCommitPvtDataOfOldBlocks implements method in interface `txmgmt.TxMgr`
 // The following six operations are performed:
 // (1) constructs the unique pvt data from the passed reconciledPvtdata
@@ -542,6 +563,17 @@ func (txmgr *LockBasedTxMgr) Commit() error {
 		return err
 	}
 
+	// the state commitment is updated first, as the state database merges the
+	// private updates into the public updates when applying them. A crash
+	// before the state database is updated causes the block to be recommitted,
+	// which the state commitment skips
+	if txmgr.stateCommitment != nil {
+		if err := txmgr.stateCommitment.Commit(
+			txmgr.currentUpdates.blockNum(), txmgr.currentUpdates.batch.PubUpdates.UpdateBatch); err != nil {
+			return err
+		}
+	}
+
 	commitHeight := version.NewHeight(txmgr.currentUpdates.blockNum(), txmgr.currentUpdates.maxTxNumber())
 	txmgr.commitRWLock.Lock()
 	logger.Debugf("Write lock acquired for committing updates to state database")
@@ -7,8 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package txmgr
 
 import (
+	"encoding/json"
+
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statemetadata"
 	"github.com/hyperledger/fabric/core/ledger/util"
@@ -24,13 +27,14 @@ type txSimulator struct {
 	simulationResultsComputed bool
 	paginatedQueriesPerformed bool
 	writesetMetadata          ledger.WritesetMetadata
+	crossChannelHeights       map[string]uint64
 }
 
 func newTxSimulator(txmgr *LockBasedTxMgr, txid string, hashFunc rwsetutil.HashFunc) (*txSimulator, error) {
 	rwsetBuilder := rwsetutil.NewRWSetBuilder()
 	qe := newQueryExecutor(txmgr, txid, rwsetBuilder, true, hashFunc)
 	logger.Debugf("constructing new tx simulator txid = [%s]", txid)
-	return &txSimulator{qe, rwsetBuilder, false, false, false, false, ledger.WritesetMetadata{}}, nil
+	return &txSimulator{qe, rwsetBuilder, false, false, false, false, ledger.WritesetMetadata{}, nil}, nil
 }
 
 // SetState implements method in interface `ledger.TxSimulator`
@@ -212,6 +216,81 @@ func (s *txSimulator) GetTxSimulationResults() (*ledger.TxSimulationResults, err
 	return simResults, nil
 }
 
+// AddCrossChannelReads implements method in interface `ledger.TxSimulator`
+func (s *txSimulator) AddCrossChannelReads(channelID string, sim ledger.TxSimulator, prover ledger.StateProver) error {
+	if err := s.checkDone(); err != nil {
+		return err
+	}
+	other, ok := sim.(*txSimulator)
+	if !ok {
+		return errors.Errorf("txid [%s]: unsupported simulator of type %T for the reads of channel [%s]", s.txid, sim, channelID)
+	}
+	savepoint, err := other.txmgr.GetLastSavepoint()
+	if err != nil {
+		return err
+	}
+	if savepoint == nil {
+		return errors.Errorf("txid [%s]: ledger of channel [%s] is empty", s.txid, channelID)
+	}
+	height := savepoint.BlockNum + 1
+	if recorded, ok := s.crossChannelHeights[channelID]; ok && recorded != height {
+		return errors.Errorf("txid [%s]: reads of channel [%s] were performed at heights %d and %d", s.txid, channelID, recorded, height)
+	}
+
+	simResults, err := other.GetTxSimulationResults()
+	if err != nil {
+		return err
+	}
+	if simResults.PvtSimulationResults != nil || len(simResults.PrivateReads) > 0 {
+		return errors.Errorf("txid [%s]: operations on private data are not supported on channel [%s]", s.txid, channelID)
+	}
+	txRWSet, err := rwsetutil.TxRwSetFromProtoMsg(simResults.PubSimulationResults)
+	if err != nil {
+		return err
+	}
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		switch {
+		case len(nsRWSet.KvRwSet.Writes) > 0 || len(nsRWSet.KvRwSet.MetadataWrites) > 0:
+			return errors.Errorf("txid [%s]: writes are not supported on channel [%s]", s.txid, channelID)
+		case len(nsRWSet.KvRwSet.RangeQueriesInfo) > 0:
+			return errors.Errorf("txid [%s]: range queries are not supported on channel [%s]", s.txid, channelID)
+		case len(nsRWSet.CollHashedRwSets) > 0:
+			return errors.Errorf("txid [%s]: operations on private data are not supported on channel [%s]", s.txid, channelID)
+		case rwsetutil.IsCrossChannelNamespace(nsRWSet.NameSpace):
+			return errors.Errorf("txid [%s]: nested reads of other channels are not supported on channel [%s]", s.txid, channelID)
+		}
+	}
+
+	// the simulation holds back the commit of blocks to the other ledger,
+	// which getting the proofs may wait for, hence it is ended first
+	sim.Done()
+
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		ns := rwsetutil.CrossChannelNamespace(channelID, nsRWSet.NameSpace)
+		for _, read := range nsRWSet.KvRwSet.Reads {
+			proof, err := prover.GetStateProof(nsRWSet.NameSpace, read.Key, height-1)
+			if err != nil {
+				return errors.WithMessagef(err, "txid [%s]: failed to get the proof of key [%s] of namespace [%s] on channel [%s]", s.txid, read.Key, nsRWSet.NameSpace, channelID)
+			}
+			if (read.Version == nil) != (len(proof.Value) == 0) {
+				return errors.Errorf("txid [%s]: proof of key [%s] of namespace [%s] on channel [%s] does not match its read", s.txid, read.Key, nsRWSet.NameSpace, channelID)
+			}
+			proofBytes, err := json.Marshal(proof)
+			if err != nil {
+				return err
+			}
+			s.rwsetBuilder.AddToReadSet(ns, read.Key, rwsetutil.NewVersion(read.Version))
+			s.rwsetBuilder.AddToMetadataWriteSet(ns, read.Key, map[string][]byte{rwsetutil.CrossChannelProofEntry: proofBytes})
+		}
+		s.rwsetBuilder.AddToReadSet(ns, rwsetutil.CrossChannelHeightKey, version.NewHeight(height, 0))
+	}
+	if s.crossChannelHeights == nil {
+		s.crossChannelHeights = map[string]uint64{}
+	}
+	s.crossChannelHeights[channelID] = height
+	return nil
+}
+
 // ExecuteUpdate implements method in interface `ledger.TxSimulator`
 func (s *txSimulator) ExecuteUpdate(query string) error {
 	return errors.New("not supported")
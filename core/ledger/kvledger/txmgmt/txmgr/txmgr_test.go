@@ -25,8 +25,11 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/mock"
 	btltestutil "github.com/hyperledger/fabric/core/ledger/pvtdatapolicy/testutil"
+	"github.com/hyperledger/fabric/core/ledger/stateproof"
 	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -888,6 +891,160 @@ func TestTxSimulatorUnsupportedTx(t *testing.T) {
 	require.EqualError(t, err, "txid [txid4]: unsuppored transaction. Transaction has already performed a paginated query. Writes are not allowed")
 }
 
+func TestTxSimulatorCrossChannelReads(t *testing.T) {
+	testEnv := testEnvsMap[levelDBtestEnvName]
+	testEnv.init(t, "testtxsimulatorcrosschannelreads", nil)
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+
+	otherEnv := &lockBasedEnv{name: levelDBtestEnvName, testDBEnv: &privacyenabledstate.LevelDBTestEnv{}}
+	otherEnv.init(t, "otherchannel", nil)
+	defer otherEnv.cleanup()
+	otherTxMgr := otherEnv.getTxMgr()
+	otherTxMgrHelper := newTxMgrTestHelper(t, otherTxMgr)
+
+	s, _ := otherTxMgr.NewTxSimulator("test_tx1")
+	require.NoError(t, s.SetState("ns1", "key1", []byte("value1")))
+	s.Done()
+	txRWSet, _ := s.GetTxSimulationResults()
+	otherTxMgrHelper.validateAndCommitRWSet(txRWSet.PubSimulationResults)
+
+	prover := &mock.StateProver{}
+	prover.GetStateProofStub = func(namespace, key string, blockNum uint64) (*stateproof.Proof, error) {
+		proof := &stateproof.Proof{BlockNum: blockNum, Namespace: namespace, Key: key, Root: []byte("root")}
+		if key == "key1" {
+			proof.Value = []byte("value1")
+		}
+		return proof, nil
+	}
+
+	t.Run("reads are recorded with the height and their proofs", func(t *testing.T) {
+		simulator, _ := txMgr.NewTxSimulator("txid1")
+		_, err := simulator.GetState("ns1", "key1")
+		require.NoError(t, err)
+
+		otherSimulator, _ := otherTxMgr.NewTxSimulator("txid1")
+		value, err := otherSimulator.GetState("ns1", "key1")
+		require.NoError(t, err)
+		require.Equal(t, []byte("value1"), value)
+		_, err = otherSimulator.GetState("ns1", "missing")
+		require.NoError(t, err)
+		require.NoError(t, simulator.AddCrossChannelReads("otherchannel", otherSimulator, prover))
+		otherSimulator.Done()
+		simulator.Done()
+
+		simResults, err := simulator.GetTxSimulationResults()
+		require.NoError(t, err)
+		txRWSet, err := rwsetutil.TxRwSetFromProtoMsg(simResults.PubSimulationResults)
+		require.NoError(t, err)
+		require.Len(t, txRWSet.NsRwSets, 2)
+		require.Equal(t, "ns1", txRWSet.NsRwSets[0].NameSpace)
+		require.Equal(t, "otherchannel/ns1", txRWSet.NsRwSets[1].NameSpace)
+		proofEntry := func(key string) []*kvrwset.KVMetadataEntry {
+			proof, err := prover.GetStateProof("ns1", key, 1)
+			require.NoError(t, err)
+			proofBytes, err := json.Marshal(proof)
+			require.NoError(t, err)
+			return []*kvrwset.KVMetadataEntry{{Name: rwsetutil.CrossChannelProofEntry, Value: proofBytes}}
+		}
+		require.True(t, proto.Equal(
+			&kvrwset.KVRWSet{
+				Reads: []*kvrwset.KVRead{
+					rwsetutil.NewKVRead(rwsetutil.CrossChannelHeightKey, version.NewHeight(2, 0)),
+					rwsetutil.NewKVRead("key1", version.NewHeight(1, 0)),
+					rwsetutil.NewKVRead("missing", nil),
+				},
+				MetadataWrites: []*kvrwset.KVMetadataWrite{
+					{Key: "key1", Entries: proofEntry("key1")},
+					{Key: "missing", Entries: proofEntry("missing")},
+				},
+			},
+			txRWSet.NsRwSets[1].KvRwSet,
+		))
+	})
+
+	t.Run("proof cannot be obtained", func(t *testing.T) {
+		simulator, _ := txMgr.NewTxSimulator("txid6")
+		defer simulator.Done()
+		otherSimulator, _ := otherTxMgr.NewTxSimulator("txid6")
+		defer otherSimulator.Done()
+		_, err := otherSimulator.GetState("ns1", "key1")
+		require.NoError(t, err)
+
+		failingProver := &mock.StateProver{}
+		failingProver.GetStateProofReturns(nil, errors.New("state commitment is not enabled"))
+		err = simulator.AddCrossChannelReads("otherchannel", otherSimulator, failingProver)
+		require.EqualError(t, err, "txid [txid6]: failed to get the proof of key [key1] of namespace [ns1] on channel [otherchannel]: state commitment is not enabled")
+	})
+
+	t.Run("proof does not match the read", func(t *testing.T) {
+		simulator, _ := txMgr.NewTxSimulator("txid7")
+		defer simulator.Done()
+		otherSimulator, _ := otherTxMgr.NewTxSimulator("txid7")
+		defer otherSimulator.Done()
+		_, err := otherSimulator.GetState("ns1", "key1")
+		require.NoError(t, err)
+
+		absenceProver := &mock.StateProver{}
+		absenceProver.GetStateProofReturns(&stateproof.Proof{}, nil)
+		err = simulator.AddCrossChannelReads("otherchannel", otherSimulator, absenceProver)
+		require.EqualError(t, err, "txid [txid7]: proof of key [key1] of namespace [ns1] on channel [otherchannel] does not match its read")
+	})
+
+	t.Run("reads at different heights", func(t *testing.T) {
+		simulator, _ := txMgr.NewTxSimulator("txid2")
+		defer simulator.Done()
+
+		otherSimulator, _ := otherTxMgr.NewTxSimulator("txid2")
+		_, err := otherSimulator.GetState("ns1", "key1")
+		require.NoError(t, err)
+		require.NoError(t, simulator.AddCrossChannelReads("otherchannel", otherSimulator, prover))
+		otherSimulator.Done()
+
+		s, _ := otherTxMgr.NewTxSimulator("test_tx2")
+		require.NoError(t, s.SetState("ns1", "key2", []byte("value2")))
+		s.Done()
+		txRWSet, _ := s.GetTxSimulationResults()
+		otherTxMgrHelper.validateAndCommitRWSet(txRWSet.PubSimulationResults)
+
+		otherSimulator, _ = otherTxMgr.NewTxSimulator("txid2")
+		defer otherSimulator.Done()
+		_, err = otherSimulator.GetState("ns1", "key2")
+		require.NoError(t, err)
+		err = simulator.AddCrossChannelReads("otherchannel", otherSimulator, prover)
+		require.EqualError(t, err, "txid [txid2]: reads of channel [otherchannel] were performed at heights 2 and 3")
+	})
+
+	t.Run("writes", func(t *testing.T) {
+		simulator, _ := txMgr.NewTxSimulator("txid3")
+		defer simulator.Done()
+		otherSimulator, _ := otherTxMgr.NewTxSimulator("txid3")
+		defer otherSimulator.Done()
+		require.NoError(t, otherSimulator.SetState("ns1", "key1", []byte("value")))
+		err := simulator.AddCrossChannelReads("otherchannel", otherSimulator, prover)
+		require.EqualError(t, err, "txid [txid3]: writes are not supported on channel [otherchannel]")
+	})
+
+	t.Run("range queries", func(t *testing.T) {
+		simulator, _ := txMgr.NewTxSimulator("txid4")
+		defer simulator.Done()
+		otherSimulator, _ := otherTxMgr.NewTxSimulator("txid4")
+		defer otherSimulator.Done()
+		itr, err := otherSimulator.GetStateRangeScanIterator("ns1", "", "")
+		require.NoError(t, err)
+		itr.Close()
+		err = simulator.AddCrossChannelReads("otherchannel", otherSimulator, prover)
+		require.EqualError(t, err, "txid [txid4]: range queries are not supported on channel [otherchannel]")
+	})
+
+	t.Run("unsupported simulator", func(t *testing.T) {
+		simulator, _ := txMgr.NewTxSimulator("txid5")
+		defer simulator.Done()
+		err := simulator.AddCrossChannelReads("otherchannel", &mock.TxSimulator{}, prover)
+		require.EqualError(t, err, "txid [txid5]: unsupported simulator of type *mock.TxSimulator for the reads of channel [otherchannel]")
+	})
+}
+
 func TestTxSimulatorUnsupportedTxCouchDBQuery(t *testing.T) {
 	testEnv := testEnvsMap[couchDBtestEnvName]
 	testEnv.init(t, "testtxsimulatorunsupportedtxqueries", nil)
@@ -50,19 +50,22 @@ type TxStatInfo struct {
 }
 
 // NewCommitBatchPreparer constructs a validator that internally manages statebased validator and in addition
-// handles the tasks that are agnostic to a particular validation scheme such as parsing the block and handling the pvt data
+// handles the tasks that are agnostic to a particular validation scheme such as parsing the block and handling the pvt data.
+// The transactions of a block are validated by parallelWorkers goroutines, or sequentially if parallelWorkers is below 2.
 func NewCommitBatchPreparer(
 	postOrderSimulatorProvider PostOrderSimulatorProvider,
 	db *privacyenabledstate.DB,
 	customTxProcessors map[common.HeaderType]ledger.CustomTxProcessor,
 	hashFunc rwsetutil.HashFunc,
+	parallelWorkers int,
 ) *CommitBatchPreparer {
 	return &CommitBatchPreparer{
 		postOrderSimulatorProvider,
 		db,
 		&validator{
-			db:       db,
-			hashFunc: hashFunc,
+			db:              db,
+			hashFunc:        hashFunc,
+			parallelWorkers: parallelWorkers,
 		},
 		customTxProcessors,
 	}
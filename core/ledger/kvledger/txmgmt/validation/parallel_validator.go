@@ -0,0 +1,314 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validation
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+)
+
+// conflictGraph captures, for the transactions of a block, which transactions
+// may be affected by the writes of a preceding transaction in the same block.
+// A transaction is dependent if a preceding transaction writes a key that it
+// reads or a key that falls in one of its range queries. The graph is an over
+// approximation, as it ignores the validity of the writers: a transaction which
+// is not dependent has the same validation result against the committed state
+// alone as against the committed state and the writes of the preceding valid
+// transactions.
+type conflictGraph struct {
+	// dependent[i] is true if the validation of the i-th transaction needs
+	// the writes of preceding transactions
+	dependent []bool
+	// dependedUpon[i] is true if the writes of the i-th transaction are
+	// needed for the validation of a subsequent transaction
+	dependedUpon []bool
+}
+
+// hashedKey identifies a key in the hashed space of a collection
+type hashedKey struct {
+	ns, coll, keyHash string
+}
+
+// writeIndex records the transactions which have written each key
+type writeIndex struct {
+	pubWriters    map[compositeKey][]int
+	hashedWriters map[hashedKey][]int
+	// sortedKeys holds, for each namespace, the written public keys in order
+	sortedKeys map[string][]string
+}
+
+func newWriteIndex() *writeIndex {
+	return &writeIndex{
+		pubWriters:    map[compositeKey][]int{},
+		hashedWriters: map[hashedKey][]int{},
+		sortedKeys:    map[string][]string{},
+	}
+}
+
+func (w *writeIndex) addPubWrite(ns, key string, txIndex int) {
+	ck := compositeKey{ns: ns, key: key}
+	writers, ok := w.pubWriters[ck]
+	if !ok {
+		keys := w.sortedKeys[ns]
+		i := sort.SearchStrings(keys, key)
+		keys = append(keys, "")
+		copy(keys[i+1:], keys[i:])
+		keys[i] = key
+		w.sortedKeys[ns] = keys
+	}
+	if len(writers) == 0 || writers[len(writers)-1] != txIndex {
+		w.pubWriters[ck] = append(writers, txIndex)
+	}
+}
+
+func (w *writeIndex) addHashedWrite(ns, coll string, keyHash []byte, txIndex int) {
+	hk := hashedKey{ns: ns, coll: coll, keyHash: string(keyHash)}
+	writers := w.hashedWriters[hk]
+	if len(writers) == 0 || writers[len(writers)-1] != txIndex {
+		w.hashedWriters[hk] = append(writers, txIndex)
+	}
+}
+
+// rangeWriters returns the transactions which have written a key of the
+// namespace in the range [startKey, endKey]. An empty endKey denotes the end
+// of the namespace.
+func (w *writeIndex) rangeWriters(ns, startKey, endKey string) []int {
+	keys := w.sortedKeys[ns]
+	var writers []int
+	for i := sort.SearchStrings(keys, startKey); i < len(keys); i++ {
+		if endKey != "" && keys[i] > endKey {
+			break
+		}
+		writers = append(writers, w.pubWriters[compositeKey{ns: ns, key: keys[i]}]...)
+	}
+	return writers
+}
+
+func (w *writeIndex) addWrites(txRWSet *rwsetutil.TxRwSet, txIndex int) {
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		ns := nsRWSet.NameSpace
+		if rwsetutil.IsCrossChannelNamespace(ns) {
+			continue
+		}
+		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+			w.addPubWrite(ns, kvWrite.Key, txIndex)
+		}
+		for _, kvMetadataWrite := range nsRWSet.KvRwSet.MetadataWrites {
+			w.addPubWrite(ns, kvMetadataWrite.Key, txIndex)
+		}
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			coll := collHashedRWSet.CollectionName
+			for _, hashedWrite := range collHashedRWSet.HashedRwSet.HashedWrites {
+				w.addHashedWrite(ns, coll, hashedWrite.KeyHash, txIndex)
+			}
+			for _, metadataWrite := range collHashedRWSet.HashedRwSet.MetadataWrites {
+				w.addHashedWrite(ns, coll, metadataWrite.KeyHash, txIndex)
+			}
+		}
+	}
+}
+
+// writersOf returns the preceding transactions whose writes intersect the
+// reads and the range queries of the transaction
+func (w *writeIndex) writersOf(txRWSet *rwsetutil.TxRwSet) []int {
+	var writers []int
+	for _, nsRWSet := range txRWSet.NsRwSets {
+		ns := nsRWSet.NameSpace
+		for _, kvRead := range nsRWSet.KvRwSet.Reads {
+			writers = append(writers, w.pubWriters[compositeKey{ns: ns, key: kvRead.Key}]...)
+		}
+		for _, rqi := range nsRWSet.KvRwSet.RangeQueriesInfo {
+			writers = append(writers, w.rangeWriters(ns, rqi.StartKey, rqi.EndKey)...)
+		}
+		for _, collHashedRWSet := range nsRWSet.CollHashedRwSets {
+			coll := collHashedRWSet.CollectionName
+			for _, hashedRead := range collHashedRWSet.HashedRwSet.HashedReads {
+				writers = append(writers, w.hashedWriters[hashedKey{ns: ns, coll: coll, keyHash: string(hashedRead.KeyHash)}]...)
+			}
+		}
+	}
+	return writers
+}
+
+// buildConflictGraph builds the conflict graph of the transactions of a block
+func buildConflictGraph(blk *block) *conflictGraph {
+	g := &conflictGraph{
+		dependent:    make([]bool, len(blk.txs)),
+		dependedUpon: make([]bool, len(blk.txs)),
+	}
+	writes := newWriteIndex()
+	for i, tx := range blk.txs {
+		for _, writer := range writes.writersOf(tx.rwset) {
+			g.dependent[i] = true
+			g.dependedUpon[writer] = true
+		}
+		writes.addWrites(tx.rwset, i)
+	}
+	return g
+}
+
+// validateAndPrepareBatchInParallel produces the same results as the sequential
+// validation of the block. The transactions which do not depend on preceding
+// transactions are validated in parallel against the committed state; the
+// dependent transactions are then validated in block order against the writes
+// of the preceding valid transactions they may depend on. Finally, the writes
+// of the valid transactions are applied in parallel per namespace.
+func (v *validator) validateAndPrepareBatchInParallel(blk *block, doMVCCValidation bool) (*publicAndHashUpdates, []*AppInitiatedPurgeUpdate, error) {
+	if doMVCCValidation {
+		if err := v.validateTxsInParallel(blk); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		for _, tx := range blk.txs {
+			tx.validationCode = peer.TxValidationCode_VALID
+		}
+	}
+
+	purgeTracker := newPvtdataPurgeTracker()
+	var validTxs []*transaction
+	for _, tx := range blk.txs {
+		if tx.validationCode == peer.TxValidationCode_VALID {
+			logger.Debugf("Block [%d] Transaction index [%d] TxId [%s] marked as valid by state validator. ContainsPostOrderWrites [%t]", blk.num, tx.indexInBlock, tx.id, tx.containsPostOrderWrites)
+			validTxs = append(validTxs, tx)
+			purgeTracker.update(tx.rwset, version.NewHeight(blk.num, uint64(tx.indexInBlock)))
+		} else {
+			logger.Warningf("Block [%d] Transaction index [%d] TxId [%s] marked as invalid by state validator. Reason code [%s]",
+				blk.num, tx.indexInBlock, tx.id, tx.validationCode.String())
+		}
+	}
+
+	updates, err := v.applyWriteSetsInParallel(blk.num, validTxs)
+	if err != nil {
+		return nil, nil, err
+	}
+	return updates, purgeTracker.getUpdates(), nil
+}
+
+// validateTxsInParallel sets the validation code of the transactions of the block
+func (v *validator) validateTxsInParallel(blk *block) error {
+	graph := buildConflictGraph(blk)
+
+	// the independent transactions only see the committed state, hence they
+	// can all be validated against the same empty batch of preceding updates
+	noUpdates := newPubAndHashUpdates()
+	codes := make([]peer.TxValidationCode, len(blk.txs))
+	errs := make([]error, len(blk.txs))
+	v.forEachInParallel(len(blk.txs), func(i int) {
+		if !graph.dependent[i] {
+			codes[i], errs[i] = v.validateTx(blk.txs[i].rwset, noUpdates)
+		}
+	})
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	// the dependent transactions are validated in block order against the
+	// writes of the preceding valid transactions which are depended upon
+	updates := newPubAndHashUpdates()
+	for i, tx := range blk.txs {
+		if graph.dependent[i] {
+			code, err := v.validateTx(tx.rwset, updates)
+			if err != nil {
+				return err
+			}
+			codes[i] = code
+		}
+		tx.validationCode = codes[i]
+		if codes[i] == peer.TxValidationCode_VALID && graph.dependedUpon[i] {
+			committingTxHeight := version.NewHeight(blk.num, uint64(tx.indexInBlock))
+			if err := updates.applyWriteSet(tx.rwset, committingTxHeight, v.db, tx.containsPostOrderWrites); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// nsWrites holds the writes of a transaction to a namespace
+type nsWrites struct {
+	txHeight *version.Height
+	rwset    *rwsetutil.TxRwSet
+}
+
+// applyWriteSetsInParallel applies the writes of the valid transactions, in
+// block order, to a batch per namespace and merges the batches.
+func (v *validator) applyWriteSetsInParallel(blockNum uint64, validTxs []*transaction) (*publicAndHashUpdates, error) {
+	updates := newPubAndHashUpdates()
+	var namespaces []string
+	writesByNs := map[string][]*nsWrites{}
+	for _, tx := range validTxs {
+		updates.publicUpdates.ContainsPostOrderWrites =
+			updates.publicUpdates.ContainsPostOrderWrites || tx.containsPostOrderWrites
+		txHeight := version.NewHeight(blockNum, uint64(tx.indexInBlock))
+		for _, nsRWSet := range tx.rwset.NsRwSets {
+			ns := nsRWSet.NameSpace
+			if _, ok := writesByNs[ns]; !ok {
+				namespaces = append(namespaces, ns)
+			}
+			writesByNs[ns] = append(writesByNs[ns], &nsWrites{
+				txHeight: txHeight,
+				rwset:    &rwsetutil.TxRwSet{NsRwSets: []*rwsetutil.NsRwSet{nsRWSet}},
+			})
+		}
+	}
+
+	nsUpdates := make([]*publicAndHashUpdates, len(namespaces))
+	errs := make([]error, len(namespaces))
+	v.forEachInParallel(len(namespaces), func(i int) {
+		nsUpdates[i] = newPubAndHashUpdates()
+		for _, w := range writesByNs[namespaces[i]] {
+			if errs[i] = nsUpdates[i].applyWriteSet(w.rwset, w.txHeight, v.db, false); errs[i] != nil {
+				return
+			}
+		}
+	})
+
+	for i, ns := range namespaces {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		if u, ok := nsUpdates[i].publicUpdates.Updates[ns]; ok {
+			updates.publicUpdates.Updates[ns] = u
+		}
+		if u, ok := nsUpdates[i].hashUpdates.UpdateMap[ns]; ok {
+			updates.hashUpdates.UpdateMap[ns] = u
+		}
+	}
+	return updates, nil
+}
+
+// forEachInParallel calls f for each index in [0, n) using at most
+// v.parallelWorkers goroutines
+func (v *validator) forEachInParallel(n int, f func(i int)) {
+	workers := v.parallelWorkers
+	if workers > n {
+		workers = n
+	}
+	indexes := make(chan int, n)
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				f(i)
+			}
+		}()
+	}
+	wg.Wait()
+}
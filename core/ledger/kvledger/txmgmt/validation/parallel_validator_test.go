@@ -0,0 +1,192 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package validation
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/core/ledger/internal/version"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConflictGraph(t *testing.T) {
+	rwsetBuilder1 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder1.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+	rwsetBuilder1.AddToWriteSet("ns1", "key1", []byte("value1"))
+
+	// reads a key written by tx 0
+	rwsetBuilder2 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder2.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
+
+	// reads the same key in another namespace
+	rwsetBuilder3 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder3.AddToReadSet("ns2", "key1", version.NewHeight(1, 0))
+	rwsetBuilder3.AddToPvtAndHashedWriteSet("ns2", "coll1", "key2", []byte("value2"))
+
+	// range query covering a key written by tx 0
+	rwsetBuilder4 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder4.AddToRangeQuerySet("ns1", &kvrwset.RangeQueryInfo{StartKey: "key0", EndKey: "key2", ItrExhausted: true})
+
+	// range query not covering any written key
+	rwsetBuilder5 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder5.AddToRangeQuerySet("ns1", &kvrwset.RangeQueryInfo{StartKey: "key2", EndKey: "", ItrExhausted: true})
+
+	// reads a hashed key written by tx 2
+	rwsetBuilder6 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder6.AddToHashedReadSet("ns2", "coll1", "key2", nil)
+
+	transRWSets := getTestPubSimulationRWSet(t, rwsetBuilder1, rwsetBuilder2, rwsetBuilder3, rwsetBuilder4, rwsetBuilder5, rwsetBuilder6)
+	var txs []*transaction
+	for i, txRWSet := range transRWSets {
+		txs = append(txs, &transaction{indexInBlock: i, rwset: txRWSet})
+	}
+
+	graph := buildConflictGraph(&block{num: 2, txs: txs})
+	require.Equal(t, []bool{false, true, false, true, false, true}, graph.dependent)
+	require.Equal(t, []bool{true, false, true, false, false, false}, graph.dependedUpon)
+}
+
+func TestParallelValidationDeterminism(t *testing.T) {
+	testDBEnv := testEnvs[levelDBtestEnvName]
+	testDBEnv.Init(t)
+	defer testDBEnv.Cleanup()
+	db := testDBEnv.GetDBHandle("TestDB")
+
+	const (
+		numNamespaces = 2
+		numKeys       = 20
+		numPvtKeys    = 10
+	)
+	pubKey := func(i int) string { return fmt.Sprintf("key%02d", i) }
+	pvtKey := func(i int) string { return fmt.Sprintf("pvtkey%02d", i) }
+	namespace := func(i int) string { return fmt.Sprintf("ns%d", i) }
+
+	// populate db with initial data
+	committed := map[string]*version.Height{}
+	batch := privacyenabledstate.NewUpdateBatch()
+	for n := 0; n < numNamespaces; n++ {
+		for k := 0; k < numKeys; k++ {
+			ver := version.NewHeight(1, uint64(n*numKeys+k))
+			batch.PubUpdates.Put(namespace(n), pubKey(k), []byte("value"), ver)
+			committed[namespace(n)+pubKey(k)] = ver
+		}
+		for k := 0; k < numPvtKeys; k++ {
+			ver := version.NewHeight(1, uint64(n*numPvtKeys+k))
+			batch.HashUpdates.Put(namespace(n), "coll1", []byte(pvtKey(k)), []byte("value-hash"), ver)
+		}
+	}
+	require.NoError(t, db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 100)))
+
+	staleVersion := version.NewHeight(0, 1)
+	randomRWSet := func(rnd *rand.Rand) *rwsetutil.RWSetBuilder {
+		b := rwsetutil.NewRWSetBuilder()
+		for i := rnd.Intn(4); i > 0; i-- {
+			ns, key := namespace(rnd.Intn(numNamespaces)), pubKey(rnd.Intn(numKeys+5))
+			ver := committed[ns+key]
+			if rnd.Intn(5) == 0 {
+				ver = staleVersion
+			}
+			b.AddToReadSet(ns, key, ver)
+		}
+		if rnd.Intn(3) == 0 {
+			ns := namespace(rnd.Intn(numNamespaces))
+			start := rnd.Intn(numKeys)
+			end := start + 1 + rnd.Intn(5)
+			var reads []*kvrwset.KVRead
+			for k := start; k < end && k < numKeys; k++ {
+				if rnd.Intn(10) != 0 {
+					reads = append(reads, rwsetutil.NewKVRead(pubKey(k), committed[ns+pubKey(k)]))
+				}
+			}
+			rqi := &kvrwset.RangeQueryInfo{StartKey: pubKey(start), EndKey: pubKey(end), ItrExhausted: true}
+			rwsetutil.SetRawReads(rqi, reads)
+			b.AddToRangeQuerySet(ns, rqi)
+		}
+		for i := rnd.Intn(3); i > 0; i-- {
+			n, k := rnd.Intn(numNamespaces), rnd.Intn(numPvtKeys+3)
+			ver := version.NewHeight(1, uint64(n*numPvtKeys+k))
+			if k >= numPvtKeys {
+				ver = nil
+			}
+			if rnd.Intn(5) == 0 {
+				ver = staleVersion
+			}
+			b.AddToHashedReadSet(namespace(n), "coll1", pvtKey(k), ver)
+		}
+		for i := rnd.Intn(3); i > 0; i-- {
+			ns, key := namespace(rnd.Intn(numNamespaces)), pubKey(rnd.Intn(numKeys+5))
+			if rnd.Intn(5) == 0 {
+				b.AddToWriteSet(ns, key, nil)
+			} else {
+				b.AddToWriteSet(ns, key, []byte(fmt.Sprintf("value-%d", rnd.Int())))
+			}
+		}
+		// a metadata write is preceded by a read of the key, as during simulation
+		if rnd.Intn(6) == 0 {
+			ns, key := namespace(rnd.Intn(numNamespaces)), pubKey(rnd.Intn(numKeys+5))
+			b.AddToReadSet(ns, key, committed[ns+key])
+			b.AddToMetadataWriteSet(ns, key, map[string][]byte{"entry": []byte(fmt.Sprintf("metadata-%d", rnd.Int()))})
+		}
+		switch rnd.Intn(6) {
+		case 0:
+			b.AddToPvtAndHashedWriteSet(namespace(rnd.Intn(numNamespaces)), "coll1", pvtKey(rnd.Intn(numPvtKeys+3)), []byte("pvt-value"))
+		case 1:
+			b.AddToPvtAndHashedWriteSetForPurge(namespace(rnd.Intn(numNamespaces)), "coll1", pvtKey(rnd.Intn(numPvtKeys+3)))
+		case 2:
+			n, k := rnd.Intn(numNamespaces), rnd.Intn(numPvtKeys)
+			b.AddToHashedReadSet(namespace(n), "coll1", pvtKey(k), version.NewHeight(1, uint64(n*numPvtKeys+k)))
+			b.AddToHashedMetadataWriteSet(namespace(n), "coll1", pvtKey(k), map[string][]byte{"entry": []byte("pvt-metadata")})
+		}
+		return b
+	}
+
+	validate := func(transRWSets []*rwsetutil.TxRwSet, parallelWorkers int) ([]peer.TxValidationCode, *publicAndHashUpdates, []*AppInitiatedPurgeUpdate) {
+		var txs []*transaction
+		for i, txRWSet := range transRWSets {
+			txs = append(txs, &transaction{
+				id:           fmt.Sprintf("txid-%d", i),
+				indexInBlock: i,
+				rwset:        txRWSet,
+			})
+		}
+		v := &validator{db: db, hashFunc: testHashFunc, parallelWorkers: parallelWorkers}
+		updates, purgeUpdates, err := v.validateAndPrepareBatch(&block{num: 2, txs: txs}, true)
+		require.NoError(t, err)
+		var codes []peer.TxValidationCode
+		for _, tx := range txs {
+			codes = append(codes, tx.validationCode)
+		}
+		return codes, updates, purgeUpdates
+	}
+
+	for seed := int64(0); seed < 20; seed++ {
+		t.Run(fmt.Sprintf("seed-%d", seed), func(t *testing.T) {
+			rnd := rand.New(rand.NewSource(seed))
+			var builders []*rwsetutil.RWSetBuilder
+			for i := 0; i < 100; i++ {
+				builders = append(builders, randomRWSet(rnd))
+			}
+			transRWSets := getTestPubSimulationRWSet(t, builders...)
+
+			expectedCodes, expectedUpdates, expectedPurgeUpdates := validate(transRWSets, 0)
+			require.Contains(t, expectedCodes, peer.TxValidationCode_VALID)
+			require.Contains(t, expectedCodes, peer.TxValidationCode_MVCC_READ_CONFLICT)
+			for _, workers := range []int{2, 4, 16} {
+				codes, updates, purgeUpdates := validate(transRWSets, workers)
+				require.Equal(t, expectedCodes, codes)
+				require.Equal(t, expectedUpdates, updates)
+				require.ElementsMatch(t, expectedPurgeUpdates, purgeUpdates)
+			}
+		})
+	}
+}
@@ -57,6 +57,11 @@ func prepareTxOps(rwset *rwsetutil.TxRwSet, precedingUpdates *publicAndHashUpdat
 func (txops txOps) applyTxRwset(rwset *rwsetutil.TxRwSet) error {
 	for _, nsRWSet := range rwset.NsRwSets {
 		ns := nsRWSet.NameSpace
+		// the metadata writes of a cross-channel namespace carry the proofs
+		// of the reads of another channel, which are not part of the state
+		if rwsetutil.IsCrossChannelNamespace(ns) {
+			continue
+		}
 		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
 			txops.applyKVWrite(ns, "", kvWrite)
 		}
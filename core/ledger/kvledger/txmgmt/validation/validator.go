@@ -20,6 +20,9 @@ import (
 type validator struct {
 	db       *privacyenabledstate.DB
 	hashFunc rwsetutil.HashFunc
+	// parallelWorkers is the number of goroutines used to validate the
+	// transactions of a block; the validation is sequential if it is below 2
+	parallelWorkers int
 }
 
 // preLoadCommittedVersionOfRSet loads committed version of all keys in each
@@ -39,6 +42,9 @@ func (v *validator) preLoadCommittedVersionOfRSet(blk *block) error {
 
 	for _, tx := range blk.txs {
 		for _, nsRWSet := range tx.rwset.NsRwSets {
+			if rwsetutil.IsCrossChannelNamespace(nsRWSet.NameSpace) {
+				continue
+			}
 			for _, kvRead := range nsRWSet.KvRwSet.Reads {
 				compositeKey := statedb.CompositeKey{
 					Namespace: nsRWSet.NameSpace,
@@ -89,6 +95,10 @@ func (v *validator) validateAndPrepareBatch(blk *block, doMVCCValidation bool) (
 		}
 	}
 
+	if v.parallelWorkers > 1 {
+		return v.validateAndPrepareBatchInParallel(blk, doMVCCValidation)
+	}
+
 	updates := newPubAndHashUpdates()
 	purgeTracker := newPvtdataPurgeTracker()
 
@@ -136,6 +146,11 @@ func (v *validator) validateTx(txRWSet *rwsetutil.TxRwSet, updates *publicAndHas
 	// logger.Debugf("validateTx - validating txRWSet: %s", spew.Sdump(txRWSet))
 	for _, nsRWSet := range txRWSet.NsRwSets {
 		ns := nsRWSet.NameSpace
+		// The reads of other channels are validated against the ledgers of
+		// these channels by the transaction validator of the committer
+		if rwsetutil.IsCrossChannelNamespace(ns) {
+			continue
+		}
 		// Validate public reads
 		if valid, err := v.validateReadSet(ns, nsRWSet.KvRwSet.Reads, updates.publicUpdates); !valid || err != nil {
 			if err != nil {
@@ -235,6 +235,13 @@ func TestValidator(t *testing.T) {
 	rwsetBuilder5 := rwsetutil.NewRWSetBuilder()
 	rwsetBuilder5.AddToReadSet("ns1", "key1", version.NewHeight(1, 0))
 	checkValidation(t, testValidator, getTestPubSimulationRWSet(t, rwsetBuilder4, rwsetBuilder5), []int{1})
+
+	// rwset6 should be valid, as the reads of other channels are not validated against this state
+	rwsetBuilder6 := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder6.AddToReadSet("ns1", "key2", version.NewHeight(1, 1))
+	rwsetBuilder6.AddToReadSet(rwsetutil.CrossChannelNamespace("otherchannel", "ns1"), "key2", version.NewHeight(5, 0))
+	rwsetBuilder6.AddToReadSet(rwsetutil.CrossChannelNamespace("otherchannel", "ns1"), rwsetutil.CrossChannelHeightKey, version.NewHeight(7, 0))
+	checkValidation(t, testValidator, getTestPubSimulationRWSet(t, rwsetBuilder6), []int{})
 }
 
 func TestPhantomValidation(t *testing.T) {
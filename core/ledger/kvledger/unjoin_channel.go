@@ -18,6 +18,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/bookkeeping"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/history"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/msgs"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/statecommitment"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/pvtdatastorage"
 	"github.com/pkg/errors"
@@ -116,6 +117,15 @@ func removeLedgerData(config *ledger.Config, ledgerID string) error {
 	}
 	defer historydbProvider.Close()
 
+	stateCommitmentProvider, err := statecommitment.NewDBProvider(
+		StateCommitmentDBPath(config.RootFSPath),
+		0,
+	)
+	if err != nil {
+		return err
+	}
+	defer stateCommitmentProvider.Close()
+
 	configHistoryMgr, err := confighistory.NewMgr(
 		ConfigHistoryDBPath(config.RootFSPath),
 		&noopDeployedChaincodeInfoProvider{},
@@ -126,12 +136,13 @@ func removeLedgerData(config *ledger.Config, ledgerID string) error {
 	defer configHistoryMgr.Close()
 
 	ledgerDataRemover := &ledgerDataRemover{
-		blkStoreProvider:     blkStoreProvider,
-		statedbProvider:      dbProvider,
-		bookkeepingProvider:  bookkeepingProvider,
-		configHistoryMgr:     configHistoryMgr,
-		historydbProvider:    historydbProvider,
-		pvtdataStoreProvider: pvtdataStoreProvider,
+		blkStoreProvider:        blkStoreProvider,
+		statedbProvider:         dbProvider,
+		bookkeepingProvider:     bookkeepingProvider,
+		configHistoryMgr:        configHistoryMgr,
+		historydbProvider:       historydbProvider,
+		stateCommitmentProvider: stateCommitmentProvider,
+		pvtdataStoreProvider:    pvtdataStoreProvider,
 	}
 	return ledgerDataRemover.Drop(ledgerID)
 }
@@ -20,6 +20,7 @@ import (
 	"github.com/hyperledger/fabric/bccsp"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/ledger/stateproof"
 )
 
 const (
@@ -38,6 +39,7 @@ type Initializer struct {
 	Config                          *Config
 	CustomTxProcessors              map[common.HeaderType]CustomTxProcessor
 	HashProvider                    HashProvider
+	CryptoProvider                  bccsp.BCCSP
 }
 
 // Config is a structure used to configure a ledger provider.
@@ -52,6 +54,8 @@ type Config struct {
 	HistoryDBConfig *HistoryDBConfig
 	// SnapshotsConfig holds the configuration parameters for the snapshots.
 	SnapshotsConfig *SnapshotsConfig
+	// StateCommitmentConfig holds the configuration parameters for the state commitment.
+	StateCommitmentConfig *StateCommitmentConfig
 }
 
 // StateDBConfig is a structure used to configure the state parameters for the ledger.
@@ -62,6 +66,10 @@ type StateDBConfig struct {
 	// CouchDB is the configuration for CouchDB.  It is used when StateDatabase
 	// is set to "CouchDB".
 	CouchDB *CouchDBConfig
+	// ParallelValidationWorkers is the number of goroutines used to validate
+	// the transactions of a block against the state database. The validation
+	// is sequential when it is below 2.
+	ParallelValidationWorkers int
 }
 
 // CouchDBConfig is a structure used to configure a CouchInstance.
@@ -120,6 +128,27 @@ type PrivateDataConfig struct {
 	DeprioritizedDataReconcilerInterval time.Duration
 	// PurgedKeyAuditLogging specifies whether to log private data keys purged from private data store (INFO level) when explicitly purged via chaincode
 	PurgedKeyAuditLogging bool
+	// Encryption holds the configuration parameters for the encryption at rest of the
+	// private data, in the private data store and in the state database.
+	Encryption *PrivateDataEncryptionConfig
+}
+
+// PrivateDataEncryptionConfig is a structure used to configure the encryption at rest of
+// the private data. The values of the collections are encrypted with data keys that are
+// wrapped with a key generated and kept by the BCCSP of the peer.
+type PrivateDataEncryptionConfig struct {
+	// Enabled specifies whether the private data of the collections are encrypted.
+	Enabled bool
+	// Collections lists the collections to encrypt, as "<namespace>/<collection>".
+	// All the collections are encrypted if none is listed.
+	Collections []string
+	// ReencryptionBatchSize is the maximum number of values examined in one batch by
+	// the background job that brings the stored values in line with the configuration
+	// and with the current key.
+	ReencryptionBatchSize int
+	// ReencryptionBatchesInterval is the minimum duration between two batches of the
+	// background re-encryption job.
+	ReencryptionBatchesInterval time.Duration
 }
 
 // HistoryDBConfig is a structure used to configure the transaction history database.
@@ -127,6 +156,16 @@ type HistoryDBConfig struct {
 	Enabled bool
 }
 
+// StateCommitmentConfig is a structure used to configure the Merkle tree maintained over the
+// public state, from which the proofs of the values of keys are generated.
+type StateCommitmentConfig struct {
+	Enabled bool
+	// RetainBlocks is the number of most recent blocks as of which proofs can
+	// be generated. The tree versions of the older blocks are pruned. Zero
+	// retains the versions of all the blocks.
+	RetainBlocks uint64
+}
+
 // SnapshotsConfig is a structure used to configure snapshot function
 type SnapshotsConfig struct {
 	// RootDir is the top-level directory for the snapshots.
@@ -224,6 +263,18 @@ type PeerLedger interface {
 	// CommitNotifications channel to close. There is expected to be only one consumer at a time. The function returns error
 	// if already a CommitNotification channel is active.
 	CommitNotificationsChannel(done <-chan struct{}) (<-chan *CommitNotification, error)
+	// GetStateProof returns the value of the key of the namespace in the public state after the commit of the
+	// given block, or no value if the key did not exist, with a proof that can be verified offline against the
+	// root of the state commitment at that block. It returns an error if the state commitment is not enabled or
+	// does not cover the block.
+	GetStateProof(namespace, key string, blockNum uint64) (*stateproof.Proof, error)
+}
+
+// StateProver returns proofs of the values of the keys of the public state of a ledger.
+type StateProver interface {
+	// GetStateProof returns the value of the key of the namespace after the commit of the given block with a proof
+	// of that value against the root of the state commitment at that block.
+	GetStateProof(namespace, key string, blockNum uint64) (*stateproof.Proof, error)
 }
 
 // SimpleQueryExecutor encapsulates basic functions
@@ -341,6 +392,14 @@ type TxSimulator interface {
 	// The public data simulation results are expected to be used as in V1 while the private data simulation results are expected
 	// to be used by the gossip to disseminate this to the other endorsers (in phase-2 of sidedb)
 	GetTxSimulationResults() (*TxSimulationResults, error)
+	// AddCrossChannelReads records the reads performed by 'sim', a simulator of the ledger of the channel 'channelID'
+	// obtained from the same ledger provider, in the read set of this simulator, along with the height of that ledger
+	// at which the reads were performed and the proofs of the values of the keys read at that height obtained from
+	// 'prover', the ledger of the channel, so that the reads can be validated without access to that ledger when this
+	// transaction is committed. An error is returned if 'sim' performed writes, range queries or operations on private
+	// data, or if a proof cannot be obtained. This function consumes the simulation results of 'sim' and ends its
+	// simulation.
+	AddCrossChannelReads(channelID string, sim TxSimulator, prover StateProver) error
 }
 
 // QueryResultsIterator - an iterator for query result set
@@ -824,6 +883,7 @@ type CommitNotificationTxInfo struct {
 //go:generate counterfeiter -o mock/state_listener.go -fake-name StateListener . StateListener
 //go:generate counterfeiter -o mock/query_executor.go -fake-name QueryExecutor . QueryExecutor
 //go:generate counterfeiter -o mock/tx_simulator.go -fake-name TxSimulator . TxSimulator
+//go:generate counterfeiter -o mock/state_prover.go -fake-name StateProver . StateProver
 //go:generate counterfeiter -o mock/deployed_ccinfo_provider.go -fake-name DeployedChaincodeInfoProvider . DeployedChaincodeInfoProvider
 //go:generate counterfeiter -o mock/membership_info_provider.go -fake-name MembershipInfoProvider . MembershipInfoProvider
 //go:generate counterfeiter -o mock/health_check_registry.go -fake-name HealthCheckRegistry . HealthCheckRegistry
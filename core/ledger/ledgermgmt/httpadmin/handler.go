@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+)
+
+const (
+	// URLBase is the path under which the handler is registered.
+	URLBase = "/pvtdataencryption/"
+	// keySuffix completes the path of the key encryption key of a channel,
+	// URLBase + "<channel>" + keySuffix, which is rotated with a PUT request.
+	keySuffix = "/key"
+)
+
+// LedgerMgr is the part of the ledger manager used by the handler.
+type LedgerMgr interface {
+	PvtdataEncryptionStatus() []*kvledger.PvtdataEncryptionStatus
+	RotatePvtdataEncryptionKey(ledgerID string) ([]byte, error)
+}
+
+// StatusResponse reports the encryption at rest of the private data of the channels.
+type StatusResponse struct {
+	Channels []*kvledger.PvtdataEncryptionStatus `json:"channels"`
+}
+
+// KeyResponse reports the key with which the private data of a channel are encrypted.
+type KeyResponse struct {
+	EncryptionKeySKI string `json:"encryption_key_ski"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func NewHandler(ledgerMgr LedgerMgr) *Handler {
+	return &Handler{
+		LedgerMgr: ledgerMgr,
+		Logger:    flogging.MustGetLogger("ledgermgmt.httpadmin"),
+	}
+}
+
+// Handler reports the encryption at rest of the private data of the channels, and
+// rotates the key with which the private data of a channel are encrypted.
+type Handler struct {
+	LedgerMgr LedgerMgr
+	Logger    *flogging.FabricLogger
+}
+
+func (h *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	channel, isKeyPath := channelOfKeyPath(req.URL.Path)
+	switch {
+	case req.URL.Path == URLBase && req.Method == http.MethodGet:
+		h.sendResponse(resp, http.StatusOK, &StatusResponse{Channels: h.LedgerMgr.PvtdataEncryptionStatus()})
+
+	case isKeyPath && req.Method == http.MethodPut:
+		ski, err := h.LedgerMgr.RotatePvtdataEncryptionKey(channel)
+		if err != nil {
+			h.sendResponse(resp, http.StatusBadRequest, err)
+			return
+		}
+		h.sendResponse(resp, http.StatusOK, &KeyResponse{EncryptionKeySKI: hex.EncodeToString(ski)})
+
+	case req.URL.Path == URLBase || isKeyPath:
+		h.sendResponse(resp, http.StatusMethodNotAllowed, fmt.Errorf("invalid request method: %s", req.Method))
+
+	default:
+		h.sendResponse(resp, http.StatusNotFound, fmt.Errorf("invalid path: %s", req.URL.Path))
+	}
+}
+
+func channelOfKeyPath(path string) (string, bool) {
+	rest := strings.TrimPrefix(path, URLBase)
+	if rest == path || !strings.HasSuffix(rest, keySuffix) {
+		return "", false
+	}
+	channel := strings.TrimSuffix(rest, keySuffix)
+	if channel == "" || strings.Contains(channel, "/") {
+		return "", false
+	}
+	return channel, true
+}
+
+func (h *Handler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	encoder := json.NewEncoder(resp)
+	if err, ok := payload.(error); ok {
+		payload = &ErrorResponse{Error: err.Error()}
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+
+	if err := encoder.Encode(payload); err != nil {
+		h.Logger.Errorw("failed to encode payload", "error", err)
+	}
+}
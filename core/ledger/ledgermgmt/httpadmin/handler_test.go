@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLedgerMgr struct {
+	statuses []*kvledger.PvtdataEncryptionStatus
+	rotated  []string
+}
+
+func (m *fakeLedgerMgr) PvtdataEncryptionStatus() []*kvledger.PvtdataEncryptionStatus {
+	return m.statuses
+}
+
+func (m *fakeLedgerMgr) RotatePvtdataEncryptionKey(ledgerID string) ([]byte, error) {
+	if ledgerID != "mychannel" {
+		return nil, errors.New("Ledger not opened [" + ledgerID + "]")
+	}
+	m.rotated = append(m.rotated, ledgerID)
+	return []byte{0x01, 0x02}, nil
+}
+
+func serve(t *testing.T, h *Handler, method, path string, response interface{}) int {
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(method, path, nil))
+	require.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), response))
+	return resp.Code
+}
+
+func TestHandlerStatus(t *testing.T) {
+	ledgerMgr := &fakeLedgerMgr{
+		statuses: []*kvledger.PvtdataEncryptionStatus{
+			{LedgerID: "mychannel", Enabled: true, EncryptionKeySKI: "0102", Reencryption: "completed"},
+		},
+	}
+	h := NewHandler(ledgerMgr)
+
+	status := &StatusResponse{}
+	require.Equal(t, http.StatusOK, serve(t, h, http.MethodGet, URLBase, status))
+	require.Equal(t, ledgerMgr.statuses, status.Channels)
+
+	errResp := &ErrorResponse{}
+	require.Equal(t, http.StatusMethodNotAllowed, serve(t, h, http.MethodPost, URLBase, errResp))
+	require.Equal(t, "invalid request method: POST", errResp.Error)
+	require.Equal(t, http.StatusNotFound, serve(t, h, http.MethodGet, URLBase+"unknown", errResp))
+	require.Equal(t, "invalid path: /pvtdataencryption/unknown", errResp.Error)
+}
+
+func TestHandlerRotateKey(t *testing.T) {
+	ledgerMgr := &fakeLedgerMgr{}
+	h := NewHandler(ledgerMgr)
+
+	key := &KeyResponse{}
+	require.Equal(t, http.StatusOK, serve(t, h, http.MethodPut, URLBase+"mychannel/key", key))
+	require.Equal(t, "0102", key.EncryptionKeySKI)
+	require.Equal(t, []string{"mychannel"}, ledgerMgr.rotated)
+
+	errResp := &ErrorResponse{}
+	require.Equal(t, http.StatusBadRequest, serve(t, h, http.MethodPut, URLBase+"otherchannel/key", errResp))
+	require.Equal(t, "Ledger not opened [otherchannel]", errResp.Error)
+	require.Equal(t, http.StatusMethodNotAllowed, serve(t, h, http.MethodGet, URLBase+"mychannel/key", errResp))
+	require.Equal(t, "invalid request method: GET", errResp.Error)
+	require.Equal(t, http.StatusNotFound, serve(t, h, http.MethodPut, URLBase+"my/channel/key", errResp))
+	require.Equal(t, http.StatusNotFound, serve(t, h, http.MethodPut, URLBase+"key", errResp))
+}
@@ -9,10 +9,12 @@ package ledgermgmt
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/hyperledger/fabric-protos-go/common"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
@@ -58,6 +60,7 @@ type Initializer struct {
 	HealthCheckRegistry             ledger.HealthCheckRegistry
 	Config                          *ledger.Config
 	HashProvider                    ledger.HashProvider
+	CryptoProvider                  bccsp.BCCSP
 	EbMetadataProvider              MetadataProvider
 }
 
@@ -79,6 +82,7 @@ func NewLedgerMgr(initializer *Initializer) *LedgerMgr {
 			Config:                          initializer.Config,
 			CustomTxProcessors:              initializer.CustomTxProcessors,
 			HashProvider:                    initializer.HashProvider,
+			CryptoProvider:                  initializer.CryptoProvider,
 		},
 	)
 	if err != nil {
@@ -253,6 +257,45 @@ func (m *LedgerMgr) Close() {
 	logger.Infof("ledger mgmt closed")
 }
 
+// pvtdataEncryptionLedger is implemented by the ledgers that encrypt their private data at rest.
+type pvtdataEncryptionLedger interface {
+	PvtdataEncryptionStatus() *kvledger.PvtdataEncryptionStatus
+	RotatePvtdataEncryptionKey() ([]byte, error)
+}
+
+// PvtdataEncryptionStatus returns the status of the encryption at rest of the private data of the
+// opened ledgers whose private data have ever been encrypted, sorted by ledger ID.
+func (m *LedgerMgr) PvtdataEncryptionStatus() []*kvledger.PvtdataEncryptionStatus {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	statuses := []*kvledger.PvtdataEncryptionStatus{}
+	for _, l := range m.openedLedgers {
+		if el, ok := l.(pvtdataEncryptionLedger); ok {
+			if status := el.PvtdataEncryptionStatus(); status != nil {
+				statuses = append(statuses, status)
+			}
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].LedgerID < statuses[j].LedgerID
+	})
+	return statuses
+}
+
+// RotatePvtdataEncryptionKey generates a new key to encrypt the private data of the ledger at rest
+// and returns its subject key identifier. The private data are re-encrypted in the background.
+func (m *LedgerMgr) RotatePvtdataEncryptionKey(ledgerID string) ([]byte, error) {
+	l, err := m.getOpenedLedger(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	el, ok := l.(pvtdataEncryptionLedger)
+	if !ok {
+		return nil, errors.Errorf("private data encryption is not supported by ledger [%s]", ledgerID)
+	}
+	return el.RotatePvtdataEncryptionKey()
+}
+
 func (m *LedgerMgr) getOpenedLedger(ledgerID string) (ledger.PeerLedger, error) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package ledgermgmt
 
 import (
+	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -14,6 +15,7 @@ import (
 	"time"
 
 	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp/sw"
 	"github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
@@ -295,6 +297,42 @@ func TestChaincodeInfoProvider(t *testing.T) {
 	require.Equal(t, constructTestCCInfo("cc1", "cc1", "cc1"), ccInfo)
 }
 
+func TestPvtdataEncryption(t *testing.T) {
+	testDir := t.TempDir()
+	initializer, err := constructDefaultInitializer(testDir)
+	require.NoError(t, err)
+	initializer.Config.PrivateDataConfig.Encryption = &ledger.PrivateDataEncryptionConfig{Enabled: true}
+	initializer.CryptoProvider, err = sw.NewDefaultSecurityLevel(filepath.Join(testDir, "keystore"))
+	require.NoError(t, err)
+	initializer.DeployedChaincodeInfoProvider.(*mock.DeployedChaincodeInfoProvider).GenerateImplicitCollectionForOrgStub = func(mspID string) *peer.StaticCollectionConfig {
+		return &peer.StaticCollectionConfig{Name: "_implicit_org_" + mspID}
+	}
+	ledgerMgr := NewLedgerMgr(initializer)
+	defer ledgerMgr.Close()
+	require.Empty(t, ledgerMgr.PvtdataEncryptionStatus())
+
+	for _, ledgerID := range []string{"ledger2", "ledger1"} {
+		gb, err := test.MakeGenesisBlock(ledgerID)
+		require.NoError(t, err)
+		_, err = ledgerMgr.CreateLedger(ledgerID, gb)
+		require.NoError(t, err)
+	}
+	statuses := ledgerMgr.PvtdataEncryptionStatus()
+	require.Len(t, statuses, 2)
+	require.Equal(t, "ledger1", statuses[0].LedgerID)
+	require.Equal(t, "ledger2", statuses[1].LedgerID)
+	require.True(t, statuses[0].Enabled)
+
+	ski, err := ledgerMgr.RotatePvtdataEncryptionKey("ledger1")
+	require.NoError(t, err)
+	statuses = ledgerMgr.PvtdataEncryptionStatus()
+	require.Equal(t, hex.EncodeToString(ski), statuses[0].EncryptionKeySKI)
+	require.NotEqual(t, statuses[0].EncryptionKeySKI, statuses[1].EncryptionKeySKI)
+
+	_, err = ledgerMgr.RotatePvtdataEncryptionKey("unknown-ledger")
+	require.EqualError(t, err, "Ledger not opened [unknown-ledger]")
+}
+
 func setup(t *testing.T) (*Initializer, *LedgerMgr, func()) {
 	testDir := t.TempDir()
 	initializer, err := constructDefaultInitializer(testDir)
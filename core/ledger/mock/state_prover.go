@@ -0,0 +1,121 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package mock
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/stateproof"
+)
+
+type StateProver struct {
+	GetStateProofStub        func(string, string, uint64) (*stateproof.Proof, error)
+	getStateProofMutex       sync.RWMutex
+	getStateProofArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 uint64
+	}
+	getStateProofReturns struct {
+		result1 *stateproof.Proof
+		result2 error
+	}
+	getStateProofReturnsOnCall map[int]struct {
+		result1 *stateproof.Proof
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *StateProver) GetStateProof(arg1 string, arg2 string, arg3 uint64) (*stateproof.Proof, error) {
+	fake.getStateProofMutex.Lock()
+	ret, specificReturn := fake.getStateProofReturnsOnCall[len(fake.getStateProofArgsForCall)]
+	fake.getStateProofArgsForCall = append(fake.getStateProofArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 uint64
+	}{arg1, arg2, arg3})
+	stub := fake.GetStateProofStub
+	fakeReturns := fake.getStateProofReturns
+	fake.recordInvocation("GetStateProof", []interface{}{arg1, arg2, arg3})
+	fake.getStateProofMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *StateProver) GetStateProofCallCount() int {
+	fake.getStateProofMutex.RLock()
+	defer fake.getStateProofMutex.RUnlock()
+	return len(fake.getStateProofArgsForCall)
+}
+
+func (fake *StateProver) GetStateProofCalls(stub func(string, string, uint64) (*stateproof.Proof, error)) {
+	fake.getStateProofMutex.Lock()
+	defer fake.getStateProofMutex.Unlock()
+	fake.GetStateProofStub = stub
+}
+
+func (fake *StateProver) GetStateProofArgsForCall(i int) (string, string, uint64) {
+	fake.getStateProofMutex.RLock()
+	defer fake.getStateProofMutex.RUnlock()
+	argsForCall := fake.getStateProofArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *StateProver) GetStateProofReturns(result1 *stateproof.Proof, result2 error) {
+	fake.getStateProofMutex.Lock()
+	defer fake.getStateProofMutex.Unlock()
+	fake.GetStateProofStub = nil
+	fake.getStateProofReturns = struct {
+		result1 *stateproof.Proof
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateProver) GetStateProofReturnsOnCall(i int, result1 *stateproof.Proof, result2 error) {
+	fake.getStateProofMutex.Lock()
+	defer fake.getStateProofMutex.Unlock()
+	fake.GetStateProofStub = nil
+	if fake.getStateProofReturnsOnCall == nil {
+		fake.getStateProofReturnsOnCall = make(map[int]struct {
+			result1 *stateproof.Proof
+			result2 error
+		})
+	}
+	fake.getStateProofReturnsOnCall[i] = struct {
+		result1 *stateproof.Proof
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StateProver) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.getStateProofMutex.RLock()
+	defer fake.getStateProofMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *StateProver) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ ledger.StateProver = new(StateProver)
@@ -0,0 +1,150 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatastorage
+
+import (
+	"github.com/hyperledger/fabric/common/ledger/encryption"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/internal/pvtdataencryption"
+)
+
+// encryptedDataValueMarker prefixes the envelope of an encrypted data value. A
+// data value is otherwise a marshaled CollectionPvtReadWriteSet, which cannot
+// start with this byte, as it is not a valid protobuf field tag.
+const encryptedDataValueMarker = byte(0x01)
+
+// SetEncryption sets the encryption of the data values of the collections. It
+// is expected to be invoked, if at all, before using the store.
+func (s *Store) SetEncryption(e *pvtdataencryption.Encryption) {
+	s.encryption = e
+}
+
+// EncryptionMetadata returns the store that keeps the keyring of the private data
+// encryption and the progress of the re-encryption. They are kept along with the
+// data values so that they are not lost while the encrypted data values remain,
+// such as when the other ledger databases are dropped to be rebuilt.
+func (s *Store) EncryptionMetadata() encryption.MetadataStore {
+	return &encryptionMetadataStore{s.db}
+}
+
+type encryptionMetadataStore struct {
+	db *leveldbhelper.DBHandle
+}
+
+func (m *encryptionMetadataStore) Get(key []byte) ([]byte, error) {
+	return m.db.Get(encodeEncryptionMetadataKey(key))
+}
+
+func (m *encryptionMetadataStore) Put(key []byte, value []byte, sync bool) error {
+	return m.db.Put(encodeEncryptionMetadataKey(key), value, sync)
+}
+
+func encodeEncryptionMetadataKey(key []byte) []byte {
+	return append(append([]byte{}, encryptionMetadataKeyPrefix...), key...)
+}
+
+// encryptDataValue encrypts the encoded data value if the encryption of its
+// collection is enabled. The envelope is bound to the data key.
+func encryptDataValue(e *pvtdataencryption.Encryption, dataKeyBytes, dataValueBytes []byte) ([]byte, error) {
+	if e == nil {
+		return dataValueBytes, nil
+	}
+	k, err := decodeDatakey(dataKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+	if !e.Enabled(k.ns, k.coll) {
+		return dataValueBytes, nil
+	}
+	envelope, err := e.Encrypt(k.ns, k.coll, dataValueBytes, dataKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{encryptedDataValueMarker}, envelope...), nil
+}
+
+// decryptDataValue returns the encoded data value, decrypting it if it is
+// encrypted.
+func decryptDataValue(e *pvtdataencryption.Encryption, dataKeyBytes, dataValueBytes []byte) ([]byte, error) {
+	if !isEncryptedDataValue(dataValueBytes) {
+		return dataValueBytes, nil
+	}
+	k, err := decodeDatakey(dataKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+	return e.Decrypt(k.ns, k.coll, dataValueBytes[1:], dataKeyBytes)
+}
+
+func isEncryptedDataValue(dataValueBytes []byte) bool {
+	return len(dataValueBytes) > 0 && dataValueBytes[0] == encryptedDataValueMarker
+}
+
+// ReencryptData brings the data values in line with the encryption of their
+// collection, starting at the data key startKey, or at the first one if it is
+// nil. It encrypts the values of the collections whose encryption is enabled,
+// re-encrypts those encrypted with a former data key, and decrypts the others.
+// At most maxEntries values are examined, and the key at which to resume is
+// returned, which is nil once all the values have been examined.
+func (s *Store) ReencryptData(startKey []byte, maxEntries int) (nextKey []byte, updated int, err error) {
+	s.purgerLock.Lock()
+	defer s.purgerLock.Unlock()
+
+	if startKey == nil {
+		startKey = pvtDataKeyPrefix
+	}
+	itr, err := s.db.GetIterator(startKey, expiryKeyPrefix)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer itr.Release()
+
+	batch := s.db.NewUpdateBatch()
+	examined := 0
+	for itr.Next() {
+		if examined == maxEntries {
+			nextKey = append([]byte{}, itr.Key()...)
+			break
+		}
+		examined++
+
+		dataKeyBytes, dataValueBytes := itr.Key(), itr.Value()
+		k, err := decodeDatakey(dataKeyBytes)
+		if err != nil {
+			return nil, 0, err
+		}
+		encrypted := isEncryptedDataValue(dataValueBytes)
+		var envelope []byte
+		if encrypted {
+			envelope = dataValueBytes[1:]
+		}
+		needsUpdate, err := s.encryption.NeedsUpdate(k.ns, k.coll, envelope, encrypted)
+		if err != nil {
+			return nil, 0, err
+		}
+		if !needsUpdate {
+			continue
+		}
+		plaintext, err := decryptDataValue(s.encryption, dataKeyBytes, dataValueBytes)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, err := encryptDataValue(s.encryption, dataKeyBytes, plaintext)
+		if err != nil {
+			return nil, 0, err
+		}
+		batch.Put(append([]byte{}, dataKeyBytes...), value)
+		updated++
+	}
+	if err := itr.Error(); err != nil {
+		return nil, 0, err
+	}
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return nil, 0, err
+	}
+	return nextKey, updated, nil
+}
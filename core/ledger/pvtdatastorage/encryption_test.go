@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatastorage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/ledger/encryption"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/internal/pvtdataencryption"
+	btltestutil "github.com/hyperledger/fabric/core/ledger/pvtdatapolicy/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreEncryption(t *testing.T) {
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 0,
+			{"ns-1", "coll-2"}: 0,
+		},
+	)
+	env := NewTestStoreEnv(t, "TestStoreEncryption", btlPolicy, pvtDataConf())
+	defer env.Cleanup()
+	store := env.TestStore
+
+	csp, err := sw.NewDefaultSecurityLevel(t.TempDir())
+	require.NoError(t, err)
+	keyring, err := encryption.NewKeyring(csp, store.EncryptionMetadata(), []byte("keyring/"))
+	require.NoError(t, err)
+	store.SetEncryption(pvtdataencryption.New(keyring, true, []string{"ns-1/coll-1"}))
+
+	testData := []*ledger.TxPvtData{
+		produceSamplePvtdata(t, 2, []string{"ns-1:coll-1", "ns-1:coll-2"}),
+		produceSamplePvtdata(t, 4, []string{"ns-1:coll-1", "ns-1:coll-2"}),
+	}
+	require.NoError(t, store.Commit(0, nil, nil, nil))
+	require.NoError(t, store.Commit(1, testData, nil, nil))
+
+	coll1Key := &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-1", blkNum: 1}, txNum: 2}
+	coll2Key := &dataKey{nsCollBlk: nsCollBlk{ns: "ns-1", coll: "coll-2", blkNum: 1}, txNum: 2}
+	requireEncrypted := func(k *dataKey, expected bool) {
+		v, err := store.db.Get(encodeDataKey(k))
+		require.NoError(t, err)
+		require.Equal(t, expected, isEncryptedDataValue(v))
+		require.Equal(t, !expected, strings.Contains(string(v), "value-"+k.ns+"-"+k.coll))
+	}
+	requireRetrievable := func() {
+		retrievedData, err := store.GetPvtDataByBlockNum(1, nil)
+		require.NoError(t, err)
+		require.Len(t, retrievedData, len(testData))
+		for i, data := range retrievedData {
+			require.Equal(t, testData[i].SeqInBlock, data.SeqInBlock)
+			require.True(t, proto.Equal(testData[i].WriteSet, data.WriteSet))
+		}
+		entries, err := store.retrieveDataEntries([]*dataKey{coll1Key, coll2Key})
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		require.Equal(t, "coll-1", entries[0].value.CollectionName)
+	}
+	requireEncrypted(coll1Key, true)
+	requireEncrypted(coll2Key, false)
+	requireRetrievable()
+
+	t.Run("encryption not configured", func(t *testing.T) {
+		store.SetEncryption(nil)
+		defer store.SetEncryption(pvtdataencryption.New(keyring, true, []string{"ns-1/coll-1"}))
+		_, err := store.GetPvtDataByBlockNum(1, nil)
+		require.EqualError(t, err, "private data of collection [coll-1] of namespace [ns-1] is encrypted but the private data encryption is not configured")
+	})
+
+	reencryptAll := func() int {
+		var nextKey []byte
+		total := 0
+		for {
+			var updated int
+			nextKey, updated, err = store.ReencryptData(nextKey, 1)
+			require.NoError(t, err)
+			total += updated
+			if nextKey == nil {
+				return total
+			}
+		}
+	}
+
+	// nothing to do while the values are in line with the configuration
+	require.Equal(t, 0, reencryptAll())
+
+	// rotating the key and enabling the encryption of all the collections
+	// updates all the values
+	_, err = keyring.RotateKey()
+	require.NoError(t, err)
+	e := pvtdataencryption.New(keyring, true, nil)
+	store.SetEncryption(e)
+	require.Equal(t, 4, reencryptAll())
+	requireEncrypted(coll1Key, true)
+	requireEncrypted(coll2Key, true)
+	v, err := store.db.Get(encodeDataKey(coll2Key))
+	require.NoError(t, err)
+	needsUpdate, err := e.NeedsUpdate("ns-1", "coll-2", v[1:], true)
+	require.NoError(t, err)
+	require.False(t, needsUpdate)
+	requireRetrievable()
+	require.Equal(t, 0, reencryptAll())
+
+	// disabling the encryption decrypts the values
+	store.SetEncryption(pvtdataencryption.New(keyring, false, nil))
+	require.Equal(t, 4, reencryptAll())
+	requireEncrypted(coll1Key, false)
+	requireEncrypted(coll2Key, false)
+	store.SetEncryption(nil)
+	requireRetrievable()
+}
@@ -34,6 +34,7 @@ var (
 	purgeMarkerKeyPrefix             = []byte{'c'}
 	purgeMarkerCollKeyPrefix         = []byte{'d'}
 	purgeMarkerForReconKeyPrefix     = []byte{'e'}
+	encryptionMetadataKeyPrefix      = []byte{'f'}
 
 	nilByte    = byte(0)
 	emptyValue = []byte{}
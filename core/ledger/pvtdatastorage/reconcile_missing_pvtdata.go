@@ -11,6 +11,7 @@ import (
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/internal/pvtdataencryption"
 	"github.com/pkg/errors"
 )
 
@@ -303,7 +304,7 @@ func (p *oldBlockDataProcessor) getDeprioMissingDataFromEntriesOrStore(nsCollBlk
 func (p *oldBlockDataProcessor) constructDBUpdateBatch() (*leveldbhelper.UpdateBatch, error) {
 	batch := p.db.NewUpdateBatch()
 
-	if err := p.entries.addDataEntriesTo(batch); err != nil {
+	if err := p.entries.addDataEntriesTo(batch, p.encryption); err != nil {
 		return nil, errors.WithMessage(err, "error while adding data entries to the update batch")
 	}
 
@@ -337,7 +338,7 @@ type entriesForPvtDataOfOldBlocks struct {
 	bootKVHashesDeletions           []*bootKVHashesKey
 }
 
-func (e *entriesForPvtDataOfOldBlocks) addDataEntriesTo(batch *leveldbhelper.UpdateBatch) error {
+func (e *entriesForPvtDataOfOldBlocks) addDataEntriesTo(batch *leveldbhelper.UpdateBatch, encryption *pvtdataencryption.Encryption) error {
 	var key, val []byte
 	var err error
 
@@ -346,6 +347,9 @@ func (e *entriesForPvtDataOfOldBlocks) addDataEntriesTo(batch *leveldbhelper.Upd
 		if val, err = encodeDataValue(pvtData); err != nil {
 			return errors.Wrap(err, "error while encoding data value")
 		}
+		if val, err = encryptDataValue(encryption, key, val); err != nil {
+			return err
+		}
 		batch.Put(key, val)
 	}
 	return nil
@@ -19,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/confighistory"
+	"github.com/hyperledger/fabric/core/ledger/internal/pvtdataencryption"
 	"github.com/hyperledger/fabric/core/ledger/internal/version"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
@@ -54,6 +55,7 @@ type Store struct {
 	maxBatchSize          int
 	purgeInterval         uint64
 	purgedKeyAuditLogging bool
+	encryption            *pvtdataencryption.Encryption
 
 	isEmpty            bool
 	lastCommittedBlock uint64
@@ -328,6 +330,9 @@ func (s *Store) Commit(blockNum uint64, pvtData []*ledger.TxPvtData, missingPvtD
 		if val, err = encodeDataValue(dataEntry.value); err != nil {
 			return err
 		}
+		if val, err = encryptDataValue(s.encryption, key, val); err != nil {
+			return err
+		}
 		batch.Put(key, val)
 	}
 
@@ -513,6 +518,10 @@ func (s *Store) GetPvtDataByBlockNum(blockNum uint64, filter ledger.PvtNsCollFil
 			currentTxWsetAssember = newTxPvtdataAssembler(blockNum, currentTxNum)
 		}
 
+		dataValueBytes, err = decryptDataValue(s.encryption, dataKeyBytes, dataValueBytes)
+		if err != nil {
+			return nil, err
+		}
 		dataValue, err := decodeDataValue(dataValueBytes)
 		if err != nil {
 			return nil, err
@@ -871,7 +880,7 @@ func (s *Store) deleteDataMarkedForPurge() error {
 	maxBatchSize := 4 * 1024 * 1024 // 4Mb
 	purgeMarkerCounter := 0
 	hashedIndexCounter := 0
-	p := newPurgeUpdatesProcessor(s.ledgerid, s.db, s.purgedKeyAuditLogging, maxBatchSize)
+	p := newPurgeUpdatesProcessor(s.ledgerid, s.db, s.encryption, s.purgedKeyAuditLogging, maxBatchSize)
 	pStart, pEnd := rangeScanKeysForPurgeMarkers()
 
 	// get the purge markers that need to be processed at this block height
@@ -956,10 +965,14 @@ func (s *Store) retrieveExpiryEntries(minBlkNum, maxBlkNum uint64) ([]*expiryEnt
 func (s *Store) retrieveDataEntries(dataKeys []*dataKey) ([]*dataEntry, error) {
 	dataEntries := []*dataEntry{}
 	for _, k := range dataKeys {
-		v, err := s.db.Get(encodeDataKey(k))
+		dataKeyBytes := encodeDataKey(k)
+		v, err := s.db.Get(dataKeyBytes)
 		if err != nil {
 			return nil, err
 		}
+		if v, err = decryptDataValue(s.encryption, dataKeyBytes, v); err != nil {
+			return nil, err
+		}
 
 		collWS, err := decodeDataValue(v)
 		if err != nil {
@@ -1169,6 +1182,7 @@ func (c *collElgProcSync) waitForDone() {
 type purgeUpdatesProcessor struct {
 	ledgerid     string
 	db           *leveldbhelper.DBHandle
+	encryption   *pvtdataencryption.Encryption
 	batch        *leveldbhelper.UpdateBatch
 	maxBatchSize int
 
@@ -1180,10 +1194,11 @@ type purgeUpdatesProcessor struct {
 
 // newPurgeUpdatesProcessor is used for processing the purge markers - i.e., delete the private data versions that are marked for purge from
 // the pvtdata store.
-func newPurgeUpdatesProcessor(ledgerid string, db *leveldbhelper.DBHandle, purgedKeyAuditLogging bool, maxBatchSize int) *purgeUpdatesProcessor {
+func newPurgeUpdatesProcessor(ledgerid string, db *leveldbhelper.DBHandle, encryption *pvtdataencryption.Encryption, purgedKeyAuditLogging bool, maxBatchSize int) *purgeUpdatesProcessor {
 	return &purgeUpdatesProcessor{
 		ledgerid:              ledgerid,
 		db:                    db,
+		encryption:            encryption,
 		purgedKeyAuditLogging: purgedKeyAuditLogging,
 		maxBatchSize:          maxBatchSize,
 		pvtWrites:             map[string]*rwsetutil.CollPvtRwSet{},
@@ -1208,6 +1223,9 @@ func (p *purgeUpdatesProcessor) process(hashedIndexKey, hashedIndexVal []byte) e
 		if err != nil {
 			return err
 		}
+		if dataValue, err = decryptDataValue(p.encryption, dataKey, dataValue); err != nil {
+			return err
+		}
 		collPvtRWSetProto, err := decodeDataValue(dataValue)
 		if err != nil {
 			return err
@@ -1263,6 +1281,9 @@ func (p *purgeUpdatesProcessor) commitPendingChanges() error {
 		if err != nil {
 			return err
 		}
+		if encDataValue, err = encryptDataValue(p.encryption, []byte(k), encDataValue); err != nil {
+			return err
+		}
 		p.batch.Put([]byte(k), encDataValue)
 	}
 	if err := p.db.WriteBatch(p.batch, true); err != nil {
@@ -8,13 +8,16 @@ package snapshotgrpc
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/empty"
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/audit"
 	"github.com/hyperledger/fabric/common/crypto"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/aclmgmt/resources"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/protoutil"
@@ -25,6 +28,8 @@ import (
 type SnapshotService struct {
 	LedgerGetter LedgerGetter
 	ACLProvider  ACLProvider
+	// AuditLogger records the snapshot requests and their cancellations, or is nil if they are not audited
+	AuditLogger *audit.Logger
 }
 
 // LedgerGetter gets the PeerLedger associated with a channel.
@@ -44,20 +49,26 @@ func (s *SnapshotService) Generate(ctx context.Context, signedRequest *pb.Signed
 		return nil, errors.Wrap(err, "failed to unmarshal snapshot request")
 	}
 
-	if err := s.checkACL(resources.Snapshot_submitrequest, request.SignatureHeader, signedRequest); err != nil {
+	err := s.generate(request, signedRequest)
+	s.audit(ctx, "Generate", request, err)
+	if err != nil {
 		return nil, err
 	}
 
-	lgr, err := s.getLedger(request.ChannelId)
-	if err != nil {
-		return nil, err
+	return &empty.Empty{}, nil
+}
+
+func (s *SnapshotService) generate(request *pb.SnapshotRequest, signedRequest *pb.SignedSnapshotRequest) error {
+	if err := s.checkACL(resources.Snapshot_submitrequest, request.SignatureHeader, signedRequest); err != nil {
+		return err
 	}
 
-	if err := lgr.SubmitSnapshotRequest(request.BlockNumber); err != nil {
-		return nil, err
+	lgr, err := s.getLedger(request.ChannelId)
+	if err != nil {
+		return err
 	}
 
-	return &empty.Empty{}, nil
+	return lgr.SubmitSnapshotRequest(request.BlockNumber)
 }
 
 // Cancel cancels a snapshot request.
@@ -67,20 +78,36 @@ func (s *SnapshotService) Cancel(ctx context.Context, signedRequest *pb.SignedSn
 		return nil, errors.Wrap(err, "failed to unmarshal snapshot request")
 	}
 
-	if err := s.checkACL(resources.Snapshot_cancelrequest, request.SignatureHeader, signedRequest); err != nil {
+	err := s.cancel(request, signedRequest)
+	s.audit(ctx, "Cancel", request, err)
+	if err != nil {
 		return nil, err
 	}
 
+	return &empty.Empty{}, nil
+}
+
+func (s *SnapshotService) cancel(request *pb.SnapshotRequest, signedRequest *pb.SignedSnapshotRequest) error {
+	if err := s.checkACL(resources.Snapshot_cancelrequest, request.SignatureHeader, signedRequest); err != nil {
+		return err
+	}
+
 	lgr, err := s.getLedger(request.ChannelId)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if err := lgr.CancelSnapshotRequest(request.BlockNumber); err != nil {
-		return nil, err
-	}
+	return lgr.CancelSnapshotRequest(request.BlockNumber)
+}
 
-	return &empty.Empty{}, nil
+// audit records the given snapshot request on behalf of its creator.
+func (s *SnapshotService) audit(ctx context.Context, action string, request *pb.SnapshotRequest, err error) {
+	caller := audit.CallerFromSerializedIdentity(request.SignatureHeader.GetCreator())
+	caller.Address = util.ExtractRemoteAddress(ctx)
+	s.AuditLogger.Log("snapshot", action, caller, map[string]string{
+		"channel":     request.ChannelId,
+		"blockNumber": strconv.FormatUint(request.BlockNumber, 10),
+	}, err)
 }
 
 // QueryPendings returns a list of pending snapshot requests.
@@ -7,12 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package snapshotgrpc
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"testing"
 
 	"github.com/hyperledger/fabric-protos-go/common"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/audit"
 	"github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt/ledgermgmttest"
@@ -48,7 +50,8 @@ func TestSnapshot(t *testing.T) {
 	fakeLedgerGetter.GetLedgerReturns(lgr)
 	fakeACLProvider := &mock.ACLProvider{}
 	fakeACLProvider.CheckACLNoChannelReturns(nil)
-	snapshotSvc := &SnapshotService{LedgerGetter: fakeLedgerGetter, ACLProvider: fakeACLProvider}
+	auditBuff := &bytes.Buffer{}
+	snapshotSvc := &SnapshotService{LedgerGetter: fakeLedgerGetter, ACLProvider: fakeACLProvider, AuditLogger: audit.NewLogger(auditBuff, nil)}
 
 	// test generate, cancel and query bindings
 	var signedRequest *pb.SignedSnapshotRequest
@@ -79,6 +82,16 @@ func TestSnapshot(t *testing.T) {
 	_, err = snapshotSvc.Cancel(context.Background(), signedRequest)
 	require.EqualError(t, err, "no snapshot request exists for block number 100")
 
+	// the requests and cancellations are audited, but the queries are not
+	lastRecord, err := audit.Verify(bytes.NewReader(auditBuff.Bytes()), "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), lastRecord.Sequence)
+	require.Equal(t, "snapshot", lastRecord.Component)
+	require.Equal(t, "Cancel", lastRecord.Action)
+	require.Equal(t, map[string]string{"channel": ledgerID, "blockNumber": "100"}, lastRecord.Parameters)
+	require.Equal(t, audit.OutcomeFailure, lastRecord.Outcome)
+	require.Equal(t, "no snapshot request exists for block number 100", lastRecord.Error)
+
 	// common error tests for all requests
 	tests := []struct {
 		name          string
@@ -0,0 +1,159 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package stateproof defines the proofs that a key of the public state of a
+// channel had a value, or did not exist, at a block, and verifies them without
+// access to the ledger.
+//
+// The public state is committed to by the root of a binary Merkle tree whose
+// leaves are the hashes of the keys and values of the state, positioned by the
+// bits of the hashes of the keys. A leaf is placed at the shallowest position
+// of the path of its key hash at which no other leaf shares the subtree, so the
+// tree is determined by the state alone. An empty subtree hashes to HashSize
+// zero bytes.
+package stateproof
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// HashSize is the size of the hashes of the tree.
+const HashSize = sha256.Size
+
+const (
+	leafPrefix     = byte(0x00)
+	internalPrefix = byte(0x01)
+)
+
+// EmptyHash returns the hash of an empty subtree.
+func EmptyHash() []byte {
+	return make([]byte, HashSize)
+}
+
+// KeyHash returns the hash which positions the key of the namespace in the tree.
+func KeyHash(namespace, key string) []byte {
+	h := sha256.New()
+	lenBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(lenBytes, uint64(len(namespace)))
+	h.Write(lenBytes)
+	h.Write([]byte(namespace))
+	h.Write([]byte(key))
+	return h.Sum(nil)
+}
+
+// ValueHash returns the hash of a value of the state.
+func ValueHash(value []byte) []byte {
+	h := sha256.Sum256(value)
+	return h[:]
+}
+
+// LeafHash returns the hash of the leaf of a key hash and a value hash.
+func LeafHash(keyHash, valueHash []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{leafPrefix})
+	h.Write(keyHash)
+	h.Write(valueHash)
+	return h.Sum(nil)
+}
+
+// InternalHash returns the hash of an internal node from the hashes of its children.
+func InternalHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write([]byte{internalPrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// KeyBit returns the bit of the key hash which selects the child of a node at
+// the supplied depth, 0 for the left child and 1 for the right child.
+func KeyBit(keyHash []byte, depth int) byte {
+	return (keyHash[depth/8] >> (7 - uint(depth%8))) & 1
+}
+
+// Proof proves that the key of the namespace had the value, or did not exist
+// if the value is empty, when the block BlockNum was committed to the ledger of
+// a channel. A proof only establishes the value of the key if the root is
+// trusted, for instance because peers of several organizations return the same
+// root for the block.
+type Proof struct {
+	BlockNum  uint64 `json:"block_num"`
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+	Value     []byte `json:"value,omitempty"`
+	// Root is the root of the tree at the block.
+	Root []byte `json:"root"`
+	// Siblings are the hashes of the siblings of the nodes on the path of the
+	// key, from the children of the root down to the leaf or empty subtree
+	// that ends the path.
+	Siblings [][]byte `json:"siblings"`
+	// Leaf ends the path of the key. It is the leaf of the key if it exists,
+	// or possibly the leaf of another key if it does not.
+	Leaf *Leaf `json:"leaf,omitempty"`
+}
+
+// Leaf is a leaf of the tree.
+type Leaf struct {
+	KeyHash   []byte `json:"key_hash"`
+	ValueHash []byte `json:"value_hash"`
+}
+
+// Verify checks that the proof leads from the value, or the absence, of the
+// key to the root.
+func (p *Proof) Verify() error {
+	keyHash := KeyHash(p.Namespace, p.Key)
+	if len(p.Siblings) >= HashSize*8 {
+		return errors.Errorf("proof has %d siblings, more than the depth of the tree", len(p.Siblings))
+	}
+	for _, sibling := range p.Siblings {
+		if len(sibling) != HashSize {
+			return errors.Errorf("proof has a sibling of %d bytes instead of %d", len(sibling), HashSize)
+		}
+	}
+
+	var hash []byte
+	switch {
+	case len(p.Value) != 0:
+		if p.Leaf == nil || !bytes.Equal(p.Leaf.KeyHash, keyHash) {
+			return errors.Errorf("proof of the value of key [%s] of namespace [%s] does not end with its leaf", p.Key, p.Namespace)
+		}
+		if !bytes.Equal(p.Leaf.ValueHash, ValueHash(p.Value)) {
+			return errors.Errorf("leaf of key [%s] of namespace [%s] does not match the value", p.Key, p.Namespace)
+		}
+		hash = LeafHash(p.Leaf.KeyHash, p.Leaf.ValueHash)
+	case p.Leaf == nil:
+		hash = EmptyHash()
+	default:
+		if bytes.Equal(p.Leaf.KeyHash, keyHash) {
+			return errors.Errorf("proof of the absence of key [%s] of namespace [%s] ends with its leaf", p.Key, p.Namespace)
+		}
+		if len(p.Leaf.KeyHash) != HashSize {
+			return errors.Errorf("proof has a leaf key hash of %d bytes instead of %d", len(p.Leaf.KeyHash), HashSize)
+		}
+		for depth := range p.Siblings {
+			if KeyBit(p.Leaf.KeyHash, depth) != KeyBit(keyHash, depth) {
+				return errors.Errorf("proof of the absence of key [%s] of namespace [%s] ends with a leaf off its path", p.Key, p.Namespace)
+			}
+		}
+		hash = LeafHash(p.Leaf.KeyHash, p.Leaf.ValueHash)
+	}
+
+	for depth := len(p.Siblings) - 1; depth >= 0; depth-- {
+		if KeyBit(keyHash, depth) == 0 {
+			hash = InternalHash(hash, p.Siblings[depth])
+		} else {
+			hash = InternalHash(p.Siblings[depth], hash)
+		}
+	}
+	if !bytes.Equal(hash, p.Root) {
+		return errors.Errorf("proof leads to root [%x] instead of [%x]", hash, p.Root)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package stateproof
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// twoLeafTree returns the root of the tree holding the two keys of the
+// namespace, whose key hashes differ in their first bit, and the proofs of the
+// key whose hash starts with a 0 bit and of the other key.
+func twoLeafTree(t *testing.T) (root []byte, left, right *Proof) {
+	keys := map[byte]string{}
+	for i := 0; len(keys) < 2; i++ {
+		key := string(rune('a' + i))
+		keys[KeyBit(KeyHash("ns", key), 0)] = key
+	}
+	leftLeaf := &Leaf{KeyHash: KeyHash("ns", keys[0]), ValueHash: ValueHash([]byte("value0"))}
+	rightLeaf := &Leaf{KeyHash: KeyHash("ns", keys[1]), ValueHash: ValueHash([]byte("value1"))}
+	leftHash := LeafHash(leftLeaf.KeyHash, leftLeaf.ValueHash)
+	rightHash := LeafHash(rightLeaf.KeyHash, rightLeaf.ValueHash)
+	root = InternalHash(leftHash, rightHash)
+
+	left = &Proof{Namespace: "ns", Key: keys[0], Value: []byte("value0"), Root: root, Siblings: [][]byte{rightHash}, Leaf: leftLeaf}
+	right = &Proof{Namespace: "ns", Key: keys[1], Value: []byte("value1"), Root: root, Siblings: [][]byte{leftHash}, Leaf: rightLeaf}
+	return root, left, right
+}
+
+func TestVerify(t *testing.T) {
+	root, left, right := twoLeafTree(t)
+	require.NoError(t, left.Verify())
+	require.NoError(t, right.Verify())
+
+	t.Run("empty tree", func(t *testing.T) {
+		proof := &Proof{Namespace: "ns", Key: "key", Root: EmptyHash()}
+		require.NoError(t, proof.Verify())
+		proof.Value = []byte("value")
+		require.EqualError(t, proof.Verify(), "proof of the value of key [key] of namespace [ns] does not end with its leaf")
+	})
+
+	t.Run("single leaf tree", func(t *testing.T) {
+		leaf := &Leaf{KeyHash: KeyHash("ns", "key"), ValueHash: ValueHash([]byte("value"))}
+		proof := &Proof{Namespace: "ns", Key: "key", Value: []byte("value"), Root: LeafHash(leaf.KeyHash, leaf.ValueHash), Leaf: leaf}
+		require.NoError(t, proof.Verify())
+
+		absence := &Proof{Namespace: "ns", Key: "other-key", Root: proof.Root, Leaf: leaf}
+		require.NoError(t, absence.Verify())
+	})
+
+	t.Run("wrong value", func(t *testing.T) {
+		proof := *left
+		proof.Value = []byte("value1")
+		require.EqualError(t, proof.Verify(), "leaf of key ["+left.Key+"] of namespace [ns] does not match the value")
+	})
+
+	t.Run("absence of an existing key", func(t *testing.T) {
+		proof := *left
+		proof.Value = nil
+		require.EqualError(t, proof.Verify(), "proof of the absence of key ["+left.Key+"] of namespace [ns] ends with its leaf")
+	})
+
+	t.Run("leaf off the path", func(t *testing.T) {
+		proof := *left
+		proof.Value = nil
+		proof.Leaf = right.Leaf
+		require.EqualError(t, proof.Verify(), "proof of the absence of key ["+left.Key+"] of namespace [ns] ends with a leaf off its path")
+	})
+
+	t.Run("wrong root", func(t *testing.T) {
+		proof := *left
+		proof.Root = EmptyHash()
+		require.Error(t, proof.Verify())
+		require.Contains(t, proof.Verify().Error(), "proof leads to root")
+	})
+
+	t.Run("wrong siblings", func(t *testing.T) {
+		proof := *left
+		proof.Siblings = [][]byte{EmptyHash()}
+		require.Error(t, proof.Verify())
+
+		proof.Siblings = [][]byte{[]byte("short")}
+		require.EqualError(t, proof.Verify(), "proof has a sibling of 5 bytes instead of 32")
+	})
+
+	t.Run("json encoding", func(t *testing.T) {
+		b, err := json.Marshal(left)
+		require.NoError(t, err)
+		proof := &Proof{}
+		require.NoError(t, json.Unmarshal(b, proof))
+		require.Equal(t, left, proof)
+		require.Equal(t, root, proof.Root)
+		require.NoError(t, proof.Verify())
+	})
+}
@@ -9,11 +9,13 @@ package operations
 import (
 	"context"
 	"net"
+	"net/http"
 	"strings"
 	"time"
 
 	kitstatsd "github.com/go-kit/kit/metrics/statsd"
 	"github.com/hyperledger/fabric-lib-go/healthz"
+	"github.com/hyperledger/fabric/common/audit"
 	"github.com/hyperledger/fabric/common/fabhttp"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/flogging/httpadmin"
@@ -23,6 +25,7 @@ import (
 	"github.com/hyperledger/fabric/common/metrics/prometheus"
 	"github.com/hyperledger/fabric/common/metrics/statsd"
 	"github.com/hyperledger/fabric/common/metrics/statsd/goruntime"
+	prom "github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
@@ -49,6 +52,8 @@ type Options struct {
 	fabhttp.Options
 	Metrics MetricsOptions
 	Version string
+	// AuditLogger records changes of the logging spec, or is nil if they are not audited
+	AuditLogger *audit.Logger
 }
 
 type System struct {
@@ -137,7 +142,7 @@ func (s *System) initializeMetricsProvider() error {
 		// responses:
 		//     '200':
 		//        description: Ok.
-		s.RegisterHandler("/metrics", promhttp.Handler(), s.options.TLS.Enabled)
+		s.RegisterHandler("/metrics", metricsHandler(), s.options.TLS.Enabled)
 		return nil
 
 	default:
@@ -154,20 +159,20 @@ func (s *System) initializeMetricsProvider() error {
 func (s *System) initializeLoggingHandler() {
 	// swagger:operation GET /logspec operations logspecget
 	// ---
-	// summary: Retrieves the active logging spec for a peer or orderer.
+	// summary: Retrieves the active logging spec and the specs of the log sinks for a peer or orderer.
 	// responses:
 	//     '200':
 	//        description: Ok.
 
 	// swagger:operation PUT /logspec operations logspecput
 	// ---
-	// summary: Updates the active logging spec for a peer or orderer.
+	// summary: Updates the active logging spec or the specs of the log sinks for a peer or orderer.
 	//
 	// parameters:
 	// - name: payload
 	//   in: formData
 	//   type: string
-	//   description: The payload must consist of a single attribute named spec.
+	//   description: The payload consists of an attribute named spec, an attribute named sinks, or both.
 	//   required: true
 	// responses:
 	//     '204':
@@ -176,7 +181,9 @@ func (s *System) initializeLoggingHandler() {
 	//        description: Bad request.
 	// consumes:
 	//   - multipart/form-data
-	s.RegisterHandler("/logspec", httpadmin.NewSpecHandler(), s.options.TLS.Enabled)
+	specHandler := httpadmin.NewSpecHandler()
+	specHandler.AuditLogger = s.options.AuditLogger
+	s.RegisterHandler("/logspec", specHandler, s.options.TLS.Enabled)
 }
 
 func (s *System) initializeHealthCheckHandler() {
@@ -230,3 +237,13 @@ func (s *System) startMetricsTickers() error {
 
 	return nil
 }
+
+// metricsHandler returns the handler of the prometheus metrics. It serves
+// the OpenMetrics format, which carries exemplars, to the scrapers that
+// request it, and the text format to the others.
+func metricsHandler() http.Handler {
+	return promhttp.InstrumentMetricHandler(
+		prom.DefaultRegisterer,
+		promhttp.HandlerFor(prom.DefaultGatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}),
+	)
+}
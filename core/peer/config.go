@@ -48,6 +48,18 @@ type ExternalBuilder struct {
 	Path                 string   `yaml:"path"`
 }
 
+// ClientLimits limits the requests of a client to a service. A zero value
+// disables the corresponding limit.
+type ClientLimits struct {
+	// Rate is the sustained number of requests per second.
+	Rate float64
+	// Burst is the number of requests which may momentarily exceed the rate.
+	// It defaults to the rate.
+	Burst int
+	// Concurrency is the number of concurrent requests.
+	Concurrency int
+}
+
 // Config is the struct that defines the Peer configurations.
 type Config struct {
 	// LocalMSPID is the identifier of the local MSP.
@@ -129,6 +141,34 @@ type Config struct {
 	// gateway service that handles the submission and evaluation of transactions.
 	LimitsConcurrencyGatewayService int
 
+	// LimitsIdentityEndorserService, LimitsIdentityDeliverService and LimitsIdentityGatewayService
+	// limit the requests of each client identity to the endorser, deliver and gateway services.
+	LimitsIdentityEndorserService ClientLimits
+	LimitsIdentityDeliverService  ClientLimits
+	LimitsIdentityGatewayService  ClientLimits
+
+	// LimitsMSPEndorserService, LimitsMSPDeliverService and LimitsMSPGatewayService limit the
+	// requests of all the client identities of each MSP to the endorser, deliver and gateway services.
+	LimitsMSPEndorserService ClientLimits
+	LimitsMSPDeliverService  ClientLimits
+	LimitsMSPGatewayService  ClientLimits
+
+	// ----- Endorsement cache -----
+	// The endorsement cache holds the simulation results of read-only proposals so that
+	// identical proposals at the same ledger height are endorsed without simulation.
+
+	// EndorsementCacheEnabled enables caching the results of read-only proposals.
+	EndorsementCacheEnabled bool
+	// EndorsementCacheMaxEntries sets the maximum number of cached proposal results.
+	EndorsementCacheMaxEntries int
+	// EndorsementCacheChaincodes restricts caching to the named chaincodes. When empty,
+	// the results of every application chaincode are cached.
+	EndorsementCacheChaincodes []string
+
+	// TransientStoreEncryptionEnabled enables the encryption of the private data held
+	// in the transient store with an AES key generated and kept by the BCCSP.
+	TransientStoreEncryptionEnabled bool
+
 	// ----- TLS -----
 	// Require server-side TLS.
 	// TODO: create separate sub-struct for PeerTLS config.
@@ -166,6 +206,15 @@ type Config struct {
 	// chaincode. The external builder detection processing will iterate over the
 	// builders in the order specified below.
 	ExternalBuilders []ExternalBuilder
+	// PackageSignaturesRequired enables the rejection of chaincode install
+	// packages which do not carry a valid signature from a trusted signer.
+	PackageSignaturesRequired bool
+	// PackageSignatureTrustedMSPs lists the MSP IDs whose members are trusted
+	// to sign chaincode install packages.
+	PackageSignatureTrustedMSPs []string
+	// PackageSignatureTrustedRoots provides the paths to PEM encoded CA
+	// certificates of signers trusted to sign chaincode install packages.
+	PackageSignatureTrustedRoots []string
 
 	// ----- Operations config -----
 	// TODO: create separate sub-struct for Operations config.
@@ -256,6 +305,21 @@ func (c *Config) load() error {
 	c.LimitsConcurrencyEndorserService = viper.GetInt("peer.limits.concurrency.endorserService")
 	c.LimitsConcurrencyDeliverService = viper.GetInt("peer.limits.concurrency.deliverService")
 	c.LimitsConcurrencyGatewayService = viper.GetInt("peer.limits.concurrency.gatewayService")
+	c.LimitsIdentityEndorserService = clientLimits("peer.limits.clients.identity.endorserService")
+	c.LimitsIdentityDeliverService = clientLimits("peer.limits.clients.identity.deliverService")
+	c.LimitsIdentityGatewayService = clientLimits("peer.limits.clients.identity.gatewayService")
+	c.LimitsMSPEndorserService = clientLimits("peer.limits.clients.msp.endorserService")
+	c.LimitsMSPDeliverService = clientLimits("peer.limits.clients.msp.deliverService")
+	c.LimitsMSPGatewayService = clientLimits("peer.limits.clients.msp.gatewayService")
+	c.EndorsementCacheEnabled = viper.GetBool("peer.endorsementCache.enabled")
+	c.EndorsementCacheMaxEntries = viper.GetInt("peer.endorsementCache.maxEntries")
+	if c.EndorsementCacheEnabled && c.EndorsementCacheMaxEntries <= 0 {
+		defaultMaxEntries := 10000
+		logger.Warningf("`peer.endorsementCache.maxEntries` not set; defaulting to %d", defaultMaxEntries)
+		c.EndorsementCacheMaxEntries = defaultMaxEntries
+	}
+	c.EndorsementCacheChaincodes = viper.GetStringSlice("peer.endorsementCache.chaincodes")
+	c.TransientStoreEncryptionEnabled = viper.GetBool("peer.transientStore.encryption.enabled")
 	c.DiscoveryEnabled = viper.GetBool("peer.discovery.enabled")
 	c.ProfileEnabled = viper.GetBool("peer.profile.enabled")
 	c.ProfileListenAddress = viper.GetString("peer.profile.listenAddress")
@@ -311,6 +375,12 @@ func (c *Config) load() error {
 		}
 	}
 
+	c.PackageSignaturesRequired = viper.GetBool("chaincode.packageSignatures.required")
+	c.PackageSignatureTrustedMSPs = viper.GetStringSlice("chaincode.packageSignatures.trustedMSPs")
+	for _, cert := range viper.GetStringSlice("chaincode.packageSignatures.trustedRoots") {
+		c.PackageSignatureTrustedRoots = append(c.PackageSignatureTrustedRoots, config.TranslatePath(configDir, cert))
+	}
+
 	c.OperationsListenAddress = viper.GetString("operations.listenAddress")
 	c.OperationsTLSEnabled = viper.GetBool("operations.tls.enabled")
 	c.OperationsTLSCertFile = config.GetPath("operations.tls.cert.file")
@@ -334,6 +404,14 @@ func (c *Config) load() error {
 	return nil
 }
 
+func clientLimits(key string) ClientLimits {
+	return ClientLimits{
+		Rate:        viper.GetFloat64(key + ".rate"),
+		Burst:       viper.GetInt(key + ".burst"),
+		Concurrency: viper.GetInt(key + ".concurrency"),
+	}
+}
+
 // getLocalAddress returns the address:port the local peer is operating on.  Affected by env:peer.addressAutoDetect
 func getLocalAddress() (string, error) {
 	peerAddress := viper.GetString("peer.address")
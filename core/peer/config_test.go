@@ -271,6 +271,13 @@ func TestGlobalConfig(t *testing.T) {
 	viper.Set("peer.limits.concurrency.endorserService", 2500)
 	viper.Set("peer.limits.concurrency.deliverService", 2500)
 	viper.Set("peer.limits.concurrency.gatewayService", 500)
+	viper.Set("peer.limits.clients.identity.endorserService.rate", 10.5)
+	viper.Set("peer.limits.clients.identity.endorserService.burst", 20)
+	viper.Set("peer.limits.clients.msp.deliverService.concurrency", 100)
+	viper.Set("peer.endorsementCache.enabled", true)
+	viper.Set("peer.endorsementCache.maxEntries", 500)
+	viper.Set("peer.endorsementCache.chaincodes", []string{"basic"})
+	viper.Set("peer.transientStore.encryption.enabled", true)
 	viper.Set("peer.discovery.enabled", true)
 	viper.Set("peer.profile.enabled", false)
 	viper.Set("peer.profile.listenAddress", "peer.authentication.timewindow")
@@ -318,6 +325,10 @@ func TestGlobalConfig(t *testing.T) {
 		},
 	})
 
+	viper.Set("chaincode.packageSignatures.required", true)
+	viper.Set("chaincode.packageSignatures.trustedMSPs", []string{"Org1MSP"})
+	viper.Set("chaincode.packageSignatures.trustedRoots", []string{"relative/ca.pem", "/absolute/ca.pem"})
+
 	coreConfig, err := GlobalConfig()
 	require.NoError(t, err)
 
@@ -332,6 +343,12 @@ func TestGlobalConfig(t *testing.T) {
 		LimitsConcurrencyEndorserService:      2500,
 		LimitsConcurrencyDeliverService:       2500,
 		LimitsConcurrencyGatewayService:       500,
+		LimitsIdentityEndorserService:         ClientLimits{Rate: 10.5, Burst: 20},
+		LimitsMSPDeliverService:               ClientLimits{Concurrency: 100},
+		EndorsementCacheEnabled:               true,
+		EndorsementCacheMaxEntries:            500,
+		EndorsementCacheChaincodes:            []string{"basic"},
+		TransientStoreEncryptionEnabled:       true,
 		DiscoveryEnabled:                      true,
 		ProfileEnabled:                        false,
 		ProfileListenAddress:                  "peer.authentication.timewindow",
@@ -360,6 +377,12 @@ func TestGlobalConfig(t *testing.T) {
 				Name: "absolute",
 			},
 		},
+		PackageSignaturesRequired:   true,
+		PackageSignatureTrustedMSPs: []string{"Org1MSP"},
+		PackageSignatureTrustedRoots: []string{
+			filepath.Join(cwd, "relative", "ca.pem"),
+			"/absolute/ca.pem",
+		},
 		OperationsListenAddress:         "127.0.0.1:9443",
 		OperationsTLSEnabled:            false,
 		OperationsTLSCertFile:           filepath.Join(cwd, "test/tls/cert/file"),
@@ -410,6 +433,18 @@ func TestGlobalConfigDefault(t *testing.T) {
 	require.Equal(t, expectedConfig, coreConfig)
 }
 
+func TestGlobalConfigEndorsementCacheDefaults(t *testing.T) {
+	defer viper.Reset()
+	viper.Set("peer.address", "localhost:8080")
+	viper.Set("peer.endorsementCache.enabled", true)
+
+	coreConfig, err := GlobalConfig()
+	require.NoError(t, err)
+	require.True(t, coreConfig.EndorsementCacheEnabled)
+	require.Equal(t, 10000, coreConfig.EndorsementCacheMaxEntries)
+	require.Empty(t, coreConfig.EndorsementCacheChaincodes)
+}
+
 func TestPropagateEnvironment(t *testing.T) {
 	defer viper.Reset()
 	viper.Set("peer.address", "localhost:8080")
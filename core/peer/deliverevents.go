@@ -16,6 +16,7 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/aclmgmt/resources"
 	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/common/redaction"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/internal/pkg/txflags"
 	"github.com/hyperledger/fabric/msp"
@@ -36,6 +37,9 @@ type DeliverServer struct {
 	PolicyCheckerProvider   PolicyCheckerProvider
 	CollectionPolicyChecker CollectionPolicyChecker
 	IdentityDeserializerMgr IdentityDeserializerManager
+	// ACLProvider, when set, lets Deliver serve redacted blocks to the
+	// clients which satisfy the event/RedactedBlock but not the event/Block ACL
+	ACLProvider redaction.ACLProvider
 }
 
 // Chain adds Ledger() to deliver.Chain
@@ -59,6 +63,7 @@ type IdentityDeserializerManager interface {
 // blockResponseSender structure used to send block responses
 type blockResponseSender struct {
 	peer.Deliver_DeliverServer
+	aclProvider redaction.ACLProvider
 }
 
 // SendStatusResponse generates status reply proto message
@@ -76,7 +81,12 @@ func (brs *blockResponseSender) SendBlockResponse(
 	chain deliver.Chain,
 	signedData *protoutil.SignedData,
 ) error {
-	// Generates filtered block response
+	if brs.aclProvider != nil {
+		if err := brs.aclProvider.CheckACL(resources.Event_Block, channelID, signedData); err != nil {
+			logger.Debugf("Sending redacted block %d on channel %s: %s", block.Header.Number, channelID, err)
+			block = redaction.Block(block, redaction.ACLFilter(brs.aclProvider, channelID, signedData))
+		}
+	}
 	response := &peer.DeliverResponse{
 		Type: &peer.DeliverResponse_Block{Block: block},
 	}
@@ -255,16 +265,33 @@ func (s *DeliverServer) Deliver(srv peer.Deliver_DeliverServer) (err error) {
 	logger.Debugf("Starting new Deliver handler")
 	defer dumpStacktraceOnPanic()
 	// getting policy checker based on resources.Event_Block resource name
+	policyChecker := s.PolicyCheckerProvider(resources.Event_Block)
+	if s.ACLProvider != nil {
+		policyChecker = anyPolicyChecker(policyChecker, s.PolicyCheckerProvider(resources.Event_RedactedBlock))
+	}
 	deliverServer := &deliver.Server{
-		PolicyChecker: s.PolicyCheckerProvider(resources.Event_Block),
+		PolicyChecker: policyChecker,
 		Receiver:      srv,
 		ResponseSender: &blockResponseSender{
 			Deliver_DeliverServer: srv,
+			aclProvider:           s.ACLProvider,
 		},
 	}
 	return s.DeliverHandler.Handle(srv.Context(), deliverServer)
 }
 
+// anyPolicyChecker returns a policy checker which accepts the envelopes
+// accepted by the first or the second policy checker.
+func anyPolicyChecker(first, second deliver.PolicyCheckerFunc) deliver.PolicyCheckerFunc {
+	return func(env *common.Envelope, channelID string) error {
+		err := first(env, channelID)
+		if err == nil || second(env, channelID) == nil {
+			return nil
+		}
+		return err
+	}
+}
+
 // DeliverWithPrivateData sends a stream of blocks and pvtdata to a client after commitment
 func (s *DeliverServer) DeliverWithPrivateData(srv peer.Deliver_DeliverWithPrivateDataServer) (err error) {
 	logger.Debug("Starting new DeliverWithPrivateData handler")
@@ -24,10 +24,12 @@ import (
 	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	fake "github.com/hyperledger/fabric/core/peer/mock"
 	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/metadata"
@@ -734,3 +736,78 @@ func produceSamplePvtdataOrPanic(txNum uint64, nsColls []string) *ledger.TxPvtDa
 	}
 	return &ledger.TxPvtData{SeqInBlock: txNum, WriteSet: simRes.PvtSimulationResults}
 }
+
+type fakeACLProvider map[string]error
+
+func (f fakeACLProvider) CheckACL(resName string, channelID string, idinfo interface{}) error {
+	return f[resName]
+}
+
+func TestBlockResponseSenderRedaction(t *testing.T) {
+	chaincodeActionPayload, err := createChaincodeAction("mycc", "testEvent", "testID")
+	require.NoError(t, err)
+	payload, err := createEndorsement("testChannelID", "testID", chaincodeActionPayload)
+	require.NoError(t, err)
+	block, err := createTestBlock([]*common.Envelope{{Payload: protoutil.MarshalOrPanic(payload)}})
+	require.NoError(t, err)
+
+	sendBlock := func(aclProvider fakeACLProvider) *common.Block {
+		deliverServer := &mockDeliverServer{}
+		var sent *peer.DeliverResponse
+		deliverServer.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+			sent = args.Get(0).(*peer.DeliverResponse)
+		}).Return(nil)
+
+		brs := &blockResponseSender{Deliver_DeliverServer: deliverServer}
+		if aclProvider != nil {
+			brs.aclProvider = aclProvider
+		}
+		err := brs.SendBlockResponse(block, "testChannelID", nil, &protoutil.SignedData{})
+		require.NoError(t, err)
+		return sent.GetBlock()
+	}
+
+	eventsOf := func(b *common.Block) []byte {
+		env, err := protoutil.GetEnvelopeFromBlock(b.Data.Data[0])
+		require.NoError(t, err)
+		p, err := protoutil.UnmarshalPayload(env.Payload)
+		require.NoError(t, err)
+		tx, err := protoutil.UnmarshalTransaction(p.Data)
+		require.NoError(t, err)
+		_, ccAction, err := protoutil.GetPayloads(tx.Actions[0])
+		require.NoError(t, err)
+		return ccAction.Events
+	}
+
+	t.Run("NoACLProvider", func(t *testing.T) {
+		require.Same(t, block, sendBlock(nil))
+	})
+
+	t.Run("FullBlockAllowed", func(t *testing.T) {
+		require.Same(t, block, sendBlock(fakeACLProvider{}))
+	})
+
+	t.Run("NamespaceAllowed", func(t *testing.T) {
+		sent := sendBlock(fakeACLProvider{resources.Event_Block: errors.New("denied")})
+		require.NotSame(t, block, sent)
+		require.NotEmpty(t, eventsOf(sent))
+	})
+
+	t.Run("NamespaceNotAllowed", func(t *testing.T) {
+		sent := sendBlock(fakeACLProvider{
+			resources.Event_Block:           errors.New("denied"),
+			resources.NamespaceRead("mycc"): errors.New("denied"),
+		})
+		require.True(t, proto.Equal(block.Header, sent.Header))
+		require.Empty(t, eventsOf(sent))
+	})
+}
+
+func TestAnyPolicyChecker(t *testing.T) {
+	accept := func(*common.Envelope, string) error { return nil }
+	reject := func(*common.Envelope, string) error { return errors.New("rejected") }
+
+	require.NoError(t, anyPolicyChecker(accept, reject)(nil, "testChannelID"))
+	require.NoError(t, anyPolicyChecker(reject, accept)(nil, "testChannelID"))
+	require.EqualError(t, anyPolicyChecker(reject, reject)(nil, "testChannelID"), "rejected")
+}
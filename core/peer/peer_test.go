@@ -97,7 +97,7 @@ func NewTestPeer(t *testing.T) (*Peer, func()) {
 
 	require.NoError(t, err)
 	transientStoreProvider, err := transientstore.NewStoreProvider(
-		filepath.Join(tempdir, "transientstore"),
+		&transientstore.Config{Path: filepath.Join(tempdir, "transientstore")},
 	)
 	require.NoError(t, err)
 	peerInstance := &Peer{
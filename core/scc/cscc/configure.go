@@ -19,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric-protos-go/common"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/common/audit"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/aclmgmt"
@@ -64,6 +65,9 @@ type PeerConfiger struct {
 	newLifecycle           plugindispatcher.CollectionAndLifecycleResources
 	peer                   *peer.Peer
 	bccsp                  bccsp.BCCSP
+
+	// AuditLogger records the attempts to join channels
+	AuditLogger *audit.Logger
 }
 
 var cnflogger = flogging.MustGetLogger("cscc")
@@ -129,6 +133,35 @@ func (e *PeerConfiger) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 }
 
 func (e *PeerConfiger) InvokeNoShim(args [][]byte, sp *pb.SignedProposal) pb.Response {
+	res := e.invoke(args, sp)
+
+	switch fname := string(args[0]); fname {
+	case JoinChain, JoinChainBySnapshot:
+		e.audit(fname, args[1], sp, res)
+	}
+
+	return res
+}
+
+// audit records an attempt to join a channel along with its outcome.
+func (e *PeerConfiger) audit(fname string, arg []byte, sp *pb.SignedProposal, res pb.Response) {
+	params := map[string]string{}
+	if fname == JoinChainBySnapshot {
+		params["snapshotDir"] = string(arg)
+	} else if block, err := protoutil.UnmarshalBlock(arg); err == nil {
+		if cid, err := protoutil.GetChannelIDFromBlock(block); err == nil {
+			params["channel"] = cid
+		}
+	}
+
+	var err error
+	if res.Status != shim.OK {
+		err = errors.New(res.Message)
+	}
+	e.AuditLogger.Log("cscc", fname, audit.CallerFromSignedProposal(sp), params, err)
+}
+
+func (e *PeerConfiger) invoke(args [][]byte, sp *pb.SignedProposal) pb.Response {
 	var err error
 	fname := string(args[0])
 
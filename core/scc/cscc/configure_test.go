@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package cscc
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"net"
 	"os"
@@ -18,6 +20,7 @@ import (
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/audit"
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/genesis"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
@@ -366,6 +369,9 @@ func TestConfigerInvokeJoinChainBySnapshot(t *testing.T) {
 	go grpcServer.Serve(listener)
 	defer grpcServer.Stop()
 
+	auditBuff := &bytes.Buffer{}
+	cscc.AuditLogger = audit.NewLogger(auditBuff, nil)
+
 	channelID := "testjoinchainbysnapshot"
 	sProp := validSignedProposal()
 	sProp.Signature = sProp.ProposalBytes
@@ -429,6 +435,25 @@ func TestConfigerInvokeJoinChainBySnapshot(t *testing.T) {
 	res = cscc.Invoke(mockStub)
 	require.Equal(t, int32(shim.ERROR), res.Status)
 	require.Contains(t, res.Message, "access denied for [JoinChainBySnapshot]")
+
+	// verify the join attempts are audited
+	_, err = audit.Verify(bytes.NewReader(auditBuff.Bytes()), "")
+	require.NoError(t, err)
+	var records []audit.Record
+	for _, line := range bytes.Split(bytes.TrimSpace(auditBuff.Bytes()), []byte("\n")) {
+		record := audit.Record{}
+		require.NoError(t, json.Unmarshal(line, &record))
+		records = append(records, record)
+	}
+	require.Len(t, records, 3)
+	for i, outcome := range []string{audit.OutcomeSuccess, audit.OutcomeFailure, audit.OutcomeFailure} {
+		require.Equal(t, "cscc", records[i].Component)
+		require.Equal(t, JoinChainBySnapshot, records[i].Action)
+		require.Equal(t, outcome, records[i].Outcome)
+	}
+	require.Equal(t, map[string]string{"snapshotDir": snapshotDir}, records[0].Parameters)
+	require.Equal(t, map[string]string{"snapshotDir": "invalid-snapshot"}, records[1].Parameters)
+	require.Contains(t, records[2].Error, "access denied for [JoinChainBySnapshot]")
 }
 
 func TestConfigerInvokeGetChannelConfig(t *testing.T) {
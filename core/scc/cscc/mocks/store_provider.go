@@ -12,6 +12,16 @@ type StoreProvider struct {
 	closeMutex       sync.RWMutex
 	closeArgsForCall []struct {
 	}
+	EncryptionKeySKIStub        func() []byte
+	encryptionKeySKIMutex       sync.RWMutex
+	encryptionKeySKIArgsForCall []struct {
+	}
+	encryptionKeySKIReturns struct {
+		result1 []byte
+	}
+	encryptionKeySKIReturnsOnCall map[int]struct {
+		result1 []byte
+	}
 	OpenStoreStub        func(string) (*transientstore.Store, error)
 	openStoreMutex       sync.RWMutex
 	openStoreArgsForCall []struct {
@@ -25,6 +35,28 @@ type StoreProvider struct {
 		result1 *transientstore.Store
 		result2 error
 	}
+	RotateEncryptionKeyStub        func() ([]byte, error)
+	rotateEncryptionKeyMutex       sync.RWMutex
+	rotateEncryptionKeyArgsForCall []struct {
+	}
+	rotateEncryptionKeyReturns struct {
+		result1 []byte
+		result2 error
+	}
+	rotateEncryptionKeyReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
+	StatsStub        func() []transientstore.Stats
+	statsMutex       sync.RWMutex
+	statsArgsForCall []struct {
+	}
+	statsReturns struct {
+		result1 []transientstore.Stats
+	}
+	statsReturnsOnCall map[int]struct {
+		result1 []transientstore.Stats
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -52,6 +84,58 @@ func (fake *StoreProvider) CloseCalls(stub func()) {
 	fake.CloseStub = stub
 }
 
+func (fake *StoreProvider) EncryptionKeySKI() []byte {
+	fake.encryptionKeySKIMutex.Lock()
+	ret, specificReturn := fake.encryptionKeySKIReturnsOnCall[len(fake.encryptionKeySKIArgsForCall)]
+	fake.encryptionKeySKIArgsForCall = append(fake.encryptionKeySKIArgsForCall, struct {
+	}{})
+	fake.recordInvocation("EncryptionKeySKI", []interface{}{})
+	fake.encryptionKeySKIMutex.Unlock()
+	if fake.EncryptionKeySKIStub != nil {
+		return fake.EncryptionKeySKIStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.encryptionKeySKIReturns
+	return fakeReturns.result1
+}
+
+func (fake *StoreProvider) EncryptionKeySKICallCount() int {
+	fake.encryptionKeySKIMutex.RLock()
+	defer fake.encryptionKeySKIMutex.RUnlock()
+	return len(fake.encryptionKeySKIArgsForCall)
+}
+
+func (fake *StoreProvider) EncryptionKeySKICalls(stub func() []byte) {
+	fake.encryptionKeySKIMutex.Lock()
+	defer fake.encryptionKeySKIMutex.Unlock()
+	fake.EncryptionKeySKIStub = stub
+}
+
+func (fake *StoreProvider) EncryptionKeySKIReturns(result1 []byte) {
+	fake.encryptionKeySKIMutex.Lock()
+	defer fake.encryptionKeySKIMutex.Unlock()
+	fake.EncryptionKeySKIStub = nil
+	fake.encryptionKeySKIReturns = struct {
+		result1 []byte
+	}{result1}
+}
+
+func (fake *StoreProvider) EncryptionKeySKIReturnsOnCall(i int, result1 []byte) {
+	fake.encryptionKeySKIMutex.Lock()
+	defer fake.encryptionKeySKIMutex.Unlock()
+	fake.EncryptionKeySKIStub = nil
+	if fake.encryptionKeySKIReturnsOnCall == nil {
+		fake.encryptionKeySKIReturnsOnCall = make(map[int]struct {
+			result1 []byte
+		})
+	}
+	fake.encryptionKeySKIReturnsOnCall[i] = struct {
+		result1 []byte
+	}{result1}
+}
+
 func (fake *StoreProvider) OpenStore(arg1 string) (*transientstore.Store, error) {
 	fake.openStoreMutex.Lock()
 	ret, specificReturn := fake.openStoreReturnsOnCall[len(fake.openStoreArgsForCall)]
@@ -115,13 +199,126 @@ func (fake *StoreProvider) OpenStoreReturnsOnCall(i int, result1 *transientstore
 	}{result1, result2}
 }
 
+func (fake *StoreProvider) RotateEncryptionKey() ([]byte, error) {
+	fake.rotateEncryptionKeyMutex.Lock()
+	ret, specificReturn := fake.rotateEncryptionKeyReturnsOnCall[len(fake.rotateEncryptionKeyArgsForCall)]
+	fake.rotateEncryptionKeyArgsForCall = append(fake.rotateEncryptionKeyArgsForCall, struct {
+	}{})
+	fake.recordInvocation("RotateEncryptionKey", []interface{}{})
+	fake.rotateEncryptionKeyMutex.Unlock()
+	if fake.RotateEncryptionKeyStub != nil {
+		return fake.RotateEncryptionKeyStub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.rotateEncryptionKeyReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *StoreProvider) RotateEncryptionKeyCallCount() int {
+	fake.rotateEncryptionKeyMutex.RLock()
+	defer fake.rotateEncryptionKeyMutex.RUnlock()
+	return len(fake.rotateEncryptionKeyArgsForCall)
+}
+
+func (fake *StoreProvider) RotateEncryptionKeyCalls(stub func() ([]byte, error)) {
+	fake.rotateEncryptionKeyMutex.Lock()
+	defer fake.rotateEncryptionKeyMutex.Unlock()
+	fake.RotateEncryptionKeyStub = stub
+}
+
+func (fake *StoreProvider) RotateEncryptionKeyReturns(result1 []byte, result2 error) {
+	fake.rotateEncryptionKeyMutex.Lock()
+	defer fake.rotateEncryptionKeyMutex.Unlock()
+	fake.RotateEncryptionKeyStub = nil
+	fake.rotateEncryptionKeyReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StoreProvider) RotateEncryptionKeyReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.rotateEncryptionKeyMutex.Lock()
+	defer fake.rotateEncryptionKeyMutex.Unlock()
+	fake.RotateEncryptionKeyStub = nil
+	if fake.rotateEncryptionKeyReturnsOnCall == nil {
+		fake.rotateEncryptionKeyReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.rotateEncryptionKeyReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *StoreProvider) Stats() []transientstore.Stats {
+	fake.statsMutex.Lock()
+	ret, specificReturn := fake.statsReturnsOnCall[len(fake.statsArgsForCall)]
+	fake.statsArgsForCall = append(fake.statsArgsForCall, struct {
+	}{})
+	fake.recordInvocation("Stats", []interface{}{})
+	fake.statsMutex.Unlock()
+	if fake.StatsStub != nil {
+		return fake.StatsStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.statsReturns
+	return fakeReturns.result1
+}
+
+func (fake *StoreProvider) StatsCallCount() int {
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
+	return len(fake.statsArgsForCall)
+}
+
+func (fake *StoreProvider) StatsCalls(stub func() []transientstore.Stats) {
+	fake.statsMutex.Lock()
+	defer fake.statsMutex.Unlock()
+	fake.StatsStub = stub
+}
+
+func (fake *StoreProvider) StatsReturns(result1 []transientstore.Stats) {
+	fake.statsMutex.Lock()
+	defer fake.statsMutex.Unlock()
+	fake.StatsStub = nil
+	fake.statsReturns = struct {
+		result1 []transientstore.Stats
+	}{result1}
+}
+
+func (fake *StoreProvider) StatsReturnsOnCall(i int, result1 []transientstore.Stats) {
+	fake.statsMutex.Lock()
+	defer fake.statsMutex.Unlock()
+	fake.StatsStub = nil
+	if fake.statsReturnsOnCall == nil {
+		fake.statsReturnsOnCall = make(map[int]struct {
+			result1 []transientstore.Stats
+		})
+	}
+	fake.statsReturnsOnCall[i] = struct {
+		result1 []transientstore.Stats
+	}{result1}
+}
+
 func (fake *StoreProvider) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.closeMutex.RLock()
 	defer fake.closeMutex.RUnlock()
+	fake.encryptionKeySKIMutex.RLock()
+	defer fake.encryptionKeySKIMutex.RUnlock()
 	fake.openStoreMutex.RLock()
 	defer fake.openStoreMutex.RUnlock()
+	fake.rotateEncryptionKeyMutex.RLock()
+	defer fake.rotateEncryptionKeyMutex.RUnlock()
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
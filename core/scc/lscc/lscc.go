@@ -168,6 +168,11 @@ type SCC struct {
 	BCCSP bccsp.BCCSP
 
 	PackageCache PackageCache
+
+	// PackageVerifier, when set, verifies chaincode deployment specs and
+	// their signatures before they are installed, as for the packages
+	// installed with _lifecycle.
+	PackageVerifier lifecycle.PackageVerifier
 }
 
 // PeerShim adapts the peer instance for use with LSCC by providing methods
@@ -678,6 +683,12 @@ func isValidStatedbArtifactsTar(statedbArtifactsTar []byte) error {
 
 // executeInstall implements the "install" Invoke transaction
 func (lscc *SCC) executeInstall(stub shim.ChaincodeStubInterface, ccbytes []byte) error {
+	if lscc.PackageVerifier != nil {
+		if err := lifecycle.VerifyInstallPackage(lscc.PackageVerifier, stub, ccbytes); err != nil {
+			return errors.WithMessage(err, "chaincode install package rejected")
+		}
+	}
+
 	ccpack, err := ccprovider.GetCCPackage(ccbytes, lscc.BCCSP)
 	if err != nil {
 		return err
@@ -29,6 +29,8 @@ import (
 	"github.com/hyperledger/fabric/core/aclmgmt/mocks"
 	"github.com/hyperledger/fabric/core/aclmgmt/resources"
 	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	lifecyclemock "github.com/hyperledger/fabric/core/chaincode/lifecycle/mock"
+	"github.com/hyperledger/fabric/core/chaincode/persistence"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/container"
@@ -246,6 +248,85 @@ func testInstall(t *testing.T, ccname string, version string, path string, creat
 	})
 }
 
+func TestInstallPackageVerifier(t *testing.T) {
+	tempdir := t.TempDir()
+
+	initializer := ledgermgmttest.NewInitializer(tempdir)
+
+	ledgerMgr := ledgermgmt.NewLedgerMgr(initializer)
+	defer ledgerMgr.Close()
+
+	chaincodeBuilder := &mock.ChaincodeBuilder{}
+	packageVerifier := &lifecyclemock.PackageVerifier{}
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	scc := &SCC{
+		BuiltinSCCs:      map[string]struct{}{"lscc": {}},
+		Support:          &MockSupport{},
+		ACLProvider:      mockAclProvider,
+		GetMSPIDs:        getMSPIDs,
+		GetMSPManager:    getMSPManager,
+		BCCSP:            cryptoProvider,
+		BuildRegistry:    &container.BuildRegistry{},
+		ChaincodeBuilder: chaincodeBuilder,
+		EbMetadataProvider: &externalbuilder.MetadataProvider{
+			DurablePath: "testdata",
+		},
+		PackageVerifier: packageVerifier,
+	}
+	stub := shimtest.NewMockStub("lscc", scc)
+	res := stub.MockInit("1", nil)
+	require.Equal(t, int32(shim.OK), res.Status, res.Message)
+
+	cds, err := constructDeploymentSpec("example02", "mychaincode", "0", [][]byte{[]byte("init")}, false, false, scc)
+	require.NoError(t, err)
+	cdsBytes := protoutil.MarshalOrPanic(cds)
+	args := [][]byte{[]byte("install"), cdsBytes}
+	sProp, _ := protoutil.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
+	mockAclProvider.Reset()
+	mockAclProvider.On("CheckACL", resources.Lscc_Install, "", sProp).Return(nil)
+
+	t.Run("rejected", func(t *testing.T) {
+		packageVerifier.VerifyPackageReturns(errors.New("chaincode package is not signed"))
+		stub.TransientMap = nil
+
+		res := stub.MockInvokeWithSignedProposal("1", args, sProp)
+		require.Equal(t, int32(shim.ERROR), res.Status)
+		require.Equal(t, "chaincode install package rejected: chaincode package is not signed", res.Message)
+		require.Equal(t, 0, chaincodeBuilder.BuildCallCount())
+
+		pkg, signatures := packageVerifier.VerifyPackageArgsForCall(0)
+		require.Equal(t, cdsBytes, pkg)
+		require.Empty(t, signatures)
+	})
+
+	t.Run("unparsable signatures", func(t *testing.T) {
+		stub.TransientMap = map[string][]byte{persistence.SignaturesTransientKey: []byte("garbage")}
+
+		res := stub.MockInvokeWithSignedProposal("1", args, sProp)
+		require.Equal(t, int32(shim.ERROR), res.Status)
+		require.Contains(t, res.Message, "chaincode install package rejected: could not unmarshal package signatures as json")
+		require.Equal(t, 0, chaincodeBuilder.BuildCallCount())
+	})
+
+	t.Run("verified", func(t *testing.T) {
+		packageVerifier.VerifyPackageReturns(nil)
+		signature := &persistence.PackageSignature{Signer: []byte("signer"), Signature: []byte("signature")}
+		signaturesBytes, err := (&persistence.PackageSignatures{Signatures: []*persistence.PackageSignature{signature}}).Bytes()
+		require.NoError(t, err)
+		stub.TransientMap = map[string][]byte{persistence.SignaturesTransientKey: signaturesBytes}
+
+		res := stub.MockInvokeWithSignedProposal("1", args, sProp)
+		require.Equal(t, int32(shim.OK), res.Status, res.Message)
+		require.Equal(t, 1, chaincodeBuilder.BuildCallCount())
+
+		pkg, signatures := packageVerifier.VerifyPackageArgsForCall(packageVerifier.VerifyPackageCallCount() - 1)
+		require.Equal(t, cdsBytes, pkg)
+		require.Equal(t, []*persistence.PackageSignature{signature}, signatures)
+	})
+}
+
 func TestNewLifecycleEnabled(t *testing.T) {
 	// Enable PrivateChannelData
 	capabilities := &mock.ApplicationCapabilities{}
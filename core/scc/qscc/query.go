@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package qscc
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 
@@ -14,6 +15,8 @@ import (
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/aclmgmt"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/common/redaction"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/protoutil"
 )
@@ -40,6 +43,7 @@ func (e *LedgerQuerier) Chaincode() shim.Chaincode { return e }
 // - GetBlockByNumber returns a block
 // - GetBlockByHash returns a block
 // - GetTransactionByID returns a transaction
+// - GetStateProof returns the value of a key with a proof against the state commitment
 type LedgerQuerier struct {
 	aclProvider aclmgmt.ACLProvider
 	ledgers     LedgerGetter
@@ -54,6 +58,7 @@ const (
 	GetBlockByHash     string = "GetBlockByHash"
 	GetTransactionByID string = "GetTransactionByID"
 	GetBlockByTxID     string = "GetBlockByTxID"
+	GetStateProof      string = "GetStateProof"
 )
 
 // Init is called once per chain when the chain is created.
@@ -72,6 +77,9 @@ func (e *LedgerQuerier) Init(stub shim.ChaincodeStubInterface) pb.Response {
 // # GetBlockByNumber: Return the block specified by block number in args[2]
 // # GetBlockByHash: Return the block specified by block hash in args[2]
 // # GetTransactionByID: Return the transaction specified by ID in args[2]
+// # GetStateProof: Return the JSON encoded proof of the value of the key in args[3]
+// of the namespace in args[2] as of the block number in args[4], or as of the
+// last block if args[4] is omitted
 func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	args := stub.GetArgs()
 
@@ -108,28 +116,44 @@ func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	qscclogger.Debugf("Invoke function: %s on chain: %s", fname, cid)
 
 	// Handle ACL:
+	// callers which may not read whole blocks and transactions may still be
+	// granted a redacted view, limited to the namespaces they may read
+	var redact redaction.NamespaceFilter
 	res := getACLResource(fname)
 	if err = e.aclProvider.CheckACL(res, cid, sp); err != nil {
-		return shim.Error(fmt.Sprintf("access denied for [%s][%s]: [%s]", fname, cid, err))
+		if !isRedactable(fname) || e.aclProvider.CheckACL(resources.Qscc_RedactedBlocks, cid, sp) != nil {
+			return shim.Error(fmt.Sprintf("access denied for [%s][%s]: [%s]", fname, cid, err))
+		}
+		qscclogger.Debugf("Serving redacted results of %s on chain %s: %s", fname, cid, err)
+		redact = redaction.ACLFilter(e.aclProvider, cid, sp)
 	}
 
 	switch fname {
 	case GetTransactionByID:
-		return getTransactionByID(targetLedger, args[2])
+		return getTransactionByID(targetLedger, args[2], redact)
 	case GetBlockByNumber:
-		return getBlockByNumber(targetLedger, args[2])
+		return getBlockByNumber(targetLedger, args[2], redact)
 	case GetBlockByHash:
-		return getBlockByHash(targetLedger, args[2])
+		return getBlockByHash(targetLedger, args[2], redact)
 	case GetChainInfo:
 		return getChainInfo(targetLedger)
 	case GetBlockByTxID:
-		return getBlockByTxID(targetLedger, args[2])
+		return getBlockByTxID(targetLedger, args[2], redact)
+	case GetStateProof:
+		// the value of a key discloses as much as the blocks which wrote it,
+		// so callers which may not read whole blocks must be granted the read
+		// ACL of its namespace
+		var allowed redaction.NamespaceFilter
+		if e.aclProvider.CheckACL(resources.Qscc_GetBlockByNumber, cid, sp) != nil {
+			allowed = redaction.ACLFilter(e.aclProvider, cid, sp)
+		}
+		return getStateProof(targetLedger, args[2:], allowed, cid)
 	}
 
 	return shim.Error(fmt.Sprintf("Requested function %s not found.", fname))
 }
 
-func getTransactionByID(vledger ledger.PeerLedger, tid []byte) pb.Response {
+func getTransactionByID(vledger ledger.PeerLedger, tid []byte, redact redaction.NamespaceFilter) pb.Response {
 	if tid == nil {
 		return shim.Error("Transaction ID must not be nil.")
 	}
@@ -138,6 +162,16 @@ func getTransactionByID(vledger ledger.PeerLedger, tid []byte) pb.Response {
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Failed to get transaction with id %s, error %s", string(tid), err))
 	}
+	if redact != nil && processedTran.TransactionEnvelope != nil {
+		env, err := redaction.Envelope(processedTran.TransactionEnvelope, redact)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to redact transaction with id %s, error %s", string(tid), err))
+		}
+		processedTran = &pb.ProcessedTransaction{
+			TransactionEnvelope: env,
+			ValidationCode:      processedTran.ValidationCode,
+		}
+	}
 
 	bytes, err := protoutil.Marshal(processedTran)
 	if err != nil {
@@ -147,7 +181,7 @@ func getTransactionByID(vledger ledger.PeerLedger, tid []byte) pb.Response {
 	return shim.Success(bytes)
 }
 
-func getBlockByNumber(vledger ledger.PeerLedger, number []byte) pb.Response {
+func getBlockByNumber(vledger ledger.PeerLedger, number []byte, redact redaction.NamespaceFilter) pb.Response {
 	if number == nil {
 		return shim.Error("Block number must not be nil.")
 	}
@@ -164,6 +198,10 @@ func getBlockByNumber(vledger ledger.PeerLedger, number []byte) pb.Response {
 	//  This will preserve the transaction Payload header,
 	//  and client can do GetTransactionByID() if they want the full transaction details
 
+	if redact != nil {
+		block = redaction.Block(block, redact)
+	}
+
 	bytes, err := protoutil.Marshal(block)
 	if err != nil {
 		return shim.Error(err.Error())
@@ -172,7 +210,7 @@ func getBlockByNumber(vledger ledger.PeerLedger, number []byte) pb.Response {
 	return shim.Success(bytes)
 }
 
-func getBlockByHash(vledger ledger.PeerLedger, hash []byte) pb.Response {
+func getBlockByHash(vledger ledger.PeerLedger, hash []byte, redact redaction.NamespaceFilter) pb.Response {
 	if hash == nil {
 		return shim.Error("Block hash must not be nil.")
 	}
@@ -185,6 +223,10 @@ func getBlockByHash(vledger ledger.PeerLedger, hash []byte) pb.Response {
 	//  This will preserve the transaction Payload header,
 	//  and client can do GetTransactionByID() if they want the full transaction details
 
+	if redact != nil {
+		block = redaction.Block(block, redact)
+	}
+
 	bytes, err := protoutil.Marshal(block)
 	if err != nil {
 		return shim.Error(err.Error())
@@ -206,13 +248,17 @@ func getChainInfo(vledger ledger.PeerLedger) pb.Response {
 	return shim.Success(bytes)
 }
 
-func getBlockByTxID(vledger ledger.PeerLedger, rawTxID []byte) pb.Response {
+func getBlockByTxID(vledger ledger.PeerLedger, rawTxID []byte, redact redaction.NamespaceFilter) pb.Response {
 	txID := string(rawTxID)
 	block, err := vledger.GetBlockByTxID(txID)
 	if err != nil {
 		return shim.Error(fmt.Sprintf("Failed to get block for txID %s, error %s", txID, err))
 	}
 
+	if redact != nil {
+		block = redaction.Block(block, redact)
+	}
+
 	bytes, err := protoutil.Marshal(block)
 	if err != nil {
 		return shim.Error(err.Error())
@@ -221,6 +267,52 @@ func getBlockByTxID(vledger ledger.PeerLedger, rawTxID []byte) pb.Response {
 	return shim.Success(bytes)
 }
 
+func getStateProof(vledger ledger.PeerLedger, args [][]byte, allowed redaction.NamespaceFilter, cid string) pb.Response {
+	if len(args) < 2 || len(args) > 3 {
+		return shim.Error(fmt.Sprintf("Incorrect number of arguments for %s, namespace, key and optional block number expected", GetStateProof))
+	}
+	namespace, key := string(args[0]), string(args[1])
+	if allowed != nil && !allowed(namespace) {
+		return shim.Error(fmt.Sprintf("access denied for [%s][%s]: namespace %s may not be read", GetStateProof, cid, namespace))
+	}
+
+	var blockNum uint64
+	if len(args) == 3 {
+		var err error
+		if blockNum, err = strconv.ParseUint(string(args[2]), 10, 64); err != nil {
+			return shim.Error(fmt.Sprintf("Failed to parse block number with error %s", err))
+		}
+	} else {
+		binfo, err := vledger.GetBlockchainInfo()
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to get block info with error %s", err))
+		}
+		blockNum = binfo.Height - 1
+	}
+
+	proof, err := vledger.GetStateProof(namespace, key, blockNum)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get proof of key %s of namespace %s at block %d, error %s", key, namespace, blockNum, err))
+	}
+	bytes, err := json.Marshal(proof)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
+// isRedactable returns true for the functions which may return
+// redacted blocks and transactions.
+func isRedactable(fname string) bool {
+	switch fname {
+	case GetTransactionByID, GetBlockByNumber, GetBlockByHash, GetBlockByTxID:
+		return true
+	default:
+		return false
+	}
+}
+
 func getACLResource(fname string) string {
 	return "qscc/" + fname
 }
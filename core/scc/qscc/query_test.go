@@ -7,13 +7,16 @@ SPDX-License-Identifier: Apache-2.0
 package qscc
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
 	"github.com/hyperledger/fabric-chaincode-go/shimtest"
 	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	peer2 "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp/sw"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
@@ -23,6 +26,7 @@ import (
 	ledger2 "github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt/ledgermgmttest"
+	"github.com/hyperledger/fabric/core/ledger/stateproof"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
@@ -42,6 +46,7 @@ func setupTestLedger(t *testing.T, chainid string, path string) (*shimtest.MockS
 	}
 
 	initializer := ledgermgmttest.NewInitializer(testDir)
+	initializer.Config.StateCommitmentConfig = &ledger2.StateCommitmentConfig{Enabled: true}
 
 	ledgerMgr := ledgermgmt.NewLedgerMgr(initializer)
 
@@ -196,6 +201,79 @@ func TestQueryGetBlockByTxID(t *testing.T) {
 	require.Equal(t, int32(shim.ERROR), res.Status, "GetBlockByTxID should have failed with blank txId.")
 }
 
+func TestQueryGetStateProof(t *testing.T) {
+	chainid := "mytestchainid10"
+	path := t.TempDir()
+
+	stub, p, cleanup, err := setupTestLedger(t, chainid, path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+	defer cleanup()
+
+	addBlockForTesting(t, chainid, p)
+
+	getStateProof := func(args ...string) *stateproof.Proof {
+		invokeArgs := [][]byte{[]byte(GetStateProof), []byte(chainid)}
+		for _, arg := range args {
+			invokeArgs = append(invokeArgs, []byte(arg))
+		}
+		prop := resetProvider(resources.Qscc_GetStateProof, chainid, nil, nil)
+		mockAclProvider.On("CheckACL", resources.Qscc_GetBlockByNumber, chainid, prop).Return(nil)
+		res := stub.MockInvokeWithSignedProposal("1", invokeArgs, prop)
+		require.Equal(t, int32(shim.OK), res.Status, "GetStateProof failed with err: %s", res.Message)
+		proof := &stateproof.Proof{}
+		require.NoError(t, json.Unmarshal(res.Payload, proof))
+		require.NoError(t, proof.Verify())
+		return proof
+	}
+
+	proof := getStateProof("ns1", "key1")
+	require.Equal(t, uint64(1), proof.BlockNum)
+	require.Equal(t, []byte("value1"), proof.Value)
+
+	proof = getStateProof("ns2", "key4", "1")
+	require.Equal(t, uint64(1), proof.BlockNum)
+	require.Equal(t, []byte("value4"), proof.Value)
+
+	proof = getStateProof("ns1", "key1", "0")
+	require.Equal(t, uint64(0), proof.BlockNum)
+	require.Nil(t, proof.Value)
+
+	// callers which may not read whole blocks need the read ACL of the namespace
+	prop := resetProvider(resources.Qscc_GetStateProof, chainid, nil, nil)
+	mockAclProvider.On("CheckACL", resources.Qscc_GetBlockByNumber, chainid, prop).Return(errors.New("Failed access control"))
+	mockAclProvider.On("CheckACL", resources.NamespaceRead("ns1"), chainid, prop).Return(nil)
+	mockAclProvider.On("CheckACL", resources.NamespaceRead("ns2"), chainid, prop).Return(errors.New("Failed access control"))
+	res := stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(GetStateProof), []byte(chainid), []byte("ns1"), []byte("key1")}, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetStateProof failed with err: %s", res.Message)
+	res = stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(GetStateProof), []byte(chainid), []byte("ns2"), []byte("key4")}, prop)
+	require.Equal(t, int32(shim.ERROR), res.Status)
+	require.Equal(t, "access denied for [GetStateProof][mytestchainid10]: namespace ns2 may not be read", res.Message)
+	mockAclProvider.AssertExpectations(t)
+
+	prop = resetProvider(resources.Qscc_GetStateProof, chainid, nil, nil)
+	mockAclProvider.On("CheckACL", resources.Qscc_GetBlockByNumber, chainid, prop).Return(nil)
+	for _, tc := range []struct {
+		args        []string
+		expectedErr string
+	}{
+		{args: []string{}, expectedErr: "missing 3rd argument for GetStateProof"},
+		{args: []string{"ns1"}, expectedErr: "Incorrect number of arguments for GetStateProof, namespace, key and optional block number expected"},
+		{args: []string{"ns1", "key1", "1", "extra"}, expectedErr: "Incorrect number of arguments for GetStateProof, namespace, key and optional block number expected"},
+		{args: []string{"ns1", "key1", "one"}, expectedErr: "Failed to parse block number with error strconv.ParseUint: parsing \"one\": invalid syntax"},
+		{args: []string{"ns1", "key1", "2"}, expectedErr: "Failed to get proof of key key1 of namespace ns1 at block 2, error block [2] is not committed, ledger [mytestchainid10] is at height [2]"},
+	} {
+		invokeArgs := [][]byte{[]byte(GetStateProof), []byte(chainid)}
+		for _, arg := range tc.args {
+			invokeArgs = append(invokeArgs, []byte(arg))
+		}
+		res := stub.MockInvokeWithSignedProposal("2", invokeArgs, prop)
+		require.Equal(t, int32(shim.ERROR), res.Status)
+		require.Equal(t, tc.expectedErr, res.Message)
+	}
+}
+
 func TestFailingCC2CC(t *testing.T) {
 	t.Run("BadProposal", func(t *testing.T) {
 		stub := shimtest.NewMockStub("testchannel", &LedgerQuerier{})
@@ -283,6 +361,7 @@ func TestFailingAccessControl(t *testing.T) {
 	sProp.Signature = sProp.ProposalBytes
 	// Set the ACLProvider to have a failure
 	resetProvider(resources.Qscc_GetBlockByNumber, chainid, sProp, errors.New("Failed access control"))
+	mockAclProvider.On("CheckACL", resources.Qscc_RedactedBlocks, chainid, sProp).Return(errors.New("Failed access control"))
 	res = stub.MockInvokeWithSignedProposal("2", args, sProp)
 	require.Equal(t, int32(shim.ERROR), res.Status, "GetBlockByNumber must fail: %s", res.Message)
 	require.Contains(t, res.Message, "Failed access control")
@@ -304,6 +383,7 @@ func TestFailingAccessControl(t *testing.T) {
 	sProp.Signature = sProp.ProposalBytes
 	// Set the ACLProvider to have a failure
 	resetProvider(resources.Qscc_GetBlockByHash, chainid, sProp, errors.New("Failed access control"))
+	mockAclProvider.On("CheckACL", resources.Qscc_RedactedBlocks, chainid, sProp).Return(errors.New("Failed access control"))
 	res = stub.MockInvokeWithSignedProposal("2", args, sProp)
 	require.Equal(t, int32(shim.ERROR), res.Status, "GetBlockByHash must fail: %s", res.Message)
 	require.Contains(t, res.Message, "Failed access control")
@@ -325,6 +405,7 @@ func TestFailingAccessControl(t *testing.T) {
 	sProp.Signature = sProp.ProposalBytes
 	// Set the ACLProvider to have a failure
 	resetProvider(resources.Qscc_GetBlockByTxID, chainid, sProp, errors.New("Failed access control"))
+	mockAclProvider.On("CheckACL", resources.Qscc_RedactedBlocks, chainid, sProp).Return(errors.New("Failed access control"))
 	res = stub.MockInvokeWithSignedProposal("2", args, sProp)
 	require.Equal(t, int32(shim.ERROR), res.Status, "GetBlockByTxID must fail: %s", res.Message)
 	require.Contains(t, res.Message, "Failed access control")
@@ -346,6 +427,7 @@ func TestFailingAccessControl(t *testing.T) {
 	sProp.Signature = sProp.ProposalBytes
 	// Set the ACLProvider to have a failure
 	resetProvider(resources.Qscc_GetTransactionByID, chainid, sProp, errors.New("Failed access control"))
+	mockAclProvider.On("CheckACL", resources.Qscc_RedactedBlocks, chainid, sProp).Return(errors.New("Failed access control"))
 	res = stub.MockInvokeWithSignedProposal("2", args, sProp)
 	require.Equal(t, int32(shim.ERROR), res.Status, "Qscc_GetTransactionByID must fail: %s", res.Message)
 	require.Contains(t, res.Message, "Failed access control")
@@ -464,3 +546,73 @@ func TestMain(m *testing.M) {
 
 	os.Exit(m.Run())
 }
+
+func TestQueryRedactedBlock(t *testing.T) {
+	chainid := "mytestchainid9"
+	path := t.TempDir()
+
+	stub, p, cleanup, err := setupTestLedger(t, chainid, path)
+	require.NoError(t, err)
+	defer cleanup()
+
+	block1 := addBlockForTesting(t, chainid, p)
+
+	setupRedactedProvider := func(res string) *peer2.SignedProposal {
+		prop := resetProvider(res, chainid, nil, errors.New("Failed access control"))
+		mockAclProvider.On("CheckACL", resources.Qscc_RedactedBlocks, chainid, prop).Return(nil)
+		mockAclProvider.On("CheckACL", resources.NamespaceRead("foo"), chainid, prop).Return(errors.New("Failed access control"))
+		mockAclProvider.On("CheckACL", resources.NamespaceRead("ns1"), chainid, prop).Return(nil)
+		mockAclProvider.On("CheckACL", resources.NamespaceRead("ns2"), chainid, prop).Return(errors.New("Failed access control"))
+		return prop
+	}
+
+	requireRedacted := func(env *common.Envelope, ns string, disclosed bool) {
+		payload, err := protoutil.UnmarshalPayload(env.Payload)
+		require.NoError(t, err)
+		tx, err := protoutil.UnmarshalTransaction(payload.Data)
+		require.NoError(t, err)
+		_, ccAction, err := protoutil.GetPayloads(tx.Actions[0])
+		require.NoError(t, err)
+		txRWSet := &rwset.TxReadWriteSet{}
+		require.NoError(t, proto.Unmarshal(ccAction.Results, txRWSet))
+		require.Len(t, txRWSet.NsRwset, 1)
+		require.Equal(t, ns, txRWSet.NsRwset[0].Namespace)
+		require.Equal(t, disclosed, len(txRWSet.NsRwset[0].Rwset) != 0)
+	}
+
+	args := [][]byte{[]byte(GetBlockByNumber), []byte(chainid), []byte("1")}
+	prop := setupRedactedProvider(resources.Qscc_GetBlockByNumber)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetBlockByNumber should have succeeded: %s", res.Message)
+
+	block := &common.Block{}
+	require.NoError(t, proto.Unmarshal(res.Payload, block))
+	require.True(t, proto.Equal(block1.Header, block.Header))
+	require.Len(t, block.Data.Data, 2)
+	env, err := protoutil.GetEnvelopeFromBlock(block.Data.Data[0])
+	require.NoError(t, err)
+	requireRedacted(env, "ns1", true)
+	env, err = protoutil.GetEnvelopeFromBlock(block.Data.Data[1])
+	require.NoError(t, err)
+	requireRedacted(env, "ns2", false)
+	mockAclProvider.AssertExpectations(t)
+
+	chdr, err := protoutil.ChannelHeader(env)
+	require.NoError(t, err)
+	args = [][]byte{[]byte(GetTransactionByID), []byte(chainid), []byte(chdr.TxId)}
+	prop = setupRedactedProvider(resources.Qscc_GetTransactionByID)
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	require.Equal(t, int32(shim.OK), res.Status, "GetTransactionByID should have succeeded: %s", res.Message)
+
+	processedTran := &peer2.ProcessedTransaction{}
+	require.NoError(t, proto.Unmarshal(res.Payload, processedTran))
+	require.Equal(t, int32(peer2.TxValidationCode_VALID), processedTran.ValidationCode)
+	requireRedacted(processedTran.TransactionEnvelope, "ns2", false)
+
+	// GetChainInfo is never redacted
+	args = [][]byte{[]byte(GetChainInfo), []byte(chainid)}
+	prop = setupRedactedProvider(resources.Qscc_GetChainInfo)
+	res = stub.MockInvokeWithSignedProposal("3", args, prop)
+	require.Equal(t, int32(shim.ERROR), res.Status, "GetChainInfo must fail")
+	require.Contains(t, res.Message, "Failed access control")
+}
@@ -28,7 +28,7 @@ func TestNewStoreProvider(t *testing.T) {
 	tempdir := t.TempDir()
 
 	storedir := filepath.Join(tempdir, "transientstore")
-	p, err := NewStoreProvider(storedir)
+	p, err := NewStoreProvider(&Config{Path: storedir})
 	require.NoError(t, err)
 	require.NotNil(t, p)
 }
@@ -259,7 +259,7 @@ func TestPackageDropTransientStorage(t *testing.T) {
 	// drop the storage
 	require.NoError(t, Drop(env.storedir, ledgerID))
 
-	sp, err := NewStoreProvider(env.storedir)
+	sp, err := NewStoreProvider(&Config{Path: env.storedir})
 	require.NoError(t, err)
 	require.NotNil(t, sp)
 	defer sp.Close()
@@ -315,18 +315,18 @@ func TestOneAndOnlyOneTransientStorageProviderMayBeOpened(t *testing.T) {
 	env.storeProvider.Close()
 
 	// open the first provider
-	sp, err := NewStoreProvider(env.storedir)
+	sp, err := NewStoreProvider(&Config{Path: env.storedir})
 	require.NoError(t, err)
 	require.NotNil(t, sp)
 
 	// opening a second provider is an error
-	_, err = NewStoreProvider(env.storedir)
+	_, err = NewStoreProvider(&Config{Path: env.storedir})
 	require.ErrorContains(t, err, "as another peer node command is executing, wait for that command to complete its execution or terminate it before retrying: lock is already acquired on file")
 
 	// After closing the provider it may be reopened.
 	sp.Close()
 
-	sp, err = NewStoreProvider(env.storedir)
+	sp, err = NewStoreProvider(&Config{Path: env.storedir})
 	require.NoError(t, err)
 	require.NotNil(t, sp)
 	defer sp.Close()
@@ -387,7 +387,7 @@ func TestProviderRestartAfterFailedDeletionScrubsPendingDeletions(t *testing.T)
 	env.storeProvider.Close()
 
 	// re-opening the provider will trigger the processPendingStorageDeletions()
-	env.storeProvider, err = NewStoreProvider(env.storedir)
+	env.storeProvider, err = NewStoreProvider(&Config{Path: env.storedir})
 	require.NoError(t, err)
 	sp = env.storeProvider.(*storeProvider)
 
@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/transientstore"
+)
+
+const (
+	// URLBase is the path under which the handler is registered.
+	URLBase = "/transientstore/"
+	// URLKey is the path of the key encryption key, which is rotated with a PUT request.
+	URLKey = URLBase + "key"
+)
+
+// StoreProvider is the part of the transient store provider used by the handler.
+type StoreProvider interface {
+	Stats() []transientstore.Stats
+	EncryptionKeySKI() []byte
+	RotateEncryptionKey() ([]byte, error)
+}
+
+// StatusResponse reports the contents of the transient stores of the channels.
+type StatusResponse struct {
+	// EncryptionKeySKI is the hex encoded subject key identifier of the key with
+	// which the values are encrypted. It is empty when the encryption is disabled.
+	EncryptionKeySKI string                 `json:"encryption_key_ski,omitempty"`
+	Channels         []transientstore.Stats `json:"channels"`
+}
+
+// KeyResponse reports the key with which the values are encrypted.
+type KeyResponse struct {
+	EncryptionKeySKI string `json:"encryption_key_ski"`
+}
+
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func NewHandler(provider StoreProvider) *Handler {
+	return &Handler{
+		StoreProvider: provider,
+		Logger:        flogging.MustGetLogger("transientstore.httpadmin"),
+	}
+}
+
+// Handler reports the size of the transient stores and the progress of their
+// purge, and rotates the key with which their values are encrypted.
+type Handler struct {
+	StoreProvider StoreProvider
+	Logger        *flogging.FabricLogger
+}
+
+func (h *Handler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	switch {
+	case req.URL.Path == URLBase && req.Method == http.MethodGet:
+		h.sendResponse(resp, http.StatusOK, &StatusResponse{
+			EncryptionKeySKI: hex.EncodeToString(h.StoreProvider.EncryptionKeySKI()),
+			Channels:         h.StoreProvider.Stats(),
+		})
+
+	case req.URL.Path == URLKey && req.Method == http.MethodGet:
+		h.sendResponse(resp, http.StatusOK, &KeyResponse{
+			EncryptionKeySKI: hex.EncodeToString(h.StoreProvider.EncryptionKeySKI()),
+		})
+
+	case req.URL.Path == URLKey && req.Method == http.MethodPut:
+		ski, err := h.StoreProvider.RotateEncryptionKey()
+		if err != nil {
+			h.sendResponse(resp, http.StatusBadRequest, err)
+			return
+		}
+		h.sendResponse(resp, http.StatusOK, &KeyResponse{EncryptionKeySKI: hex.EncodeToString(ski)})
+
+	case req.URL.Path == URLBase || req.URL.Path == URLKey:
+		h.sendResponse(resp, http.StatusMethodNotAllowed, fmt.Errorf("invalid request method: %s", req.Method))
+
+	default:
+		h.sendResponse(resp, http.StatusNotFound, fmt.Errorf("invalid path: %s", req.URL.Path))
+	}
+}
+
+func (h *Handler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	encoder := json.NewEncoder(resp)
+	if err, ok := payload.(error); ok {
+		payload = &ErrorResponse{Error: err.Error()}
+	}
+
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+
+	if err := encoder.Encode(payload); err != nil {
+		h.Logger.Errorw("failed to encode payload", "error", err)
+	}
+}
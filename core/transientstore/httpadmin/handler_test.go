@@ -0,0 +1,97 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package httpadmin
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric-protos-go/transientstore"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	ts "github.com/hyperledger/fabric/core/transientstore"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProvider(t *testing.T, encryptionEnabled bool) ts.StoreProvider {
+	csp, err := sw.NewDefaultSecurityLevel(t.TempDir())
+	require.NoError(t, err)
+	provider, err := ts.NewStoreProvider(&ts.Config{
+		Path:              filepath.Join(t.TempDir(), "transientstore"),
+		EncryptionEnabled: encryptionEnabled,
+		CryptoProvider:    csp,
+	})
+	require.NoError(t, err)
+	t.Cleanup(provider.Close)
+	return provider
+}
+
+func serve(t *testing.T, h *Handler, method, path string, response interface{}) int {
+	resp := httptest.NewRecorder()
+	h.ServeHTTP(resp, httptest.NewRequest(method, path, nil))
+	require.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), response))
+	return resp.Code
+}
+
+func TestHandlerStatus(t *testing.T) {
+	provider := newTestProvider(t, true)
+	store, err := provider.OpenStore("mychannel")
+	require.NoError(t, err)
+	require.NoError(t, store.Persist("txid1", 5, &transientstore.TxPvtReadWriteSetWithConfigInfo{EndorsedAt: 5}))
+	require.NoError(t, store.Persist("txid2", 7, &transientstore.TxPvtReadWriteSetWithConfigInfo{EndorsedAt: 7}))
+	require.NoError(t, store.PurgeBelowHeight(6))
+	_, err = provider.OpenStore("emptychannel")
+	require.NoError(t, err)
+
+	h := NewHandler(provider)
+	status := &StatusResponse{}
+	require.Equal(t, http.StatusOK, serve(t, h, http.MethodGet, URLBase, status))
+	require.Equal(t, hex.EncodeToString(provider.EncryptionKeySKI()), status.EncryptionKeySKI)
+	require.Len(t, status.Channels, 2)
+	require.Equal(t, ts.Stats{LedgerID: "emptychannel"}, status.Channels[0])
+	require.Equal(t, "mychannel", status.Channels[1].LedgerID)
+	require.Equal(t, int64(1), status.Channels[1].Entries)
+	require.NotZero(t, status.Channels[1].SizeBytes)
+	require.Equal(t, uint64(7), status.Channels[1].OldestEntryHeight)
+	require.Equal(t, uint64(6), status.Channels[1].PurgedBelowHeight)
+	require.Equal(t, uint64(1), status.Channels[1].PurgedEntries)
+
+	errResp := &ErrorResponse{}
+	require.Equal(t, http.StatusMethodNotAllowed, serve(t, h, http.MethodPost, URLBase, errResp))
+	require.Equal(t, "invalid request method: POST", errResp.Error)
+	require.Equal(t, http.StatusNotFound, serve(t, h, http.MethodGet, URLBase+"unknown", errResp))
+	require.Equal(t, "invalid path: /transientstore/unknown", errResp.Error)
+}
+
+func TestHandlerRotateKey(t *testing.T) {
+	provider := newTestProvider(t, true)
+	h := NewHandler(provider)
+
+	key := &KeyResponse{}
+	require.Equal(t, http.StatusOK, serve(t, h, http.MethodGet, URLKey, key))
+	initialSKI := key.EncryptionKeySKI
+	require.NotEmpty(t, initialSKI)
+
+	require.Equal(t, http.StatusOK, serve(t, h, http.MethodPut, URLKey, key))
+	require.NotEqual(t, initialSKI, key.EncryptionKeySKI)
+	require.Equal(t, hex.EncodeToString(provider.EncryptionKeySKI()), key.EncryptionKeySKI)
+
+	t.Run("encryption disabled", func(t *testing.T) {
+		h := NewHandler(newTestProvider(t, false))
+		status := &StatusResponse{}
+		require.Equal(t, http.StatusOK, serve(t, h, http.MethodGet, URLBase, status))
+		require.Empty(t, status.EncryptionKeySKI)
+
+		errResp := &ErrorResponse{}
+		require.Equal(t, http.StatusBadRequest, serve(t, h, http.MethodPut, URLKey, errResp))
+		require.Equal(t, "transient storage encryption is not enabled", errResp.Error)
+	})
+}
@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transientstore
+
+import "github.com/hyperledger/fabric/common/metrics"
+
+type stats struct {
+	entries           metrics.Gauge
+	size              metrics.Gauge
+	oldestEntryHeight metrics.Gauge
+	purgedBelowHeight metrics.Gauge
+	purgedEntries     metrics.Counter
+}
+
+func newStats(metricsProvider metrics.Provider) *stats {
+	stats := &stats{}
+	stats.entries = metricsProvider.NewGauge(entriesGaugeOpts)
+	stats.size = metricsProvider.NewGauge(sizeGaugeOpts)
+	stats.oldestEntryHeight = metricsProvider.NewGauge(oldestEntryHeightGaugeOpts)
+	stats.purgedBelowHeight = metricsProvider.NewGauge(purgedBelowHeightGaugeOpts)
+	stats.purgedEntries = metricsProvider.NewCounter(purgedEntriesCounterOpts)
+	return stats
+}
+
+type storeStats struct {
+	stats    *stats
+	ledgerID string
+}
+
+func (s *stats) storeStats(ledgerID string) *storeStats {
+	return &storeStats{
+		s, ledgerID,
+	}
+}
+
+func (s *storeStats) updateContents(entries, size int64, oldestEntryHeight uint64) {
+	s.stats.entries.With("channel", s.ledgerID).Set(float64(entries))
+	s.stats.size.With("channel", s.ledgerID).Set(float64(size))
+	s.stats.oldestEntryHeight.With("channel", s.ledgerID).Set(float64(oldestEntryHeight))
+}
+
+func (s *storeStats) updatePurgedBelowHeight(height uint64) {
+	s.stats.purgedBelowHeight.With("channel", s.ledgerID).Set(float64(height))
+}
+
+func (s *storeStats) addPurgedEntries(reason string, count int) {
+	s.stats.purgedEntries.With("channel", s.ledgerID, "reason", reason).Add(float64(count))
+}
+
+var (
+	entriesGaugeOpts = metrics.GaugeOpts{
+		Namespace:    "transientstore",
+		Name:         "entries",
+		Help:         "The number of private write sets held in the transient store.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	sizeGaugeOpts = metrics.GaugeOpts{
+		Namespace:    "transientstore",
+		Name:         "size_bytes",
+		Help:         "The size in bytes of the private write sets held in the transient store.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	oldestEntryHeightGaugeOpts = metrics.GaugeOpts{
+		Namespace:    "transientstore",
+		Name:         "oldest_entry_height",
+		Help:         "The lowest block height at which a private write set held in the transient store was received.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	purgedBelowHeightGaugeOpts = metrics.GaugeOpts{
+		Namespace:    "transientstore",
+		Name:         "purged_below_height",
+		Help:         "The block height below which the orphaned private write sets were last purged from the transient store.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+
+	purgedEntriesCounterOpts = metrics.CounterOpts{
+		Namespace:    "transientstore",
+		Name:         "purged_entries",
+		Help:         "The number of private write sets purged from the transient store, either once their transaction is committed or once they are orphaned.",
+		LabelNames:   []string{"channel", "reason"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{reason}",
+	}
+)
@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package transientstore
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStats(t *testing.T) {
+	fakeProvider := &metricsfakes.Provider{}
+	gauges := map[string]*metricsfakes.Gauge{}
+	fakeProvider.NewGaugeStub = func(opts metrics.GaugeOpts) metrics.Gauge {
+		gauge := &metricsfakes.Gauge{}
+		gauge.WithReturns(gauge)
+		gauges[opts.Name] = gauge
+		return gauge
+	}
+	purgedEntries := &metricsfakes.Counter{}
+	purgedEntries.WithReturns(purgedEntries)
+	fakeProvider.NewCounterReturns(purgedEntries)
+
+	provider, err := NewStoreProvider(&Config{
+		Path:            filepath.Join(t.TempDir(), "transientstore"),
+		MetricsProvider: fakeProvider,
+	})
+	require.NoError(t, err)
+	defer provider.Close()
+	store, err := provider.OpenStore("mychannel")
+	require.NoError(t, err)
+
+	require.NoError(t, store.Persist("txid-1", 10, samplePvtDataWithConfigInfo(t)))
+	require.NoError(t, store.Persist("txid-2", 11, samplePvtDataWithConfigInfo(t)))
+	size := store.Stats().SizeBytes
+
+	require.Equal(t, 3, gauges["entries"].SetCallCount())
+	require.Equal(t, []string{"channel", "mychannel"}, gauges["entries"].WithArgsForCall(2))
+	require.Equal(t, float64(2), gauges["entries"].SetArgsForCall(2))
+	require.Equal(t, float64(size), gauges["size_bytes"].SetArgsForCall(2))
+	require.Equal(t, float64(10), gauges["oldest_entry_height"].SetArgsForCall(2))
+
+	require.NoError(t, store.PurgeByTxids([]string{"txid-1"}))
+	require.Equal(t, float64(1), gauges["entries"].SetArgsForCall(3))
+	require.Equal(t, float64(size/2), gauges["size_bytes"].SetArgsForCall(3))
+	require.Equal(t, float64(11), gauges["oldest_entry_height"].SetArgsForCall(3))
+	require.Equal(t, 1, purgedEntries.AddCallCount())
+	require.Equal(t, []string{"channel", "mychannel", "reason", "txid"}, purgedEntries.WithArgsForCall(0))
+	require.Equal(t, float64(1), purgedEntries.AddArgsForCall(0))
+
+	require.NoError(t, store.PurgeBelowHeight(12))
+	require.Equal(t, float64(0), gauges["entries"].SetArgsForCall(4))
+	require.Equal(t, float64(0), gauges["oldest_entry_height"].SetArgsForCall(4))
+	require.Equal(t, []string{"channel", "mychannel", "reason", "height"}, purgedEntries.WithArgsForCall(1))
+	require.Equal(t, []string{"channel", "mychannel"}, gauges["purged_below_height"].WithArgsForCall(0))
+	require.Equal(t, float64(12), gauges["purged_below_height"].SetArgsForCall(0))
+
+	// a purge that removes nothing only records its height
+	require.NoError(t, store.PurgeBelowHeight(13))
+	require.Equal(t, 5, gauges["entries"].SetCallCount())
+	require.Equal(t, 2, purgedEntries.AddCallCount())
+	require.Equal(t, float64(13), gauges["purged_below_height"].SetArgsForCall(1))
+}
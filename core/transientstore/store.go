@@ -7,13 +7,20 @@ SPDX-License-Identifier: Apache-2.0
 package transientstore
 
 import (
+	"encoding/hex"
 	"path/filepath"
+	"sort"
+	"sync"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	"github.com/hyperledger/fabric-protos-go/transientstore"
+	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/encryption"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/pkg/errors"
@@ -25,11 +32,15 @@ var logger = flogging.MustGetLogger("transientstore")
 var (
 	emptyValue = []byte{}
 	nilByte    = byte('\x00')
+	// encryptedByte is prepended to the envelope of an encrypted value. Like the nil byte,
+	// it cannot start a marshaled message.
+	encryptedByte = byte('\x01')
 	// ErrStoreEmpty is used to indicate that there are no entries in transient store
 	ErrStoreEmpty = errors.New("Transient store is empty")
 	// transient system namespace is the name of a db used for storage bookkeeping metadata.
 	systemNamespace          = ""
 	underDeletionKey         = []byte("UNDER_DELETION")
+	keyEncryptionKeySKIKey   = []byte("KEY_ENCRYPTION_KEY")
 	transientStorageLockName = "transientStoreFileLock"
 )
 
@@ -40,9 +51,52 @@ var (
 // StoreProvider provides an instance of a TransientStore
 type StoreProvider interface {
 	OpenStore(ledgerID string) (*Store, error)
+	// Stats returns the statistics of the opened stores, ordered by ledger ID.
+	Stats() []Stats
+	// EncryptionKeySKI returns the subject key identifier of the key with which the
+	// values are encrypted, or nil if the encryption is not enabled.
+	EncryptionKeySKI() []byte
+	// RotateEncryptionKey generates a new key with which the values are encrypted
+	// from then on and returns its subject key identifier. The values encrypted
+	// before remain readable as long as the crypto provider retains their key.
+	RotateEncryptionKey() ([]byte, error)
 	Close()
 }
 
+// Config is the configuration of the transient store provider.
+type Config struct {
+	// Path is the folder of the transient store database.
+	Path string
+	// EncryptionEnabled enables the envelope encryption of the values persisted
+	// from then on. The values persisted before are left in plaintext.
+	EncryptionEnabled bool
+	// CryptoProvider generates and keeps the AES keys with which the values are
+	// encrypted. It is required if the encryption is enabled, and to read the
+	// values encrypted while it was enabled.
+	CryptoProvider bccsp.BCCSP
+	// MetricsProvider is used to report the size of the stores and their purges.
+	MetricsProvider metrics.Provider
+}
+
+// Stats captures the contents of the transient store of a ledger and the progress
+// of their purge.
+type Stats struct {
+	LedgerID string `json:"channel"`
+	// Entries is the number of private write sets held in the store.
+	Entries int64 `json:"entries"`
+	// SizeBytes is the size of the private write sets held in the store.
+	SizeBytes int64 `json:"size_bytes"`
+	// OldestEntryHeight is the lowest block height at which a private write set
+	// held in the store was received. It is zero when the store is empty.
+	OldestEntryHeight uint64 `json:"oldest_entry_height"`
+	// PurgedBelowHeight is the block height below which the orphaned private
+	// write sets were last purged.
+	PurgedBelowHeight uint64 `json:"purged_below_height"`
+	// PurgedEntries is the number of private write sets purged since the store
+	// was opened.
+	PurgedEntries uint64 `json:"purged_entries"`
+}
+
 // RWSetScanner provides an iterator for EndorserPvtSimulationResults
 type RWSetScanner interface {
 	// Next returns the next EndorserPvtSimulationResults from the RWSetScanner.
@@ -67,25 +121,46 @@ type EndorserPvtSimulationResults struct {
 // private write sets of simulated transactions, and implements TransientStoreProvider
 // interface.
 type storeProvider struct {
-	dbProvider *leveldbhelper.Provider
-	fileLock   *leveldbhelper.FileLock
+	dbProvider        *leveldbhelper.Provider
+	fileLock          *leveldbhelper.FileLock
+	encryptor         *encryption.Encryptor
+	encryptionEnabled bool
+	stats             *stats
+
+	storesLock sync.Mutex
+	stores     map[string]*Store
 }
 
 // store holds an instance of a levelDB.
 type Store struct {
-	db       *leveldbhelper.DBHandle
-	ledgerID string
+	db        *leveldbhelper.DBHandle
+	ledgerID  string
+	encryptor *encryption.Encryptor
+	encrypt   bool
+	stats     *storeStats
+
+	// purgeLock serializes the purges so that an entry is accounted for once
+	purgeLock sync.Mutex
+
+	statsLock         sync.Mutex
+	entries           int64
+	size              int64
+	oldestEntryHeight uint64
+	purgedBelowHeight uint64
+	purgedEntries     uint64
 }
 
 // RwsetScanner helps iterating over results
 type RwsetScanner struct {
-	txid   string
-	dbItr  iterator.Iterator
-	filter ledger.PvtNsCollFilter
+	txid      string
+	dbItr     iterator.Iterator
+	filter    ledger.PvtNsCollFilter
+	encryptor *encryption.Encryptor
 }
 
 // NewStoreProvider instantiates TransientStoreProvider
-func NewStoreProvider(path string) (StoreProvider, error) {
+func NewStoreProvider(conf *Config) (StoreProvider, error) {
+	path := conf.Path
 	// Ensure the routine is invoked while the peer is down.
 	lockPath := filepath.Join(filepath.Dir(path), transientStorageLockName)
 	lock := leveldbhelper.NewFileLock(lockPath)
@@ -100,6 +175,14 @@ func NewStoreProvider(path string) (StoreProvider, error) {
 		return nil, errors.WithMessagef(err, "could not construct storage provider in folder [%s]", path)
 	}
 
+	if err := provider.initEncryption(conf.EncryptionEnabled, conf.CryptoProvider); err != nil {
+		provider.Close()
+		return nil, errors.WithMessagef(err, "could not initialize the encryption of the storage in folder [%s]", path)
+	}
+	if conf.MetricsProvider != nil {
+		provider.stats = newStats(conf.MetricsProvider)
+	}
+
 	return provider, nil
 }
 
@@ -117,7 +200,12 @@ func newStoreProvider(providerPath string, fileLock *leveldbhelper.FileLock) (*s
 		return nil, errors.WithMessage(err, "could not open dbprovider")
 	}
 
-	provider := &storeProvider{dbProvider: dbProvider, fileLock: fileLock}
+	provider := &storeProvider{
+		dbProvider: dbProvider,
+		fileLock:   fileLock,
+		stats:      newStats(&disabled.Provider{}),
+		stores:     map[string]*Store{},
+	}
 
 	// purge any databases marked for deletion.  This may occur at the next peer init after a
 	// transient storage deletion failed due to a crash or system error.
@@ -128,10 +216,99 @@ func newStoreProvider(providerPath string, fileLock *leveldbhelper.FileLock) (*s
 	return provider, nil
 }
 
+// initEncryption sets up the encryptor of the values. The encryptor is also set up when the
+// encryption is disabled if a key was recorded while it was enabled, so that the values
+// encrypted then remain readable.
+func (provider *storeProvider) initEncryption(enabled bool, csp bccsp.BCCSP) error {
+	db := provider.dbProvider.GetDBHandle(systemNamespace)
+	if !enabled {
+		ski, err := db.Get(keyEncryptionKeySKIKey)
+		if err != nil {
+			return errors.WithMessage(err, "retrieving the key encryption key")
+		}
+		if ski == nil {
+			return nil
+		}
+		if csp == nil {
+			logger.Warnw("Transient storage holds values encrypted with a key that cannot be retrieved without a crypto provider", "ski", hex.EncodeToString(ski))
+			return nil
+		}
+	}
+	if csp == nil {
+		return errors.New("a crypto provider is required to encrypt the transient storage")
+	}
+
+	encryptor, err := encryption.NewEncryptor(csp, db, keyEncryptionKeySKIKey)
+	if err != nil {
+		if !enabled {
+			logger.Warnw("Values encrypted while the transient storage encryption was enabled cannot be read", "error", err)
+			return nil
+		}
+		return err
+	}
+	provider.encryptor = encryptor
+	provider.encryptionEnabled = enabled
+	if enabled {
+		logger.Infow("Transient storage encryption is enabled", "ski", hex.EncodeToString(encryptor.CurrentKeySKI()))
+	}
+	return nil
+}
+
 // OpenStore returns a handle to a ledgerId in Store
 func (provider *storeProvider) OpenStore(ledgerID string) (*Store, error) {
+	provider.storesLock.Lock()
+	defer provider.storesLock.Unlock()
+	if store, ok := provider.stores[ledgerID]; ok {
+		return store, nil
+	}
+
 	dbHandle := provider.dbProvider.GetDBHandle(ledgerID)
-	return &Store{db: dbHandle, ledgerID: ledgerID}, nil
+	store := &Store{
+		db:        dbHandle,
+		ledgerID:  ledgerID,
+		encryptor: provider.encryptor,
+		encrypt:   provider.encryptionEnabled,
+		stats:     provider.stats.storeStats(ledgerID),
+	}
+	if err := store.loadStats(); err != nil {
+		return nil, errors.WithMessagef(err, "loading the statistics of the transient storage [%s]", ledgerID)
+	}
+	provider.stores[ledgerID] = store
+	return store, nil
+}
+
+// Stats returns the statistics of the opened stores, ordered by ledger ID.
+func (provider *storeProvider) Stats() []Stats {
+	provider.storesLock.Lock()
+	defer provider.storesLock.Unlock()
+	stats := make([]Stats, 0, len(provider.stores))
+	for _, store := range provider.stores {
+		stats = append(stats, store.Stats())
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].LedgerID < stats[j].LedgerID })
+	return stats
+}
+
+// EncryptionKeySKI returns the subject key identifier of the key with which the values are
+// encrypted, or nil if the encryption is not enabled.
+func (provider *storeProvider) EncryptionKeySKI() []byte {
+	if !provider.encryptionEnabled {
+		return nil
+	}
+	return provider.encryptor.CurrentKeySKI()
+}
+
+// RotateEncryptionKey generates a new key with which the values are encrypted from then on.
+func (provider *storeProvider) RotateEncryptionKey() ([]byte, error) {
+	if !provider.encryptionEnabled {
+		return nil, errors.New("transient storage encryption is not enabled")
+	}
+	ski, err := provider.encryptor.RotateKey()
+	if err != nil {
+		return nil, err
+	}
+	logger.Infow("Rotated the transient storage key encryption key", "ski", hex.EncodeToString(ski))
+	return ski, nil
 }
 
 // Close closes the TransientStoreProvider
@@ -335,22 +512,34 @@ func (s *Store) Persist(txid string, blockHeight uint64,
 	// as a marshaled message can never start with a nil byte. In v1.3, we can avoid prepending the
 	// nil byte.
 	value := append([]byte{nilByte}, privateSimulationResultsWithConfigBytes...)
+	if s.encrypt {
+		// the key is authenticated along with the value so that a value cannot be
+		// passed off as the private write set of another transaction
+		envelope, err := s.encryptor.Encrypt(value, compositeKeyPvtRWSet)
+		if err != nil {
+			return errors.WithMessagef(err, "encrypting the private data of txid [%s]", txid)
+		}
+		value = append([]byte{encryptedByte}, envelope...)
+	}
 	dbBatch.Put(compositeKeyPvtRWSet, value)
+	// the size of the value is recorded in the purge indexes so that the size of the
+	// store is maintained on purge without reading the (potentially large) value
+	entrySize := encodeEntrySize(len(value))
 
 	// Create two index: (i) by txid, and (ii) by height
 
 	// Create compositeKey for purge index by height with appropriate prefix, blockHeight,
-	// txid, uuid and store the compositeKey (purge index) with a nil byte as value. Note that
+	// txid, uuid and store the compositeKey (purge index) with the size of the value as value. Note that
 	// the purge index is used to remove orphan entries in the transient store (which are not removed
 	// by PurgeTxids()) using BTL policy by PurgeBelowHeight(). Note that orphan entries are due to transaction
 	// that gets endorsed but not submitted by the client for commit)
 	compositeKeyPurgeIndexByHeight := createCompositeKeyForPurgeIndexByHeight(blockHeight, txid, uuid)
-	dbBatch.Put(compositeKeyPurgeIndexByHeight, emptyValue)
+	dbBatch.Put(compositeKeyPurgeIndexByHeight, entrySize)
 
 	// Create compositeKey for purge index by txid with appropriate prefix, txid, uuid,
-	// blockHeight and store the compositeKey (purge index) with a nil byte as value.
+	// blockHeight and store the compositeKey (purge index) with the size of the value as value.
 	// Though compositeKeyPvtRWSet itself can be used to purge private write set by txid,
-	// we create a separate composite key with a nil byte as value. The reason is that
+	// we create a separate composite key with a small value. The reason is that
 	// if we use compositeKeyPvtRWSet, we unnecessarily read (potentially large) private write
 	// set associated with the key from db. Note that this purge index is used to remove non-orphan
 	// entries in the transient store and is used by PurgeTxids()
@@ -358,9 +547,13 @@ func (s *Store) Persist(txid string, blockHeight uint64,
 	// with purgeIndexByTxidPrefix. For code readability and to be expressive, we use a
 	// createCompositeKeyForPurgeIndexByTxid() instead.
 	compositeKeyPurgeIndexByTxid := createCompositeKeyForPurgeIndexByTxid(txid, uuid, blockHeight)
-	dbBatch.Put(compositeKeyPurgeIndexByTxid, emptyValue)
+	dbBatch.Put(compositeKeyPurgeIndexByTxid, entrySize)
 
-	return s.db.WriteBatch(dbBatch, true)
+	if err := s.db.WriteBatch(dbBatch, true); err != nil {
+		return err
+	}
+	s.recordPersisted(blockHeight, len(value))
+	return nil
 }
 
 // GetTxPvtRWSetByTxid returns an iterator due to the fact that the txid may have multiple private
@@ -376,7 +569,7 @@ func (s *Store) GetTxPvtRWSetByTxid(txid string, filter ledger.PvtNsCollFilter)
 	if err != nil {
 		return nil, err
 	}
-	return &RwsetScanner{txid, iter, filter}, nil
+	return &RwsetScanner{txid, iter, filter, s.encryptor}, nil
 }
 
 // PurgeByTxids removes private write sets of a given set of transactions from the
@@ -385,7 +578,11 @@ func (s *Store) GetTxPvtRWSetByTxid(txid string, filter ledger.PvtNsCollFilter)
 func (s *Store) PurgeByTxids(txids []string) error {
 	logger.Debug("Purging private data from transient store for committed txids")
 
+	s.purgeLock.Lock()
+	defer s.purgeLock.Unlock()
+
 	dbBatch := s.db.NewUpdateBatch()
+	purged, purgedSize := 0, int64(0)
 
 	for _, txid := range txids {
 		// Construct startKey and endKey to do an range query
@@ -411,7 +608,13 @@ func (s *Store) PurgeByTxids(txids []string) error {
 				return err
 			}
 			compositeKeyPvtRWSet := createCompositeKeyForPvtRWSet(txid, uuid, blockHeight)
+			size, err := s.entrySize(iter.Value(), compositeKeyPvtRWSet)
+			if err != nil {
+				return err
+			}
 			dbBatch.Delete(compositeKeyPvtRWSet)
+			purged++
+			purgedSize += size
 
 			// Remove purge index -- purgeIndexByHeight
 			compositeKeyPurgeIndexByHeight := createCompositeKeyForPurgeIndexByHeight(blockHeight, txid, uuid)
@@ -424,7 +627,11 @@ func (s *Store) PurgeByTxids(txids []string) error {
 	}
 	// If peer fails before/while writing the batch to golevelDB, these entries will be
 	// removed as per BTL policy later by PurgeBelowHeight()
-	return s.db.WriteBatch(dbBatch, true)
+	if err := s.db.WriteBatch(dbBatch, true); err != nil {
+		return err
+	}
+	s.recordPurged("txid", purged, purgedSize)
+	return nil
 }
 
 // PurgeBelowHeight removes private write sets at block height lesser than
@@ -436,6 +643,9 @@ func (s *Store) PurgeByTxids(txids []string) error {
 func (s *Store) PurgeBelowHeight(maxBlockNumToRetain uint64) error {
 	logger.Debugf("Purging orphaned private data from transient store received prior to block [%d]", maxBlockNumToRetain)
 
+	s.purgeLock.Lock()
+	defer s.purgeLock.Unlock()
+
 	// Do a range query with 0 as startKey and maxBlockNumToRetain-1 as endKey
 	startKey := createPurgeIndexByHeightRangeStartKey(0)
 	endKey := createPurgeIndexByHeightRangeEndKey(maxBlockNumToRetain - 1)
@@ -445,6 +655,7 @@ func (s *Store) PurgeBelowHeight(maxBlockNumToRetain uint64) error {
 	}
 
 	dbBatch := s.db.NewUpdateBatch()
+	purged, purgedSize := 0, int64(0)
 
 	// Get all txid and uuid from above result and remove it from transient store (both
 	// write set and the corresponding index.
@@ -460,7 +671,13 @@ func (s *Store) PurgeBelowHeight(maxBlockNumToRetain uint64) error {
 		logger.Debugf("Purging from transient store private data simulated at block [%d]: txid [%s] uuid [%s]", blockHeight, txid, uuid)
 
 		compositeKeyPvtRWSet := createCompositeKeyForPvtRWSet(txid, uuid, blockHeight)
+		size, err := s.entrySize(iter.Value(), compositeKeyPvtRWSet)
+		if err != nil {
+			return err
+		}
 		dbBatch.Delete(compositeKeyPvtRWSet)
+		purged++
+		purgedSize += size
 
 		// Remove purge index -- purgeIndexByTxid
 		compositeKeyPurgeIndexByTxid := createCompositeKeyForPurgeIndexByTxid(txid, uuid, blockHeight)
@@ -471,7 +688,12 @@ func (s *Store) PurgeBelowHeight(maxBlockNumToRetain uint64) error {
 	}
 	iter.Release()
 
-	return s.db.WriteBatch(dbBatch, true)
+	if err := s.db.WriteBatch(dbBatch, true); err != nil {
+		return err
+	}
+	s.recordPurged("height", purged, purgedSize)
+	s.recordPurgedBelowHeight(maxBlockNumToRetain)
+	return nil
 }
 
 // GetMinTransientBlkHt returns the lowest block height remaining in transient store
@@ -498,6 +720,111 @@ func (s *Store) GetMinTransientBlkHt() (uint64, error) {
 	return 0, ErrStoreEmpty
 }
 
+// Stats returns the statistics of the store.
+func (s *Store) Stats() Stats {
+	s.statsLock.Lock()
+	defer s.statsLock.Unlock()
+	return Stats{
+		LedgerID:          s.ledgerID,
+		Entries:           s.entries,
+		SizeBytes:         s.size,
+		OldestEntryHeight: s.oldestEntryHeight,
+		PurgedBelowHeight: s.purgedBelowHeight,
+		PurgedEntries:     s.purgedEntries,
+	}
+}
+
+// loadStats counts the entries of the store and their size from the purge index by height.
+func (s *Store) loadStats() error {
+	iter, err := s.db.GetIterator(createPurgeIndexByHeightRangeStartKey(0), createPurgeIndexByHeightFullRangeEndKey())
+	if err != nil {
+		return err
+	}
+	defer iter.Release()
+
+	var entries, size int64
+	var oldestEntryHeight uint64
+	for iter.Next() {
+		txid, uuid, blockHeight, err := splitCompositeKeyOfPurgeIndexByHeight(iter.Key())
+		if err != nil {
+			return err
+		}
+		if entries == 0 {
+			oldestEntryHeight = blockHeight
+		}
+		entrySize, err := s.entrySize(iter.Value(), createCompositeKeyForPvtRWSet(txid, uuid, blockHeight))
+		if err != nil {
+			return err
+		}
+		entries++
+		size += entrySize
+	}
+	if err := iter.Error(); err != nil {
+		return errors.Wrap(err, "internal leveldb error while iterating the purge index")
+	}
+
+	s.statsLock.Lock()
+	defer s.statsLock.Unlock()
+	s.entries, s.size, s.oldestEntryHeight = entries, size, oldestEntryHeight
+	s.stats.updateContents(s.entries, s.size, s.oldestEntryHeight)
+	return nil
+}
+
+// entrySize returns the size of the value of an entry, as recorded in the value of its purge
+// index. The entries persisted by earlier versions of the store do not record their size, which
+// is then obtained from the value itself.
+func (s *Store) entrySize(purgeIndexValue, compositeKeyPvtRWSet []byte) (int64, error) {
+	if size, ok := decodeEntrySize(purgeIndexValue); ok {
+		return size, nil
+	}
+	value, err := s.db.Get(compositeKeyPvtRWSet)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(value)), nil
+}
+
+func (s *Store) recordPersisted(blockHeight uint64, size int) {
+	s.statsLock.Lock()
+	defer s.statsLock.Unlock()
+	if s.entries <= 0 || blockHeight < s.oldestEntryHeight {
+		s.oldestEntryHeight = blockHeight
+	}
+	s.entries++
+	s.size += int64(size)
+	s.stats.updateContents(s.entries, s.size, s.oldestEntryHeight)
+}
+
+func (s *Store) recordPurged(reason string, purged int, size int64) {
+	if purged == 0 {
+		return
+	}
+	// the oldest entry is looked up before taking the lock as it is not
+	// affected by the entries persisted meanwhile, which update it themselves
+	oldestEntryHeight, err := s.GetMinTransientBlkHt()
+	if err != nil && err != ErrStoreEmpty {
+		logger.Warnw("Failed to retrieve the lowest block height remaining in transient store", "ledgerID", s.ledgerID, "error", err)
+	}
+
+	s.statsLock.Lock()
+	defer s.statsLock.Unlock()
+	s.entries -= int64(purged)
+	s.size -= size
+	s.purgedEntries += uint64(purged)
+	if err == nil || err == ErrStoreEmpty {
+		s.oldestEntryHeight = oldestEntryHeight
+	}
+	s.stats.updateContents(s.entries, s.size, s.oldestEntryHeight)
+	s.stats.addPurgedEntries(reason, purged)
+}
+
+func (s *Store) recordPurgedBelowHeight(maxBlockNumToRetain uint64) {
+	s.statsLock.Lock()
+	defer s.statsLock.Unlock()
+	s.purgedBelowHeight = maxBlockNumToRetain
+	s.stats.updatePurgedBelowHeight(maxBlockNumToRetain)
+}
+
 func (s *Store) Shutdown() {
 	// do nothing because shared db is used
 }
@@ -515,6 +842,15 @@ func (scanner *RwsetScanner) Next() (*EndorserPvtSimulationResults, error) {
 		return nil, err
 	}
 
+	if dbVal[0] == encryptedByte {
+		if scanner.encryptor == nil {
+			return nil, errors.Errorf("private data of txid [%s] is encrypted but the transient store encryption is not configured", scanner.txid)
+		}
+		if dbVal, err = scanner.encryptor.Decrypt(dbVal[1:], dbKey); err != nil {
+			return nil, errors.WithMessagef(err, "decrypting the private data of txid [%s]", scanner.txid)
+		}
+	}
+
 	txPvtRWSet := &rwset.TxPvtReadWriteSet{}
 	txPvtRWSetWithConfig := &transientstore.TxPvtReadWriteSetWithConfigInfo{}
 
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"errors"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/ledger/util"
@@ -155,6 +156,12 @@ func createPurgeIndexByHeightRangeEndKey(blockHeight uint64) []byte {
 	return endKey
 }
 
+// createPurgeIndexByHeightFullRangeEndKey returns a endKey to do a range query on the whole index stored
+// in transient store using blockHeight
+func createPurgeIndexByHeightFullRangeEndKey() []byte {
+	return []byte{purgeIndexByHeightPrefix, compositeKeySep + 1}
+}
+
 // createPurgeIndexByTxidRangeStartKey returns a startKey to do a range query on index stored in transient store
 // using txid
 func createPurgeIndexByTxidRangeStartKey(txid string) []byte {
@@ -179,6 +186,21 @@ func createPurgeIndexByTxidRangeEndKey(txid string) []byte {
 	return endKey
 }
 
+// encodeEntrySize encodes the size of the value of an entry, which is stored as the value of its purge indexes.
+func encodeEntrySize(size int) []byte {
+	return proto.EncodeVarint(uint64(size))
+}
+
+// decodeEntrySize decodes the size of the value of an entry from the value of one of its purge indexes.
+// It returns false if the size is not recorded.
+func decodeEntrySize(purgeIndexValue []byte) (int64, bool) {
+	size, n := proto.DecodeVarint(purgeIndexValue)
+	if n == 0 {
+		return 0, false
+	}
+	return int64(size), true
+}
+
 // trimPvtWSet returns a `TxPvtReadWriteSet` that retains only list of 'ns/collections' supplied in the filter
 // A nil filter does not filter any results and returns the original `pvtWSet` as is
 func trimPvtWSet(pvtWSet *rwset.TxPvtReadWriteSet, filter ledger.PvtNsCollFilter) *rwset.TxPvtReadWriteSet {
@@ -19,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
 	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric-protos-go/transientstore"
+	"github.com/hyperledger/fabric/bccsp/sw"
 	"github.com/hyperledger/fabric/common/policydsl"
 	commonutil "github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
@@ -49,7 +50,7 @@ func initTestEnv(t *testing.T) *testEnv {
 	tempdir := t.TempDir()
 
 	storedir := filepath.Join(tempdir, "transientstore")
-	storeProvider, err := NewStoreProvider(storedir)
+	storeProvider, err := NewStoreProvider(&Config{Path: storedir})
 	require.NoError(t, err)
 	require.NotNil(t, storeProvider)
 
@@ -768,3 +769,172 @@ func TestDeleteMissingTransientStoreIsOK(t *testing.T) {
 	sp := env.storeProvider.(*storeProvider)
 	require.NoError(t, sp.deleteStore("_not_a_valid_store"))
 }
+
+func retrieveTestResults(t *testing.T, store *Store, txid string) ([]*EndorserPvtSimulationResults, error) {
+	iter, err := store.GetTxPvtRWSetByTxid(txid, nil)
+	require.NoError(t, err)
+	defer iter.Close()
+	var results []*EndorserPvtSimulationResults
+	for {
+		result, err := iter.Next()
+		if err != nil || result == nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+}
+
+func TestTransientStoreEncryption(t *testing.T) {
+	storedir := filepath.Join(t.TempDir(), "transientstore")
+	csp, err := sw.NewDefaultSecurityLevel(t.TempDir())
+	require.NoError(t, err)
+	samplePvtRWSetWithConfig := samplePvtDataWithConfigInfo(t)
+
+	openStore := func(conf *Config) (StoreProvider, *Store) {
+		conf.Path = storedir
+		provider, err := NewStoreProvider(conf)
+		require.NoError(t, err)
+		store, err := provider.OpenStore("TestStore")
+		require.NoError(t, err)
+		return provider, store
+	}
+
+	// persist a value in plaintext before enabling the encryption
+	provider, store := openStore(&Config{})
+	require.Nil(t, provider.EncryptionKeySKI())
+	require.NoError(t, store.Persist("txid-plaintext", 5, samplePvtRWSetWithConfig))
+	provider.Close()
+
+	provider, store = openStore(&Config{EncryptionEnabled: true, CryptoProvider: csp})
+	firstSKI := provider.EncryptionKeySKI()
+	require.NotNil(t, firstSKI)
+	require.NoError(t, store.Persist("txid-1", 10, samplePvtRWSetWithConfig))
+	_, err = provider.RotateEncryptionKey()
+	require.NoError(t, err)
+	require.NotEqual(t, firstSKI, provider.EncryptionKeySKI())
+	require.NoError(t, store.Persist("txid-2", 10, samplePvtRWSetWithConfig))
+
+	// the values are encrypted at rest and authenticated along with their key
+	iter, err := store.db.GetIterator(createTxidRangeStartKey("txid-1"), createTxidRangeEndKey("txid-1"))
+	require.NoError(t, err)
+	require.True(t, iter.Next())
+	encryptedValue := append([]byte(nil), iter.Value()...)
+	iter.Release()
+	require.Equal(t, encryptedByte, encryptedValue[0])
+	require.NotContains(t, string(encryptedValue), "RandomBytes-PvtRWSet")
+
+	for _, txid := range []string{"txid-plaintext", "txid-1", "txid-2"} {
+		results, err := retrieveTestResults(t, store, txid)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		require.True(t, proto.Equal(samplePvtRWSetWithConfig, results[0].PvtSimulationResultsWithConfig))
+	}
+	provider.Close()
+
+	t.Run("encryption disabled afterwards", func(t *testing.T) {
+		provider, store := openStore(&Config{CryptoProvider: csp})
+		defer provider.Close()
+		require.Nil(t, provider.EncryptionKeySKI())
+		_, err := provider.RotateEncryptionKey()
+		require.EqualError(t, err, "transient storage encryption is not enabled")
+
+		results, err := retrieveTestResults(t, store, "txid-1")
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+
+		require.NoError(t, store.Persist("txid-3", 10, samplePvtRWSetWithConfig))
+		iter, err := store.db.GetIterator(createTxidRangeStartKey("txid-3"), createTxidRangeEndKey("txid-3"))
+		require.NoError(t, err)
+		require.True(t, iter.Next())
+		require.Equal(t, nilByte, iter.Value()[0])
+		iter.Release()
+	})
+
+	t.Run("encrypted values without crypto provider", func(t *testing.T) {
+		provider, store := openStore(&Config{})
+		defer provider.Close()
+		_, err := retrieveTestResults(t, store, "txid-1")
+		require.EqualError(t, err, "private data of txid [txid-1] is encrypted but the transient store encryption is not configured")
+
+		results, err := retrieveTestResults(t, store, "txid-plaintext")
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+	})
+
+	t.Run("value moved to another key", func(t *testing.T) {
+		provider, store := openStore(&Config{CryptoProvider: csp})
+		defer provider.Close()
+		require.NoError(t, store.db.Put(createCompositeKeyForPvtRWSet("txid-4", "uuid", 10), encryptedValue, true))
+		_, err := retrieveTestResults(t, store, "txid-4")
+		require.EqualError(t, err, "decrypting the private data of txid [txid-4]: error while decrypting the envelope: cipher: message authentication failed")
+	})
+
+	t.Run("encryption enabled without crypto provider", func(t *testing.T) {
+		_, err := NewStoreProvider(&Config{Path: storedir, EncryptionEnabled: true})
+		require.EqualError(t, err, "could not initialize the encryption of the storage in folder ["+storedir+"]: a crypto provider is required to encrypt the transient storage")
+
+		// the provider released the file lock
+		provider, _ := openStore(&Config{})
+		provider.Close()
+	})
+}
+
+func TestTransientStoreStats(t *testing.T) {
+	env := initTestEnv(t)
+	samplePvtRWSetWithConfig := samplePvtDataWithConfigInfo(t)
+	require.Equal(t, Stats{LedgerID: "TestStore"}, env.store.Stats())
+
+	require.NoError(t, env.store.persistOldProto("txid-1", 8, samplePvtData(t)))
+	require.NoError(t, env.store.Persist("txid-1", 10, samplePvtRWSetWithConfig))
+	require.NoError(t, env.store.Persist("txid-2", 11, samplePvtRWSetWithConfig))
+	require.NoError(t, env.store.Persist("txid-3", 12, samplePvtRWSetWithConfig))
+
+	// the entries persisted with the old proto are not counted until the store is reopened
+	newProtoSize := env.store.Stats().SizeBytes / 3
+	require.Equal(t, Stats{LedgerID: "TestStore", Entries: 3, SizeBytes: 3 * newProtoSize, OldestEntryHeight: 10}, env.store.Stats())
+
+	oldProtoBytes, err := proto.Marshal(samplePvtData(t))
+	require.NoError(t, err)
+	env.storeProvider.Close()
+	env.storeProvider, err = NewStoreProvider(&Config{Path: env.storedir})
+	require.NoError(t, err)
+	defer env.storeProvider.Close()
+	store, err := env.storeProvider.OpenStore("TestStore")
+	require.NoError(t, err)
+	require.Equal(t, Stats{
+		LedgerID:          "TestStore",
+		Entries:           4,
+		SizeBytes:         3*newProtoSize + int64(len(oldProtoBytes)),
+		OldestEntryHeight: 8,
+	}, store.Stats())
+
+	require.NoError(t, store.PurgeByTxids([]string{"txid-1"}))
+	require.Equal(t, Stats{
+		LedgerID:          "TestStore",
+		Entries:           2,
+		SizeBytes:         2 * newProtoSize,
+		OldestEntryHeight: 11,
+		PurgedEntries:     2,
+	}, store.Stats())
+
+	require.NoError(t, store.PurgeBelowHeight(12))
+	require.Equal(t, Stats{
+		LedgerID:          "TestStore",
+		Entries:           1,
+		SizeBytes:         newProtoSize,
+		OldestEntryHeight: 12,
+		PurgedBelowHeight: 12,
+		PurgedEntries:     3,
+	}, store.Stats())
+
+	require.NoError(t, store.PurgeBelowHeight(13))
+	require.Equal(t, Stats{LedgerID: "TestStore", PurgedBelowHeight: 13, PurgedEntries: 4}, store.Stats())
+
+	other, err := env.storeProvider.OpenStore("OtherStore")
+	require.NoError(t, err)
+	require.NoError(t, other.Persist("txid-1", 1, samplePvtRWSetWithConfig))
+	require.Equal(t, []Stats{
+		{LedgerID: "OtherStore", Entries: 1, SizeBytes: newProtoSize, OldestEntryHeight: 1},
+		{LedgerID: "TestStore", PurgedBelowHeight: 13, PurgedEntries: 4},
+	}, env.storeProvider.Stats())
+}
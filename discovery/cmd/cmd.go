@@ -19,6 +19,7 @@ const (
 	PeersCommand     = "peers"
 	ConfigCommand    = "config"
 	EndorsersCommand = "endorsers"
+	PlanCommand      = "plan"
 )
 
 // responseParserWriter defines the stdout
@@ -81,4 +82,24 @@ func AddCommands(cli CommandRegistrar) {
 	endorserCmd.SetChaincodes(chaincodes)
 	endorserCmd.SetCollections(collections)
 	endorserCmd.SetNoPrivateReads(noPrivReads)
+
+	planCmd := NewPlanCmd(&ClientStub{}, &PlanResponseParser{Writer: responseParserWriter})
+	plan := cli.Command(PlanCommand, "Evaluate an endorsement policy and collection config against the current channel membership", planCmd.Execute)
+	planChaincode := plan.Flag("chaincode", "Specifies the chaincode name the plan is made for").String()
+	policy := plan.Flag("policy", "Specifies the signature policy to evaluate").String()
+	collectionsConfig := plan.Flag("collectionsConfig", "Specifies the path to the collection config file to evaluate").String()
+	planCollections := plan.Flag("collection", "Specifies the name(s) of the collections that are written to").Strings()
+	planNoPrivReads := plan.Flag("noPrivateReads", "Specifies that the collections are not expected to be read from").Bool()
+	planConfigBlock := plan.Flag("configBlock", "Specifies the path to the latest config block of the channel").String()
+
+	server = plan.Flag("server", "Sets the endpoint of the server to connect").String()
+	channel = plan.Flag("channel", "Sets the channel the query is intended to").String()
+	planCmd.SetChannel(channel)
+	planCmd.SetServer(server)
+	planCmd.SetChaincode(planChaincode)
+	planCmd.SetPolicy(policy)
+	planCmd.SetCollectionsConfig(collectionsConfig)
+	planCmd.SetCollections(planCollections)
+	planCmd.SetNoPrivateReads(planNoPrivReads)
+	planCmd.SetConfigBlock(planConfigBlock)
 }
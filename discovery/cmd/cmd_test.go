@@ -23,13 +23,18 @@ func TestAddCommands(t *testing.T) {
 	cli.On("Command", discovery.PeersCommand, mock.Anything, configFunc).Return(app.Command(discovery.PeersCommand, ""))
 	cli.On("Command", discovery.ConfigCommand, mock.Anything, configFunc).Return(app.Command(discovery.ConfigCommand, ""))
 	cli.On("Command", discovery.EndorsersCommand, mock.Anything, configFunc).Return(app.Command(discovery.EndorsersCommand, ""))
+	cli.On("Command", discovery.PlanCommand, mock.Anything, configFunc).Return(app.Command(discovery.PlanCommand, ""))
 	discovery.AddCommands(cli)
 	// Ensure that serve and channel flags are were configured for the sub-commands
-	for _, cmd := range []string{discovery.PeersCommand, discovery.ConfigCommand, discovery.EndorsersCommand} {
+	for _, cmd := range []string{discovery.PeersCommand, discovery.ConfigCommand, discovery.EndorsersCommand, discovery.PlanCommand} {
 		require.NotNil(t, app.GetCommand(cmd).GetFlag("server"))
 		require.NotNil(t, app.GetCommand(cmd).GetFlag("channel"))
 	}
 	// Ensure that chaincode and collection flags were called for the endorsers
 	require.NotNil(t, app.GetCommand(discovery.EndorsersCommand).GetFlag("chaincode"))
 	require.NotNil(t, app.GetCommand(discovery.EndorsersCommand).GetFlag("collection"))
+	// Ensure that policy and collection flags were called for the plan
+	require.NotNil(t, app.GetCommand(discovery.PlanCommand).GetFlag("policy"))
+	require.NotNil(t, app.GetCommand(discovery.PlanCommand).GetFlag("collectionsConfig"))
+	require.NotNil(t, app.GetCommand(discovery.PlanCommand).GetFlag("collection"))
 }
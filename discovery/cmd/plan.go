@@ -0,0 +1,280 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hyperledger/fabric-protos-go/discovery"
+	mspprotos "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/cmd/common"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/policydsl"
+	discoveryclient "github.com/hyperledger/fabric/discovery/client"
+	"github.com/hyperledger/fabric/discovery/endorsement"
+	"github.com/hyperledger/fabric/gossip/api"
+	gcommon "github.com/hyperledger/fabric/gossip/common"
+	gdiscovery "github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/hyperledger/fabric/internal/pkg/collections"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// NewPlanCmd creates a new PlanCmd
+func NewPlanCmd(stub Stub, parser *PlanResponseParser) *PlanCmd {
+	return &PlanCmd{
+		stub:   stub,
+		parser: parser,
+	}
+}
+
+// PlanCmd executes a command that evaluates a hypothetical endorsement policy
+// and collection config against the current membership of a channel
+type PlanCmd struct {
+	stub              Stub
+	server            *string
+	channel           *string
+	chaincode         *string
+	policy            *string
+	collectionsConfig *string
+	collections       *[]string
+	noPrivReads       *bool
+	configBlock       *string
+	parser            *PlanResponseParser
+}
+
+// SetServer sets the server
+func (pc *PlanCmd) SetServer(server *string) {
+	pc.server = server
+}
+
+// SetChannel sets the channel
+func (pc *PlanCmd) SetChannel(channel *string) {
+	pc.channel = channel
+}
+
+// SetChaincode sets the name of the chaincode the plan is made for
+func (pc *PlanCmd) SetChaincode(chaincode *string) {
+	pc.chaincode = chaincode
+}
+
+// SetPolicy sets the endorsement policy to be evaluated
+func (pc *PlanCmd) SetPolicy(policy *string) {
+	pc.policy = policy
+}
+
+// SetCollectionsConfig sets the path of the collection config file to be evaluated
+func (pc *PlanCmd) SetCollectionsConfig(collectionsConfig *string) {
+	pc.collectionsConfig = collectionsConfig
+}
+
+// SetCollections sets the collections that the invocation writes to
+func (pc *PlanCmd) SetCollections(collections *[]string) {
+	pc.collections = collections
+}
+
+// SetNoPrivateReads sets whether the invocation reads from the collections
+func (pc *PlanCmd) SetNoPrivateReads(noPrivReads *bool) {
+	pc.noPrivReads = noPrivReads
+}
+
+// SetConfigBlock sets the path of the config block of the channel
+func (pc *PlanCmd) SetConfigBlock(configBlock *string) {
+	pc.configBlock = configBlock
+}
+
+// Execute executes the command
+func (pc *PlanCmd) Execute(conf common.Config) error {
+	if pc.channel == nil || *pc.channel == "" {
+		return errors.New("no channel specified")
+	}
+
+	if pc.server == nil || *pc.server == "" {
+		return errors.New("no server specified")
+	}
+
+	server := *pc.server
+	channel := *pc.channel
+
+	plan, err := pc.plan()
+	if err != nil {
+		return err
+	}
+
+	if pc.configBlock == nil || *pc.configBlock == "" {
+		return errors.New("no config block specified")
+	}
+	mspManager, err := channelMSPManager(channel, *pc.configBlock)
+	if err != nil {
+		return err
+	}
+
+	req := discoveryclient.NewRequest().OfChannel(channel).AddPeersQuery()
+	res, err := pc.stub.Send(server, conf, req)
+	if err != nil {
+		return err
+	}
+
+	return pc.parser.ParseResponse(channel, plan, mspManager, res)
+}
+
+func (pc *PlanCmd) plan() (*endorsement.Plan, error) {
+	if pc.policy == nil || *pc.policy == "" {
+		return nil, errors.New("no policy specified")
+	}
+	policy, err := policydsl.FromString(*pc.policy)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid policy %s", *pc.policy)
+	}
+
+	plan := &endorsement.Plan{
+		Policy: policy,
+	}
+	if pc.chaincode != nil {
+		plan.Chaincode = *pc.chaincode
+	}
+	if pc.noPrivReads != nil {
+		plan.NoPrivateReads = *pc.noPrivReads
+	}
+	if pc.collections != nil {
+		plan.CollectionNames = *pc.collections
+	}
+	if pc.collectionsConfig != nil && *pc.collectionsConfig != "" {
+		plan.CollectionsConfig, _, err = collections.ConfigFromFile(*pc.collectionsConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(plan.CollectionNames) > 0 && plan.CollectionsConfig == nil {
+		return nil, errors.New("collections were specified but no collection config was given")
+	}
+	return plan, nil
+}
+
+// channelMSPManager returns the MSPs of the given channel, as set up by the config block in the given file.
+// The discovery service does not convey the capabilities of the channel, which determine the version of the
+// MSPs, hence the config block is read instead of the config that the peer responds with.
+func channelMSPManager(channel, configBlockPath string) (msp.MSPManager, error) {
+	blockBytes, err := os.ReadFile(configBlockPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read config block '%s'", configBlockPath)
+	}
+	block, err := protoutil.UnmarshalBlock(blockBytes)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not parse config block '%s'", configBlockPath)
+	}
+	envelope, err := protoutil.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not parse config block '%s'", configBlockPath)
+	}
+
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := channelconfig.NewBundleFromEnvelope(envelope, cryptoProvider)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid config block '%s'", configBlockPath)
+	}
+	if blockChannel := bundle.ConfigtxValidator().ChannelID(); blockChannel != channel {
+		return nil, errors.Errorf("config block '%s' is of channel %s, not %s", configBlockPath, blockChannel, channel)
+	}
+	return bundle.MSPManager(), nil
+}
+
+// PlanResponseParser evaluates endorsement plans against the channel MSPs
+// and the channel membership that the peer responds with
+type PlanResponseParser struct {
+	io.Writer
+}
+
+// ParseResponse evaluates the given plan against the given MSPs and the peers of the given channel in the given response
+func (parser *PlanResponseParser) ParseResponse(channel string, plan *endorsement.Plan, mspManager msp.MSPManager, res ServiceResponse) error {
+	peers, err := res.ForChannel(channel).Peers()
+	if err != nil {
+		return err
+	}
+
+	evaluator := &mspPrincipalEvaluator{MSPManager: mspManager}
+	analyzer := endorsement.NewEndorsementAnalyzer(newChannelMembership(peers), nil, evaluator, nil)
+	desc, err := analyzer.PeersForPlan(gcommon.ChannelID(channel), plan)
+	if err != nil {
+		return err
+	}
+
+	jsonBytes, _ := json.MarshalIndent(parseEndorsementDescriptors([]*discovery.EndorsementDescriptor{desc}), "", "\t")
+	fmt.Fprintln(parser.Writer, string(jsonBytes))
+	return nil
+}
+
+// mspPrincipalEvaluator evaluates principals with the MSPs of a channel config
+type mspPrincipalEvaluator struct {
+	msp.MSPManager
+}
+
+// SatisfiesPrincipal returns whether the given peer identity satisfies the given principal
+func (e *mspPrincipalEvaluator) SatisfiesPrincipal(_ string, identity []byte, principal *mspprotos.MSPPrincipal) error {
+	id, err := e.DeserializeIdentity(identity)
+	if err != nil {
+		return err
+	}
+	return id.SatisfiesPrincipal(principal)
+}
+
+// channelMembership is the membership of a channel as the peer that answered the query sees it
+type channelMembership struct {
+	alive      gdiscovery.Members
+	ofChannel  gdiscovery.Members
+	identities api.PeerIdentitySet
+}
+
+func newChannelMembership(peers []*discoveryclient.Peer) *channelMembership {
+	membership := &channelMembership{}
+	for _, p := range peers {
+		aliveMsg := p.AliveMessage.GetAliveMsg()
+		stateInfoMsg := p.StateInfoMessage.GetStateInfo()
+		if aliveMsg == nil || aliveMsg.Membership == nil || stateInfoMsg == nil {
+			continue
+		}
+		membership.alive = append(membership.alive, gdiscovery.NetworkMember{
+			PKIid:    aliveMsg.Membership.PkiId,
+			Endpoint: aliveMsg.Membership.Endpoint,
+			Envelope: p.AliveMessage.Envelope,
+		})
+		membership.ofChannel = append(membership.ofChannel, gdiscovery.NetworkMember{
+			PKIid:      stateInfoMsg.PkiId,
+			Properties: stateInfoMsg.Properties,
+			Envelope:   p.StateInfoMessage.Envelope,
+		})
+		membership.identities = append(membership.identities, api.PeerIdentityInfo{
+			PKIId:        aliveMsg.Membership.PkiId,
+			Identity:     p.Identity,
+			Organization: api.OrgIdentityType(p.MSPID),
+		})
+	}
+	return membership
+}
+
+// IdentityInfo returns the identities of the peers
+func (cm *channelMembership) IdentityInfo() api.PeerIdentitySet {
+	return cm.identities
+}
+
+// PeersOfChannel returns the peers that joined the channel
+func (cm *channelMembership) PeersOfChannel(gcommon.ChannelID) gdiscovery.Members {
+	return cm.ofChannel
+}
+
+// Peers returns the peers that are alive
+func (cm *channelMembership) Peers() gdiscovery.Members {
+	return cm.alive
+}
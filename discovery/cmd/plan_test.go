@@ -0,0 +1,303 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	discprotos "github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/hyperledger/fabric-protos-go/gossip"
+	mspprotos "github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/cmd/common"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
+	"github.com/hyperledger/fabric/common/policydsl"
+	"github.com/hyperledger/fabric/core/config/configtest"
+	. "github.com/hyperledger/fabric/discovery/client"
+	discovery "github.com/hyperledger/fabric/discovery/cmd"
+	"github.com/hyperledger/fabric/discovery/cmd/mocks"
+	"github.com/hyperledger/fabric/discovery/endorsement"
+	"github.com/hyperledger/fabric/gossip/protoext"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanCmd(t *testing.T) {
+	server := "peer0"
+	channel := "mychannel"
+	policy := "OR('Org1MSP.peer')"
+	stub := &mocks.Stub{}
+	parser := &discovery.PlanResponseParser{Writer: &bytes.Buffer{}}
+	configBlock := writeConfigBlock(t, channel)
+
+	t.Run("no channel supplied", func(t *testing.T) {
+		cmd := discovery.NewPlanCmd(stub, parser)
+		cmd.SetServer(&server)
+
+		err := cmd.Execute(common.Config{})
+		require.EqualError(t, err, "no channel specified")
+	})
+
+	t.Run("no server supplied", func(t *testing.T) {
+		cmd := discovery.NewPlanCmd(stub, parser)
+		cmd.SetChannel(&channel)
+
+		err := cmd.Execute(common.Config{})
+		require.EqualError(t, err, "no server specified")
+	})
+
+	t.Run("no policy supplied", func(t *testing.T) {
+		cmd := discovery.NewPlanCmd(stub, parser)
+		cmd.SetChannel(&channel)
+		cmd.SetServer(&server)
+
+		err := cmd.Execute(common.Config{})
+		require.EqualError(t, err, "no policy specified")
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		invalidPolicy := "OR('Org1MSP.peer'"
+		cmd := discovery.NewPlanCmd(stub, parser)
+		cmd.SetChannel(&channel)
+		cmd.SetServer(&server)
+		cmd.SetPolicy(&invalidPolicy)
+
+		err := cmd.Execute(common.Config{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid policy OR('Org1MSP.peer'")
+	})
+
+	t.Run("collections without collection config", func(t *testing.T) {
+		collections := []string{"col1"}
+		cmd := discovery.NewPlanCmd(stub, parser)
+		cmd.SetChannel(&channel)
+		cmd.SetServer(&server)
+		cmd.SetPolicy(&policy)
+		cmd.SetCollections(&collections)
+
+		err := cmd.Execute(common.Config{})
+		require.EqualError(t, err, "collections were specified but no collection config was given")
+	})
+
+	t.Run("missing collection config file", func(t *testing.T) {
+		collectionsConfig := filepath.Join(t.TempDir(), "collections.json")
+		cmd := discovery.NewPlanCmd(stub, parser)
+		cmd.SetChannel(&channel)
+		cmd.SetServer(&server)
+		cmd.SetPolicy(&policy)
+		cmd.SetCollectionsConfig(&collectionsConfig)
+
+		err := cmd.Execute(common.Config{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "could not read file")
+	})
+
+	t.Run("no config block supplied", func(t *testing.T) {
+		cmd := discovery.NewPlanCmd(stub, parser)
+		cmd.SetChannel(&channel)
+		cmd.SetServer(&server)
+		cmd.SetPolicy(&policy)
+
+		err := cmd.Execute(common.Config{})
+		require.EqualError(t, err, "no config block specified")
+	})
+
+	t.Run("missing config block", func(t *testing.T) {
+		missingConfigBlock := filepath.Join(t.TempDir(), "config.block")
+		cmd := discovery.NewPlanCmd(stub, parser)
+		cmd.SetChannel(&channel)
+		cmd.SetServer(&server)
+		cmd.SetPolicy(&policy)
+		cmd.SetConfigBlock(&missingConfigBlock)
+
+		err := cmd.Execute(common.Config{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "could not read config block")
+	})
+
+	t.Run("config block of another channel", func(t *testing.T) {
+		otherConfigBlock := writeConfigBlock(t, "otherchannel")
+		cmd := discovery.NewPlanCmd(stub, parser)
+		cmd.SetChannel(&channel)
+		cmd.SetServer(&server)
+		cmd.SetPolicy(&policy)
+		cmd.SetConfigBlock(&otherConfigBlock)
+
+		err := cmd.Execute(common.Config{})
+		require.EqualError(t, err, fmt.Sprintf("config block '%s' is of channel otherchannel, not mychannel", otherConfigBlock))
+	})
+
+	t.Run("Server return error", func(t *testing.T) {
+		cmd := discovery.NewPlanCmd(stub, parser)
+		cmd.SetChannel(&channel)
+		cmd.SetServer(&server)
+		cmd.SetPolicy(&policy)
+		cmd.SetConfigBlock(&configBlock)
+		stub.On("Send", server, mock.Anything, mock.Anything).Return(nil, errors.New("deadline exceeded")).Once()
+
+		err := cmd.Execute(common.Config{})
+		require.Contains(t, err.Error(), "deadline exceeded")
+	})
+
+	t.Run("Plan with collections", func(t *testing.T) {
+		collectionsConfig := filepath.Join(t.TempDir(), "collections.json")
+		err := os.WriteFile(collectionsConfig, []byte(`[{"name": "col1", "policy": "OR('SampleOrg.member')"}]`), 0o600)
+		require.NoError(t, err)
+		samplePolicy := "OR('SampleOrg.member')"
+		collections := []string{"col1"}
+
+		buff := &bytes.Buffer{}
+		cmd := discovery.NewPlanCmd(stub, &discovery.PlanResponseParser{Writer: buff})
+		cmd.SetChannel(&channel)
+		cmd.SetServer(&server)
+		cmd.SetPolicy(&samplePolicy)
+		cmd.SetCollectionsConfig(&collectionsConfig)
+		cmd.SetCollections(&collections)
+		cmd.SetConfigBlock(&configBlock)
+		stub.On("Send", server, mock.Anything, mock.Anything).Return(sampleOrgResponse(t), nil).Once()
+
+		err = cmd.Execute(common.Config{})
+		require.NoError(t, err)
+		require.Contains(t, buff.String(), `"MSPID": "SampleOrg"`)
+	})
+}
+
+func TestParsePlanResponse(t *testing.T) {
+	buff := &bytes.Buffer{}
+	parser := &discovery.PlanResponseParser{Writer: buff}
+	mspManager := sampleMSPManager(t)
+
+	t.Run("Failure", func(t *testing.T) {
+		chanRes := &mocks.ChannelResponse{}
+		chanRes.On("Peers").Return(nil, errors.New("not found"))
+		res := &mocks.ServiceResponse{}
+		res.On("ForChannel", "mychannel").Return(chanRes)
+
+		err := parser.ParseResponse("mychannel", &endorsement.Plan{}, mspManager, res)
+		require.EqualError(t, err, "not found")
+	})
+
+	t.Run("Unsatisfiable policy", func(t *testing.T) {
+		plan := &endorsement.Plan{
+			Chaincode: "mycc",
+			Policy:    policydsl.SignedByAnyMember([]string{"Org1MSP"}),
+		}
+		err := parser.ParseResponse("mychannel", plan, mspManager, sampleOrgResponse(t))
+		require.EqualError(t, err, "no peer combination can satisfy the endorsement policy")
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		buff.Reset()
+		plan := &endorsement.Plan{
+			Chaincode: "mycc",
+			Policy:    policydsl.SignedByAnyMember([]string{"SampleOrg"}),
+		}
+		err := parser.ParseResponse("mychannel", plan, mspManager, sampleOrgResponse(t))
+		require.NoError(t, err)
+
+		var descriptors []struct {
+			Chaincode         string
+			EndorsersByGroups map[string][]struct {
+				MSPID        string
+				LedgerHeight uint64
+				Endpoint     string
+			}
+			Layouts []*discprotos.Layout
+		}
+		require.NoError(t, json.Unmarshal(buff.Bytes(), &descriptors))
+		require.Len(t, descriptors, 1)
+		require.Equal(t, "mycc", descriptors[0].Chaincode)
+		require.Len(t, descriptors[0].Layouts, 1)
+		require.Equal(t, map[string]uint32{"G0": 1}, descriptors[0].Layouts[0].QuantitiesByGroup)
+		require.Len(t, descriptors[0].EndorsersByGroups["G0"], 1)
+		endorser := descriptors[0].EndorsersByGroups["G0"][0]
+		require.Equal(t, "SampleOrg", endorser.MSPID)
+		require.Equal(t, uint64(100), endorser.LedgerHeight)
+		require.Equal(t, "p0", endorser.Endpoint)
+	})
+}
+
+// writeConfigBlock writes the config block of a channel made of the sample MSP
+// and returns the path of the file it was written to
+func writeConfigBlock(t *testing.T, channel string) string {
+	block, err := configtxtest.MakeGenesisBlock(channel)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "config.block")
+	require.NoError(t, os.WriteFile(path, protoutil.MarshalOrPanic(block), 0o600))
+	return path
+}
+
+// sampleMSPManager returns the MSPs of a channel made of the sample MSP
+func sampleMSPManager(t *testing.T) msp.MSPManager {
+	block, err := configtxtest.MakeGenesisBlock("mychannel")
+	require.NoError(t, err)
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	require.NoError(t, err)
+	bundle, err := channelconfig.NewBundleFromEnvelope(protoutil.ExtractEnvelopeOrPanic(block, 0), cryptoProvider)
+	require.NoError(t, err)
+	return bundle.MSPManager()
+}
+
+// sampleOrgResponse returns a response with a single peer of a channel made of
+// the sample MSP, which uses the sample signing certificate
+func sampleOrgResponse(t *testing.T) *mocks.ServiceResponse {
+	signCert, err := os.ReadFile(filepath.Join(configtest.GetDevMspDir(), "signcerts", "peer.pem"))
+	require.NoError(t, err)
+	identity := protoutil.MarshalOrPanic(&mspprotos.SerializedIdentity{
+		Mspid:   "SampleOrg",
+		IdBytes: signCert,
+	})
+
+	chanRes := &mocks.ChannelResponse{}
+	chanRes.On("Peers").Return([]*Peer{
+		{
+			MSPID:            "SampleOrg",
+			Identity:         identity,
+			AliveMessage:     aliveMessageWithPKIID(0),
+			StateInfoMessage: stateInfoMessageWithPKIID(0, 100),
+		},
+	}, nil)
+	res := &mocks.ServiceResponse{}
+	res.On("ForChannel", "mychannel").Return(chanRes)
+	return res
+}
+
+func aliveMessageWithPKIID(id int) *protoext.SignedGossipMessage {
+	sMsg := aliveMessage(id)
+	sMsg.GetAliveMsg().Membership.PkiId = []byte(fmt.Sprintf("p%d", id))
+	sMsg, _ = protoext.NoopSign(sMsg.GossipMessage)
+	return sMsg
+}
+
+func stateInfoMessageWithPKIID(id int, height uint64) *protoext.SignedGossipMessage {
+	g := &gossip.GossipMessage{
+		Content: &gossip.GossipMessage_StateInfo{
+			StateInfo: &gossip.StateInfo{
+				PkiId: []byte(fmt.Sprintf("p%d", id)),
+				Timestamp: &gossip.PeerTime{
+					SeqNum: 5,
+					IncNum: uint64(time.Now().UnixNano()),
+				},
+				Properties: &gossip.Properties{
+					LedgerHeight: height,
+				},
+			},
+		},
+	}
+	sMsg, _ := protoext.NoopSign(g)
+	return sMsg
+}
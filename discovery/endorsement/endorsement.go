@@ -288,21 +288,9 @@ func (ea *endorsementAnalyzer) computePrincipalSets(channelID common.ChannelID,
 		inquireablePoliciesForChaincodeAndCollections = append(inquireablePoliciesForChaincodeAndCollections, policies...)
 	}
 
-	var cpss []inquire.ComparablePrincipalSets
-
-	for _, policy := range inquireablePoliciesForChaincodeAndCollections {
-		var cmpsets inquire.ComparablePrincipalSets
-		for _, ps := range policy.SatisfiedBy() {
-			cps := inquire.NewComparablePrincipalSet(ps)
-			if cps == nil {
-				return nil, errors.New("failed creating a comparable principal set")
-			}
-			cmpsets = append(cmpsets, cps)
-		}
-		if len(cmpsets) == 0 {
-			return nil, errors.New("endorsement policy cannot be satisfied")
-		}
-		cpss = append(cpss, cmpsets)
+	cpss, err := comparablePrincipalSetsOf(inquireablePoliciesForChaincodeAndCollections)
+	if err != nil {
+		return nil, errors.WithStack(err)
 	}
 
 	stateBasedCPS, err := computeStateBasedPrincipalSets(interest.Chaincodes, sessionLogger)
@@ -324,6 +312,26 @@ func (ea *endorsementAnalyzer) computePrincipalSets(channelID common.ChannelID,
 	return cps.ToPrincipalSets(), nil
 }
 
+// comparablePrincipalSetsOf computes the principal sets that satisfy each of the given policies
+func comparablePrincipalSetsOf(inquireablePolicies []policies.InquireablePolicy) ([]inquire.ComparablePrincipalSets, error) {
+	var cpss []inquire.ComparablePrincipalSets
+	for _, policy := range inquireablePolicies {
+		var cmpsets inquire.ComparablePrincipalSets
+		for _, ps := range policy.SatisfiedBy() {
+			cps := inquire.NewComparablePrincipalSet(ps)
+			if cps == nil {
+				return nil, errors.New("failed creating a comparable principal set")
+			}
+			cmpsets = append(cmpsets, cps)
+		}
+		if len(cmpsets) == 0 {
+			return nil, errors.New("endorsement policy cannot be satisfied")
+		}
+		cpss = append(cpss, cmpsets)
+	}
+	return cpss, nil
+}
+
 type metadataAndFilterContext struct {
 	chainID          common.ChannelID
 	interest         *peer.ChaincodeInterest
@@ -0,0 +1,134 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorsement
+
+import (
+	common2 "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/policies/inquire"
+	"github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// Plan is a hypothetical chaincode definition, made of an endorsement policy and
+// a collection config that need not be defined on the channel yet.
+type Plan struct {
+	// Chaincode is the name the resulting EndorsementDescriptor carries
+	Chaincode string
+	// Policy is the endorsement policy of the chaincode
+	Policy *common2.SignaturePolicyEnvelope
+	// CollectionsConfig is the collection config of the chaincode
+	CollectionsConfig *peer.CollectionConfigPackage
+	// CollectionNames are the collections that the invocation writes to
+	CollectionNames []string
+	// NoPrivateReads indicates that the invocation does not read from the collections,
+	// hence the endorsers need not be members of them
+	NoPrivateReads bool
+}
+
+// PeersForPlan returns an EndorsementDescriptor that lays out the combinations of alive peers of the channel
+// that satisfy the given plan. As the chaincode of the plan need not be defined on the channel, the chaincodes
+// installed on the peers are not taken into account.
+func (ea *endorsementAnalyzer) PeersForPlan(channelID common.ChannelID, plan *Plan) (*discovery.EndorsementDescriptor, error) {
+	if plan == nil || plan.Policy == nil {
+		return nil, errors.New("plan has no endorsement policy")
+	}
+	principalSetsByCollections, err := principalsFromCollectionConfig(plan.CollectionsConfig)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	members := ea.PeersOfChannel(channelID)
+	if len(plan.CollectionNames) > 0 && !plan.NoPrivateReads {
+		filter, err := principalSetsByCollections.toIdentityFilter(string(channelID), ea, &peer.ChaincodeCall{
+			Name:            plan.Chaincode,
+			CollectionNames: plan.CollectionNames,
+		})
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		members = members.Filter(filter.toMemberFilter(ea.IdentityInfo().ByID()))
+	}
+
+	inquireablePolicies, err := policiesOfPlan(plan)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	cpss, err := comparablePrincipalSetsOf(inquireablePolicies)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	cps, err := mergePrincipalSets(cpss)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	channelMembersById := members.ByID()
+	aliveMembership := ea.Peers().Intersect(members)
+	return ea.computeEndorsementResponse(&context{
+		chaincode:           plan.Chaincode,
+		channel:             string(channelID),
+		principalsSets:      cps.ToPrincipalSets(),
+		channelMembersById:  channelMembersById,
+		aliveMembership:     aliveMembership,
+		identitiesOfMembers: computeIdentitiesOfMembers(ea.IdentityInfo(), aliveMembership.ByID()),
+		// Every member is considered to have the chaincode of the plan installed
+		chaincodeMapping: channelMembersById,
+	})
+}
+
+// policiesOfPlan returns the policies that an invocation that writes to the collections of the plan
+// needs to satisfy, in the same way the chaincode support of the discovery service does for chaincodes
+// that are defined: a collection with an endorsement policy of its own replaces the chaincode policy.
+func policiesOfPlan(plan *Plan) ([]policies.InquireablePolicy, error) {
+	chaincodePolicy := inquire.NewInquireableSignaturePolicy(plan.Policy)
+	if len(plan.CollectionNames) == 0 {
+		return []policies.InquireablePolicy{chaincodePolicy}, nil
+	}
+
+	collectionPolicies := make(map[string]*common2.SignaturePolicyEnvelope)
+	for _, colConfig := range plan.CollectionsConfig.GetConfig() {
+		staticCol := colConfig.GetStaticCollectionConfig()
+		if staticCol == nil {
+			continue
+		}
+		collectionPolicies[staticCol.Name] = nil
+		if staticCol.EndorsementPolicy == nil {
+			continue
+		}
+		if staticCol.EndorsementPolicy.GetChannelConfigPolicyReference() != "" {
+			return nil, errors.Errorf("endorsement policy of collection %s refers to a channel config policy, only signature policies can be evaluated", staticCol.Name)
+		}
+		collectionPolicies[staticCol.Name] = staticCol.EndorsementPolicy.GetSignaturePolicy()
+	}
+
+	seen := make(map[string]struct{})
+	var res []policies.InquireablePolicy
+	for _, collectionName := range plan.CollectionNames {
+		pol, exists := collectionPolicies[collectionName]
+		if !exists {
+			return nil, errors.Errorf("collection %s doesn't exist in collection config for chaincode %s", collectionName, plan.Chaincode)
+		}
+		// default to the chaincode policy if the collection has no policy of its own
+		inquireablePolicy := chaincodePolicy
+		if pol != nil {
+			inquireablePolicy = inquire.NewInquireableSignaturePolicy(pol)
+		} else {
+			pol = plan.Policy
+		}
+		key := string(protoutil.MarshalOrPanic(pol))
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+		res = append(res, inquireablePolicy)
+	}
+	return res, nil
+}
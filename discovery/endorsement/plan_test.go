@@ -0,0 +1,157 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorsement
+
+import (
+	"testing"
+
+	discoveryprotos "github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/policydsl"
+	"github.com/hyperledger/fabric/gossip/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeersForPlan(t *testing.T) {
+	channel := common.ChannelID("test")
+	alivePeers := peerSet{
+		newPeer(0),
+		newPeer(2),
+		newPeer(6),
+		newPeer(11),
+		newPeer(12),
+	}
+	// None of the peers of the channel has the chaincode of the plan installed
+	chanPeers := peerSet{
+		newPeer(0),
+		newPeer(3),
+		newPeer(6),
+		newPeer(11),
+		newPeer(12),
+	}
+
+	endorsers := func(desc *discoveryprotos.EndorsementDescriptor) []string {
+		var res []string
+		for _, peers := range desc.EndorsersByGroups {
+			for _, p := range peers.Peers {
+				res = append(res, string(p.Identity))
+			}
+		}
+		return res
+	}
+
+	collections := buildCollectionConfig(map[string][]*msp.MSPPrincipal{
+		"col1": {peerRole("p0"), peerRole("p12")},
+		"col2": {peerRole("p11"), peerRole("p12")},
+	})
+	for _, colConfig := range collections.Config {
+		if colConfig.GetStaticCollectionConfig().Name == "col2" {
+			colConfig.GetStaticCollectionConfig().EndorsementPolicy = &peer.ApplicationPolicy{
+				Type: &peer.ApplicationPolicy_SignaturePolicy{
+					SignaturePolicy: policydsl.SignedByAnyPeer([]string{"Org11MSP"}),
+				},
+			}
+		}
+	}
+
+	for _, tst := range []struct {
+		name              string
+		plan              *Plan
+		expectedErr       string
+		expectedEndorsers []string
+		expectedLayouts   int
+	}{
+		{
+			name:        "no policy",
+			plan:        &Plan{Chaincode: "mycc"},
+			expectedErr: "plan has no endorsement policy",
+		},
+		{
+			name: "policy only",
+			plan: &Plan{
+				Chaincode: "mycc",
+				Policy:    policydsl.SignedByNOutOfGivenRole(2, msp.MSPRole_PEER, []string{"Org0MSP", "Org3MSP", "Org6MSP"}),
+			},
+			// p3 is not alive, hence only p0 and p6 make up a layout
+			expectedEndorsers: []string{peerIdentityString("p0"), peerIdentityString("p6")},
+			expectedLayouts:   1,
+		},
+		{
+			name: "policy that no alive peer satisfies",
+			plan: &Plan{
+				Chaincode: "mycc",
+				Policy:    policydsl.SignedByAnyPeer([]string{"Org2MSP", "Org3MSP"}),
+			},
+			expectedErr: "no peer combination can satisfy the endorsement policy",
+		},
+		{
+			name: "collection members only",
+			plan: &Plan{
+				Chaincode:         "mycc",
+				Policy:            policydsl.SignedByAnyPeer([]string{"Org0MSP", "Org6MSP", "Org12MSP"}),
+				CollectionsConfig: collections,
+				CollectionNames:   []string{"col1"},
+			},
+			expectedEndorsers: []string{peerIdentityString("p0"), peerIdentityString("p12")},
+			expectedLayouts:   2,
+		},
+		{
+			name: "collection without private reads",
+			plan: &Plan{
+				Chaincode:         "mycc",
+				Policy:            policydsl.SignedByAnyPeer([]string{"Org0MSP", "Org6MSP", "Org12MSP"}),
+				CollectionsConfig: collections,
+				CollectionNames:   []string{"col1"},
+				NoPrivateReads:    true,
+			},
+			expectedEndorsers: []string{peerIdentityString("p0"), peerIdentityString("p12"), peerIdentityString("p6")},
+			expectedLayouts:   3,
+		},
+		{
+			name: "collection endorsement policy",
+			plan: &Plan{
+				Chaincode:         "mycc",
+				Policy:            policydsl.SignedByAnyPeer([]string{"Org0MSP"}),
+				CollectionsConfig: collections,
+				CollectionNames:   []string{"col2"},
+			},
+			expectedEndorsers: []string{peerIdentityString("p11")},
+			expectedLayouts:   1,
+		},
+		{
+			name: "collection not in collection config",
+			plan: &Plan{
+				Chaincode:         "mycc",
+				Policy:            policydsl.SignedByAnyPeer([]string{"Org0MSP"}),
+				CollectionsConfig: collections,
+				CollectionNames:   []string{"col3"},
+				NoPrivateReads:    true,
+			},
+			expectedErr: "collection col3 doesn't exist in collection config for chaincode mycc",
+		},
+	} {
+		t.Run(tst.name, func(t *testing.T) {
+			g := &gossipMock{}
+			g.On("Peers").Return(alivePeers.toMembers())
+			g.On("IdentityInfo").Return(identitySet(pkiID2MSPID))
+			g.On("PeersOfChannel").Return(chanPeers.toMembers())
+
+			analyzer := NewEndorsementAnalyzer(g, nil, &principalEvaluatorMock{}, nil)
+			desc, err := analyzer.PeersForPlan(channel, tst.plan)
+			if tst.expectedErr != "" {
+				require.EqualError(t, err, tst.expectedErr)
+				require.Nil(t, desc)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "mycc", desc.Chaincode)
+			require.Len(t, desc.Layouts, tst.expectedLayouts)
+			require.ElementsMatch(t, tst.expectedEndorsers, endorsers(desc))
+		})
+	}
+}
@@ -37,6 +37,20 @@ func (_m *ApplicationCapabilities) CollectionUpgrade() bool {
 	return r0
 }
 
+// CrossChannelReads provides a mock function with given fields:
+func (_m *ApplicationCapabilities) CrossChannelReads() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // ForbidDuplicateTXIdInBlock provides a mock function with given fields:
 func (_m *ApplicationCapabilities) ForbidDuplicateTXIdInBlock() bool {
 	ret := _m.Called()
@@ -62,6 +62,7 @@ type Reconciler struct {
 	stopChan               chan struct{}
 	startOnce              sync.Once
 	stopOnce               sync.Once
+	configLock             sync.RWMutex
 	ReconciliationFetcher
 	committer.Committer
 }
@@ -96,6 +97,21 @@ func NewReconciler(channel string, metrics *metrics.PrivdataMetrics, c committer
 	}
 }
 
+// SetConfig changes the interval between reconciliation cycles and the number
+// of blocks reconciled at a time, from the next reconciliation cycle on.
+func (r *Reconciler) SetConfig(reconcileSleepInterval time.Duration, reconcileBatchSize int) {
+	r.configLock.Lock()
+	defer r.configLock.Unlock()
+	r.ReconcileSleepInterval = reconcileSleepInterval
+	r.ReconcileBatchSize = reconcileBatchSize
+}
+
+func (r *Reconciler) config() (time.Duration, int) {
+	r.configLock.RLock()
+	defer r.configLock.RUnlock()
+	return r.ReconcileSleepInterval, r.ReconcileBatchSize
+}
+
 func (r *Reconciler) Stop() {
 	r.stopOnce.Do(func() {
 		close(r.stopChan)
@@ -110,10 +126,11 @@ func (r *Reconciler) Start() {
 
 func (r *Reconciler) run() {
 	for {
+		reconcileSleepInterval, _ := r.config()
 		select {
 		case <-r.stopChan:
 			return
-		case <-time.After(r.ReconcileSleepInterval):
+		case <-time.After(reconcileSleepInterval):
 			r.logger.Debug("Start reconcile missing private info")
 			if err := r.reconcile(); err != nil {
 				r.logger.Error("Failed to reconcile missing private info, error: ", err.Error())
@@ -137,8 +154,9 @@ func (r *Reconciler) reconcile() error {
 
 	defer r.reportReconciliationDuration(time.Now())
 
+	_, reconcileBatchSize := r.config()
 	for {
-		missingPvtDataInfo, err := missingPvtDataTracker.GetMissingPvtDataInfoForMostRecentBlocks(r.ReconcileBatchSize)
+		missingPvtDataInfo, err := missingPvtDataTracker.GetMissingPvtDataInfoForMostRecentBlocks(reconcileBatchSize)
 		if err != nil {
 			r.logger.Error("reconciliation error when trying to get missing pvt data info recent blocks:", err)
 			return err
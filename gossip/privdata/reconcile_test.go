@@ -52,6 +52,29 @@ func TestNoItemsToReconcile(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestReconcilerSetConfig(t *testing.T) {
+	// Scenario: the batch size is changed after the reconciler was created.
+	// reconciler should get the missing private data of the recent blocks in batches of the new size.
+	committer := &mocks.Committer{}
+	fetcher := &mocks.ReconciliationFetcher{}
+	missingPvtDataTracker := &mocks.MissingPvtDataTracker{}
+
+	missingPvtDataTracker.On("GetMissingPvtDataInfoForMostRecentBlocks", 5).Return(ledger.MissingPvtDataInfo{}, nil)
+	committer.On("GetMissingPvtDataTracker").Return(missingPvtDataTracker, nil)
+
+	r := NewReconciler("mychannel", metrics.NewGossipMetrics(&disabled.Provider{}).PrivdataMetrics, committer, fetcher,
+		&PrivdataConfig{ReconcileSleepInterval: time.Minute, ReconcileBatchSize: 1, ReconciliationEnabled: true})
+	r.SetConfig(time.Second, 5)
+
+	reconcileSleepInterval, reconcileBatchSize := r.config()
+	require.Equal(t, time.Second, reconcileSleepInterval)
+	require.Equal(t, 5, reconcileBatchSize)
+
+	err := r.reconcile()
+	require.NoError(t, err)
+	missingPvtDataTracker.AssertCalled(t, "GetMissingPvtDataInfoForMostRecentBlocks", 5)
+}
+
 func TestNotReconcilingWhenCollectionConfigNotAvailable(t *testing.T) {
 	// Scenario: reconciler gets an error when trying to read collection config for the missing private data.
 	// as a result it removes the digest slice, and there are no digests to pull.
@@ -9,6 +9,7 @@ package service
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	gproto "github.com/hyperledger/fabric-protos-go/gossip"
 	tspb "github.com/hyperledger/fabric-protos-go/transientstore"
@@ -173,6 +174,15 @@ type GossipService struct {
 	serviceConfig     *ServiceConfig
 	privdataConfig    *gossipprivdata.PrivdataConfig
 	anchorPeerTracker *anchorPeerTracker
+
+	// deliverRetryThresholds overrides the retry thresholds of the delivery
+	// services once they have been changed at runtime
+	deliverRetryThresholds *deliverRetryThresholds
+}
+
+type deliverRetryThresholds struct {
+	reConnectBackoffThreshold   time.Duration
+	reconnectTotalTimeThreshold time.Duration
 }
 
 // This is an implementation of api.JoinChannelMessage.
@@ -382,6 +392,9 @@ func (g *GossipService) InitializeChannel(channelID string, ordererSource *order
 		stateConfig)
 	if g.deliveryService[channelID] == nil {
 		g.deliveryService[channelID] = g.deliveryFactory.Service(g, ordererSource, g.mcs, g.serviceConfig.OrgLeader)
+		if g.deliveryService[channelID] != nil && g.deliverRetryThresholds != nil {
+			g.deliveryService[channelID].SetRetryThresholds(g.deliverRetryThresholds.reConnectBackoffThreshold, g.deliverRetryThresholds.reconnectTotalTimeThreshold)
+		}
 	}
 
 	// Delivery service might be nil only if it was not able to get connected
@@ -464,6 +477,43 @@ func (g *GossipService) AddPayload(channelID string, payload *gproto.Payload) er
 	return g.chains[channelID].AddPayload(payload)
 }
 
+// SetDeliverRetryThresholds changes the maximum delay between attempts of the
+// delivery services to reconnect to the ordering service, and the total time
+// the attempts may take, for all channels.
+func (g *GossipService) SetDeliverRetryThresholds(reConnectBackoffThreshold, reconnectTotalTimeThreshold time.Duration) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	g.deliverRetryThresholds = &deliverRetryThresholds{
+		reConnectBackoffThreshold:   reConnectBackoffThreshold,
+		reconnectTotalTimeThreshold: reconnectTotalTimeThreshold,
+	}
+	for _, ds := range g.deliveryService {
+		if ds != nil {
+			ds.SetRetryThresholds(reConnectBackoffThreshold, reconnectTotalTimeThreshold)
+		}
+	}
+}
+
+// SetReconcileConfig changes the interval between private data reconciliation
+// cycles and the number of blocks reconciled at a time, for all channels.
+func (g *GossipService) SetReconcileConfig(reconcileSleepInterval time.Duration, reconcileBatchSize int) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	// the config is copied as it may be shared with other components
+	privdataConfig := *g.privdataConfig
+	privdataConfig.ReconcileSleepInterval = reconcileSleepInterval
+	privdataConfig.ReconcileBatchSize = reconcileBatchSize
+	g.privdataConfig = &privdataConfig
+
+	for _, handler := range g.privateHandlers {
+		if reconciler, ok := handler.reconciler.(*gossipprivdata.Reconciler); ok {
+			reconciler.SetConfig(reconcileSleepInterval, reconcileBatchSize)
+		}
+	}
+}
+
 // Stop stops the gossip component
 func (g *GossipService) Stop() {
 	g.lock.Lock()
@@ -70,7 +70,7 @@ func newTransientStore(t *testing.T) *testTransientStore {
 	s := &testTransientStore{}
 	var err error
 	s.tempdir = t.TempDir()
-	s.storeProvider, err = transientstore.NewStoreProvider(s.tempdir)
+	s.storeProvider, err = transientstore.NewStoreProvider(&transientstore.Config{Path: s.tempdir})
 	if err != nil {
 		t.Fatalf("Failed to open store, got err %s", err)
 		return s
@@ -379,7 +379,8 @@ func (mf *mockDeliverServiceFactory) Service(GossipServiceAdapter, *orderers.Con
 }
 
 type mockDeliverService struct {
-	running map[string]bool
+	running         map[string]bool
+	retryThresholds []time.Duration
 }
 
 func (ds *mockDeliverService) StartDeliverForChannel(chainID string, ledgerInfo blocksprovider.LedgerInfo, finalizer func()) error {
@@ -392,6 +393,10 @@ func (ds *mockDeliverService) StopDeliverForChannel(chainID string) error {
 	return nil
 }
 
+func (ds *mockDeliverService) SetRetryThresholds(reConnectBackoffThreshold, reconnectTotalTimeThreshold time.Duration) {
+	ds.retryThresholds = []time.Duration{reConnectBackoffThreshold, reconnectTotalTimeThreshold}
+}
+
 func (ds *mockDeliverService) Stop() {
 }
 
@@ -967,3 +972,34 @@ func TestChannelConfig(t *testing.T) {
 	require.True(t, gService.anchorPeerTracker.IsAnchorPeer("localhost:2001"))
 	require.False(t, gService.anchorPeerTracker.IsAnchorPeer("localhost:5000"))
 }
+
+func TestSetRuntimeConfig(t *testing.T) {
+	ds := &mockDeliverService{running: make(map[string]bool)}
+	privdataConfig := &privdata.PrivdataConfig{
+		ReconcileSleepInterval: time.Minute,
+		ReconcileBatchSize:     10,
+		ReconciliationEnabled:  true,
+	}
+	reconciler := privdata.NewReconciler("chanA", gossipmetrics.NewGossipMetrics(&disabled.Provider{}).PrivdataMetrics, nil, nil, privdataConfig)
+	g := &GossipService{
+		deliveryService: map[string]deliverservice.DeliverService{"chanA": ds},
+		privateHandlers: map[string]privateHandler{
+			"chanA": {reconciler: reconciler},
+			"chanB": {reconciler: &privdata.NoOpReconciler{}},
+		},
+		privdataConfig: privdataConfig,
+	}
+
+	g.SetDeliverRetryThresholds(time.Second, time.Minute)
+	require.Equal(t, []time.Duration{time.Second, time.Minute}, ds.retryThresholds)
+	require.Equal(t, &deliverRetryThresholds{time.Second, time.Minute}, g.deliverRetryThresholds)
+
+	g.SetReconcileConfig(time.Second, 5)
+	require.Equal(t, time.Second, reconciler.ReconcileSleepInterval)
+	require.Equal(t, 5, reconciler.ReconcileBatchSize)
+	require.Equal(t, time.Second, g.privdataConfig.ReconcileSleepInterval)
+	require.Equal(t, 5, g.privdataConfig.ReconcileBatchSize)
+	// the original config is left untouched
+	require.Equal(t, time.Minute, privdataConfig.ReconcileSleepInterval)
+	require.Equal(t, 10, privdataConfig.ReconcileBatchSize)
+}
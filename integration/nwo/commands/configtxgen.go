@@ -70,6 +70,28 @@ func (o OutputAnchorPeersUpdate) Args() []string {
 	}
 }
 
+type OutputConfigUpdate struct {
+	ChannelID          string
+	Profile            string
+	ConfigPath         string
+	ConfigBlock        string
+	OutputConfigUpdate string
+}
+
+func (o OutputConfigUpdate) SessionName() string {
+	return "configtxgen-output-config-update"
+}
+
+func (o OutputConfigUpdate) Args() []string {
+	return []string{
+		"-channelID", o.ChannelID,
+		"-profile", o.Profile,
+		"-configPath", o.ConfigPath,
+		"-configBlock", o.ConfigBlock,
+		"-outputConfigUpdate", o.OutputConfigUpdate,
+	}
+}
+
 type PrintOrg struct {
 	ConfigPath string
 	ChannelID  string
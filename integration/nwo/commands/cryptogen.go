@@ -39,3 +39,25 @@ func (c Extend) Args() []string {
 		"--input", c.Input,
 	}
 }
+
+type Renew struct {
+	Input    string
+	Output   string
+	RotateCA bool
+}
+
+func (c Renew) SessionName() string {
+	return "cryptogen-renew"
+}
+
+func (c Renew) Args() []string {
+	args := []string{
+		"renew",
+		"--input", c.Input,
+		"--output", c.Output,
+	}
+	if c.RotateCA {
+		args = append(args, "--rotate-ca")
+	}
+	return args
+}
@@ -20,10 +20,12 @@ type Core struct {
 	Ledger     *Ledger     `yaml:"ledger,omitempty"`
 	Operations *Operations `yaml:"operations,omitempty"`
 	Metrics    *Metrics    `yaml:"metrics,omitempty"`
+	Audit      *Audit      `yaml:"audit,omitempty"`
 }
 
 type Logging struct {
-	Format string `yaml:"format,omitempty"`
+	Format string    `yaml:"format,omitempty"`
+	Sinks  []LogSink `yaml:"sinks,omitempty"`
 
 	ExtraProperties map[string]interface{} `yaml:",inline,omitempty"`
 }
@@ -302,9 +304,10 @@ type SystemFlags struct {
 
 type Ledger struct {
 	// Blockchain - not sure if it's needed
-	State        *StateConfig   `yaml:"state,omitempty"`
-	History      *HistoryConfig `yaml:"history,omitempty"`
-	PvtdataStore *PvtdataStore  `yaml:"pvtdataStore,omitempty"`
+	State           *StateConfig           `yaml:"state,omitempty"`
+	History         *HistoryConfig         `yaml:"history,omitempty"`
+	StateCommitment *StateCommitmentConfig `yaml:"stateCommitment,omitempty"`
+	PvtdataStore    *PvtdataStore          `yaml:"pvtdataStore,omitempty"`
 }
 
 type StateConfig struct {
@@ -327,8 +330,18 @@ type HistoryConfig struct {
 	EnableHistoryDatabase bool `yaml:"enableHistoryDatabase"`
 }
 
+type StateCommitmentConfig struct {
+	Enabled bool `yaml:"enabled"`
+}
+
 type PvtdataStore struct {
 	DeprioritizedDataReconcilerInterval time.Duration
+	Encryption                          *PvtdataEncryption `yaml:"encryption,omitempty"`
+}
+
+type PvtdataEncryption struct {
+	Enabled     bool     `yaml:"enabled"`
+	Collections []string `yaml:"collections,omitempty"`
 }
 
 type Operations struct {
@@ -341,9 +354,32 @@ type Metrics struct {
 	Statsd   *Statsd `yaml:"statsd,omitempty"`
 }
 
+type LogSink struct {
+	Name             string        `yaml:"name"`
+	Type             string        `yaml:"type"`
+	Spec             string        `yaml:"spec,omitempty"`
+	Format           string        `yaml:"format,omitempty"`
+	Loggers          []string      `yaml:"loggers,omitempty"`
+	File             string        `yaml:"file,omitempty"`
+	MaxSize          int           `yaml:"maxSize,omitempty"`
+	RotationInterval time.Duration `yaml:"rotationInterval,omitempty"`
+	MaxBackups       int           `yaml:"maxBackups,omitempty"`
+	Network          string        `yaml:"network,omitempty"`
+	Address          string        `yaml:"address,omitempty"`
+	Facility         string        `yaml:"facility,omitempty"`
+	AppName          string        `yaml:"appName,omitempty"`
+}
+
 type Statsd struct {
 	Network       string        `yaml:"network,omitempty"`
 	Address       string        `yaml:"address,omitempty"`
 	WriteInterval time.Duration `yaml:"writeInterval,omitempty"`
 	Prefix        string        `yaml:"prefix,omitempty"`
 }
+
+type Audit struct {
+	Enabled    bool   `yaml:"enabled"`
+	File       string `yaml:"file,omitempty"`
+	MaxSize    int    `yaml:"maxSize,omitempty"`
+	MaxBackups int    `yaml:"maxBackups,omitempty"`
+}
@@ -16,6 +16,8 @@ type Orderer struct {
 	ChannelParticipation *ChannelParticipation `yaml:"ChannelParticipation,omitempty"`
 	Consensus            map[string]string     `yaml:"Consensus,omitempty"`
 	Admin                *Admin                `yaml:"Admin,omitempty"`
+	Audit                *OrdererAudit         `yaml:"Audit,omitempty"`
+	Logging              *OrdererLogging       `yaml:"Logging,omitempty"`
 
 	ExtraProperties map[string]interface{} `yaml:",inline,omitempty"`
 }
@@ -59,6 +61,7 @@ type Cluster struct {
 	ReplicationRetryTimeout              time.Duration `yaml:"ReplicationRetryTimeout,omitempty"`
 	ReplicationBackgroundRefreshInterval time.Duration `yaml:"ReplicationBackgroundRefreshInterval,omitempty"`
 	ReplicationMaxRetries                int           `yaml:"ReplicationMaxRetries,omitempty"`
+	ReplicationMaxBandwidth              int           `yaml:"ReplicationMaxBandwidth,omitempty"`
 	SendBufferSize                       int           `yaml:"SendBufferSize,omitempty"`
 	CertExpirationWarningThreshold       time.Duration `yaml:"CertExpirationWarningThreshold,omitempty"`
 	TLSHandshakeTimeShift                time.Duration `yaml:"TLSHandshakeTimeShift,omitempty"`
@@ -155,3 +158,30 @@ type ChannelParticipation struct {
 	Enabled            bool   `yaml:"Enabled"`
 	MaxRequestBodySize string `yaml:"MaxRequestBodySize,omitempty"`
 }
+
+type OrdererLogging struct {
+	Sinks []OrdererLogSink `yaml:"Sinks,omitempty"`
+}
+
+type OrdererLogSink struct {
+	Name             string        `yaml:"Name"`
+	Type             string        `yaml:"Type"`
+	Spec             string        `yaml:"Spec,omitempty"`
+	Format           string        `yaml:"Format,omitempty"`
+	Loggers          []string      `yaml:"Loggers,omitempty"`
+	File             string        `yaml:"File,omitempty"`
+	MaxSize          int           `yaml:"MaxSize,omitempty"`
+	RotationInterval time.Duration `yaml:"RotationInterval,omitempty"`
+	MaxBackups       int           `yaml:"MaxBackups,omitempty"`
+	Network          string        `yaml:"Network,omitempty"`
+	Address          string        `yaml:"Address,omitempty"`
+	Facility         string        `yaml:"Facility,omitempty"`
+	AppName          string        `yaml:"AppName,omitempty"`
+}
+
+type OrdererAudit struct {
+	Enabled    bool   `yaml:"Enabled"`
+	File       string `yaml:"File,omitempty"`
+	MaxSize    int    `yaml:"MaxSize,omitempty"`
+	MaxBackups int    `yaml:"MaxBackups,omitempty"`
+}
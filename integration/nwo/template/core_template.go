@@ -212,9 +212,14 @@ ledger:
       maxBatchUpdateSize: 1000
   history:
     enableHistoryDatabase: true
+  stateCommitment:
+    enabled: false
   pvtdataStore:
     deprioritizedDataReconcilerInterval: 60m
     purgeInterval: 1
+    encryption:
+      enabled: false
+      collections: []
 
 operations:
   listenAddress: 127.0.0.1:{{ .PeerPort Peer "Operations" }}
@@ -35,6 +35,9 @@ type CA struct {
 	PostalCode         string
 	Signer             crypto.Signer
 	SignCert           *x509.Certificate
+	// Chain holds the certificates of the issuers of an intermediate CA, from
+	// its direct issuer up to the root CA. It is empty for a root CA.
+	Chain []*x509.Certificate
 }
 
 // NewCA creates an instance of CA and saves the signing key pair in
@@ -49,6 +52,37 @@ func NewCA(
 	orgUnit,
 	streetAddress,
 	postalCode string,
+) (*CA, error) {
+	return newCA(baseDir, org, name, country, province, locality, orgUnit, streetAddress, postalCode, nil)
+}
+
+// NewIntermediateCA creates an instance of CA certified by ca, and saves the
+// signing key pair in baseDir/name
+func (ca *CA) NewIntermediateCA(
+	baseDir,
+	org,
+	name,
+	country,
+	province,
+	locality,
+	orgUnit,
+	streetAddress,
+	postalCode string,
+) (*CA, error) {
+	return newCA(baseDir, org, name, country, province, locality, orgUnit, streetAddress, postalCode, ca)
+}
+
+func newCA(
+	baseDir,
+	org,
+	name,
+	country,
+	province,
+	locality,
+	orgUnit,
+	streetAddress,
+	postalCode string,
+	parent *CA,
 ) (*CA, error) {
 	var ca *CA
 
@@ -81,13 +115,23 @@ func NewCA(
 	template.Subject = subject
 	template.SubjectKeyId = computeSKI(priv)
 
+	// a root CA is self-signed
+	var signer crypto.Signer = priv
+	parentCert := &template
+	var chain []*x509.Certificate
+	if parent != nil {
+		signer = parent.Signer
+		parentCert = parent.SignCert
+		chain = append([]*x509.Certificate{parent.SignCert}, parent.Chain...)
+	}
+
 	x509Cert, err := genCertificateECDSA(
 		baseDir,
 		name,
 		&template,
-		&template,
+		parentCert,
 		&priv.PublicKey,
-		priv,
+		signer,
 	)
 	if err != nil {
 		return nil, err
@@ -104,11 +148,135 @@ func NewCA(
 		OrganizationalUnit: orgUnit,
 		StreetAddress:      streetAddress,
 		PostalCode:         postalCode,
+		Chain:              chain,
 	}
 
 	return ca, err
 }
 
+// ImportCA loads an existing CA from the PEM-encoded certificate in certFile
+// and private key in keyFile, and saves the signing key pair in baseDir. When
+// the CA is an intermediate CA, chainFile holds the certificates of its issuers
+// up to the root CA, in any order.
+func ImportCA(baseDir, certFile, keyFile, chainFile string) (*CA, error) {
+	certs, err := loadCertificates(certFile)
+	if err != nil {
+		return nil, err
+	}
+	cert := certs[0]
+	if !cert.IsCA {
+		return nil, errors.Errorf("%s: not a CA certificate", certFile)
+	}
+
+	priv, err := csp.LoadPrivateKeyFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if !priv.PublicKey.Equal(cert.PublicKey) {
+		return nil, errors.Errorf("%s: private key does not match the certificate in %s", keyFile, certFile)
+	}
+
+	var issuers []*x509.Certificate
+	if chainFile != "" {
+		issuers, err = loadCertificates(chainFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	chain, err := buildChain(cert, issuers)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "%s", certFile)
+	}
+
+	err = os.MkdirAll(baseDir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+	err = csp.StorePrivateKey(baseDir, priv)
+	if err != nil {
+		return nil, err
+	}
+	err = os.WriteFile(
+		filepath.Join(baseDir, cert.Subject.CommonName+"-cert.pem"),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}),
+		0o644,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CA{
+		Name: cert.Subject.CommonName,
+		Signer: &csp.ECDSASigner{
+			PrivateKey: priv,
+		},
+		SignCert: cert,
+		Chain:    chain,
+	}, nil
+}
+
+// RootCert returns the certificate of the root CA of the hierarchy of the CA.
+func (ca *CA) RootCert() *x509.Certificate {
+	if len(ca.Chain) == 0 {
+		return ca.SignCert
+	}
+	return ca.Chain[len(ca.Chain)-1]
+}
+
+// IntermediateCerts returns the certificates of the intermediate CAs of the
+// hierarchy of the CA, from the CA itself up to, but excluding, the root CA.
+// It is empty for a root CA.
+func (ca *CA) IntermediateCerts() []*x509.Certificate {
+	if len(ca.Chain) == 0 {
+		return nil
+	}
+	return append([]*x509.Certificate{ca.SignCert}, ca.Chain[:len(ca.Chain)-1]...)
+}
+
+// buildChain orders the issuers of cert from its direct issuer up to the root
+// CA.
+func buildChain(cert *x509.Certificate, issuers []*x509.Certificate) ([]*x509.Certificate, error) {
+	var chain []*x509.Certificate
+	for cert.CheckSignatureFrom(cert) != nil {
+		var issuer *x509.Certificate
+		for _, candidate := range issuers {
+			if !candidate.Equal(cert) && cert.CheckSignatureFrom(candidate) == nil {
+				issuer = candidate
+				break
+			}
+		}
+		if issuer == nil || len(chain) == len(issuers) {
+			return nil, errors.Errorf("the issuer of %s, up to a root CA, is missing from the chain", cert.Subject.CommonName)
+		}
+		chain = append(chain, issuer)
+		cert = issuer
+	}
+	return chain, nil
+}
+
+func loadCertificates(path string) ([]*x509.Certificate, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(raw); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Errorf("%s: wrong DER encoding", path)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.Errorf("%s: no PEM encoded certificate found", path)
+	}
+	return certs, nil
+}
+
 // SignCertificate creates a signed certificate based on a built-in template
 // and saves it in baseDir/name
 func (ca *CA) SignCertificate(
@@ -163,6 +331,53 @@ func (ca *CA) SignCertificate(
 	return cert, nil
 }
 
+// RenewCertificate reissues cert under the CA with a new serial number and
+// validity period, and saves it in baseDir/name. The subject, public key,
+// subject key identifier, subject alternative names and key usages of cert are
+// preserved, so that a CA certificate reissued under its own key keeps
+// verifying the certificates it issued before.
+func (ca *CA) RenewCertificate(
+	baseDir,
+	name string,
+	cert *x509.Certificate,
+) (*x509.Certificate, error) {
+	pub, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("certificate %s does not hold an ECDSA public key", cert.Subject.CommonName)
+	}
+
+	template := x509Template()
+	template.Subject = cert.Subject
+	template.SubjectKeyId = cert.SubjectKeyId
+	template.KeyUsage = cert.KeyUsage
+	template.ExtKeyUsage = cert.ExtKeyUsage
+	template.IsCA = cert.IsCA
+	template.MaxPathLen = cert.MaxPathLen
+	template.MaxPathLenZero = cert.MaxPathLenZero
+	template.DNSNames = cert.DNSNames
+	template.IPAddresses = cert.IPAddresses
+	template.EmailAddresses = cert.EmailAddresses
+	template.URIs = cert.URIs
+
+	parent := ca.SignCert
+	if cert.Equal(ca.SignCert) {
+		if len(ca.Chain) != 0 {
+			return nil, errors.Errorf("the certificate of intermediate CA %s can only be renewed by its issuer", ca.Name)
+		}
+		// a self-signed certificate is its own parent
+		parent = &template
+	}
+
+	return genCertificateECDSA(
+		baseDir,
+		name,
+		&template,
+		parent,
+		pub,
+		ca.Signer,
+	)
+}
+
 // compute Subject Key Identifier using RFC 7093, Section 2, Method 4
 func computeSKI(privKey *ecdsa.PrivateKey) []byte {
 	// Marshall the public key
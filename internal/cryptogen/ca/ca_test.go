@@ -8,6 +8,8 @@ package ca_test
 import (
 	"crypto/ecdsa"
 	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
 	"io/ioutil"
 	"net"
 	"os"
@@ -240,6 +242,219 @@ func TestGenerateSignCertificate(t *testing.T) {
 	require.Error(t, err, "Empty CA should not be able to sign")
 }
 
+func TestRenewCertificate(t *testing.T) {
+	testDir := t.TempDir()
+
+	certDir := filepath.Join(testDir, "certs")
+	require.NoError(t, os.MkdirAll(certDir, 0o755))
+	priv, err := csp.GeneratePrivateKey(certDir)
+	require.NoError(t, err)
+
+	caDir := filepath.Join(testDir, "ca")
+	rootCA, err := ca.NewCA(caDir, testCAName, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	require.NoError(t, err)
+	cert, err := rootCA.SignCertificate(certDir, testName, []string{"PeerOU"}, []string{testName2, testIP}, &priv.PublicKey,
+		x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth})
+	require.NoError(t, err)
+
+	// the CA certificate reissued under its own key keeps its subject key
+	// identifier, and verifies the certificates issued before
+	renewedCACert, err := rootCA.RenewCertificate(caDir, testCAName, rootCA.SignCert)
+	require.NoError(t, err)
+	require.NotEqual(t, rootCA.SignCert.SerialNumber, renewedCACert.SerialNumber)
+	require.Equal(t, rootCA.SignCert.Subject.String(), renewedCACert.Subject.String())
+	require.Equal(t, rootCA.SignCert.SubjectKeyId, renewedCACert.SubjectKeyId)
+	require.Equal(t, rootCA.SignCert.PublicKey, renewedCACert.PublicKey)
+	require.True(t, renewedCACert.IsCA)
+	require.NoError(t, renewedCACert.CheckSignatureFrom(renewedCACert))
+	require.NoError(t, cert.CheckSignatureFrom(renewedCACert))
+	loadedCert, err := ca.LoadCertificateECDSA(caDir)
+	require.NoError(t, err)
+	require.Equal(t, renewedCACert, loadedCert)
+
+	renewedCert, err := rootCA.RenewCertificate(certDir, testName, cert)
+	require.NoError(t, err)
+	require.NotEqual(t, cert.SerialNumber, renewedCert.SerialNumber)
+	require.Equal(t, cert.Subject.String(), renewedCert.Subject.String())
+	require.Equal(t, &priv.PublicKey, renewedCert.PublicKey)
+	require.Equal(t, cert.KeyUsage, renewedCert.KeyUsage)
+	require.Equal(t, cert.ExtKeyUsage, renewedCert.ExtKeyUsage)
+	require.Equal(t, cert.DNSNames, renewedCert.DNSNames)
+	require.Equal(t, cert.IPAddresses, renewedCert.IPAddresses)
+	require.Equal(t, rootCA.SignCert.SubjectKeyId, renewedCert.AuthorityKeyId)
+	require.NoError(t, renewedCert.CheckSignatureFrom(renewedCACert))
+
+	// a CA certificate reissued under another CA is cross-signed
+	otherCA, err := ca.NewCA(filepath.Join(testDir, "other"), testCA2Name, testCA2Name, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	require.NoError(t, err)
+	crossSigned, err := rootCA.RenewCertificate(certDir, testCA2Name, otherCA.SignCert)
+	require.NoError(t, err)
+	require.True(t, crossSigned.IsCA)
+	require.Equal(t, otherCA.SignCert.SubjectKeyId, crossSigned.SubjectKeyId)
+	require.Equal(t, rootCA.SignCert.Subject.String(), crossSigned.Issuer.String())
+	require.NoError(t, crossSigned.CheckSignatureFrom(rootCA.SignCert))
+
+	_, err = rootCA.RenewCertificate(certDir, testName, &x509.Certificate{Subject: pkix.Name{CommonName: "rsa"}})
+	require.EqualError(t, err, "certificate rsa does not hold an ECDSA public key")
+}
+
+func TestNewIntermediateCA(t *testing.T) {
+	testDir := t.TempDir()
+
+	rootCA, err := ca.NewCA(filepath.Join(testDir, "root"), testCAName, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	require.NoError(t, err)
+	require.Empty(t, rootCA.Chain)
+	require.Equal(t, rootCA.SignCert, rootCA.RootCert())
+	require.Empty(t, rootCA.IntermediateCerts())
+
+	intermediateCA, err := rootCA.NewIntermediateCA(filepath.Join(testDir, "intermediate1"), testCAName, testCA2Name, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	require.NoError(t, err)
+	issuingCA, err := intermediateCA.NewIntermediateCA(filepath.Join(testDir, "intermediate2"), testCAName, testCA3Name, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	require.NoError(t, err)
+
+	require.True(t, issuingCA.SignCert.IsCA)
+	require.Equal(t, testCA3Name, issuingCA.SignCert.Subject.CommonName)
+	require.Equal(t, intermediateCA.SignCert.SubjectKeyId, issuingCA.SignCert.AuthorityKeyId)
+	require.NoError(t, issuingCA.SignCert.CheckSignatureFrom(intermediateCA.SignCert))
+	require.NoError(t, intermediateCA.SignCert.CheckSignatureFrom(rootCA.SignCert))
+	require.Equal(t, []*x509.Certificate{intermediateCA.SignCert, rootCA.SignCert}, issuingCA.Chain)
+	require.Equal(t, rootCA.SignCert, issuingCA.RootCert())
+	require.Equal(t, []*x509.Certificate{issuingCA.SignCert, intermediateCA.SignCert}, issuingCA.IntermediateCerts())
+	require.True(t, checkForFile(filepath.Join(testDir, "intermediate2", testCA3Name+"-cert.pem")))
+	require.True(t, checkForFile(filepath.Join(testDir, "intermediate2", "priv_sk")))
+
+	// the certificates issued by an intermediate CA chain up to the root CA
+	priv, err := csp.GeneratePrivateKey(testDir)
+	require.NoError(t, err)
+	cert, err := issuingCA.SignCertificate(testDir, testName, nil, nil, &priv.PublicKey, x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{})
+	require.NoError(t, err)
+	roots := x509.NewCertPool()
+	roots.AddCert(rootCA.SignCert)
+	intermediates := x509.NewCertPool()
+	for _, c := range issuingCA.IntermediateCerts() {
+		intermediates.AddCert(c)
+	}
+	_, err = cert.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates})
+	require.NoError(t, err)
+
+	// an intermediate CA certificate is renewed by its issuer
+	_, err = issuingCA.RenewCertificate(testDir, testCA3Name, issuingCA.SignCert)
+	require.EqualError(t, err, "the certificate of intermediate CA root2 can only be renewed by its issuer")
+	renewed, err := intermediateCA.RenewCertificate(filepath.Join(testDir, "intermediate2"), testCA3Name, issuingCA.SignCert)
+	require.NoError(t, err)
+	require.Equal(t, issuingCA.SignCert.SubjectKeyId, renewed.SubjectKeyId)
+	require.NoError(t, cert.CheckSignatureFrom(renewed))
+}
+
+func TestImportCA(t *testing.T) {
+	testDir := t.TempDir()
+
+	rootDir := filepath.Join(testDir, "root")
+	rootCA, err := ca.NewCA(rootDir, testCAName, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	require.NoError(t, err)
+	intermediateDir := filepath.Join(testDir, "intermediate")
+	intermediateCA, err := rootCA.NewIntermediateCA(intermediateDir, testCAName, testCA2Name, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	require.NoError(t, err)
+	rootCertFile := filepath.Join(rootDir, testCAName+"-cert.pem")
+	intermediateCertFile := filepath.Join(intermediateDir, testCA2Name+"-cert.pem")
+
+	t.Run("root CA", func(t *testing.T) {
+		importDir := filepath.Join(t.TempDir(), "ca")
+		imported, err := ca.ImportCA(importDir, rootCertFile, filepath.Join(rootDir, "priv_sk"), "")
+		require.NoError(t, err)
+		require.Equal(t, testCAName, imported.Name)
+		require.Equal(t, rootCA.SignCert, imported.SignCert)
+		require.Empty(t, imported.Chain)
+
+		priv, err := csp.LoadPrivateKey(importDir)
+		require.NoError(t, err)
+		require.True(t, priv.PublicKey.Equal(rootCA.SignCert.PublicKey))
+		loadedCert, err := ca.LoadCertificateECDSA(importDir)
+		require.NoError(t, err)
+		require.Equal(t, rootCA.SignCert, loadedCert)
+	})
+
+	t.Run("intermediate CA", func(t *testing.T) {
+		// the chain may hold unrelated certificates, in any order
+		otherCA, err := ca.NewCA(filepath.Join(t.TempDir(), "other"), testCA3Name, testCA3Name, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+		require.NoError(t, err)
+		chainFile := filepath.Join(t.TempDir(), "chain.pem")
+		var chain []byte
+		for _, cert := range []*x509.Certificate{otherCA.SignCert, rootCA.SignCert} {
+			chain = append(chain, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})...)
+		}
+		require.NoError(t, os.WriteFile(chainFile, chain, 0o644))
+
+		// SEC 1 encoded keys are supported
+		priv, err := csp.LoadPrivateKey(intermediateDir)
+		require.NoError(t, err)
+		sec1, err := x509.MarshalECPrivateKey(priv)
+		require.NoError(t, err)
+		keyFile := filepath.Join(t.TempDir(), "key.pem")
+		require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1}), 0o600))
+
+		imported, err := ca.ImportCA(filepath.Join(t.TempDir(), "ca"), intermediateCertFile, keyFile, chainFile)
+		require.NoError(t, err)
+		require.Equal(t, testCA2Name, imported.Name)
+		require.Equal(t, []*x509.Certificate{rootCA.SignCert}, imported.Chain)
+		require.Equal(t, rootCA.SignCert, imported.RootCert())
+		require.Equal(t, []*x509.Certificate{intermediateCA.SignCert}, imported.IntermediateCerts())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		priv, err := csp.GeneratePrivateKey(t.TempDir())
+		require.NoError(t, err)
+		leafDir := t.TempDir()
+		_, err = rootCA.SignCertificate(leafDir, testName, nil, nil, &priv.PublicKey, x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{})
+		require.NoError(t, err)
+		leafCertFile := filepath.Join(leafDir, testName+"-cert.pem")
+
+		for _, test := range []struct {
+			name      string
+			certFile  string
+			keyFile   string
+			chainFile string
+			errMsg    string
+		}{
+			{
+				name:     "missing certificate",
+				certFile: filepath.Join(testDir, "missing.pem"),
+				errMsg:   "no such file or directory",
+			},
+			{
+				name:     "not a CA",
+				certFile: leafCertFile,
+				errMsg:   leafCertFile + ": not a CA certificate",
+			},
+			{
+				name:     "wrong key",
+				certFile: rootCertFile,
+				keyFile:  filepath.Join(intermediateDir, "priv_sk"),
+				errMsg:   filepath.Join(intermediateDir, "priv_sk") + ": private key does not match the certificate in " + rootCertFile,
+			},
+			{
+				name:     "missing chain",
+				certFile: intermediateCertFile,
+				keyFile:  filepath.Join(intermediateDir, "priv_sk"),
+				errMsg:   intermediateCertFile + ": the issuer of root1, up to a root CA, is missing from the chain",
+			},
+			{
+				name:      "empty chain",
+				certFile:  intermediateCertFile,
+				keyFile:   filepath.Join(intermediateDir, "priv_sk"),
+				chainFile: filepath.Join(intermediateDir, "priv_sk"),
+				errMsg:    filepath.Join(intermediateDir, "priv_sk") + ": no PEM encoded certificate found",
+			},
+		} {
+			t.Run(test.name, func(t *testing.T) {
+				_, err := ca.ImportCA(t.TempDir(), test.certFile, test.keyFile, test.chainFile)
+				require.Error(t, err)
+				require.Contains(t, err.Error(), test.errMsg)
+			})
+		}
+	})
+}
+
 func checkForFile(file string) bool {
 	if _, err := os.Stat(file); os.IsNotExist(err) {
 		return false
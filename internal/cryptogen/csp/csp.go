@@ -71,6 +71,30 @@ func parsePrivateKeyPEM(rawKey []byte) (*ecdsa.PrivateKey, error) {
 	return priv, nil
 }
 
+// LoadPrivateKeyFile loads an EC private key from keyFile. It expects a
+// PEM-encoded PKCS8 or SEC 1 EC private key.
+func LoadPrivateKeyFile(keyFile string) (*ecdsa.PrivateKey, error) {
+	rawKey, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(rawKey)
+	if block != nil && block.Type == "EC PRIVATE KEY" {
+		priv, err := x509.ParseECPrivateKey(block.Bytes)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "%s: pem bytes are not SEC 1 encoded", keyFile)
+		}
+		return priv, nil
+	}
+
+	priv, err := parsePrivateKeyPEM(rawKey)
+	if err != nil {
+		return nil, errors.WithMessage(err, keyFile)
+	}
+	return priv, nil
+}
+
 // GeneratePrivateKey creates an EC private key using a P-256 curve and stores
 // it in keystorePath.
 func GeneratePrivateKey(keystorePath string) (*ecdsa.PrivateKey, error) {
@@ -79,9 +103,20 @@ func GeneratePrivateKey(keystorePath string) (*ecdsa.PrivateKey, error) {
 		return nil, errors.WithMessage(err, "failed to generate private key")
 	}
 
+	err = StorePrivateKey(keystorePath, priv)
+	if err != nil {
+		return nil, err
+	}
+
+	return priv, err
+}
+
+// StorePrivateKey stores priv in keystorePath as a PEM-encoded PKCS8 private
+// key.
+func StorePrivateKey(keystorePath string, priv *ecdsa.PrivateKey) error {
 	pkcs8Encoded, err := x509.MarshalPKCS8PrivateKey(priv)
 	if err != nil {
-		return nil, errors.WithMessage(err, "failed to marshal private key")
+		return errors.WithMessage(err, "failed to marshal private key")
 	}
 
 	pemEncoded := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Encoded})
@@ -89,10 +124,10 @@ func GeneratePrivateKey(keystorePath string) (*ecdsa.PrivateKey, error) {
 	keyFile := filepath.Join(keystorePath, "priv_sk")
 	err = os.WriteFile(keyFile, pemEncoded, 0o600)
 	if err != nil {
-		return nil, errors.WithMessagef(err, "failed to save private key to file %s", keyFile)
+		return errors.WithMessagef(err, "failed to save private key to file %s", keyFile)
 	}
 
-	return priv, err
+	return nil
 }
 
 /*
@@ -98,6 +98,32 @@ func TestLoadPrivateKey_BadPEM(t *testing.T) {
 	}
 }
 
+func TestLoadPrivateKeyFile(t *testing.T) {
+	testDir := t.TempDir()
+	priv, err := csp.GeneratePrivateKey(testDir)
+	require.NoError(t, err)
+
+	loadedPriv, err := csp.LoadPrivateKeyFile(filepath.Join(testDir, "priv_sk"))
+	require.NoError(t, err)
+	require.Equal(t, priv, loadedPriv)
+
+	sec1, err := x509.MarshalECPrivateKey(priv)
+	require.NoError(t, err)
+	sec1File := filepath.Join(testDir, "sec1.pem")
+	require.NoError(t, os.WriteFile(sec1File, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1}), 0o600))
+	loadedPriv, err = csp.LoadPrivateKeyFile(sec1File)
+	require.NoError(t, err)
+	require.Equal(t, priv, loadedPriv)
+
+	badFile := filepath.Join(testDir, "bad.pem")
+	require.NoError(t, os.WriteFile(badFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("bad")}), 0o600))
+	_, err = csp.LoadPrivateKeyFile(badFile)
+	require.ErrorContains(t, err, badFile+": pem bytes are not SEC 1 encoded")
+
+	_, err = csp.LoadPrivateKeyFile(filepath.Join(testDir, "missing"))
+	require.Error(t, err)
+}
+
 func TestGeneratePrivateKey(t *testing.T) {
 	testDir := t.TempDir()
 
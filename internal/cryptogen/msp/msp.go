@@ -93,27 +93,14 @@ func GenerateLocalMSP(
 	}
 
 	// write artifacts to MSP folders
-
-	// the signing CA certificate goes into cacerts
-	err = x509Export(
-		filepath.Join(mspDir, "cacerts", x509Filename(signCA.Name)),
-		signCA.SignCert,
-	)
-	if err != nil {
-		return err
-	}
-	// the TLS CA certificate goes into tlscacerts
-	err = x509Export(
-		filepath.Join(mspDir, "tlscacerts", x509Filename(tlsCA.Name)),
-		tlsCA.SignCert,
-	)
+	caFile, err := exportCACerts(mspDir, signCA, tlsCA)
 	if err != nil {
 		return err
 	}
 
 	// generate config.yaml if required
 	if nodeOUs {
-		exportConfig(mspDir, filepath.Join("cacerts", x509Filename(signCA.Name)), true)
+		exportConfig(mspDir, caFile, true)
 	}
 
 	// the signing identity goes into admincerts.
@@ -156,7 +143,7 @@ func GenerateLocalMSP(
 	if err != nil {
 		return err
 	}
-	err = x509Export(filepath.Join(tlsDir, "ca.crt"), tlsCA.SignCert)
+	err = x509Export(filepath.Join(tlsDir, "ca.crt"), tlsCA.RootCert())
 	if err != nil {
 		return err
 	}
@@ -171,6 +158,10 @@ func GenerateLocalMSP(
 	if err != nil {
 		return err
 	}
+	err = appendIntermediateCerts(filepath.Join(tlsDir, tlsFilePrefix+".crt"), tlsCA)
+	if err != nil {
+		return err
+	}
 
 	err = keyExport(tlsDir, filepath.Join(tlsDir, tlsFilePrefix+".key"))
 	if err != nil {
@@ -191,26 +182,14 @@ func GenerateVerifyingMSP(
 	if err != nil {
 		return err
 	}
-	// the signing CA certificate goes into cacerts
-	err = x509Export(
-		filepath.Join(baseDir, "cacerts", x509Filename(signCA.Name)),
-		signCA.SignCert,
-	)
-	if err != nil {
-		return err
-	}
-	// the TLS CA certificate goes into tlscacerts
-	err = x509Export(
-		filepath.Join(baseDir, "tlscacerts", x509Filename(tlsCA.Name)),
-		tlsCA.SignCert,
-	)
+	caFile, err := exportCACerts(baseDir, signCA, tlsCA)
 	if err != nil {
 		return err
 	}
 
 	// generate config.yaml if required
 	if nodeOUs {
-		exportConfig(baseDir, "cacerts/"+x509Filename(signCA.Name), true)
+		exportConfig(baseDir, caFile, true)
 	}
 
 	// create a throwaway cert to act as an admin cert
@@ -248,6 +227,197 @@ func GenerateVerifyingMSP(
 	return nil
 }
 
+// Renewal records a certificate reissued in place of a previous one.
+type Renewal struct {
+	Path     string
+	Previous *x509.Certificate
+	Renewed  *x509.Certificate
+}
+
+// RenewLocalMSP reissues the signing certificate of the local MSP in
+// baseDir/msp under signCA, and the TLS certificate in baseDir/tls under tlsCA.
+// The keys of the certificates are preserved. When a CA is an intermediate CA,
+// it is added to the intermediate certificates of the MSP and, for the TLS CA,
+// appended to the TLS certificate.
+func RenewLocalMSP(
+	baseDir string,
+	signCA,
+	tlsCA *ca.CA,
+) ([]*Renewal, error) {
+	mspDir := filepath.Join(baseDir, "msp")
+	tlsDir := filepath.Join(baseDir, "tls")
+
+	signcertsDir := filepath.Join(mspDir, "signcerts")
+	cert, err := ca.LoadCertificateECDSA(signcertsDir)
+	if err != nil {
+		return nil, err
+	}
+	if cert == nil {
+		return nil, errors.Errorf("no signing certificate found in %s", signcertsDir)
+	}
+	renewed, err := signCA.RenewCertificate(signcertsDir, cert.Subject.CommonName, cert)
+	if err != nil {
+		return nil, err
+	}
+	renewals := []*Renewal{{
+		Path:     filepath.Join(signcertsDir, x509Filename(cert.Subject.CommonName)),
+		Previous: cert,
+		Renewed:  renewed,
+	}}
+
+	tlsFile := filepath.Join(tlsDir, "server.crt")
+	if _, err := os.Stat(tlsFile); os.IsNotExist(err) {
+		tlsFile = filepath.Join(tlsDir, "client.crt")
+	}
+	cert, err = loadCertificate(tlsFile)
+	if err != nil {
+		return nil, err
+	}
+	renewed, err = tlsCA.RenewCertificate(tlsDir, cert.Subject.CommonName, cert)
+	if err != nil {
+		return nil, err
+	}
+	err = os.Rename(filepath.Join(tlsDir, x509Filename(cert.Subject.CommonName)), tlsFile)
+	if err != nil {
+		return nil, err
+	}
+	err = appendIntermediateCerts(tlsFile, tlsCA)
+	if err != nil {
+		return nil, err
+	}
+	renewals = append(renewals, &Renewal{
+		Path:     tlsFile,
+		Previous: cert,
+		Renewed:  renewed,
+	})
+
+	err = AddIntermediateCAs(mspDir, signCA, tlsCA)
+	if err != nil {
+		return nil, err
+	}
+
+	return renewals, nil
+}
+
+// AddIntermediateCAs adds to the MSP in mspDir the certificates of the
+// intermediate CAs of the hierarchies of signCA and tlsCA, as intermediate and
+// TLS intermediate certificates. When NodeOUs are enabled and signCA is an
+// intermediate CA, the OU identifiers are moved to signCA, which certifies the
+// identities.
+func AddIntermediateCAs(mspDir string, signCA, tlsCA *ca.CA) error {
+	err := exportIntermediateCerts(filepath.Join(mspDir, "intermediatecerts"), signCA)
+	if err != nil {
+		return err
+	}
+	err = exportIntermediateCerts(filepath.Join(mspDir, "tlsintermediatecerts"), tlsCA)
+	if err != nil {
+		return err
+	}
+
+	if len(signCA.Chain) == 0 {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(mspDir, "config.yaml")); err != nil {
+		return nil
+	}
+	return exportConfig(mspDir, filepath.Join("intermediatecerts", x509Filename(signCA.Name)), true)
+}
+
+// exportCACerts writes the certificate of the root CA of the hierarchy of
+// signCA into mspDir/cacerts and the certificates of its intermediate CAs into
+// mspDir/intermediatecerts, and likewise for tlsCA into tlscacerts and
+// tlsintermediatecerts. It returns the path of the certificate of signCA,
+// relative to mspDir.
+func exportCACerts(mspDir string, signCA, tlsCA *ca.CA) (string, error) {
+	err := x509Export(
+		filepath.Join(mspDir, "cacerts", x509Filename(rootCAName(signCA))),
+		signCA.RootCert(),
+	)
+	if err != nil {
+		return "", err
+	}
+	err = x509Export(
+		filepath.Join(mspDir, "tlscacerts", x509Filename(rootCAName(tlsCA))),
+		tlsCA.RootCert(),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	err = exportIntermediateCerts(filepath.Join(mspDir, "intermediatecerts"), signCA)
+	if err != nil {
+		return "", err
+	}
+	err = exportIntermediateCerts(filepath.Join(mspDir, "tlsintermediatecerts"), tlsCA)
+	if err != nil {
+		return "", err
+	}
+
+	if len(signCA.Chain) == 0 {
+		return filepath.Join("cacerts", x509Filename(signCA.Name)), nil
+	}
+	return filepath.Join("intermediatecerts", x509Filename(signCA.Name)), nil
+}
+
+func exportIntermediateCerts(dir string, c *ca.CA) error {
+	certs := c.IntermediateCerts()
+	if len(certs) == 0 {
+		return nil
+	}
+
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+	for i, cert := range certs {
+		name := cert.Subject.CommonName
+		if i == 0 {
+			name = c.Name
+		}
+		err = x509Export(filepath.Join(dir, x509Filename(name)), cert)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendIntermediateCerts appends the certificates of the intermediate CAs of
+// the hierarchy of c to the certificate in path, so that it is verifiable
+// against the root CA.
+func appendIntermediateCerts(path string, c *ca.CA) error {
+	for _, cert := range c.IntermediateCerts() {
+		err := pemAppend(path, "CERTIFICATE", cert.Raw)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func rootCAName(c *ca.CA) string {
+	if len(c.Chain) == 0 {
+		return c.Name
+	}
+	return c.RootCert().Subject.CommonName
+}
+
+func loadCertificate(path string) (*x509.Certificate, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.Errorf("%s: wrong PEM encoding", path)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, errors.Errorf("%s: wrong DER encoding", path)
+	}
+	return cert, nil
+}
+
 func createFolderStructure(rootDir string, local bool) error {
 	var folders []string
 	// create admincerts, cacerts, keystore and signcerts folders
@@ -294,6 +464,16 @@ func pemExport(path, pemType string, bytes []byte) error {
 	return pem.Encode(file, &pem.Block{Type: pemType, Bytes: bytes})
 }
 
+func pemAppend(path, pemType string, bytes []byte) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return pem.Encode(file, &pem.Block{Type: pemType, Bytes: bytes})
+}
+
 func exportConfig(mspDir, caFile string, enable bool) error {
 	config := &fabricmsp.Configuration{
 		NodeOUs: &fabricmsp.NodeOUs{
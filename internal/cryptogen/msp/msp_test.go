@@ -6,6 +6,9 @@ SPDX-License-Identifier: Apache-2.0
 package msp_test
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -176,6 +179,143 @@ func TestGenerateVerifyingMSPWithoutNodeOU(t *testing.T) {
 	testGenerateVerifyingMSP(t, true)
 }
 
+func TestGenerateMSPWithIntermediateCA(t *testing.T) {
+	testDir := t.TempDir()
+	nodeDir := filepath.Join(testDir, testName)
+	mspDir := filepath.Join(nodeDir, "msp")
+	tlsDir := filepath.Join(nodeDir, "tls")
+	verifyingMSPDir := filepath.Join(testDir, "msp")
+
+	rootCA, err := ca.NewCA(filepath.Join(testDir, "rootca"), testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	require.NoError(t, err)
+	signCA, err := rootCA.NewIntermediateCA(filepath.Join(testDir, "ca"), testCAOrg, "intermediate."+testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	require.NoError(t, err)
+	tlsRootCA, err := ca.NewCA(filepath.Join(testDir, "tlsrootca"), testCAOrg, "tls"+testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	require.NoError(t, err)
+	tlsCA, err := tlsRootCA.NewIntermediateCA(filepath.Join(testDir, "tlsca"), testCAOrg, "intermediate.tls"+testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	require.NoError(t, err)
+
+	err = msp.GenerateLocalMSP(nodeDir, testName, []string{testName}, signCA, tlsCA, msp.PEER, true)
+	require.NoError(t, err)
+	err = msp.GenerateVerifyingMSP(verifyingMSPDir, signCA, tlsCA, true)
+	require.NoError(t, err)
+
+	for _, dir := range []string{mspDir, verifyingMSPDir} {
+		caCert, err := ca.LoadCertificateECDSA(filepath.Join(dir, "cacerts"))
+		require.NoError(t, err)
+		require.Equal(t, rootCA.SignCert, caCert)
+		intermediateCert, err := ca.LoadCertificateECDSA(filepath.Join(dir, "intermediatecerts"))
+		require.NoError(t, err)
+		require.Equal(t, signCA.SignCert, intermediateCert)
+		tlsCACert, err := ca.LoadCertificateECDSA(filepath.Join(dir, "tlscacerts"))
+		require.NoError(t, err)
+		require.Equal(t, tlsRootCA.SignCert, tlsCACert)
+		tlsIntermediateCert, err := ca.LoadCertificateECDSA(filepath.Join(dir, "tlsintermediatecerts"))
+		require.NoError(t, err)
+		require.Equal(t, tlsCA.SignCert, tlsIntermediateCert)
+
+		configBytes, err := ioutil.ReadFile(filepath.Join(dir, "config.yaml"))
+		require.NoError(t, err)
+		config := &fabricmsp.Configuration{}
+		require.NoError(t, yaml.Unmarshal(configBytes, config))
+		require.Equal(t, filepath.Join("intermediatecerts", "intermediate."+testCAName+"-cert.pem"), config.NodeOUs.PeerOUIdentifier.Certificate)
+	}
+
+	signCert, err := ca.LoadCertificateECDSA(filepath.Join(mspDir, "signcerts"))
+	require.NoError(t, err)
+	require.NoError(t, signCert.CheckSignatureFrom(signCA.SignCert))
+
+	// the TLS certificate is served along with the intermediate CA
+	// certificates and verifies against the TLS root CA
+	keyPair, err := tls.LoadX509KeyPair(filepath.Join(tlsDir, "server.crt"), filepath.Join(tlsDir, "server.key"))
+	require.NoError(t, err)
+	require.Len(t, keyPair.Certificate, 2)
+	require.Equal(t, tlsCA.SignCert.Raw, keyPair.Certificate[1])
+	tlsRootCert, err := ioutil.ReadFile(filepath.Join(tlsDir, "ca.crt"))
+	require.NoError(t, err)
+	block, _ := pem.Decode(tlsRootCert)
+	require.NotNil(t, block)
+	require.Equal(t, tlsRootCA.SignCert.Raw, block.Bytes)
+}
+
+func TestRenewLocalMSP(t *testing.T) {
+	testDir := t.TempDir()
+	caDir := filepath.Join(testDir, "ca")
+	tlsCADir := filepath.Join(testDir, "tlsca")
+	nodeDir := filepath.Join(testDir, testName)
+	mspDir := filepath.Join(nodeDir, "msp")
+	tlsDir := filepath.Join(nodeDir, "tls")
+
+	signCA, err := ca.NewCA(caDir, testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	require.NoError(t, err)
+	tlsCA, err := ca.NewCA(tlsCADir, testCAOrg, "tls"+testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	require.NoError(t, err)
+	err = msp.GenerateLocalMSP(nodeDir, testName, []string{testName}, signCA, tlsCA, msp.PEER, true)
+	require.NoError(t, err)
+	signCert, err := ca.LoadCertificateECDSA(filepath.Join(mspDir, "signcerts"))
+	require.NoError(t, err)
+	tlsCert, err := ioutil.ReadFile(filepath.Join(tlsDir, "server.crt"))
+	require.NoError(t, err)
+
+	renewals, err := msp.RenewLocalMSP(nodeDir, signCA, tlsCA)
+	require.NoError(t, err)
+	require.Len(t, renewals, 2)
+	require.Equal(t, filepath.Join(mspDir, "signcerts", testName+"-cert.pem"), renewals[0].Path)
+	require.Equal(t, signCert, renewals[0].Previous)
+	require.Equal(t, signCert.Subject.String(), renewals[0].Renewed.Subject.String())
+	require.Equal(t, signCert.PublicKey, renewals[0].Renewed.PublicKey)
+	require.NotEqual(t, signCert.SerialNumber, renewals[0].Renewed.SerialNumber)
+	require.Equal(t, filepath.Join(tlsDir, "server.crt"), renewals[1].Path)
+	require.Equal(t, []string{testName}, renewals[1].Renewed.DNSNames)
+	renewedTLSCert, err := ioutil.ReadFile(filepath.Join(tlsDir, "server.crt"))
+	require.NoError(t, err)
+	require.NotEqual(t, tlsCert, renewedTLSCert)
+	_, err = tls.LoadX509KeyPair(filepath.Join(tlsDir, "server.crt"), filepath.Join(tlsDir, "server.key"))
+	require.NoError(t, err)
+	require.False(t, checkForFile(filepath.Join(mspDir, "intermediatecerts")))
+
+	// certificates renewed under intermediate CAs are verifiable against the
+	// root CAs through the intermediate certificates added to the MSP
+	newSignCA, err := ca.NewCA(filepath.Join(testDir, "newca"), testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	require.NoError(t, err)
+	crossSigned, err := signCA.RenewCertificate(filepath.Join(testDir, "newca"), testCAName, newSignCA.SignCert)
+	require.NoError(t, err)
+	newTLSCA, err := ca.NewCA(filepath.Join(testDir, "newtlsca"), testCAOrg, "tls"+testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	require.NoError(t, err)
+	tlsCrossSigned, err := tlsCA.RenewCertificate(filepath.Join(testDir, "newtlsca"), "tls"+testCAName, newTLSCA.SignCert)
+	require.NoError(t, err)
+
+	renewals, err = msp.RenewLocalMSP(
+		nodeDir,
+		&ca.CA{Name: testCAName, Signer: newSignCA.Signer, SignCert: crossSigned, Chain: []*x509.Certificate{signCA.SignCert}},
+		&ca.CA{Name: "tls" + testCAName, Signer: newTLSCA.Signer, SignCert: tlsCrossSigned, Chain: []*x509.Certificate{tlsCA.SignCert}},
+	)
+	require.NoError(t, err)
+	require.NoError(t, renewals[0].Renewed.CheckSignatureFrom(crossSigned))
+	require.NoError(t, renewals[1].Renewed.CheckSignatureFrom(tlsCrossSigned))
+
+	intermediateCert, err := ca.LoadCertificateECDSA(filepath.Join(mspDir, "intermediatecerts"))
+	require.NoError(t, err)
+	require.Equal(t, crossSigned, intermediateCert)
+	tlsIntermediateCert, err := ca.LoadCertificateECDSA(filepath.Join(mspDir, "tlsintermediatecerts"))
+	require.NoError(t, err)
+	require.Equal(t, tlsCrossSigned, tlsIntermediateCert)
+
+	keyPair, err := tls.LoadX509KeyPair(filepath.Join(tlsDir, "server.crt"), filepath.Join(tlsDir, "server.key"))
+	require.NoError(t, err)
+	require.Len(t, keyPair.Certificate, 2)
+	require.Equal(t, tlsCrossSigned.Raw, keyPair.Certificate[1])
+
+	configBytes, err := ioutil.ReadFile(filepath.Join(mspDir, "config.yaml"))
+	require.NoError(t, err)
+	config := &fabricmsp.Configuration{}
+	require.NoError(t, yaml.Unmarshal(configBytes, config))
+	require.Equal(t, filepath.Join("intermediatecerts", testCAName+"-cert.pem"), config.NodeOUs.PeerOUIdentifier.Certificate)
+
+	_, err = msp.RenewLocalMSP(filepath.Join(testDir, "missing"), signCA, tlsCA)
+	require.Error(t, err)
+}
+
 func TestExportConfig(t *testing.T) {
 	path := filepath.Join(testDir, "export-test")
 	configFile := filepath.Join(path, "config.yaml")
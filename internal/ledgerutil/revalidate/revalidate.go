@@ -0,0 +1,446 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package revalidate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/sw"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/semaphore"
+	"github.com/hyperledger/fabric/core/chaincode/lifecycle"
+	"github.com/hyperledger/fabric/core/committer/txvalidator/plugin"
+	txvalidator "github.com/hyperledger/fabric/core/committer/txvalidator/v20"
+	vir "github.com/hyperledger/fabric/core/committer/txvalidator/v20/valinforetriever"
+	"github.com/hyperledger/fabric/core/handlers/library"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/core/scc/lscc"
+	gossipprivdata "github.com/hyperledger/fabric/gossip/privdata"
+	"github.com/hyperledger/fabric/internal/fileutil"
+	"github.com/hyperledger/fabric/internal/ledgerutil/jsonrw"
+	"github.com/hyperledger/fabric/internal/pkg/txflags"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+const (
+	ledgersDataDirName      = "ledgersData"
+	resultJsonFilename      = "txs.json"
+	defaultValidationPlugin = "vscc"
+)
+
+// Revalidate - Replays the blocks of a channel through transaction validation using a given channel config
+// The channel config is read from the config block at configBlockPath, which identifies the channel whose
+// blocks are read from the block store in fsPath. The blocks are committed in order to a scratch ledger, so
+// that each transaction is validated against the state resulting from the blocks before it. The blocks from
+// firstBlock to lastBlock are validated by the v20 transaction validator, with the given channel config and
+// the validation plugins, and by the MVCC validator of the ledger; the blocks before firstBlock keep their
+// recorded validation codes. Config transactions do not update the channel config used for validation.
+// The transactions whose validation code differs from the one recorded in the block metadata are written to a
+// JSON file in a directory created in outputDirLoc. validationPlugins maps the names of the validation plugins
+// used by the chaincodes to the shared libraries implementing them, in addition to the built-in "vscc" plugin.
+// It returns the number of differing transactions and the path to the output directory.
+func Revalidate(configBlockPath, fsPath, outputDirLoc string, firstBlock, lastBlock uint64, validationPlugins map[string]string) (int, string, error) {
+	cryptoProvider, err := sw.NewDefaultSecurityLevelWithKeystore(sw.NewDummyKeyStore())
+	if err != nil {
+		return 0, "", err
+	}
+
+	bundle, err := readChannelConfig(configBlockPath, cryptoProvider)
+	if err != nil {
+		return 0, "", err
+	}
+	channelID := bundle.ConfigtxValidator().ChannelID()
+
+	// Get the block store provider
+	blockStoreProvider, err := getBlockStoreProvider(fsPath)
+	if err != nil {
+		return 0, "", err
+	}
+	defer blockStoreProvider.Close()
+
+	exists, err := blockStoreProvider.Exists(channelID)
+	if err != nil {
+		return 0, "", err
+	}
+	if !exists {
+		return 0, "", errors.Errorf("BlockStore for %s does not exist in %s. Aborting revalidate", channelID, fsPath)
+	}
+
+	store, err := blockStoreProvider.Open(channelID)
+	if err != nil {
+		return 0, "", err
+	}
+	defer store.Shutdown()
+
+	info, err := store.GetBlockchainInfo()
+	if err != nil {
+		return 0, "", err
+	}
+	if info.GetBootstrappingSnapshotInfo() != nil {
+		return 0, "", errors.Errorf("the ledger for %s was bootstrapped from a snapshot and cannot be replayed from its genesis block. Aborting revalidate", channelID)
+	}
+	if lastBlock >= info.Height {
+		lastBlock = info.Height - 1
+	}
+	// The genesis block is not validated
+	if firstBlock == 0 {
+		firstBlock = 1
+	}
+	if firstBlock > lastBlock {
+		return 0, "", errors.Errorf("first block %d is greater than last block %d. Aborting revalidate", firstBlock, lastBlock)
+	}
+
+	// Create output directory
+	outputDirName := fmt.Sprintf("%s_revalidation_result", channelID)
+	outputDirPath := filepath.Join(outputDirLoc, outputDirName)
+	empty, err := fileutil.CreateDirIfMissing(outputDirPath)
+	if err != nil {
+		return 0, "", err
+	}
+	if !empty {
+		return 0, "", errors.Errorf("%s already exists in %s. Choose a different location or remove the existing results. Aborting revalidate", outputDirName, outputDirLoc)
+	}
+
+	scratchDir, err := os.MkdirTemp("", "ledgerutil-revalidate")
+	if err != nil {
+		return 0, "", err
+	}
+	defer os.RemoveAll(scratchDir)
+
+	pluginMapper, err := newPluginMapper(validationPlugins)
+	if err != nil {
+		return 0, "", err
+	}
+
+	channelConfig := &channelConfig{bundle: bundle}
+	validatorCommitter := &lifecycle.ValidatorCommitter{
+		CoreConfig:     &peer.Config{},
+		PrivdataConfig: &gossipprivdata.PrivdataConfig{},
+		Resources: &lifecycle.Resources{
+			Serializer:          &lifecycle.Serializer{},
+			ChannelConfigSource: channelConfig,
+		},
+		LegacyDeployedCCInfoProvider: &lscc.DeployedCCInfoProvider{},
+	}
+
+	ledgerProvider, err := kvledger.NewProvider(&ledger.Initializer{
+		DeployedChaincodeInfoProvider:   validatorCommitter,
+		MembershipInfoProvider:          &noMembership{},
+		ChaincodeLifecycleEventProvider: &noChaincodeLifecycleEvents{},
+		MetricsProvider:                 &disabled.Provider{},
+		HashProvider:                    cryptoProvider,
+		CustomTxProcessors: map[common.HeaderType]ledger.CustomTxProcessor{
+			common.HeaderType_CONFIG: &peer.ConfigTxProcessor{},
+		},
+		Config: &ledger.Config{
+			RootFSPath:    scratchDir,
+			StateDBConfig: &ledger.StateDBConfig{},
+			PrivateDataConfig: &ledger.PrivateDataConfig{
+				MaxBatchSize:    5000,
+				BatchesInterval: 1000,
+				PurgeInterval:   100,
+			},
+			HistoryDBConfig: &ledger.HistoryDBConfig{},
+			SnapshotsConfig: &ledger.SnapshotsConfig{
+				RootDir: filepath.Join(scratchDir, "snapshots"),
+			},
+		},
+	})
+	if err != nil {
+		return 0, "", err
+	}
+	defer ledgerProvider.Close()
+
+	iterator, err := store.RetrieveBlocks(0)
+	if err != nil {
+		return 0, "", err
+	}
+	defer iterator.Close()
+
+	genesisBlock, err := nextBlock(iterator, 0)
+	if err != nil {
+		return 0, "", err
+	}
+	scratchLedger, err := ledgerProvider.CreateFromGenesisBlock(genesisBlock)
+	if err != nil {
+		return 0, "", err
+	}
+	defer scratchLedger.Close()
+
+	validator := txvalidator.NewTxValidator(
+		channelID,
+		semaphore.New(runtime.NumCPU()),
+		channelConfig,
+		scratchLedger,
+		&vir.ValidationInfoRetrieveShim{
+			New:    validatorCommitter,
+			Legacy: &lscc.SCC{},
+		},
+		&peer.CollectionInfoShim{
+			CollectionAndLifecycleResources: validatorCommitter,
+			ChannelID:                       channelID,
+		},
+		pluginMapper,
+		policies.PolicyManagerGetterFunc(func(string) policies.Manager { return bundle.PolicyManager() }),
+		cryptoProvider,
+	)
+	// The creators of the transactions are verified with the MSPs of the channel configuration
+	validator.IdentityDeserializer = bundle.MSPManager()
+
+	// Open the result JSON for transactions
+	writer, err := jsonrw.NewJSONFileWriter(filepath.Join(outputDirPath, resultJsonFilename))
+	if err != nil {
+		return 0, "", err
+	}
+	defer writer.Close()
+
+	err = writer.OpenList()
+	if err != nil {
+		return 0, "", err
+	}
+
+	diffs := 0
+	for blockNum := uint64(1); blockNum <= lastBlock; blockNum++ {
+		block, err := nextBlock(iterator, blockNum)
+		if err != nil {
+			return 0, "", err
+		}
+
+		// Validation sets the transaction filter in the block metadata, hence a copy of the block is committed
+		replayed := proto.Clone(block).(*common.Block)
+		if blockNum >= firstBlock {
+			if err := validator.Validate(replayed); err != nil {
+				return 0, "", errors.WithMessagef(err, "failed to validate block %d", blockNum)
+			}
+		}
+
+		if err := scratchLedger.CommitLegacy(&ledger.BlockAndPvtData{Block: replayed}, &ledger.CommitOptions{}); err != nil {
+			return 0, "", errors.WithMessagef(err, "failed to commit block %d", blockNum)
+		}
+
+		if blockNum < firstBlock {
+			continue
+		}
+
+		results, err := compareValidationCodes(block, replayed)
+		if err != nil {
+			return 0, "", err
+		}
+		for _, result := range results {
+			if err := writer.AddEntry(result); err != nil {
+				return 0, "", err
+			}
+		}
+		diffs += len(results)
+	}
+
+	err = writer.CloseList()
+	if err != nil {
+		return 0, "", err
+	}
+
+	return diffs, outputDirPath, nil
+}
+
+// txResult - Records a transaction whose validation code differs from the recorded one
+type txResult struct {
+	BlockNum        uint64 `json:"blockNum"`
+	TxNum           int    `json:"txNum"`
+	TxID            string `json:"txId"`
+	RecordedCode    string `json:"recordedCode"`
+	RevalidatedCode string `json:"revalidatedCode"`
+}
+
+// compareValidationCodes - Compares the recorded validation codes of the transactions of a block with
+// those of its replayed copy
+func compareValidationCodes(recorded, replayed *common.Block) ([]*txResult, error) {
+	recordedFlags := txflags.ValidationFlags(recorded.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	replayedFlags := txflags.ValidationFlags(replayed.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	if len(recordedFlags) != len(recorded.Data.Data) {
+		return nil, errors.Errorf("block %d has %d transactions but %d recorded validation codes", recorded.Header.Number, len(recorded.Data.Data), len(recordedFlags))
+	}
+
+	var results []*txResult
+	for txNum := range recorded.Data.Data {
+		if recordedFlags.Flag(txNum) == replayedFlags.Flag(txNum) {
+			continue
+		}
+
+		var txID string
+		env, err := protoutil.GetEnvelopeFromBlock(recorded.Data.Data[txNum])
+		if err == nil {
+			if chdr, err := protoutil.ChannelHeader(env); err == nil {
+				txID = chdr.TxId
+			}
+		}
+
+		results = append(results, &txResult{
+			BlockNum:        recorded.Header.Number,
+			TxNum:           txNum,
+			TxID:            txID,
+			RecordedCode:    recordedFlags.Flag(txNum).String(),
+			RevalidatedCode: replayedFlags.Flag(txNum).String(),
+		})
+	}
+
+	return results, nil
+}
+
+// readChannelConfig - Reads the channel config from a config block file
+func readChannelConfig(configBlockPath string, cryptoProvider bccsp.BCCSP) (*channelconfig.Bundle, error) {
+	blockBytes, err := os.ReadFile(configBlockPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read config block %s", configBlockPath)
+	}
+	block, err := protoutil.UnmarshalBlock(blockBytes)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not unmarshal config block %s", configBlockPath)
+	}
+	env, err := protoutil.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not extract config envelope from %s", configBlockPath)
+	}
+	bundle, err := channelconfig.NewBundleFromEnvelope(env, cryptoProvider)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "could not load channel config from %s", configBlockPath)
+	}
+
+	ac, ok := bundle.ApplicationConfig()
+	if !ok || !ac.Capabilities().V2_0Validation() {
+		return nil, errors.Errorf("the channel config in %s does not enable the V2_0 application capability. Aborting revalidate", configBlockPath)
+	}
+	return bundle, nil
+}
+
+// newPluginMapper - Loads the built-in validation plugin and the given plugin libraries
+func newPluginMapper(validationPlugins map[string]string) (plugin.MapBasedMapper, error) {
+	validators := library.PluginMapping{
+		defaultValidationPlugin: &library.HandlerConfig{Name: "DefaultValidation"},
+	}
+	for name, path := range validationPlugins {
+		if path == "" {
+			return nil, errors.Errorf("no library given for validation plugin %s", name)
+		}
+		validators[name] = &library.HandlerConfig{Library: path}
+	}
+
+	reg := library.InitRegistry(library.Config{Validators: validators})
+	factories := reg.Lookup(library.Validation).(map[string]validation.PluginFactory)
+	return plugin.MapBasedMapper(factories), nil
+}
+
+// getBlockStoreProvider - Gets a default block store provider to access the peer's block store
+func getBlockStoreProvider(fsPath string) (*blkstorage.BlockStoreProvider, error) {
+	// Format path to block store
+	blockStorePath := kvledger.BlockStorePath(filepath.Join(fsPath, ledgersDataDirName))
+	isEmpty, err := fileutil.DirEmpty(blockStorePath)
+	if err != nil {
+		return nil, err
+	}
+	if isEmpty {
+		return nil, errors.Errorf("provided path %s is empty. Aborting revalidate", fsPath)
+	}
+	// Default fields for block store provider
+	conf := blkstorage.NewConf(blockStorePath, 0)
+	indexConfig := &blkstorage.IndexConfig{
+		AttrsToIndex: []blkstorage.IndexableAttr{
+			blkstorage.IndexableAttrBlockNum,
+			blkstorage.IndexableAttrBlockHash,
+			blkstorage.IndexableAttrTxID,
+			blkstorage.IndexableAttrBlockNumTranNum,
+		},
+	}
+	metricsProvider := &disabled.Provider{}
+	// Create new block store provider
+	return blkstorage.NewProvider(conf, indexConfig, metricsProvider)
+}
+
+// nextBlock - Retrieves the next block from the iterator, which is expected to have the given number
+func nextBlock(iterator commonledger.ResultsIterator, blockNum uint64) (*common.Block, error) {
+	result, err := iterator.Next()
+	if err != nil {
+		return nil, err
+	}
+	block, ok := result.(*common.Block)
+	if !ok || block == nil {
+		return nil, errors.Errorf("cannot decode the block %d", blockNum)
+	}
+	if block.Header.Number != blockNum {
+		return nil, errors.Errorf("the next block is expected to be %d but got %d", blockNum, block.Header.Number)
+	}
+	return block, nil
+}
+
+// channelConfig - Provides the given channel config to the validator and the lifecycle. Config transactions
+// are not applied, so that every block is validated with the given channel config.
+type channelConfig struct {
+	bundle *channelconfig.Bundle
+}
+
+func (c *channelConfig) MSPManager() msp.MSPManager {
+	return c.bundle.MSPManager()
+}
+
+func (c *channelConfig) Apply(*common.ConfigEnvelope) error {
+	return nil
+}
+
+func (c *channelConfig) GetMSPIDs() []string {
+	ac, ok := c.bundle.ApplicationConfig()
+	if !ok {
+		return nil
+	}
+	var mspIDs []string
+	for _, org := range ac.Organizations() {
+		mspIDs = append(mspIDs, org.MSPID())
+	}
+	return mspIDs
+}
+
+func (c *channelConfig) Capabilities() channelconfig.ApplicationCapabilities {
+	ac, _ := c.bundle.ApplicationConfig()
+	return ac.Capabilities()
+}
+
+func (c *channelConfig) GetStableChannelConfig(string) channelconfig.Resources {
+	return c.bundle
+}
+
+// noMembership - Reports that the peer is a member of no collection, as the replay has no private data
+type noMembership struct{}
+
+func (*noMembership) AmMemberOf(string, *pb.CollectionPolicyConfig) (bool, error) {
+	return false, nil
+}
+
+func (*noMembership) MyImplicitCollectionName() string {
+	return ""
+}
+
+// noChaincodeLifecycleEvents - Ignores the listeners of chaincode lifecycle events, as the scratch ledger
+// needs no state database indexes
+type noChaincodeLifecycleEvents struct{}
+
+func (*noChaincodeLifecycleEvents) RegisterListener(string, ledger.ChaincodeLifecycleEventListener, bool) error {
+	return nil
+}
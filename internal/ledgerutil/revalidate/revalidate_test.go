@@ -0,0 +1,180 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package revalidate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/common/policydsl"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	TestDataDir                 = "../testdata/"
+	SampleGoodLedgerDir         = TestDataDir + "sample_prod/"
+	SampleLedgerFromSnapshotDir = TestDataDir + "sample_ledger_from_snapshot/"
+	RevalidationResultFile      = "mychannel_revalidation_result/txs.json"
+)
+
+// writeConfigBlock - Writes the latest config block of mychannel in the block store to a file, after
+// applying modify to its channel config
+func writeConfigBlock(t *testing.T, fsDir string, modify func(*common.Config)) string {
+	blockStoreProvider, err := getBlockStoreProvider(fsDir)
+	require.NoError(t, err)
+	defer blockStoreProvider.Close()
+	store, err := blockStoreProvider.Open("mychannel")
+	require.NoError(t, err)
+	defer store.Shutdown()
+
+	info, err := store.GetBlockchainInfo()
+	require.NoError(t, err)
+	lastBlock, err := store.RetrieveBlockByNumber(info.Height - 1)
+	require.NoError(t, err)
+	configIndex, err := protoutil.GetLastConfigIndexFromBlock(lastBlock)
+	require.NoError(t, err)
+	configBlock, err := store.RetrieveBlockByNumber(configIndex)
+	require.NoError(t, err)
+
+	if modify != nil {
+		env, err := protoutil.ExtractEnvelope(configBlock, 0)
+		require.NoError(t, err)
+		payload, err := protoutil.UnmarshalPayload(env.Payload)
+		require.NoError(t, err)
+		configEnv := &common.ConfigEnvelope{}
+		require.NoError(t, proto.Unmarshal(payload.Data, configEnv))
+		modify(configEnv.Config)
+		payload.Data = protoutil.MarshalOrPanic(configEnv)
+		env.Payload = protoutil.MarshalOrPanic(payload)
+		configBlock.Data.Data[0] = protoutil.MarshalOrPanic(env)
+	}
+
+	configBlockPath := filepath.Join(t.TempDir(), "config.block")
+	require.NoError(t, os.WriteFile(configBlockPath, protoutil.MarshalOrPanic(configBlock), 0o644))
+	return configBlockPath
+}
+
+func readResults(t *testing.T, outputDir string) []*txResult {
+	resultBytes, err := os.ReadFile(filepath.Join(outputDir, RevalidationResultFile))
+	require.NoError(t, err)
+	var results []*txResult
+	require.NoError(t, json.Unmarshal(resultBytes, &results))
+	return results
+}
+
+func TestRevalidate(t *testing.T) {
+	rejectEndorsements := func(config *common.Config) {
+		application := config.ChannelGroup.Groups["Application"]
+		application.Policies["Endorsement"].Policy = &common.Policy{
+			Type:  int32(common.Policy_SIGNATURE),
+			Value: protoutil.MarshalOrPanic(policydsl.RejectAllPolicy),
+		}
+	}
+
+	testCases := map[string]struct {
+		modify        func(*common.Config)
+		firstBlock    uint64
+		expectedDiffs bool
+		expectedErr   string
+	}{
+		"unchanged-config": {
+			modify:        nil,
+			expectedDiffs: false,
+		},
+		"endorsement-policy-rejects-all": {
+			modify:        rejectEndorsements,
+			expectedDiffs: true,
+		},
+		"first-block-after-last-block": {
+			modify:      nil,
+			firstBlock:  1000,
+			expectedErr: "first block 1000 is greater than last block 7",
+		},
+	}
+
+	for testName, testCase := range testCases {
+		t.Run(testName, func(t *testing.T) {
+			outputDir := t.TempDir()
+			fsDir := t.TempDir()
+			require.NoError(t, testutil.CopyDir(SampleGoodLedgerDir, fsDir, false))
+			configBlockPath := writeConfigBlock(t, fsDir, testCase.modify)
+
+			count, outputDirPath, err := Revalidate(configBlockPath, fsDir, outputDir, testCase.firstBlock, 1000, nil)
+			if testCase.expectedErr != "" {
+				require.ErrorContains(t, err, testCase.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, filepath.Join(outputDir, "mychannel_revalidation_result"), outputDirPath)
+
+			results := readResults(t, outputDir)
+			require.Len(t, results, count)
+			if !testCase.expectedDiffs {
+				require.Zero(t, count)
+				return
+			}
+
+			require.NotZero(t, count)
+			for _, result := range results {
+				require.NotZero(t, result.BlockNum)
+				require.NotEmpty(t, result.TxID)
+				require.Equal(t, "VALID", result.RecordedCode)
+				require.Equal(t, "ENDORSEMENT_POLICY_FAILURE", result.RevalidatedCode)
+			}
+		})
+	}
+}
+
+func TestRevalidateErrors(t *testing.T) {
+	fsDir := t.TempDir()
+	require.NoError(t, testutil.CopyDir(SampleGoodLedgerDir, fsDir, false))
+	configBlockPath := writeConfigBlock(t, fsDir, nil)
+
+	t.Run("config-block-does-not-exist", func(t *testing.T) {
+		_, _, err := Revalidate(filepath.Join(t.TempDir(), "missing.block"), fsDir, t.TempDir(), 0, 1000, nil)
+		require.ErrorContains(t, err, "could not read config block")
+	})
+
+	t.Run("config-block-is-not-a-block", func(t *testing.T) {
+		badBlockPath := filepath.Join(t.TempDir(), "bad.block")
+		require.NoError(t, os.WriteFile(badBlockPath, []byte("garbage"), 0o644))
+		_, _, err := Revalidate(badBlockPath, fsDir, t.TempDir(), 0, 1000, nil)
+		require.ErrorContains(t, err, "could not unmarshal config block")
+	})
+
+	t.Run("empty-block-store", func(t *testing.T) {
+		emptyDir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(emptyDir, "ledgersData", "chains"), 0o700))
+		_, _, err := Revalidate(configBlockPath, emptyDir, t.TempDir(), 0, 1000, nil)
+		require.ErrorContains(t, err, "is empty. Aborting revalidate")
+	})
+
+	t.Run("ledger-bootstrapped-from-snapshot", func(t *testing.T) {
+		snapshotDir := t.TempDir()
+		require.NoError(t, testutil.CopyDir(SampleLedgerFromSnapshotDir, snapshotDir, false))
+		_, _, err := Revalidate(configBlockPath, snapshotDir, t.TempDir(), 0, 1000, nil)
+		require.ErrorContains(t, err, "was bootstrapped from a snapshot")
+	})
+
+	t.Run("output-directory-not-empty", func(t *testing.T) {
+		outputDir := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(outputDir, "mychannel_revalidation_result", "existing"), 0o700))
+		_, _, err := Revalidate(configBlockPath, fsDir, outputDir, 0, 1000, nil)
+		require.ErrorContains(t, err, "mychannel_revalidation_result already exists")
+	})
+
+	t.Run("validation-plugin-without-library", func(t *testing.T) {
+		_, _, err := Revalidate(configBlockPath, fsDir, t.TempDir(), 0, 1000, map[string]string{"custom": ""})
+		require.ErrorContains(t, err, "no library given for validation plugin custom")
+	})
+}
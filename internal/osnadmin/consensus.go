@@ -0,0 +1,56 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package osnadmin
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/hyperledger/fabric/orderer/common/types"
+)
+
+// Reports the consensus status of a channel an OSN is a member of.
+func ConsensusInfo(osnURL, channelID string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate) (*http.Response, error) {
+	url := fmt.Sprintf("%s/participation/v1/channels/%s/consensus", osnURL, channelID)
+
+	return httpGet(url, caCertPool, tlsClientCert)
+}
+
+// Makes an OSN vote for a view change away from the current leader of a channel.
+func ForceViewChange(osnURL, channelID string, caCertPool *x509.CertPool, tlsClientCert tls.Certificate) (*http.Response, error) {
+	url := fmt.Sprintf("%s/participation/v1/channels/%s/consensus/viewchange", osnURL, channelID)
+
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return httpDo(req, caCertPool, tlsClientCert)
+}
+
+// Makes an OSN vote for a view change whenever the given node is the leader of a channel, for the given duration.
+func BlacklistLeader(osnURL, channelID string, id uint64, duration time.Duration, caCertPool *x509.CertPool, tlsClientCert tls.Certificate) (*http.Response, error) {
+	url := fmt.Sprintf("%s/participation/v1/channels/%s/consensus/blacklist", osnURL, channelID)
+
+	body, err := json.Marshal(&types.BlacklistLeaderRequest{ID: id, Duration: duration.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return httpDo(req, caCertPool, tlsClientCert)
+}
@@ -18,7 +18,7 @@ import (
 // Joins an OSN to a new or existing channel.
 func Join(osnURL string, blockBytes []byte, caCertPool *x509.CertPool, tlsClientCert tls.Certificate) (*http.Response, error) {
 	url := fmt.Sprintf("%s/participation/v1/channels", osnURL)
-	req, err := createJoinRequest(url, blockBytes)
+	req, err := createJoinRequest(url, blockBytes, false)
 	if err != nil {
 		return nil, err
 	}
@@ -26,7 +26,19 @@ func Join(osnURL string, blockBytes []byte, caCertPool *x509.CertPool, tlsClient
 	return httpDo(req, caCertPool, tlsClientCert)
 }
 
-func createJoinRequest(url string, blockBytes []byte) (*http.Request, error) {
+// Joins an OSN to an existing channel, starting its ledger at a signed
+// checkpoint config block instead of the genesis block.
+func JoinFromCheckpoint(osnURL string, blockBytes []byte, caCertPool *x509.CertPool, tlsClientCert tls.Certificate) (*http.Response, error) {
+	url := fmt.Sprintf("%s/participation/v1/channels", osnURL)
+	req, err := createJoinRequest(url, blockBytes, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return httpDo(req, caCertPool, tlsClientCert)
+}
+
+func createJoinRequest(url string, blockBytes []byte, fromCheckpoint bool) (*http.Request, error) {
 	joinBody := new(bytes.Buffer)
 	writer := multipart.NewWriter(joinBody)
 	part, err := writer.CreateFormFile("config-block", "config.block")
@@ -34,6 +46,11 @@ func createJoinRequest(url string, blockBytes []byte) (*http.Request, error) {
 		return nil, err
 	}
 	part.Write(blockBytes)
+	if fromCheckpoint {
+		if err := writer.WriteField("checkpoint", "true"); err != nil {
+			return nil, err
+		}
+	}
 	err = writer.Close()
 	if err != nil {
 		return nil, err
@@ -12,11 +12,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
-	"os"
 	"strings"
 	"sync"
 
-	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-chaincode-go/shim"
 	pcommon "github.com/hyperledger/fabric-protos-go/common"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
@@ -25,6 +23,7 @@ import (
 	"github.com/hyperledger/fabric/common/policydsl"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/hyperledger/fabric/internal/pkg/collections"
 	"github.com/hyperledger/fabric/internal/pkg/identity"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
@@ -181,136 +180,6 @@ func chaincodeInvokeOrQuery(cmd *cobra.Command, invoke bool, cf *ChaincodeCmdFac
 	return nil
 }
 
-type endorsementPolicy struct {
-	ChannelConfigPolicy string `json:"channelConfigPolicy,omitempty"`
-	SignaturePolicy     string `json:"signaturePolicy,omitempty"`
-}
-
-type collectionConfigJson struct {
-	Name              string             `json:"name"`
-	Policy            string             `json:"policy"`
-	RequiredPeerCount *int32             `json:"requiredPeerCount"`
-	MaxPeerCount      *int32             `json:"maxPeerCount"`
-	BlockToLive       uint64             `json:"blockToLive"`
-	MemberOnlyRead    bool               `json:"memberOnlyRead"`
-	MemberOnlyWrite   bool               `json:"memberOnlyWrite"`
-	EndorsementPolicy *endorsementPolicy `json:"endorsementPolicy,omitempty"`
-}
-
-// GetCollectionConfigFromFile retrieves the collection configuration
-// from the supplied file; the supplied file must contain a
-// json-formatted array of collectionConfigJson elements
-func GetCollectionConfigFromFile(ccFile string) (*pb.CollectionConfigPackage, []byte, error) {
-	fileBytes, err := os.ReadFile(ccFile)
-	if err != nil {
-		return nil, nil, errors.Wrapf(err, "could not read file '%s'", ccFile)
-	}
-
-	return getCollectionConfigFromBytes(fileBytes)
-}
-
-// getCollectionConfig retrieves the collection configuration
-// from the supplied byte array; the byte array must contain a
-// json-formatted array of collectionConfigJson elements
-func getCollectionConfigFromBytes(cconfBytes []byte) (*pb.CollectionConfigPackage, []byte, error) {
-	cconf := &[]collectionConfigJson{}
-	err := json.Unmarshal(cconfBytes, cconf)
-	if err != nil {
-		return nil, nil, errors.Wrap(err, "could not parse the collection configuration")
-	}
-
-	ccarray := make([]*pb.CollectionConfig, 0, len(*cconf))
-	for _, cconfitem := range *cconf {
-		p, err := policydsl.FromString(cconfitem.Policy)
-		if err != nil {
-			return nil, nil, errors.WithMessagef(err, "invalid policy %s", cconfitem.Policy)
-		}
-
-		cpc := &pb.CollectionPolicyConfig{
-			Payload: &pb.CollectionPolicyConfig_SignaturePolicy{
-				SignaturePolicy: p,
-			},
-		}
-
-		var ep *pb.ApplicationPolicy
-		if cconfitem.EndorsementPolicy != nil {
-			signaturePolicy := cconfitem.EndorsementPolicy.SignaturePolicy
-			channelConfigPolicy := cconfitem.EndorsementPolicy.ChannelConfigPolicy
-			ep, err = getApplicationPolicy(signaturePolicy, channelConfigPolicy)
-			if err != nil {
-				return nil, nil, errors.WithMessagef(err, "invalid endorsement policy [%#v]", cconfitem.EndorsementPolicy)
-			}
-		}
-
-		// Set default requiredPeerCount and MaxPeerCount if not specified in json
-		requiredPeerCount := int32(0)
-		maxPeerCount := int32(1)
-		if cconfitem.RequiredPeerCount != nil {
-			requiredPeerCount = *cconfitem.RequiredPeerCount
-		}
-		if cconfitem.MaxPeerCount != nil {
-			maxPeerCount = *cconfitem.MaxPeerCount
-		}
-
-		cc := &pb.CollectionConfig{
-			Payload: &pb.CollectionConfig_StaticCollectionConfig{
-				StaticCollectionConfig: &pb.StaticCollectionConfig{
-					Name:              cconfitem.Name,
-					MemberOrgsPolicy:  cpc,
-					RequiredPeerCount: requiredPeerCount,
-					MaximumPeerCount:  maxPeerCount,
-					BlockToLive:       cconfitem.BlockToLive,
-					MemberOnlyRead:    cconfitem.MemberOnlyRead,
-					MemberOnlyWrite:   cconfitem.MemberOnlyWrite,
-					EndorsementPolicy: ep,
-				},
-			},
-		}
-
-		ccarray = append(ccarray, cc)
-	}
-
-	ccp := &pb.CollectionConfigPackage{Config: ccarray}
-	ccpBytes, err := proto.Marshal(ccp)
-	return ccp, ccpBytes, err
-}
-
-func getApplicationPolicy(signaturePolicy, channelConfigPolicy string) (*pb.ApplicationPolicy, error) {
-	if signaturePolicy == "" && channelConfigPolicy == "" {
-		// no policy, no problem
-		return nil, nil
-	}
-
-	if signaturePolicy != "" && channelConfigPolicy != "" {
-		// mo policies, mo problems
-		return nil, errors.New(`cannot specify both "--signature-policy" and "--channel-config-policy"`)
-	}
-
-	var applicationPolicy *pb.ApplicationPolicy
-	if signaturePolicy != "" {
-		signaturePolicyEnvelope, err := policydsl.FromString(signaturePolicy)
-		if err != nil {
-			return nil, errors.Errorf("invalid signature policy: %s", signaturePolicy)
-		}
-
-		applicationPolicy = &pb.ApplicationPolicy{
-			Type: &pb.ApplicationPolicy_SignaturePolicy{
-				SignaturePolicy: signaturePolicyEnvelope,
-			},
-		}
-	}
-
-	if channelConfigPolicy != "" {
-		applicationPolicy = &pb.ApplicationPolicy{
-			Type: &pb.ApplicationPolicy_ChannelConfigPolicyReference{
-				ChannelConfigPolicyReference: channelConfigPolicy,
-			},
-		}
-	}
-
-	return applicationPolicy, nil
-}
-
 func checkChaincodeCmdParams(cmd *cobra.Command) error {
 	// we need chaincode name for everything, including deploy
 	if chaincodeName == common.UndefinedParamValue {
@@ -347,7 +216,7 @@ func checkChaincodeCmdParams(cmd *cobra.Command) error {
 
 		if collectionsConfigFile != common.UndefinedParamValue {
 			var err error
-			_, collectionConfigBytes, err = GetCollectionConfigFromFile(collectionsConfigFile)
+			_, collectionConfigBytes, err = collections.ConfigFromFile(collectionsConfigFile)
 			if err != nil {
 				return errors.WithMessagef(err, "invalid collection configuration in file %s", collectionsConfigFile)
 			}
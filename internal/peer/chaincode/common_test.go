@@ -17,11 +17,9 @@ import (
 	"testing"
 	"time"
 
-	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp/sw"
-	"github.com/hyperledger/fabric/common/policydsl"
 	"github.com/hyperledger/fabric/core/config/configtest"
 	"github.com/hyperledger/fabric/internal/peer/chaincode/mock"
 	"github.com/hyperledger/fabric/internal/peer/common"
@@ -149,197 +147,6 @@ func TestCheckInvalidJSON(t *testing.T) {
 	}
 }
 
-const sampleCollectionConfigGood = `[
-	{
-		"name": "foo",
-		"policy": "OR('A.member', 'B.member')",
-		"requiredPeerCount": 3,
-		"maxPeerCount": 483279847,
-		"blockToLive":10,
-		"memberOnlyRead": true,
-		"memberOnlyWrite": true
-	}
-]`
-
-const sampleCollectionConfigGoodNoMaxPeerCountOrRequiredPeerCount = `[
-	{
-		"name": "foo",
-		"policy": "OR('A.member', 'B.member')",
-		"blockToLive":10,
-		"memberOnlyRead": true,
-		"memberOnlyWrite": true
-	}
-]`
-
-const sampleCollectionConfigGoodWithSignaturePolicy = `[
-	{
-		"name": "foo",
-		"policy": "OR('A.member', 'B.member')",
-		"requiredPeerCount": 3,
-		"maxPeerCount": 483279847,
-		"blockToLive":10,
-		"memberOnlyRead": true,
-		"memberOnlyWrite": true,
-		"endorsementPolicy": {
-			"signaturePolicy": "OR('A.member', 'B.member')"
-		}
-	}
-]`
-
-const sampleCollectionConfigGoodWithChannelConfigPolicy = `[
-	{
-		"name": "foo",
-		"policy": "OR('A.member', 'B.member')",
-		"requiredPeerCount": 3,
-		"maxPeerCount": 483279847,
-		"blockToLive":10,
-		"memberOnlyRead": true,
-		"memberOnlyWrite": true,
-		"endorsementPolicy": {
-			"channelConfigPolicy": "/Channel/Application/Endorsement"
-		}
-	}
-]`
-
-const sampleCollectionConfigBad = `[
-	{
-		"name": "foo",
-		"policy": "barf",
-		"requiredPeerCount": 3,
-		"maxPeerCount": 483279847
-	}
-]`
-
-const sampleCollectionConfigBadInvalidSignaturePolicy = `[
-	{
-		"name": "foo",
-		"policy": "OR('A.member', 'B.member')",
-		"requiredPeerCount": 3,
-		"maxPeerCount": 483279847,
-		"blockToLive":10,
-		"memberOnlyRead": true,
-		"memberOnlyWrite": true,
-		"endorsementPolicy": {
-			"signaturePolicy": "invalid"
-		}
-	}
-]`
-
-const sampleCollectionConfigBadSignaturePolicyAndChannelConfigPolicy = `[
-	{
-		"name": "foo",
-		"policy": "OR('A.member', 'B.member')",
-		"requiredPeerCount": 3,
-		"maxPeerCount": 483279847,
-		"blockToLive":10,
-		"memberOnlyRead": true,
-		"memberOnlyWrite": true,
-		"endorsementPolicy": {
-			"signaturePolicy": "OR('A.member', 'B.member')",
-			"channelConfigPolicy": "/Channel/Application/Endorsement"
-		}
-	}
-]`
-
-func TestCollectionParsing(t *testing.T) {
-	ccp, ccpBytes, err := getCollectionConfigFromBytes([]byte(sampleCollectionConfigGood))
-	require.NoError(t, err)
-	require.NotNil(t, ccp)
-	require.NotNil(t, ccpBytes)
-	conf := ccp.Config[0].GetStaticCollectionConfig()
-	pol, _ := policydsl.FromString("OR('A.member', 'B.member')")
-	require.Equal(t, 3, int(conf.RequiredPeerCount))
-	require.Equal(t, 483279847, int(conf.MaximumPeerCount))
-	require.Equal(t, "foo", conf.Name)
-	require.True(t, proto.Equal(pol, conf.MemberOrgsPolicy.GetSignaturePolicy()))
-	require.Equal(t, 10, int(conf.BlockToLive))
-	require.Equal(t, true, conf.MemberOnlyRead)
-	require.Nil(t, conf.EndorsementPolicy)
-	t.Logf("conf=%s", conf)
-
-	// Test default values for RequiredPeerCount and MaxPeerCount
-	ccp, ccpBytes, err = getCollectionConfigFromBytes([]byte(sampleCollectionConfigGoodNoMaxPeerCountOrRequiredPeerCount))
-	require.NoError(t, err)
-	require.NotNil(t, ccp)
-	require.NotNil(t, ccpBytes)
-	conf = ccp.Config[0].GetStaticCollectionConfig()
-	pol, _ = policydsl.FromString("OR('A.member', 'B.member')")
-	require.Equal(t, 0, int(conf.RequiredPeerCount))
-	require.Equal(t, 1, int(conf.MaximumPeerCount))
-	require.Equal(t, "foo", conf.Name)
-	require.True(t, proto.Equal(pol, conf.MemberOrgsPolicy.GetSignaturePolicy()))
-	require.Equal(t, 10, int(conf.BlockToLive))
-	require.Equal(t, true, conf.MemberOnlyRead)
-	require.Nil(t, conf.EndorsementPolicy)
-	t.Logf("conf=%s", conf)
-
-	ccp, ccpBytes, err = getCollectionConfigFromBytes([]byte(sampleCollectionConfigGoodWithSignaturePolicy))
-	require.NoError(t, err)
-	require.NotNil(t, ccp)
-	require.NotNil(t, ccpBytes)
-	conf = ccp.Config[0].GetStaticCollectionConfig()
-	pol, _ = policydsl.FromString("OR('A.member', 'B.member')")
-	require.Equal(t, 3, int(conf.RequiredPeerCount))
-	require.Equal(t, 483279847, int(conf.MaximumPeerCount))
-	require.Equal(t, "foo", conf.Name)
-	require.True(t, proto.Equal(pol, conf.MemberOrgsPolicy.GetSignaturePolicy()))
-	require.Equal(t, 10, int(conf.BlockToLive))
-	require.Equal(t, true, conf.MemberOnlyRead)
-	require.True(t, proto.Equal(pol, conf.EndorsementPolicy.GetSignaturePolicy()))
-	t.Logf("conf=%s", conf)
-
-	ccp, ccpBytes, err = getCollectionConfigFromBytes([]byte(sampleCollectionConfigGoodWithChannelConfigPolicy))
-	require.NoError(t, err)
-	require.NotNil(t, ccp)
-	require.NotNil(t, ccpBytes)
-	conf = ccp.Config[0].GetStaticCollectionConfig()
-	pol, _ = policydsl.FromString("OR('A.member', 'B.member')")
-	require.Equal(t, 3, int(conf.RequiredPeerCount))
-	require.Equal(t, 483279847, int(conf.MaximumPeerCount))
-	require.Equal(t, "foo", conf.Name)
-	require.True(t, proto.Equal(pol, conf.MemberOrgsPolicy.GetSignaturePolicy()))
-	require.Equal(t, 10, int(conf.BlockToLive))
-	require.Equal(t, true, conf.MemberOnlyRead)
-	require.Equal(t, "/Channel/Application/Endorsement", conf.EndorsementPolicy.GetChannelConfigPolicyReference())
-	t.Logf("conf=%s", conf)
-
-	failureTests := []struct {
-		name             string
-		collectionConfig string
-		expectedErr      string
-	}{
-		{
-			name:             "Invalid member orgs policy",
-			collectionConfig: sampleCollectionConfigBad,
-			expectedErr:      "invalid policy barf: unrecognized token 'barf' in policy string",
-		},
-		{
-			name:             "Invalid collection config",
-			collectionConfig: "barf",
-			expectedErr:      "could not parse the collection configuration: invalid character 'b' looking for beginning of value",
-		},
-		{
-			name:             "Invalid signature policy",
-			collectionConfig: sampleCollectionConfigBadInvalidSignaturePolicy,
-			expectedErr:      `invalid endorsement policy [&chaincode.endorsementPolicy{ChannelConfigPolicy:"", SignaturePolicy:"invalid"}]: invalid signature policy: invalid`,
-		},
-		{
-			name:             "Signature policy and channel config policy both specified",
-			collectionConfig: sampleCollectionConfigBadSignaturePolicyAndChannelConfigPolicy,
-			expectedErr:      `invalid endorsement policy [&chaincode.endorsementPolicy{ChannelConfigPolicy:"/Channel/Application/Endorsement", SignaturePolicy:"OR('A.member', 'B.member')"}]: cannot specify both "--signature-policy" and "--channel-config-policy"`,
-		},
-	}
-
-	for _, test := range failureTests {
-		t.Run(test.name, func(t *testing.T) {
-			ccp, ccpBytes, err = getCollectionConfigFromBytes([]byte(test.collectionConfig))
-			require.EqualError(t, err, test.expectedErr)
-			require.Nil(t, ccp)
-			require.Nil(t, ccpBytes)
-		})
-	}
-}
-
 func TestValidatePeerConnectionParams(t *testing.T) {
 	defer resetFlags()
 	defer viper.Reset()
@@ -15,7 +15,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/policydsl"
-	"github.com/hyperledger/fabric/internal/peer/chaincode"
+	"github.com/hyperledger/fabric/internal/pkg/collections"
 	"github.com/hyperledger/fabric/protoutil"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
@@ -111,7 +111,7 @@ func createCollectionConfigPackage(collectionsConfigFile string) (*pb.Collection
 	var ccp *pb.CollectionConfigPackage
 	if collectionsConfigFile != "" {
 		var err error
-		ccp, _, err = chaincode.GetCollectionConfigFromFile(collectionsConfigFile)
+		ccp, _, err = collections.ConfigFromFile(collectionsConfigFile)
 		if err != nil {
 			return nil, errors.WithMessagef(err, "invalid collection configuration in file %s", collectionsConfigFile)
 		}
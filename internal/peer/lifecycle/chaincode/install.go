@@ -39,7 +39,8 @@ type Installer struct {
 // InstallInput holds the input parameters for installing
 // a chaincode.
 type InstallInput struct {
-	PackageFile string
+	PackageFile    string
+	SignaturesFile string
 }
 
 // Validate checks that the required install parameters
@@ -92,6 +93,7 @@ func InstallCmd(i *Installer, cryptoProvider bccsp.BCCSP) *cobra.Command {
 		"tlsRootCertFiles",
 		"connectionProfile",
 		"targetPeer",
+		"signatures",
 	}
 	attachFlags(chaincodeInstallCmd, flagList)
 
@@ -111,7 +113,9 @@ func (i *Installer) InstallChaincode(args []string) error {
 }
 
 func (i *Installer) setInput(args []string) {
-	i.Input = &InstallInput{}
+	i.Input = &InstallInput{
+		SignaturesFile: signaturesFile,
+	}
 
 	if len(args) > 0 {
 		i.Input.PackageFile = args[0]
@@ -130,12 +134,23 @@ func (i *Installer) Install() error {
 		return errors.WithMessagef(err, "failed to read chaincode package at '%s'", i.Input.PackageFile)
 	}
 
+	var signaturesBytes []byte
+	if i.Input.SignaturesFile != "" {
+		signaturesBytes, err = i.Reader.ReadFile(i.Input.SignaturesFile)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to read package signatures at '%s'", i.Input.SignaturesFile)
+		}
+		if _, err := persistence.ParsePackageSignatures(signaturesBytes); err != nil {
+			return errors.WithMessagef(err, "failed to parse package signatures at '%s'", i.Input.SignaturesFile)
+		}
+	}
+
 	serializedSigner, err := i.Signer.Serialize()
 	if err != nil {
 		return errors.Wrap(err, "failed to serialize signer")
 	}
 
-	proposal, err := i.createInstallProposal(pkgBytes, serializedSigner)
+	proposal, err := i.createInstallProposal(pkgBytes, signaturesBytes, serializedSigner)
 	if err != nil {
 		return err
 	}
@@ -177,7 +192,7 @@ func (i *Installer) submitInstallProposal(signedProposal *pb.SignedProposal) err
 	return nil
 }
 
-func (i *Installer) createInstallProposal(pkgBytes []byte, creatorBytes []byte) (*pb.Proposal, error) {
+func (i *Installer) createInstallProposal(pkgBytes, signaturesBytes, creatorBytes []byte) (*pb.Proposal, error) {
 	installChaincodeArgs := &lb.InstallChaincodeArgs{
 		ChaincodeInstallPackage: pkgBytes,
 	}
@@ -196,7 +211,14 @@ func (i *Installer) createInstallProposal(pkgBytes []byte, creatorBytes []byte)
 		},
 	}
 
-	proposal, _, err := protoutil.CreateProposalFromCIS(cb.HeaderType_ENDORSER_TRANSACTION, "", cis, creatorBytes)
+	// the detached signatures of the package are carried as transient data,
+	// which is not part of the proposal response
+	var transientMap map[string][]byte
+	if signaturesBytes != nil {
+		transientMap = map[string][]byte{persistence.SignaturesTransientKey: signaturesBytes}
+	}
+
+	proposal, _, err := protoutil.CreateChaincodeProposalWithTransient(cb.HeaderType_ENDORSER_TRANSACTION, "", cis, creatorBytes, transientMap)
 	if err != nil {
 		return nil, errors.WithMessage(err, "failed to create proposal for ChaincodeInvocationSpec")
 	}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package chaincode_test
 
 import (
+	"github.com/golang/protobuf/proto"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp/sw"
 	"github.com/hyperledger/fabric/internal/peer/lifecycle/chaincode"
@@ -58,6 +59,58 @@ var _ = Describe("Install", func() {
 			Expect(err).NotTo(HaveOccurred())
 		})
 
+		Context("when the package signatures are provided", func() {
+			BeforeEach(func() {
+				installer.Input.SignaturesFile = "pkgFile.signatures.json"
+				mockReader.ReadFileStub = func(name string) ([]byte, error) {
+					if name == "pkgFile.signatures.json" {
+						return []byte(`{"signatures":[{"signer":"c2lnbmVy","signature":"c2lnbmF0dXJl"}]}`), nil
+					}
+					return []byte("package"), nil
+				}
+			})
+
+			It("carries the signatures in the transient data of the proposal", func() {
+				err := installer.Install()
+				Expect(err).NotTo(HaveOccurred())
+
+				_, signedProposal, _ := mockEndorserClient.ProcessProposalArgsForCall(0)
+				proposal := &pb.Proposal{}
+				Expect(proto.Unmarshal(signedProposal.ProposalBytes, proposal)).To(Succeed())
+				payload := &pb.ChaincodeProposalPayload{}
+				Expect(proto.Unmarshal(proposal.Payload, payload)).To(Succeed())
+				Expect(payload.TransientMap).To(HaveKeyWithValue("package_signatures", []byte(`{"signatures":[{"signer":"c2lnbmVy","signature":"c2lnbmF0dXJl"}]}`)))
+			})
+
+			Context("when the signatures cannot be read", func() {
+				BeforeEach(func() {
+					mockReader.ReadFileStub = func(name string) ([]byte, error) {
+						if name == "pkgFile.signatures.json" {
+							return nil, errors.New("mocha")
+						}
+						return []byte("package"), nil
+					}
+				})
+
+				It("returns an error", func() {
+					err := installer.Install()
+					Expect(err).To(MatchError("failed to read package signatures at 'pkgFile.signatures.json': mocha"))
+				})
+			})
+
+			Context("when the signatures cannot be parsed", func() {
+				BeforeEach(func() {
+					mockReader.ReadFileReturns([]byte("garbage"), nil)
+					mockReader.ReadFileStub = nil
+				})
+
+				It("returns an error", func() {
+					err := installer.Install()
+					Expect(err).To(MatchError(ContainSubstring("failed to parse package signatures at 'pkgFile.signatures.json'")))
+				})
+			})
+		})
+
 		Context("when the chaincode install package is not provided", func() {
 			BeforeEach(func() {
 				installer.Input.PackageFile = ""
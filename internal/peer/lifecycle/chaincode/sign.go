@@ -0,0 +1,148 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// PackageSigner holds the dependencies needed to sign
+// a chaincode install package.
+type PackageSigner struct {
+	Command *cobra.Command
+	Input   *SignInput
+	Reader  Reader
+	Signer  Signer
+	Writer  Writer
+}
+
+// SignInput holds the input parameters for signing
+// a chaincode install package.
+type SignInput struct {
+	PackageFile string
+}
+
+// Validate checks that the required sign parameters
+// are provided.
+func (s *SignInput) Validate() error {
+	if s.PackageFile == "" {
+		return errors.New("chaincode install package must be provided")
+	}
+
+	return nil
+}
+
+// SignCmd returns the cobra command for signing a chaincode install package.
+func SignCmd(s *PackageSigner) *cobra.Command {
+	chaincodeSignCmd := &cobra.Command{
+		Use:   "sign packageFile",
+		Short: "Sign a chaincode install package.",
+		Long: "Sign a chaincode install package with the local MSP identity. The signature is detached from the package, which is left untouched, " +
+			"and is added to the signatures stored next to the package in packageFile" + persistence.SignaturesFileSuffix + ".",
+		ValidArgs: []string{"1"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if s == nil {
+				signer, err := common.GetDefaultSigner()
+				if err != nil {
+					return err
+				}
+				s = &PackageSigner{
+					Reader: &persistence.FilesystemIO{},
+					Signer: signer,
+					Writer: &persistence.FilesystemIO{},
+				}
+			}
+			s.Command = cmd
+
+			return s.SignPackage(args)
+		},
+	}
+
+	return chaincodeSignCmd
+}
+
+// SignPackage signs the chaincode install package.
+func (s *PackageSigner) SignPackage(args []string) error {
+	if s.Command != nil {
+		// Parsing of the command line is done so silence cmd usage
+		s.Command.SilenceUsage = true
+	}
+
+	if len(args) != 1 {
+		return errors.New("invalid number of args. expected only the packaged chaincode file")
+	}
+	s.Input = &SignInput{
+		PackageFile: args[0],
+	}
+
+	return s.Sign()
+}
+
+// Sign adds a signature of the chaincode install package to the signatures
+// stored next to the package.
+func (s *PackageSigner) Sign() error {
+	err := s.Input.Validate()
+	if err != nil {
+		return err
+	}
+
+	pkgBytes, err := s.Reader.ReadFile(s.Input.PackageFile)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to read chaincode package at '%s'", s.Input.PackageFile)
+	}
+
+	if _, _, err := persistence.ParseChaincodePackage(pkgBytes); err != nil {
+		return errors.WithMessage(err, "could not parse as a chaincode install package")
+	}
+
+	signaturesFile := s.Input.PackageFile + persistence.SignaturesFileSuffix
+	signatures := &persistence.PackageSignatures{}
+	signaturesBytes, err := s.Reader.ReadFile(signaturesFile)
+	switch {
+	case err == nil:
+		if signatures, err = persistence.ParsePackageSignatures(signaturesBytes); err != nil {
+			return errors.WithMessagef(err, "failed to parse package signatures at '%s'", signaturesFile)
+		}
+	case !os.IsNotExist(errors.Cause(err)):
+		return errors.WithMessagef(err, "failed to read package signatures at '%s'", signaturesFile)
+	}
+
+	signer, err := s.Signer.Serialize()
+	if err != nil {
+		return errors.WithMessage(err, "failed to serialize signer")
+	}
+
+	signature, err := s.Signer.Sign(pkgBytes)
+	if err != nil {
+		return errors.WithMessage(err, "failed to sign chaincode package")
+	}
+
+	signatures.Signatures = append(signatures.Signatures, &persistence.PackageSignature{
+		Signer:    signer,
+		Signature: signature,
+	})
+	if signaturesBytes, err = signatures.Bytes(); err != nil {
+		return err
+	}
+
+	dir, name := filepath.Split(signaturesFile)
+	if dir, err = filepath.Abs(dir); err != nil {
+		return err
+	}
+	err = s.Writer.WriteFile(dir, name, signaturesBytes)
+	if err != nil {
+		return errors.Wrapf(err, "error writing package signatures to %s", signaturesFile)
+	}
+
+	return nil
+}
@@ -0,0 +1,243 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package chaincode_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hyperledger/fabric/core/chaincode/persistence"
+	"github.com/hyperledger/fabric/internal/peer/lifecycle/chaincode"
+	"github.com/hyperledger/fabric/internal/peer/lifecycle/chaincode/mock"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sign", func() {
+	Describe("PackageSigner", func() {
+		var (
+			mockReader    *mock.Reader
+			mockSigner    *mock.Signer
+			mockWriter    *mock.Writer
+			input         *chaincode.SignInput
+			packageSigner *chaincode.PackageSigner
+			pkgBytes      []byte
+		)
+
+		BeforeEach(func() {
+			input = &chaincode.SignInput{
+				PackageFile: "pkgFile",
+			}
+
+			var err error
+			pkgBytes, err = ioutil.ReadFile("testdata/good-package.tar.gz")
+			Expect(err).NotTo(HaveOccurred())
+			mockReader = &mock.Reader{}
+			mockReader.ReadFileStub = func(name string) ([]byte, error) {
+				if name == "pkgFile.signatures.json" {
+					return nil, os.ErrNotExist
+				}
+				return pkgBytes, nil
+			}
+
+			mockSigner = &mock.Signer{}
+			mockSigner.SerializeReturns([]byte("signer"), nil)
+			mockSigner.SignReturns([]byte("signature"), nil)
+
+			mockWriter = &mock.Writer{}
+
+			packageSigner = &chaincode.PackageSigner{
+				Input:  input,
+				Reader: mockReader,
+				Signer: mockSigner,
+				Writer: mockWriter,
+			}
+		})
+
+		It("signs the package bytes and stores the signature next to the package", func() {
+			err := packageSigner.Sign()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(mockReader.ReadFileArgsForCall(0)).To(Equal("pkgFile"))
+			Expect(mockSigner.SignCallCount()).To(Equal(1))
+			Expect(mockSigner.SignArgsForCall(0)).To(Equal(pkgBytes))
+
+			Expect(mockWriter.WriteFileCallCount()).To(Equal(1))
+			dir, name, signaturesBytes := mockWriter.WriteFileArgsForCall(0)
+			wd, err := filepath.Abs(".")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(dir).To(Equal(wd))
+			Expect(name).To(Equal("pkgFile.signatures.json"))
+
+			signatures, err := persistence.ParsePackageSignatures(signaturesBytes)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(signatures.Signatures).To(Equal([]*persistence.PackageSignature{
+				{Signer: []byte("signer"), Signature: []byte("signature")},
+			}))
+		})
+
+		Context("when the package is already signed", func() {
+			BeforeEach(func() {
+				mockReader.ReadFileStub = func(name string) ([]byte, error) {
+					if name == "pkgFile.signatures.json" {
+						return []byte(`{"signatures":[{"signer":"c2lnbmVyMQ==","signature":"c2lnbmF0dXJlMQ=="}]}`), nil
+					}
+					return pkgBytes, nil
+				}
+			})
+
+			It("adds the signature to the existing ones", func() {
+				err := packageSigner.Sign()
+				Expect(err).NotTo(HaveOccurred())
+
+				_, _, signaturesBytes := mockWriter.WriteFileArgsForCall(0)
+				signatures, err := persistence.ParsePackageSignatures(signaturesBytes)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(signatures.Signatures).To(Equal([]*persistence.PackageSignature{
+					{Signer: []byte("signer1"), Signature: []byte("signature1")},
+					{Signer: []byte("signer"), Signature: []byte("signature")},
+				}))
+			})
+		})
+
+		Context("when the existing signatures cannot be read", func() {
+			BeforeEach(func() {
+				mockReader.ReadFileStub = func(name string) ([]byte, error) {
+					if name == "pkgFile.signatures.json" {
+						return nil, errors.New("mocha")
+					}
+					return pkgBytes, nil
+				}
+			})
+
+			It("returns an error", func() {
+				err := packageSigner.Sign()
+				Expect(err).To(MatchError("failed to read package signatures at 'pkgFile.signatures.json': mocha"))
+			})
+		})
+
+		Context("when the existing signatures cannot be parsed", func() {
+			BeforeEach(func() {
+				mockReader.ReadFileStub = func(name string) ([]byte, error) {
+					if name == "pkgFile.signatures.json" {
+						return []byte("garbage"), nil
+					}
+					return pkgBytes, nil
+				}
+			})
+
+			It("returns an error", func() {
+				err := packageSigner.Sign()
+				Expect(err).To(MatchError(ContainSubstring("failed to parse package signatures at 'pkgFile.signatures.json'")))
+			})
+		})
+
+		Context("when the chaincode install package is not provided", func() {
+			BeforeEach(func() {
+				packageSigner.Input.PackageFile = ""
+			})
+
+			It("returns an error", func() {
+				err := packageSigner.Sign()
+				Expect(err).To(MatchError("chaincode install package must be provided"))
+			})
+		})
+
+		Context("when the package file cannot be read", func() {
+			BeforeEach(func() {
+				mockReader.ReadFileStub = nil
+				mockReader.ReadFileReturns(nil, errors.New("coffee"))
+			})
+
+			It("returns an error", func() {
+				err := packageSigner.Sign()
+				Expect(err).To(MatchError("failed to read chaincode package at 'pkgFile': coffee"))
+			})
+		})
+
+		Context("when the package is not a chaincode install package", func() {
+			BeforeEach(func() {
+				mockReader.ReadFileStub = nil
+				mockReader.ReadFileReturns([]byte("garbage"), nil)
+			})
+
+			It("returns an error", func() {
+				err := packageSigner.Sign()
+				Expect(err).To(MatchError(ContainSubstring("could not parse as a chaincode install package")))
+			})
+		})
+
+		Context("when the signer cannot be serialized", func() {
+			BeforeEach(func() {
+				mockSigner.SerializeReturns(nil, errors.New("cafe"))
+			})
+
+			It("returns an error", func() {
+				err := packageSigner.Sign()
+				Expect(err).To(MatchError("failed to serialize signer: cafe"))
+			})
+		})
+
+		Context("when signing fails", func() {
+			BeforeEach(func() {
+				mockSigner.SignReturns(nil, errors.New("tea"))
+			})
+
+			It("returns an error", func() {
+				err := packageSigner.Sign()
+				Expect(err).To(MatchError("failed to sign chaincode package: tea"))
+			})
+		})
+
+		Context("when the signatures cannot be written", func() {
+			BeforeEach(func() {
+				mockWriter.WriteFileReturns(errors.New("latte"))
+			})
+
+			It("returns an error", func() {
+				err := packageSigner.Sign()
+				Expect(err).To(MatchError("error writing package signatures to pkgFile.signatures.json: latte"))
+			})
+		})
+	})
+
+	Describe("SignCmd", func() {
+		var signCmd *cobra.Command
+
+		BeforeEach(func() {
+			mockReader := &mock.Reader{}
+			mockReader.ReadFileReturns([]byte("garbage"), nil)
+			packageSigner := &chaincode.PackageSigner{
+				Reader: mockReader,
+				Signer: &mock.Signer{},
+				Writer: &mock.Writer{},
+			}
+			signCmd = chaincode.SignCmd(packageSigner)
+			signCmd.SetArgs([]string{"testpkg"})
+		})
+
+		It("sets up the package signer and attempts to sign the package", func() {
+			err := signCmd.Execute()
+			Expect(err).To(MatchError(ContainSubstring("could not parse as a chaincode install package")))
+		})
+
+		Context("when more than one argument is provided", func() {
+			BeforeEach(func() {
+				signCmd.SetArgs([]string{"testpkg", "whatthe"})
+			})
+
+			It("returns an error", func() {
+				err := signCmd.Execute()
+				Expect(err).To(MatchError("invalid number of args. expected only the packaged chaincode file"))
+			})
+		})
+	})
+})
@@ -8,10 +8,16 @@ package node
 
 import (
 	"path/filepath"
+	"runtime"
 	"time"
 
+	"github.com/hyperledger/fabric/common/audit"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/viperutil"
 	coreconfig "github.com/hyperledger/fabric/core/config"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 )
 
@@ -41,11 +47,27 @@ func ledgerConfig() *ledger.Config {
 	if viper.IsSet("ledger.pvtdataStore.deprioritizedDataReconcilerInterval") {
 		deprioritizedDataReconcilerInterval = viper.GetDuration("ledger.pvtdataStore.deprioritizedDataReconcilerInterval")
 	}
+	parallelValidationWorkers := 0
+	if viper.GetBool("ledger.state.parallelValidation.enabled") {
+		parallelValidationWorkers = viper.GetInt("ledger.state.parallelValidation.workers")
+		if parallelValidationWorkers <= 0 {
+			parallelValidationWorkers = runtime.NumCPU()
+		}
+	}
 	purgedKeyAuditLogging := true
 	if viper.IsSet("ledger.pvtdataStore.purgedKeyAuditLogging") {
 		purgedKeyAuditLogging = viper.GetBool("ledger.pvtdataStore.purgedKeyAuditLogging")
 	}
 
+	reencryptionBatchSize := 1000
+	if viper.IsSet("ledger.pvtdataStore.encryption.reencryptionBatchSize") {
+		reencryptionBatchSize = viper.GetInt("ledger.pvtdataStore.encryption.reencryptionBatchSize")
+	}
+	reencryptionBatchesInterval := time.Second
+	if viper.IsSet("ledger.pvtdataStore.encryption.reencryptionBatchesInterval") {
+		reencryptionBatchesInterval = viper.GetDuration("ledger.pvtdataStore.encryption.reencryptionBatchesInterval")
+	}
+
 	fsPath := coreconfig.GetPath("peer.fileSystemPath")
 	ledgersDataRootDir := filepath.Join(fsPath, "ledgersData")
 	snapshotsRootDir := viper.GetString("ledger.snapshots.rootDir")
@@ -55,8 +77,9 @@ func ledgerConfig() *ledger.Config {
 	conf := &ledger.Config{
 		RootFSPath: ledgersDataRootDir,
 		StateDBConfig: &ledger.StateDBConfig{
-			StateDatabase: viper.GetString("ledger.state.stateDatabase"),
-			CouchDB:       &ledger.CouchDBConfig{},
+			StateDatabase:             viper.GetString("ledger.state.stateDatabase"),
+			CouchDB:                   &ledger.CouchDBConfig{},
+			ParallelValidationWorkers: parallelValidationWorkers,
 		},
 		PrivateDataConfig: &ledger.PrivateDataConfig{
 			MaxBatchSize:                        collElgProcMaxDbBatchSize,
@@ -64,10 +87,20 @@ func ledgerConfig() *ledger.Config {
 			PurgeInterval:                       purgeInterval,
 			DeprioritizedDataReconcilerInterval: deprioritizedDataReconcilerInterval,
 			PurgedKeyAuditLogging:               purgedKeyAuditLogging,
+			Encryption: &ledger.PrivateDataEncryptionConfig{
+				Enabled:                     viper.GetBool("ledger.pvtdataStore.encryption.enabled"),
+				Collections:                 viper.GetStringSlice("ledger.pvtdataStore.encryption.collections"),
+				ReencryptionBatchSize:       reencryptionBatchSize,
+				ReencryptionBatchesInterval: reencryptionBatchesInterval,
+			},
 		},
 		HistoryDBConfig: &ledger.HistoryDBConfig{
 			Enabled: viper.GetBool("ledger.history.enableHistoryDatabase"),
 		},
+		StateCommitmentConfig: &ledger.StateCommitmentConfig{
+			Enabled:      viper.GetBool("ledger.stateCommitment.enabled"),
+			RetainBlocks: uint64(viper.GetInt64("ledger.stateCommitment.retainBlocks")),
+		},
 		SnapshotsConfig: &ledger.SnapshotsConfig{
 			RootDir: snapshotsRootDir,
 		},
@@ -90,3 +123,82 @@ func ledgerConfig() *ledger.Config {
 	}
 	return conf
 }
+
+func auditConfig() audit.Config {
+	file := coreconfig.GetPath("audit.file")
+	if file == "" {
+		file = filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "audit", "audit.log")
+	}
+	return audit.Config{
+		Enabled:    viper.GetBool("audit.enabled"),
+		File:       file,
+		MaxSize:    viper.GetInt("audit.maxSize"),
+		MaxBackups: viper.GetInt("audit.maxBackups"),
+	}
+}
+
+// logSink is the configuration of a log sink in the logging.sinks section of core.yaml.
+type logSink struct {
+	Name             string        `yaml:"name"`
+	Type             string        `yaml:"type"`
+	Spec             string        `yaml:"spec"`
+	Format           string        `yaml:"format"`
+	Loggers          []string      `yaml:"loggers"`
+	File             string        `yaml:"file"`
+	MaxSize          int           `yaml:"maxSize"`
+	RotationInterval time.Duration `yaml:"rotationInterval"`
+	MaxBackups       int           `yaml:"maxBackups"`
+	Network          string        `yaml:"network"`
+	Address          string        `yaml:"address"`
+	Facility         string        `yaml:"facility"`
+	AppName          string        `yaml:"appName"`
+}
+
+// logSinksConfig returns the configuration of the log sinks of the peer,
+// where the paths of the log files are relative to the configuration file.
+func logSinksConfig() ([]flogging.SinkConfig, error) {
+	var sinks []logSink
+	err := viper.UnmarshalKey("logging.sinks", &sinks, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		viperutil.YamlStringToStructHook(sinks),
+		mapstructure.StringToTimeDurationHookFunc(),
+	)))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid logging.sinks configuration")
+	}
+
+	var configs []flogging.SinkConfig
+	for _, s := range sinks {
+		if s.File != "" {
+			s.File = coreconfig.TranslatePath(filepath.Dir(viper.ConfigFileUsed()), s.File)
+		}
+		configs = append(configs, flogging.SinkConfig{
+			Name:             s.Name,
+			Type:             s.Type,
+			Spec:             s.Spec,
+			Format:           s.Format,
+			Loggers:          s.Loggers,
+			File:             s.File,
+			MaxSize:          s.MaxSize,
+			RotationInterval: s.RotationInterval,
+			MaxBackups:       s.MaxBackups,
+			Network:          s.Network,
+			Address:          s.Address,
+			Facility:         s.Facility,
+			AppName:          s.AppName,
+		})
+	}
+	return configs, nil
+}
+
+// auditOffline records an action which is performed while the peer is offline,
+// and returns the error the action failed with.
+func auditOffline(action, channelID string, err error) error {
+	auditLogger, auditErr := audit.NewFileLogger(auditConfig())
+	if auditErr != nil {
+		logger.Errorf("Failed recording %s of channel %s in the audit log: %s", action, channelID, auditErr)
+		return err
+	}
+	defer auditLogger.Close()
+	auditLogger.Log("peer", action, audit.Caller{}, map[string]string{"channel": channelID}, err)
+	return err
+}
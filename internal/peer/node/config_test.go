@@ -7,9 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package node
 
 import (
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
@@ -40,10 +42,17 @@ func TestLedgerConfig(t *testing.T) {
 					PurgeInterval:                       100,
 					DeprioritizedDataReconcilerInterval: 60 * time.Minute,
 					PurgedKeyAuditLogging:               true,
+					Encryption: &ledger.PrivateDataEncryptionConfig{
+						ReencryptionBatchSize:       1000,
+						ReencryptionBatchesInterval: time.Second,
+					},
 				},
 				HistoryDBConfig: &ledger.HistoryDBConfig{
 					Enabled: false,
 				},
+				StateCommitmentConfig: &ledger.StateCommitmentConfig{
+					Enabled: false,
+				},
 				SnapshotsConfig: &ledger.SnapshotsConfig{
 					RootDir: "/peerfs/snapshots",
 				},
@@ -87,10 +96,17 @@ func TestLedgerConfig(t *testing.T) {
 					PurgeInterval:                       100,
 					DeprioritizedDataReconcilerInterval: 60 * time.Minute,
 					PurgedKeyAuditLogging:               true,
+					Encryption: &ledger.PrivateDataEncryptionConfig{
+						ReencryptionBatchSize:       1000,
+						ReencryptionBatchesInterval: time.Second,
+					},
 				},
 				HistoryDBConfig: &ledger.HistoryDBConfig{
 					Enabled: false,
 				},
+				StateCommitmentConfig: &ledger.StateCommitmentConfig{
+					Enabled: false,
+				},
 				SnapshotsConfig: &ledger.SnapshotsConfig{
 					RootDir: "/peerfs/snapshots",
 				},
@@ -99,25 +115,31 @@ func TestLedgerConfig(t *testing.T) {
 		{
 			name: "CouchDB Explicit",
 			config: map[string]interface{}{
-				"peer.fileSystemPath":                                     "/peerfs",
-				"ledger.state.stateDatabase":                              "CouchDB",
-				"ledger.state.couchDBConfig.couchDBAddress":               "localhost:5984",
-				"ledger.state.couchDBConfig.username":                     "username",
-				"ledger.state.couchDBConfig.password":                     "password",
-				"ledger.state.couchDBConfig.maxRetries":                   3,
-				"ledger.state.couchDBConfig.maxRetriesOnStartup":          10,
-				"ledger.state.couchDBConfig.requestTimeout":               "30s",
-				"ledger.state.couchDBConfig.internalQueryLimit":           500,
-				"ledger.state.couchDBConfig.maxBatchUpdateSize":           600,
-				"ledger.state.couchDBConfig.createGlobalChangesDB":        true,
-				"ledger.state.couchDBConfig.cacheSize":                    64,
-				"ledger.pvtdataStore.collElgProcMaxDbBatchSize":           50000,
-				"ledger.pvtdataStore.collElgProcDbBatchesInterval":        10000,
-				"ledger.pvtdataStore.purgeInterval":                       1000,
-				"ledger.pvtdataStore.purgedKeyAuditLogging":               false,
-				"ledger.pvtdataStore.deprioritizedDataReconcilerInterval": "180m",
-				"ledger.history.enableHistoryDatabase":                    true,
-				"ledger.snapshots.rootDir":                                "/peerfs/customLocationForsnapshots",
+				"peer.fileSystemPath":                                        "/peerfs",
+				"ledger.state.stateDatabase":                                 "CouchDB",
+				"ledger.state.couchDBConfig.couchDBAddress":                  "localhost:5984",
+				"ledger.state.couchDBConfig.username":                        "username",
+				"ledger.state.couchDBConfig.password":                        "password",
+				"ledger.state.couchDBConfig.maxRetries":                      3,
+				"ledger.state.couchDBConfig.maxRetriesOnStartup":             10,
+				"ledger.state.couchDBConfig.requestTimeout":                  "30s",
+				"ledger.state.couchDBConfig.internalQueryLimit":              500,
+				"ledger.state.couchDBConfig.maxBatchUpdateSize":              600,
+				"ledger.state.couchDBConfig.createGlobalChangesDB":           true,
+				"ledger.state.couchDBConfig.cacheSize":                       64,
+				"ledger.pvtdataStore.collElgProcMaxDbBatchSize":              50000,
+				"ledger.pvtdataStore.collElgProcDbBatchesInterval":           10000,
+				"ledger.pvtdataStore.purgeInterval":                          1000,
+				"ledger.pvtdataStore.purgedKeyAuditLogging":                  false,
+				"ledger.pvtdataStore.deprioritizedDataReconcilerInterval":    "180m",
+				"ledger.pvtdataStore.encryption.enabled":                     true,
+				"ledger.pvtdataStore.encryption.collections":                 []string{"ns1/coll1", "ns2/coll2"},
+				"ledger.pvtdataStore.encryption.reencryptionBatchSize":       100,
+				"ledger.pvtdataStore.encryption.reencryptionBatchesInterval": "5s",
+				"ledger.history.enableHistoryDatabase":                       true,
+				"ledger.stateCommitment.enabled":                             true,
+				"ledger.stateCommitment.retainBlocks":                        1000,
+				"ledger.snapshots.rootDir":                                   "/peerfs/customLocationForsnapshots",
 			},
 			expected: &ledger.Config{
 				RootFSPath: "/peerfs/ledgersData",
@@ -143,20 +165,68 @@ func TestLedgerConfig(t *testing.T) {
 					PurgeInterval:                       1000,
 					DeprioritizedDataReconcilerInterval: 180 * time.Minute,
 					PurgedKeyAuditLogging:               false,
+					Encryption: &ledger.PrivateDataEncryptionConfig{
+						Enabled:                     true,
+						Collections:                 []string{"ns1/coll1", "ns2/coll2"},
+						ReencryptionBatchSize:       100,
+						ReencryptionBatchesInterval: 5 * time.Second,
+					},
 				},
 				HistoryDBConfig: &ledger.HistoryDBConfig{
 					Enabled: true,
 				},
+				StateCommitmentConfig: &ledger.StateCommitmentConfig{
+					Enabled:      true,
+					RetainBlocks: 1000,
+				},
 				SnapshotsConfig: &ledger.SnapshotsConfig{
 					RootDir: "/peerfs/customLocationForsnapshots",
 				},
 			},
 		},
+		{
+			name: "Parallel validation",
+			config: map[string]interface{}{
+				"peer.fileSystemPath":                     "/peerfs",
+				"ledger.state.stateDatabase":              "goleveldb",
+				"ledger.state.parallelValidation.enabled": true,
+				"ledger.state.parallelValidation.workers": 4,
+			},
+			expected: &ledger.Config{
+				RootFSPath: "/peerfs/ledgersData",
+				StateDBConfig: &ledger.StateDBConfig{
+					StateDatabase:             "goleveldb",
+					CouchDB:                   &ledger.CouchDBConfig{},
+					ParallelValidationWorkers: 4,
+				},
+				PrivateDataConfig: &ledger.PrivateDataConfig{
+					MaxBatchSize:                        5000,
+					BatchesInterval:                     1000,
+					PurgeInterval:                       100,
+					DeprioritizedDataReconcilerInterval: 60 * time.Minute,
+					PurgedKeyAuditLogging:               true,
+					Encryption: &ledger.PrivateDataEncryptionConfig{
+						ReencryptionBatchSize:       1000,
+						ReencryptionBatchesInterval: time.Second,
+					},
+				},
+				HistoryDBConfig: &ledger.HistoryDBConfig{
+					Enabled: false,
+				},
+				StateCommitmentConfig: &ledger.StateCommitmentConfig{
+					Enabled: false,
+				},
+				SnapshotsConfig: &ledger.SnapshotsConfig{
+					RootDir: "/peerfs/snapshots",
+				},
+			},
+		},
 	}
 
 	for _, test := range tests {
 		_test := test
 		t.Run(_test.name, func(t *testing.T) {
+			viper.Reset()
 			for k, v := range _test.config {
 				viper.Set(k, v)
 			}
@@ -165,3 +235,67 @@ func TestLedgerConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestLogSinksConfig(t *testing.T) {
+	defer viper.Reset()
+
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+logging:
+    sinks:
+        - name: gossip
+          type: file
+          spec: debug
+          loggers: [gossip]
+          file: /var/log/peer/gossip.log
+          maxSize: 10
+          rotationInterval: 24h
+          maxBackups: 7
+        - name: syslog
+          type: syslog
+          format: json
+          network: udp
+          address: syslog.example.com:514
+          facility: local0
+          appName: peer0
+`))
+	require.NoError(t, err)
+
+	sinks, err := logSinksConfig()
+	require.NoError(t, err)
+	require.Equal(t, []flogging.SinkConfig{
+		{
+			Name:             "gossip",
+			Type:             "file",
+			Spec:             "debug",
+			Loggers:          []string{"gossip"},
+			File:             "/var/log/peer/gossip.log",
+			MaxSize:          10,
+			RotationInterval: 24 * time.Hour,
+			MaxBackups:       7,
+		},
+		{
+			Name:     "syslog",
+			Type:     "syslog",
+			Format:   "json",
+			Network:  "udp",
+			Address:  "syslog.example.com:514",
+			Facility: "local0",
+			AppName:  "peer0",
+		},
+	}, sinks)
+
+	t.Run("environment", func(t *testing.T) {
+		viper.Set("logging.sinks", "[{name: collector, type: remote, network: tcp, address: 127.0.0.1:5170, rotationInterval: 1h}]")
+		sinks, err := logSinksConfig()
+		require.NoError(t, err)
+		require.Equal(t, []flogging.SinkConfig{{Name: "collector", Type: "remote", Network: "tcp", Address: "127.0.0.1:5170", RotationInterval: time.Hour}}, sinks)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		viper.Reset()
+		sinks, err := logSinksConfig()
+		require.NoError(t, err)
+		require.Empty(t, sinks)
+	})
+}
@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode"
+	"github.com/hyperledger/fabric/core/config/dynamic"
+	"github.com/hyperledger/fabric/core/deliverservice"
+	"github.com/hyperledger/fabric/core/peer"
+	gossipprivdata "github.com/hyperledger/fabric/gossip/privdata"
+	gossipservice "github.com/hyperledger/fabric/gossip/service"
+	"github.com/hyperledger/fabric/internal/peer/common"
+	"github.com/hyperledger/fabric/internal/pkg/gateway"
+	"github.com/spf13/viper"
+)
+
+// The settings of the peer that can be changed at runtime.
+const (
+	gatewayEndorsementTimeoutKey     = "peer.gateway.endorsementTimeout"
+	gatewayBroadcastTimeoutKey       = "peer.gateway.broadcastTimeout"
+	chaincodeExecuteTimeoutKey       = "chaincode.executetimeout"
+	endorserConcurrencyKey           = "peer.limits.concurrency.endorserService"
+	deliverConcurrencyKey            = "peer.limits.concurrency.deliverService"
+	gatewayConcurrencyKey            = "peer.limits.concurrency.gatewayService"
+	reConnectBackoffThresholdKey     = "peer.deliveryclient.reConnectBackoffThreshold"
+	reconnectTotalTimeThresholdKey   = "peer.deliveryclient.reconnectTotalTimeThreshold"
+	pvtDataReconcileSleepIntervalKey = "peer.gossip.pvtData.reconcileSleepInterval"
+	pvtDataReconcileBatchSizeKey     = "peer.gossip.pvtData.reconcileBatchSize"
+	minDynamicDuration               = time.Second
+	minDynamicReconcileBatchSize     = 1
+	minDynamicConcurrencyLimit       = 0 // no limit
+)
+
+// newConfigRegistry takes a snapshot of the configuration of the peer, with
+// the values in effect of the settings that can be changed at runtime.
+func newConfigRegistry(
+	coreConfig *peer.Config,
+	chaincodeConfig *chaincode.Config,
+	deliverServiceConfig *deliverservice.DeliverServiceConfig,
+	privdataConfig *gossipprivdata.PrivdataConfig,
+) (*dynamic.Registry, error) {
+	return dynamic.NewRegistry(viper.GetViper(), common.CmdRoot,
+		dynamic.DurationSetting(gatewayEndorsementTimeoutKey, coreConfig.GatewayOptions.EndorsementTimeout, minDynamicDuration),
+		dynamic.DurationSetting(gatewayBroadcastTimeoutKey, coreConfig.GatewayOptions.BroadcastTimeout, minDynamicDuration),
+		dynamic.DurationSetting(chaincodeExecuteTimeoutKey, chaincodeConfig.ExecuteTimeout, minDynamicDuration),
+		dynamic.IntSetting(endorserConcurrencyKey, coreConfig.LimitsConcurrencyEndorserService, minDynamicConcurrencyLimit),
+		dynamic.IntSetting(deliverConcurrencyKey, coreConfig.LimitsConcurrencyDeliverService, minDynamicConcurrencyLimit),
+		dynamic.IntSetting(gatewayConcurrencyKey, coreConfig.LimitsConcurrencyGatewayService, minDynamicConcurrencyLimit),
+		dynamic.DurationSetting(reConnectBackoffThresholdKey, deliverServiceConfig.ReConnectBackoffThreshold, minDynamicDuration),
+		dynamic.DurationSetting(reconnectTotalTimeThresholdKey, deliverServiceConfig.ReconnectTotalTimeThreshold, minDynamicDuration),
+		dynamic.DurationSetting(pvtDataReconcileSleepIntervalKey, privdataConfig.ReconcileSleepInterval, minDynamicDuration),
+		dynamic.IntSetting(pvtDataReconcileBatchSizeKey, privdataConfig.ReconcileBatchSize, minDynamicReconcileBatchSize),
+	)
+}
+
+// dynamicComponents are the components of the peer that apply the settings
+// changed at runtime.
+type dynamicComponents struct {
+	grpcLimiter      *grpcLimiter
+	chaincodeSupport *chaincode.ChaincodeSupport
+	gatewayServer    *gateway.Server // nil when the gateway is not enabled
+	gossipService    *gossipservice.GossipService
+}
+
+// subscribeDynamicComponents subscribes the components to the changes of the
+// settings they use.
+func subscribeDynamicComponents(r *dynamic.Registry, c dynamicComponents) error {
+	// the retry thresholds of the delivery service, as well as the interval
+	// and the batch size of the reconciler, are applied together
+	setDeliverRetryThresholds := func() {
+		c.gossipService.SetDeliverRetryThresholds(
+			r.Value(reConnectBackoffThresholdKey).(time.Duration),
+			r.Value(reconnectTotalTimeThresholdKey).(time.Duration),
+		)
+	}
+	setReconcileConfig := func() {
+		c.gossipService.SetReconcileConfig(
+			r.Value(pvtDataReconcileSleepIntervalKey).(time.Duration),
+			r.Value(pvtDataReconcileBatchSizeKey).(int),
+		)
+	}
+
+	errs := []error{
+		r.SubscribeDuration(chaincodeExecuteTimeoutKey, c.chaincodeSupport.SetExecuteTimeout),
+		r.SubscribeInt(endorserConcurrencyKey, func(limit int) { c.grpcLimiter.setLimit(endorserServiceName, limit) }),
+		r.SubscribeInt(deliverConcurrencyKey, func(limit int) { c.grpcLimiter.setLimit(deliverServiceName, limit) }),
+		r.SubscribeInt(gatewayConcurrencyKey, func(limit int) { c.grpcLimiter.setLimit(gatewayServiceName, limit) }),
+		r.SubscribeDuration(reConnectBackoffThresholdKey, func(time.Duration) { setDeliverRetryThresholds() }),
+		r.SubscribeDuration(reconnectTotalTimeThresholdKey, func(time.Duration) { setDeliverRetryThresholds() }),
+		r.SubscribeDuration(pvtDataReconcileSleepIntervalKey, func(time.Duration) { setReconcileConfig() }),
+		r.SubscribeInt(pvtDataReconcileBatchSizeKey, func(int) { setReconcileConfig() }),
+	}
+	if c.gatewayServer != nil {
+		errs = append(errs,
+			r.SubscribeDuration(gatewayEndorsementTimeoutKey, c.gatewayServer.SetEndorsementTimeout),
+			r.SubscribeDuration(gatewayBroadcastTimeoutKey, c.gatewayServer.SetBroadcastTimeout),
+		)
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/chaincode"
+	"github.com/hyperledger/fabric/core/deliverservice"
+	"github.com/hyperledger/fabric/core/peer"
+	gossipprivdata "github.com/hyperledger/fabric/gossip/privdata"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDynamicConfig(t *testing.T) {
+	coreConfig := &peer.Config{LimitsConcurrencyEndorserService: 2500}
+	coreConfig.GatewayOptions.EndorsementTimeout = 30 * time.Second
+	registry, err := newConfigRegistry(
+		coreConfig,
+		&chaincode.Config{ExecuteTimeout: 30 * time.Second},
+		&deliverservice.DeliverServiceConfig{ReConnectBackoffThreshold: time.Hour, ReconnectTotalTimeThreshold: time.Hour},
+		&gossipprivdata.PrivdataConfig{ReconcileSleepInterval: time.Minute, ReconcileBatchSize: 10},
+	)
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, registry.Value(gatewayEndorsementTimeoutKey))
+	require.Equal(t, 2500, registry.Value(endorserConcurrencyKey))
+	require.Equal(t, 10, registry.Value(pvtDataReconcileBatchSizeKey))
+
+	limiter := newGrpcLimiter(initGrpcSemaphores(coreConfig))
+	err = subscribeDynamicComponents(registry, dynamicComponents{
+		grpcLimiter:      limiter,
+		chaincodeSupport: &chaincode.ChaincodeSupport{},
+	})
+	require.NoError(t, err)
+
+	err = registry.Update(map[string]string{
+		endorserConcurrencyKey:     "10",
+		gatewayConcurrencyKey:      "20",
+		chaincodeExecuteTimeoutKey: "1m",
+	})
+	require.NoError(t, err)
+	sema, ok := limiter.semaphore(endorserServiceName)
+	require.True(t, ok)
+	require.Equal(t, 10, cap(sema))
+	sema, ok = limiter.semaphore(gatewayServiceName)
+	require.True(t, ok)
+	require.Equal(t, 20, cap(sema))
+
+	err = registry.Update(map[string]string{gatewayEndorsementTimeoutKey: "10s"})
+	require.EqualError(t, err, "setting peer.gateway.endorsementTimeout is not used by this node")
+}
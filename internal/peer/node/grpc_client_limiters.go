@@ -0,0 +1,485 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	gp "github.com/hyperledger/fabric-protos-go/gateway"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var rejectedRequestsOpts = metrics.CounterOpts{
+	Namespace:    "grpc",
+	Subsystem:    "server",
+	Name:         "client_limit_rejected_requests",
+	Help:         "The number of requests rejected because a client exceeded its limits.",
+	LabelNames:   []string{"service", "msp", "scope", "limit"},
+	StatsdFormat: "%{#fqname}.%{service}.%{msp}.%{scope}.%{limit}",
+}
+
+// retryAfterKey is the trailer which holds the number of seconds after which
+// a rejected request may be retried.
+const retryAfterKey = "retry-after"
+
+// concurrencyRetryAfter is the retry delay suggested for the requests rejected
+// by a concurrency limit.
+const concurrencyRetryAfter = time.Second
+
+// minPruneThreshold is the number of tracked clients above which the idle
+// clients of a limiter are pruned.
+const minPruneThreshold = 1024
+
+// unknownMSP is the MSP label of the requests of the anonymous client
+const unknownMSP = "unknown"
+
+// client identifies the creator of a request
+type client struct {
+	mspID string
+	// id is the MSP ID together with the hash of the certificate of the
+	// creator
+	id string
+}
+
+// anonymousClient is the client of the requests whose creator cannot be
+// deserialized. They share the limits of a single client, so that a client
+// cannot escape its limits by sending requests with malformed creators or
+// creators of unknown MSPs.
+var anonymousClient = &client{}
+
+// creatorRequest is the creator of a request together with its channel
+type creatorRequest struct {
+	channelID string
+	creator   []byte
+}
+
+// rejection describes why a request is rejected
+type rejection struct {
+	scope      string
+	limit      string
+	retryAfter time.Duration
+}
+
+type clientState struct {
+	tokens     float64
+	lastRefill time.Time
+	inFlight   int
+}
+
+// clientLimiter enforces the same limits on the requests of each client
+type clientLimiter struct {
+	scope  string
+	limits peer.ClientLimits
+	burst  float64
+	now    func() time.Time
+
+	mutex          sync.Mutex
+	clients        map[string]*clientState
+	pruneThreshold int
+}
+
+func newClientLimiter(scope string, limits peer.ClientLimits) *clientLimiter {
+	if limits.Rate <= 0 && limits.Concurrency <= 0 {
+		return nil
+	}
+	burst := float64(limits.Burst)
+	if burst <= 0 {
+		burst = math.Max(1, math.Ceil(limits.Rate))
+	}
+	return &clientLimiter{
+		scope:          scope,
+		limits:         limits,
+		burst:          burst,
+		now:            time.Now,
+		clients:        map[string]*clientState{},
+		pruneThreshold: minPruneThreshold,
+	}
+}
+
+// admit accounts for a request of the client when the limits allow it. A
+// concurrent request occupies a concurrency slot until it is released.
+func (l *clientLimiter) admit(key string, concurrent bool) *rejection {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := l.now()
+	state, ok := l.clients[key]
+	if !ok {
+		if len(l.clients) >= l.pruneThreshold {
+			l.prune(now)
+		}
+		state = &clientState{tokens: l.burst, lastRefill: now}
+		l.clients[key] = state
+	}
+
+	if concurrent && l.limits.Concurrency > 0 && state.inFlight >= l.limits.Concurrency {
+		return &rejection{scope: l.scope, limit: "concurrency", retryAfter: concurrencyRetryAfter}
+	}
+	if l.limits.Rate > 0 {
+		l.refill(state, now)
+		if state.tokens < 1 {
+			retryAfter := time.Duration((1 - state.tokens) / l.limits.Rate * float64(time.Second))
+			return &rejection{scope: l.scope, limit: "rate", retryAfter: retryAfter}
+		}
+		state.tokens--
+	}
+	if concurrent {
+		state.inFlight++
+	}
+	return nil
+}
+
+// cancel reverts the admission of a request which has been rejected by
+// another limiter.
+func (l *clientLimiter) cancel(key string, concurrent bool) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	state, ok := l.clients[key]
+	if !ok {
+		return
+	}
+	if l.limits.Rate > 0 {
+		state.tokens = math.Min(l.burst, state.tokens+1)
+	}
+	if concurrent && state.inFlight > 0 {
+		state.inFlight--
+	}
+}
+
+// release frees the concurrency slot of a completed concurrent request
+func (l *clientLimiter) release(key string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if state, ok := l.clients[key]; ok && state.inFlight > 0 {
+		state.inFlight--
+	}
+}
+
+func (l *clientLimiter) refill(state *clientState, now time.Time) {
+	elapsed := now.Sub(state.lastRefill).Seconds()
+	if elapsed > 0 {
+		state.tokens = math.Min(l.burst, state.tokens+elapsed*l.limits.Rate)
+		state.lastRefill = now
+	}
+}
+
+// prune forgets the clients without requests in flight whose rate budget is
+// entirely replenished, as their state is the one of a new client.
+func (l *clientLimiter) prune(now time.Time) {
+	for key, state := range l.clients {
+		if state.inFlight > 0 {
+			continue
+		}
+		if l.limits.Rate > 0 {
+			l.refill(state, now)
+			if state.tokens < l.burst {
+				continue
+			}
+		}
+		delete(l.clients, key)
+	}
+	l.pruneThreshold = len(l.clients) * 2
+	if l.pruneThreshold < minPruneThreshold {
+		l.pruneThreshold = minPruneThreshold
+	}
+}
+
+// serviceLimiters holds the limiters of a service
+type serviceLimiters struct {
+	identity *clientLimiter
+	msp      *clientLimiter
+}
+
+func (s *serviceLimiters) admit(c *client, concurrent bool) *rejection {
+	if s.identity != nil {
+		if r := s.identity.admit(c.id, concurrent); r != nil {
+			return r
+		}
+	}
+	if s.msp != nil {
+		if r := s.msp.admit(c.mspID, concurrent); r != nil {
+			if s.identity != nil {
+				s.identity.cancel(c.id, concurrent)
+			}
+			return r
+		}
+	}
+	return nil
+}
+
+func (s *serviceLimiters) release(c *client) {
+	if s.identity != nil {
+		s.identity.release(c.id)
+	}
+	if s.msp != nil {
+		s.msp.release(c.mspID)
+	}
+}
+
+// clientLimiters enforces the limits of each client identity and of each MSP
+// on the requests to the endorser, deliver and gateway services. A request is
+// accounted to its creator as deserialized by the MSPs of the channel of the
+// request, or by the local MSP for the requests without a channel. The
+// signature of the creator is not verified here: the limits are only meant to
+// keep the peer from doing that work, and the services verify it themselves.
+type clientLimiters struct {
+	services         map[string]*serviceLimiters
+	rejectedRequests metrics.Counter
+
+	// deserializer returns the identity deserializer of a channel, or nil
+	// when the peer has not joined the channel
+	deserializer func(channelID string) msp.IdentityDeserializer
+}
+
+func initGrpcClientLimiters(config *peer.Config, metricsProvider metrics.Provider) *clientLimiters {
+	limits := map[string][2]peer.ClientLimits{
+		"/protos.Endorser": {config.LimitsIdentityEndorserService, config.LimitsMSPEndorserService},
+		"/protos.Deliver":  {config.LimitsIdentityDeliverService, config.LimitsMSPDeliverService},
+		"/gateway.Gateway": {config.LimitsIdentityGatewayService, config.LimitsMSPGatewayService},
+	}
+
+	services := map[string]*serviceLimiters{}
+	for serviceName, l := range limits {
+		s := &serviceLimiters{
+			identity: newClientLimiter("identity", l[0]),
+			msp:      newClientLimiter("msp", l[1]),
+		}
+		if s.identity == nil && s.msp == nil {
+			continue
+		}
+		logger.Infof("client limits for %s are %+v per identity and %+v per MSP", serviceName, l[0], l[1])
+		services[serviceName] = s
+	}
+	if len(services) == 0 {
+		return nil
+	}
+
+	return &clientLimiters{
+		services:         services,
+		rejectedRequests: metricsProvider.NewCounter(rejectedRequestsOpts),
+	}
+}
+
+// client returns the creator of the request, or the anonymous client when it
+// cannot be deserialized.
+func (l *clientLimiters) client(req interface{}) *client {
+	c, err := l.creatorClient(req)
+	if err != nil {
+		logger.Debugf("Client limits of the anonymous client applied to request: %s", err)
+		return anonymousClient
+	}
+	return c
+}
+
+func (l *clientLimiters) creatorClient(req interface{}) (*client, error) {
+	cr, err := requestCreator(req)
+	if err != nil {
+		return nil, err
+	}
+	var deserializer msp.IdentityDeserializer
+	if l.deserializer != nil {
+		deserializer = l.deserializer(cr.channelID)
+	}
+	if deserializer == nil {
+		return nil, errors.Errorf("no identity deserializer for channel '%s'", cr.channelID)
+	}
+	identity, err := deserializer.DeserializeIdentity(cr.creator)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error deserializing creator")
+	}
+	identifier := identity.GetIdentifier()
+	if identifier == nil {
+		return nil, errors.New("creator has no identifier")
+	}
+	return &client{
+		mspID: identifier.Mspid,
+		id:    identifier.Mspid + "/" + identifier.Id,
+	}, nil
+}
+
+func (l *clientLimiters) rejectionError(serviceName string, c *client, r *rejection) error {
+	mspLabel := c.mspID
+	if c == anonymousClient {
+		mspLabel = unknownMSP
+	}
+	l.rejectedRequests.With("service", serviceName, "msp", mspLabel, "scope", r.scope, "limit", r.limit).Add(1)
+	logger.Warningf("Too many requests from a client of %s for %s, exceeding the %s limit per %s", mspLabel, serviceName, r.limit, r.scope)
+	return status.Errorf(codes.ResourceExhausted, "too many requests for %s, exceeding the %s limit per %s", serviceName, r.limit, r.scope)
+}
+
+func retryAfterTrailer(r *rejection) metadata.MD {
+	seconds := int64(math.Ceil(r.retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return metadata.Pairs(retryAfterKey, strconv.FormatInt(seconds, 10))
+}
+
+func unaryGrpcClientLimiter(limiters *clientLimiters) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		serviceName := getServiceName(info.FullMethod)
+		s, ok := limiters.services[serviceName]
+		if !ok {
+			return handler(ctx, req)
+		}
+		c := limiters.client(req)
+		if r := s.admit(c, true); r != nil {
+			grpc.SetTrailer(ctx, retryAfterTrailer(r))
+			return nil, limiters.rejectionError(serviceName, c, r)
+		}
+		defer s.release(c)
+		return handler(ctx, req)
+	}
+}
+
+func streamGrpcClientLimiter(limiters *clientLimiters) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		serviceName := getServiceName(info.FullMethod)
+		s, ok := limiters.services[serviceName]
+		if !ok {
+			return handler(srv, ss)
+		}
+		ls := &limitedServerStream{
+			ServerStream: ss,
+			serviceName:  serviceName,
+			service:      s,
+			limiters:     limiters,
+		}
+		defer ls.release()
+		return handler(srv, ls)
+	}
+}
+
+// limitedServerStream applies the client limits to the messages received on a
+// stream. The stream occupies a concurrency slot of the client which sent the
+// first message, the anonymous client when it cannot be deserialized; every
+// message counts towards the rate of its client.
+type limitedServerStream struct {
+	grpc.ServerStream
+	serviceName string
+	service     *serviceLimiters
+	limiters    *clientLimiters
+
+	admitted *client
+	received bool
+}
+
+func (ls *limitedServerStream) RecvMsg(m interface{}) error {
+	if err := ls.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	first := !ls.received
+	ls.received = true
+
+	c := ls.limiters.client(m)
+	if r := ls.service.admit(c, first); r != nil {
+		ls.SetTrailer(retryAfterTrailer(r))
+		return ls.limiters.rejectionError(ls.serviceName, c, r)
+	}
+	if first {
+		ls.admitted = c
+	}
+	return nil
+}
+
+func (ls *limitedServerStream) release() {
+	if ls.admitted != nil {
+		ls.service.release(ls.admitted)
+	}
+}
+
+// requestCreator returns the creator and the channel of the request.
+func requestCreator(req interface{}) (*creatorRequest, error) {
+	switch r := req.(type) {
+	case *pb.SignedProposal:
+		return signedProposalCreator(r)
+	case *gp.EvaluateRequest:
+		return signedProposalCreator(r.ProposedTransaction)
+	case *gp.EndorseRequest:
+		return signedProposalCreator(r.ProposedTransaction)
+	case *gp.SubmitRequest:
+		return envelopeCreator(r.PreparedTransaction)
+	case *gp.SignedCommitStatusRequest:
+		request := &gp.CommitStatusRequest{}
+		if err := proto.Unmarshal(r.Request, request); err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling commit status request")
+		}
+		return &creatorRequest{channelID: request.ChannelId, creator: request.Identity}, nil
+	case *gp.SignedChaincodeEventsRequest:
+		request := &gp.ChaincodeEventsRequest{}
+		if err := proto.Unmarshal(r.Request, request); err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling chaincode events request")
+		}
+		return &creatorRequest{channelID: request.ChannelId, creator: request.Identity}, nil
+	case *cb.Envelope:
+		return envelopeCreator(r)
+	default:
+		return nil, errors.Errorf("unsupported request type %T", req)
+	}
+}
+
+func signedProposalCreator(signedProposal *pb.SignedProposal) (*creatorRequest, error) {
+	if signedProposal == nil {
+		return nil, errors.New("missing signed proposal")
+	}
+	proposal, err := protoutil.UnmarshalProposal(signedProposal.ProposalBytes)
+	if err != nil {
+		return nil, err
+	}
+	header, err := protoutil.UnmarshalHeader(proposal.Header)
+	if err != nil {
+		return nil, err
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	shdr, err := protoutil.UnmarshalSignatureHeader(header.SignatureHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &creatorRequest{channelID: chdr.ChannelId, creator: shdr.Creator}, nil
+}
+
+func envelopeCreator(env *cb.Envelope) (*creatorRequest, error) {
+	if env == nil {
+		return nil, errors.New("missing envelope")
+	}
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("missing header")
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	shdr, err := protoutil.UnmarshalSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return nil, err
+	}
+	return &creatorRequest{channelID: chdr.ChannelId, creator: shdr.Creator}, nil
+}
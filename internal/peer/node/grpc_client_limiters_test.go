@@ -0,0 +1,375 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	gp "github.com/hyperledger/fabric-protos-go/gateway"
+	mspproto "github.com/hyperledger/fabric-protos-go/msp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/metrics/disabled"
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/internal/peer/node/mock"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+//go:generate counterfeiter -o mock/identity_deserializer.go -fake-name IdentityDeserializer . identityDeserializer
+type identityDeserializer interface {
+	msp.IdentityDeserializer
+}
+
+//go:generate counterfeiter -o mock/identity.go -fake-name Identity . identity
+type identity interface {
+	msp.Identity
+}
+
+func serializedIdentity(mspID, id string) []byte {
+	return protoutil.MarshalOrPanic(&mspproto.SerializedIdentity{Mspid: mspID, IdBytes: []byte(id)})
+}
+
+func signedProposal(creator []byte) *pb.SignedProposal {
+	header := protoutil.MarshalOrPanic(&cb.Header{
+		ChannelHeader:   protoutil.MarshalOrPanic(&cb.ChannelHeader{ChannelId: "mychannel"}),
+		SignatureHeader: protoutil.MarshalOrPanic(&cb.SignatureHeader{Creator: creator}),
+	})
+	return &pb.SignedProposal{
+		ProposalBytes: protoutil.MarshalOrPanic(&pb.Proposal{Header: header}),
+		Signature:     []byte("signature"),
+	}
+}
+
+func envelope(creator []byte) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader:   protoutil.MarshalOrPanic(&cb.ChannelHeader{ChannelId: "mychannel"}),
+				SignatureHeader: protoutil.MarshalOrPanic(&cb.SignatureHeader{Creator: creator}),
+			},
+		}),
+		Signature: []byte("signature"),
+	}
+}
+
+// channelDeserializer deserializes the identities of the known MSPs of
+// mychannel
+func channelDeserializer(channelID string) msp.IdentityDeserializer {
+	if channelID != "mychannel" {
+		return nil
+	}
+	deserializer := &mock.IdentityDeserializer{}
+	deserializer.DeserializeIdentityStub = func(serializedIdentity []byte) (msp.Identity, error) {
+		sid := &mspproto.SerializedIdentity{}
+		if err := proto.Unmarshal(serializedIdentity, sid); err != nil {
+			return nil, err
+		}
+		if sid.Mspid != "Org1MSP" {
+			return nil, errors.Errorf("unknown MSP %s", sid.Mspid)
+		}
+		identity := &mock.Identity{}
+		identity.GetIdentifierReturns(&msp.IdentityIdentifier{Mspid: sid.Mspid, Id: string(sid.IdBytes)})
+		return identity, nil
+	}
+	return deserializer
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time { return c.now }
+
+func TestInitGrpcClientLimiters(t *testing.T) {
+	require.Nil(t, initGrpcClientLimiters(&peer.Config{}, &disabled.Provider{}))
+
+	limiters := initGrpcClientLimiters(&peer.Config{
+		LimitsIdentityEndorserService: peer.ClientLimits{Rate: 10},
+		LimitsMSPEndorserService:      peer.ClientLimits{Concurrency: 10},
+		LimitsMSPGatewayService:       peer.ClientLimits{Rate: 5, Burst: 10},
+	}, &disabled.Provider{})
+	require.Len(t, limiters.services, 2)
+	require.NotNil(t, limiters.services["/protos.Endorser"].identity)
+	require.NotNil(t, limiters.services["/protos.Endorser"].msp)
+	require.Nil(t, limiters.services["/gateway.Gateway"].identity)
+	require.Equal(t, float64(10), limiters.services["/gateway.Gateway"].msp.burst)
+}
+
+func TestClientLimiterRate(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	l := newClientLimiter("identity", peer.ClientLimits{Rate: 2})
+	l.now = clock.Now
+
+	require.Nil(t, l.admit("client1", true))
+	require.Nil(t, l.admit("client1", true))
+	r := l.admit("client1", true)
+	require.Equal(t, &rejection{scope: "identity", limit: "rate", retryAfter: 500 * time.Millisecond}, r)
+	require.Nil(t, l.admit("client2", true))
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	require.Nil(t, l.admit("client1", false))
+	require.NotNil(t, l.admit("client1", false))
+
+	l.cancel("client1", false)
+	require.Nil(t, l.admit("client1", false))
+}
+
+func TestClientLimiterConcurrency(t *testing.T) {
+	l := newClientLimiter("msp", peer.ClientLimits{Concurrency: 2})
+
+	require.Nil(t, l.admit("Org1MSP", true))
+	require.Nil(t, l.admit("Org1MSP", true))
+	r := l.admit("Org1MSP", true)
+	require.Equal(t, &rejection{scope: "msp", limit: "concurrency", retryAfter: time.Second}, r)
+	require.Nil(t, l.admit("Org1MSP", false))
+	require.Nil(t, l.admit("Org2MSP", true))
+
+	l.release("Org1MSP")
+	require.Nil(t, l.admit("Org1MSP", true))
+	l.cancel("Org1MSP", true)
+	require.Nil(t, l.admit("Org1MSP", true))
+}
+
+func TestClientLimiterPrune(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	l := newClientLimiter("identity", peer.ClientLimits{Rate: 1, Concurrency: 1})
+	l.now = clock.Now
+	l.pruneThreshold = 3
+
+	require.Nil(t, l.admit("client1", true))
+	require.Nil(t, l.admit("client2", false))
+	require.Nil(t, l.admit("client3", false))
+
+	clock.now = clock.now.Add(time.Second)
+	require.Nil(t, l.admit("client4", false))
+	require.Len(t, l.clients, 2)
+	require.Contains(t, l.clients, "client1")
+	require.Contains(t, l.clients, "client4")
+	require.Equal(t, minPruneThreshold, l.pruneThreshold)
+}
+
+func TestClientLimitersClient(t *testing.T) {
+	limiters := initGrpcClientLimiters(&peer.Config{
+		LimitsIdentityEndorserService: peer.ClientLimits{Rate: 10},
+	}, &disabled.Provider{})
+	limiters.deserializer = channelDeserializer
+
+	creator := serializedIdentity("Org1MSP", "cert")
+	commitStatusRequest := protoutil.MarshalOrPanic(&gp.CommitStatusRequest{ChannelId: "mychannel", Identity: creator})
+	chaincodeEventsRequest := protoutil.MarshalOrPanic(&gp.ChaincodeEventsRequest{ChannelId: "mychannel", Identity: creator})
+	requests := []interface{}{
+		signedProposal(creator),
+		&gp.EvaluateRequest{ProposedTransaction: signedProposal(creator)},
+		&gp.EndorseRequest{ProposedTransaction: signedProposal(creator)},
+		&gp.SubmitRequest{PreparedTransaction: envelope(creator)},
+		&gp.SignedCommitStatusRequest{Request: commitStatusRequest, Signature: []byte("signature")},
+		&gp.SignedChaincodeEventsRequest{Request: chaincodeEventsRequest, Signature: []byte("signature")},
+		envelope(creator),
+	}
+	for _, req := range requests {
+		c := limiters.client(req)
+		require.Equal(t, "Org1MSP", c.mspID)
+		require.NotEmpty(t, c.id)
+	}
+
+	c1 := limiters.client(envelope(serializedIdentity("Org1MSP", "cert1")))
+	c2 := limiters.client(envelope(serializedIdentity("Org1MSP", "cert2")))
+	require.Equal(t, "Org1MSP/cert1", c1.id)
+	require.NotEqual(t, c1.id, c2.id)
+
+	// the signature is left to the services to verify
+	unsigned := envelope(creator)
+	unsigned.Signature = nil
+	require.Equal(t, &client{mspID: "Org1MSP", id: "Org1MSP/cert"}, limiters.client(unsigned))
+
+	// the requests whose creator cannot be deserialized are anonymous
+	unknownMSP := envelope(serializedIdentity("Org2MSP", "cert"))
+	otherChannel := &gp.SignedCommitStatusRequest{
+		Request:   protoutil.MarshalOrPanic(&gp.CommitStatusRequest{ChannelId: "otherchannel", Identity: creator}),
+		Signature: []byte("signature"),
+	}
+	for _, req := range []interface{}{
+		unknownMSP,
+		otherChannel,
+		&gp.EvaluateRequest{},
+		&cb.Envelope{Payload: []byte("garbage")},
+		"request",
+	} {
+		require.Same(t, anonymousClient, limiters.client(req))
+	}
+
+	_, err := limiters.creatorClient(unknownMSP)
+	require.EqualError(t, err, "error deserializing creator: unknown MSP Org2MSP")
+	_, err = limiters.creatorClient(otherChannel)
+	require.EqualError(t, err, "no identity deserializer for channel 'otherchannel'")
+	_, err = limiters.creatorClient(&gp.EvaluateRequest{})
+	require.EqualError(t, err, "missing signed proposal")
+	_, err = limiters.creatorClient("request")
+	require.EqualError(t, err, "unsupported request type string")
+}
+
+func TestUnaryGrpcClientLimiter(t *testing.T) {
+	rejected := &metricsfakes.Counter{}
+	rejected.WithReturns(rejected)
+	provider := &metricsfakes.Provider{}
+	provider.NewCounterReturns(rejected)
+
+	limiters := initGrpcClientLimiters(&peer.Config{
+		LimitsIdentityEndorserService: peer.ClientLimits{Concurrency: 1},
+		LimitsMSPEndorserService:      peer.ClientLimits{Concurrency: 2},
+	}, provider)
+	limiters.deserializer = channelDeserializer
+	interceptor := unaryGrpcClientLimiter(limiters)
+	info := &grpc.UnaryServerInfo{FullMethod: "/protos.Endorser/ProcessProposal"}
+
+	client1 := signedProposal(serializedIdentity("Org1MSP", "client1"))
+	client2 := signedProposal(serializedIdentity("Org1MSP", "client2"))
+	client3 := signedProposal(serializedIdentity("Org1MSP", "client3"))
+
+	var nested []error
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		if req == client1 {
+			// while client1 is being served, client1 exceeds the identity limit and,
+			// once client2 is being served, client3 exceeds the MSP limit
+			_, err := interceptor(ctx, client1, info, nil)
+			nested = append(nested, err)
+			_, err = interceptor(ctx, client2, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				_, err := interceptor(ctx, client3, info, nil)
+				nested = append(nested, err)
+				return nil, nil
+			})
+			nested = append(nested, err)
+		}
+		return "response", nil
+	}
+
+	resp, err := interceptor(context.Background(), client1, info, handler)
+	require.NoError(t, err)
+	require.Equal(t, "response", resp)
+	require.Len(t, nested, 3)
+	require.Equal(t, codes.ResourceExhausted, status.Code(nested[0]))
+	require.EqualError(t, nested[0], "rpc error: code = ResourceExhausted desc = too many requests for /protos.Endorser, exceeding the concurrency limit per identity")
+	require.EqualError(t, nested[1], "rpc error: code = ResourceExhausted desc = too many requests for /protos.Endorser, exceeding the concurrency limit per msp")
+	require.NoError(t, nested[2])
+
+	require.Equal(t, 2, rejected.AddCallCount())
+	require.Equal(t, []string{"service", "/protos.Endorser", "msp", "Org1MSP", "scope", "identity", "limit", "concurrency"}, rejected.WithArgsForCall(0))
+	require.Equal(t, []string{"service", "/protos.Endorser", "msp", "Org1MSP", "scope", "msp", "limit", "concurrency"}, rejected.WithArgsForCall(1))
+
+	// the concurrency slots are released once the requests complete
+	noop := func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil }
+	for _, client := range []*pb.SignedProposal{client1, client2, client3} {
+		_, err = interceptor(context.Background(), client, info, noop)
+		require.NoError(t, err)
+	}
+
+	// requests to other services are not limited
+	_, err = interceptor(context.Background(), client1, &grpc.UnaryServerInfo{FullMethod: "/discovery.Discovery/Discover"}, noop)
+	require.NoError(t, err)
+
+	// while requests whose creator cannot be deserialized share the limits
+	// of the anonymous client
+	unknown := signedProposal(serializedIdentity("Org2MSP", "client1"))
+	_, err = interceptor(context.Background(), unknown, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		_, err := interceptor(ctx, &pb.SignedProposal{}, info, noop)
+		nested = append(nested, err)
+		return interceptor(ctx, client1, info, noop)
+	})
+	require.NoError(t, err)
+	require.EqualError(t, nested[3], "rpc error: code = ResourceExhausted desc = too many requests for /protos.Endorser, exceeding the concurrency limit per identity")
+	require.Equal(t, []string{"service", "/protos.Endorser", "msp", "unknown", "scope", "identity", "limit", "concurrency"}, rejected.WithArgsForCall(2))
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	messages []*cb.Envelope
+	trailer  metadata.MD
+}
+
+func (f *fakeServerStream) RecvMsg(m interface{}) error {
+	*m.(*cb.Envelope) = *f.messages[0]
+	f.messages = f.messages[1:]
+	return nil
+}
+
+func (f *fakeServerStream) SetTrailer(md metadata.MD) {
+	f.trailer = md
+}
+
+func TestStreamGrpcClientLimiter(t *testing.T) {
+	limiters := initGrpcClientLimiters(&peer.Config{
+		LimitsIdentityDeliverService: peer.ClientLimits{Rate: 2, Concurrency: 1},
+	}, &disabled.Provider{})
+	limiters.deserializer = channelDeserializer
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	limiters.services["/protos.Deliver"].identity.now = clock.Now
+	interceptor := streamGrpcClientLimiter(limiters)
+	info := &grpc.StreamServerInfo{FullMethod: "/protos.Deliver/Deliver"}
+
+	client1 := envelope(serializedIdentity("Org1MSP", "client1"))
+	client2 := envelope(serializedIdentity("Org1MSP", "client2"))
+
+	stream := &fakeServerStream{messages: []*cb.Envelope{client1, client1, client1}}
+	var errs []error
+	err := interceptor(nil, stream, info, func(srv interface{}, ss grpc.ServerStream) error {
+		for i := 0; i < 3; i++ {
+			errs = append(errs, ss.RecvMsg(&cb.Envelope{}))
+		}
+
+		// another stream of the same client exceeds the concurrency limit
+		other := &fakeServerStream{messages: []*cb.Envelope{client1}}
+		err := interceptor(nil, other, info, func(srv interface{}, ss grpc.ServerStream) error {
+			return ss.RecvMsg(&cb.Envelope{})
+		})
+		require.EqualError(t, err, "rpc error: code = ResourceExhausted desc = too many requests for /protos.Deliver, exceeding the concurrency limit per identity")
+		require.Equal(t, metadata.Pairs("retry-after", "1"), other.trailer)
+
+		// while another client is not limited
+		other = &fakeServerStream{messages: []*cb.Envelope{client2}}
+		err = interceptor(nil, other, info, func(srv interface{}, ss grpc.ServerStream) error {
+			return ss.RecvMsg(&cb.Envelope{})
+		})
+		require.NoError(t, err)
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, errs[0])
+	require.NoError(t, errs[1])
+	require.EqualError(t, errs[2], "rpc error: code = ResourceExhausted desc = too many requests for /protos.Deliver, exceeding the rate limit per identity")
+	require.Equal(t, metadata.Pairs("retry-after", "1"), stream.trailer)
+
+	// the concurrency slot is released once the stream completes
+	clock.now = clock.now.Add(time.Second)
+	stream = &fakeServerStream{messages: []*cb.Envelope{client1}}
+	err = interceptor(nil, stream, info, func(srv interface{}, ss grpc.ServerStream) error {
+		return ss.RecvMsg(&cb.Envelope{})
+	})
+	require.NoError(t, err)
+
+	// a stream whose first message cannot be deserialized occupies the
+	// concurrency slot of the anonymous client
+	stream = &fakeServerStream{messages: []*cb.Envelope{{Payload: []byte("garbage")}}}
+	err = interceptor(nil, stream, info, func(srv interface{}, ss grpc.ServerStream) error {
+		require.NoError(t, ss.RecvMsg(&cb.Envelope{}))
+		other := &fakeServerStream{messages: []*cb.Envelope{{Payload: []byte("garbage")}}}
+		return interceptor(nil, other, info, func(srv interface{}, ss grpc.ServerStream) error {
+			return ss.RecvMsg(&cb.Envelope{})
+		})
+	})
+	require.EqualError(t, err, "rpc error: code = ResourceExhausted desc = too many requests for /protos.Deliver, exceeding the concurrency limit per identity")
+}
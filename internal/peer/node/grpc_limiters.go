@@ -9,6 +9,7 @@ package node
 import (
 	"context"
 	"strings"
+	"sync"
 
 	"github.com/hyperledger/fabric/common/semaphore"
 	"github.com/hyperledger/fabric/core/peer"
@@ -16,61 +17,109 @@ import (
 	"google.golang.org/grpc"
 )
 
+// Currently concurrency limit is applied to endorser service, deliver service and gateway service.
+// These services are defined in fabric-protos and fabric-protos-go (generated from fabric-protos).
+// Below service names must match their definitions.
+const (
+	endorserServiceName = "/protos.Endorser"
+	deliverServiceName  = "/protos.Deliver"
+	gatewayServiceName  = "/gateway.Gateway"
+)
+
 func initGrpcSemaphores(config *peer.Config) map[string]semaphore.Semaphore {
 	semaphores := make(map[string]semaphore.Semaphore)
 	endorserConcurrency := config.LimitsConcurrencyEndorserService
 	deliverConcurrency := config.LimitsConcurrencyDeliverService
 	gatewayConcurrency := config.LimitsConcurrencyGatewayService
 
-	// Currently concurrency limit is applied to endorser service, deliver service and gateway service.
-	// These services are defined in fabric-protos and fabric-protos-go (generated from fabric-protos).
-	// Below service names must match their definitions.
 	if endorserConcurrency != 0 {
 		logger.Infof("concurrency limit for endorser service is %d", endorserConcurrency)
-		semaphores["/protos.Endorser"] = semaphore.New(endorserConcurrency)
+		semaphores[endorserServiceName] = semaphore.New(endorserConcurrency)
 	}
 	if deliverConcurrency != 0 {
 		logger.Infof("concurrency limit for deliver service is %d", deliverConcurrency)
-		semaphores["/protos.Deliver"] = semaphore.New(deliverConcurrency)
+		semaphores[deliverServiceName] = semaphore.New(deliverConcurrency)
 	}
 	if gatewayConcurrency != 0 {
 		logger.Infof("concurrency limit for gateway service is %d", gatewayConcurrency)
-		semaphores["/gateway.Gateway"] = semaphore.New(gatewayConcurrency)
+		semaphores[gatewayServiceName] = semaphore.New(gatewayConcurrency)
 	}
 
 	return semaphores
 }
 
 func unaryGrpcLimiter(semaphores map[string]semaphore.Semaphore) grpc.UnaryServerInterceptor {
-	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		serviceName := getServiceName(info.FullMethod)
-		sema, ok := semaphores[serviceName]
-		if !ok {
-			return handler(ctx, req)
-		}
-		if !sema.TryAcquire() {
-			logger.Errorf("Too many requests for %s, exceeding concurrency limit (%d)", serviceName, cap(sema))
-			return nil, errors.Errorf("too many requests for %s, exceeding concurrency limit (%d)", serviceName, cap(sema))
-		}
-		defer sema.Release()
+	return newGrpcLimiter(semaphores).unary
+}
+
+func streamGrpcLimiter(semaphores map[string]semaphore.Semaphore) grpc.StreamServerInterceptor {
+	return newGrpcLimiter(semaphores).stream
+}
+
+// grpcLimiter limits the number of concurrent requests per service, where the
+// limits can be changed at runtime. A request that is in flight when a limit is
+// changed releases the permit of the semaphore it acquired it from.
+type grpcLimiter struct {
+	mutex      sync.RWMutex
+	semaphores map[string]semaphore.Semaphore
+}
+
+func newGrpcLimiter(semaphores map[string]semaphore.Semaphore) *grpcLimiter {
+	l := &grpcLimiter{semaphores: make(map[string]semaphore.Semaphore)}
+	for serviceName, sema := range semaphores {
+		l.semaphores[serviceName] = sema
+	}
+	return l
+}
+
+// setLimit changes the concurrency limit of the service, where a limit of 0
+// removes the limit.
+func (l *grpcLimiter) setLimit(serviceName string, limit int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if limit == 0 {
+		delete(l.semaphores, serviceName)
+		logger.Infof("concurrency limit for %s removed", serviceName)
+		return
+	}
+	l.semaphores[serviceName] = semaphore.New(limit)
+	logger.Infof("concurrency limit for %s is %d", serviceName, limit)
+}
+
+func (l *grpcLimiter) semaphore(serviceName string) (semaphore.Semaphore, bool) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	sema, ok := l.semaphores[serviceName]
+	return sema, ok
+}
+
+func (l *grpcLimiter) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	serviceName := getServiceName(info.FullMethod)
+	sema, ok := l.semaphore(serviceName)
+	if !ok {
 		return handler(ctx, req)
 	}
+	if !sema.TryAcquire() {
+		logger.Errorf("Too many requests for %s, exceeding concurrency limit (%d)", serviceName, cap(sema))
+		return nil, errors.Errorf("too many requests for %s, exceeding concurrency limit (%d)", serviceName, cap(sema))
+	}
+	defer sema.Release()
+	return handler(ctx, req)
 }
 
-func streamGrpcLimiter(semaphores map[string]semaphore.Semaphore) grpc.StreamServerInterceptor {
-	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		serviceName := getServiceName(info.FullMethod)
-		sema, ok := semaphores[serviceName]
-		if !ok {
-			return handler(srv, ss)
-		}
-		if !sema.TryAcquire() {
-			logger.Errorf("Too many requests for %s, exceeding concurrency limit (%d)", serviceName, cap(sema))
-			return errors.Errorf("too many requests for %s, exceeding concurrency limit (%d)", serviceName, cap(sema))
-		}
-		defer sema.Release()
+func (l *grpcLimiter) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	serviceName := getServiceName(info.FullMethod)
+	sema, ok := l.semaphore(serviceName)
+	if !ok {
 		return handler(srv, ss)
 	}
+	if !sema.TryAcquire() {
+		logger.Errorf("Too many requests for %s, exceeding concurrency limit (%d)", serviceName, cap(sema))
+		return errors.Errorf("too many requests for %s, exceeding concurrency limit (%d)", serviceName, cap(sema))
+	}
+	defer sema.Release()
+	return handler(srv, ss)
 }
 
 func getServiceName(methodName string) string {
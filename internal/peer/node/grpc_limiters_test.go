@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
@@ -83,3 +84,50 @@ func TestGetServiceName(t *testing.T) {
 	require.Equal(t, "/gateway.Gateway", getServiceName("/gateway.Gateway/CommitStatus"))
 	require.Equal(t, "/gateway.Gateway", getServiceName("/gateway.Gateway/ChaincodeEvents"))
 }
+
+func TestGrpcLimiterSetLimit(t *testing.T) {
+	fullMethod := "/protos.Endorser/ProcessProposal"
+	limiter := newGrpcLimiter(initGrpcSemaphores(&peer.Config{LimitsConcurrencyEndorserService: 1}))
+
+	// a request in flight holds the only permit until it is released
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		limiter.unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: fullMethod}, func(context.Context, interface{}) (interface{}, error) {
+			<-release
+			return nil, nil
+		})
+	}()
+	require.Eventually(t, func() bool {
+		sema, _ := limiter.semaphore(endorserServiceName)
+		return len(sema) == 1
+	}, time.Second, 10*time.Millisecond)
+
+	handler := func(context.Context, interface{}) (interface{}, error) { return "ok", nil }
+	_, err := limiter.unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: fullMethod}, handler)
+	require.EqualError(t, err, "too many requests for /protos.Endorser, exceeding concurrency limit (1)")
+
+	limiter.setLimit(endorserServiceName, 2)
+	resp, err := limiter.unary(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: fullMethod}, handler)
+	require.NoError(t, err)
+	require.Equal(t, "ok", resp)
+
+	// the request in flight releases the permit of the semaphore it acquired it from
+	close(release)
+	<-done
+	sema, ok := limiter.semaphore(endorserServiceName)
+	require.True(t, ok)
+	require.Equal(t, 2, cap(sema))
+	require.Equal(t, 0, len(sema))
+
+	limiter.setLimit(endorserServiceName, 0)
+	_, ok = limiter.semaphore(endorserServiceName)
+	require.False(t, ok)
+
+	limiter.setLimit(deliverServiceName, 1)
+	sema, _ = limiter.semaphore(deliverServiceName)
+	require.True(t, sema.TryAcquire())
+	err = limiter.stream(nil, nil, &grpc.StreamServerInfo{FullMethod: "/protos.Deliver/Deliver"}, nil)
+	require.EqualError(t, err, "too many requests for /protos.Deliver, exceeding concurrency limit (1)")
+}
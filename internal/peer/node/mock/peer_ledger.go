@@ -8,6 +8,7 @@ import (
 	"github.com/hyperledger/fabric-protos-go/peer"
 	ledgera "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/stateproof"
 )
 
 type PeerLedger struct {
@@ -194,6 +195,21 @@ type PeerLedger struct {
 		result1 []*ledger.TxPvtData
 		result2 error
 	}
+	GetStateProofStub        func(string, string, uint64) (*stateproof.Proof, error)
+	getStateProofMutex       sync.RWMutex
+	getStateProofArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 uint64
+	}
+	getStateProofReturns struct {
+		result1 *stateproof.Proof
+		result2 error
+	}
+	getStateProofReturnsOnCall map[int]struct {
+		result1 *stateproof.Proof
+		result2 error
+	}
 	GetTransactionByIDStub        func(string) (*peer.ProcessedTransaction, error)
 	getTransactionByIDMutex       sync.RWMutex
 	getTransactionByIDArgsForCall []struct {
@@ -1188,6 +1204,71 @@ func (fake *PeerLedger) GetPvtDataByNumReturnsOnCall(i int, result1 []*ledger.Tx
 	}{result1, result2}
 }
 
+func (fake *PeerLedger) GetStateProof(arg1 string, arg2 string, arg3 uint64) (*stateproof.Proof, error) {
+	fake.getStateProofMutex.Lock()
+	ret, specificReturn := fake.getStateProofReturnsOnCall[len(fake.getStateProofArgsForCall)]
+	fake.getStateProofArgsForCall = append(fake.getStateProofArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 uint64
+	}{arg1, arg2, arg3})
+	fake.recordInvocation("GetStateProof", []interface{}{arg1, arg2, arg3})
+	fake.getStateProofMutex.Unlock()
+	if fake.GetStateProofStub != nil {
+		return fake.GetStateProofStub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	fakeReturns := fake.getStateProofReturns
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *PeerLedger) GetStateProofCallCount() int {
+	fake.getStateProofMutex.RLock()
+	defer fake.getStateProofMutex.RUnlock()
+	return len(fake.getStateProofArgsForCall)
+}
+
+func (fake *PeerLedger) GetStateProofCalls(stub func(string, string, uint64) (*stateproof.Proof, error)) {
+	fake.getStateProofMutex.Lock()
+	defer fake.getStateProofMutex.Unlock()
+	fake.GetStateProofStub = stub
+}
+
+func (fake *PeerLedger) GetStateProofArgsForCall(i int) (string, string, uint64) {
+	fake.getStateProofMutex.RLock()
+	defer fake.getStateProofMutex.RUnlock()
+	argsForCall := fake.getStateProofArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *PeerLedger) GetStateProofReturns(result1 *stateproof.Proof, result2 error) {
+	fake.getStateProofMutex.Lock()
+	defer fake.getStateProofMutex.Unlock()
+	fake.GetStateProofStub = nil
+	fake.getStateProofReturns = struct {
+		result1 *stateproof.Proof
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *PeerLedger) GetStateProofReturnsOnCall(i int, result1 *stateproof.Proof, result2 error) {
+	fake.getStateProofMutex.Lock()
+	defer fake.getStateProofMutex.Unlock()
+	fake.GetStateProofStub = nil
+	if fake.getStateProofReturnsOnCall == nil {
+		fake.getStateProofReturnsOnCall = make(map[int]struct {
+			result1 *stateproof.Proof
+			result2 error
+		})
+	}
+	fake.getStateProofReturnsOnCall[i] = struct {
+		result1 *stateproof.Proof
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *PeerLedger) GetTransactionByID(arg1 string) (*peer.ProcessedTransaction, error) {
 	fake.getTransactionByIDMutex.Lock()
 	ret, specificReturn := fake.getTransactionByIDReturnsOnCall[len(fake.getTransactionByIDArgsForCall)]
@@ -1701,6 +1782,8 @@ func (fake *PeerLedger) Invocations() map[string][][]interface{} {
 	defer fake.getPvtDataAndBlockByNumMutex.RUnlock()
 	fake.getPvtDataByNumMutex.RLock()
 	defer fake.getPvtDataByNumMutex.RUnlock()
+	fake.getStateProofMutex.RLock()
+	defer fake.getStateProofMutex.RUnlock()
 	fake.getTransactionByIDMutex.RLock()
 	defer fake.getTransactionByIDMutex.RUnlock()
 	fake.getTxValidationCodeByTxIDMutex.RLock()
@@ -15,7 +15,7 @@ import (
 
 const (
 	nodeFuncName = "node"
-	nodeCmdDes   = "Operate a peer node: start|reset|rollback|pause|resume|rebuild-dbs|unjoin|upgrade-dbs."
+	nodeCmdDes   = "Operate a peer node: start|reset|rollback|pause|resume|rebuild-dbs|unjoin|upgrade-dbs|config."
 )
 
 var logger = flogging.MustGetLogger("nodeCmd")
@@ -30,6 +30,7 @@ func Cmd() *cobra.Command {
 	nodeCmd.AddCommand(rebuildDBsCmd())
 	nodeCmd.AddCommand(unjoinCmd())
 	nodeCmd.AddCommand(upgradeDBsCmd())
+	nodeCmd.AddCommand(configCmd())
 	return nodeCmd
 }
 
@@ -0,0 +1,188 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/hyperledger/fabric/core/config/dynamic"
+	confighttpadmin "github.com/hyperledger/fabric/core/config/dynamic/httpadmin"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func configCmd() *cobra.Command {
+	var (
+		settings    []string
+		dynamicOnly bool
+		address     string
+		caFile      string
+		certFile    string
+		keyFile     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Show or change the configuration of a running peer.",
+		Long: "Show the effective configuration of a running peer, with the source of each setting, through its operations service. " +
+			"Settings marked as dynamic can be changed with --set without restarting the peer; changes are not persisted across restarts.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if address == "" {
+				address = localOperationsAddress(viper.GetString("operations.listenAddress"))
+			}
+			client, url, err := operationsClient(address, caFile, certFile, keyFile)
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+			return nodeConfig(cmd.OutOrStdout(), client, url, settings, dynamicOnly)
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringArrayVar(&settings, "set", nil, "Setting to change, as key=value. May be repeated to change several settings at once.")
+	flags.BoolVar(&dynamicOnly, "dynamic", false, "Only show the settings that can be changed without restarting the peer.")
+	flags.StringVar(&address, "address", "", "Address of the operations service of the peer. Defaults to operations.listenAddress.")
+	flags.StringVar(&caFile, "cafile", "", "Path to file containing PEM-encoded TLS CA certificate(s) of the operations service. Enables TLS.")
+	flags.StringVar(&certFile, "certfile", "", "Path to file containing PEM-encoded X509 certificate used for mutual TLS with the operations service.")
+	flags.StringVar(&keyFile, "keyfile", "", "Path to file containing PEM-encoded private key used for mutual TLS with the operations service.")
+
+	return cmd
+}
+
+// localOperationsAddress turns the listen address of the operations service
+// into an address it can be reached at from the local host.
+func localOperationsAddress(listenAddress string) string {
+	host, port, err := net.SplitHostPort(listenAddress)
+	if err != nil {
+		return listenAddress
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+func operationsClient(address, caFile, certFile, keyFile string) (*http.Client, string, error) {
+	if address == "" {
+		return nil, "", errors.New("the address of the operations service is not set")
+	}
+	if caFile == "" {
+		return &http.Client{}, "http://" + address + confighttpadmin.URLBase, nil
+	}
+
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to read CA certificate")
+	}
+	caCertPool := x509.NewCertPool()
+	if !caCertPool.AppendCertsFromPEM(caPEM) {
+		return nil, "", errors.Errorf("no CA certificate found in %s", caFile)
+	}
+	tlsConfig := &tls.Config{RootCAs: caCertPool}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "failed to load client certificate and key")
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	return client, "https://" + address + confighttpadmin.URLBase, nil
+}
+
+// nodeConfig changes the settings, if any, and writes the resulting
+// configuration of the peer to w.
+func nodeConfig(w io.Writer, client *http.Client, url string, settings []string, dynamicOnly bool) error {
+	method, body := http.MethodGet, []byte(nil)
+	if len(settings) != 0 {
+		update := confighttpadmin.UpdateRequest{Settings: map[string]string{}}
+		for _, s := range settings {
+			key, value, ok := strings.Cut(s, "=")
+			if !ok || key == "" {
+				return errors.Errorf("invalid setting '%s', must be key=value", s)
+			}
+			update.Settings[key] = value
+		}
+		var err error
+		if body, err = json.Marshal(update); err != nil {
+			return err
+		}
+		method = http.MethodPut
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to reach the operations service of the peer")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		errResp := &confighttpadmin.ErrorResponse{}
+		if err := json.NewDecoder(resp.Body).Decode(errResp); err != nil || errResp.Error == "" {
+			return errors.Errorf("operations service responded with %s", resp.Status)
+		}
+		return errors.New(errResp.Error)
+	}
+	config := &confighttpadmin.ConfigResponse{}
+	decoder := json.NewDecoder(resp.Body)
+	decoder.UseNumber() // large integers are shown as they are configured
+	if err := decoder.Decode(config); err != nil {
+		return errors.Wrap(err, "failed to decode the configuration of the peer")
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE\tDYNAMIC")
+	for _, e := range config.Settings {
+		if dynamicOnly && !e.Dynamic {
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.Key, formatValue(e), e.Source, dynamicMark(e))
+	}
+	return tw.Flush()
+}
+
+func formatValue(e dynamic.Entry) string {
+	switch v := e.Value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func dynamicMark(e dynamic.Entry) string {
+	if e.Dynamic {
+		return "yes"
+	}
+	return "no"
+}
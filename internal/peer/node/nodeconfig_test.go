@@ -0,0 +1,78 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/config/dynamic"
+	confighttpadmin "github.com/hyperledger/fabric/core/config/dynamic/httpadmin"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeConfig(t *testing.T) {
+	v := viper.New()
+	v.Set("peer.id", "peer0")
+	v.Set("peer.limits.concurrency.endorserservice", 2500)
+	v.Set("peer.gossip.bootstrap", []interface{}{"peer1:7051", "peer2:7051"})
+	registry, err := dynamic.NewRegistry(v, "CORE",
+		dynamic.IntSetting("peer.limits.concurrency.endorserService", 2500, 0),
+		dynamic.DurationSetting("chaincode.executetimeout", 30*time.Second, time.Second),
+	)
+	require.NoError(t, err)
+	var limit int
+	require.NoError(t, registry.SubscribeInt("peer.limits.concurrency.endorserService", func(l int) { limit = l }))
+
+	server := httptest.NewServer(confighttpadmin.NewHandler(registry))
+	defer server.Close()
+	client, url, err := operationsClient(server.Listener.Addr().String(), "", "", "")
+	require.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	err = nodeConfig(buf, client, url, nil, false)
+	require.NoError(t, err)
+	require.Equal(t, ""+
+		"KEY                                      VALUE                        SOURCE   DYNAMIC\n"+
+		"chaincode.executetimeout                 30s                          default  yes\n"+
+		"peer.gossip.bootstrap                    [\"peer1:7051\",\"peer2:7051\"]  default  no\n"+
+		"peer.id                                  peer0                        default  no\n"+
+		"peer.limits.concurrency.endorserService  2500                         default  yes\n",
+		buf.String())
+
+	buf.Reset()
+	err = nodeConfig(buf, client, url, []string{"peer.limits.concurrency.endorserService=100"}, true)
+	require.NoError(t, err)
+	require.Equal(t, 100, limit)
+	require.Equal(t, ""+
+		"KEY                                      VALUE  SOURCE   DYNAMIC\n"+
+		"chaincode.executetimeout                 30s    default  yes\n"+
+		"peer.limits.concurrency.endorserService  100    runtime  yes\n",
+		buf.String())
+
+	err = nodeConfig(buf, client, url, []string{"chaincode.executetimeout=10s"}, false)
+	require.EqualError(t, err, "setting chaincode.executetimeout is not used by this node")
+
+	err = nodeConfig(buf, client, url, []string{"peer.id"}, false)
+	require.EqualError(t, err, "invalid setting 'peer.id', must be key=value")
+
+	_, _, err = operationsClient("", "", "", "")
+	require.EqualError(t, err, "the address of the operations service is not set")
+	_, _, err = operationsClient("127.0.0.1:9443", "testdata/missing.pem", "", "")
+	require.ErrorContains(t, err, "failed to read CA certificate")
+}
+
+func TestLocalOperationsAddress(t *testing.T) {
+	require.Equal(t, "127.0.0.1:9443", localOperationsAddress("0.0.0.0:9443"))
+	require.Equal(t, "127.0.0.1:9443", localOperationsAddress(":9443"))
+	require.Equal(t, "127.0.0.1:9443", localOperationsAddress("[::]:9443"))
+	require.Equal(t, "peer0.org1.example.com:9443", localOperationsAddress("peer0.org1.example.com:9443"))
+	require.Equal(t, "", localOperationsAddress(""))
+}
@@ -31,6 +31,6 @@ var pauseChannelCmd = &cobra.Command{
 		}
 
 		config := ledgerConfig()
-		return kvledger.PauseChannel(config.RootFSPath, channelID)
+		return auditOffline("PauseChannel", channelID, kvledger.PauseChannel(config.RootFSPath, channelID))
 	},
 }
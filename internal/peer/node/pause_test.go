@@ -7,9 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package node
 
 import (
+	"encoding/json"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/hyperledger/fabric/common/audit"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
 )
@@ -35,4 +38,24 @@ func TestPauseCmd(t *testing.T) {
 		err := cmd.Execute()
 		require.EqualError(t, err, "cannot update ledger status, ledger [ch_p] does not exist")
 	})
+
+	t.Run("when the audit log is enabled", func(t *testing.T) {
+		testPath := t.TempDir()
+		viper.Set("peer.fileSystemPath", testPath)
+		viper.Set("audit.enabled", true)
+		defer viper.Set("audit.enabled", false)
+
+		cmd := pauseCmd()
+		cmd.SetArgs([]string{"-c", "ch_p"})
+		require.Error(t, cmd.Execute())
+
+		data, err := os.ReadFile(filepath.Join(testPath, "audit", "audit.log"))
+		require.NoError(t, err)
+		record := audit.Record{}
+		require.NoError(t, json.Unmarshal(data, &record))
+		require.Equal(t, "PauseChannel", record.Action)
+		require.Equal(t, map[string]string{"channel": "ch_p"}, record.Parameters)
+		require.Equal(t, audit.OutcomeFailure, record.Outcome)
+		require.Equal(t, "cannot update ledger status, ledger [ch_p] does not exist", record.Error)
+	})
 }
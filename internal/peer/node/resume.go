@@ -31,6 +31,6 @@ var resumeChannelCmd = &cobra.Command{
 		}
 
 		config := ledgerConfig()
-		return kvledger.ResumeChannel(config.RootFSPath, channelID)
+		return auditOffline("ResumeChannel", channelID, kvledger.ResumeChannel(config.RootFSPath, channelID))
 	},
 }
@@ -8,6 +8,7 @@ package node
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -27,6 +28,7 @@ import (
 	gatewayprotos "github.com/hyperledger/fabric-protos-go/gateway"
 	pb "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/audit"
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	ccdef "github.com/hyperledger/fabric/common/chaincode"
 	"github.com/hyperledger/fabric/common/crypto"
@@ -53,6 +55,7 @@ import (
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/privdata"
 	coreconfig "github.com/hyperledger/fabric/core/config"
+	confighttpadmin "github.com/hyperledger/fabric/core/config/dynamic/httpadmin"
 	"github.com/hyperledger/fabric/core/container"
 	"github.com/hyperledger/fabric/core/container/dockercontroller"
 	"github.com/hyperledger/fabric/core/container/externalbuilder"
@@ -68,6 +71,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
 	"github.com/hyperledger/fabric/core/ledger/kvledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	ledgermgmthttpadmin "github.com/hyperledger/fabric/core/ledger/ledgermgmt/httpadmin"
 	"github.com/hyperledger/fabric/core/ledger/snapshotgrpc"
 	"github.com/hyperledger/fabric/core/operations"
 	"github.com/hyperledger/fabric/core/peer"
@@ -77,6 +81,7 @@ import (
 	"github.com/hyperledger/fabric/core/scc/lscc"
 	"github.com/hyperledger/fabric/core/scc/qscc"
 	"github.com/hyperledger/fabric/core/transientstore"
+	transientstorehttpadmin "github.com/hyperledger/fabric/core/transientstore/httpadmin"
 	"github.com/hyperledger/fabric/discovery"
 	"github.com/hyperledger/fabric/discovery/endorsement"
 	discsupport "github.com/hyperledger/fabric/discovery/support"
@@ -93,6 +98,8 @@ import (
 	"github.com/hyperledger/fabric/internal/peer/version"
 	"github.com/hyperledger/fabric/internal/pkg/comm"
 	"github.com/hyperledger/fabric/internal/pkg/gateway"
+	"github.com/hyperledger/fabric/internal/pkg/gateway/commit"
+	gatewayledger "github.com/hyperledger/fabric/internal/pkg/gateway/ledger"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protoutil"
@@ -187,6 +194,50 @@ func (c custodianLauncherAdapter) Stop(ccid string) error {
 	return c.launcher.Stop(ccid)
 }
 
+// packageSignerDeserializer deserializes the identities of package signers
+// using the local MSP, then the MSPs of the channels the peer has joined.
+type packageSignerDeserializer struct {
+	localMSP msp.IdentityDeserializer
+	peer     *peer.Peer
+}
+
+func (p packageSignerDeserializer) DeserializeIdentity(serializedIdentity []byte) (msp.Identity, error) {
+	identity, err := p.localMSP.DeserializeIdentity(serializedIdentity)
+	if err == nil {
+		return identity, nil
+	}
+	for _, channel := range p.peer.GetChannelsInfo() {
+		c := p.peer.Channel(channel.ChannelId)
+		if c == nil {
+			continue
+		}
+		if identity, err = c.MSPManager().DeserializeIdentity(serializedIdentity); err == nil {
+			return identity, nil
+		}
+	}
+	return nil, err
+}
+
+func newPackageSignatureVerifier(coreConfig *peer.Config, localMSP msp.IdentityDeserializer, peerInstance *peer.Peer) (*lifecycle.PackageSignatureVerifier, error) {
+	verifier := &lifecycle.PackageSignatureVerifier{
+		TrustedMSPs:  coreConfig.PackageSignatureTrustedMSPs,
+		Deserializer: packageSignerDeserializer{localMSP: localMSP, peer: peerInstance},
+	}
+	if len(coreConfig.PackageSignatureTrustedRoots) != 0 {
+		verifier.TrustedRoots = x509.NewCertPool()
+	}
+	for _, certFile := range coreConfig.PackageSignatureTrustedRoots {
+		pemBytes, err := ioutil.ReadFile(certFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not read trusted signer CA certificate")
+		}
+		if !verifier.TrustedRoots.AppendCertsFromPEM(pemBytes) {
+			return nil, errors.Errorf("no PEM encoded certificate found in trusted signer CA certificate file %s", certFile)
+		}
+	}
+	return verifier, nil
+}
+
 func serve(args []string) error {
 	logger.Infof("Starting %s", version.GetInfo())
 
@@ -229,7 +280,22 @@ func serve(args []string) error {
 
 	platformRegistry := platforms.NewRegistry(platforms.SupportedPlatforms...)
 
-	opsSystem := newOperationsSystem(coreConfig)
+	logSinks, err := logSinksConfig()
+	if err != nil {
+		return err
+	}
+	if err := flogging.SetSinks(logSinks); err != nil {
+		return errors.WithMessage(err, "failed to initialize log sinks")
+	}
+	defer flogging.SetSinks(nil)
+
+	auditLogger, err := audit.NewFileLogger(auditConfig())
+	if err != nil {
+		return errors.WithMessage(err, "failed to initialize audit log")
+	}
+	defer auditLogger.Close()
+
+	opsSystem := newOperationsSystem(coreConfig, auditLogger)
 	err = opsSystem.Start()
 	if err != nil {
 		return errors.WithMessage(err, "failed to initialize operations subsystem")
@@ -270,10 +336,14 @@ func serve(args []string) error {
 		grpclogging.StreamServerInterceptor(flogging.MustGetLogger("comm.grpc.server").Zap()),
 	)
 
-	semaphores := initGrpcSemaphores(coreConfig)
-	if len(semaphores) != 0 {
-		serverConfig.UnaryInterceptors = append(serverConfig.UnaryInterceptors, unaryGrpcLimiter(semaphores))
-		serverConfig.StreamInterceptors = append(serverConfig.StreamInterceptors, streamGrpcLimiter(semaphores))
+	// the limiter is installed even without limits, as the limits can be set at runtime
+	grpcLimiter := newGrpcLimiter(initGrpcSemaphores(coreConfig))
+	serverConfig.UnaryInterceptors = append(serverConfig.UnaryInterceptors, grpcLimiter.unary)
+	serverConfig.StreamInterceptors = append(serverConfig.StreamInterceptors, grpcLimiter.stream)
+	clientLimiters := initGrpcClientLimiters(coreConfig, metricsProvider)
+	if clientLimiters != nil {
+		serverConfig.UnaryInterceptors = append(serverConfig.UnaryInterceptors, unaryGrpcClientLimiter(clientLimiters))
+		serverConfig.StreamInterceptors = append(serverConfig.StreamInterceptors, streamGrpcClientLimiter(clientLimiters))
 	}
 
 	cs := comm.NewCredentialSupport()
@@ -290,11 +360,21 @@ func serve(args []string) error {
 	}
 
 	transientStoreProvider, err := transientstore.NewStoreProvider(
-		filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "transientstore"),
+		&transientstore.Config{
+			Path:              filepath.Join(coreconfig.GetPath("peer.fileSystemPath"), "transientstore"),
+			EncryptionEnabled: coreConfig.TransientStoreEncryptionEnabled,
+			CryptoProvider:    factory.GetDefault(),
+			MetricsProvider:   metricsProvider,
+		},
 	)
 	if err != nil {
 		return errors.WithMessage(err, "failed to open transient store")
 	}
+	opsSystem.RegisterHandler(
+		transientstorehttpadmin.URLBase,
+		transientstorehttpadmin.NewHandler(transientStoreProvider),
+		coreConfig.OperationsTLSEnabled,
+	)
 
 	deliverServiceConfig := deliverservice.GlobalConfig()
 
@@ -316,6 +396,15 @@ func serve(args []string) error {
 	mspID := coreConfig.LocalMSPID
 	localMSP := mgmt.GetLocalMSP(factory.GetDefault())
 
+	if clientLimiters != nil {
+		clientLimiters.deserializer = func(channelID string) msp.IdentityDeserializer {
+			if channelID == "" {
+				return localMSP
+			}
+			return identityDeserializerFactory(channelID)
+		}
+	}
+
 	signingIdentity, err := localMSP.GetDefaultSigningIdentity()
 	if err != nil {
 		logger.Panicf("Could not get the default signing identity from the local MSP: [%+v]", err)
@@ -453,9 +542,15 @@ func serve(args []string) error {
 			StateListeners:                  []ledger.StateListener{lifecycleCache},
 			Config:                          ledgerConfig(),
 			HashProvider:                    factory.GetDefault(),
+			CryptoProvider:                  factory.GetDefault(),
 			EbMetadataProvider:              ebMetadataProvider,
 		},
 	)
+	opsSystem.RegisterHandler(
+		ledgermgmthttpadmin.URLBase,
+		ledgermgmthttpadmin.NewHandler(peerInstance.LedgerMgr),
+		coreConfig.OperationsTLSEnabled,
+	)
 
 	peerServer, err := comm.NewGRPCServer(listenAddr, serverConfig)
 	if err != nil {
@@ -512,6 +607,7 @@ func serve(args []string) error {
 			false,
 		),
 		PolicyCheckerProvider: policyCheckerProvider,
+		ACLProvider:           aclProvider,
 	}
 	pb.RegisterDeliverServer(peerServer.Server(), abServer)
 
@@ -648,7 +744,6 @@ func serve(args []string) error {
 		ChaincodeBuilder:          containerRouter,
 		BuildRegistry:             buildRegistry,
 	}
-
 	lifecycleSCC := &lifecycle.SCC{
 		Dispatcher: &dispatcher.Dispatcher{
 			Protobuf: &dispatcher.ProtobufImpl{},
@@ -659,6 +754,15 @@ func serve(args []string) error {
 		OrgMSPID:               mspID,
 		ChannelConfigSource:    peerInstance,
 		ACLProvider:            aclProvider,
+		AuditLogger:            auditLogger,
+	}
+	if coreConfig.PackageSignaturesRequired {
+		packageVerifier, err := newPackageSignatureVerifier(coreConfig, localMSP, peerInstance)
+		if err != nil {
+			logger.Panicf("Failed to load the chaincode package signature trust policy: %s", err)
+		}
+		lifecycleSCC.PackageVerifier = packageVerifier
+		lsccInst.PackageVerifier = packageVerifier
 	}
 
 	chaincodeLauncher := &chaincode.RuntimeLauncher{
@@ -669,7 +773,7 @@ func serve(args []string) error {
 		CertGenerator:     authenticator,
 		CACert:            ca.CertBytes(),
 		PeerAddress:       ccEndpoint,
-		ConnectionHandler: &extcc.ExternalChaincodeRuntime{},
+		ConnectionHandler: &extcc.ExternalChaincodeRuntime{Metrics: extcc.NewMetrics(opsSystem.Provider)},
 	}
 
 	// Keep TestQueries working
@@ -714,6 +818,7 @@ func serve(args []string) error {
 		peerInstance,
 		factory.GetDefault(),
 	)
+	csccInst.AuditLogger = auditLogger
 	qsccInst := scc.SelfDescribingSysCC(qscc.New(aclProvider, peerInstance))
 
 	pb.RegisterChaincodeSupportServer(ccSrv.Server(), ccSupSrv)
@@ -761,6 +866,18 @@ func serve(args []string) error {
 		Metrics:                endorser.NewMetrics(metricsProvider),
 	}
 
+	// the commit notifier is shared by the gateway and the endorsement cache, as
+	// each ledger supports a single consumer of its commit notifications
+	commitNotifier := commit.NewNotifier(&gatewayledger.PeerAdapter{Peer: peerInstance})
+	if coreConfig.EndorsementCacheEnabled {
+		logger.Infof("Caching the results of read-only proposals, max entries: %d", coreConfig.EndorsementCacheMaxEntries)
+		serverEndorser.ResultCache = endorser.NewResultCache(
+			commitNotifier,
+			coreConfig.EndorsementCacheMaxEntries,
+			coreConfig.EndorsementCacheChaincodes,
+		)
+	}
+
 	// deploy system chaincodes
 	for _, cc := range []scc.SelfDescribingSysCC{lsccInst, csccInst, qsccInst, lifecycleSCC} {
 		if enabled, ok := chaincodeConfig.SCCAllowlist[cc.Name()]; !ok || !enabled {
@@ -834,11 +951,12 @@ func serve(args []string) error {
 		discprotos.RegisterDiscoveryServer(peerServer.Server(), discoveryService)
 	}
 
+	var gatewayServer *gateway.Server
 	if coreConfig.GatewayOptions.Enabled {
 		if coreConfig.DiscoveryEnabled {
 			logger.Info("Starting peer with Gateway enabled")
 
-			gatewayServer := gateway.CreateServer(
+			gatewayServer = gateway.CreateServer(
 				serverEndorser,
 				discoveryService,
 				peerInstance,
@@ -847,6 +965,8 @@ func serve(args []string) error {
 				coreConfig.LocalMSPID,
 				coreConfig.GatewayOptions,
 				builtinSCCs,
+				commitNotifier,
+				metricsProvider,
 			)
 			gatewayprotos.RegisterGatewayServer(peerServer.Server(), gatewayServer)
 		} else {
@@ -854,6 +974,23 @@ func serve(args []string) error {
 		}
 	}
 
+	configRegistry, err := newConfigRegistry(coreConfig, chaincodeConfig, deliverServiceConfig, privdataConfig)
+	if err != nil {
+		return errors.WithMessage(err, "failed to take a snapshot of the configuration")
+	}
+	err = subscribeDynamicComponents(configRegistry, dynamicComponents{
+		grpcLimiter:      grpcLimiter,
+		chaincodeSupport: chaincodeSupport,
+		gatewayServer:    gatewayServer,
+		gossipService:    gossipService,
+	})
+	if err != nil {
+		return errors.WithMessage(err, "failed to subscribe to configuration changes")
+	}
+	configHandler := confighttpadmin.NewHandler(configRegistry)
+	configHandler.AuditLogger = auditLogger
+	opsSystem.RegisterHandler(confighttpadmin.URLBase, configHandler, coreConfig.OperationsTLSEnabled)
+
 	logger.Infof("Starting peer with ID=[%s], network ID=[%s], address=[%s]", coreConfig.PeerID, coreConfig.NetworkID, coreConfig.PeerAddress)
 
 	// Get configuration before starting go routines to avoid
@@ -914,7 +1051,7 @@ func serve(args []string) error {
 	pb.RegisterEndorserServer(peerServer.Server(), auth)
 
 	// register the snapshot server
-	snapshotSvc := &snapshotgrpc.SnapshotService{LedgerGetter: peerInstance, ACLProvider: aclProvider}
+	snapshotSvc := &snapshotgrpc.SnapshotService{LedgerGetter: peerInstance, ACLProvider: aclProvider, AuditLogger: auditLogger}
 	pb.RegisterSnapshotServer(peerServer.Server(), snapshotSvc)
 
 	go func() {
@@ -1263,7 +1400,7 @@ func initGossipService(
 	)
 }
 
-func newOperationsSystem(coreConfig *peer.Config) *operations.System {
+func newOperationsSystem(coreConfig *peer.Config, auditLogger *audit.Logger) *operations.System {
 	return operations.NewSystem(operations.Options{
 		Options: fabhttp.Options{
 			Logger:        flogging.MustGetLogger("peer.operations"),
@@ -1285,7 +1422,8 @@ func newOperationsSystem(coreConfig *peer.Config) *operations.System {
 				Prefix:        coreConfig.StatsdPrefix,
 			},
 		},
-		Version: metadata.Version,
+		Version:     metadata.Version,
+		AuditLogger: auditLogger,
 	})
 }
 
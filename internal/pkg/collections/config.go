@@ -0,0 +1,149 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package collections parses the collection configuration files that the
+// command line tools accept.
+package collections
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/golang/protobuf/proto"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/policydsl"
+	"github.com/pkg/errors"
+)
+
+type endorsementPolicy struct {
+	ChannelConfigPolicy string `json:"channelConfigPolicy,omitempty"`
+	SignaturePolicy     string `json:"signaturePolicy,omitempty"`
+}
+
+type collectionConfigJson struct {
+	Name              string             `json:"name"`
+	Policy            string             `json:"policy"`
+	RequiredPeerCount *int32             `json:"requiredPeerCount"`
+	MaxPeerCount      *int32             `json:"maxPeerCount"`
+	BlockToLive       uint64             `json:"blockToLive"`
+	MemberOnlyRead    bool               `json:"memberOnlyRead"`
+	MemberOnlyWrite   bool               `json:"memberOnlyWrite"`
+	EndorsementPolicy *endorsementPolicy `json:"endorsementPolicy,omitempty"`
+}
+
+// ConfigFromFile retrieves the collection configuration
+// from the supplied file; the supplied file must contain a
+// json-formatted array of collectionConfigJson elements
+func ConfigFromFile(ccFile string) (*pb.CollectionConfigPackage, []byte, error) {
+	fileBytes, err := os.ReadFile(ccFile)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "could not read file '%s'", ccFile)
+	}
+
+	return configFromBytes(fileBytes)
+}
+
+// configFromBytes retrieves the collection configuration
+// from the supplied byte array; the byte array must contain a
+// json-formatted array of collectionConfigJson elements
+func configFromBytes(cconfBytes []byte) (*pb.CollectionConfigPackage, []byte, error) {
+	cconf := &[]collectionConfigJson{}
+	err := json.Unmarshal(cconfBytes, cconf)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not parse the collection configuration")
+	}
+
+	ccarray := make([]*pb.CollectionConfig, 0, len(*cconf))
+	for _, cconfitem := range *cconf {
+		p, err := policydsl.FromString(cconfitem.Policy)
+		if err != nil {
+			return nil, nil, errors.WithMessagef(err, "invalid policy %s", cconfitem.Policy)
+		}
+
+		cpc := &pb.CollectionPolicyConfig{
+			Payload: &pb.CollectionPolicyConfig_SignaturePolicy{
+				SignaturePolicy: p,
+			},
+		}
+
+		var ep *pb.ApplicationPolicy
+		if cconfitem.EndorsementPolicy != nil {
+			signaturePolicy := cconfitem.EndorsementPolicy.SignaturePolicy
+			channelConfigPolicy := cconfitem.EndorsementPolicy.ChannelConfigPolicy
+			ep, err = getApplicationPolicy(signaturePolicy, channelConfigPolicy)
+			if err != nil {
+				return nil, nil, errors.WithMessagef(err, "invalid endorsement policy [%#v]", cconfitem.EndorsementPolicy)
+			}
+		}
+
+		// Set default requiredPeerCount and MaxPeerCount if not specified in json
+		requiredPeerCount := int32(0)
+		maxPeerCount := int32(1)
+		if cconfitem.RequiredPeerCount != nil {
+			requiredPeerCount = *cconfitem.RequiredPeerCount
+		}
+		if cconfitem.MaxPeerCount != nil {
+			maxPeerCount = *cconfitem.MaxPeerCount
+		}
+
+		cc := &pb.CollectionConfig{
+			Payload: &pb.CollectionConfig_StaticCollectionConfig{
+				StaticCollectionConfig: &pb.StaticCollectionConfig{
+					Name:              cconfitem.Name,
+					MemberOrgsPolicy:  cpc,
+					RequiredPeerCount: requiredPeerCount,
+					MaximumPeerCount:  maxPeerCount,
+					BlockToLive:       cconfitem.BlockToLive,
+					MemberOnlyRead:    cconfitem.MemberOnlyRead,
+					MemberOnlyWrite:   cconfitem.MemberOnlyWrite,
+					EndorsementPolicy: ep,
+				},
+			},
+		}
+
+		ccarray = append(ccarray, cc)
+	}
+
+	ccp := &pb.CollectionConfigPackage{Config: ccarray}
+	ccpBytes, err := proto.Marshal(ccp)
+	return ccp, ccpBytes, err
+}
+
+func getApplicationPolicy(signaturePolicy, channelConfigPolicy string) (*pb.ApplicationPolicy, error) {
+	if signaturePolicy == "" && channelConfigPolicy == "" {
+		// no policy, no problem
+		return nil, nil
+	}
+
+	if signaturePolicy != "" && channelConfigPolicy != "" {
+		// mo policies, mo problems
+		return nil, errors.New(`cannot specify both "--signature-policy" and "--channel-config-policy"`)
+	}
+
+	var applicationPolicy *pb.ApplicationPolicy
+	if signaturePolicy != "" {
+		signaturePolicyEnvelope, err := policydsl.FromString(signaturePolicy)
+		if err != nil {
+			return nil, errors.Errorf("invalid signature policy: %s", signaturePolicy)
+		}
+
+		applicationPolicy = &pb.ApplicationPolicy{
+			Type: &pb.ApplicationPolicy_SignaturePolicy{
+				SignaturePolicy: signaturePolicyEnvelope,
+			},
+		}
+	}
+
+	if channelConfigPolicy != "" {
+		applicationPolicy = &pb.ApplicationPolicy{
+			Type: &pb.ApplicationPolicy_ChannelConfigPolicyReference{
+				ChannelConfigPolicyReference: channelConfigPolicy,
+			},
+		}
+	}
+
+	return applicationPolicy, nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package commit
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/core/ledger"
+)
+
+// blockEventBufferSize is the number of block events buffered for each listener before it is considered to have fallen
+// too far behind and is closed.
+const blockEventBufferSize = 100
+
+type blockEventListenerSet map[*blockEventListener]struct{}
+
+type blockEventNotifier struct {
+	lock      sync.Mutex
+	listeners blockEventListenerSet
+	closed    bool
+}
+
+func newBlockEventNotifier() *blockEventNotifier {
+	return &blockEventNotifier{
+		listeners: make(blockEventListenerSet),
+	}
+}
+
+func (notifier *blockEventNotifier) ReceiveBlock(blockEvent *ledger.CommitNotification) {
+	notifier.lock.Lock()
+	defer notifier.lock.Unlock()
+
+	for listener := range notifier.listeners {
+		if listener.isDone() || !listener.receive(blockEvent) {
+			notifier.removeListener(listener)
+		}
+	}
+}
+
+func (notifier *blockEventNotifier) registerListener(done <-chan struct{}) <-chan *ledger.CommitNotification {
+	notifyChannel := make(chan *ledger.CommitNotification, blockEventBufferSize)
+
+	notifier.lock.Lock()
+	defer notifier.lock.Unlock()
+
+	if notifier.closed {
+		close(notifyChannel)
+	} else {
+		listener := &blockEventListener{
+			done:          done,
+			notifyChannel: notifyChannel,
+		}
+		notifier.listeners[listener] = struct{}{}
+	}
+
+	return notifyChannel
+}
+
+func (notifier *blockEventNotifier) removeListener(listener *blockEventListener) {
+	listener.close()
+	delete(notifier.listeners, listener)
+}
+
+func (notifier *blockEventNotifier) Close() {
+	notifier.lock.Lock()
+	defer notifier.lock.Unlock()
+
+	for listener := range notifier.listeners {
+		listener.close()
+	}
+
+	notifier.listeners = nil
+	notifier.closed = true
+}
+
+type blockEventListener struct {
+	done          <-chan struct{}
+	notifyChannel chan *ledger.CommitNotification
+}
+
+func (listener *blockEventListener) isDone() bool {
+	select {
+	case <-listener.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (listener *blockEventListener) close() {
+	close(listener.notifyChannel)
+}
+
+// receive delivers the block event without blocking, returning false if the listener's buffer is full.
+func (listener *blockEventListener) receive(event *ledger.CommitNotification) bool {
+	select {
+	case listener.notifyChannel <- event:
+		return true
+	default:
+		return false
+	}
+}
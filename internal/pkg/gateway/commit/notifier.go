@@ -9,24 +9,26 @@ package commit
 import (
 	"sync"
 
-	"github.com/hyperledger/fabric/internal/pkg/gateway/ledger"
+	"github.com/hyperledger/fabric/core/ledger"
+	gatewayledger "github.com/hyperledger/fabric/internal/pkg/gateway/ledger"
 )
 
 type notifiers struct {
-	block  *blockNotifier
-	status *statusNotifier
+	block       *blockNotifier
+	status      *statusNotifier
+	blockEvents *blockEventNotifier
 }
 
 // Notifier provides notification of transaction commits.
 type Notifier struct {
-	provider           ledger.Provider
+	provider           gatewayledger.Provider
 	lock               sync.Mutex
 	notifiersByChannel map[string]*notifiers
 	cancel             chan struct{}
 	once               sync.Once
 }
 
-func NewNotifier(provider ledger.Provider) *Notifier {
+func NewNotifier(provider gatewayledger.Provider) *Notifier {
 	return &Notifier{
 		provider:           provider,
 		notifiersByChannel: make(map[string]*notifiers),
@@ -46,6 +48,20 @@ func (n *Notifier) notifyStatus(done <-chan struct{}, channelName string, transa
 	return notifyChannel, nil
 }
 
+// NotifyBlocks notifies the caller of each block committed on the named channel after registering for notifications.
+// The returned channel is closed once the done channel is closed, when the notifier stops receiving blocks from the
+// ledger, or when the caller falls too far behind in consuming notifications. In the last case some blocks have not
+// been notified, and the caller should register again.
+func (n *Notifier) NotifyBlocks(done <-chan struct{}, channelName string) (<-chan *ledger.CommitNotification, error) {
+	notifiers, err := n.notifiersForChannel(channelName)
+	if err != nil {
+		return nil, err
+	}
+
+	notifyChannel := notifiers.blockEvents.registerListener(done)
+	return notifyChannel, nil
+}
+
 // close the notifier. This closes all notification channels obtained from this notifier. Behavior is undefined after
 // closing and the notifier should not be used.
 func (n *Notifier) close() {
@@ -74,10 +90,12 @@ func (n *Notifier) notifiersForChannel(channelName string) (*notifiers, error) {
 	}
 
 	statusNotifier := newStatusNotifier()
-	blockNotifier := newBlockNotifier(n.cancel, commitChannel, statusNotifier)
+	blockEventNotifier := newBlockEventNotifier()
+	blockNotifier := newBlockNotifier(n.cancel, commitChannel, statusNotifier, blockEventNotifier)
 	result = &notifiers{
-		block:  blockNotifier,
-		status: statusNotifier,
+		block:       blockNotifier,
+		status:      statusNotifier,
+		blockEvents: blockEventNotifier,
 	}
 	n.notifiersByChannel[channelName] = result
 
@@ -410,6 +410,112 @@ func TestNotifier(t *testing.T) {
 		})
 	})
 
+	t.Run("NotifyBlocks", func(t *testing.T) {
+		t.Run("returns error from notification supplier", func(t *testing.T) {
+			provider, ledger := newLedgerMocks()
+			ledger.CommitNotificationsChannelReturns(nil, errors.New("MY_ERROR"))
+			notifier := NewNotifier(provider)
+			defer notifier.close()
+
+			_, err := notifier.NotifyBlocks(nil, "CHANNEL_NAME")
+
+			require.ErrorContains(t, err, "MY_ERROR")
+		})
+
+		t.Run("delivers blocks in order", func(t *testing.T) {
+			commitSend := make(chan *ledger.CommitNotification, 2)
+			notifier := newTestNotifier(commitSend)
+			defer notifier.close()
+
+			blockReceive, err := notifier.NotifyBlocks(nil, "CHANNEL_NAME")
+			require.NoError(t, err)
+
+			commitSend <- &ledger.CommitNotification{BlockNumber: 1}
+			commitSend <- &ledger.CommitNotification{BlockNumber: 2}
+
+			require.EqualValues(t, 1, (<-blockReceive).BlockNumber)
+			require.EqualValues(t, 2, (<-blockReceive).BlockNumber)
+		})
+
+		t.Run("shares the ledger notifications with status listeners", func(t *testing.T) {
+			provider, ledgerMock := newLedgerMocks()
+			commitSend := make(chan *ledger.CommitNotification, 1)
+			ledgerMock.CommitNotificationsChannelReturns(commitSend, nil)
+			notifier := NewNotifier(provider)
+			defer notifier.close()
+
+			blockReceive, err := notifier.NotifyBlocks(nil, "CHANNEL_NAME")
+			require.NoError(t, err)
+			statusReceive, err := notifier.notifyStatus(nil, "CHANNEL_NAME", "TX_ID")
+			require.NoError(t, err)
+
+			commitSend <- &ledger.CommitNotification{
+				BlockNumber: 1,
+				TxsInfo: []*ledger.CommitNotificationTxInfo{
+					{
+						TxID:           "TX_ID",
+						ValidationCode: peer.TxValidationCode_VALID,
+					},
+				},
+			}
+
+			require.EqualValues(t, 1, (<-blockReceive).BlockNumber)
+			require.EqualValues(t, 1, (<-statusReceive).BlockNumber)
+			require.Equal(t, 1, ledgerMock.CommitNotificationsChannelCallCount())
+		})
+
+		t.Run("stops notification when done channel closed", func(t *testing.T) {
+			commitSend := make(chan *ledger.CommitNotification, 1)
+			notifier := newTestNotifier(commitSend)
+			defer notifier.close()
+
+			done := make(chan struct{})
+			blockReceive, err := notifier.NotifyBlocks(done, "CHANNEL_NAME")
+			require.NoError(t, err)
+
+			close(done)
+			commitSend <- &ledger.CommitNotification{BlockNumber: 1}
+			_, ok := <-blockReceive
+
+			require.False(t, ok, "Expected notification channel to be closed but receive was successful")
+		})
+
+		t.Run("closes channel of listener that falls behind", func(t *testing.T) {
+			commitSend := make(chan *ledger.CommitNotification)
+			notifier := newTestNotifier(commitSend)
+			defer notifier.close()
+
+			blockReceive, err := notifier.NotifyBlocks(nil, "CHANNEL_NAME")
+			require.NoError(t, err)
+
+			for i := 0; i <= blockEventBufferSize; i++ {
+				commitSend <- &ledger.CommitNotification{BlockNumber: uint64(i)}
+			}
+			// Unbuffered send returns once the previous block has been delivered to listeners
+			commitSend <- &ledger.CommitNotification{BlockNumber: blockEventBufferSize + 1}
+
+			count := 0
+			for range blockReceive {
+				count++
+			}
+			require.Equal(t, blockEventBufferSize, count)
+		})
+
+		t.Run("stops notification if supplier stops", func(t *testing.T) {
+			commitSend := make(chan *ledger.CommitNotification, 1)
+			notifier := newTestNotifier(commitSend)
+			defer notifier.close()
+
+			blockReceive, err := notifier.NotifyBlocks(nil, "CHANNEL_NAME")
+			require.NoError(t, err)
+
+			close(commitSend)
+			_, ok := <-blockReceive
+
+			require.False(t, ok, "Expected notification channel to be closed but receive was successful")
+		})
+	})
+
 	t.Run("Close", func(t *testing.T) {
 		t.Run("stops all listeners", func(t *testing.T) {
 			commitSend := make(chan *ledger.CommitNotification)
@@ -9,6 +9,7 @@ package gateway
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	gp "github.com/hyperledger/fabric-protos-go/gateway"
@@ -55,6 +56,7 @@ func (gs *Server) Endorse(ctx context.Context, request *gp.EndorseRequest) (*gp.
 	hasTransientData := len(payload.GetTransientMap()) > 0
 
 	logger := gs.logger.With("channel", channel, "chaincode", chaincodeID, "txID", request.GetTransactionId())
+	requested := time.Now()
 
 	var plan *plan
 	var action *peer.ChaincodeEndorsedAction
@@ -115,6 +117,7 @@ func (gs *Server) Endorse(ctx context.Context, request *gp.EndorseRequest) (*gp.
 		return nil, status.Errorf(codes.Aborted, "failed to assemble transaction: %s", err)
 	}
 
+	gs.tracker.endorsed(channel, chaincodeID, request.GetTransactionId(), requested)
 	return &gp.EndorseResponse{PreparedTransaction: preparedTransaction}, nil
 }
 
@@ -132,7 +135,7 @@ func (gs *Server) processProposal(ctx context.Context, plan *plan, endorser *end
 	go func() {
 		defer close(done)
 		logger.Debugw("Sending to endorser:", "MSPID", endorser.mspid, "endpoint", endorser.address)
-		ctx, cancel := context.WithTimeout(ctx, gs.options.EndorsementTimeout) // timeout of individual endorsement
+		ctx, cancel := context.WithTimeout(ctx, gs.endorsementTimeout()) // timeout of individual endorsement
 		defer cancel()
 		response, err := endorser.client.ProcessProposal(ctx, signedProposal)
 		done <- &ppResponse{response: response, err: err}
@@ -200,7 +203,7 @@ func (gs *Server) planFromFirstEndorser(ctx context.Context, channel string, cha
 		go func() {
 			defer close(done)
 
-			ctx, cancel := context.WithTimeout(ctx, gs.options.EndorsementTimeout)
+			ctx, cancel := context.WithTimeout(ctx, gs.endorsementTimeout())
 			defer cancel()
 			firstResponse, err = firstEndorser.client.ProcessProposal(ctx, signedProposal)
 			code, message, _, remove := responseStatus(firstResponse, err)
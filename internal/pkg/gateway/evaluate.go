@@ -59,7 +59,7 @@ func (gs *Server) Evaluate(ctx context.Context, request *gp.EvaluateRequest) (*g
 		done := make(chan error)
 		go func() {
 			defer close(done)
-			ctx, cancel := context.WithTimeout(ctx, gs.options.EndorsementTimeout)
+			ctx, cancel := context.WithTimeout(ctx, gs.endorsementTimeout())
 			defer cancel()
 			pr, err := endorser.client.ProcessProposal(ctx, signedProposal)
 			code, message, retry, remove := responseStatus(pr, err)
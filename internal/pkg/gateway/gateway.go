@@ -7,10 +7,13 @@ package gateway
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	peerproto "github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/scc"
 	gdiscovery "github.com/hyperledger/fabric/gossip/discovery"
@@ -30,9 +33,37 @@ type Server struct {
 	commitFinder     CommitFinder
 	policy           ACLChecker
 	options          config.Options
+	optionsLock      sync.RWMutex
 	logger           *flogging.FabricLogger
 	ledgerProvider   ledger.Provider
 	getChannelConfig channelConfigGetter
+	tracker          *txTracker
+}
+
+// SetEndorsementTimeout changes the maximum time to wait for endorsement responses, from the next request on.
+func (gs *Server) SetEndorsementTimeout(timeout time.Duration) {
+	gs.optionsLock.Lock()
+	defer gs.optionsLock.Unlock()
+	gs.options.EndorsementTimeout = timeout
+}
+
+// SetBroadcastTimeout changes the maximum time to wait for responses from ordering nodes, from the next request on.
+func (gs *Server) SetBroadcastTimeout(timeout time.Duration) {
+	gs.optionsLock.Lock()
+	defer gs.optionsLock.Unlock()
+	gs.options.BroadcastTimeout = timeout
+}
+
+func (gs *Server) endorsementTimeout() time.Duration {
+	gs.optionsLock.RLock()
+	defer gs.optionsLock.RUnlock()
+	return gs.options.EndorsementTimeout
+}
+
+func (gs *Server) broadcastTimeout() time.Duration {
+	gs.optionsLock.RLock()
+	defer gs.optionsLock.RUnlock()
+	return gs.options.BroadcastTimeout
 }
 
 type EndorserServerAdapter struct {
@@ -53,7 +84,8 @@ type ACLChecker interface {
 
 type channelConfigGetter func(cid string) channelconfig.Resources
 
-// CreateServer creates an embedded instance of the Gateway.
+// CreateServer creates an embedded instance of the Gateway. The notifier must have been created for the same peer and
+// may be shared with other consumers of the peer's commit notifications.
 func CreateServer(
 	localEndorser peerproto.EndorserServer,
 	discovery Discovery,
@@ -63,11 +95,12 @@ func CreateServer(
 	localMSPID string,
 	options config.Options,
 	systemChaincodes scc.BuiltinSCCs,
+	notifier *commit.Notifier,
+	metricsProvider metrics.Provider,
 ) *Server {
 	adapter := &ledger.PeerAdapter{
 		Peer: peerInstance,
 	}
-	notifier := commit.NewNotifier(adapter)
 
 	server := newServer(
 		&EndorserServerAdapter{
@@ -86,6 +119,8 @@ func CreateServer(
 		peerInstance.GetChannelConfig,
 	)
 
+	server.tracker = newTxTracker(notifier, NewMetrics(metricsProvider))
+
 	peerInstance.AddConfigCallbacks(server.registry.configUpdate)
 
 	return server
@@ -0,0 +1,32 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/internal/pkg/gateway/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTimeouts(t *testing.T) {
+	server := &Server{
+		options: config.Options{
+			EndorsementTimeout: 30 * time.Second,
+			BroadcastTimeout:   30 * time.Second,
+		},
+	}
+
+	server.SetEndorsementTimeout(5 * time.Second)
+	require.Equal(t, 5*time.Second, server.endorsementTimeout())
+	require.Equal(t, 30*time.Second, server.broadcastTimeout())
+
+	server.SetBroadcastTimeout(10 * time.Second)
+	require.Equal(t, 5*time.Second, server.endorsementTimeout())
+	require.Equal(t, 10*time.Second, server.broadcastTimeout())
+}
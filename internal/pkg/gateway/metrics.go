@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import "github.com/hyperledger/fabric/common/metrics"
+
+var (
+	endorseToCommitDurationOpts = metrics.HistogramOpts{
+		Namespace:    "gateway",
+		Name:         "tx_endorse_to_commit_duration",
+		Help:         "The time from the endorsement request of a transaction to its commit to the ledger, in seconds.",
+		LabelNames:   []string{"channel", "chaincode", "validation_code"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}.%{validation_code}",
+		Buckets:      []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}
+
+	submitToCommitDurationOpts = metrics.HistogramOpts{
+		Namespace:    "gateway",
+		Name:         "tx_submit_to_commit_duration",
+		Help:         "The time from the submission of a transaction to the ordering service to its commit to the ledger, in seconds.",
+		LabelNames:   []string{"channel", "chaincode", "validation_code"},
+		StatsdFormat: "%{#fqname}.%{channel}.%{chaincode}.%{validation_code}",
+		Buckets:      []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}
+)
+
+// Metrics holds the metrics of the lifecycle of the transactions submitted through the gateway.
+// The histograms carry the ID of the transaction as an exemplar when the metrics provider supports exemplars.
+type Metrics struct {
+	EndorseToCommitDuration metrics.Histogram
+	SubmitToCommitDuration  metrics.Histogram
+}
+
+// NewMetrics creates the gateway metrics.
+func NewMetrics(p metrics.Provider) *Metrics {
+	return &Metrics{
+		EndorseToCommitDuration: p.NewHistogram(endorseToCommitDurationOpts),
+		SubmitToCommitDuration:  p.NewHistogram(submitToCommitDurationOpts),
+	}
+}
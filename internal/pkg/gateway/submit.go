@@ -28,10 +28,16 @@ import (
 // Submit will send the signed transaction to the ordering service. The response indicates whether the transaction was
 // successfully received by the orderer. This does not imply successful commit of the transaction, only that is has
 // been delivered to the orderer.
-func (gs *Server) Submit(ctx context.Context, request *gp.SubmitRequest) (*gp.SubmitResponse, error) {
+func (gs *Server) Submit(ctx context.Context, request *gp.SubmitRequest) (response *gp.SubmitResponse, err error) {
 	if request == nil {
 		return nil, status.Error(codes.InvalidArgument, "a submit request is required")
 	}
+	// The transaction, which is tracked since its endorsement, is not committed if its submission fails
+	defer func() {
+		if err != nil {
+			gs.tracker.abandon(request.ChannelId, request.TransactionId)
+		}
+	}()
 	txn := request.GetPreparedTransaction()
 	if txn == nil {
 		return nil, status.Error(codes.InvalidArgument, "a prepared transaction is required")
@@ -50,11 +56,12 @@ func (gs *Server) Submit(ctx context.Context, request *gp.SubmitRequest) (*gp.Su
 
 	logger := logger.With("txID", request.TransactionId)
 	config := gs.getChannelConfig(request.ChannelId)
+
+	gs.tracker.submitting(request.ChannelId, request.TransactionId)
 	if config.ChannelConfig().Capabilities().ConsensusTypeBFT() {
 		return gs.submitBFT(ctx, orderers, txn, clusterSize, logger)
-	} else {
-		return gs.submitNonBFT(ctx, orderers, txn, logger)
 	}
+	return gs.submitNonBFT(ctx, orderers, txn, logger)
 }
 
 func (gs *Server) submitBFT(ctx context.Context, orderers []*orderer, txn *common.Envelope, clusterSize int, logger *flogging.FabricLogger) (*gp.SubmitResponse, error) {
@@ -126,7 +133,7 @@ func (gs *Server) broadcastToAll(orderers []*orderer, txn *common.Envelope, wait
 		}(o)
 	}
 
-	t1 := time.NewTimer(gs.options.BroadcastTimeout)
+	t1 := time.NewTimer(gs.broadcastTimeout())
 	defer t1.Stop()
 	select {
 	case <-everyoneSubmitted:
@@ -169,7 +176,7 @@ func (gs *Server) submitNonBFT(ctx context.Context, orderers []*orderer, txn *co
 		done := make(chan struct{})
 		go func() {
 			defer close(done)
-			ctx, cancel := context.WithTimeout(ctx, gs.options.BroadcastTimeout)
+			ctx, cancel := context.WithTimeout(ctx, gs.broadcastTimeout())
 			defer cancel()
 
 			response, err = gs.broadcast(ctx, orderer, txn)
@@ -0,0 +1,232 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/ledger"
+)
+
+const (
+	// maxTrackedTransactions bounds the number of transactions awaiting their commit,
+	// so that transactions which are never committed cannot exhaust the memory.
+	maxTrackedTransactions = 100000
+
+	// trackingTimeout is the time after which a transaction that has not been
+	// committed is no longer tracked.
+	trackingTimeout = 10 * time.Minute
+
+	// purgeInterval is the interval in which the transactions that have not been
+	// committed in due time are purged.
+	purgeInterval = time.Minute
+)
+
+// BlockNotifier notifies of the blocks committed to the ledger of a channel.
+type BlockNotifier interface {
+	NotifyBlocks(done <-chan struct{}, channelName string) (<-chan *ledger.CommitNotification, error)
+}
+
+type trackedTx struct {
+	chaincode string
+	endorsed  time.Time
+	submitted time.Time
+}
+
+// txTracker correlates the transactions endorsed and submitted through the gateway with their commit
+// to the ledger, and records the latency of their lifecycle. A nil txTracker tracks nothing.
+type txTracker struct {
+	notifier BlockNotifier
+	metrics  *Metrics
+	now      func() time.Time
+
+	lock     sync.Mutex
+	pending  map[string]map[string]*trackedTx // by channel, then by transaction ID
+	count    int
+	watching map[string]struct{}
+	done     chan struct{}
+}
+
+func newTxTracker(notifier BlockNotifier, metrics *Metrics) *txTracker {
+	t := &txTracker{
+		notifier: notifier,
+		metrics:  metrics,
+		now:      time.Now,
+		pending:  map[string]map[string]*trackedTx{},
+		watching: map[string]struct{}{},
+		done:     make(chan struct{}),
+	}
+	go t.purgePeriodically(purgeInterval)
+	return t
+}
+
+// endorsed records that the transaction of the given chaincode, whose endorsement was requested
+// at the given time, has been endorsed. Transactions whose endorsement failed are not tracked.
+func (t *txTracker) endorsed(channelID, chaincode, txID string, requested time.Time) {
+	if t == nil || txID == "" {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if tx := t.track(channelID, txID); tx != nil {
+		tx.chaincode = chaincode
+		tx.endorsed = requested
+	}
+}
+
+// submitting records that the transaction is about to be submitted to the ordering service, and watches the
+// channel for its commit. It must be called before the transaction is submitted, so that its commit is not missed.
+func (t *txTracker) submitting(channelID, txID string) {
+	if t == nil || txID == "" {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.watching[channelID]; !ok {
+		blocks, err := t.notifier.NotifyBlocks(t.done, channelID)
+		if err != nil {
+			logger.Warnw("Failed to watch for committed blocks, not tracking transaction", "channel", channelID, "txID", txID, "error", err)
+			t.untrack(channelID, txID)
+			return
+		}
+		t.watching[channelID] = struct{}{}
+		go t.watch(channelID, blocks)
+	}
+
+	if tx := t.track(channelID, txID); tx != nil {
+		tx.submitted = t.now()
+	}
+}
+
+// abandon stops tracking the transaction, as its submission to the ordering service failed.
+func (t *txTracker) abandon(channelID, txID string) {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.untrack(channelID, txID)
+}
+
+// track returns the tracked transaction, which is tracked from now on if it was not yet,
+// or nil if too many transactions are tracked already.
+func (t *txTracker) track(channelID, txID string) *trackedTx {
+	txs, ok := t.pending[channelID]
+	if !ok {
+		txs = map[string]*trackedTx{}
+		t.pending[channelID] = txs
+	}
+	if tx, ok := txs[txID]; ok {
+		return tx
+	}
+	if t.count >= maxTrackedTransactions {
+		logger.Debugw("Too many transactions awaiting their commit, not tracking transaction", "channel", channelID, "txID", txID)
+		return nil
+	}
+	tx := &trackedTx{}
+	txs[txID] = tx
+	t.count++
+	return tx
+}
+
+func (t *txTracker) untrack(channelID, txID string) {
+	if _, ok := t.pending[channelID][txID]; ok {
+		delete(t.pending[channelID], txID)
+		t.count--
+	}
+}
+
+// watch records the latency of the tracked transactions of each committed block. Once the notifications
+// stop, the transactions of the channel are no longer tracked and it is watched again on the next submit.
+func (t *txTracker) watch(channelID string, blocks <-chan *ledger.CommitNotification) {
+	for block := range blocks {
+		t.committed(channelID, block)
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	delete(t.watching, channelID)
+	t.count -= len(t.pending[channelID])
+	delete(t.pending, channelID)
+}
+
+func (t *txTracker) committed(channelID string, block *ledger.CommitNotification) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.now()
+	txs := t.pending[channelID]
+	for _, txInfo := range block.TxsInfo {
+		tx, ok := txs[txInfo.TxID]
+		if !ok || tx.submitted.IsZero() {
+			continue
+		}
+		t.untrack(channelID, txInfo.TxID)
+
+		chaincode := tx.chaincode
+		if chaincode == "" {
+			chaincode = txInfo.ChaincodeID.GetName()
+		}
+		labels := []string{
+			"channel", channelID,
+			"chaincode", chaincode,
+			"validation_code", txInfo.ValidationCode.String(),
+		}
+		exemplar := map[string]string{"tx_id": txInfo.TxID}
+
+		metrics.ObserveWithExemplar(t.metrics.SubmitToCommitDuration.With(labels...), now.Sub(tx.submitted).Seconds(), exemplar)
+		if !tx.endorsed.IsZero() {
+			metrics.ObserveWithExemplar(t.metrics.EndorseToCommitDuration.With(labels...), now.Sub(tx.endorsed).Seconds(), exemplar)
+		}
+	}
+}
+
+// purgePeriodically purges the transactions that are not committed in due time, in the given interval,
+// so that they do not take the room of other transactions when too many transactions are tracked.
+func (t *txTracker) purgePeriodically(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			t.purge()
+		case <-t.done:
+			return
+		}
+	}
+}
+
+// purge stops tracking the transactions which are not committed in due time, such as those
+// rejected by the ordering service, those that are never submitted, and those of channels
+// that no block is committed to.
+func (t *txTracker) purge() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := t.now()
+	for channelID, txs := range t.pending {
+		for txID, tx := range txs {
+			last := tx.submitted
+			if last.IsZero() {
+				last = tx.endorsed
+			}
+			if now.Sub(last) > trackingTimeout {
+				t.untrack(channelID, txID)
+			}
+		}
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package gateway
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/internal/pkg/gateway/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+//go:generate counterfeiter -o mocks/blocknotifier.go --fake-name BlockNotifier . blockNotifier
+type blockNotifier interface {
+	BlockNotifier
+}
+
+type trackerTest struct {
+	tracker      *txTracker
+	notifier     *mocks.BlockNotifier
+	blocks       chan *ledger.CommitNotification
+	endorseToCmt *metricsfakes.ExemplarHistogram
+	submitToCmt  *metricsfakes.ExemplarHistogram
+	now          time.Time
+}
+
+func newTrackerTest() *trackerTest {
+	tt := &trackerTest{
+		notifier:     &mocks.BlockNotifier{},
+		blocks:       make(chan *ledger.CommitNotification),
+		endorseToCmt: &metricsfakes.ExemplarHistogram{},
+		submitToCmt:  &metricsfakes.ExemplarHistogram{},
+		now:          time.Unix(1000, 0),
+	}
+	tt.notifier.NotifyBlocksReturns(tt.blocks, nil)
+	tt.endorseToCmt.WithReturns(tt.endorseToCmt)
+	tt.submitToCmt.WithReturns(tt.submitToCmt)
+	tt.tracker = newTxTracker(tt.notifier, &Metrics{
+		EndorseToCommitDuration: tt.endorseToCmt,
+		SubmitToCommitDuration:  tt.submitToCmt,
+	})
+	tt.tracker.now = func() time.Time { return tt.now }
+	return tt
+}
+
+// commit delivers the block to the tracker, and waits until it has been processed.
+func (tt *trackerTest) commit(block *ledger.CommitNotification) {
+	tt.blocks <- block
+	// the notification channel is unbuffered, hence the previous block has been processed once the next one is received
+	tt.blocks <- &ledger.CommitNotification{}
+}
+
+func (tt *trackerTest) pending() int {
+	tt.tracker.lock.Lock()
+	defer tt.tracker.lock.Unlock()
+	return tt.tracker.count
+}
+
+func TestTxTracker(t *testing.T) {
+	t.Run("records the latency of committed transactions", func(t *testing.T) {
+		tt := newTrackerTest()
+
+		tt.tracker.endorsed("mychannel", "basic", "tx1", tt.now)
+		tt.now = tt.now.Add(time.Second)
+		tt.tracker.submitting("mychannel", "tx1")
+		tt.tracker.submitting("mychannel", "tx2")
+		require.Equal(t, 2, tt.pending())
+
+		require.Equal(t, 1, tt.notifier.NotifyBlocksCallCount())
+		_, channelID := tt.notifier.NotifyBlocksArgsForCall(0)
+		require.Equal(t, "mychannel", channelID)
+
+		tt.now = tt.now.Add(2 * time.Second)
+		tt.commit(&ledger.CommitNotification{
+			BlockNumber: 5,
+			TxsInfo: []*ledger.CommitNotificationTxInfo{
+				{TxID: "tx1", ValidationCode: peer.TxValidationCode_VALID, ChaincodeID: &peer.ChaincodeID{Name: "_lifecycle"}},
+				{TxID: "other", ValidationCode: peer.TxValidationCode_VALID},
+				{TxID: "tx2", ValidationCode: peer.TxValidationCode_MVCC_READ_CONFLICT, ChaincodeID: &peer.ChaincodeID{Name: "asset"}},
+			},
+		})
+		require.Equal(t, 0, tt.pending())
+
+		require.Equal(t, 2, tt.submitToCmt.ObserveWithExemplarCallCount())
+		require.Equal(t, []string{"channel", "mychannel", "chaincode", "basic", "validation_code", "VALID"}, tt.submitToCmt.WithArgsForCall(0))
+		value, exemplar := tt.submitToCmt.ObserveWithExemplarArgsForCall(0)
+		require.Equal(t, 2.0, value)
+		require.Equal(t, map[string]string{"tx_id": "tx1"}, exemplar)
+		// the chaincode of a transaction whose endorsement was not seen is the one it was committed for
+		require.Equal(t, []string{"channel", "mychannel", "chaincode", "asset", "validation_code", "MVCC_READ_CONFLICT"}, tt.submitToCmt.WithArgsForCall(1))
+		value, exemplar = tt.submitToCmt.ObserveWithExemplarArgsForCall(1)
+		require.Equal(t, 2.0, value)
+		require.Equal(t, map[string]string{"tx_id": "tx2"}, exemplar)
+
+		require.Equal(t, 1, tt.endorseToCmt.ObserveWithExemplarCallCount())
+		require.Equal(t, []string{"channel", "mychannel", "chaincode", "basic", "validation_code", "VALID"}, tt.endorseToCmt.WithArgsForCall(0))
+		value, exemplar = tt.endorseToCmt.ObserveWithExemplarArgsForCall(0)
+		require.Equal(t, 3.0, value)
+		require.Equal(t, map[string]string{"tx_id": "tx1"}, exemplar)
+	})
+
+	t.Run("ignores transactions which were not submitted", func(t *testing.T) {
+		tt := newTrackerTest()
+
+		tt.tracker.endorsed("mychannel", "basic", "tx1", tt.now)
+		tt.tracker.submitting("mychannel", "tx2")
+		tt.commit(&ledger.CommitNotification{
+			TxsInfo: []*ledger.CommitNotificationTxInfo{{TxID: "tx1"}},
+		})
+		require.Equal(t, 0, tt.submitToCmt.ObserveWithExemplarCallCount())
+		require.Equal(t, 2, tt.pending())
+
+		tt.tracker.abandon("mychannel", "tx2")
+		require.Equal(t, 1, tt.pending())
+	})
+
+	t.Run("stops tracking transactions which are not committed in due time", func(t *testing.T) {
+		tt := newTrackerTest()
+
+		tt.tracker.endorsed("mychannel", "basic", "tx1", tt.now)
+		tt.tracker.submitting("mychannel", "tx2")
+		tt.tracker.endorsed("otherchannel", "basic", "tx4", tt.now)
+		tt.now = tt.now.Add(trackingTimeout / 2)
+		tt.tracker.submitting("mychannel", "tx3")
+		tt.now = tt.now.Add(trackingTimeout/2 + time.Second)
+
+		// committing a block does not purge them
+		tt.commit(&ledger.CommitNotification{})
+		require.Equal(t, 4, tt.pending())
+
+		tt.tracker.purge()
+		require.Equal(t, 1, tt.pending())
+		require.Contains(t, tt.tracker.pending["mychannel"], "tx3")
+	})
+
+	t.Run("resumes tracking once stale transactions are purged", func(t *testing.T) {
+		tt := newTrackerTest()
+		tt.tracker.count = maxTrackedTransactions - 1
+
+		tt.tracker.endorsed("mychannel", "basic", "tx1", tt.now)
+		tt.tracker.endorsed("mychannel", "basic", "tx2", tt.now)
+		require.Equal(t, maxTrackedTransactions, tt.pending())
+		require.NotContains(t, tt.tracker.pending["mychannel"], "tx2")
+
+		tt.tracker.count = 1
+		tt.now = tt.now.Add(trackingTimeout + time.Second)
+		tt.tracker.purge()
+		tt.tracker.endorsed("mychannel", "basic", "tx2", tt.now)
+		require.Equal(t, 1, tt.pending())
+		require.Contains(t, tt.tracker.pending["mychannel"], "tx2")
+	})
+
+	t.Run("purges periodically", func(t *testing.T) {
+		tt := newTrackerTest()
+		tt.tracker.endorsed("mychannel", "basic", "tx1", tt.now)
+		tt.tracker.lock.Lock()
+		tt.now = tt.now.Add(trackingTimeout + time.Second)
+		tt.tracker.lock.Unlock()
+
+		go tt.tracker.purgePeriodically(10 * time.Millisecond)
+		require.Eventually(t, func() bool { return tt.pending() == 0 }, time.Second, 10*time.Millisecond)
+		close(tt.tracker.done)
+	})
+
+	t.Run("stops tracking the channel when the notifications stop", func(t *testing.T) {
+		tt := newTrackerTest()
+
+		tt.tracker.submitting("mychannel", "tx1")
+		close(tt.blocks)
+		require.Eventually(t, func() bool { return tt.pending() == 0 }, time.Second, 10*time.Millisecond)
+
+		tt.blocks = make(chan *ledger.CommitNotification)
+		tt.notifier.NotifyBlocksReturns(tt.blocks, nil)
+		require.Eventually(t, func() bool {
+			tt.tracker.submitting("mychannel", "tx2")
+			return tt.notifier.NotifyBlocksCallCount() == 2
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("does not track transactions when the channel cannot be watched", func(t *testing.T) {
+		tt := newTrackerTest()
+		tt.notifier.NotifyBlocksReturns(nil, errors.New("no such ledger"))
+
+		tt.tracker.endorsed("mychannel", "basic", "tx1", tt.now)
+		tt.tracker.submitting("mychannel", "tx1")
+		require.Equal(t, 0, tt.pending())
+	})
+
+	t.Run("nil tracker", func(t *testing.T) {
+		var tracker *txTracker
+		tracker.endorsed("mychannel", "basic", "tx1", time.Now())
+		tracker.submitting("mychannel", "tx1")
+		tracker.abandon("mychannel", "tx1")
+	})
+}
@@ -9,6 +9,7 @@ package blocksprovider
 import (
 	"context"
 	"math"
+	"sync"
 	"time"
 
 	"github.com/hyperledger/fabric-protos-go/common"
@@ -106,6 +107,9 @@ type Deliverer struct {
 	TLSCertHash []byte // util.ComputeSHA256(b.credSupport.GetClientCertificate().Certificate[0])
 
 	sleeper sleeper
+
+	// retryLock guards MaxRetryDelay and MaxRetryDuration once delivery has started
+	retryLock sync.Mutex
 }
 
 const backoffExponentBase = 1.2
@@ -116,11 +120,6 @@ func (d *Deliverer) DeliverBlocks() {
 	failureCounter := 0
 	totalDuration := time.Duration(0)
 
-	// InitialRetryDelay * backoffExponentBase^n > MaxRetryDelay
-	// backoffExponentBase^n > MaxRetryDelay / InitialRetryDelay
-	// n * log(backoffExponentBase) > log(MaxRetryDelay / InitialRetryDelay)
-	// n > log(MaxRetryDelay / InitialRetryDelay) / log(backoffExponentBase)
-	maxFailures := int(math.Log(float64(d.MaxRetryDelay)/float64(d.InitialRetryDelay)) / math.Log(backoffExponentBase))
 	for {
 		select {
 		case <-d.DoneC:
@@ -129,16 +128,25 @@ func (d *Deliverer) DeliverBlocks() {
 		}
 
 		if failureCounter > 0 {
+			// the thresholds may change at runtime, so they are read on every retry
+			maxRetryDelay, maxRetryDuration := d.retryThresholds()
+
+			// InitialRetryDelay * backoffExponentBase^n > MaxRetryDelay
+			// backoffExponentBase^n > MaxRetryDelay / InitialRetryDelay
+			// n * log(backoffExponentBase) > log(MaxRetryDelay / InitialRetryDelay)
+			// n > log(MaxRetryDelay / InitialRetryDelay) / log(backoffExponentBase)
+			maxFailures := int(math.Log(float64(maxRetryDelay)/float64(d.InitialRetryDelay)) / math.Log(backoffExponentBase))
+
 			var sleepDuration time.Duration
 			if failureCounter-1 > maxFailures {
-				sleepDuration = d.MaxRetryDelay // configured from peer.deliveryclient.reConnectBackoffThreshold
+				sleepDuration = maxRetryDelay // configured from peer.deliveryclient.reConnectBackoffThreshold
 			} else {
 				sleepDuration = time.Duration(math.Pow(backoffExponentBase, float64(failureCounter-1))*100) * time.Millisecond
 			}
 			totalDuration += sleepDuration
-			if totalDuration > d.MaxRetryDuration {
+			if totalDuration > maxRetryDuration {
 				if d.YieldLeadership {
-					d.Logger.Warningf("attempted to retry block delivery for more than peer.deliveryclient.reconnectTotalTimeThreshold duration %v, giving up", d.MaxRetryDuration)
+					d.Logger.Warningf("attempted to retry block delivery for more than peer.deliveryclient.reconnectTotalTimeThreshold duration %v, giving up", maxRetryDuration)
 					return
 				}
 				d.Logger.Warningf("peer is a static leader, ignoring peer.deliveryclient.reconnectTotalTimeThreshold")
@@ -273,6 +281,22 @@ func (d *Deliverer) processMsg(msg *orderer.DeliverResponse) error {
 	}
 }
 
+// SetRetryThresholds changes the maximum delay between attempts to reconnect
+// to the ordering service and the total time the attempts may take, from the
+// next attempt on.
+func (d *Deliverer) SetRetryThresholds(maxRetryDelay, maxRetryDuration time.Duration) {
+	d.retryLock.Lock()
+	defer d.retryLock.Unlock()
+	d.MaxRetryDelay = maxRetryDelay
+	d.MaxRetryDuration = maxRetryDuration
+}
+
+func (d *Deliverer) retryThresholds() (time.Duration, time.Duration) {
+	d.retryLock.Lock()
+	defer d.retryLock.Unlock()
+	return d.MaxRetryDelay, d.MaxRetryDuration
+}
+
 // Stop stops blocks delivery provider
 func (d *Deliverer) Stop() {
 	// this select is not race-safe, but it prevents a panic
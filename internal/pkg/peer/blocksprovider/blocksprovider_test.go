@@ -299,6 +299,21 @@ var _ = Describe("Blocksprovider", func() {
 		})
 	})
 
+	When("the retry thresholds are changed", func() {
+		BeforeEach(func() {
+			fakeDeliverStreamer.DeliverReturns(nil, fmt.Errorf("deliver-error"))
+			fakeDeliverStreamer.DeliverReturnsOnCall(50, fakeDeliverClient, nil)
+			d.SetRetryThresholds(time.Second, time.Hour)
+		})
+
+		It("hits the changed maximum sleep time value", func() {
+			Eventually(fakeSleeper.SleepCallCount).Should(Equal(50))
+			Expect(fakeSleeper.SleepArgsForCall(12)).To(Equal(891 * time.Millisecond))
+			Expect(fakeSleeper.SleepArgsForCall(13)).To(Equal(time.Second))
+			Expect(fakeSleeper.SleepArgsForCall(49)).To(Equal(time.Second))
+		})
+	})
+
 	When("an error occurs, then a block is successfully delivered", func() {
 		BeforeEach(func() {
 			fakeDeliverStreamer.DeliverReturnsOnCall(0, nil, fmt.Errorf("deliver-error"))
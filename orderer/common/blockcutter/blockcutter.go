@@ -9,6 +9,7 @@ package blockcutter
 import (
 	"time"
 
+	"code.cloudfoundry.org/clock"
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
@@ -29,12 +30,31 @@ type Receiver interface {
 
 	// Cut returns the current batch and starts a new one
 	Cut() []*cb.Envelope
+
+	// Deadline returns the time by which the pending batch must be cut to
+	// honor the maximum delay of the transaction classes of its messages,
+	// or false if none of its messages belongs to a transaction class.
+	// Only the etcdraft chain honors it, as SmartBFT batches the requests
+	// of its own request pool.
+	Deadline() (time.Time, bool)
+}
+
+// classEntry records when a message of a transaction class was enqueued
+type classEntry struct {
+	class    string
+	enqueued time.Time
 }
 
 type receiver struct {
 	sharedConfigFetcher   OrdererConfigFetcher
 	pendingBatch          []*cb.Envelope
 	pendingBatchSizeBytes uint32
+	txReordering          bool
+	classifier            *txClassifier
+	pendingClassEntries   []classEntry
+	pendingDeadline       time.Time
+	pendingDeadlineClass  string
+	clock                 clock.Clock
 
 	PendingBatchStartTime time.Time
 	ChannelID             string
@@ -42,9 +62,12 @@ type receiver struct {
 }
 
 // NewReceiverImpl creates a Receiver implementation based on the given configtxorderer manager
-func NewReceiverImpl(channelID string, sharedConfigFetcher OrdererConfigFetcher, metrics *Metrics) Receiver {
+// and transaction classes. The clock times the pending batches and the deadlines of the classes.
+func NewReceiverImpl(channelID string, sharedConfigFetcher OrdererConfigFetcher, metrics *Metrics, txClasses []TxClass, clock clock.Clock) Receiver {
 	return &receiver{
 		sharedConfigFetcher: sharedConfigFetcher,
+		classifier:          newTxClassifier(txClasses),
+		clock:               clock,
 		Metrics:             metrics,
 		ChannelID:           channelID,
 	}
@@ -61,6 +84,9 @@ func NewReceiverImpl(channelID string, sharedConfigFetcher OrdererConfigFetcher,
 // messageBatches length: 1, pending: false
 //   - the message count reaches BatchSize.MaxMessageCount
 //
+// messageBatches length: 1, pending: false
+//   - the maximum delay of a transaction class of the pending messages has expired.
+//
 // messageBatches length: 1, pending: true
 //   - the current message will cause the pending batch size in bytes to exceed BatchSize.PreferredMaxBytes.
 //
@@ -74,7 +100,7 @@ func NewReceiverImpl(channelID string, sharedConfigFetcher OrdererConfigFetcher,
 func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, pending bool) {
 	if len(r.pendingBatch) == 0 {
 		// We are beginning a new batch, mark the time
-		r.PendingBatchStartTime = time.Now()
+		r.PendingBatchStartTime = r.clock.Now()
 	}
 
 	ordererConfig, ok := r.sharedConfigFetcher.OrdererConfig()
@@ -83,6 +109,9 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 	}
 
 	batchSize := ordererConfig.BatchSize()
+	r.txReordering = txReorderingEnabled(ordererConfig)
+
+	class, classified := r.classifier.classify(msg)
 
 	messageSizeBytes := messageSizeBytes(msg)
 	if messageSizeBytes > batchSize.PreferredMaxBytes {
@@ -99,6 +128,9 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 
 		// Record that this batch took no time to fill
 		r.Metrics.BlockFillDuration.With("channel", r.ChannelID).Observe(0)
+		if classified {
+			r.Metrics.TxClassBatchingDelay.With("channel", r.ChannelID, "class", class.Name).Observe(0)
+		}
 
 		return
 	}
@@ -109,7 +141,7 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 		logger.Debugf("The current message, with %v bytes, will overflow the pending batch of %v bytes.", messageSizeBytes, r.pendingBatchSizeBytes)
 		logger.Debugf("Pending batch would overflow if current message is added, cutting batch now.")
 		messageBatch := r.Cut()
-		r.PendingBatchStartTime = time.Now()
+		r.PendingBatchStartTime = r.clock.Now()
 		messageBatches = append(messageBatches, messageBatch)
 	}
 
@@ -118,28 +150,83 @@ func (r *receiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, p
 	r.pendingBatchSizeBytes += messageSizeBytes
 	pending = true
 
+	if classified {
+		r.enqueueClassEntry(class)
+	}
+
 	if uint32(len(r.pendingBatch)) >= batchSize.MaxMessageCount {
 		logger.Debugf("Batch size met, cutting batch")
 		messageBatch := r.Cut()
 		messageBatches = append(messageBatches, messageBatch)
 		pending = false
+	} else if r.deadlineExpired() {
+		logger.Debugf("Maximum delay of transaction class %s expired, cutting batch", r.pendingDeadlineClass)
+		messageBatch := r.Cut()
+		messageBatches = append(messageBatches, messageBatch)
+		pending = false
 	}
 
 	return
 }
 
+// enqueueClassEntry records that a message of the class was enqueued, and
+// brings the deadline of the pending batch forward if the class requires it.
+func (r *receiver) enqueueClassEntry(class *TxClass) {
+	now := r.clock.Now()
+	r.pendingClassEntries = append(r.pendingClassEntries, classEntry{class: class.Name, enqueued: now})
+	deadline := now.Add(class.MaxDelay)
+	if r.pendingDeadline.IsZero() || deadline.Before(r.pendingDeadline) {
+		r.pendingDeadline = deadline
+		r.pendingDeadlineClass = class.Name
+	}
+}
+
+func (r *receiver) deadlineExpired() bool {
+	return !r.pendingDeadline.IsZero() && !r.clock.Now().Before(r.pendingDeadline)
+}
+
+// Deadline returns the time by which the pending batch must be cut to honor
+// the maximum delay of the transaction classes of its messages.
+func (r *receiver) Deadline() (time.Time, bool) {
+	return r.pendingDeadline, !r.pendingDeadline.IsZero()
+}
+
 // Cut returns the current batch and starts a new one
 func (r *receiver) Cut() []*cb.Envelope {
 	if r.pendingBatch != nil {
-		r.Metrics.BlockFillDuration.With("channel", r.ChannelID).Observe(time.Since(r.PendingBatchStartTime).Seconds())
+		r.Metrics.BlockFillDuration.With("channel", r.ChannelID).Observe(r.clock.Since(r.PendingBatchStartTime).Seconds())
 	}
+	if r.deadlineExpired() {
+		r.Metrics.TxClassDeadlineCuts.With("channel", r.ChannelID, "class", r.pendingDeadlineClass).Add(1)
+	}
+	for _, entry := range r.pendingClassEntries {
+		r.Metrics.TxClassBatchingDelay.With("channel", r.ChannelID, "class", entry.class).Observe(r.clock.Since(entry.enqueued).Seconds())
+	}
+	r.pendingClassEntries = nil
+	r.pendingDeadline = time.Time{}
+	r.pendingDeadlineClass = ""
 	r.PendingBatchStartTime = time.Time{}
 	batch := r.pendingBatch
 	r.pendingBatch = nil
 	r.pendingBatchSizeBytes = 0
+	if r.txReordering && len(batch) > 1 {
+		var aborted int
+		batch, aborted = Reorder(batch)
+		if aborted > 0 {
+			logger.Debugf("Moved %d conflicting transactions to the end of the batch", aborted)
+			r.Metrics.ReorderingAbortedTxs.With("channel", r.ChannelID).Add(float64(aborted))
+		}
+	}
 	return batch
 }
 
+// txReorderingEnabled returns whether the transactions of a batch are
+// reordered to reduce the intra-block MVCC conflicts.
+func txReorderingEnabled(ordererConfig channelconfig.Orderer) bool {
+	capabilities := ordererConfig.Capabilities()
+	return capabilities != nil && capabilities.TxReordering()
+}
+
 func messageSizeBytes(message *cb.Envelope) uint32 {
 	return uint32(len(message.Payload) + len(message.Signature))
 }
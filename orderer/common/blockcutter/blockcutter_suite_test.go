@@ -22,6 +22,11 @@ type metricsHistogram interface {
 	metrics.Histogram
 }
 
+//go:generate counterfeiter -o mock/metrics_counter.go --fake-name MetricsCounter . metricsCounter
+type metricsCounter interface {
+	metrics.Counter
+}
+
 //go:generate counterfeiter -o mock/metrics_provider.go --fake-name MetricsProvider . metricsProvider
 type metricsProvider interface {
 	metrics.Provider
@@ -37,6 +42,11 @@ type ordererConfig interface {
 	channelconfig.Orderer
 }
 
+//go:generate counterfeiter -o mock/orderer_capabilities.go --fake-name OrdererCapabilities . ordererCapabilities
+type ordererCapabilities interface {
+	channelconfig.OrdererCapabilities
+}
+
 func TestBlockcutter(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Blockcutter Suite")
@@ -7,10 +7,12 @@ SPDX-License-Identifier: Apache-2.0
 package blockcutter_test
 
 import (
+	"code.cloudfoundry.org/clock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter/mock"
@@ -22,8 +24,9 @@ var _ = Describe("Blockcutter", func() {
 		fakeConfig        *mock.OrdererConfig
 		fakeConfigFetcher *mock.OrdererConfigFetcher
 
-		metrics               *blockcutter.Metrics
-		fakeBlockFillDuration *mock.MetricsHistogram
+		metrics                  *blockcutter.Metrics
+		fakeBlockFillDuration    *mock.MetricsHistogram
+		fakeReorderingAbortedTxs *mock.MetricsCounter
 	)
 
 	BeforeEach(func() {
@@ -33,11 +36,14 @@ var _ = Describe("Blockcutter", func() {
 
 		fakeBlockFillDuration = &mock.MetricsHistogram{}
 		fakeBlockFillDuration.WithReturns(fakeBlockFillDuration)
+		fakeReorderingAbortedTxs = &mock.MetricsCounter{}
+		fakeReorderingAbortedTxs.WithReturns(fakeReorderingAbortedTxs)
 		metrics = &blockcutter.Metrics{
-			BlockFillDuration: fakeBlockFillDuration,
+			BlockFillDuration:    fakeBlockFillDuration,
+			ReorderingAbortedTxs: fakeReorderingAbortedTxs,
 		}
 
-		bc = blockcutter.NewReceiverImpl("mychannel", fakeConfigFetcher, metrics)
+		bc = blockcutter.NewReceiverImpl("mychannel", fakeConfigFetcher, metrics, nil, clock.NewClock())
 	})
 
 	Describe("Ordered", func() {
@@ -186,5 +192,54 @@ var _ = Describe("Blockcutter", func() {
 			Expect(batch).To(BeNil())
 			Expect(fakeBlockFillDuration.ObserveCallCount()).To(Equal(0))
 		})
+
+		Context("when transaction reordering is enabled", func() {
+			var (
+				fakeCapabilities *mock.OrdererCapabilities
+				batch            []*cb.Envelope
+			)
+
+			BeforeEach(func() {
+				fakeConfig.BatchSizeReturns(&ab.BatchSize{
+					MaxMessageCount:   10,
+					PreferredMaxBytes: 10000,
+				})
+				fakeCapabilities = &mock.OrdererCapabilities{}
+				fakeCapabilities.TxReorderingReturns(true)
+				fakeConfig.CapabilitiesReturns(fakeCapabilities)
+
+				batch = []*cb.Envelope{
+					endorserTx(&kvrwset.KVRWSet{Reads: reads("a"), Writes: writes("b")}),
+					endorserTx(&kvrwset.KVRWSet{Reads: reads("b"), Writes: writes("a")}),
+					endorserTx(&kvrwset.KVRWSet{Reads: reads("c"), Writes: writes("b")}),
+				}
+				for _, env := range batch {
+					_, pending := bc.Ordered(env)
+					Expect(pending).To(BeTrue())
+				}
+			})
+
+			It("reorders the batch", func() {
+				Expect(bc.Cut()).To(Equal([]*cb.Envelope{batch[0], batch[2], batch[1]}))
+				Expect(fakeReorderingAbortedTxs.WithCallCount()).To(Equal(1))
+				Expect(fakeReorderingAbortedTxs.WithArgsForCall(0)).To(Equal([]string{"channel", "mychannel"}))
+				Expect(fakeReorderingAbortedTxs.AddCallCount()).To(Equal(1))
+				Expect(fakeReorderingAbortedTxs.AddArgsForCall(0)).To(Equal(float64(1)))
+			})
+
+			Context("when the capability is disabled", func() {
+				BeforeEach(func() {
+					fakeCapabilities.TxReorderingReturns(false)
+					_, pending := bc.Ordered(batch[0])
+					Expect(pending).To(BeTrue())
+					batch = append(batch, batch[0])
+				})
+
+				It("keeps the arrival order", func() {
+					Expect(bc.Cut()).To(Equal(batch))
+					Expect(fakeReorderingAbortedTxs.AddCallCount()).To(Equal(0))
+				})
+			})
+		})
 	})
 })
@@ -16,12 +16,42 @@ var blockFillDuration = metrics.HistogramOpts{
 	StatsdFormat: "%{#fqname}.%{channel}",
 }
 
+var reorderingAbortedTxs = metrics.CounterOpts{
+	Namespace:    "blockcutter",
+	Name:         "reordering_aborted_transactions",
+	Help:         "The number of conflicting transactions moved to the end of a block by the transaction reordering.",
+	LabelNames:   []string{"channel"},
+	StatsdFormat: "%{#fqname}.%{channel}",
+}
+
+var txClassBatchingDelay = metrics.HistogramOpts{
+	Namespace:    "blockcutter",
+	Name:         "tx_class_batching_delay",
+	Help:         "The time from a transaction of a transaction class being enqueued to its block being cut in seconds.",
+	LabelNames:   []string{"channel", "class"},
+	StatsdFormat: "%{#fqname}.%{channel}.%{class}",
+}
+
+var txClassDeadlineCuts = metrics.CounterOpts{
+	Namespace:    "blockcutter",
+	Name:         "tx_class_deadline_cuts",
+	Help:         "The number of blocks cut because the maximum delay of a transaction class expired.",
+	LabelNames:   []string{"channel", "class"},
+	StatsdFormat: "%{#fqname}.%{channel}.%{class}",
+}
+
 type Metrics struct {
-	BlockFillDuration metrics.Histogram
+	BlockFillDuration    metrics.Histogram
+	ReorderingAbortedTxs metrics.Counter
+	TxClassBatchingDelay metrics.Histogram
+	TxClassDeadlineCuts  metrics.Counter
 }
 
 func NewMetrics(p metrics.Provider) *Metrics {
 	return &Metrics{
-		BlockFillDuration: p.NewHistogram(blockFillDuration),
+		BlockFillDuration:    p.NewHistogram(blockFillDuration),
+		ReorderingAbortedTxs: p.NewCounter(reorderingAbortedTxs),
+		TxClassBatchingDelay: p.NewHistogram(txClassBatchingDelay),
+		TxClassDeadlineCuts:  p.NewCounter(txClassDeadlineCuts),
 	}
 }
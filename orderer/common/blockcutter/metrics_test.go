@@ -21,14 +21,19 @@ var _ = Describe("Metrics", func() {
 		BeforeEach(func() {
 			fakeProvider = &mock.MetricsProvider{}
 			fakeProvider.NewHistogramReturns(&mock.MetricsHistogram{})
+			fakeProvider.NewCounterReturns(&mock.MetricsCounter{})
 		})
 
 		It("uses the provider to initialize its field", func() {
 			metrics := blockcutter.NewMetrics(fakeProvider)
 			Expect(metrics).NotTo(BeNil())
 			Expect(metrics.BlockFillDuration).To(Equal(&mock.MetricsHistogram{}))
+			Expect(metrics.ReorderingAbortedTxs).To(Equal(&mock.MetricsCounter{}))
+			Expect(metrics.TxClassBatchingDelay).To(Equal(&mock.MetricsHistogram{}))
+			Expect(metrics.TxClassDeadlineCuts).To(Equal(&mock.MetricsCounter{}))
 
-			Expect(fakeProvider.NewHistogramCallCount()).To(Equal(1))
+			Expect(fakeProvider.NewHistogramCallCount()).To(Equal(2))
+			Expect(fakeProvider.NewCounterCallCount()).To(Equal(2))
 		})
 	})
 })
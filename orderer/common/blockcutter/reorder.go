@@ -0,0 +1,430 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockcutter
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// stateKey identifies a public key, or the hash of a private key when coll is set
+type stateKey struct {
+	ns, coll, key string
+}
+
+// keyRange is a range query over the public keys of a namespace. An empty end
+// denotes the end of the namespace.
+type keyRange struct {
+	ns, start, end string
+}
+
+func (r keyRange) contains(k stateKey) bool {
+	return k.coll == "" && k.ns == r.ns && k.key >= r.start && (r.end == "" || k.key < r.end)
+}
+
+// txAccesses holds the keys read and written by a transaction
+type txAccesses struct {
+	reads  []stateKey
+	ranges []keyRange
+	writes []stateKey
+}
+
+// extractAccesses returns the keys read and written by the endorser
+// transaction in the envelope.
+func extractAccesses(env *cb.Envelope) (*txAccesses, error) {
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("missing header")
+	}
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	if cb.HeaderType(chdr.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+		return nil, errors.Errorf("not an endorser transaction, header type %s", cb.HeaderType(chdr.Type))
+	}
+	tx, err := protoutil.UnmarshalTransaction(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	accesses := &txAccesses{}
+	for _, action := range tx.Actions {
+		ccActionPayload, err := protoutil.UnmarshalChaincodeActionPayload(action.Payload)
+		if err != nil {
+			return nil, err
+		}
+		if ccActionPayload.Action == nil {
+			return nil, errors.New("missing endorsed action")
+		}
+		prp, err := protoutil.UnmarshalProposalResponsePayload(ccActionPayload.Action.ProposalResponsePayload)
+		if err != nil {
+			return nil, err
+		}
+		ccAction, err := protoutil.UnmarshalChaincodeAction(prp.Extension)
+		if err != nil {
+			return nil, err
+		}
+		txRWSet := &rwset.TxReadWriteSet{}
+		if err := proto.Unmarshal(ccAction.Results, txRWSet); err != nil {
+			return nil, errors.Wrap(err, "error unmarshalling read-write set")
+		}
+		if err := accesses.add(txRWSet); err != nil {
+			return nil, err
+		}
+	}
+	return accesses, nil
+}
+
+func (a *txAccesses) add(txRWSet *rwset.TxReadWriteSet) error {
+	for _, nsRWSet := range txRWSet.NsRwset {
+		ns := nsRWSet.Namespace
+		kvRWSet := &kvrwset.KVRWSet{}
+		if err := proto.Unmarshal(nsRWSet.Rwset, kvRWSet); err != nil {
+			return errors.Wrapf(err, "error unmarshalling read-write set of namespace %s", ns)
+		}
+		for _, kvRead := range kvRWSet.Reads {
+			a.reads = append(a.reads, stateKey{ns: ns, key: kvRead.Key})
+		}
+		for _, rqi := range kvRWSet.RangeQueriesInfo {
+			a.ranges = append(a.ranges, keyRange{ns: ns, start: rqi.StartKey, end: rqi.EndKey})
+		}
+		for _, kvWrite := range kvRWSet.Writes {
+			a.writes = append(a.writes, stateKey{ns: ns, key: kvWrite.Key})
+		}
+		for _, kvMetadataWrite := range kvRWSet.MetadataWrites {
+			a.writes = append(a.writes, stateKey{ns: ns, key: kvMetadataWrite.Key})
+		}
+
+		for _, collRWSet := range nsRWSet.CollectionHashedRwset {
+			coll := collRWSet.CollectionName
+			hashedRWSet := &kvrwset.HashedRWSet{}
+			if err := proto.Unmarshal(collRWSet.HashedRwset, hashedRWSet); err != nil {
+				return errors.Wrapf(err, "error unmarshalling hashed read-write set of collection %s:%s", ns, coll)
+			}
+			for _, hashedRead := range hashedRWSet.HashedReads {
+				a.reads = append(a.reads, stateKey{ns: ns, coll: coll, key: string(hashedRead.KeyHash)})
+			}
+			for _, hashedWrite := range hashedRWSet.HashedWrites {
+				a.writes = append(a.writes, stateKey{ns: ns, coll: coll, key: string(hashedWrite.KeyHash)})
+			}
+			for _, metadataWrite := range hashedRWSet.MetadataWrites {
+				a.writes = append(a.writes, stateKey{ns: ns, coll: coll, key: string(metadataWrite.KeyHash)})
+			}
+		}
+	}
+	return nil
+}
+
+// maxReorderWindow is the maximum number of consecutive transactions of a
+// batch which are reordered together. It bounds the cost of breaking the
+// cycles of conflicts, which recomputes the strongly connected components of
+// a window after each aborted transaction.
+const maxReorderWindow = 256
+
+// Reorder reorders the transactions of a batch so as to minimize the number of
+// transactions that peers invalidate because of an MVCC read conflict with a
+// preceding transaction of the same block, in the style of Fabric++.
+//
+// A transaction reading a key (directly or through a range query) must precede
+// every other transaction of the batch writing that key, as the read of the
+// later transaction would be stale otherwise. When these constraints form a
+// cycle, not all the transactions of the cycle can be valid: the transaction
+// with the most conflicts in the cycle is aborted early by moving it to the end
+// of the batch, where the peers invalidate it, until no cycle is left. The
+// remaining transactions are sorted so as to satisfy the constraints, keeping
+// the arrival order wherever the constraints allow it. Envelopes which are not
+// endorser transactions are not subject to any constraint.
+//
+// Batches larger than maxReorderWindow are split into windows of consecutive
+// transactions which are reordered independently and keep their relative
+// order, so conflicts between windows are left to the peers.
+//
+// The result only depends on the content and the order of the batch, hence all
+// the orderers reordering the same batch produce the same block. The second
+// return value is the number of aborted transactions.
+func Reorder(batch []*cb.Envelope) ([]*cb.Envelope, int) {
+	reordered := make([]*cb.Envelope, 0, len(batch))
+	var aborted []*cb.Envelope
+	for start := 0; start < len(batch); start += maxReorderWindow {
+		end := start + maxReorderWindow
+		if end > len(batch) {
+			end = len(batch)
+		}
+		window := batch[start:end]
+
+		accesses := make([]*txAccesses, len(window))
+		for i, env := range window {
+			a, err := extractAccesses(env)
+			if err != nil {
+				logger.Debugf("Transaction %d of the batch is not reordered: %s", start+i, err)
+				a = &txAccesses{}
+			}
+			accesses[i] = a
+		}
+
+		g := newPrecedenceGraph(accesses)
+		for _, i := range g.breakCycles() {
+			aborted = append(aborted, window[i])
+		}
+		for _, i := range g.sort() {
+			reordered = append(reordered, window[i])
+		}
+	}
+	return append(reordered, aborted...), len(aborted)
+}
+
+// precedenceGraph has an edge from i to j if the i-th transaction must precede
+// the j-th transaction. The adjacency lists are sorted.
+type precedenceGraph struct {
+	succ    [][]int
+	pred    [][]int
+	removed []bool
+}
+
+func newPrecedenceGraph(accesses []*txAccesses) *precedenceGraph {
+	n := len(accesses)
+	writers := map[stateKey][]int{}
+	var writtenKeys []stateKey
+	for i, a := range accesses {
+		for _, k := range a.writes {
+			w := writers[k]
+			if len(w) == 0 {
+				writtenKeys = append(writtenKeys, k)
+			}
+			if len(w) == 0 || w[len(w)-1] != i {
+				writers[k] = append(w, i)
+			}
+		}
+	}
+
+	edges := make([]map[int]struct{}, n)
+	addEdge := func(reader int, writers []int) {
+		for _, writer := range writers {
+			if writer == reader {
+				continue
+			}
+			if edges[reader] == nil {
+				edges[reader] = map[int]struct{}{}
+			}
+			edges[reader][writer] = struct{}{}
+		}
+	}
+	for i, a := range accesses {
+		for _, k := range a.reads {
+			addEdge(i, writers[k])
+		}
+		for _, r := range a.ranges {
+			for _, k := range writtenKeys {
+				if r.contains(k) {
+					addEdge(i, writers[k])
+				}
+			}
+		}
+	}
+
+	g := &precedenceGraph{
+		succ:    make([][]int, n),
+		pred:    make([][]int, n),
+		removed: make([]bool, n),
+	}
+	for i := range edges {
+		for j := range edges[i] {
+			g.succ[i] = append(g.succ[i], j)
+			g.pred[j] = append(g.pred[j], i)
+		}
+		sort.Ints(g.succ[i])
+	}
+	for j := range g.pred {
+		sort.Ints(g.pred[j])
+	}
+	return g
+}
+
+// breakCycles removes transactions from the graph until it is acyclic and
+// returns the removed transactions in ascending order.
+//
+// Removing a transaction only splits the component it belongs to, so after
+// each removal only that component is searched again for cycles.
+func (g *precedenceGraph) breakCycles() []int {
+	var aborted []int
+	pending := g.stronglyConnectedComponents(nil)
+	for len(pending) > 0 {
+		scc := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if len(scc) < 2 {
+			continue
+		}
+		victim := g.mostConflicting(scc)
+		g.removed[victim] = true
+		aborted = append(aborted, victim)
+		pending = append(pending, g.stronglyConnectedComponents(scc)...)
+	}
+	sort.Ints(aborted)
+	return aborted
+}
+
+// mostConflicting returns the transaction of the component with the most edges
+// within the component, preferring the latest arrival on ties.
+func (g *precedenceGraph) mostConflicting(scc []int) int {
+	inSCC := make(map[int]bool, len(scc))
+	for _, i := range scc {
+		inSCC[i] = true
+	}
+	victim, maxDegree := -1, -1
+	for _, i := range scc {
+		degree := 0
+		for _, j := range g.succ[i] {
+			if inSCC[j] {
+				degree++
+			}
+		}
+		for _, j := range g.pred[i] {
+			if inSCC[j] {
+				degree++
+			}
+		}
+		if degree > maxDegree || (degree == maxDegree && i > victim) {
+			victim, maxDegree = i, degree
+		}
+	}
+	return victim
+}
+
+// stronglyConnectedComponents returns the strongly connected components of the
+// subgraph induced by the given transactions, or of the whole graph when nil,
+// ignoring the removed transactions, using Tarjan's algorithm.
+func (g *precedenceGraph) stronglyConnectedComponents(within []int) [][]int {
+	n := len(g.succ)
+	inScope := make([]bool, n)
+	if within == nil {
+		for v := range inScope {
+			inScope[v] = true
+		}
+	}
+	for _, v := range within {
+		inScope[v] = true
+	}
+	index := make([]int, n)
+	lowLink := make([]int, n)
+	onStack := make([]bool, n)
+	for i := range index {
+		index[i] = -1
+	}
+	var (
+		stack   []int
+		sccs    [][]int
+		counter int
+	)
+
+	var visit func(v int)
+	visit = func(v int) {
+		index[v], lowLink[v] = counter, counter
+		counter++
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, w := range g.succ[v] {
+			if g.removed[w] || !inScope[w] {
+				continue
+			}
+			if index[w] == -1 {
+				visit(w)
+				if lowLink[w] < lowLink[v] {
+					lowLink[v] = lowLink[w]
+				}
+			} else if onStack[w] && index[w] < lowLink[v] {
+				lowLink[v] = index[w]
+			}
+		}
+		if lowLink[v] == index[v] {
+			var scc []int
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				scc = append(scc, w)
+				if w == v {
+					break
+				}
+			}
+			sort.Ints(scc)
+			sccs = append(sccs, scc)
+		}
+	}
+
+	for v := 0; v < n; v++ {
+		if inScope[v] && !g.removed[v] && index[v] == -1 {
+			visit(v)
+		}
+	}
+	return sccs
+}
+
+// sort returns the transactions of the acyclic graph, ignoring the removed
+// ones, in a topological order. Among the transactions whose predecessors are
+// all sorted, the earliest arrival comes first.
+func (g *precedenceGraph) sort() []int {
+	n := len(g.succ)
+	inDegree := make([]int, n)
+	for i := 0; i < n; i++ {
+		if g.removed[i] {
+			continue
+		}
+		for _, j := range g.succ[i] {
+			if !g.removed[j] {
+				inDegree[j]++
+			}
+		}
+	}
+
+	ready := &intHeap{}
+	for i := 0; i < n; i++ {
+		if !g.removed[i] && inDegree[i] == 0 {
+			heap.Push(ready, i)
+		}
+	}
+	var order []int
+	for ready.Len() > 0 {
+		i := heap.Pop(ready).(int)
+		order = append(order, i)
+		for _, j := range g.succ[i] {
+			if g.removed[j] {
+				continue
+			}
+			inDegree[j]--
+			if inDegree[j] == 0 {
+				heap.Push(ready, j)
+			}
+		}
+	}
+	return order
+}
+
+type intHeap []int
+
+func (h intHeap) Len() int            { return len(h) }
+func (h intHeap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h intHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *intHeap) Push(x interface{}) { *h = append(*h, x.(int)) }
+
+func (h *intHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,198 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockcutter_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset"
+	"github.com/hyperledger/fabric-protos-go/ledger/rwset/kvrwset"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
+	"github.com/hyperledger/fabric/protoutil"
+)
+
+func endorserTx(kvRWSet *kvrwset.KVRWSet, collHashedRWSets ...*rwset.CollectionHashedReadWriteSet) *cb.Envelope {
+	if kvRWSet == nil {
+		kvRWSet = &kvrwset.KVRWSet{}
+	}
+	results := protoutil.MarshalOrPanic(&rwset.TxReadWriteSet{
+		DataModel: rwset.TxReadWriteSet_KV,
+		NsRwset: []*rwset.NsReadWriteSet{{
+			Namespace:             "ns",
+			Rwset:                 protoutil.MarshalOrPanic(kvRWSet),
+			CollectionHashedRwset: collHashedRWSets,
+		}},
+	})
+	prp := protoutil.MarshalOrPanic(&pb.ProposalResponsePayload{
+		Extension: protoutil.MarshalOrPanic(&pb.ChaincodeAction{Results: results}),
+	})
+	tx := &pb.Transaction{
+		Actions: []*pb.TransactionAction{{
+			Payload: protoutil.MarshalOrPanic(&pb.ChaincodeActionPayload{
+				Action: &pb.ChaincodeEndorsedAction{ProposalResponsePayload: prp},
+			}),
+		}},
+	}
+	return &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{
+					Type: int32(cb.HeaderType_ENDORSER_TRANSACTION),
+				}),
+			},
+			Data: protoutil.MarshalOrPanic(tx),
+		}),
+	}
+}
+
+func reads(keys ...string) []*kvrwset.KVRead {
+	var kvReads []*kvrwset.KVRead
+	for _, k := range keys {
+		kvReads = append(kvReads, &kvrwset.KVRead{Key: k})
+	}
+	return kvReads
+}
+
+func writes(keys ...string) []*kvrwset.KVWrite {
+	var kvWrites []*kvrwset.KVWrite
+	for _, k := range keys {
+		kvWrites = append(kvWrites, &kvrwset.KVWrite{Key: k, Value: []byte("value")})
+	}
+	return kvWrites
+}
+
+var _ = Describe("Reorder", func() {
+	It("keeps the arrival order of the transactions without conflicts", func() {
+		batch := []*cb.Envelope{
+			endorserTx(&kvrwset.KVRWSet{Reads: reads("a"), Writes: writes("a")}),
+			endorserTx(&kvrwset.KVRWSet{Reads: reads("b"), Writes: writes("b")}),
+			endorserTx(&kvrwset.KVRWSet{Writes: writes("c")}),
+			endorserTx(&kvrwset.KVRWSet{Writes: writes("c")}),
+		}
+		reordered, aborted := blockcutter.Reorder(batch)
+		Expect(reordered).To(Equal(batch))
+		Expect(aborted).To(Equal(0))
+	})
+
+	It("moves the readers of a key before its writers", func() {
+		batch := []*cb.Envelope{
+			endorserTx(&kvrwset.KVRWSet{Writes: writes("a")}),
+			endorserTx(&kvrwset.KVRWSet{Reads: reads("a"), Writes: writes("b")}),
+			endorserTx(&kvrwset.KVRWSet{Reads: reads("b")}),
+			endorserTx(&kvrwset.KVRWSet{Reads: reads("c")}),
+		}
+		reordered, aborted := blockcutter.Reorder(batch)
+		Expect(reordered).To(Equal([]*cb.Envelope{batch[2], batch[1], batch[0], batch[3]}))
+		Expect(aborted).To(Equal(0))
+	})
+
+	It("moves the readers of a key range before the writers of a key in the range", func() {
+		batch := []*cb.Envelope{
+			endorserTx(&kvrwset.KVRWSet{Writes: writes("key5")}),
+			endorserTx(&kvrwset.KVRWSet{RangeQueriesInfo: []*kvrwset.RangeQueryInfo{{StartKey: "key6", EndKey: ""}}}),
+			endorserTx(&kvrwset.KVRWSet{RangeQueriesInfo: []*kvrwset.RangeQueryInfo{{StartKey: "key1", EndKey: "key9"}}}),
+			endorserTx(&kvrwset.KVRWSet{RangeQueriesInfo: []*kvrwset.RangeQueryInfo{{StartKey: "key1", EndKey: "key5"}}}),
+		}
+		reordered, aborted := blockcutter.Reorder(batch)
+		Expect(reordered).To(Equal([]*cb.Envelope{batch[1], batch[2], batch[0], batch[3]}))
+		Expect(aborted).To(Equal(0))
+	})
+
+	It("moves the readers of a private key before its writers", func() {
+		hashedRWSet := func(h *kvrwset.HashedRWSet) *rwset.CollectionHashedReadWriteSet {
+			return &rwset.CollectionHashedReadWriteSet{CollectionName: "coll", HashedRwset: protoutil.MarshalOrPanic(h)}
+		}
+		batch := []*cb.Envelope{
+			endorserTx(nil, hashedRWSet(&kvrwset.HashedRWSet{HashedWrites: []*kvrwset.KVWriteHash{{KeyHash: []byte("a")}}})),
+			endorserTx(nil, hashedRWSet(&kvrwset.HashedRWSet{HashedReads: []*kvrwset.KVReadHash{{KeyHash: []byte("b")}}})),
+			endorserTx(nil, hashedRWSet(&kvrwset.HashedRWSet{HashedReads: []*kvrwset.KVReadHash{{KeyHash: []byte("a")}}})),
+			endorserTx(&kvrwset.KVRWSet{Reads: reads("a")}),
+		}
+		reordered, aborted := blockcutter.Reorder(batch)
+		Expect(reordered).To(Equal([]*cb.Envelope{batch[1], batch[2], batch[0], batch[3]}))
+		Expect(aborted).To(Equal(0))
+	})
+
+	It("aborts the transactions which break a cycle of conflicts", func() {
+		batch := []*cb.Envelope{
+			endorserTx(&kvrwset.KVRWSet{Reads: reads("a"), Writes: writes("b")}),
+			endorserTx(&kvrwset.KVRWSet{Reads: reads("b"), Writes: writes("a")}),
+			endorserTx(&kvrwset.KVRWSet{Reads: reads("c"), Writes: writes("c")}),
+			endorserTx(&kvrwset.KVRWSet{Reads: reads("c"), Writes: writes("c")}),
+			endorserTx(&kvrwset.KVRWSet{Reads: reads("c"), Writes: writes("c")}),
+			endorserTx(&kvrwset.KVRWSet{Reads: reads("d")}),
+		}
+		reordered, aborted := blockcutter.Reorder(batch)
+		Expect(reordered).To(Equal([]*cb.Envelope{batch[0], batch[2], batch[5], batch[1], batch[3], batch[4]}))
+		Expect(aborted).To(Equal(3))
+	})
+
+	It("aborts the transaction with the most conflicts in a cycle", func() {
+		batch := []*cb.Envelope{
+			endorserTx(&kvrwset.KVRWSet{Reads: reads("a", "b", "c"), Writes: writes("d")}),
+			endorserTx(&kvrwset.KVRWSet{Reads: reads("d"), Writes: writes("a")}),
+			endorserTx(&kvrwset.KVRWSet{Reads: reads("d"), Writes: writes("b")}),
+			endorserTx(&kvrwset.KVRWSet{Reads: reads("d"), Writes: writes("c")}),
+		}
+		reordered, aborted := blockcutter.Reorder(batch)
+		Expect(reordered).To(Equal([]*cb.Envelope{batch[1], batch[2], batch[3], batch[0]}))
+		Expect(aborted).To(Equal(1))
+	})
+
+	It("reorders large batches in windows", func() {
+		var batch []*cb.Envelope
+		for i := 0; i < 300; i++ {
+			batch = append(batch, endorserTx(&kvrwset.KVRWSet{Reads: reads("a"), Writes: writes("a")}))
+		}
+		reordered, aborted := blockcutter.Reorder(batch)
+		Expect(reordered).To(HaveLen(len(batch)))
+		Expect(reordered[0]).To(BeIdenticalTo(batch[0]))
+		Expect(reordered[1]).To(BeIdenticalTo(batch[256]))
+		Expect(reordered[2]).To(BeIdenticalTo(batch[1]))
+		Expect(aborted).To(Equal(298))
+	})
+
+	It("leaves the envelopes which are not endorser transactions unconstrained", func() {
+		batch := []*cb.Envelope{
+			{Payload: []byte("garbage")},
+			endorserTx(&kvrwset.KVRWSet{Writes: writes("a")}),
+			{
+				Payload: protoutil.MarshalOrPanic(&cb.Payload{
+					Header: &cb.Header{
+						ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{Type: int32(cb.HeaderType_MESSAGE)}),
+					},
+				}),
+			},
+			endorserTx(&kvrwset.KVRWSet{Reads: reads("a")}),
+		}
+		reordered, aborted := blockcutter.Reorder(batch)
+		Expect(reordered).To(Equal([]*cb.Envelope{batch[0], batch[2], batch[3], batch[1]}))
+		Expect(aborted).To(Equal(0))
+	})
+
+	It("is deterministic", func() {
+		keys := []string{"a", "b", "c", "d", "e"}
+		var batch []*cb.Envelope
+		for i := 0; i < 50; i++ {
+			batch = append(batch, endorserTx(&kvrwset.KVRWSet{
+				Reads:  reads(keys[i%5], keys[(i*3)%5]),
+				Writes: writes(keys[(i*7)%5]),
+			}))
+		}
+		expected, expectedAborted := blockcutter.Reorder(batch)
+		Expect(expected).To(HaveLen(len(batch)))
+		Expect(expected).To(ConsistOf(batch))
+		for i := 0; i < 10; i++ {
+			reordered, aborted := blockcutter.Reorder(batch)
+			Expect(reordered).To(Equal(expected))
+			Expect(aborted).To(Equal(expectedAborted))
+		}
+	})
+})
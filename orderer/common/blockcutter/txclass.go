@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockcutter
+
+import (
+	"time"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/protoutil"
+)
+
+// TxClass is a class of transactions, matched by the chaincode they invoke or
+// by the MSP of their creator, whose pending batch is cut at most MaxDelay
+// after a transaction of the class is enqueued, even if the batch timeout of
+// the channel is longer.
+type TxClass struct {
+	Name       string
+	Chaincodes []string
+	MSPIDs     []string
+	MaxDelay   time.Duration
+}
+
+// txClassifier matches the envelopes to the first of the transaction classes
+// that they belong to.
+type txClassifier struct {
+	classes []TxClass
+}
+
+func newTxClassifier(classes []TxClass) *txClassifier {
+	return &txClassifier{classes: classes}
+}
+
+// classify returns the class of the envelope, or false if it belongs to none.
+func (tc *txClassifier) classify(env *cb.Envelope) (*TxClass, bool) {
+	if len(tc.classes) == 0 {
+		return nil, false
+	}
+
+	chaincodeName, mspID := envelopeOrigin(env)
+	for i, class := range tc.classes {
+		if (chaincodeName != "" && contains(class.Chaincodes, chaincodeName)) || (mspID != "" && contains(class.MSPIDs, mspID)) {
+			return &tc.classes[i], true
+		}
+	}
+	return nil, false
+}
+
+// envelopeOrigin returns the name of the chaincode invoked by the endorser
+// transaction in the envelope and the MSP ID of its creator. Either is empty
+// if it cannot be extracted.
+func envelopeOrigin(env *cb.Envelope) (chaincodeName, mspID string) {
+	payload, err := protoutil.UnmarshalPayload(env.Payload)
+	if err != nil || payload.Header == nil {
+		return "", ""
+	}
+
+	if shdr, err := protoutil.UnmarshalSignatureHeader(payload.Header.SignatureHeader); err == nil {
+		if creator, err := protoutil.UnmarshalSerializedIdentity(shdr.Creator); err == nil {
+			mspID = creator.Mspid
+		}
+	}
+
+	chdr, err := protoutil.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil || cb.HeaderType(chdr.Type) != cb.HeaderType_ENDORSER_TRANSACTION {
+		return "", mspID
+	}
+	if ext, err := protoutil.UnmarshalChaincodeHeaderExtension(chdr.Extension); err == nil {
+		chaincodeName = ext.GetChaincodeId().GetName()
+	}
+	return chaincodeName, mspID
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
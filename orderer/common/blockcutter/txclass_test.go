@@ -0,0 +1,173 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockcutter_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"code.cloudfoundry.org/clock/fakeclock"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	ab "github.com/hyperledger/fabric-protos-go/orderer"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter/mock"
+	"github.com/hyperledger/fabric/protoutil"
+)
+
+func classifiedTx(chaincodeName, mspID string) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{
+					Type: int32(cb.HeaderType_ENDORSER_TRANSACTION),
+					Extension: protoutil.MarshalOrPanic(&pb.ChaincodeHeaderExtension{
+						ChaincodeId: &pb.ChaincodeID{Name: chaincodeName},
+					}),
+				}),
+				SignatureHeader: protoutil.MarshalOrPanic(&cb.SignatureHeader{
+					Creator: protoutil.MarshalOrPanic(&msp.SerializedIdentity{Mspid: mspID}),
+				}),
+			},
+		}),
+	}
+}
+
+var _ = Describe("Transaction classes", func() {
+	var (
+		bc         blockcutter.Receiver
+		fakeConfig *mock.OrdererConfig
+
+		fakeTxClassBatchingDelay *mock.MetricsHistogram
+		fakeTxClassDeadlineCuts  *mock.MetricsCounter
+		fakeClock                *fakeclock.FakeClock
+	)
+
+	BeforeEach(func() {
+		fakeConfig = &mock.OrdererConfig{}
+		fakeConfig.BatchSizeReturns(&ab.BatchSize{
+			MaxMessageCount:   10,
+			PreferredMaxBytes: 10000,
+		})
+		fakeConfigFetcher := &mock.OrdererConfigFetcher{}
+		fakeConfigFetcher.OrdererConfigReturns(fakeConfig, true)
+
+		fakeBlockFillDuration := &mock.MetricsHistogram{}
+		fakeBlockFillDuration.WithReturns(fakeBlockFillDuration)
+		fakeTxClassBatchingDelay = &mock.MetricsHistogram{}
+		fakeTxClassBatchingDelay.WithReturns(fakeTxClassBatchingDelay)
+		fakeTxClassDeadlineCuts = &mock.MetricsCounter{}
+		fakeTxClassDeadlineCuts.WithReturns(fakeTxClassDeadlineCuts)
+		metrics := &blockcutter.Metrics{
+			BlockFillDuration:    fakeBlockFillDuration,
+			ReorderingAbortedTxs: &mock.MetricsCounter{},
+			TxClassBatchingDelay: fakeTxClassBatchingDelay,
+			TxClassDeadlineCuts:  fakeTxClassDeadlineCuts,
+		}
+
+		fakeClock = fakeclock.NewFakeClock(time.Unix(1000, 0))
+		bc = blockcutter.NewReceiverImpl("mychannel", fakeConfigFetcher, metrics, []blockcutter.TxClass{
+			{Name: "payments", Chaincodes: []string{"payments"}, MaxDelay: time.Hour},
+			{Name: "transfers", Chaincodes: []string{"transfers"}, MaxDelay: time.Minute},
+			{Name: "org2", MSPIDs: []string{"Org2MSP"}, MaxDelay: time.Second},
+		}, fakeClock)
+	})
+
+	It("has no deadline for messages that belong to no class", func() {
+		_, pending := bc.Ordered(classifiedTx("bulk", "Org1MSP"))
+		Expect(pending).To(BeTrue())
+
+		_, ok := bc.Deadline()
+		Expect(ok).To(BeFalse())
+
+		Expect(bc.Cut()).To(HaveLen(1))
+		Expect(fakeTxClassBatchingDelay.ObserveCallCount()).To(Equal(0))
+	})
+
+	It("sets the deadline by the maximum delay of the class", func() {
+		_, pending := bc.Ordered(classifiedTx("payments", "Org1MSP"))
+		Expect(pending).To(BeTrue())
+
+		deadline, ok := bc.Deadline()
+		Expect(ok).To(BeTrue())
+		Expect(deadline).To(Equal(fakeClock.Now().Add(time.Hour)))
+
+		fakeClock.Increment(time.Second / 2)
+		Expect(bc.Cut()).To(HaveLen(1))
+		_, ok = bc.Deadline()
+		Expect(ok).To(BeFalse())
+
+		Expect(fakeTxClassBatchingDelay.ObserveCallCount()).To(Equal(1))
+		Expect(fakeTxClassBatchingDelay.WithArgsForCall(0)).To(Equal([]string{"channel", "mychannel", "class", "payments"}))
+		Expect(fakeTxClassBatchingDelay.ObserveArgsForCall(0)).To(Equal(0.5))
+		Expect(fakeTxClassDeadlineCuts.AddCallCount()).To(Equal(0))
+	})
+
+	It("keeps the earliest deadline of the pending messages", func() {
+		bc.Ordered(classifiedTx("payments", "Org1MSP"))
+		bc.Ordered(classifiedTx("transfers", "Org1MSP"))
+		deadline, ok := bc.Deadline()
+		Expect(ok).To(BeTrue())
+		Expect(deadline).To(Equal(fakeClock.Now().Add(time.Minute)))
+
+		bc.Ordered(classifiedTx("payments", "Org1MSP"))
+		laterDeadline, ok := bc.Deadline()
+		Expect(ok).To(BeTrue())
+		Expect(laterDeadline).To(Equal(deadline))
+
+		Expect(bc.Cut()).To(HaveLen(3))
+		Expect(fakeTxClassBatchingDelay.ObserveCallCount()).To(Equal(3))
+		Expect(fakeTxClassBatchingDelay.WithArgsForCall(1)).To(Equal([]string{"channel", "mychannel", "class", "transfers"}))
+	})
+
+	It("matches the first class of the message", func() {
+		_, pending := bc.Ordered(classifiedTx("payments", "Org2MSP"))
+		Expect(pending).To(BeTrue())
+
+		bc.Cut()
+		Expect(fakeTxClassBatchingDelay.WithArgsForCall(0)).To(Equal([]string{"channel", "mychannel", "class", "payments"}))
+	})
+
+	It("cuts the batch once the maximum delay of a class expires", func() {
+		_, pending := bc.Ordered(classifiedTx("bulk", "Org2MSP"))
+		Expect(pending).To(BeTrue())
+
+		fakeClock.Increment(time.Second)
+		batches, pending := bc.Ordered(classifiedTx("bulk", "Org1MSP"))
+		Expect(pending).To(BeFalse())
+		Expect(batches).To(HaveLen(1))
+		Expect(batches[0]).To(HaveLen(2))
+
+		Expect(fakeTxClassDeadlineCuts.AddCallCount()).To(Equal(1))
+		Expect(fakeTxClassDeadlineCuts.WithArgsForCall(0)).To(Equal([]string{"channel", "mychannel", "class", "org2"}))
+		_, ok := bc.Deadline()
+		Expect(ok).To(BeFalse())
+	})
+
+	Context("when the message is larger than the preferred max bytes", func() {
+		BeforeEach(func() {
+			fakeConfig.BatchSizeReturns(&ab.BatchSize{
+				MaxMessageCount:   10,
+				PreferredMaxBytes: 10,
+			})
+		})
+
+		It("records that the message was not delayed", func() {
+			batches, pending := bc.Ordered(classifiedTx("payments", "Org1MSP"))
+			Expect(pending).To(BeFalse())
+			Expect(batches).To(HaveLen(1))
+
+			Expect(fakeTxClassBatchingDelay.ObserveCallCount()).To(Equal(1))
+			Expect(fakeTxClassBatchingDelay.ObserveArgsForCall(0)).To(Equal(float64(0)))
+			_, ok := bc.Deadline()
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
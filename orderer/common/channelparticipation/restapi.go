@@ -13,11 +13,14 @@ import (
 	"mime/multipart"
 	"net/http"
 	"path"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/gorilla/mux"
 	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/audit"
 	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
@@ -29,9 +32,14 @@ const (
 	URLBaseV1              = "/participation/v1/"
 	URLBaseV1Channels      = URLBaseV1 + "channels"
 	FormDataConfigBlockKey = "config-block"
+	FormDataCheckpointKey  = "checkpoint"
 
 	channelIDKey        = "channelID"
 	urlWithChannelIDKey = URLBaseV1Channels + "/{" + channelIDKey + "}"
+
+	urlConsensus           = urlWithChannelIDKey + "/consensus"
+	urlConsensusViewChange = urlConsensus + "/viewchange"
+	urlConsensusBlacklist  = urlConsensus + "/blacklist"
 )
 
 //go:generate counterfeiter -o mocks/channel_management.go -fake-name ChannelManagement . ChannelManagement
@@ -50,8 +58,23 @@ type ChannelManagement interface {
 	// The URL field is empty, and is to be completed by the caller.
 	JoinChannel(channelID string, configBlock *cb.Block, isAppChannel bool) (types.ChannelInfo, error)
 
+	// JoinChannelFromCheckpoint instructs the orderer to create an application channel whose ledger starts at the
+	// provided checkpoint config block, and join it.
+	// The URL field is empty, and is to be completed by the caller.
+	JoinChannelFromCheckpoint(channelID string, checkpointBlock *cb.Block) (types.ChannelInfo, error)
+
 	// RemoveChannel instructs the orderer to remove a channel.
 	RemoveChannel(channelID string) error
+
+	// ConsensusInfo provides the consensus status of a channel.
+	ConsensusInfo(channelID string) (types.ConsensusInfo, error)
+
+	// ForceViewChange instructs the orderer to vote for a view change away from the current leader of a channel.
+	ForceViewChange(channelID string) error
+
+	// BlacklistLeader instructs the orderer to vote for a view change whenever the given node is the leader
+	// of a channel, until the given duration elapses.
+	BlacklistLeader(channelID string, id uint64, duration time.Duration) error
 }
 
 // HTTPHandler handles all the HTTP requests to the channel participation API.
@@ -60,6 +83,9 @@ type HTTPHandler struct {
 	config    localconfig.ChannelParticipation
 	registrar ChannelManagement
 	router    *mux.Router
+
+	// AuditLogger records the channel management operations, or is nil if they are not audited
+	AuditLogger *audit.Logger
 }
 
 func NewHTTPHandler(config localconfig.ChannelParticipation, registrar ChannelManagement) *HTTPHandler {
@@ -92,6 +118,79 @@ func NewHTTPHandler(config localconfig.ChannelParticipation, registrar ChannelMa
 	//         description: The directives for caching responses
 	//         type: string
 
+	// swagger:operation GET /v1/participation/channels/{channelID}/consensus channels consensusInfo
+	// ---
+	// summary: Returns the consensus status of a channel an Ordering Service Node (OSN) has joined.
+	// parameters:
+	// - name: channelID
+	//   in: path
+	//   description: Channel ID
+	//   required: true
+	//   type: string
+	// responses:
+	//    '200':
+	//       description: Successfully retrieved the consensus status.
+	//       schema:
+	//         "$ref": "#/definitions/consensusInfo"
+	//    '404':
+	//      description: The channel does not exist.
+	//    '501':
+	//      description: The consensus type of the channel does not support operator controls.
+
+	handler.router.HandleFunc(urlConsensus, handler.serveConsensusInfo).Methods(http.MethodGet)
+	handler.router.HandleFunc(urlConsensus, handler.serveConsensusNotAllowed)
+
+	// swagger:operation POST /v1/participation/channels/{channelID}/consensus/viewchange channels forceViewChange
+	// ---
+	// summary: Makes an Ordering Service Node (OSN) vote for a view change away from the current leader of a channel.
+	// parameters:
+	// - name: channelID
+	//   in: path
+	//   description: Channel ID
+	//   required: true
+	//   type: string
+	// responses:
+	//    '204':
+	//      description: Successfully voted for a view change.
+	//    '400':
+	//      description: Bad request.
+	//    '404':
+	//      description: The channel does not exist.
+	//    '501':
+	//      description: The consensus type of the channel does not support operator controls.
+
+	handler.router.HandleFunc(urlConsensusViewChange, handler.serveViewChange).Methods(http.MethodPost)
+	handler.router.HandleFunc(urlConsensusViewChange, handler.serveConsensusNotAllowed)
+
+	// swagger:operation POST /v1/participation/channels/{channelID}/consensus/blacklist channels blacklistLeader
+	// ---
+	// summary: Makes an Ordering Service Node (OSN) vote for a view change whenever the given node is the leader of a channel.
+	// parameters:
+	// - name: channelID
+	//   in: path
+	//   description: Channel ID
+	//   required: true
+	//   type: string
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/blacklistLeaderRequest"
+	// responses:
+	//    '204':
+	//      description: Successfully blacklisted the leader.
+	//    '400':
+	//      description: Bad request.
+	//    '404':
+	//      description: The channel does not exist.
+	//    '501':
+	//      description: The consensus type of the channel does not support operator controls.
+	// consumes:
+	//   - application/json
+
+	handler.router.HandleFunc(urlConsensusBlacklist, handler.serveBlacklistLeader).Methods(http.MethodPost)
+	handler.router.HandleFunc(urlConsensusBlacklist, handler.serveConsensusNotAllowed)
+
 	handler.router.HandleFunc(urlWithChannelIDKey, handler.serveListOne).Methods(http.MethodGet)
 
 	// swagger:operation DELETE /v1/participation/channels/{channelID} channels removeChannel
@@ -145,6 +244,14 @@ func NewHTTPHandler(config localconfig.ChannelParticipation, registrar ChannelMa
 	//   in: formData
 	//   type: string
 	//   required: true
+	// - name: checkpoint
+	//   in: formData
+	//   description: |
+	//                If true, the ledger of the channel starts at the config block, whose signatures must satisfy
+	//                its block validation policy, and the blocks preceding it are not pulled. The config block is
+	//                trusted as the checkpoint of the channel, and must be vetted by the operator.
+	//   type: boolean
+	//   required: false
 	// responses:
 	//    '201':
 	//      description: Successfully joined channel.
@@ -255,7 +362,7 @@ func (h *HTTPHandler) serveJoin(resp http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	block := h.multipartFormDataBodyToBlock(params, req, resp)
+	block, fromCheckpoint := h.multipartFormDataBodyToBlock(params, req, resp)
 	if block == nil {
 		return
 	}
@@ -266,7 +373,21 @@ func (h *HTTPHandler) serveJoin(resp http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	info, err := h.registrar.JoinChannel(channelID, block, isAppChannel)
+	var info types.ChannelInfo
+	if fromCheckpoint {
+		if !isAppChannel {
+			h.sendResponseJsonError(resp, http.StatusBadRequest, errors.New("cannot join the system channel from a checkpoint"))
+			return
+		}
+		info, err = h.registrar.JoinChannelFromCheckpoint(channelID, block)
+	} else {
+		info, err = h.registrar.JoinChannel(channelID, block, isAppChannel)
+	}
+	h.audit(req, "JoinChannel", map[string]string{
+		"channel":        channelID,
+		"blockNumber":    strconv.FormatUint(block.GetHeader().GetNumber(), 10),
+		"fromCheckpoint": strconv.FormatBool(fromCheckpoint),
+	}, err)
 	if err != nil {
 		h.sendJoinError(err, resp)
 		return
@@ -277,8 +398,9 @@ func (h *HTTPHandler) serveJoin(resp http.ResponseWriter, req *http.Request) {
 	h.sendResponseCreated(resp, info.URL, info)
 }
 
-// Expect a multipart/form-data with a single part, of type file, with key FormDataConfigBlockKey.
-func (h *HTTPHandler) multipartFormDataBodyToBlock(params map[string]string, req *http.Request, resp http.ResponseWriter) *cb.Block {
+// Expect a multipart/form-data with a part of type file with key FormDataConfigBlockKey, and an optional boolean
+// value with key FormDataCheckpointKey.
+func (h *HTTPHandler) multipartFormDataBodyToBlock(params map[string]string, req *http.Request, resp http.ResponseWriter) (*cb.Block, bool) {
 	boundary := params["boundary"]
 	reader := multipart.NewReader(
 		http.MaxBytesReader(resp, req.Body, int64(h.config.MaxRequestBodySize)),
@@ -287,30 +409,44 @@ func (h *HTTPHandler) multipartFormDataBodyToBlock(params map[string]string, req
 	form, err := reader.ReadForm(2 * int64(h.config.MaxRequestBodySize))
 	if err != nil {
 		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrap(err, "cannot read form from request body"))
-		return nil
+		return nil, false
 	}
 
 	if _, exist := form.File[FormDataConfigBlockKey]; !exist {
 		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Errorf("form does not contains part key: %s", FormDataConfigBlockKey))
-		return nil
+		return nil, false
+	}
+
+	fromCheckpoint := false
+	if values, exist := form.Value[FormDataCheckpointKey]; exist {
+		if len(values) != 1 {
+			h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Errorf("form contains too many parts with key: %s", FormDataCheckpointKey))
+			return nil, false
+		}
+		fromCheckpoint, err = strconv.ParseBool(values[0])
+		if err != nil {
+			h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrapf(err, "cannot parse part %s", FormDataCheckpointKey))
+			return nil, false
+		}
+		delete(form.Value, FormDataCheckpointKey)
 	}
 
 	if len(form.File) != 1 || len(form.Value) != 0 {
 		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.New("form contains too many parts"))
-		return nil
+		return nil, false
 	}
 
 	fileHeader := form.File[FormDataConfigBlockKey][0]
 	file, err := fileHeader.Open()
 	if err != nil {
 		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrapf(err, "cannot open file part %s from request body", FormDataConfigBlockKey))
-		return nil
+		return nil, false
 	}
 
 	blockBytes, err := ioutil.ReadAll(file)
 	if err != nil {
 		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrapf(err, "cannot read file part %s from request body", FormDataConfigBlockKey))
-		return nil
+		return nil, false
 	}
 
 	block := &cb.Block{}
@@ -318,10 +454,10 @@ func (h *HTTPHandler) multipartFormDataBodyToBlock(params map[string]string, req
 	if err != nil {
 		h.logger.Debugf("Failed to unmarshal blockBytes: %s", err)
 		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrapf(err, "cannot unmarshal file part %s into a block", FormDataConfigBlockKey))
-		return nil
+		return nil, false
 	}
 
-	return block
+	return block, fromCheckpoint
 }
 
 func (h *HTTPHandler) extractChannelID(req *http.Request, resp http.ResponseWriter) (string, error) {
@@ -377,6 +513,7 @@ func (h *HTTPHandler) serveRemove(resp http.ResponseWriter, req *http.Request) {
 	}
 
 	err = h.registrar.RemoveChannel(channelID)
+	h.audit(req, "RemoveChannel", map[string]string{"channel": channelID}, err)
 	if err == nil {
 		h.logger.Debugf("Successfully removed channel: %s", channelID)
 		resp.WriteHeader(http.StatusNoContent)
@@ -397,6 +534,124 @@ func (h *HTTPHandler) serveRemove(resp http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// Get the consensus status of a channel
+func (h *HTTPHandler) serveConsensusInfo(resp http.ResponseWriter, req *http.Request) {
+	_, err := negotiateContentType(req) // Only application/json responses for now
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusNotAcceptable, err)
+		return
+	}
+
+	channelID, err := h.extractChannelID(req, resp)
+	if err != nil {
+		return
+	}
+
+	info, err := h.registrar.ConsensusInfo(channelID)
+	if err != nil {
+		h.sendConsensusError(err, resp)
+		return
+	}
+
+	resp.Header().Set("Cache-Control", "no-store")
+	h.sendResponseOK(resp, info)
+}
+
+// Vote for a view change
+func (h *HTTPHandler) serveViewChange(resp http.ResponseWriter, req *http.Request) {
+	_, err := negotiateContentType(req) // Only application/json responses for now
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusNotAcceptable, err)
+		return
+	}
+
+	channelID, err := h.extractChannelID(req, resp)
+	if err != nil {
+		return
+	}
+
+	err = h.registrar.ForceViewChange(channelID)
+	h.audit(req, "ViewChange", map[string]string{"channel": channelID}, err)
+	if err != nil {
+		h.sendConsensusError(err, resp)
+		return
+	}
+
+	h.logger.Infof("Voted for a view change on channel: %s", channelID)
+	resp.WriteHeader(http.StatusNoContent)
+}
+
+// Temporarily blacklist a leader.
+// Expect application/json.
+func (h *HTTPHandler) serveBlacklistLeader(resp http.ResponseWriter, req *http.Request) {
+	_, err := negotiateContentType(req) // Only application/json responses for now
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusNotAcceptable, err)
+		return
+	}
+
+	channelID, err := h.extractChannelID(req, resp)
+	if err != nil {
+		return
+	}
+
+	blacklistReq := &types.BlacklistLeaderRequest{}
+	decoder := json.NewDecoder(http.MaxBytesReader(resp, req.Body, int64(h.config.MaxRequestBodySize)))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(blacklistReq); err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrap(err, "cannot decode request body"))
+		return
+	}
+
+	duration, err := time.ParseDuration(blacklistReq.Duration)
+	if err != nil {
+		h.sendResponseJsonError(resp, http.StatusBadRequest, errors.Wrap(err, "invalid duration"))
+		return
+	}
+
+	err = h.registrar.BlacklistLeader(channelID, blacklistReq.ID, duration)
+	h.audit(req, "BlacklistLeader", map[string]string{
+		"channel":  channelID,
+		"id":       strconv.FormatUint(blacklistReq.ID, 10),
+		"duration": duration.String(),
+	}, err)
+	if err != nil {
+		h.sendConsensusError(err, resp)
+		return
+	}
+
+	h.logger.Infof("Blacklisted leader %d on channel %s for %s", blacklistReq.ID, channelID, duration)
+	resp.WriteHeader(http.StatusNoContent)
+}
+
+// audit records the given channel management operation on behalf of the caller of the given request.
+func (h *HTTPHandler) audit(req *http.Request, action string, parameters map[string]string, err error) {
+	h.AuditLogger.Log("channelparticipation", action, audit.CallerFromHTTPRequest(req), parameters, err)
+}
+
+func (h *HTTPHandler) sendConsensusError(err error, resp http.ResponseWriter) {
+	h.logger.Debugf("Failed to serve consensus request: %s", err)
+	switch err {
+	case types.ErrChannelNotExist:
+		h.sendResponseJsonError(resp, http.StatusNotFound, err)
+	case types.ErrConsensusControlNotSupported:
+		h.sendResponseJsonError(resp, http.StatusNotImplemented, err)
+	default:
+		h.sendResponseJsonError(resp, http.StatusBadRequest, err)
+	}
+}
+
+func (h *HTTPHandler) serveConsensusNotAllowed(resp http.ResponseWriter, req *http.Request) {
+	err := errors.Errorf("invalid request method: %s", req.Method)
+
+	if strings.HasSuffix(req.URL.Path, "/consensus") {
+		h.sendResponseNotAllowed(resp, err, http.MethodGet)
+		return
+	}
+
+	h.sendResponseNotAllowed(resp, err, http.MethodPost)
+}
+
 func (h *HTTPHandler) serveBadContentType(resp http.ResponseWriter, req *http.Request) {
 	err := errors.Errorf("unsupported Content-Type: %s", req.Header.Values("Content-Type"))
 	h.sendResponseJsonError(resp, http.StatusBadRequest, err)
@@ -15,9 +15,12 @@ import (
 	"net/http/httptest"
 	"os"
 	"path"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/audit"
 	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
 	"github.com/hyperledger/fabric/orderer/common/channelparticipation/mocks"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
@@ -395,6 +398,71 @@ func TestHTTPHandler_ServeHTTP_Join(t *testing.T) {
 		checkErrorResponse(t, http.StatusBadRequest, "form contains too many parts", resp)
 	})
 
+	t.Run("created ok from checkpoint", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelFromCheckpointReturns(types.ChannelInfo{
+			Name:              "app-channel",
+			ConsensusRelation: "follower",
+			Status:            "active",
+			Height:            11,
+		}, nil)
+
+		resp := httptest.NewRecorder()
+		req := genJoinFromCheckpointRequestFormData(t, validBlockBytes("ch-id"), "true")
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+		require.Equal(t, 0, fakeManager.JoinChannelCallCount())
+		require.Equal(t, 1, fakeManager.JoinChannelFromCheckpointCallCount())
+		channelID, block := fakeManager.JoinChannelFromCheckpointArgsForCall(0)
+		require.Equal(t, "ch-id", channelID)
+		require.NotNil(t, block)
+
+		infoResp := types.ChannelInfo{}
+		err := json.Unmarshal(resp.Body.Bytes(), &infoResp)
+		require.NoError(t, err, "cannot be unmarshaled")
+		require.Equal(t, channelparticipation.URLBaseV1Channels+"/app-channel", infoResp.URL)
+		require.Equal(t, uint64(11), infoResp.Height)
+	})
+
+	t.Run("checkpoint false", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := genJoinFromCheckpointRequestFormData(t, validBlockBytes("ch-id"), "false")
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusCreated, resp.Result().StatusCode)
+		require.Equal(t, 1, fakeManager.JoinChannelCallCount())
+		require.Equal(t, 0, fakeManager.JoinChannelFromCheckpointCallCount())
+	})
+
+	t.Run("Error: checkpoint join failed", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.JoinChannelFromCheckpointReturns(types.ChannelInfo{}, errors.New("invalid checkpoint block"))
+		resp := httptest.NewRecorder()
+		req := genJoinFromCheckpointRequestFormData(t, validBlockBytes("ch-id"), "true")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "cannot join: invalid checkpoint block", resp)
+	})
+
+	t.Run("Error: system channel checkpoint", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		blockBytes := protoutil.MarshalOrPanic(blockWithGroups(map[string]*common.ConfigGroup{
+			"Consortiums": {},
+		}, "sys-channel"))
+		req := genJoinFromCheckpointRequestFormData(t, blockBytes, "true")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "cannot join the system channel from a checkpoint", resp)
+		require.Equal(t, 0, fakeManager.JoinChannelFromCheckpointCallCount())
+	})
+
+	t.Run("form-data: bad checkpoint value", func(t *testing.T) {
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := genJoinFromCheckpointRequestFormData(t, validBlockBytes("ch-id"), "maybe")
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "cannot parse part checkpoint", resp)
+	})
+
 	t.Run("body larger that MaxRequestBodySize", func(t *testing.T) {
 		config := localconfig.ChannelParticipation{
 			Enabled:            true,
@@ -488,6 +556,139 @@ func TestHTTPHandler_ServeHTTP_Remove(t *testing.T) {
 	})
 }
 
+func TestHTTPHandler_ServeHTTP_Audit(t *testing.T) {
+	config := localconfig.ChannelParticipation{Enabled: true}
+	fakeManager, h := setup(config, t)
+	buff := &bytes.Buffer{}
+	h.AuditLogger = audit.NewLogger(buff, nil)
+
+	fakeManager.RemoveChannelReturnsOnCall(0, nil)
+	fakeManager.RemoveChannelReturnsOnCall(1, types.ErrChannelNotExist)
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodDelete, path.Join(channelparticipation.URLBaseV1Channels, "my-channel"), nil)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	last, err := audit.Verify(bytes.NewReader(buff.Bytes()), "")
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), last.Sequence)
+	require.Equal(t, "channelparticipation", last.Component)
+	require.Equal(t, "RemoveChannel", last.Action)
+	require.Equal(t, map[string]string{"channel": "my-channel"}, last.Parameters)
+	require.Equal(t, audit.OutcomeFailure, last.Outcome)
+	require.Equal(t, types.ErrChannelNotExist.Error(), last.Error)
+	require.Equal(t, "192.0.2.1:1234", last.Caller.Address)
+}
+
+func TestHTTPHandler_ServeHTTP_Consensus(t *testing.T) {
+	config := localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 1024 * 1024}
+	consensusURL := path.Join(channelparticipation.URLBaseV1Channels, "my-channel", "consensus")
+
+	t.Run("status ok", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ConsensusInfoReturns(types.ConsensusInfo{
+			Name:             "my-channel",
+			View:             2,
+			LeaderID:         3,
+			DecisionSequence: 17,
+			PendingRequests:  5,
+			Blacklist:        []uint64{1},
+			SuspectedLeaders: []uint64{},
+		}, nil)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, consensusURL, nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Result().StatusCode)
+		require.Equal(t, "application/json", resp.Result().Header.Get("Content-Type"))
+		require.Equal(t, "no-store", resp.Result().Header.Get("Cache-Control"))
+
+		infoResp := types.ConsensusInfo{}
+		err := json.Unmarshal(resp.Body.Bytes(), &infoResp)
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), infoResp.LeaderID)
+		require.Equal(t, uint64(17), infoResp.DecisionSequence)
+		require.Equal(t, 5, infoResp.PendingRequests)
+		require.Equal(t, "my-channel", fakeManager.ConsensusInfoArgsForCall(0))
+	})
+
+	t.Run("status errors", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ConsensusInfoReturnsOnCall(0, types.ConsensusInfo{}, types.ErrChannelNotExist)
+		fakeManager.ConsensusInfoReturnsOnCall(1, types.ConsensusInfo{}, types.ErrConsensusControlNotSupported)
+
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, consensusURL, nil))
+		checkErrorResponse(t, http.StatusNotFound, "channel does not exist", resp)
+
+		resp = httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, consensusURL, nil))
+		checkErrorResponse(t, http.StatusNotImplemented, "consensus type does not support operator controls", resp)
+	})
+
+	t.Run("view change ok", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, consensusURL+"/viewchange", nil)
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusNoContent, resp.Result().StatusCode)
+		require.Equal(t, 1, fakeManager.ForceViewChangeCallCount())
+		require.Equal(t, "my-channel", fakeManager.ForceViewChangeArgsForCall(0))
+	})
+
+	t.Run("view change error", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		fakeManager.ForceViewChangeReturns(errors.New("consensus is not running"))
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, consensusURL+"/viewchange", nil)
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, "consensus is not running", resp)
+	})
+
+	t.Run("blacklist ok", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, consensusURL+"/blacklist", strings.NewReader(`{"id":2,"duration":"5m"}`))
+		h.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusNoContent, resp.Result().StatusCode)
+		require.Equal(t, 1, fakeManager.BlacklistLeaderCallCount())
+		channelID, id, duration := fakeManager.BlacklistLeaderArgsForCall(0)
+		require.Equal(t, "my-channel", channelID)
+		require.Equal(t, uint64(2), id)
+		require.Equal(t, 5*time.Minute, duration)
+	})
+
+	t.Run("blacklist bad body", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, consensusURL+"/blacklist", strings.NewReader(`{"id":2,"oops":1}`))
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, `cannot decode request body: json: unknown field "oops"`, resp)
+		require.Equal(t, 0, fakeManager.BlacklistLeaderCallCount())
+	})
+
+	t.Run("blacklist bad duration", func(t *testing.T) {
+		fakeManager, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, consensusURL+"/blacklist", strings.NewReader(`{"id":2,"duration":"forever"}`))
+		h.ServeHTTP(resp, req)
+		checkErrorResponse(t, http.StatusBadRequest, `invalid duration: time: invalid duration "forever"`, resp)
+		require.Equal(t, 0, fakeManager.BlacklistLeaderCallCount())
+	})
+
+	t.Run("invalid methods", func(t *testing.T) {
+		_, h := setup(config, t)
+		resp := httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, consensusURL, nil))
+		checkErrorResponse(t, http.StatusMethodNotAllowed, "invalid request method: POST", resp)
+		require.Equal(t, "GET", resp.Result().Header.Get("Allow"))
+
+		resp = httptest.NewRecorder()
+		h.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, consensusURL+"/viewchange", nil))
+		checkErrorResponse(t, http.StatusMethodNotAllowed, "invalid request method: GET", resp)
+		require.Equal(t, "POST", resp.Result().Header.Get("Allow"))
+	})
+}
+
 func setup(config localconfig.ChannelParticipation, t *testing.T) (*mocks.ChannelManagement, *channelparticipation.HTTPHandler) {
 	fakeManager := &mocks.ChannelManagement{}
 	h := channelparticipation.NewHTTPHandler(config, fakeManager)
@@ -525,6 +726,23 @@ func genJoinRequestFormData(t *testing.T, blockBytes []byte) *http.Request {
 	return req
 }
 
+func genJoinFromCheckpointRequestFormData(t *testing.T, blockBytes []byte, checkpoint string) *http.Request {
+	joinBody := new(bytes.Buffer)
+	writer := multipart.NewWriter(joinBody)
+	part, err := writer.CreateFormFile(channelparticipation.FormDataConfigBlockKey, "join-config.block")
+	require.NoError(t, err)
+	part.Write(blockBytes)
+	err = writer.WriteField(channelparticipation.FormDataCheckpointKey, checkpoint)
+	require.NoError(t, err)
+	err = writer.Close()
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, channelparticipation.URLBaseV1Channels, joinBody)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	return req
+}
+
 func validBlockBytes(channelID string) []byte {
 	blockBytes := protoutil.MarshalOrPanic(blockWithGroups(map[string]*common.ConfigGroup{
 		"Application": {},
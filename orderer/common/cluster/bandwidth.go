@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cluster
+
+import (
+	"sync"
+	"time"
+)
+
+// BandwidthLimiter limits the rate in which bytes are consumed.
+// It is a token bucket that is refilled at a rate of bytesPerSecond,
+// and holds up to one second worth of bytes.
+// A nil BandwidthLimiter imposes no limit.
+type BandwidthLimiter struct {
+	bytesPerSecond float64
+	now            func() time.Time
+	after          func(time.Duration) <-chan time.Time
+
+	lock       sync.Mutex
+	available  float64
+	lastRefill time.Time
+}
+
+// NewBandwidthLimiter returns a BandwidthLimiter that limits the consumption of bytes
+// to the given amount of bytes per second, or nil if the amount is not positive.
+func NewBandwidthLimiter(bytesPerSecond int) *BandwidthLimiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	return &BandwidthLimiter{
+		bytesPerSecond: float64(bytesPerSecond),
+		available:      float64(bytesPerSecond),
+		lastRefill:     time.Now(),
+		now:            time.Now,
+		after:          time.After,
+	}
+}
+
+// Wait blocks until the given amount of bytes can be consumed without exceeding the limit,
+// or until the given stop channel is closed, in which case it returns false.
+// Amounts larger than what the bucket holds are consumed in debt of future refills.
+func (bl *BandwidthLimiter) Wait(bytes int, stop <-chan struct{}) bool {
+	if bl == nil {
+		return true
+	}
+
+	delay := bl.reserve(bytes)
+	if delay <= 0 {
+		return true
+	}
+
+	select {
+	case <-bl.after(delay):
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// reserve consumes the given amount of bytes and returns how long
+// the caller needs to wait until the bucket is no longer in debt.
+func (bl *BandwidthLimiter) reserve(bytes int) time.Duration {
+	bl.lock.Lock()
+	defer bl.lock.Unlock()
+
+	now := bl.now()
+	bl.available += now.Sub(bl.lastRefill).Seconds() * bl.bytesPerSecond
+	if bl.available > bl.bytesPerSecond {
+		bl.available = bl.bytesPerSecond
+	}
+	bl.lastRefill = now
+
+	bl.available -= float64(bytes)
+	if bl.available >= 0 {
+		return 0
+	}
+	return time.Duration(-bl.available / bl.bytesPerSecond * float64(time.Second))
+}
@@ -496,6 +496,131 @@ func TestBlockingSend(t *testing.T) {
 	}
 }
 
+func TestConsensusPreemptsSubmit(t *testing.T) {
+	// Scenario: Consensus requests that are queued after SubmitRequests
+	// are sent before them, whether they are sent over the same stream
+	// or over another stream to the same remote node.
+
+	for _, testCase := range []struct {
+		description   string
+		separateQueue bool
+	}{
+		{
+			description: "same stream",
+		},
+		{
+			description:   "separate streams",
+			separateQueue: true,
+		},
+	} {
+		t.Run(testCase.description, func(t *testing.T) {
+			node1 := newTestNode(t)
+			node2 := newTestNode(t)
+
+			node1.c.SendBufferSize = 10
+
+			defer node1.stop()
+			defer node2.stop()
+
+			config := []cluster.RemoteNode{node1.nodeInfo, node2.nodeInfo}
+			node1.c.Configure(testChannel, config)
+			node2.c.Configure(testChannel, config)
+
+			rm, err := node1.c.Remote(testChannel, node2.nodeInfo.ID)
+			require.NoError(t, err)
+
+			fakeStream := &mocks.StepClientStream{}
+			rm.GetStreamFunc = func(ctx context.Context) (cluster.StepClientStream, error) {
+				return fakeStream, nil
+			}
+
+			rm.ProbeConn = func(_ *grpc.ClientConn) error {
+				return nil
+			}
+
+			fakeStream.On("Context", mock.Anything).Return(context.Background())
+			fakeStream.On("Auth").Return(nil)
+
+			unBlock := make(chan struct{})
+			var sendInvoked sync.WaitGroup
+			sendInvoked.Add(1)
+			var once sync.Once
+
+			var lock sync.Mutex
+			var sent []string
+			var allSent sync.WaitGroup
+			allSent.Add(3)
+
+			fakeStream.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+				req := args.Get(0).(*orderer.StepRequest)
+				lock.Lock()
+				if req.GetConsensusRequest() != nil {
+					sent = append(sent, string(req.GetConsensusRequest().Payload))
+				} else {
+					sent = append(sent, string(req.GetSubmitRequest().Payload.Payload))
+				}
+				lock.Unlock()
+				once.Do(sendInvoked.Done)
+				<-unBlock
+				allSent.Done()
+			}).Return(nil)
+
+			submitStream, err := rm.NewStream(time.Hour)
+			require.NoError(t, err)
+
+			consensusStream := submitStream
+			if testCase.separateQueue {
+				consensusStream, err = rm.NewStream(time.Hour)
+				require.NoError(t, err)
+			}
+
+			submit := func(payload string) *orderer.StepRequest {
+				return wrapSubmitReq(&orderer.SubmitRequest{
+					Channel: testChannel,
+					Payload: &common.Envelope{Payload: []byte(payload)},
+				})
+			}
+
+			consensus := func(payload string) *orderer.StepRequest {
+				return &orderer.StepRequest{
+					Payload: &orderer.StepRequest_ConsensusRequest{
+						ConsensusRequest: &orderer.ConsensusRequest{
+							Channel: testChannel,
+							Payload: []byte(payload),
+						},
+					},
+				}
+			}
+
+			var expected []string
+			if testCase.separateQueue {
+				// The first consensus request blocks in Send while the next one waits in the queue,
+				// hence the submit request needs to wait for both.
+				require.NoError(t, consensusStream.Send(consensus("c1")))
+				sendInvoked.Wait()
+				require.NoError(t, consensusStream.Send(consensus("c2")))
+				require.NoError(t, submitStream.Send(submit("s1")))
+				expected = []string{"c1", "c2", "s1"}
+			} else {
+				// The first submit request blocks in Send while the next one waits in the queue,
+				// hence the consensus request overtakes the second submit request.
+				require.NoError(t, submitStream.Send(submit("s1")))
+				sendInvoked.Wait()
+				require.NoError(t, submitStream.Send(submit("s2")))
+				require.NoError(t, consensusStream.Send(consensus("c1")))
+				expected = []string{"s1", "c1", "s2"}
+			}
+
+			close(unBlock)
+			allSent.Wait()
+
+			lock.Lock()
+			defer lock.Unlock()
+			require.Equal(t, expected, sent)
+		})
+	}
+}
+
 func TestEmptyRequest(t *testing.T) {
 	// Scenario: Ensures empty messages are discarded and an error is returned
 	// back to the sender.
@@ -1223,6 +1348,7 @@ type testMetrics struct {
 	fakeProvider        *mocks.MetricsProvider
 	egressQueueLength   metricsfakes.Gauge
 	egressQueueCapacity metricsfakes.Gauge
+	egressQueueLatency  metricsfakes.Histogram
 	egressStreamCount   metricsfakes.Gauge
 	egressTLSConnCount  metricsfakes.Gauge
 	egressWorkerSize    metricsfakes.Gauge
@@ -1234,6 +1360,7 @@ type testMetrics struct {
 func (tm *testMetrics) initialize() {
 	tm.egressQueueLength.WithReturns(&tm.egressQueueLength)
 	tm.egressQueueCapacity.WithReturns(&tm.egressQueueCapacity)
+	tm.egressQueueLatency.WithReturns(&tm.egressQueueLatency)
 	tm.egressStreamCount.WithReturns(&tm.egressStreamCount)
 	tm.egressTLSConnCount.WithReturns(&tm.egressTLSConnCount)
 	tm.egressWorkerSize.WithReturns(&tm.egressWorkerSize)
@@ -1250,6 +1377,7 @@ func (tm *testMetrics) initialize() {
 	fakeProvider.On("NewGauge", cluster.EgressWorkersOpts).Return(&tm.egressWorkerSize)
 	fakeProvider.On("NewCounter", cluster.MessagesDroppedCountOpts).Return(&tm.msgDropCount)
 	fakeProvider.On("NewHistogram", cluster.MessageSendTimeOpts).Return(&tm.msgSendTime)
+	fakeProvider.On("NewHistogram", cluster.EgressQueueLatencyOpts).Return(&tm.egressQueueLatency)
 }
 
 func TestMetrics(t *testing.T) {
@@ -1336,6 +1464,32 @@ func TestMetrics(t *testing.T) {
 				require.Equal(t, []string{"host", node2.nodeInfo.Endpoint, "channel", testChannel}, testMetrics.msgSendTime.WithArgsForCall(0))
 			},
 		},
+		{
+			name: "EgressQueueLatency",
+			runTest: func(t *testing.T, node1, node2 *clusterNode, testMetrics *testMetrics) {
+				assertBiDiCommunication(t, node1, node2, testReq)
+				require.Eventually(t, func() bool { return testMetrics.egressQueueLatency.ObserveCallCount() > 0 }, time.Second, 10*time.Millisecond)
+				require.Equal(t, []string{"host", node2.nodeInfo.Endpoint, "msg_type", "transaction", "channel", testChannel},
+					testMetrics.egressQueueLatency.WithArgsForCall(0))
+
+				var messageReceived sync.WaitGroup
+				messageReceived.Add(1)
+				node2.handler.On("OnConsensus", testChannel, node1.nodeInfo.ID, mock.Anything).Run(func(args mock.Arguments) {
+					messageReceived.Done()
+				}).Return(nil)
+
+				rm, err := node1.c.Remote(testChannel, node2.nodeInfo.ID)
+				require.NoError(t, err)
+
+				stream := assertEventualEstablishStream(t, rm)
+				stream.Send(testConsensusReq)
+				messageReceived.Wait()
+
+				require.Equal(t, 2, testMetrics.egressQueueLatency.ObserveCallCount())
+				require.Equal(t, []string{"host", node2.nodeInfo.Endpoint, "msg_type", "consensus", "channel", testChannel},
+					testMetrics.egressQueueLatency.WithArgsForCall(1))
+			},
+		},
 		{
 			name: "MsgDropCount",
 			runTest: func(t *testing.T, node1, node2 *clusterNode, testMetrics *testMetrics) {
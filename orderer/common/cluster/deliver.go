@@ -40,6 +40,8 @@ type BlockPuller struct {
 	Dialer              Dialer
 	VerifyBlockSequence BlockSequenceVerifier
 	Endpoints           []EndpointCriteria
+	// BandwidthLimiter limits the rate in which blocks are pulled, or is nil if it is unlimited.
+	BandwidthLimiter *BandwidthLimiter
 
 	// A 'stopper' goroutine may signal the go-routine servicing PullBlock & HeightsByEndpoints to stop by closing this
 	// channel. Note: all methods of the BlockPuller must be serviced by a single goroutine, it is not thread safe.
@@ -234,6 +236,10 @@ func (p *BlockPuller) pullBlocks(seq uint64, reConnected bool) error {
 		p.blockBuff = append(p.blockBuff, block)
 		nextExpectedSequence++
 		p.Logger.Infof("Got block [%d] of size %d KB from %s", seq, size/1024, p.endpoint)
+		// Hold off receiving the next block until the bandwidth limit allows it
+		if !p.BandwidthLimiter.Wait(size, p.StopChannel) {
+			return errors.Errorf("stopped while throttling blocks from %s", p.endpoint)
+		}
 	}
 	return nil
 }
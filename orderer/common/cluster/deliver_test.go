@@ -466,6 +466,93 @@ func TestBlockPullerHeavyBlocks(t *testing.T) {
 	dialer.assertAllConnectionsClosed(t)
 }
 
+func TestBlockPullerBandwidthLimit(t *testing.T) {
+	// Scenario: Single ordering node,
+	// and the block puller pulls 10 blocks, each
+	// weighing 1K, but the bandwidth is limited
+	// to 4K per second, so pulling the blocks
+	// takes more than a second.
+
+	osn := newClusterNode(t)
+	defer osn.stop()
+	osn.addExpectProbeAssert()
+	osn.addExpectPullAssert(1)
+	osn.enqueueResponse(10)
+
+	for seq := uint64(1); seq <= 10; seq++ {
+		block := protoutil.NewBlock(seq, nil)
+		block.Data.Data = append(block.Data.Data, make([]byte, 1024))
+		osn.blockResponses <- &orderer.DeliverResponse{
+			Type: &orderer.DeliverResponse_Block{Block: block},
+		}
+	}
+
+	dialer := newCountingDialer()
+	bp := newBlockPuller(dialer, osn.srv.Address())
+	bp.BandwidthLimiter = cluster.NewBandwidthLimiter(1024 * 4)
+
+	start := time.Now()
+	for seq := uint64(1); seq <= 10; seq++ {
+		require.Equal(t, seq, bp.PullBlock(seq).Header.Number)
+	}
+	require.Greater(t, time.Since(start), time.Second)
+
+	bp.Close()
+	dialer.assertAllConnectionsClosed(t)
+}
+
+func TestBandwidthLimiter(t *testing.T) {
+	t.Run("unlimited", func(t *testing.T) {
+		require.Nil(t, cluster.NewBandwidthLimiter(0))
+		var bl *cluster.BandwidthLimiter
+		require.True(t, bl.Wait(1024*1024, nil))
+	})
+
+	t.Run("limited", func(t *testing.T) {
+		now := time.Now()
+		var waits []time.Duration
+		after := func(d time.Duration) <-chan time.Time {
+			waits = append(waits, d)
+			c := make(chan time.Time, 1)
+			c <- now
+			return c
+		}
+
+		bl := cluster.NewBandwidthLimiter(1000)
+		bl.SetClock(func() time.Time { return now }, after)
+
+		// The bucket starts full
+		require.True(t, bl.Wait(600, nil))
+		require.Empty(t, waits)
+
+		// Consuming more than what is left waits for the debt to be refilled
+		require.True(t, bl.Wait(600, nil))
+		require.Equal(t, []time.Duration{200 * time.Millisecond}, waits)
+
+		// The bucket is refilled over time
+		now = now.Add(time.Second)
+		require.True(t, bl.Wait(1000, nil))
+		require.Equal(t, []time.Duration{200 * time.Millisecond, 200 * time.Millisecond}, waits)
+
+		// The bucket doesn't hold more than a second worth of bytes
+		now = now.Add(time.Hour)
+		require.True(t, bl.Wait(1500, nil))
+		require.Equal(t, 500*time.Millisecond, waits[2])
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		bl := cluster.NewBandwidthLimiter(1000)
+		now := time.Now()
+		bl.SetClock(func() time.Time { return now }, func(time.Duration) <-chan time.Time {
+			return nil
+		})
+
+		stop := make(chan struct{})
+		close(stop)
+		require.False(t, bl.Wait(2000, stop))
+	})
+}
+
 func TestBlockPullerClone(t *testing.T) {
 	// Scenario: We have a block puller that is connected
 	// to an ordering node, and we clone it.
@@ -6,7 +6,16 @@ SPDX-License-Identifier: Apache-2.0
 
 package cluster
 
+import "time"
+
 // export for testing
 var (
 	ClusterRequestAsString = clusterRequestAsString
 )
+
+// SetClock sets the clock the BandwidthLimiter uses to refill its bucket and to wait
+func (bl *BandwidthLimiter) SetClock(now func() time.Time, after func(time.Duration) <-chan time.Time) {
+	bl.now = now
+	bl.after = after
+	bl.lastRefill = now()
+}
@@ -74,6 +74,15 @@ var (
 		StatsdFormat: "%{#fqname}.%{host}.%{channel}",
 	}
 
+	EgressQueueLatencyOpts = metrics.HistogramOpts{
+		Namespace:    "cluster",
+		Subsystem:    "comm",
+		Name:         "egress_queue_latency",
+		Help:         "The time a message waits in the egress queue before it is sent, in seconds.",
+		LabelNames:   []string{"host", "msg_type", "channel"},
+		StatsdFormat: "%{#fqname}.%{host}.%{msg_type}.%{channel}",
+	}
+
 	MessagesDroppedCountOpts = metrics.CounterOpts{
 		Namespace:    "cluster",
 		Subsystem:    "comm",
@@ -88,6 +97,7 @@ var (
 type Metrics struct {
 	EgressQueueLength        metrics.Gauge
 	EgressQueueCapacity      metrics.Gauge
+	EgressQueueLatency       metrics.Histogram
 	EgressWorkerCount        metrics.Gauge
 	IngressStreamsCount      metrics.Gauge
 	EgressStreamsCount       metrics.Gauge
@@ -114,6 +124,7 @@ func NewMetrics(provider MetricsProvider) *Metrics {
 	return &Metrics{
 		EgressQueueLength:        provider.NewGauge(EgressQueueLengthOpts),
 		EgressQueueCapacity:      provider.NewGauge(EgressQueueCapacityOpts),
+		EgressQueueLatency:       provider.NewHistogram(EgressQueueLatencyOpts),
 		EgressStreamsCount:       provider.NewGauge(EgressStreamsCountOpts),
 		EgressTLSConnectionCount: provider.NewGauge(EgressTLSConnectionCountOpts),
 		EgressWorkerCount:        provider.NewGauge(EgressWorkersOpts),
@@ -132,6 +143,10 @@ func (m *Metrics) reportQueueOccupancy(host string, msgType string, channel stri
 	m.EgressQueueCapacity.With("host", host, "msg_type", msgType, "channel", channel).Set(float64(capacity))
 }
 
+func (m *Metrics) reportQueueLatency(host string, msgType string, channel string, latency time.Duration) {
+	m.EgressQueueLatency.With("host", host, "msg_type", msgType, "channel", channel).Observe(latency.Seconds())
+}
+
 func (m *Metrics) reportWorkerCount(channel string, count uint32) {
 	m.EgressWorkerCount.With("channel", channel).Set(float64(count))
 }
@@ -12,7 +12,6 @@ import (
 	"sync/atomic"
 	"time"
 
-	"github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -37,6 +36,8 @@ type RemoteContext struct {
 	nextStreamID                     uint64
 	streamsByID                      streamsMapperReporter
 	workerCountReporter              workerCountReporter
+	priorityOnce                     sync.Once
+	priority                         *sendPriority
 }
 
 // NewStream creates a new stream.
@@ -79,23 +80,22 @@ func (rc *RemoteContext) NewStream(timeout time.Duration) (*Stream, error) {
 	stepLogger := logger.WithOptions(zap.AddCallerSkip(1))
 
 	s := &Stream{
-		Channel:     rc.Channel,
-		metrics:     rc.Metrics,
-		abortReason: abortReason,
-		abortChan:   abortChan,
-		sendBuff: make(chan struct {
-			request *orderer.StepRequest
-			report  func(error)
-		}, rc.SendBuffSize),
-		commShutdown: rc.shutdownSignal,
-		NodeName:     nodeName,
-		Logger:       stepLogger,
-		ID:           streamID,
-		Endpoint:     rc.endpoint,
-		Timeout:      timeout,
-		StepClient:   stream,
-		Cancel:       cancelWithReason,
-		canceled:     &canceled,
+		Channel:       rc.Channel,
+		metrics:       rc.Metrics,
+		abortReason:   abortReason,
+		abortChan:     abortChan,
+		sendBuff:      make(chan *queuedRequest, rc.SendBuffSize),
+		consensusBuff: make(chan *queuedRequest, rc.SendBuffSize),
+		priority:      rc.sendPriority(),
+		commShutdown:  rc.shutdownSignal,
+		NodeName:      nodeName,
+		Logger:        stepLogger,
+		ID:            streamID,
+		Endpoint:      rc.endpoint,
+		Timeout:       timeout,
+		StepClient:    stream,
+		Cancel:        cancelWithReason,
+		canceled:      &canceled,
 	}
 
 	s.expCheck = &certificateExpirationCheck{
@@ -129,6 +129,14 @@ func (rc *RemoteContext) NewStream(timeout time.Duration) (*Stream, error) {
 	return s, nil
 }
 
+// sendPriority returns the sendPriority shared by all streams of the RemoteContext.
+func (rc *RemoteContext) sendPriority() *sendPriority {
+	rc.priorityOnce.Do(func() {
+		rc.priority = newSendPriority()
+	})
+	return rc.priority
+}
+
 // Abort aborts the contexts the RemoteContext uses, thus effectively
 // causes all operations that use this RemoteContext to terminate.
 func (rc *RemoteContext) Abort() {
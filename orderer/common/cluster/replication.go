@@ -345,6 +345,8 @@ type PullerConfig struct {
 	Signer              identity.SignerSerializer
 	Channel             string
 	MaxTotalBufferBytes int
+	// BandwidthLimiter limits the rate in which blocks are pulled, or is nil if it is unlimited.
+	BandwidthLimiter *BandwidthLimiter
 }
 
 //go:generate mockery --dir . --name VerifierRetriever --case underscore --output mocks/
@@ -400,6 +402,7 @@ func BlockPullerFromConfigBlock(conf PullerConfig, block *common.Block, verifier
 		TLSCert:             tlsCertAsDER.Bytes,
 		VerifyBlockSequence: verifyBlockSequence,
 		MaxTotalBufferBytes: conf.MaxTotalBufferBytes,
+		BandwidthLimiter:    conf.BandwidthLimiter,
 		Endpoints:           endpoints,
 		RetryTimeout:        RetryTimeout,
 		FetchTimeout:        conf.Timeout,
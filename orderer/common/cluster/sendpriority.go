@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cluster
+
+import "sync"
+
+var closedChan = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+// sendPriority is shared among the streams of a RemoteContext, and lets
+// streams that send consensus messages preempt streams that send transactions.
+// A stream marks itself busy while it has consensus messages to send,
+// and transactions are only sent when no stream of the RemoteContext is busy.
+type sendPriority struct {
+	lock sync.Mutex
+	busy map[uint64]struct{}
+	idle chan struct{}
+}
+
+func newSendPriority() *sendPriority {
+	return &sendPriority{
+		busy: make(map[uint64]struct{}),
+		idle: closedChan,
+	}
+}
+
+// markBusy marks the given stream as one that has consensus messages to send.
+func (sp *sendPriority) markBusy(streamID uint64) {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	if _, exists := sp.busy[streamID]; exists {
+		return
+	}
+	if len(sp.busy) == 0 {
+		sp.idle = make(chan struct{})
+	}
+	sp.busy[streamID] = struct{}{}
+}
+
+// markIdle marks the given stream as one that has no consensus messages to send.
+func (sp *sendPriority) markIdle(streamID uint64) {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	if _, exists := sp.busy[streamID]; !exists {
+		return
+	}
+	delete(sp.busy, streamID)
+	if len(sp.busy) == 0 {
+		close(sp.idle)
+	}
+}
+
+// idleChan returns a channel that is closed once no stream has consensus messages to send.
+func (sp *sendPriority) idleChan() <-chan struct{} {
+	sp.lock.Lock()
+	defer sp.lock.Unlock()
+
+	return sp.idle
+}
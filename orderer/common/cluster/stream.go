@@ -18,26 +18,36 @@ import (
 	"go.uber.org/zap"
 )
 
+// queuedRequest is a request that waits in the egress queue of a stream.
+type queuedRequest struct {
+	request    *orderer.StepRequest
+	report     func(error)
+	msgType    string
+	enqueuedAt time.Time
+}
+
 // Stream is used to send/receive messages to/from the remote cluster member.
+// Consensus messages are queued separately from transactions, and are sent
+// before any transaction that waits in the queue of this stream or of any
+// other stream of the same RemoteContext.
 type Stream struct {
-	abortChan <-chan struct{}
-	sendBuff  chan struct {
-		request *orderer.StepRequest
-		report  func(error)
-	}
-	commShutdown chan struct{}
-	abortReason  *atomic.Value
-	metrics      *Metrics
-	ID           uint64
-	Channel      string
-	NodeName     string
-	Endpoint     string
-	Logger       *flogging.FabricLogger
-	Timeout      time.Duration
-	StepClient   StepClientStream
-	Cancel       func(error)
-	canceled     *uint32
-	expCheck     *certificateExpirationCheck
+	abortChan     <-chan struct{}
+	sendBuff      chan *queuedRequest
+	consensusBuff chan *queuedRequest
+	priority      *sendPriority
+	commShutdown  chan struct{}
+	abortReason   *atomic.Value
+	metrics       *Metrics
+	ID            uint64
+	Channel       string
+	NodeName      string
+	Endpoint      string
+	Logger        *flogging.FabricLogger
+	Timeout       time.Duration
+	StepClient    StepClientStream
+	Cancel        func(error)
+	canceled      *uint32
+	expCheck      *certificateExpirationCheck
 }
 
 // StreamOperation denotes an operation done by a stream, such a Send or Receive.
@@ -72,13 +82,15 @@ func (stream *Stream) SendWithReport(request *orderer.StepRequest, report func(e
 // if it is a consensus request and the queue is full.
 func (stream *Stream) sendOrDrop(request *orderer.StepRequest, allowDrop bool, report func(error)) error {
 	msgType := "transaction"
+	buff := stream.sendBuff
 	if allowDrop {
 		msgType = "consensus"
+		buff = stream.consensusBuff
 	}
 
-	stream.metrics.reportQueueOccupancy(stream.Endpoint, msgType, stream.Channel, len(stream.sendBuff), cap(stream.sendBuff))
+	stream.metrics.reportQueueOccupancy(stream.Endpoint, msgType, stream.Channel, len(buff), cap(buff))
 
-	if allowDrop && len(stream.sendBuff) == cap(stream.sendBuff) {
+	if allowDrop && len(buff) == cap(buff) {
 		stream.Cancel(errOverflow)
 		stream.metrics.reportMessagesDropped(stream.Endpoint, stream.Channel)
 		return errOverflow
@@ -87,10 +99,7 @@ func (stream *Stream) sendOrDrop(request *orderer.StepRequest, allowDrop bool, r
 	select {
 	case <-stream.abortChan:
 		return errors.Errorf("stream %d aborted", stream.ID)
-	case stream.sendBuff <- struct {
-		request *orderer.StepRequest
-		report  func(error)
-	}{request: request, report: report}:
+	case buff <- &queuedRequest{request: request, report: report, msgType: msgType, enqueuedAt: time.Now()}:
 		return nil
 	case <-stream.commShutdown:
 		return nil
@@ -98,8 +107,10 @@ func (stream *Stream) sendOrDrop(request *orderer.StepRequest, allowDrop bool, r
 }
 
 // sendMessage sends the request down the stream
-func (stream *Stream) sendMessage(request *orderer.StepRequest, report func(error)) {
+func (stream *Stream) sendMessage(queued *queuedRequest) {
+	request, report := queued.request, queued.report
 	start := time.Now()
+	stream.metrics.reportQueueLatency(stream.Endpoint, queued.msgType, stream.Channel, start.Sub(queued.enqueuedAt))
 	var err error
 	defer func() {
 		message := fmt.Sprintf("Send of %s to %s(%s) took %v",
@@ -125,15 +136,33 @@ func (stream *Stream) sendMessage(request *orderer.StepRequest, report func(erro
 func (stream *Stream) serviceStream() {
 	streamStartTime := time.Now()
 	defer func() {
+		stream.priority.markIdle(stream.ID)
 		stream.Cancel(errAborted)
 		stream.Logger.Debugf("Stream %d to (%s) terminated with total lifetime of %s",
 			stream.ID, stream.Endpoint, time.Since(streamStartTime))
 	}()
 
 	for {
+		// Consensus messages are sent before the transactions that wait in the queue
+		select {
+		case queued := <-stream.consensusBuff:
+			stream.priority.markBusy(stream.ID)
+			stream.sendMessage(queued)
+			continue
+		default:
+		}
+
+		stream.priority.markIdle(stream.ID)
+
 		select {
-		case reqReport := <-stream.sendBuff:
-			stream.sendMessage(reqReport.request, reqReport.report)
+		case queued := <-stream.consensusBuff:
+			stream.priority.markBusy(stream.ID)
+			stream.sendMessage(queued)
+		case queued := <-stream.sendBuff:
+			if !stream.awaitConsensusIdle() {
+				return
+			}
+			stream.sendMessage(queued)
 		case <-stream.abortChan:
 			return
 		case <-stream.commShutdown:
@@ -142,6 +171,24 @@ func (stream *Stream) serviceStream() {
 	}
 }
 
+// awaitConsensusIdle blocks until no stream of the RemoteContext has consensus messages to send,
+// while sending the consensus messages of this stream. It returns false if the stream is aborted
+// or the communication is shut down in the meantime.
+func (stream *Stream) awaitConsensusIdle() bool {
+	for {
+		select {
+		case <-stream.priority.idleChan():
+			return true
+		case queued := <-stream.consensusBuff:
+			stream.sendMessage(queued)
+		case <-stream.abortChan:
+			return false
+		case <-stream.commShutdown:
+			return false
+		}
+	}
+}
+
 // Recv receives a message from a remote cluster member.
 func (stream *Stream) Recv() (*orderer.StepResponse, error) {
 	start := time.Now()
@@ -155,6 +155,9 @@ func (ss StringSet) subtract(set StringSet) {
 type PredicateDialer struct {
 	lock   sync.RWMutex
 	Config comm.ClientConfig
+	// BandwidthLimiter limits the rate in which the block pullers of all channels
+	// pull blocks, or is nil if it is unlimited.
+	BandwidthLimiter *BandwidthLimiter
 }
 
 func (dialer *PredicateDialer) UpdateRootCAs(serverRootCAs [][]byte) {
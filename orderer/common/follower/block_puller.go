@@ -43,6 +43,7 @@ type BlockPullerCreator struct {
 	signer                  identity.SignerSerializer
 	der                     *pem.Block
 	stdDialer               *cluster.StandardDialer
+	bandwidthLimiter        *cluster.BandwidthLimiter
 	ClusterVerifyBlocks     ClusterVerifyBlocksFunc // Default: cluster.VerifyBlocks, or a mock for testing
 	vb                      protoutil.VerifierBuilder
 }
@@ -82,6 +83,7 @@ func NewBlockPullerCreator(
 		clusterConfig:       clusterConfig,
 		signer:              signer,
 		stdDialer:           stdDialer,
+		bandwidthLimiter:    baseDialer.BandwidthLimiter,
 		der:                 der,
 		ClusterVerifyBlocks: cluster.VerifyBlocksBFT, // The default block sequence verification method.
 		vb:                  cluster.BlockVerifierBuilder(bccsp),
@@ -105,6 +107,7 @@ func (creator *BlockPullerCreator) BlockPuller(configBlock *common.Block, stopCh
 		Logger:              flogging.MustGetLogger("orderer.common.cluster.puller").With("channel", creator.channelID),
 		RetryTimeout:        creator.clusterConfig.ReplicationRetryTimeout,
 		MaxTotalBufferBytes: creator.clusterConfig.ReplicationBufferSize,
+		BandwidthLimiter:    creator.bandwidthLimiter,
 		MaxPullBlockRetries: uint64(creator.clusterConfig.ReplicationMaxRetries),
 		FetchTimeout:        creator.clusterConfig.ReplicationPullTimeout,
 		Endpoints:           endpoints,
@@ -110,6 +110,24 @@ func TestBlockPullerFactory_BlockPuller(t *testing.T) {
 		require.EqualError(t, err, "error extracting endpoints from config block: block data is nil")
 		require.Nil(t, bp)
 	})
+
+	t.Run("bandwidth limiter is shared across channels", func(t *testing.T) {
+		limitedDialer := &cluster.PredicateDialer{
+			Config:           dialer.Config,
+			BandwidthLimiter: cluster.NewBandwidthLimiter(1024),
+		}
+		var limiters []*cluster.BandwidthLimiter
+		for _, channel := range []string{"channel1", "channel2"} {
+			factory, err := follower.NewBlockPullerCreator(channel, testLogger, mockSigner, limitedDialer, localconfig.Cluster{}, cryptoProv)
+			require.NoError(t, err)
+			bp, err := factory.BlockPuller(generateJoinBlock(t, tlsCA, channel, 10), make(chan struct{}))
+			require.NoError(t, err)
+			limiters = append(limiters, bp.(*cluster.BlockPuller).BandwidthLimiter)
+		}
+		require.NotNil(t, limiters[0])
+		require.Same(t, limitedDialer.BandwidthLimiter, limiters[0])
+		require.Same(t, limitedDialer.BandwidthLimiter, limiters[1])
+	})
 }
 
 func TestBlockPullerFactory_VerifyBlockSequence(t *testing.T) {
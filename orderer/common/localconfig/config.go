@@ -29,6 +29,8 @@ type TopLevel struct {
 	Metrics              Metrics
 	ChannelParticipation ChannelParticipation
 	Admin                Admin
+	Audit                Audit
+	Logging              Logging
 }
 
 // General contains config which should be common among all orderer types.
@@ -48,6 +50,8 @@ type General struct {
 	LocalMSPID        string
 	BCCSP             *bccsp.FactoryOpts
 	Authentication    Authentication
+	TxIDDedupe        TxIDDedupe
+	TxClasses         []TxClass
 	MaxRecvMsgSize    int32
 	MaxSendMsgSize    int32
 }
@@ -67,6 +71,7 @@ type Cluster struct {
 	ReplicationRetryTimeout              time.Duration
 	ReplicationBackgroundRefreshInterval time.Duration
 	ReplicationMaxRetries                int
+	ReplicationMaxBandwidth              int
 	SendBufferSize                       int
 	CertExpirationWarningThreshold       time.Duration
 	TLSHandshakeTimeShift                time.Duration
@@ -97,6 +102,23 @@ type Authentication struct {
 	NoExpirationChecks bool
 }
 
+// TxIDDedupe contains configuration for rejecting broadcast transactions
+// whose transaction ID was already ordered in recent blocks.
+type TxIDDedupe struct {
+	Enabled      bool
+	WindowBlocks uint64
+}
+
+// TxClass contains configuration for a class of transactions, matched by the
+// chaincode they invoke or the MSP of their creator, whose blocks are cut at
+// most MaxBatchDelay after they are enqueued.
+type TxClass struct {
+	Name          string
+	Chaincodes    []string
+	MSPIDs        []string
+	MaxBatchDelay time.Duration
+}
+
 // Profile contains configuration for Go pprof profiling.
 type Profile struct {
 	Enabled bool
@@ -148,6 +170,38 @@ type ChannelParticipation struct {
 	MaxRequestBodySize uint32
 }
 
+// Audit configures the audit log of the administrative operations of the orderer.
+type Audit struct {
+	Enabled    bool
+	File       string
+	MaxSize    int
+	MaxBackups int
+}
+
+// Logging configures the destinations of the log records of the orderer in
+// addition to its standard error.
+type Logging struct {
+	Sinks []LogSink
+}
+
+// LogSink configures a destination of log records, with its own logging
+// specification, format and loggers.
+type LogSink struct {
+	Name             string
+	Type             string
+	Spec             string
+	Format           string
+	Loggers          []string
+	File             string
+	MaxSize          int
+	RotationInterval time.Duration
+	MaxBackups       int
+	Network          string
+	Address          string
+	Facility         string
+	AppName          string
+}
+
 // Defaults carries the default orderer configuration values.
 var Defaults = TopLevel{
 	General: General{
@@ -175,6 +229,10 @@ var Defaults = TopLevel{
 		Authentication: Authentication{
 			TimeWindow: time.Duration(15 * time.Minute),
 		},
+		TxIDDedupe: TxIDDedupe{
+			Enabled:      false,
+			WindowBlocks: 100,
+		},
 		MaxRecvMsgSize: comm.DefaultMaxRecvMsgSize,
 		MaxSendMsgSize: comm.DefaultMaxSendMsgSize,
 	},
@@ -198,6 +256,9 @@ var Defaults = TopLevel{
 	Admin: Admin{
 		ListenAddress: "127.0.0.1:0",
 	},
+	Audit: Audit{
+		Enabled: false,
+	},
 }
 
 // Load parses the orderer YAML file and environment, producing
@@ -256,6 +317,17 @@ func (c *configCache) load() (*TopLevel, error) {
 	return &uconf, nil
 }
 
+// invalidTxClass returns the index of the first transaction class without a
+// positive maximum batch delay, or -1.
+func invalidTxClass(classes []TxClass) int {
+	for i, class := range classes {
+		if class.MaxBatchDelay <= 0 {
+			return i
+		}
+	}
+	return -1
+}
+
 func (c *TopLevel) completeInitialization(configDir string) {
 	defer func() {
 		// Translate any paths for cluster TLS configuration if applicable
@@ -275,6 +347,14 @@ func (c *TopLevel) completeInitialization(configDir string) {
 		coreconfig.TranslatePathInPlace(configDir, &c.General.LocalMSPDir)
 		// Translate file ledger location
 		coreconfig.TranslatePathInPlace(configDir, &c.FileLedger.Location)
+		if c.Audit.File != "" {
+			coreconfig.TranslatePathInPlace(configDir, &c.Audit.File)
+		}
+		for i := range c.Logging.Sinks {
+			if c.Logging.Sinks[i].File != "" {
+				coreconfig.TranslatePathInPlace(configDir, &c.Logging.Sinks[i].File)
+			}
+		}
 	}()
 
 	for {
@@ -327,13 +407,24 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			logger.Infof("General.Authentication.TimeWindow unset, setting to %s", Defaults.General.Authentication.TimeWindow)
 			c.General.Authentication.TimeWindow = Defaults.General.Authentication.TimeWindow
 
+		case c.General.TxIDDedupe.Enabled && c.General.TxIDDedupe.WindowBlocks == 0:
+			logger.Infof("General.TxIDDedupe.WindowBlocks unset, setting to %d", Defaults.General.TxIDDedupe.WindowBlocks)
+			c.General.TxIDDedupe.WindowBlocks = Defaults.General.TxIDDedupe.WindowBlocks
+
 		case !c.ChannelParticipation.Enabled:
 			logger.Info("General.ChannelParticipation.Enabled was set to false, setting to true")
 			c.ChannelParticipation.Enabled = true
 
+		case invalidTxClass(c.General.TxClasses) >= 0:
+			logger.Panicf("General.TxClasses[%d].MaxBatchDelay must be greater than zero", invalidTxClass(c.General.TxClasses))
+
 		case c.Admin.TLS.Enabled && !c.Admin.TLS.ClientAuthRequired:
 			logger.Panic("Admin.TLS.ClientAuthRequired must be set to true if Admin.TLS.Enabled is set to true")
 
+		case c.Audit.Enabled && c.Audit.File == "":
+			c.Audit.File = filepath.Join(c.FileLedger.Location, "audit", "audit.log")
+			logger.Infof("Audit.File unset, setting to %s", c.Audit.File)
+
 		case c.General.MaxRecvMsgSize == 0:
 			logger.Infof("General.MaxRecvMsgSize is unset, setting to %v", Defaults.General.MaxRecvMsgSize)
 			c.General.MaxRecvMsgSize = Defaults.General.MaxRecvMsgSize
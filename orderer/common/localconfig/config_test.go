@@ -256,3 +256,107 @@ func TestChannelParticipationDefaults(t *testing.T) {
 	require.Equal(t, cfg.ChannelParticipation.Enabled, Defaults.ChannelParticipation.Enabled)
 	require.Equal(t, cfg.ChannelParticipation.MaxRequestBodySize, Defaults.ChannelParticipation.MaxRequestBodySize)
 }
+
+func TestAuditDefaults(t *testing.T) {
+	cleanup := configtest.SetDevFabricConfigPath(t)
+	defer cleanup()
+
+	cc := &configCache{}
+	cfg, err := cc.load()
+	require.NoError(t, err)
+	require.False(t, cfg.Audit.Enabled)
+	require.Empty(t, cfg.Audit.File)
+
+	cfg.Audit.Enabled = true
+	cfg.completeInitialization("/dummy/path")
+	require.Equal(t, filepath.Join(cfg.FileLedger.Location, "audit", "audit.log"), cfg.Audit.File)
+}
+
+func TestTxClassesConfig(t *testing.T) {
+	name := t.TempDir()
+
+	content := `---
+General:
+  TxClasses:
+    - Name: payments
+      Chaincodes:
+        - payments
+        - settlement
+      MaxBatchDelay: 100ms
+    - Name: org2
+      MSPIDs:
+        - Org2MSP
+      MaxBatchDelay: 1s
+`
+
+	f, err := os.OpenFile(filepath.Join(name, "orderer.yaml"), os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o600)
+	require.Nil(t, err, "Error creating file: %s", err)
+	f.WriteString(content)
+	require.NoError(t, f.Close(), "Error closing file")
+
+	t.Setenv("FABRIC_CFG_PATH", name)
+
+	cc := &configCache{}
+	conf, err := cc.load()
+	require.NoError(t, err, "Load good config returned unexpected error")
+	require.Equal(t, []TxClass{
+		{Name: "payments", Chaincodes: []string{"payments", "settlement"}, MaxBatchDelay: 100 * time.Millisecond},
+		{Name: "org2", MSPIDs: []string{"Org2MSP"}, MaxBatchDelay: time.Second},
+	}, conf.General.TxClasses)
+}
+
+func TestTxClassesWithoutDelay(t *testing.T) {
+	cfg := &TopLevel{}
+	cfg.General.TxClasses = []TxClass{
+		{Name: "payments", MaxBatchDelay: time.Second},
+		{Name: "org2"},
+	}
+	require.PanicsWithValue(t, "General.TxClasses[1].MaxBatchDelay must be greater than zero", func() { cfg.completeInitialization("/dummy/path") })
+}
+
+func TestLoggingConfig(t *testing.T) {
+	name := t.TempDir()
+
+	content := `---
+Logging:
+  Sinks:
+    - Name: cluster
+      Type: file
+      Spec: debug
+      Loggers:
+        - orderer.common.cluster
+      File: logs/cluster.log
+      MaxSize: 10
+      RotationInterval: 24h
+      MaxBackups: 7
+    - Name: syslog
+      Type: syslog
+      Network: tcp
+      Address: syslog.example.com:601
+      Facility: local1
+`
+
+	f, err := os.OpenFile(filepath.Join(name, "orderer.yaml"), os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o600)
+	require.Nil(t, err, "Error creating file: %s", err)
+	f.WriteString(content)
+	require.NoError(t, f.Close(), "Error closing file")
+
+	t.Setenv("FABRIC_CFG_PATH", name)
+
+	cc := &configCache{}
+	conf, err := cc.load()
+	require.NoError(t, err, "Load good config returned unexpected error")
+	require.Equal(t, []LogSink{
+		{
+			Name:             "cluster",
+			Type:             "file",
+			Spec:             "debug",
+			Loggers:          []string{"orderer.common.cluster"},
+			File:             filepath.Join(name, "logs", "cluster.log"),
+			MaxSize:          10,
+			RotationInterval: 24 * time.Hour,
+			MaxBackups:       7,
+		},
+		{Name: "syslog", Type: "syslog", Network: "tcp", Address: "syslog.example.com:601", Facility: "local1"},
+	}, conf.Logging.Sinks)
+}
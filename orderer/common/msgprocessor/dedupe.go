@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"sync"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+)
+
+// ErrDuplicateTxID is returned by the TxID dedupe rule when a message carries a
+// transaction ID which was already ordered within the dedupe window.
+var ErrDuplicateTxID = errors.New("duplicate transaction ID")
+
+// DedupeLedgerReader defines the subset of the block ledger required to build
+// and maintain the TxID dedupe window.
+type DedupeLedgerReader interface {
+	// Height returns the number of blocks on the ledger
+	Height() uint64
+	// RetrieveBlockByNumber returns the block with the given number
+	RetrieveBlockByNumber(blockNumber uint64) (*cb.Block, error)
+}
+
+// NewTxIDDedupeRule creates a rule which rejects messages whose TxID appears in
+// one of the last windowBlocks blocks of the ledger. The window is built lazily
+// from the ledger, so it is rebuilt transparently after a restart.
+func NewTxIDDedupeRule(ledger DedupeLedgerReader, windowBlocks uint64) *TxIDDedupeRule {
+	return &TxIDDedupeRule{
+		ledger:       ledger,
+		windowBlocks: windowBlocks,
+		txIDs:        map[string]uint64{},
+	}
+}
+
+// TxIDDedupeRule implements the Rule interface.
+type TxIDDedupeRule struct {
+	ledger       DedupeLedgerReader
+	windowBlocks uint64
+
+	mutex  sync.Mutex
+	height uint64            // ledger height up to which blocks have been indexed
+	txIDs  map[string]uint64 // TxID -> number of the block it was ordered in
+	blocks []indexedBlock    // indexed blocks, oldest first
+}
+
+type indexedBlock struct {
+	number uint64
+	txIDs  []string
+}
+
+// Apply returns an error if the TxID of the message was ordered within the dedupe window.
+func (r *TxIDDedupeRule) Apply(message *cb.Envelope) error {
+	chdr, err := protoutil.ChannelHeader(message)
+	if err != nil {
+		return errors.WithMessage(err, "could not extract channel header")
+	}
+	if chdr.TxId == "" {
+		return nil
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.sync(); err != nil {
+		logger.Warningf("Failed updating TxID dedupe window: %s", err)
+	}
+
+	if blockNumber, exists := r.txIDs[chdr.TxId]; exists {
+		return errors.WithMessagef(ErrDuplicateTxID, "txID %s was already ordered in block %d", chdr.TxId, blockNumber)
+	}
+	return nil
+}
+
+// sync indexes the blocks appended to the ledger since the last invocation and
+// evicts the blocks which fell out of the window. It must be called with the mutex held.
+func (r *TxIDDedupeRule) sync() error {
+	height := r.ledger.Height()
+	if height <= r.height {
+		return nil
+	}
+
+	start := r.height
+	if height > r.windowBlocks && height-r.windowBlocks > start {
+		start = height - r.windowBlocks
+	}
+
+	for n := start; n < height; n++ {
+		block, err := r.ledger.RetrieveBlockByNumber(n)
+		if err != nil {
+			return errors.WithMessagef(err, "could not retrieve block %d", n)
+		}
+		r.index(block)
+		r.height = n + 1
+	}
+
+	r.evict()
+	return nil
+}
+
+func (r *TxIDDedupeRule) index(block *cb.Block) {
+	ib := indexedBlock{number: block.Header.Number}
+	if block.Data != nil {
+		for i, data := range block.Data.Data {
+			env, err := protoutil.UnmarshalEnvelope(data)
+			if err != nil {
+				logger.Warningf("Skipping malformed envelope %d in block %d: %s", i, ib.number, err)
+				continue
+			}
+			chdr, err := protoutil.ChannelHeader(env)
+			if err != nil || chdr.TxId == "" {
+				continue
+			}
+			r.txIDs[chdr.TxId] = ib.number
+			ib.txIDs = append(ib.txIDs, chdr.TxId)
+		}
+	}
+	r.blocks = append(r.blocks, ib)
+}
+
+func (r *TxIDDedupeRule) evict() {
+	var oldest uint64
+	if r.height > r.windowBlocks {
+		oldest = r.height - r.windowBlocks
+	}
+
+	var i int
+	for ; i < len(r.blocks) && r.blocks[i].number < oldest; i++ {
+		for _, txID := range r.blocks[i].txIDs {
+			if r.txIDs[txID] == r.blocks[i].number {
+				delete(r.txIDs, txID)
+			}
+		}
+	}
+	r.blocks = r.blocks[i:]
+}
@@ -0,0 +1,97 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"testing"
+
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDedupeLedger struct {
+	blocks []*cb.Block
+}
+
+func (m *mockDedupeLedger) Height() uint64 {
+	return uint64(len(m.blocks))
+}
+
+func (m *mockDedupeLedger) RetrieveBlockByNumber(blockNumber uint64) (*cb.Block, error) {
+	if blockNumber >= uint64(len(m.blocks)) {
+		return nil, errors.Errorf("block %d not found", blockNumber)
+	}
+	return m.blocks[blockNumber], nil
+}
+
+func (m *mockDedupeLedger) append(txIDs ...string) {
+	block := protoutil.NewBlock(uint64(len(m.blocks)), nil)
+	for _, txID := range txIDs {
+		block.Data.Data = append(block.Data.Data, protoutil.MarshalOrPanic(makeTxIDMessage(txID)))
+	}
+	m.blocks = append(m.blocks, block)
+}
+
+func makeTxIDMessage(txID string) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: protoutil.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: protoutil.MarshalOrPanic(&cb.ChannelHeader{
+					Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
+					ChannelId: "mychannel",
+					TxId:      txID,
+				}),
+			},
+		}),
+	}
+}
+
+func TestTxIDDedupeRule(t *testing.T) {
+	ledger := &mockDedupeLedger{}
+	ledger.append()
+	ledger.append("tx1", "tx2")
+	ledger.append("tx3")
+
+	rule := NewTxIDDedupeRule(ledger, 2)
+
+	t.Run("Duplicate", func(t *testing.T) {
+		err := rule.Apply(makeTxIDMessage("tx1"))
+		require.Equal(t, ErrDuplicateTxID, errors.Cause(err))
+		require.EqualError(t, err, "txID tx1 was already ordered in block 1: duplicate transaction ID")
+	})
+
+	t.Run("Unique", func(t *testing.T) {
+		require.NoError(t, rule.Apply(makeTxIDMessage("tx4")))
+	})
+
+	t.Run("Empty TxID", func(t *testing.T) {
+		require.NoError(t, rule.Apply(makeTxIDMessage("")))
+	})
+
+	t.Run("New Blocks", func(t *testing.T) {
+		ledger.append("tx4")
+		require.Equal(t, ErrDuplicateTxID, errors.Cause(rule.Apply(makeTxIDMessage("tx4"))))
+	})
+
+	t.Run("Outside Window", func(t *testing.T) {
+		require.NoError(t, rule.Apply(makeTxIDMessage("tx1")))
+		require.Equal(t, ErrDuplicateTxID, errors.Cause(rule.Apply(makeTxIDMessage("tx3"))))
+	})
+
+	t.Run("Rebuild", func(t *testing.T) {
+		restarted := NewTxIDDedupeRule(ledger, 2)
+		require.NoError(t, restarted.Apply(makeTxIDMessage("tx2")))
+		require.Equal(t, ErrDuplicateTxID, errors.Cause(restarted.Apply(makeTxIDMessage("tx3"))))
+		require.Equal(t, ErrDuplicateTxID, errors.Cause(restarted.Apply(makeTxIDMessage("tx4"))))
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		require.Error(t, rule.Apply(&cb.Envelope{Payload: []byte("garbage")}))
+	})
+}
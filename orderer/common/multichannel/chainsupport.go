@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package multichannel
 
 import (
+	"code.cloudfoundry.org/clock"
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
@@ -67,12 +68,21 @@ func newChainSupport(
 			ledgerResources.ConfigtxValidator().ChannelID(),
 			ledgerResources,
 			blockcutterMetrics,
+			txClasses(ledgerResources, registrar.config.General.TxClasses),
+			clock.NewClock(),
 		),
 		BCCSP: bccsp,
 	}
 
 	// Set up the msgprocessor
-	cs.Processor = msgprocessor.NewStandardChannel(cs, msgprocessor.CreateStandardChannelFilters(cs, registrar.config), bccsp)
+	filters := msgprocessor.CreateStandardChannelFilters(cs, registrar.config)
+	if dedupe := registrar.config.General.TxIDDedupe; dedupe.Enabled {
+		filters = msgprocessor.NewRuleSet([]msgprocessor.Rule{
+			filters,
+			msgprocessor.NewTxIDDedupeRule(ledgerResources, dedupe.WindowBlocks),
+		})
+	}
+	cs.Processor = msgprocessor.NewStandardChannel(cs, filters, bccsp)
 
 	// Set up the block writer
 	cs.BlockWriter = newBlockWriter(lastBlock, registrar, cs)
@@ -107,6 +117,36 @@ func newChainSupport(
 	return cs, nil
 }
 
+// txClasses converts the configured transaction classes for the blockcutter. The
+// classes whose maximum delay exceeds the batch timeout of the channel are
+// rejected, as the batch timer cuts their batches first.
+func txClasses(ledgerResources *ledgerResources, classes []localconfig.TxClass) []blockcutter.TxClass {
+	if len(classes) == 0 {
+		return nil
+	}
+	channelID := ledgerResources.ConfigtxValidator().ChannelID()
+	ordererConfig, ok := ledgerResources.OrdererConfig()
+	if !ok {
+		logger.Panicf("[channel: %s] Could not retrieve orderer config to validate the transaction classes", channelID)
+	}
+	batchTimeout := ordererConfig.BatchTimeout()
+
+	var txClasses []blockcutter.TxClass
+	for _, class := range classes {
+		if class.MaxBatchDelay > batchTimeout {
+			logger.Warningf("[channel: %s] Transaction class %s is ignored, as its maximum batch delay %s exceeds the batch timeout %s", channelID, class.Name, class.MaxBatchDelay, batchTimeout)
+			continue
+		}
+		txClasses = append(txClasses, blockcutter.TxClass{
+			Name:       class.Name,
+			Chaincodes: class.Chaincodes,
+			MSPIDs:     class.MSPIDs,
+			MaxDelay:   class.MaxBatchDelay,
+		})
+	}
+	return txClasses
+}
+
 func (cs *ChainSupport) Reader() blockledger.Reader {
 	return cs
 }
@@ -56,6 +56,16 @@ type OrdererCapabilities struct {
 	supportedReturnsOnCall map[int]struct {
 		result1 error
 	}
+	TxReorderingStub        func() bool
+	txReorderingMutex       sync.RWMutex
+	txReorderingArgsForCall []struct {
+	}
+	txReorderingReturns struct {
+		result1 bool
+	}
+	txReorderingReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	UseChannelCreationPolicyAsAdminsStub        func() bool
 	useChannelCreationPolicyAsAdminsMutex       sync.RWMutex
 	useChannelCreationPolicyAsAdminsArgsForCall []struct {
@@ -75,15 +85,16 @@ func (fake *OrdererCapabilities) ConsensusTypeMigration() bool {
 	ret, specificReturn := fake.consensusTypeMigrationReturnsOnCall[len(fake.consensusTypeMigrationArgsForCall)]
 	fake.consensusTypeMigrationArgsForCall = append(fake.consensusTypeMigrationArgsForCall, struct {
 	}{})
+	stub := fake.ConsensusTypeMigrationStub
+	fakeReturns := fake.consensusTypeMigrationReturns
 	fake.recordInvocation("ConsensusTypeMigration", []interface{}{})
 	fake.consensusTypeMigrationMutex.Unlock()
-	if fake.ConsensusTypeMigrationStub != nil {
-		return fake.ConsensusTypeMigrationStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.consensusTypeMigrationReturns
 	return fakeReturns.result1
 }
 
@@ -127,15 +138,16 @@ func (fake *OrdererCapabilities) ExpirationCheck() bool {
 	ret, specificReturn := fake.expirationCheckReturnsOnCall[len(fake.expirationCheckArgsForCall)]
 	fake.expirationCheckArgsForCall = append(fake.expirationCheckArgsForCall, struct {
 	}{})
+	stub := fake.ExpirationCheckStub
+	fakeReturns := fake.expirationCheckReturns
 	fake.recordInvocation("ExpirationCheck", []interface{}{})
 	fake.expirationCheckMutex.Unlock()
-	if fake.ExpirationCheckStub != nil {
-		return fake.ExpirationCheckStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.expirationCheckReturns
 	return fakeReturns.result1
 }
 
@@ -179,15 +191,16 @@ func (fake *OrdererCapabilities) PredictableChannelTemplate() bool {
 	ret, specificReturn := fake.predictableChannelTemplateReturnsOnCall[len(fake.predictableChannelTemplateArgsForCall)]
 	fake.predictableChannelTemplateArgsForCall = append(fake.predictableChannelTemplateArgsForCall, struct {
 	}{})
+	stub := fake.PredictableChannelTemplateStub
+	fakeReturns := fake.predictableChannelTemplateReturns
 	fake.recordInvocation("PredictableChannelTemplate", []interface{}{})
 	fake.predictableChannelTemplateMutex.Unlock()
-	if fake.PredictableChannelTemplateStub != nil {
-		return fake.PredictableChannelTemplateStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.predictableChannelTemplateReturns
 	return fakeReturns.result1
 }
 
@@ -231,15 +244,16 @@ func (fake *OrdererCapabilities) Resubmission() bool {
 	ret, specificReturn := fake.resubmissionReturnsOnCall[len(fake.resubmissionArgsForCall)]
 	fake.resubmissionArgsForCall = append(fake.resubmissionArgsForCall, struct {
 	}{})
+	stub := fake.ResubmissionStub
+	fakeReturns := fake.resubmissionReturns
 	fake.recordInvocation("Resubmission", []interface{}{})
 	fake.resubmissionMutex.Unlock()
-	if fake.ResubmissionStub != nil {
-		return fake.ResubmissionStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.resubmissionReturns
 	return fakeReturns.result1
 }
 
@@ -283,15 +297,16 @@ func (fake *OrdererCapabilities) Supported() error {
 	ret, specificReturn := fake.supportedReturnsOnCall[len(fake.supportedArgsForCall)]
 	fake.supportedArgsForCall = append(fake.supportedArgsForCall, struct {
 	}{})
+	stub := fake.SupportedStub
+	fakeReturns := fake.supportedReturns
 	fake.recordInvocation("Supported", []interface{}{})
 	fake.supportedMutex.Unlock()
-	if fake.SupportedStub != nil {
-		return fake.SupportedStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.supportedReturns
 	return fakeReturns.result1
 }
 
@@ -330,20 +345,74 @@ func (fake *OrdererCapabilities) SupportedReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *OrdererCapabilities) TxReordering() bool {
+	fake.txReorderingMutex.Lock()
+	ret, specificReturn := fake.txReorderingReturnsOnCall[len(fake.txReorderingArgsForCall)]
+	fake.txReorderingArgsForCall = append(fake.txReorderingArgsForCall, struct {
+	}{})
+	stub := fake.TxReorderingStub
+	fakeReturns := fake.txReorderingReturns
+	fake.recordInvocation("TxReordering", []interface{}{})
+	fake.txReorderingMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *OrdererCapabilities) TxReorderingCallCount() int {
+	fake.txReorderingMutex.RLock()
+	defer fake.txReorderingMutex.RUnlock()
+	return len(fake.txReorderingArgsForCall)
+}
+
+func (fake *OrdererCapabilities) TxReorderingCalls(stub func() bool) {
+	fake.txReorderingMutex.Lock()
+	defer fake.txReorderingMutex.Unlock()
+	fake.TxReorderingStub = stub
+}
+
+func (fake *OrdererCapabilities) TxReorderingReturns(result1 bool) {
+	fake.txReorderingMutex.Lock()
+	defer fake.txReorderingMutex.Unlock()
+	fake.TxReorderingStub = nil
+	fake.txReorderingReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *OrdererCapabilities) TxReorderingReturnsOnCall(i int, result1 bool) {
+	fake.txReorderingMutex.Lock()
+	defer fake.txReorderingMutex.Unlock()
+	fake.TxReorderingStub = nil
+	if fake.txReorderingReturnsOnCall == nil {
+		fake.txReorderingReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.txReorderingReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *OrdererCapabilities) UseChannelCreationPolicyAsAdmins() bool {
 	fake.useChannelCreationPolicyAsAdminsMutex.Lock()
 	ret, specificReturn := fake.useChannelCreationPolicyAsAdminsReturnsOnCall[len(fake.useChannelCreationPolicyAsAdminsArgsForCall)]
 	fake.useChannelCreationPolicyAsAdminsArgsForCall = append(fake.useChannelCreationPolicyAsAdminsArgsForCall, struct {
 	}{})
+	stub := fake.UseChannelCreationPolicyAsAdminsStub
+	fakeReturns := fake.useChannelCreationPolicyAsAdminsReturns
 	fake.recordInvocation("UseChannelCreationPolicyAsAdmins", []interface{}{})
 	fake.useChannelCreationPolicyAsAdminsMutex.Unlock()
-	if fake.UseChannelCreationPolicyAsAdminsStub != nil {
-		return fake.UseChannelCreationPolicyAsAdminsStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.useChannelCreationPolicyAsAdminsReturns
 	return fakeReturns.result1
 }
 
@@ -395,6 +464,8 @@ func (fake *OrdererCapabilities) Invocations() map[string][][]interface{} {
 	defer fake.resubmissionMutex.RUnlock()
 	fake.supportedMutex.RLock()
 	defer fake.supportedMutex.RUnlock()
+	fake.txReorderingMutex.RLock()
+	defer fake.txReorderingMutex.RUnlock()
 	fake.useChannelCreationPolicyAsAdminsMutex.RLock()
 	defer fake.useChannelCreationPolicyAsAdminsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
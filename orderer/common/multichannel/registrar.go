@@ -10,10 +10,12 @@ SPDX-License-Identifier: Apache-2.0
 package multichannel
 
 import (
+	"bytes"
 	"fmt"
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	cb "github.com/hyperledger/fabric-protos-go/common"
@@ -757,9 +759,79 @@ func (r *Registrar) ChannelInfo(channelID string) (types.ChannelInfo, error) {
 	return types.ChannelInfo{}, types.ErrChannelNotExist
 }
 
+// ConsensusInfo provides the consensus status of a channel.
+// The Name field is set to the channel ID.
+func (r *Registrar) ConsensusInfo(channelID string) (types.ConsensusInfo, error) {
+	controller, err := r.consensusController(channelID)
+	if err != nil {
+		return types.ConsensusInfo{}, err
+	}
+
+	info, err := controller.ConsensusInfo()
+	if err != nil {
+		return types.ConsensusInfo{}, err
+	}
+	info.Name = channelID
+
+	return info, nil
+}
+
+// ForceViewChange instructs the orderer to vote for a view change away from the current leader of a channel.
+func (r *Registrar) ForceViewChange(channelID string) error {
+	controller, err := r.consensusController(channelID)
+	if err != nil {
+		return err
+	}
+
+	return controller.ForceViewChange()
+}
+
+// BlacklistLeader instructs the orderer to vote for a view change whenever the given node is the leader
+// of a channel, until the given duration elapses.
+func (r *Registrar) BlacklistLeader(channelID string, id uint64, duration time.Duration) error {
+	controller, err := r.consensusController(channelID)
+	if err != nil {
+		return err
+	}
+
+	return controller.BlacklistLeader(id, duration)
+}
+
+func (r *Registrar) consensusController(channelID string) (consensus.ConsensusController, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	cs, ok := r.chains[channelID]
+	if !ok {
+		return nil, types.ErrChannelNotExist
+	}
+
+	controller, ok := cs.Chain.(consensus.ConsensusController)
+	if !ok {
+		return nil, types.ErrConsensusControlNotSupported
+	}
+
+	return controller, nil
+}
+
 // JoinChannel instructs the orderer to create a channel and join it with the provided config block.
 // The URL field is empty, and is to be completed by the caller.
 func (r *Registrar) JoinChannel(channelID string, configBlock *cb.Block, isAppChannel bool) (info types.ChannelInfo, err error) {
+	return r.joinChannel(channelID, configBlock, isAppChannel, false)
+}
+
+// JoinChannelFromCheckpoint instructs the orderer to create an application channel whose ledger starts at the
+// provided config block, rather than at the genesis block, and join it. The blocks preceding the checkpoint are
+// neither pulled nor served. The checkpoint block is a trust anchor which the operator must vet, for instance by
+// comparing its hash with the one reported by the orderers of other organizations: its signatures are verified
+// against the block validation policy of the config it carries, and not against any config the orderer trusts, so
+// they only show that the block was not altered since the holders of the keys of that config signed it.
+// The URL field is empty, and is to be completed by the caller.
+func (r *Registrar) JoinChannelFromCheckpoint(channelID string, checkpointBlock *cb.Block) (info types.ChannelInfo, err error) {
+	return r.joinChannel(channelID, checkpointBlock, true, true)
+}
+
+func (r *Registrar) joinChannel(channelID string, configBlock *cb.Block, isAppChannel, fromCheckpoint bool) (info types.ChannelInfo, err error) {
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
@@ -793,6 +865,17 @@ func (r *Registrar) JoinChannel(channelID string, configBlock *cb.Block, isAppCh
 			}
 		}
 	}()
+
+	if fromCheckpoint {
+		if err := r.verifyCheckpoint(configBlock); err != nil {
+			return types.ChannelInfo{}, errors.WithMessage(err, "invalid checkpoint block")
+		}
+		if _, err := r.ledgerFactory.CreateFromCheckpoint(channelID, configBlock); err != nil {
+			return types.ChannelInfo{}, err
+		}
+		logger.Infof("Created the ledger of channel %s from checkpoint block %d", channelID, configBlock.Header.Number)
+	}
+
 	ledgerRes, clusterConsenter, err := r.initLedgerResourcesClusterConsenter(configBlock)
 	if err != nil {
 		return types.ChannelInfo{}, err
@@ -845,6 +928,31 @@ func (r *Registrar) JoinChannel(channelID string, configBlock *cb.Block, isAppCh
 	return info, err
 }
 
+// verifyCheckpoint verifies that a checkpoint block follows the genesis block, that its data matches its header and
+// its metadata points to itself as the last config block, and that its signatures satisfy the block validation
+// policy of the config it carries. Since that config comes with the block, the checks are consistency checks and
+// do not establish that the block belongs to the channel.
+func (r *Registrar) verifyCheckpoint(block *cb.Block) error {
+	if block.Header.Number == 0 {
+		return errors.New("the genesis block cannot be used as a checkpoint, join the channel with it instead")
+	}
+	if !bytes.Equal(protoutil.BlockDataHash(block.Data), block.Header.DataHash) {
+		return errors.New("the data hash in the block header does not match the block data")
+	}
+	lastConfig, err := protoutil.GetLastConfigIndexFromBlock(block)
+	if err != nil {
+		return err
+	}
+	if lastConfig != block.Header.Number {
+		return errors.Errorf("the last config index in the block metadata is %d, not the block number %d", lastConfig, block.Header.Number)
+	}
+	verify := cluster.BlockVerifierBuilder(r.bccsp)(block)
+	if err := verify(block.Header, block.Metadata); err != nil {
+		return errors.WithMessage(err, "the block signatures do not satisfy its block validation policy")
+	}
+	return nil
+}
+
 func (r *Registrar) createAsMember(ledgerRes *ledgerResources, configBlock *cb.Block, channelID string) (*ChainSupport, types.ChannelInfo, error) {
 	if ledgerRes.Height() == 0 {
 		if err := ledgerRes.Append(configBlock); err != nil {
@@ -20,6 +20,7 @@ import (
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric/bccsp"
+	"github.com/hyperledger/fabric/bccsp/factory"
 	"github.com/hyperledger/fabric/bccsp/sw"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto/tlsgen"
@@ -27,11 +28,14 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/blockledger/fileledger"
 	"github.com/hyperledger/fabric/common/metrics/disabled"
 	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/config/configtest"
 	"github.com/hyperledger/fabric/internal/configtxgen/encoder"
 	"github.com/hyperledger/fabric/internal/configtxgen/genesisconfig"
 	"github.com/hyperledger/fabric/internal/pkg/comm"
 	"github.com/hyperledger/fabric/internal/pkg/identity"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	msptesttools "github.com/hyperledger/fabric/msp/mgmt/testtools"
 	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	"github.com/hyperledger/fabric/orderer/common/cluster"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
@@ -1306,6 +1310,65 @@ func TestRegistrar_JoinChannel(t *testing.T) {
 		checkMetrics(t, fakeFields, []string{"channel", "my-raft-channel"}, 2, 1, 3)
 	})
 
+	t.Run("Join app channel from checkpoint as follower", func(t *testing.T) {
+		setup(t)
+		defer cleanup()
+
+		consenter.IsChannelMemberReturns(false, nil)
+		registrar := NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, dialer)
+		registrar.Initialize(mockConsenters)
+
+		checkpoint := checkpointBlock(t, genesisBlockAppRaft, 10)
+		info, err := registrar.JoinChannelFromCheckpoint("my-raft-channel", checkpoint)
+		require.NoError(t, err)
+		require.Equal(t, types.ChannelInfo{Name: "my-raft-channel", URL: "", ConsensusRelation: "follower", Status: "active", Height: 11}, info)
+
+		fChain := registrar.GetFollower("my-raft-channel")
+		require.NotNil(t, fChain)
+		fChain.Halt()
+
+		ledgerRW, err := ledgerFactory.GetOrCreate("my-raft-channel")
+		require.NoError(t, err)
+		require.Equal(t, uint64(11), ledgerRW.Height(), "checkpoint was appended")
+		iterator, startNum := ledgerRW.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Oldest{Oldest: &ab.SeekOldest{}}})
+		defer iterator.Close()
+		require.Equal(t, uint64(10), startNum)
+		block, status := iterator.Next()
+		require.Equal(t, cb.Status_SUCCESS, status)
+		require.True(t, proto.Equal(checkpoint, block))
+	})
+
+	t.Run("Reject invalid checkpoint", func(t *testing.T) {
+		setup(t)
+		defer cleanup()
+
+		consenter.IsChannelMemberReturns(false, nil)
+		registrar := NewRegistrar(config, ledgerFactory, mockCrypto(), &disabled.Provider{}, cryptoProvider, dialer)
+		registrar.Initialize(mockConsenters)
+
+		_, err := registrar.JoinChannelFromCheckpoint("my-raft-channel", genesisBlockAppRaft)
+		require.EqualError(t, err, "invalid checkpoint block: the genesis block cannot be used as a checkpoint, join the channel with it instead")
+
+		badDataHash := checkpointBlock(t, genesisBlockAppRaft, 10)
+		badDataHash.Header.DataHash = []byte("bad-hash")
+		_, err = registrar.JoinChannelFromCheckpoint("my-raft-channel", badDataHash)
+		require.EqualError(t, err, "invalid checkpoint block: the data hash in the block header does not match the block data")
+
+		unsigned := protoutil.UnmarshalBlockOrPanic(protoutil.MarshalOrPanic(genesisBlockAppRaft))
+		unsigned.Header.Number = 10
+		_, err = registrar.JoinChannelFromCheckpoint("my-raft-channel", unsigned)
+		require.EqualError(t, err, "invalid checkpoint block: the last config index in the block metadata is 0, not the block number 10")
+
+		tampered := checkpointBlock(t, genesisBlockAppRaft, 10)
+		tampered.Header.PreviousHash = []byte("another-previous-hash")
+		_, err = registrar.JoinChannelFromCheckpoint("my-raft-channel", tampered)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid checkpoint block: the block signatures do not satisfy its block validation policy")
+
+		require.Nil(t, registrar.GetFollower("my-raft-channel"))
+		require.NotContains(t, ledgerFactory.ChannelIDs(), "my-raft-channel")
+	})
+
 	t.Run("Join system channel without on-boarding", func(t *testing.T) {
 		setup(t)
 		defer cleanup()
@@ -1731,6 +1794,34 @@ func TestRegistrar_RemoveChannel(t *testing.T) {
 	})
 }
 
+// checkpointBlock returns a copy of the config block numbered as the given block and signed by the SampleOrg
+// orderer, the way the block writer signs the blocks it cuts.
+func checkpointBlock(t *testing.T, configBlock *cb.Block, number uint64) *cb.Block {
+	require.NoError(t, msptesttools.LoadMSPSetupForTesting())
+	signer, err := mspmgmt.GetLocalMSP(factory.GetDefault()).GetDefaultSigningIdentity()
+	require.NoError(t, err)
+
+	block := protoutil.UnmarshalBlockOrPanic(protoutil.MarshalOrPanic(configBlock))
+	block.Header.Number = number
+	block.Header.PreviousHash = []byte("previous-hash")
+
+	blockSignature := &cb.MetadataSignature{
+		SignatureHeader: protoutil.MarshalOrPanic(protoutil.NewSignatureHeaderOrPanic(signer)),
+	}
+	blockSignatureValue := protoutil.MarshalOrPanic(&cb.OrdererBlockMetadata{
+		LastConfig: &cb.LastConfig{Index: number},
+	})
+	blockSignature.Signature = protoutil.SignOrPanic(
+		signer,
+		util.ConcatenateBytes(blockSignatureValue, blockSignature.SignatureHeader, protoutil.BlockHeaderBytes(block.Header)),
+	)
+	block.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(&cb.Metadata{
+		Value:      blockSignatureValue,
+		Signatures: []*cb.MetadataSignature{blockSignature},
+	})
+	return block
+}
+
 func generateCertificates(t *testing.T, confAppRaft *genesisconfig.Profile, tlsCA tlsgen.CA, certDir string) {
 	for i, c := range confAppRaft.Orderer.EtcdRaft.Consenters {
 		srvC, err := tlsCA.NewServerCertKeyPair(c.Host)
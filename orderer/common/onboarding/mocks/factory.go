@@ -4,6 +4,7 @@ package mocks
 import (
 	"sync"
 
+	"github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 )
 
@@ -22,6 +23,20 @@ type Factory struct {
 	closeMutex       sync.RWMutex
 	closeArgsForCall []struct {
 	}
+	CreateFromCheckpointStub        func(string, *common.Block) (blockledger.ReadWriter, error)
+	createFromCheckpointMutex       sync.RWMutex
+	createFromCheckpointArgsForCall []struct {
+		arg1 string
+		arg2 *common.Block
+	}
+	createFromCheckpointReturns struct {
+		result1 blockledger.ReadWriter
+		result2 error
+	}
+	createFromCheckpointReturnsOnCall map[int]struct {
+		result1 blockledger.ReadWriter
+		result2 error
+	}
 	GetOrCreateStub        func(string) (blockledger.ReadWriter, error)
 	getOrCreateMutex       sync.RWMutex
 	getOrCreateArgsForCall []struct {
@@ -55,15 +70,16 @@ func (fake *Factory) ChannelIDs() []string {
 	ret, specificReturn := fake.channelIDsReturnsOnCall[len(fake.channelIDsArgsForCall)]
 	fake.channelIDsArgsForCall = append(fake.channelIDsArgsForCall, struct {
 	}{})
+	stub := fake.ChannelIDsStub
+	fakeReturns := fake.channelIDsReturns
 	fake.recordInvocation("ChannelIDs", []interface{}{})
 	fake.channelIDsMutex.Unlock()
-	if fake.ChannelIDsStub != nil {
-		return fake.ChannelIDsStub()
+	if stub != nil {
+		return stub()
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.channelIDsReturns
 	return fakeReturns.result1
 }
 
@@ -106,9 +122,10 @@ func (fake *Factory) Close() {
 	fake.closeMutex.Lock()
 	fake.closeArgsForCall = append(fake.closeArgsForCall, struct {
 	}{})
+	stub := fake.CloseStub
 	fake.recordInvocation("Close", []interface{}{})
 	fake.closeMutex.Unlock()
-	if fake.CloseStub != nil {
+	if stub != nil {
 		fake.CloseStub()
 	}
 }
@@ -125,21 +142,87 @@ func (fake *Factory) CloseCalls(stub func()) {
 	fake.CloseStub = stub
 }
 
+func (fake *Factory) CreateFromCheckpoint(arg1 string, arg2 *common.Block) (blockledger.ReadWriter, error) {
+	fake.createFromCheckpointMutex.Lock()
+	ret, specificReturn := fake.createFromCheckpointReturnsOnCall[len(fake.createFromCheckpointArgsForCall)]
+	fake.createFromCheckpointArgsForCall = append(fake.createFromCheckpointArgsForCall, struct {
+		arg1 string
+		arg2 *common.Block
+	}{arg1, arg2})
+	stub := fake.CreateFromCheckpointStub
+	fakeReturns := fake.createFromCheckpointReturns
+	fake.recordInvocation("CreateFromCheckpoint", []interface{}{arg1, arg2})
+	fake.createFromCheckpointMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Factory) CreateFromCheckpointCallCount() int {
+	fake.createFromCheckpointMutex.RLock()
+	defer fake.createFromCheckpointMutex.RUnlock()
+	return len(fake.createFromCheckpointArgsForCall)
+}
+
+func (fake *Factory) CreateFromCheckpointCalls(stub func(string, *common.Block) (blockledger.ReadWriter, error)) {
+	fake.createFromCheckpointMutex.Lock()
+	defer fake.createFromCheckpointMutex.Unlock()
+	fake.CreateFromCheckpointStub = stub
+}
+
+func (fake *Factory) CreateFromCheckpointArgsForCall(i int) (string, *common.Block) {
+	fake.createFromCheckpointMutex.RLock()
+	defer fake.createFromCheckpointMutex.RUnlock()
+	argsForCall := fake.createFromCheckpointArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Factory) CreateFromCheckpointReturns(result1 blockledger.ReadWriter, result2 error) {
+	fake.createFromCheckpointMutex.Lock()
+	defer fake.createFromCheckpointMutex.Unlock()
+	fake.CreateFromCheckpointStub = nil
+	fake.createFromCheckpointReturns = struct {
+		result1 blockledger.ReadWriter
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Factory) CreateFromCheckpointReturnsOnCall(i int, result1 blockledger.ReadWriter, result2 error) {
+	fake.createFromCheckpointMutex.Lock()
+	defer fake.createFromCheckpointMutex.Unlock()
+	fake.CreateFromCheckpointStub = nil
+	if fake.createFromCheckpointReturnsOnCall == nil {
+		fake.createFromCheckpointReturnsOnCall = make(map[int]struct {
+			result1 blockledger.ReadWriter
+			result2 error
+		})
+	}
+	fake.createFromCheckpointReturnsOnCall[i] = struct {
+		result1 blockledger.ReadWriter
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Factory) GetOrCreate(arg1 string) (blockledger.ReadWriter, error) {
 	fake.getOrCreateMutex.Lock()
 	ret, specificReturn := fake.getOrCreateReturnsOnCall[len(fake.getOrCreateArgsForCall)]
 	fake.getOrCreateArgsForCall = append(fake.getOrCreateArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.GetOrCreateStub
+	fakeReturns := fake.getOrCreateReturns
 	fake.recordInvocation("GetOrCreate", []interface{}{arg1})
 	fake.getOrCreateMutex.Unlock()
-	if fake.GetOrCreateStub != nil {
-		return fake.GetOrCreateStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.getOrCreateReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
@@ -194,15 +277,16 @@ func (fake *Factory) Remove(arg1 string) error {
 	fake.removeArgsForCall = append(fake.removeArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.RemoveStub
+	fakeReturns := fake.removeReturns
 	fake.recordInvocation("Remove", []interface{}{arg1})
 	fake.removeMutex.Unlock()
-	if fake.RemoveStub != nil {
-		return fake.RemoveStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.removeReturns
 	return fakeReturns.result1
 }
 
@@ -255,6 +339,8 @@ func (fake *Factory) Invocations() map[string][][]interface{} {
 	defer fake.channelIDsMutex.RUnlock()
 	fake.closeMutex.RLock()
 	defer fake.closeMutex.RUnlock()
+	fake.createFromCheckpointMutex.RLock()
+	defer fake.createFromCheckpointMutex.RUnlock()
 	fake.getOrCreateMutex.RLock()
 	defer fake.getOrCreateMutex.RUnlock()
 	fake.removeMutex.RLock()
@@ -45,6 +45,7 @@ type ReplicationInitiator struct {
 	lf                cluster.LedgerFactory
 	signer            identity.SignerSerializer
 	cryptoProvider    bccsp.BCCSP
+	bandwidthLimiter  *cluster.BandwidthLimiter
 }
 
 func NewReplicationInitiator(
@@ -96,6 +97,7 @@ func NewReplicationInitiator(
 		lf:                ledgerFactory,
 		signer:            signer,
 		cryptoProvider:    bccsp,
+		bandwidthLimiter:  cluster.NewBandwidthLimiter(conf.General.Cluster.ReplicationMaxBandwidth),
 	}
 }
 
@@ -119,6 +121,7 @@ func (ri *ReplicationInitiator) createReplicator(bootstrapBlock *common.Block, f
 		ri.logger.Panicf("Failed extracting system channel name from bootstrap block: %v", err)
 	}
 	pullerConfig := cluster.PullerConfigFromTopLevelConfig(systemChannelName, ri.conf, ri.secOpts.Key, ri.secOpts.Certificate, ri.signer)
+	pullerConfig.BandwidthLimiter = ri.bandwidthLimiter
 	puller, err := cluster.BlockPullerFromConfigBlock(pullerConfig, bootstrapBlock, ri.verifierRetriever, ri.cryptoProvider)
 	if err != nil {
 		ri.logger.Panicf("Failed creating puller config from bootstrap block: %v", err)
@@ -24,6 +24,7 @@ import (
 	ab "github.com/hyperledger/fabric-protos-go/orderer"
 	"github.com/hyperledger/fabric/bccsp"
 	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/audit"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/fabhttp"
@@ -85,6 +86,10 @@ func Main() {
 		os.Exit(1)
 	}
 	initializeLogging()
+	if err := flogging.SetSinks(logSinks(conf.Logging)); err != nil {
+		logger.Panicf("Failed to initialize log sinks: %s", err)
+	}
+	defer flogging.SetSinks(nil)
 
 	prettyPrintStruct(conf)
 
@@ -95,7 +100,18 @@ func Main() {
 		logger.Panicf("Failed to get local MSP identity: %s", signErr)
 	}
 
-	opsSystem := newOperationsSystem(conf.Operations, conf.Metrics)
+	auditLogger, err := audit.NewFileLogger(audit.Config{
+		Enabled:    conf.Audit.Enabled,
+		File:       conf.Audit.File,
+		MaxSize:    conf.Audit.MaxSize,
+		MaxBackups: conf.Audit.MaxBackups,
+	})
+	if err != nil {
+		logger.Panicf("Failed to initialize audit log: %s", err)
+	}
+	defer auditLogger.Close()
+
+	opsSystem := newOperationsSystem(conf.Operations, conf.Metrics, auditLogger)
 	if err = opsSystem.Start(); err != nil {
 		logger.Panicf("failed to start operations subsystem: %s", err)
 	}
@@ -141,7 +157,8 @@ func Main() {
 	logger.Infof("Setting up cluster")
 	clusterClientConfig, reuseGrpcListener = initializeClusterClientConfig(conf)
 	clusterDialer = &cluster.PredicateDialer{
-		Config: clusterClientConfig,
+		Config:           clusterClientConfig,
+		BandwidthLimiter: cluster.NewBandwidthLimiter(conf.General.Cluster.ReplicationMaxBandwidth),
 	}
 
 	if !reuseGrpcListener {
@@ -185,10 +202,12 @@ func Main() {
 
 	manager := initializeMultichannelRegistrar(clusterDialer, clusterServerConfig, clusterGRPCServer, conf, signer, metricsProvider, lf, cryptoProvider, tlsCallback)
 
+	channelParticipationHandler := channelparticipation.NewHTTPHandler(conf.ChannelParticipation, manager)
+	channelParticipationHandler.AuditLogger = auditLogger
 	adminServer := newAdminServer(conf.Admin)
 	adminServer.RegisterHandler(
 		channelparticipation.URLBaseV1,
-		channelparticipation.NewHTTPHandler(conf.ChannelParticipation, manager),
+		channelParticipationHandler,
 		conf.Admin.TLS.Enabled,
 	)
 	if err = adminServer.Start(); err != nil {
@@ -312,6 +331,28 @@ func initializeLogging() {
 	})
 }
 
+func logSinks(conf localconfig.Logging) []flogging.SinkConfig {
+	var sinks []flogging.SinkConfig
+	for _, s := range conf.Sinks {
+		sinks = append(sinks, flogging.SinkConfig{
+			Name:             s.Name,
+			Type:             s.Type,
+			Spec:             s.Spec,
+			Format:           s.Format,
+			Loggers:          s.Loggers,
+			File:             s.File,
+			MaxSize:          s.MaxSize,
+			RotationInterval: s.RotationInterval,
+			MaxBackups:       s.MaxBackups,
+			Network:          s.Network,
+			Address:          s.Address,
+			Facility:         s.Facility,
+			AppName:          s.AppName,
+		})
+	}
+	return sinks
+}
+
 // Start the profiling service if enabled.
 func initializeProfilingService(conf *localconfig.TopLevel) {
 	logger.Info("Starting Go pprof profiling service on:", conf.General.Profile.Address)
@@ -656,7 +697,7 @@ func initializeEtcdraftConsenter(consenters map[string]consensus.Consenter, conf
 	consenters["etcdraft"] = raftConsenter
 }
 
-func newOperationsSystem(ops localconfig.Operations, metrics localconfig.Metrics) *operations.System {
+func newOperationsSystem(ops localconfig.Operations, metrics localconfig.Metrics, auditLogger *audit.Logger) *operations.System {
 	return operations.NewSystem(operations.Options{
 		Options: fabhttp.Options{
 			Logger:        flogging.MustGetLogger("orderer.operations"),
@@ -678,7 +719,8 @@ func newOperationsSystem(ops localconfig.Operations, metrics localconfig.Metrics
 				Prefix:        metrics.Statsd.Prefix,
 			},
 		},
-		Version: metadata.Version,
+		Version:     metadata.Version,
+		AuditLogger: auditLogger,
 	})
 }
 
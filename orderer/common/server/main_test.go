@@ -964,7 +964,7 @@ func TestInitializeEtcdraftConsenter(t *testing.T) {
 		rlf,
 		&cluster.PredicateDialer{},
 		genesisBlock,
-		onboarding.NewReplicationInitiator(rlf, genesisBlock, nil, comm.SecureOptions{}, nil, cryptoProvider),
+		onboarding.NewReplicationInitiator(rlf, genesisBlock, &localconfig.TopLevel{}, comm.SecureOptions{}, nil, cryptoProvider),
 		comm.ServerConfig{
 			SecOpts: comm.SecureOptions{
 				Certificate: crt.Cert,
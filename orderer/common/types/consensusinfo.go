@@ -0,0 +1,38 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package types
+
+// ConsensusInfo carries the response to an HTTP request for the consensus status of a single channel.
+// This is marshaled into the body of the HTTP response.
+// swagger:model consensusInfo
+type ConsensusInfo struct {
+	// The channel name.
+	Name string `json:"name"`
+	// The view number according to the latest committed block.
+	View uint64 `json:"view"`
+	// The ID of the current leader, 0 if consensus is not running.
+	LeaderID uint64 `json:"leaderID"`
+	// The sequence of the latest committed decision.
+	DecisionSequence uint64 `json:"decisionSequence"`
+	// The number of decisions committed in the current view.
+	DecisionsInView uint64 `json:"decisionsInView"`
+	// The number of requests pending in the request pool of this node.
+	PendingRequests int `json:"pendingRequests"`
+	// The IDs of the nodes blacklisted by the consensus protocol, according to the latest committed block.
+	Blacklist []uint64 `json:"blacklist"`
+	// The IDs of the leaders temporarily blacklisted by the operator of this node.
+	SuspectedLeaders []uint64 `json:"suspectedLeaders"`
+}
+
+// BlacklistLeaderRequest carries the body of an HTTP request to temporarily blacklist a leader.
+// swagger:model blacklistLeaderRequest
+type BlacklistLeaderRequest struct {
+	// The ID of the node to blacklist.
+	ID uint64 `json:"id"`
+	// How long the node stays blacklisted, as a Go duration string, e.g. "10m".
+	Duration string `json:"duration"`
+}
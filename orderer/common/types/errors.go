@@ -27,3 +27,7 @@ var ErrChannelPendingRemoval = errors.New("channel pending removal")
 
 // ErrChannelRemovalFailure is returned when a removal attempt failure has been recorded.
 var ErrChannelRemovalFailure = errors.New("channel removal failure")
+
+// ErrConsensusControlNotSupported is returned when trying to query or control the consensus protocol of a channel
+// whose consensus type does not expose operator controls.
+var ErrConsensusControlNotSupported = errors.New("consensus type does not support operator controls")
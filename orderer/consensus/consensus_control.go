@@ -0,0 +1,32 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package consensus
+
+import (
+	"time"
+
+	"github.com/hyperledger/fabric/orderer/common/types"
+)
+
+// ConsensusController is implemented by Chain implementations which expose operator
+// controls over their consensus protocol (e.g. smartbft).
+// It is used to serve the consensus requests of the channel participation API.
+//
+// Chains which do not implement it cause those requests to fail with
+// types.ErrConsensusControlNotSupported.
+type ConsensusController interface {
+	// ConsensusInfo reports the consensus status of the chain.
+	// The Name field is empty, and is to be completed by the caller.
+	ConsensusInfo() (types.ConsensusInfo, error)
+
+	// ForceViewChange makes this node vote for a view change away from the current leader.
+	ForceViewChange() error
+
+	// BlacklistLeader makes this node vote for a view change whenever the given node
+	// is the leader, until the given duration elapses.
+	BlacklistLeader(id uint64, duration time.Duration) error
+}
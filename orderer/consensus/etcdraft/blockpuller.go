@@ -93,6 +93,7 @@ func NewBlockPuller(support consensus.ConsenterSupport,
 		Logger:              flogging.MustGetLogger("orderer.common.cluster.puller").With("channel", support.ChannelID()),
 		RetryTimeout:        clusterConfig.ReplicationRetryTimeout,
 		MaxTotalBufferBytes: clusterConfig.ReplicationBufferSize,
+		BandwidthLimiter:    baseDialer.BandwidthLimiter,
 		FetchTimeout:        clusterConfig.ReplicationPullTimeout,
 		Endpoints:           endpoints,
 		Signer:              support,
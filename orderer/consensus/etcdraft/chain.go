@@ -620,11 +620,15 @@ func (c *Chain) run() {
 		<-timer.C()
 	}
 
+	var timerExpiry time.Time
+
 	// if timer is already started, this is a no-op
 	startTimer := func() {
 		if !ticking {
 			ticking = true
-			timer.Reset(c.support.SharedConfig().BatchTimeout())
+			batchTimeout := c.support.SharedConfig().BatchTimeout()
+			timerExpiry = c.clock.Now().Add(batchTimeout)
+			timer.Reset(batchTimeout)
 		}
 	}
 
@@ -636,6 +640,22 @@ func (c *Chain) run() {
 		ticking = false
 	}
 
+	// if the pending batch must be cut before the timer expires to honor the
+	// maximum delay of its transaction classes, the timer is brought forward
+	shortenTimer := func() {
+		deadline, ok := c.support.BlockCutter().Deadline()
+		if !ok {
+			return
+		}
+		if ticking && !deadline.Before(timerExpiry) {
+			return
+		}
+		stopTimer()
+		ticking = true
+		timerExpiry = deadline
+		timer.Reset(deadline.Sub(c.clock.Now()))
+	}
+
 	var soft raft.SoftState
 	submitC := c.submitC
 	var bc *blockCreator
@@ -731,6 +751,7 @@ func (c *Chain) run() {
 
 			if pending {
 				startTimer() // no-op if timer is already started
+				shortenTimer()
 			} else {
 				stopTimer()
 			}
@@ -396,6 +396,28 @@ var _ = Describe("Chain", func() {
 				Eventually(support.WriteBlockCallCount, LongEventualTimeout).Should(Equal(1))
 			})
 
+			It("brings the timer forward to the deadline of the pending batch", func() {
+				close(cutter.Block)
+
+				timeout := time.Second
+				support.SharedConfigReturns(mockOrdererWithBatchTimeout(timeout, nil))
+
+				err := chain.Order(env, 0)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(cutter.CurBatch, LongEventualTimeout).Should(HaveLen(1))
+
+				// the blockcutter requires the batch to be cut well before the batch timeout
+				cutter.DeadlineVal = clock.Now().Add(timeout / 4)
+				err = chain.Order(env, 0)
+				Expect(err).NotTo(HaveOccurred())
+				Eventually(cutter.CurBatch, LongEventualTimeout).Should(HaveLen(2))
+
+				clock.WaitForNWatchersAndIncrement(timeout/4, 2)
+				Eventually(support.WriteBlockCallCount, LongEventualTimeout).Should(Equal(1))
+				b, _ := support.WriteBlockArgsForCall(0)
+				Expect(b.Data.Data).To(HaveLen(2))
+			})
+
 			It("does not write a block if halted before timeout", func() {
 				close(cutter.Block)
 				timeout := time.Second
@@ -56,6 +56,16 @@ type OrdererCapabilities struct {
 	supportedReturnsOnCall map[int]struct {
 		result1 error
 	}
+	TxReorderingStub        func() bool
+	txReorderingMutex       sync.RWMutex
+	txReorderingArgsForCall []struct {
+	}
+	txReorderingReturns struct {
+		result1 bool
+	}
+	txReorderingReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	UseChannelCreationPolicyAsAdminsStub        func() bool
 	useChannelCreationPolicyAsAdminsMutex       sync.RWMutex
 	useChannelCreationPolicyAsAdminsArgsForCall []struct {
@@ -335,6 +345,59 @@ func (fake *OrdererCapabilities) SupportedReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *OrdererCapabilities) TxReordering() bool {
+	fake.txReorderingMutex.Lock()
+	ret, specificReturn := fake.txReorderingReturnsOnCall[len(fake.txReorderingArgsForCall)]
+	fake.txReorderingArgsForCall = append(fake.txReorderingArgsForCall, struct {
+	}{})
+	stub := fake.TxReorderingStub
+	fakeReturns := fake.txReorderingReturns
+	fake.recordInvocation("TxReordering", []interface{}{})
+	fake.txReorderingMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *OrdererCapabilities) TxReorderingCallCount() int {
+	fake.txReorderingMutex.RLock()
+	defer fake.txReorderingMutex.RUnlock()
+	return len(fake.txReorderingArgsForCall)
+}
+
+func (fake *OrdererCapabilities) TxReorderingCalls(stub func() bool) {
+	fake.txReorderingMutex.Lock()
+	defer fake.txReorderingMutex.Unlock()
+	fake.TxReorderingStub = stub
+}
+
+func (fake *OrdererCapabilities) TxReorderingReturns(result1 bool) {
+	fake.txReorderingMutex.Lock()
+	defer fake.txReorderingMutex.Unlock()
+	fake.TxReorderingStub = nil
+	fake.txReorderingReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *OrdererCapabilities) TxReorderingReturnsOnCall(i int, result1 bool) {
+	fake.txReorderingMutex.Lock()
+	defer fake.txReorderingMutex.Unlock()
+	fake.TxReorderingStub = nil
+	if fake.txReorderingReturnsOnCall == nil {
+		fake.txReorderingReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.txReorderingReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *OrdererCapabilities) UseChannelCreationPolicyAsAdmins() bool {
 	fake.useChannelCreationPolicyAsAdminsMutex.Lock()
 	ret, specificReturn := fake.useChannelCreationPolicyAsAdminsReturnsOnCall[len(fake.useChannelCreationPolicyAsAdminsArgsForCall)]
@@ -401,6 +464,8 @@ func (fake *OrdererCapabilities) Invocations() map[string][][]interface{} {
 	defer fake.resubmissionMutex.RUnlock()
 	fake.supportedMutex.RLock()
 	defer fake.supportedMutex.RUnlock()
+	fake.txReorderingMutex.RLock()
+	defer fake.txReorderingMutex.RUnlock()
 	fake.useChannelCreationPolicyAsAdminsMutex.RLock()
 	defer fake.useChannelCreationPolicyAsAdminsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -10,6 +10,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -75,6 +76,7 @@ type BFTChain struct {
 	Logger           *flogging.FabricLogger
 	WALDir           string
 	consensus        *smartbft.Consensus
+	control          consensusControl
 	support          consensus.ConsenterSupport
 	clusterService   *cluster.ClusterService
 	verifier         *Verifier
@@ -85,6 +87,9 @@ type BFTChain struct {
 	statusReportMutex sync.Mutex
 	consensusRelation types2.ConsensusRelation
 	status            types2.Status
+
+	blacklistLock sync.Mutex
+	blacklist     map[uint64]time.Time // leader ID -> expiration of its blacklisting by the operator
 }
 
 // NewChain creates new BFT Smart chain
@@ -128,8 +133,12 @@ func NewChain(
 			CommittedBlockNumber: metrics.CommittedBlockNumber.With("channel", support.ChannelID()),
 			IsLeader:             metrics.IsLeader.With("channel", support.ChannelID()),
 			LeaderID:             metrics.LeaderID.With("channel", support.ChannelID()),
+			ViewNumber:           metrics.ViewNumber.With("channel", support.ChannelID()),
+			PendingRequests:      metrics.PendingRequests.With("channel", support.ChannelID()),
+			OperatorViewChanges:  metrics.OperatorViewChanges.With("channel", support.ChannelID()),
 		},
-		bccsp: bccsp,
+		bccsp:     bccsp,
+		blacklist: map[uint64]time.Time{},
 	}
 
 	lastBlock := LastBlockFromLedgerOrPanic(support, c.Logger)
@@ -152,6 +161,7 @@ func NewChain(
 
 	c.verifier = buildVerifier(cv, c.RuntimeConfig, support, requestInspector, policyManager)
 	c.consensus = bftSmartConsensusBuild(c, requestInspector)
+	c.control = &smartBFTControl{Consensus: c.consensus}
 
 	// Setup communication with list of remotes notes for the new channel
 	c.Comm.Configure(c.support.ChannelID(), rtc.RemoteNodes)
@@ -378,6 +388,8 @@ func (c *BFTChain) Deliver(proposal types.Proposal, signatures []types.Signature
 		c.Config.SelfID)
 	c.Metrics.CommittedBlockNumber.Set(float64(block.Header.Number)) // report the committed block number
 	c.reportIsLeader()                                               // report the leader
+	c.reportView(proposal.Metadata)                                  // report the view and the request pool
+	c.complainIfLeaderBlacklisted()
 	if protoutil.IsConfigBlock(block) {
 		c.support.WriteConfigBlock(block, nil)
 	} else {
@@ -412,6 +424,9 @@ func (c *BFTChain) Start() {
 	if err := c.consensus.Start(); err != nil {
 		c.Logger.Panicf("Failed to start chain, aborting: %+v", err)
 	}
+	if control, ok := c.control.(*smartBFTControl); ok && c.consensus.Pool != nil {
+		control.setPool(c.consensus.Pool)
+	}
 	c.reportIsLeader() // report the leader
 }
 
@@ -552,6 +567,183 @@ func (c *BFTChain) reportIsLeader() {
 	}
 }
 
+func (c *BFTChain) reportView(metadata []byte) {
+	viewMetadata := &smartbftprotos.ViewMetadata{}
+	if err := proto.Unmarshal(metadata, viewMetadata); err != nil {
+		c.Logger.Warnf("Failed unmarshaling view metadata: %v", err)
+		return
+	}
+	c.Metrics.ViewNumber.Set(float64(viewMetadata.ViewId))
+	c.Metrics.PendingRequests.Set(float64(c.control.PendingRequests()))
+}
+
+// ConsensusInfo reports the view, leader, decision sequence and request pool size of this node.
+func (c *BFTChain) ConsensusInfo() (types2.ConsensusInfo, error) {
+	rtc := c.RuntimeConfig.Load().(RuntimeConfig)
+	viewMetadata, err := getViewMetadataFromBlock(rtc.LastBlock)
+	if err != nil {
+		return types2.ConsensusInfo{}, errors.WithMessage(err, "failed extracting view metadata from the last block")
+	}
+
+	info := types2.ConsensusInfo{
+		View:             viewMetadata.ViewId,
+		LeaderID:         c.control.GetLeaderID(),
+		DecisionSequence: viewMetadata.LatestSequence,
+		DecisionsInView:  viewMetadata.DecisionsInView,
+		Blacklist:        viewMetadata.BlackList,
+		SuspectedLeaders: c.blacklistedLeaders(),
+		PendingRequests:  c.control.PendingRequests(),
+	}
+
+	return info, nil
+}
+
+// ForceViewChange makes this node vote for a view change away from the view of the latest committed block.
+// The view changes only once enough nodes vote for it.
+func (c *BFTChain) ForceViewChange() error {
+	if c.control.GetLeaderID() == 0 {
+		return errors.New("consensus is not running")
+	}
+
+	rtc := c.RuntimeConfig.Load().(RuntimeConfig)
+	viewMetadata, err := getViewMetadataFromBlock(rtc.LastBlock)
+	if err != nil {
+		return errors.WithMessage(err, "failed extracting view metadata from the last block")
+	}
+
+	c.complain(viewMetadata.ViewId)
+	return nil
+}
+
+// BlacklistLeader makes this node vote for a view change whenever the given node is the leader,
+// until the given duration elapses. Unlike the blacklist of the consensus protocol, it is local to this node.
+func (c *BFTChain) BlacklistLeader(id uint64, duration time.Duration) error {
+	if duration <= 0 {
+		return errors.Errorf("invalid blacklisting duration: %s", duration)
+	}
+	if id == c.Config.SelfID {
+		return errors.Errorf("node %d cannot blacklist itself", id)
+	}
+
+	rtc := c.RuntimeConfig.Load().(RuntimeConfig)
+	var member bool
+	for _, node := range rtc.Nodes {
+		if node == id {
+			member = true
+			break
+		}
+	}
+	if !member {
+		return errors.Errorf("node %d is not a consenter of channel %s", id, c.Channel)
+	}
+
+	c.blacklistLock.Lock()
+	c.blacklist[id] = time.Now().Add(duration)
+	c.blacklistLock.Unlock()
+
+	c.Logger.Infof("Operator blacklisted leader %d for %s", id, duration)
+	c.complainIfLeaderBlacklisted()
+	return nil
+}
+
+func (c *BFTChain) blacklistedLeaders() []uint64 {
+	c.blacklistLock.Lock()
+	defer c.blacklistLock.Unlock()
+
+	now := time.Now()
+	leaders := []uint64{}
+	for id, expiration := range c.blacklist {
+		if now.After(expiration) {
+			delete(c.blacklist, id)
+			continue
+		}
+		leaders = append(leaders, id)
+	}
+	sort.Slice(leaders, func(i, j int) bool { return leaders[i] < leaders[j] })
+
+	return leaders
+}
+
+func (c *BFTChain) complainIfLeaderBlacklisted() {
+	leaderID := c.control.GetLeaderID()
+	if leaderID == 0 {
+		return
+	}
+
+	c.blacklistLock.Lock()
+	expiration, exists := c.blacklist[leaderID]
+	if exists && time.Now().After(expiration) {
+		delete(c.blacklist, leaderID)
+		exists = false
+	}
+	c.blacklistLock.Unlock()
+
+	if !exists {
+		return
+	}
+
+	rtc := c.RuntimeConfig.Load().(RuntimeConfig)
+	viewMetadata, err := getViewMetadataFromBlock(rtc.LastBlock)
+	if err != nil {
+		c.Logger.Warnf("Failed extracting view metadata from the last block: %v", err)
+		return
+	}
+
+	c.Logger.Infof("Leader %d is blacklisted by the operator", leaderID)
+	c.complain(viewMetadata.ViewId)
+}
+
+func (c *BFTChain) complain(view uint64) {
+	c.Logger.Infof("Voting for a view change away from view %d on behalf of the operator", view)
+	c.Metrics.OperatorViewChanges.Add(1)
+	// Complaining may wait for an in-flight reconfiguration, which in turn may wait for the delivery
+	// of a decision, so it must not block the caller.
+	go c.control.Complain(view, true)
+}
+
+// consensusControl is the part of the consensus that the operator controls of the chain act upon.
+type consensusControl interface {
+	// GetLeaderID returns the ID of the current leader, or 0 when the consensus is not running.
+	GetLeaderID() uint64
+	// Complain votes for a view change away from the given view.
+	Complain(viewNum uint64, stopView bool)
+	// PendingRequests returns the number of requests waiting in the request pool.
+	PendingRequests() int
+}
+
+type smartBFTControl struct {
+	*smartbft.Consensus
+
+	poolLock sync.RWMutex
+	pool     requestPool
+}
+
+// requestPool is the part of the consensus request pool the control reads.
+type requestPool interface {
+	Size() int
+}
+
+// setPool publishes the request pool created by the consensus when it started.
+// The consensus assigns its Pool field under its own private lock, so the
+// pool is captured once by the goroutine that started it rather than being
+// read from the consensus while its goroutines run.
+func (s *smartBFTControl) setPool(pool requestPool) {
+	s.poolLock.Lock()
+	defer s.poolLock.Unlock()
+	s.pool = pool
+}
+
+func (s *smartBFTControl) PendingRequests() int {
+	s.poolLock.RLock()
+	pool := s.pool
+	s.poolLock.RUnlock()
+
+	if pool == nil {
+		return 0
+	}
+	return pool.Size()
+}
+
 // StatusReport returns the ConsensusRelation & Status
 func (c *BFTChain) StatusReport() (types2.ConsensusRelation, types2.Status) {
 	c.statusReportMutex.Lock()
@@ -0,0 +1,183 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package smartbft
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SmartBFT-Go/consensus/smartbftprotos"
+	cb "github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics/metricsfakes"
+	types2 "github.com/hyperledger/fabric/orderer/common/types"
+	"github.com/hyperledger/fabric/protoutil"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeControl struct {
+	mutex      sync.Mutex
+	leaderID   uint64
+	pending    int
+	complaints chan uint64
+}
+
+func (f *fakeControl) GetLeaderID() uint64 {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.leaderID
+}
+
+func (f *fakeControl) setLeaderID(id uint64) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.leaderID = id
+}
+
+func (f *fakeControl) Complain(viewNum uint64, stopView bool) {
+	f.complaints <- viewNum
+}
+
+func (f *fakeControl) PendingRequests() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.pending
+}
+
+func newOperatorTestChain(t *testing.T, control *fakeControl) (*BFTChain, *metricsfakes.Counter) {
+	viewMetadata := &smartbftprotos.ViewMetadata{
+		ViewId:          3,
+		LatestSequence:  7,
+		DecisionsInView: 2,
+		BlackList:       []uint64{4},
+	}
+	lastBlock := protoutil.NewBlock(7, nil)
+	lastBlock.Metadata.Metadata[cb.BlockMetadataIndex_SIGNATURES] = protoutil.MarshalOrPanic(&cb.Metadata{
+		Value: protoutil.MarshalOrPanic(&cb.OrdererBlockMetadata{
+			ConsenterMetadata: protoutil.MarshalOrPanic(viewMetadata),
+		}),
+	})
+
+	rtc := &atomic.Value{}
+	rtc.Store(RuntimeConfig{LastBlock: lastBlock, Nodes: []uint64{1, 2, 3, 4}})
+
+	operatorViewChanges := &metricsfakes.Counter{}
+	c := &BFTChain{
+		RuntimeConfig: rtc,
+		Channel:       "mychannel",
+		Logger:        flogging.MustGetLogger("orderer.consensus.smartbft.chain"),
+		control:       control,
+		blacklist:     map[uint64]time.Time{},
+		Metrics: &Metrics{
+			PendingRequests:     &metricsfakes.Gauge{},
+			OperatorViewChanges: operatorViewChanges,
+		},
+	}
+	c.Config.SelfID = 1
+	return c, operatorViewChanges
+}
+
+func TestConsensusInfo(t *testing.T) {
+	control := &fakeControl{leaderID: 2, pending: 5, complaints: make(chan uint64, 1)}
+	c, _ := newOperatorTestChain(t, control)
+
+	info, err := c.ConsensusInfo()
+	require.NoError(t, err)
+	require.Equal(t, types2.ConsensusInfo{
+		View:             3,
+		LeaderID:         2,
+		DecisionSequence: 7,
+		DecisionsInView:  2,
+		PendingRequests:  5,
+		Blacklist:        []uint64{4},
+		SuspectedLeaders: []uint64{},
+	}, info)
+
+	require.NoError(t, c.BlacklistLeader(3, time.Hour))
+	info, err = c.ConsensusInfo()
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3}, info.SuspectedLeaders)
+}
+
+func TestForceViewChange(t *testing.T) {
+	control := &fakeControl{complaints: make(chan uint64, 1)}
+	c, operatorViewChanges := newOperatorTestChain(t, control)
+
+	err := c.ForceViewChange()
+	require.EqualError(t, err, "consensus is not running")
+
+	control.setLeaderID(2)
+	require.NoError(t, c.ForceViewChange())
+	select {
+	case view := <-control.complaints:
+		require.Equal(t, uint64(3), view)
+	case <-time.After(10 * time.Second):
+		t.Fatal("no view change was voted for")
+	}
+	require.Equal(t, 1, operatorViewChanges.AddCallCount())
+	require.Equal(t, float64(1), operatorViewChanges.AddArgsForCall(0))
+}
+
+func TestBlacklistLeader(t *testing.T) {
+	control := &fakeControl{leaderID: 2, complaints: make(chan uint64, 1)}
+	c, _ := newOperatorTestChain(t, control)
+
+	require.EqualError(t, c.BlacklistLeader(2, 0), "invalid blacklisting duration: 0s")
+	require.EqualError(t, c.BlacklistLeader(1, time.Minute), "node 1 cannot blacklist itself")
+	require.EqualError(t, c.BlacklistLeader(5, time.Minute), "node 5 is not a consenter of channel mychannel")
+
+	// blacklisting a follower does not change the view
+	require.NoError(t, c.BlacklistLeader(3, time.Minute))
+	c.complainIfLeaderBlacklisted()
+	require.Empty(t, control.complaints)
+
+	// blacklisting the leader votes for a view change right away
+	require.NoError(t, c.BlacklistLeader(2, time.Minute))
+	require.Equal(t, uint64(3), <-control.complaints)
+
+	// and again whenever the blacklisted node becomes the leader
+	control.setLeaderID(4)
+	c.complainIfLeaderBlacklisted()
+	require.Empty(t, control.complaints)
+	control.setLeaderID(3)
+	c.complainIfLeaderBlacklisted()
+	require.Equal(t, uint64(3), <-control.complaints)
+	require.Equal(t, []uint64{2, 3}, c.blacklistedLeaders())
+
+	// until the blacklisting expires
+	c.blacklistLock.Lock()
+	c.blacklist[3] = time.Now().Add(-time.Second)
+	c.blacklistLock.Unlock()
+	c.complainIfLeaderBlacklisted()
+	require.Empty(t, control.complaints)
+	require.Equal(t, []uint64{2}, c.blacklistedLeaders())
+}
+
+type fakePool int
+
+func (f fakePool) Size() int {
+	return int(f)
+}
+
+func TestSmartBFTControlPendingRequests(t *testing.T) {
+	control := &smartBFTControl{}
+	require.Equal(t, 0, control.PendingRequests())
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		control.setPool(fakePool(5))
+	}()
+	for i := 0; i < 100; i++ {
+		control.PendingRequests()
+	}
+	wg.Wait()
+	require.Equal(t, 5, control.PendingRequests())
+}
@@ -41,6 +41,30 @@ var (
 		LabelNames:   []string{"channel"},
 		StatsdFormat: "%{#fqname}.%{channel}",
 	}
+	viewNumberOpts = metrics.GaugeOpts{
+		Namespace:    "consensus",
+		Subsystem:    "BFT",
+		Name:         "view_number",
+		Help:         "The view number according to the latest committed block.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	pendingRequestsOpts = metrics.GaugeOpts{
+		Namespace:    "consensus",
+		Subsystem:    "BFT",
+		Name:         "pending_requests",
+		Help:         "The number of requests pending in the request pool of the current node.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
+	operatorViewChangesOpts = metrics.CounterOpts{
+		Namespace:    "consensus",
+		Subsystem:    "BFT",
+		Name:         "operator_view_changes",
+		Help:         "The number of view changes the current node voted for on behalf of its operator.",
+		LabelNames:   []string{"channel"},
+		StatsdFormat: "%{#fqname}.%{channel}",
+	}
 )
 
 // Metrics defines the metrics for the cluster.
@@ -49,6 +73,9 @@ type Metrics struct {
 	CommittedBlockNumber metrics.Gauge
 	IsLeader             metrics.Gauge
 	LeaderID             metrics.Gauge
+	ViewNumber           metrics.Gauge
+	PendingRequests      metrics.Gauge
+	OperatorViewChanges  metrics.Counter
 }
 
 // NewMetrics creates the Metrics
@@ -58,5 +85,8 @@ func NewMetrics(p metrics.Provider) *Metrics {
 		CommittedBlockNumber: p.NewGauge(committedBlockNumberOpts),
 		IsLeader:             p.NewGauge(isLeaderOpts),
 		LeaderID:             p.NewGauge(leaderIDOpts),
+		ViewNumber:           p.NewGauge(viewNumberOpts),
+		PendingRequests:      p.NewGauge(pendingRequestsOpts),
+		OperatorViewChanges:  p.NewCounter(operatorViewChangesOpts),
 	}
 }
@@ -158,6 +158,7 @@ func newBlockPuller(
 		Logger:              flogging.MustGetLogger("orderer.common.cluster.puller"),
 		RetryTimeout:        clusterConfig.ReplicationRetryTimeout,
 		MaxTotalBufferBytes: clusterConfig.ReplicationBufferSize,
+		BandwidthLimiter:    baseDialer.BandwidthLimiter,
 		FetchTimeout:        clusterConfig.ReplicationPullTimeout,
 		Endpoints:           endpoints,
 		Signer:              support,
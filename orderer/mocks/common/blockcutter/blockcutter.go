@@ -8,6 +8,7 @@ package blockcutter
 
 import (
 	"sync"
+	"time"
 
 	cb "github.com/hyperledger/fabric-protos-go/common"
 	"github.com/hyperledger/fabric/common/flogging"
@@ -29,6 +30,9 @@ type Receiver struct {
 	// SkipAppendCurBatch causes Ordered to skip appending to curBatch
 	SkipAppendCurBatch bool
 
+	// DeadlineVal is returned by Deadline while there is an outstanding batch, unless it is zero
+	DeadlineVal time.Time
+
 	// Lock to serialize writes access to curBatch
 	mutex sync.Mutex
 
@@ -103,3 +107,13 @@ func (mbc *Receiver) CurBatch() []*cb.Envelope {
 	defer mbc.mutex.Unlock()
 	return mbc.curBatch
 }
+
+// Deadline returns DeadlineVal if it is set and there is an outstanding batch
+func (mbc *Receiver) Deadline() (time.Time, bool) {
+	mbc.mutex.Lock()
+	defer mbc.mutex.Unlock()
+	if len(mbc.curBatch) == 0 || mbc.DeadlineVal.IsZero() {
+		return time.Time{}, false
+	}
+	return mbc.DeadlineVal, true
+}
@@ -85,7 +85,7 @@ generateOrCheck \
         docs/wrappers/peer_chaincode_postscript.md \
         "${commands[@]}"
 
-commands=("peer lifecycle" "peer lifecycle chaincode" "peer lifecycle chaincode package" "peer lifecycle chaincode install" "peer lifecycle chaincode queryinstalled" "peer lifecycle chaincode getinstalledpackage" "peer lifecycle chaincode calculatepackageid" "peer lifecycle chaincode approveformyorg" "peer lifecycle chaincode queryapproved" "peer lifecycle chaincode checkcommitreadiness" "peer lifecycle chaincode commit" "peer lifecycle chaincode querycommitted")
+commands=("peer lifecycle" "peer lifecycle chaincode" "peer lifecycle chaincode package" "peer lifecycle chaincode sign" "peer lifecycle chaincode install" "peer lifecycle chaincode queryinstalled" "peer lifecycle chaincode getinstalledpackage" "peer lifecycle chaincode calculatepackageid" "peer lifecycle chaincode approveformyorg" "peer lifecycle chaincode queryapproved" "peer lifecycle chaincode checkcommitreadiness" "peer lifecycle chaincode commit" "peer lifecycle chaincode querycommitted")
 generateOrCheck \
         docs/source/commands/peerlifecycle.md \
         docs/wrappers/peer_lifecycle_chaincode_preamble.md \
@@ -99,7 +99,7 @@ generateOrCheck \
         docs/wrappers/peer_channel_postscript.md \
         "${commands[@]}"
 
-commands=("peer node pause" "peer node rebuild-dbs" "peer node reset" "peer node resume" "peer node rollback" "peer node start" "peer node unjoin" "peer node upgrade-dbs")
+commands=("peer node config" "peer node pause" "peer node rebuild-dbs" "peer node reset" "peer node resume" "peer node rollback" "peer node start" "peer node unjoin" "peer node upgrade-dbs")
 generateOrCheck \
         docs/source/commands/peernode.md \
         docs/wrappers/peer_node_preamble.md \
@@ -120,7 +120,7 @@ generateOrCheck \
         docs/wrappers/configtxgen_postscript.md \
         "${commands[@]}"
 
-commands=("cryptogen help" "cryptogen generate" "cryptogen showtemplate" "cryptogen extend" "cryptogen version")
+commands=("cryptogen help" "cryptogen generate" "cryptogen showtemplate" "cryptogen extend" "cryptogen renew" "cryptogen version")
 generateOrCheck \
         docs/source/commands/cryptogen.md \
         docs/wrappers/cryptogen_preamble.md \
@@ -141,7 +141,14 @@ generateOrCheck \
         docs/wrappers/osnadmin_channel_postscript.md \
         "${commands[@]}"
 
-commands=("ledgerutil compare" "ledgerutil identifytxs" "ledgerutil verify")
+commands=("osnadmin consensus" "osnadmin consensus status" "osnadmin consensus viewchange" "osnadmin consensus blacklist")
+generateOrCheck \
+        docs/source/commands/osnadminconsensus.md \
+        docs/wrappers/osnadmin_consensus_preamble.md \
+        docs/wrappers/osnadmin_consensus_postscript.md \
+        "${commands[@]}"
+
+commands=("ledgerutil compare" "ledgerutil identifytxs" "ledgerutil verify" "ledgerutil revalidate")
 generateOrCheck \
         docs/source/commands/ledgerutil.md \
         docs/wrappers/ledgerutil_preamble.md \